@@ -41,8 +41,11 @@ func main() {
 		log.Printf("Failed to initialize i18n: %v", err)
 	}
 
+	// Latency profiling must exist before the database so it can monitor commands
+	perfService := services.NewPerfService()
+
 	// Initialize database
-	db, err := services.InitDatabase()
+	db, err := services.InitDatabase(perfService.ClientOptions())
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
@@ -74,13 +77,20 @@ func main() {
 		log.Fatalf("Failed to initialize MinIO: %v", err)
 	}
 
+	// Initialize organization branding, used by emails and PDFs
+	brandingService := services.NewBrandingService(db, minioService)
+
 	// Initialize services
 	jwtService := services.NewJWTService()
 	userService := services.InitUserService(db)
-	emailService := services.NewEmailService()
+	emailService := services.NewEmailService(brandingService)
+	emailOutboxService := services.NewEmailOutboxService(db, emailService)
+	emailTemplateService := services.NewEmailTemplateService(db, emailService)
 	otpService := services.NewOTPService(redisService.Client)
 	pinService := services.NewPinService(db.Database)
+	displaySessionService := services.NewDisplaySessionService(redisService.Client)
 	activityLogService := services.InitActivityLogService(db)
+	moduleRolloutService := services.NewModuleRolloutService(db)
 
 	// Initialize Firebase service
 	firebaseService, err := services.NewFirebaseService()
@@ -93,6 +103,10 @@ func main() {
 	// Initialize device and notification services
 	deviceService := services.NewDeviceService(db, firebaseService)
 	notificationService := services.NewNotificationService(db, firebaseService, deviceService, userService)
+	emailDigestService := services.NewEmailDigestService(db, notificationService, emailService, userService)
+	emailSettingsService := services.NewEmailSettingsService(db, emailService, notificationService)
+	// Cap the emails sent to each recipient and drop the duplicates
+	services.NewEmailThrottleService(redisService.Client, emailService)
 
 	// Initialize OpenAI service
 	openaiService, err := services.NewOpenAIService()
@@ -102,8 +116,9 @@ func main() {
 		openaiService = nil
 	}
 
-	// Initialize PDF service
-	pdfService := services.NewPDFService(minioService, openaiService)
+	// Initialize PDF service, with the corporate layouts managed by admins
+	pdfTemplateService := services.NewPDFTemplateService(db)
+	pdfService := services.NewPDFService(db, minioService, openaiService, brandingService, pdfTemplateService)
 
 	// Initialize Documentation service
 	documentationService := services.NewDocumentationService(db, minioService, openaiService)
@@ -111,8 +126,69 @@ func main() {
 	// Initialize macro service
 	macroService := services.NewMacroService(db, pdfService, documentationService)
 
+	// Initialize metadata section service
+	metadataSectionService := services.NewMetadataSectionService(db)
+
+	// Initialize contextual help service
+	helpArticleService := services.NewHelpArticleService(db)
+
+	// Initialize quality review registers service
+	reportService := services.NewReportService(db)
+
+	// Initialize actors registry service
+	actorService := services.NewActorService(db)
+	impactService := services.NewImpactService(db)
+	offboardingService := services.NewOffboardingService(db, userService)
+	commentService := services.NewCommentService(db)
+	reactionService := services.NewReactionService(db)
+	analyticsService := services.NewAnalyticsService(db)
+	reviewService := services.NewReviewService(db, notificationService, emailService, userService)
+	calendarService := services.NewCalendarService(db)
+	approvalDeadlineService := services.NewApprovalDeadlineService(db, notificationService, emailService, calendarService)
+	campaignService := services.NewEmailCampaignService(db, emailService)
+	accountDeletionService := services.NewAccountDeletionService(db, userService, otpService)
+
+	// Initialize document reference generator
+	referenceService := services.NewReferenceService(db, macroService)
+
+	// Initialize department default contributors
+	contributorTemplateService := services.NewContributorTemplateService(db)
+
 	// Initialize document service (depends on macroService)
-	documentService := services.NewDocumentService(db.Database, userService, pdfService, macroService, documentationService)
+	// Initialize export hooks, run on the PDFs and archives before they are served
+	exportHookService := services.NewExportHookService(activityLogService)
+	documentService := services.NewDocumentService(db.Database, userService, pdfService, macroService, documentationService, metadataSectionService, actorService, referenceService, contributorTemplateService, minioService, exportHookService)
+	triageService := services.NewTriageService(db, documentService, userService, notificationService)
+
+	// Initialize the sync of published documents to the external QMS
+	qmsSyncService := services.NewQMSSyncService(db, documentService, minioService)
+
+	// Initialize document watches
+	watchService := services.NewWatchService(db, notificationService)
+
+	// Initialize the users present on the documents, tracked in Redis
+	presenceService := services.NewPresenceService(redisService.Client, userService)
+
+	// Initialize scheduled publications and publication follow-ups
+	publicationService := services.NewPublicationService(documentService, notificationService, qmsSyncService, watchService)
+
+	// Initialize the library of shared process step snippets
+	snippetService := services.NewSnippetService(db, documentService)
+
+	// Initialize starred and recently viewed documents
+	favoriteService := services.NewFavoriteService(db, documentService)
+
+	// Initialize read acknowledgment campaigns
+	acknowledgmentService := services.NewAcknowledgmentService(db, documentService, notificationService)
+
+	// Initialize the background jobs queued in Redis, such as PDF generations
+	jobQueueService := services.NewJobQueueService(redisService.Client, documentService, notificationService)
+
+	// Initialize streamed list exports
+	exportService := services.NewExportService(db, minioService)
+
+	// Initialize document audit trail
+	documentHistoryService := services.NewDocumentHistoryService(db)
 
 	// Initialize chat service
 	var chatService *services.ChatService
@@ -120,6 +196,63 @@ func main() {
 		chatService = services.NewChatService(db.Database, openaiService)
 	}
 
+	// Initialize status service and start periodic health sampling
+	statusAlertService := services.NewStatusAlertService(notificationService, userService)
+	statusService := services.NewStatusService(db, redisService, minioService, emailService, firebaseService, statusAlertService)
+	statusCtx, stopStatusSampler := context.WithCancel(context.Background())
+	defer stopStatusSampler()
+	statusService.Start(statusCtx)
+
+	// Start the scheduled purge of trashed documents
+	trashCtx, stopTrashPurge := context.WithCancel(context.Background())
+	defer stopTrashPurge()
+	documentService.StartTrashPurge(trashCtx)
+
+	// Start the daily scan of documents due for periodic review
+	reviewCtx, stopReviewScheduler := context.WithCancel(context.Background())
+	defer stopReviewScheduler()
+	reviewService.Start(reviewCtx)
+
+	// Start the signature deadline reminders and escalations
+	deadlineCtx, stopDeadlineWorker := context.WithCancel(context.Background())
+	defer stopDeadlineWorker()
+	approvalDeadlineService.Start(deadlineCtx)
+
+	// Start the publication of approved documents at their effective date
+	publicationCtx, stopPublicationWorker := context.WithCancel(context.Background())
+	defer stopPublicationWorker()
+	publicationService.Start(publicationCtx)
+
+	// Start the sync of published documents to the external QMS, with retries
+	qmsSyncCtx, stopQMSSyncWorker := context.WithCancel(context.Background())
+	defer stopQMSSyncWorker()
+	qmsSyncService.Start(qmsSyncCtx)
+
+	// Start the workers of the background job queue
+	jobQueueCtx, stopJobQueue := context.WithCancel(context.Background())
+	defer stopJobQueue()
+	jobQueueService.Start(jobQueueCtx)
+
+	// Start the delivery of the emails queued in the outbox
+	outboxCtx, stopEmailOutbox := context.WithCancel(context.Background())
+	defer stopEmailOutbox()
+	emailOutboxService.Start(outboxCtx)
+
+	// Start the throttled delivery of email campaigns
+	campaignCtx, stopCampaignDispatcher := context.WithCancel(context.Background())
+	defer stopCampaignDispatcher()
+	campaignService.Start(campaignCtx)
+
+	// Start the hourly and daily digests of the notification emails
+	digestCtx, stopEmailDigests := context.WithCancel(context.Background())
+	defer stopEmailDigests()
+	emailDigestService.Start(digestCtx)
+
+	// Start the anonymization of accounts whose deletion grace period is over
+	deletionCtx, stopAccountDeletions := context.WithCancel(context.Background())
+	defer stopAccountDeletions()
+	accountDeletionService.Start(deletionCtx)
+
 	// Ensure default admin exists
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	if err := userService.EnsureDefaultAdmin(ctx); err != nil {
@@ -127,26 +260,69 @@ func main() {
 	}
 	cancel()
 
+	// Initialize policy service, the auth middleware flags users with policies to accept
+	policyService := services.NewPolicyService(db)
+
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtService, userService)
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, userService, policyService)
 	activityLogMiddleware := middleware.NewActivityLogMiddleware(activityLogService)
 	documentMiddleware := middleware.NewDocumentMiddleware(db.Database)
+	perfMiddleware := middleware.NewPerfMiddleware(perfService)
+	timeoutMiddleware := middleware.NewTimeoutMiddleware()
+	moduleMiddleware := middleware.NewModuleMiddleware(moduleRolloutService)
+
+	// Background work of the handlers
+	asyncRunner := services.NewAsyncRunner()
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(userService, jwtService, emailService, otpService, minioService, pinService)
+	authHandler := handlers.NewAuthHandler(userService, jwtService, emailService, otpService, minioService, pinService, policyService, asyncRunner)
 	userHandler := handlers.NewUserHandler(userService, emailService)
-	departmentHandler := handlers.NewDepartmentHandler(db)
+	departmentHandler := handlers.NewDepartmentHandler(db, contributorTemplateService)
 	domainHandler := handlers.NewDomainHandler(db)
 	jobPositionHandler := handlers.NewJobPositionHandler(db)
 	activityLogHandler := handlers.NewActivityLogHandler(activityLogService)
-	emailHandler := handlers.NewEmailHandler(emailService, userService)
-	notificationHandler := handlers.NewNotificationHandler(userService, notificationService, deviceService)
-	documentHandler := handlers.NewDocumentHandler(documentService, activityLogService, minioService, notificationService)
-	invitationHandler := handlers.NewInvitationHandler(db.Database, emailService, notificationService, activityLogService)
+	emailHandler := handlers.NewEmailHandler(emailService, userService, campaignService, emailOutboxService)
+	emailTemplateHandler := handlers.NewEmailTemplateHandler(emailTemplateService, activityLogService)
+	emailSettingsHandler := handlers.NewEmailSettingsHandler(emailSettingsService, activityLogService)
+	webhookHandler := handlers.NewWebhookHandler(emailOutboxService)
+	notificationHandler := handlers.NewNotificationHandler(userService, notificationService, deviceService, reactionService)
+	documentHandler := handlers.NewDocumentHandler(documentService, activityLogService, minioService, notificationService, analyticsService, publicationService, favoriteService, watchService, asyncRunner)
+	invitationHandler := handlers.NewInvitationHandler(db.Database, emailService, notificationService, activityLogService, asyncRunner)
 	permissionHandler := handlers.NewPermissionHandler(db.Database)
-	signatureHandler := handlers.NewSignatureHandler(db.Database)
+	signatureHandler := handlers.NewSignatureHandler(db.Database, commentService, documentService, watchService, asyncRunner)
 	userSignatureHandler := handlers.NewUserSignatureHandler(db.Database)
 	macroHandler := handlers.NewMacroHandler(macroService)
+	displayHandler := handlers.NewDisplayHandler(displaySessionService, jwtService, userService, documentService)
+	statusHandler := handlers.NewStatusHandler(statusService)
+	moduleHandler := handlers.NewModuleHandler(moduleRolloutService)
+	metadataSectionHandler := handlers.NewMetadataSectionHandler(metadataSectionService)
+	helpArticleHandler := handlers.NewHelpArticleHandler(helpArticleService)
+	policyHandler := handlers.NewPolicyHandler(policyService, activityLogService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	brandingHandler := handlers.NewBrandingHandler(brandingService, activityLogService)
+	referenceHandler := handlers.NewReferenceHandler(referenceService)
+	documentHistoryHandler := handlers.NewDocumentHistoryHandler(documentHistoryService)
+	qmsSyncHandler := handlers.NewQMSSyncHandler(qmsSyncService, activityLogService)
+	favoriteHandler := handlers.NewFavoriteHandler(favoriteService)
+	watchHandler := handlers.NewWatchHandler(watchService)
+	presenceHandler := handlers.NewPresenceHandler(presenceService)
+	jobHandler := handlers.NewJobHandler(jobQueueService, activityLogService)
+	pdfTemplateHandler := handlers.NewPDFTemplateHandler(pdfTemplateService, pdfService, documentService, macroService, activityLogService)
+	acknowledgmentHandler := handlers.NewAcknowledgmentHandler(acknowledgmentService, activityLogService)
+	exportHandler := handlers.NewExportHandler(exportService, userService, documentService, activityLogService)
+	actorHandler := handlers.NewActorHandler(actorService, documentService)
+	snippetHandler := handlers.NewSnippetHandler(snippetService, activityLogService)
+	searchHandler := handlers.NewSearchHandler(documentService, actorService, analyticsService)
+	impactHandler := handlers.NewImpactHandler(impactService)
+	offboardingHandler := handlers.NewOffboardingHandler(offboardingService)
+	triageHandler := handlers.NewTriageHandler(triageService, activityLogService)
+	perfHandler := handlers.NewPerfHandler(perfService, asyncRunner, pdfService)
+	commentHandler := handlers.NewCommentHandler(commentService, documentService, notificationService, pdfService, reactionService, asyncRunner)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, documentService, userService)
+	reviewHandler := handlers.NewReviewHandler(reviewService, documentService, activityLogService)
+	signatureReminderHandler := handlers.NewSignatureReminderHandler(approvalDeadlineService, documentService, activityLogService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService, activityLogService)
+	accountDeletionHandler := handlers.NewAccountDeletionHandler(accountDeletionService, emailService, activityLogService, asyncRunner)
 
 	// Initialize chat handler (only if OpenAI service is available)
 	var chatHandler *handlers.ChatHandler
@@ -154,8 +330,9 @@ func main() {
 		chatHandler = handlers.NewChatHandler(chatService)
 	}
 
-	// Initialize Gin router
-	r := gin.Default()
+	// Initialize Gin router, letting aborted streams through the recovery
+	r := gin.New()
+	r.Use(gin.Logger(), middleware.Recovery())
 
 	// CORS configuration
 	corsConfig := cors.DefaultConfig()
@@ -165,16 +342,26 @@ func main() {
 		corsConfig.AllowOrigins = []string{"http://localhost:3000", "https://localhost:3000", "http://localhost", "https://localhost"}
 	}
 	corsConfig.AllowCredentials = true
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Accept-Language", "X-Language"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Accept-Language", "X-Language", "X-Request-ID", "If-Match"}
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	corsConfig.ExposeHeaders = []string{"Content-Disposition", "X-Search-ID", "X-Request-ID", "ETag", "X-Policy-Acceptance-Required"}
 	r.Use(cors.New(corsConfig))
 
+	// Request IDs, echoed in X-Request-ID and in timeout errors
+	r.Use(timeoutMiddleware.RequestID())
+
 	// i18n middleware
 	r.Use(i18n.Middleware())
 
+	// Per-endpoint latency profiling (PERF_PROFILING=true)
+	r.Use(perfMiddleware.Track())
+
 	// Global middleware for activity logging
 	r.Use(activityLogMiddleware.LogActivity())
 
+	// Per-route-group request deadlines (REQUEST_TIMEOUT, REQUEST_TIMEOUT_ROUTES)
+	r.Use(timeoutMiddleware.Timeout())
+
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
 		// Check database and Redis health
@@ -196,19 +383,29 @@ func main() {
 			minioHealthy = false
 		}
 
+		// Reachability of the email providers, so a misconfiguration shows
+		// before the first OTP request
+		emailProviders := emailService.ProviderReachability(ctx)
+		emailHealthy := false
+		for _, reachable := range emailProviders {
+			emailHealthy = emailHealthy || reachable
+		}
+
 		status := "healthy"
-		if !dbHealthy || !redisHealthy || !minioHealthy {
+		if !dbHealthy || !redisHealthy || !minioHealthy || !emailHealthy {
 			status = "degraded"
 		}
 
 		c.JSON(200, gin.H{
-			"status":    status,
-			"service":   "process-manager-backend",
-			"version":   "1.0.0",
-			"database":  dbHealthy,
-			"redis":     redisHealthy,
-			"minio":     minioHealthy,
-			"timestamp": time.Now().Unix(),
+			"status":         status,
+			"service":        "process-manager-backend",
+			"version":        "1.0.0",
+			"database":       dbHealthy,
+			"redis":          redisHealthy,
+			"minio":          minioHealthy,
+			"email":          emailHealthy,
+			"emailProviders": emailProviders,
+			"timestamp":      time.Now().Unix(),
 		})
 	})
 
@@ -217,17 +414,49 @@ func main() {
 	{
 		// Setup organized routes
 		routes.SetupAuthRoutes(api, authHandler, authMiddleware)
+		routes.SetupAccountDeletionRoutes(api, accountDeletionHandler, authMiddleware)
 		routes.SetupUserRoutes(api, userHandler, authMiddleware)
 		routes.SetupDepartmentRoutes(api, departmentHandler, authMiddleware)
 		routes.SetupDomainRoutes(api, domainHandler, authMiddleware)
 		routes.SetupJobPositionRoutes(api, jobPositionHandler, authMiddleware)
 		routes.SetupActivityLogRoutes(api, activityLogHandler, authMiddleware)
 		routes.SetupEmailRoutes(api, emailHandler, authMiddleware)
+		routes.SetupEmailTemplateRoutes(api, emailTemplateHandler, authMiddleware)
+		routes.SetupEmailSettingsRoutes(api, emailSettingsHandler, authMiddleware)
+		routes.SetupWebhookRoutes(api, webhookHandler)
 		routes.SetupNotificationRoutes(api, notificationHandler, authMiddleware)
-		routes.SetupDocumentRoutes(api, documentHandler, permissionHandler, signatureHandler, authMiddleware, documentMiddleware)
+		routes.SetupDocumentRoutes(api, documentHandler, permissionHandler, signatureHandler, commentHandler, analyticsHandler, authMiddleware, documentMiddleware)
+		routes.SetupReviewRoutes(api, reviewHandler, authMiddleware, documentMiddleware)
+		routes.SetupSignatureReminderRoutes(api, signatureReminderHandler, authMiddleware)
+		routes.SetupReferenceRoutes(api, referenceHandler, authMiddleware)
+		routes.SetupDocumentHistoryRoutes(api, documentHistoryHandler, authMiddleware, documentMiddleware)
+		routes.SetupQMSSyncRoutes(api, qmsSyncHandler, authMiddleware, documentMiddleware)
+		routes.SetupFavoriteRoutes(api, favoriteHandler, authMiddleware, documentMiddleware)
+		routes.SetupWatchRoutes(api, watchHandler, authMiddleware, documentMiddleware)
+		routes.SetupPresenceRoutes(api, presenceHandler, authMiddleware, documentMiddleware)
+		routes.SetupJobRoutes(api, jobHandler, authMiddleware, documentMiddleware)
+		routes.SetupPDFTemplateRoutes(api, pdfTemplateHandler, authMiddleware)
+		routes.SetupAcknowledgmentRoutes(api, acknowledgmentHandler, authMiddleware, documentMiddleware)
+		routes.SetupExportRoutes(api, exportHandler, authMiddleware)
 		routes.RegisterInvitationRoutes(api, invitationHandler, authMiddleware)
 		routes.SetupUserSignatureRoutes(api, userSignatureHandler, authMiddleware)
+		routes.SetupCalendarRoutes(api, calendarHandler, authMiddleware)
 		routes.SetupMacroRoutes(api, macroHandler, authMiddleware)
+		routes.SetupDisplayRoutes(api, displayHandler, authMiddleware)
+		routes.SetupStatusRoutes(api, statusHandler, authMiddleware)
+		routes.SetupModuleRoutes(api, moduleHandler, authMiddleware, moduleMiddleware)
+		routes.SetupMetadataSectionRoutes(api, metadataSectionHandler, authMiddleware)
+		routes.SetupHelpArticleRoutes(api, helpArticleHandler, authMiddleware)
+		routes.SetupPolicyRoutes(api, policyHandler, authMiddleware)
+		routes.SetupReportRoutes(api, reportHandler, authMiddleware)
+		routes.SetupBrandingRoutes(api, brandingHandler, authMiddleware)
+		routes.SetupActorRoutes(api, actorHandler, authMiddleware)
+		routes.SetupSnippetRoutes(api, snippetHandler, authMiddleware, documentMiddleware)
+		routes.SetupSearchRoutes(api, searchHandler, authMiddleware)
+		routes.SetupImpactRoutes(api, impactHandler, authMiddleware)
+		routes.SetupOffboardingRoutes(api, offboardingHandler, authMiddleware)
+		routes.SetupTriageRoutes(api, triageHandler, authMiddleware)
+		routes.SetupPerfRoutes(api, perfHandler, authMiddleware)
 
 		// Setup chat routes (only if OpenAI service is available)
 		if chatHandler != nil {
@@ -239,6 +468,9 @@ func main() {
 		routes.SetupDocumentationRoutes(api, documentationHandler, authMiddleware)
 	}
 
+	// Short links printed on the PDFs, resolved to the live document
+	routes.SetupShortLinkRoutes(&r.RouterGroup, documentHandler)
+
 	// Get port from environment or default to 8080
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// apiClient calls the API with the admin access token
+type apiClient struct {
+	baseURL string
+	token   string
+}
+
+// apiResponse is the envelope of the API responses
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Error   string          `json:"error"`
+	Details string          `json:"details"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// do sends a request and returns the data of the response
+func (c *apiClient) do(method, path string, body interface{}) (json.RawMessage, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(c.baseURL, "/")+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := &http.Client{Timeout: 60 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%s %s: unexpected %d response", method, path, resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 || !result.Success {
+		message := result.Error
+		if message == "" {
+			message = result.Message
+		}
+		if result.Details != "" {
+			message += ": " + result.Details
+		}
+		return nil, fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, message)
+	}
+	return result.Data, nil
+}
+
+// printData writes the data of a response as indented JSON, for jq and scripts
+func printData(data json.RawMessage) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := out.WriteTo(os.Stdout)
+	return err
+}
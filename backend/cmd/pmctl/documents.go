@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newDocumentsCommand builds the document commands
+func newDocumentsCommand(client *apiClient) *cobra.Command {
+	documents := &cobra.Command{
+		Use:   "documents",
+		Short: "Manage documents",
+	}
+
+	var wait bool
+	regenerate := &cobra.Command{
+		Use:   "regenerate-pdf <document-id>...",
+		Short: "Queue the regeneration of the stored PDF of documents",
+		Long: "Queue the regeneration of the stored PDF of documents, e.g. after a\n" +
+			"branding or template change. With --wait, pmctl polls each job until it\n" +
+			"finishes and fails when a job fails.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, id := range args {
+				data, err := client.do(http.MethodPost, "/documents/"+url.PathEscape(id)+"/export-pdf?force=true", nil)
+				if err != nil {
+					return err
+				}
+				if wait {
+					var job struct {
+						ID string `json:"id"`
+					}
+					if err := json.Unmarshal(data, &job); err != nil {
+						return err
+					}
+					if data, err = waitForJob(client, job.ID); err != nil {
+						return err
+					}
+				}
+				if err := printData(data); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	regenerate.Flags().BoolVar(&wait, "wait", false, "Wait for each PDF to be generated")
+
+	documents.AddCommand(regenerate)
+	return documents
+}
+
+// waitForJob polls a job until it finishes and returns it
+func waitForJob(client *apiClient, id string) (json.RawMessage, error) {
+	for {
+		data, err := client.do(http.MethodGet, "/jobs/"+url.PathEscape(id), nil)
+		if err != nil {
+			return nil, err
+		}
+		var job struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		}
+		if err := json.Unmarshal(data, &job); err != nil {
+			return nil, err
+		}
+		switch job.Status {
+		case "completed":
+			return data, nil
+		case "failed":
+			return nil, fmt.Errorf("job %s failed: %s", id, job.Error)
+		}
+		fmt.Fprintf(os.Stderr, "job %s %s...\n", id, job.Status)
+		time.Sleep(2 * time.Second)
+	}
+}
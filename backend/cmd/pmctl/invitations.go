@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+// newInvitationsCommand builds the document invitation commands
+func newInvitationsCommand(client *apiClient) *cobra.Command {
+	invitations := &cobra.Command{
+		Use:   "invitations",
+		Short: "Manage document invitations",
+	}
+
+	resend := &cobra.Command{
+		Use:   "resend <invitation-id>...",
+		Short: "Send invitations again",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, id := range args {
+				data, err := client.do(http.MethodPost, "/invitations/"+url.PathEscape(id)+"/resend", nil)
+				if err != nil {
+					return err
+				}
+				if err := printData(data); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	invitations.AddCommand(resend)
+	return invitations
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+// newJobsCommand builds the background job commands
+func newJobsCommand(client *apiClient) *cobra.Command {
+	jobs := &cobra.Command{
+		Use:   "jobs",
+		Short: "Inspect the background job queue",
+	}
+
+	queue := &cobra.Command{
+		Use:   "queue",
+		Short: "Show the queued jobs count and the jobs being run",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := client.do(http.MethodGet, "/admin/jobs", nil)
+			if err != nil {
+				return err
+			}
+			return printData(data)
+		},
+	}
+
+	get := &cobra.Command{
+		Use:   "get <job-id>",
+		Short: "Show a job",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := client.do(http.MethodGet, "/jobs/"+url.PathEscape(args[0]), nil)
+			if err != nil {
+				return err
+			}
+			return printData(data)
+		},
+	}
+
+	jobs.AddCommand(queue, get)
+	return jobs
+}
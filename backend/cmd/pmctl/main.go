@@ -0,0 +1,56 @@
+// Command pmctl runs the common administration workflows of the platform
+// through its API, for scripts and runbooks.
+//
+// The API has no API keys yet, so pmctl authenticates with the access token
+// of an admin account, given by --token or PMCTL_TOKEN. PMCTL_API_URL sets
+// the API base URL.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand builds the pmctl command tree
+func newRootCommand() *cobra.Command {
+	client := &apiClient{}
+
+	root := &cobra.Command{
+		Use:          "pmctl",
+		Short:        "Administration tool of Process Manager",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Shell completion scripts are generated offline
+			if cmd.HasParent() && cmd.Parent().Name() == "completion" {
+				return nil
+			}
+			if client.token == "" {
+				return fmt.Errorf("an admin access token is required, set --token or PMCTL_TOKEN")
+			}
+			return nil
+		},
+	}
+
+	baseURL := os.Getenv("PMCTL_API_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080/api"
+	}
+	root.PersistentFlags().StringVar(&client.baseURL, "api-url", baseURL, "API base URL (PMCTL_API_URL)")
+	root.PersistentFlags().StringVar(&client.token, "token", os.Getenv("PMCTL_TOKEN"), "Access token of an admin account (PMCTL_TOKEN)")
+
+	root.AddCommand(
+		newUsersCommand(client),
+		newInvitationsCommand(client),
+		newDocumentsCommand(client),
+		newJobsCommand(client),
+	)
+	return root
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// newUsersCommand builds the user administration commands
+func newUsersCommand(client *apiClient) *cobra.Command {
+	users := &cobra.Command{
+		Use:   "users",
+		Short: "Manage user accounts",
+	}
+
+	var req struct {
+		Email         string `json:"email"`
+		FirstName     string `json:"firstName"`
+		LastName      string `json:"lastName"`
+		Role          string `json:"role"`
+		Phone         string `json:"phone,omitempty"`
+		DepartmentID  string `json:"departmentId,omitempty"`
+		JobPositionID string `json:"jobPositionId,omitempty"`
+	}
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := client.do(http.MethodPost, "/users/", req)
+			if err != nil {
+				return err
+			}
+			return printData(data)
+		},
+	}
+	create.Flags().StringVar(&req.Email, "email", "", "Email address")
+	create.Flags().StringVar(&req.FirstName, "first-name", "", "First name")
+	create.Flags().StringVar(&req.LastName, "last-name", "", "Last name")
+	create.Flags().StringVar(&req.Role, "role", "user", "Role: admin, manager or user")
+	create.Flags().StringVar(&req.Phone, "phone", "", "Phone number")
+	create.Flags().StringVar(&req.DepartmentID, "department", "", "Department ID")
+	create.Flags().StringVar(&req.JobPositionID, "job-position", "", "Job position ID")
+	for _, name := range []string{"email", "first-name", "last-name"} {
+		_ = create.MarkFlagRequired(name)
+	}
+
+	var page, limit int
+	pending := &cobra.Command{
+		Use:   "pending",
+		Short: "List the registrations awaiting validation",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{
+				"status": {"pending"},
+				"page":   {strconv.Itoa(page)},
+				"limit":  {strconv.Itoa(limit)},
+			}
+			data, err := client.do(http.MethodGet, "/users/?"+query.Encode(), nil)
+			if err != nil {
+				return err
+			}
+			return printData(data)
+		},
+	}
+	pending.Flags().IntVar(&page, "page", 1, "Page number")
+	pending.Flags().IntVar(&limit, "limit", 50, "Users per page")
+
+	var role string
+	approve := &cobra.Command{
+		Use:   "approve <user-id>...",
+		Short: "Approve pending registrations",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return validateUsers(client, args, map[string]string{"action": "approve", "role": role})
+		},
+	}
+	approve.Flags().StringVar(&role, "role", "", "Role given to the approved users, the requested one by default")
+
+	var reason string
+	reject := &cobra.Command{
+		Use:   "reject <user-id>...",
+		Short: "Reject pending registrations",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return validateUsers(client, args, map[string]string{"action": "reject", "reason": reason})
+		},
+	}
+	reject.Flags().StringVar(&reason, "reason", "", "Reason sent to the rejected users")
+
+	users.AddCommand(create, pending, approve, reject)
+	return users
+}
+
+// validateUsers approves or rejects registrations, stopping at the first failure
+func validateUsers(client *apiClient, ids []string, body map[string]string) error {
+	for key, value := range body {
+		if value == "" {
+			delete(body, key)
+		}
+	}
+	for _, id := range ids {
+		data, err := client.do(http.MethodPut, "/users/"+url.PathEscape(id)+"/validate", body)
+		if err != nil {
+			return err
+		}
+		if err := printData(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
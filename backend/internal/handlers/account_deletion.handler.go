@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AccountDeletionHandler handles self-service account deletion requests
+type AccountDeletionHandler struct {
+	accountDeletionService *services.AccountDeletionService
+	emailService           *services.EmailService
+	activityLogService     *services.ActivityLogService
+	asyncRunner            *services.AsyncRunner
+}
+
+// NewAccountDeletionHandler creates a new account deletion handler instance
+func NewAccountDeletionHandler(accountDeletionService *services.AccountDeletionService, emailService *services.EmailService, activityLogService *services.ActivityLogService, asyncRunner *services.AsyncRunner) *AccountDeletionHandler {
+	return &AccountDeletionHandler{
+		accountDeletionService: accountDeletionService,
+		emailService:           emailService,
+		activityLogService:     activityLogService,
+		asyncRunner:            asyncRunner,
+	}
+}
+
+// RequestDeletion starts the deletion of the current user's account and sends the confirmation OTP
+// POST /api/auth/delete-account
+func (h *AccountDeletionHandler) RequestDeletion(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	var req models.DeleteAccountRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	request, otp, err := h.accountDeletionService.RequestDeletion(ctx, user, req.Reason)
+	if err != nil {
+		sendAccountDeletionError(c, err)
+		return
+	}
+
+	// Send OTP via email asynchronously to avoid blocking the response
+	fullName := user.FirstName + " " + user.LastName
+	lang := emailLanguage(c, user)
+	h.asyncRunner.Go("account_deletion_otp_email", func(ctx context.Context) error {
+		if err := h.emailService.SendOTPEmail(user.Email, fullName, otp, lang); err != nil {
+			return fmt.Errorf("failed to send account deletion OTP email to %s: %w", user.Email, err)
+		}
+		return nil
+	})
+
+	response := gin.H{
+		"request":          request,
+		"expiresInMinutes": 5,
+	}
+
+	// Check if development mode
+	if os.Getenv("GIN_MODE") == "debug" || os.Getenv("DEVELOPMENT_MODE") == "true" {
+		response["otp"] = otp
+	}
+
+	helpers.SendSuccess(c, "OTP sent to your email address, confirm it to schedule the deletion of your account", response)
+}
+
+// ConfirmDeletion confirms the deletion of the current user's account with the OTP
+// POST /api/auth/delete-account/confirm
+func (h *AccountDeletionHandler) ConfirmDeletion(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	var req models.ConfirmAccountDeletionRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	request, err := h.accountDeletionService.ConfirmDeletion(ctx, user, req.OTP)
+	if err != nil {
+		sendAccountDeletionError(c, err)
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       models.ActionAccountDeletionScheduled,
+		Description:  fmt.Sprintf("Scheduled the deletion of their account for %s", request.ScheduledFor.Format("2006-01-02")),
+		ResourceType: "user",
+		ResourceID:   &user.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"requestId":    request.ID.Hex(),
+			"scheduledFor": request.ScheduledFor,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Account deletion scheduled", request)
+}
+
+// GetDeletion returns the open deletion request of the current user
+// GET /api/auth/delete-account
+func (h *AccountDeletionHandler) GetDeletion(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	request, err := h.accountDeletionService.GetOpenRequest(c.Request.Context(), userID)
+	if err != nil {
+		sendAccountDeletionError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Account deletion request retrieved successfully", request)
+}
+
+// CancelDeletion cancels the open deletion request of the current user
+// DELETE /api/auth/delete-account
+func (h *AccountDeletionHandler) CancelDeletion(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	request, err := h.accountDeletionService.CancelForUser(c.Request.Context(), userID)
+	if err != nil {
+		sendAccountDeletionError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Account deletion cancelled", request)
+}
+
+// ListDeletions lists account deletion requests, pending and scheduled ones by default
+// GET /api/admin/account-deletions?status=scheduled
+func (h *AccountDeletionHandler) ListDeletions(c *gin.Context) {
+	status := c.Query("status")
+	switch models.AccountDeletionStatus(status) {
+	case "", models.AccountDeletionPendingConfirmation, models.AccountDeletionScheduled,
+		models.AccountDeletionCompleted, models.AccountDeletionCancelled:
+	default:
+		helpers.SendBadRequest(c, "Invalid status")
+		return
+	}
+
+	page, limit := helpers.GetPaginationParams(c)
+
+	requests, total, err := h.accountDeletionService.List(c.Request.Context(), status, page, limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Account deletion requests retrieved successfully", requests, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
+
+// CancelDeletionAdmin cancels a pending or scheduled account deletion
+// POST /api/admin/account-deletions/:id/cancel
+func (h *AccountDeletionHandler) CancelDeletionAdmin(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid request ID format")
+		return
+	}
+
+	adminID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	request, err := h.accountDeletionService.Cancel(ctx, id, adminID)
+	if err != nil {
+		sendAccountDeletionError(c, err)
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       models.ActionAccountDeletionCancelled,
+		Description:  fmt.Sprintf("Cancelled the account deletion of %s", request.Email),
+		ResourceType: "user",
+		ResourceID:   &request.UserID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"requestId": request.ID.Hex(),
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Account deletion cancelled", request)
+}
+
+// sendAccountDeletionError maps account deletion errors to HTTP responses
+func sendAccountDeletionError(c *gin.Context, err error) {
+	switch err {
+	case models.ErrDeletionRequestNotFound:
+		helpers.SendNotFound(c, "No pending account deletion request")
+	case models.ErrDeletionAlreadyScheduled:
+		helpers.SendConflict(c, "Account deletion is already scheduled")
+	case models.ErrLastAdminDeletion:
+		helpers.SendForbidden(c, "The last active admin cannot delete their account", models.CodeForbidden)
+	default:
+		helpers.SendError(c, err)
+	}
+}
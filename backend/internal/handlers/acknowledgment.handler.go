@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AcknowledgmentHandler handles the read acknowledgment campaigns of published documents
+type AcknowledgmentHandler struct {
+	acknowledgmentService *services.AcknowledgmentService
+	activityLogService    *services.ActivityLogService
+}
+
+// NewAcknowledgmentHandler creates a new acknowledgment handler instance
+func NewAcknowledgmentHandler(acknowledgmentService *services.AcknowledgmentService, activityLogService *services.ActivityLogService) *AcknowledgmentHandler {
+	return &AcknowledgmentHandler{
+		acknowledgmentService: acknowledgmentService,
+		activityLogService:    activityLogService,
+	}
+}
+
+// CreateCampaign asks the members of departments to acknowledge an archived document
+// POST /api/documents/:id/acknowledgment-campaigns
+func (h *AcknowledgmentHandler) CreateCampaign(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	var req models.CreateAcknowledgmentCampaignRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+	campaign, err := h.acknowledgmentService.Create(ctx, documentID, &req, userID)
+	if err != nil {
+		switch {
+		case err.Error() == "document not found" || err.Error() == "department not found":
+			helpers.SendNotFound(c, err.Error())
+		case err.Error() == "an acknowledgment campaign is already active for this document":
+			helpers.SendConflict(c, err.Error())
+		case err.Error() == "only archived documents can be acknowledged" ||
+			err.Error() == "the selected departments have no active members" ||
+			strings.HasPrefix(err.Error(), "invalid department ID"):
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "acknowledgment_campaign_created",
+		Description:  fmt.Sprintf("Launched a read acknowledgment campaign for %s (%d recipients)", campaign.Reference, campaign.TotalRecipients),
+		ResourceType: "document",
+		ResourceID:   &documentID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"campaignId":      campaign.ID.Hex(),
+			"version":         campaign.Version,
+			"departmentIds":   req.DepartmentIDs,
+			"totalRecipients": campaign.TotalRecipients,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendCreated(c, "Acknowledgment campaign created successfully", campaign.ToResponse())
+}
+
+// ListCampaigns returns the acknowledgment campaigns of a document with their progress
+// GET /api/documents/:id/acknowledgment-campaigns
+func (h *AcknowledgmentHandler) ListCampaigns(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	campaigns, err := h.acknowledgmentService.ListByDocument(c.Request.Context(), documentID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	responses := make([]models.AcknowledgmentCampaignResponse, 0, len(campaigns))
+	for i := range campaigns {
+		responses = append(responses, campaigns[i].ToResponse())
+	}
+
+	helpers.SendSuccess(c, "Acknowledgment campaigns retrieved successfully", responses)
+}
+
+// GetCampaign returns an acknowledgment campaign with its progress
+// GET /api/acknowledgment-campaigns/:campaignId
+func (h *AcknowledgmentHandler) GetCampaign(c *gin.Context) {
+	campaignID, err := primitive.ObjectIDFromHex(c.Param("campaignId"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid campaign ID format")
+		return
+	}
+
+	campaign, err := h.acknowledgmentService.GetByID(c.Request.Context(), campaignID)
+	if err != nil {
+		if err.Error() == "acknowledgment campaign not found" {
+			helpers.SendNotFound(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Acknowledgment campaign retrieved successfully", campaign.ToResponse())
+}
+
+// GetRecipients returns the per-user acknowledgment status of a campaign
+// GET /api/acknowledgment-campaigns/:campaignId/recipients?status=pending
+func (h *AcknowledgmentHandler) GetRecipients(c *gin.Context) {
+	campaignID, err := primitive.ObjectIDFromHex(c.Param("campaignId"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid campaign ID format")
+		return
+	}
+
+	status := c.Query("status")
+	if status != "" && !models.IsValidAcknowledgmentStatus(status) {
+		helpers.SendBadRequest(c, "Invalid acknowledgment status: "+status)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.acknowledgmentService.GetByID(ctx, campaignID); err != nil {
+		if err.Error() == "acknowledgment campaign not found" {
+			helpers.SendNotFound(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	page, limit := helpers.GetPaginationParams(c)
+	recipients, total, err := h.acknowledgmentService.GetRecipients(ctx, campaignID, status, page, limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Acknowledgment recipients retrieved successfully", recipients, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	})
+}
+
+// Acknowledge confirms the current user has read the document of a campaign
+// POST /api/acknowledgment-campaigns/:campaignId/acknowledge
+func (h *AcknowledgmentHandler) Acknowledge(c *gin.Context) {
+	campaignID, err := primitive.ObjectIDFromHex(c.Param("campaignId"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid campaign ID format")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+	campaign, err := h.acknowledgmentService.Acknowledge(ctx, campaignID, userID)
+	if err != nil {
+		switch err.Error() {
+		case "acknowledgment campaign not found":
+			helpers.SendNotFound(c, err.Error())
+		case "you are not a recipient of this campaign":
+			helpers.SendForbidden(c, err.Error(), models.CodeForbidden)
+		case "acknowledgment campaign is closed":
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_acknowledged",
+		Description:  fmt.Sprintf("Acknowledged reading %s version %s", campaign.Reference, campaign.Version),
+		ResourceType: "document",
+		ResourceID:   &campaign.DocumentID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"campaignId": campaign.ID.Hex(),
+			"version":    campaign.Version,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Document acknowledged successfully", campaign.ToResponse())
+}
+
+// CloseCampaign ends an acknowledgment campaign
+// POST /api/acknowledgment-campaigns/:campaignId/close
+func (h *AcknowledgmentHandler) CloseCampaign(c *gin.Context) {
+	campaignID, err := primitive.ObjectIDFromHex(c.Param("campaignId"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid campaign ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+	campaign, err := h.acknowledgmentService.Close(ctx, campaignID)
+	if err != nil {
+		switch err.Error() {
+		case "acknowledgment campaign not found":
+			helpers.SendNotFound(c, err.Error())
+		case "acknowledgment campaign is already closed":
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "acknowledgment_campaign_closed",
+		Description:  fmt.Sprintf("Closed the read acknowledgment campaign for %s at %.0f%% completion", campaign.Reference, campaign.CompletionPercent()),
+		ResourceType: "document",
+		ResourceID:   &campaign.DocumentID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"campaignId":        campaign.ID.Hex(),
+			"acknowledgedCount": campaign.AcknowledgedCount,
+			"totalRecipients":   campaign.TotalRecipients,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Acknowledgment campaign closed successfully", campaign.ToResponse())
+}
+
+// ListPending returns the documents the current user still has to acknowledge
+// GET /api/acknowledgments/pending
+func (h *AcknowledgmentHandler) ListPending(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	pending, err := h.acknowledgmentService.ListPendingForUser(c.Request.Context(), userID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Pending acknowledgments retrieved successfully", pending)
+}
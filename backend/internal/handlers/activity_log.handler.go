@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"strconv"
 	"time"
 
@@ -27,8 +26,7 @@ func NewActivityLogHandler(activityLogService *services.ActivityLogService) *Act
 // GetActivityLogs returns activity logs with filters and pagination
 // GET /api/activity-logs
 func (h *ActivityLogHandler) GetActivityLogs(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Parse filters from query parameters
 	filters := models.ActivityLogFilters{
@@ -49,7 +47,31 @@ func (h *ActivityLogHandler) GetActivityLogs(c *gin.Context) {
 		}
 	}
 
-	// Parse filters
+	parseActivityLogFilters(c, &filters)
+
+	// Get activity logs
+	activityLogs, total, err := h.activityLogService.GetActivityLogs(ctx, filters)
+	if err != nil {
+		helpers.SendErrorWithCode(c, 500, "Failed to retrieve activity logs", err.Error())
+		return
+	}
+
+	// Convert to response format
+	responses := h.activityLogService.ToResponseList(activityLogs)
+
+	// Calculate pagination info
+	totalPages := (int(total) + filters.Limit - 1) / filters.Limit
+
+	helpers.SendSuccessWithPagination(c, "Activity logs retrieved successfully", responses, helpers.PaginationInfo{
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	})
+}
+
+// parseActivityLogFilters reads the activity log filters from the query parameters
+func parseActivityLogFilters(c *gin.Context, filters *models.ActivityLogFilters) {
 	if userIDStr := c.Query("userId"); userIDStr != "" {
 		if userID, err := primitive.ObjectIDFromHex(userIDStr); err == nil {
 			filters.UserID = &userID
@@ -106,33 +128,12 @@ func (h *ActivityLogHandler) GetActivityLogs(c *gin.Context) {
 			filters.DateTo = &dateTo
 		}
 	}
-
-	// Get activity logs
-	activityLogs, total, err := h.activityLogService.GetActivityLogs(ctx, filters)
-	if err != nil {
-		helpers.SendErrorWithCode(c, 500, "Failed to retrieve activity logs", err.Error())
-		return
-	}
-
-	// Convert to response format
-	responses := h.activityLogService.ToResponseList(activityLogs)
-
-	// Calculate pagination info
-	totalPages := (int(total) + filters.Limit - 1) / filters.Limit
-
-	helpers.SendSuccessWithPagination(c, "Activity logs retrieved successfully", responses, helpers.PaginationInfo{
-		Page:       filters.Page,
-		Limit:      filters.Limit,
-		Total:      int(total),
-		TotalPages: totalPages,
-	})
 }
 
 // GetActivityLogByID returns a specific activity log by ID
 // GET /api/activity-logs/:id
 func (h *ActivityLogHandler) GetActivityLogByID(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Parse activity log ID
 	idStr := c.Param("id")
@@ -158,8 +159,7 @@ func (h *ActivityLogHandler) GetActivityLogByID(c *gin.Context) {
 // GetUserActivitySummary returns activity summary for a specific user
 // GET /api/activity-logs/users/:userId/summary
 func (h *ActivityLogHandler) GetUserActivitySummary(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Parse user ID
 	userIDStr := c.Param("userId")
@@ -190,8 +190,7 @@ func (h *ActivityLogHandler) GetUserActivitySummary(c *gin.Context) {
 // GetMyActivityLogs returns activity logs for the authenticated user
 // GET /api/activity-logs/me
 func (h *ActivityLogHandler) GetMyActivityLogs(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Get authenticated user
 	user, exists := c.Get("user")
@@ -276,8 +275,7 @@ func (h *ActivityLogHandler) GetMyActivityLogs(c *gin.Context) {
 // GetMyActivitySummary returns activity summary for the authenticated user
 // GET /api/activity-logs/me/summary
 func (h *ActivityLogHandler) GetMyActivitySummary(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Get authenticated user
 	user, exists := c.Get("user")
@@ -313,8 +311,7 @@ func (h *ActivityLogHandler) GetMyActivitySummary(c *gin.Context) {
 // GetActivityLogStats returns general activity log statistics (admin only)
 // GET /api/activity-logs/stats
 func (h *ActivityLogHandler) GetActivityLogStats(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Get activity log statistics
 	stats, err := h.activityLogService.GetActivityLogStats(ctx)
@@ -329,8 +326,7 @@ func (h *ActivityLogHandler) GetActivityLogStats(c *gin.Context) {
 // CreateActivityLog manually creates an activity log (admin only)
 // POST /api/activity-logs
 func (h *ActivityLogHandler) CreateActivityLog(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Parse request body
 	var req models.ActivityLogRequest
@@ -362,8 +358,7 @@ func (h *ActivityLogHandler) CreateActivityLog(c *gin.Context) {
 // DeleteOldActivityLogs removes old activity logs (admin only)
 // DELETE /api/activity-logs/cleanup
 func (h *ActivityLogHandler) DeleteOldActivityLogs(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Parse olderThanDays parameter (default: 365 days)
 	olderThanDays := 365
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ActorHandler handles the implicated actors registry
+type ActorHandler struct {
+	actorService    *services.ActorService
+	documentService *services.DocumentService
+}
+
+// NewActorHandler creates a new actor handler instance
+func NewActorHandler(actorService *services.ActorService, documentService *services.DocumentService) *ActorHandler {
+	return &ActorHandler{
+		actorService:    actorService,
+		documentService: documentService,
+	}
+}
+
+// sendActorError maps actor service errors to HTTP responses
+func sendActorError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "actor not found":
+		helpers.SendNotFound(c, "Actor not found")
+	case strings.HasPrefix(err.Error(), "actor already exists"):
+		helpers.SendConflict(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
+
+// GetActors returns registered actors with optional filtering and pagination
+// GET /api/actors?search=&departmentId=&active=&page=1&limit=20
+func (h *ActorHandler) GetActors(c *gin.Context) {
+	page, limit := helpers.GetPaginationParams(c)
+	filter := &models.ActorFilter{
+		Page:  page,
+		Limit: limit,
+	}
+
+	if search := c.Query("search"); search != "" {
+		filter.Search = &search
+	}
+	if departmentID := c.Query("departmentId"); departmentID != "" {
+		objID, err := primitive.ObjectIDFromHex(departmentID)
+		if err != nil {
+			helpers.SendBadRequest(c, "Invalid departmentId format")
+			return
+		}
+		filter.DepartmentID = &objID
+	}
+	if active := c.Query("active"); active != "" {
+		if isActive, err := strconv.ParseBool(active); err == nil {
+			filter.Active = &isActive
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	actors, total, err := h.actorService.List(ctx, filter)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Actors retrieved successfully", actors, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
+
+// Autocomplete returns actor suggestions for metadata editing
+// GET /api/actors/autocomplete?q=&limit=10
+func (h *ActorHandler) Autocomplete(c *gin.Context) {
+	limit := 10
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 50 {
+			limit = parsed
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	suggestions, err := h.actorService.Autocomplete(ctx, c.Query("q"), limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Actor suggestions retrieved successfully", suggestions)
+}
+
+// GetActor returns a specific actor
+// GET /api/actors/:id
+func (h *ActorHandler) GetActor(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid actor ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	actor, err := h.actorService.GetByID(ctx, id)
+	if err != nil {
+		sendActorError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Actor retrieved successfully", actor)
+}
+
+// CreateActor registers a new actor
+// POST /api/actors
+func (h *ActorHandler) CreateActor(c *gin.Context) {
+	var req models.CreateActorRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	actor, err := h.actorService.Create(ctx, &req, userID)
+	if err != nil {
+		sendActorError(c, err)
+		return
+	}
+
+	helpers.SendCreated(c, "Actor created successfully", actor)
+}
+
+// UpdateActor updates an actor
+// PUT /api/actors/:id
+func (h *ActorHandler) UpdateActor(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid actor ID format")
+		return
+	}
+
+	var req models.UpdateActorRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	actor, err := h.actorService.Update(ctx, id, &req)
+	if err != nil {
+		sendActorError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Actor updated successfully", actor)
+}
+
+// DeleteActor removes an actor from the registry
+// DELETE /api/actors/:id
+func (h *ActorHandler) DeleteActor(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid actor ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if err := h.actorService.Delete(ctx, id); err != nil {
+		sendActorError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Actor deleted successfully", nil)
+}
+
+// NormalizeDocuments rewrites implicated actors of existing documents to canonical names
+// POST /api/actors/normalize
+func (h *ActorHandler) NormalizeDocuments(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	updated, err := h.documentService.NormalizeImplicatedActors(ctx)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Implicated actors normalized successfully", gin.H{
+		"updatedDocuments": updated,
+	})
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AnalyticsHandler handles usage analytics requests
+type AnalyticsHandler struct {
+	analyticsService *services.AnalyticsService
+	documentService  *services.DocumentService
+	userService      *services.UserService
+}
+
+// NewAnalyticsHandler creates a new analytics handler instance
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService, documentService *services.DocumentService, userService *services.UserService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+		documentService:  documentService,
+		userService:      userService,
+	}
+}
+
+// RecordDocumentView records a consultation of a published document
+// POST /api/documents/:id/views
+func (h *AnalyticsHandler) RecordDocumentView(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	var req models.RecordViewRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.documentService.GetByID(ctx, documentID)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	// Opening any document completes the onboarding step, tracked or not
+	if err := h.userService.MarkOnboardingStep(ctx, user.ID, models.OnboardingFirstDocumentViewed); err != nil {
+		fmt.Printf("⚠️  Failed to mark onboarding step %s: %v\n", models.OnboardingFirstDocumentViewed, err)
+	}
+
+	// Only consultations of published procedures are measured, not review work
+	if !document.Status.IsPublished() {
+		helpers.SendSuccess(c, "View not tracked for unpublished document", gin.H{"tracked": false})
+		return
+	}
+
+	if err := h.analyticsService.RecordDocumentView(ctx, document, user, req.DurationSeconds); err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "View recorded successfully", gin.H{"tracked": true})
+}
+
+// GetDocumentAnalytics returns the consultation statistics of a document.
+// Managers and the document creator see aggregates; admins also see top readers.
+// GET /api/documents/:id/analytics?days=30
+func (h *AnalyticsHandler) GetDocumentAnalytics(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	var from *time.Time
+	if daysStr := c.Query("days"); daysStr != "" {
+		if days, err := strconv.Atoi(daysStr); err == nil && days > 0 && days <= 365 {
+			since := time.Now().AddDate(0, 0, -days)
+			from = &since
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.documentService.GetByID(ctx, documentID)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	if user.Role != models.RoleAdmin && user.Role != models.RoleManager && document.CreatedBy != user.ID {
+		helpers.SendForbidden(c, "Only managers and the document creator can view analytics", models.CodeForbidden)
+		return
+	}
+
+	analytics, err := h.analyticsService.GetDocumentAnalytics(ctx, documentID, from, user.Role == models.RoleAdmin)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Document analytics retrieved successfully", analytics)
+}
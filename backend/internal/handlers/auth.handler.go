@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/i18n"
 	"github.com/kodesonik/process-manager/internal/middleware"
 	"github.com/kodesonik/process-manager/internal/models"
 	"github.com/kodesonik/process-manager/internal/services"
@@ -16,23 +17,27 @@ import (
 
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
-	userService  *services.UserService
-	jwtService   *services.JWTService
-	emailService *services.EmailService
-	otpService   *services.OTPService
-	minioService *services.MinIOService
-	pinService   *services.PinService
+	userService   *services.UserService
+	jwtService    *services.JWTService
+	emailService  *services.EmailService
+	otpService    *services.OTPService
+	minioService  *services.MinIOService
+	pinService    *services.PinService
+	policyService *services.PolicyService
+	asyncRunner   *services.AsyncRunner
 }
 
 // NewAuthHandler creates a new auth handler instance
-func NewAuthHandler(userService *services.UserService, jwtService *services.JWTService, emailService *services.EmailService, otpService *services.OTPService, minioService *services.MinIOService, pinService *services.PinService) *AuthHandler {
+func NewAuthHandler(userService *services.UserService, jwtService *services.JWTService, emailService *services.EmailService, otpService *services.OTPService, minioService *services.MinIOService, pinService *services.PinService, policyService *services.PolicyService, asyncRunner *services.AsyncRunner) *AuthHandler {
 	return &AuthHandler{
-		userService:  userService,
-		jwtService:   jwtService,
-		emailService: emailService,
-		otpService:   otpService,
-		minioService: minioService,
-		pinService:   pinService,
+		userService:   userService,
+		jwtService:    jwtService,
+		emailService:  emailService,
+		otpService:    otpService,
+		minioService:  minioService,
+		pinService:    pinService,
+		policyService: policyService,
+		asyncRunner:   asyncRunner,
 	}
 }
 
@@ -45,8 +50,7 @@ func (h *AuthHandler) RequestOTP(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Find user by email
 	user, err := h.userService.GetUserByEmail(ctx, req.Email)
@@ -95,12 +99,14 @@ func (h *AuthHandler) RequestOTP(c *gin.Context) {
 
 	// Send OTP via email asynchronously to avoid blocking the response
 	fullName := user.FirstName + " " + user.LastName
-	go func() {
-		if err := h.emailService.SendOTPEmail(user.Email, fullName, otp); err != nil {
+	lang := emailLanguage(c, user)
+	h.asyncRunner.Go("login_otp_email", func(ctx context.Context) error {
+		if err := h.emailService.SendOTPEmail(user.Email, fullName, otp, lang); err != nil {
 			// Log error but don't block the response
-			fmt.Printf("Failed to send OTP email to %s: %v\n", user.Email, err)
+			return fmt.Errorf("failed to send OTP email to %s: %w", user.Email, err)
 		}
-	}()
+		return nil
+	})
 
 	// Check if development mode
 	isDevelopment := os.Getenv("GIN_MODE") == "debug" || os.Getenv("DEVELOPMENT_MODE") == "true"
@@ -138,8 +144,7 @@ func (h *AuthHandler) VerifyOTP(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Get email from temporary token
 	email, err := h.otpService.GetEmailFromTemporaryToken(ctx, tempToken)
@@ -200,8 +205,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	var req models.LogoutRequest
 	if err := c.ShouldBindJSON(&req); err == nil && req.RefreshToken != "" {
 		// Revoke the specific refresh token in Redis
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
-		defer cancel()
+		ctx := c.Request.Context()
 
 		if err := h.otpService.RevokeRefreshToken(ctx, req.RefreshToken); err != nil {
 			// Log error but continue with logout
@@ -220,8 +224,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Validate refresh token in Redis
 	userIDStr, err := h.otpService.ValidateRefreshToken(ctx, req.RefreshToken)
@@ -283,8 +286,7 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Get user response with populated details
 	userResponse, err := h.userService.ToResponseWithDetails(ctx, user)
@@ -292,6 +294,10 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 		// Fallback to basic response if population fails
 		userResponse = user.ToResponse()
 	}
+	userResponse.Onboarding = user.OnboardingState()
+	if pending, err := h.policyService.PendingForUser(ctx, user); err == nil && len(pending) > 0 {
+		userResponse.PendingPolicies = pending
+	}
 
 	helpers.SendSuccess(c, "User information retrieved successfully", userResponse)
 }
@@ -311,8 +317,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	updatedUser, err := h.userService.UpdateUser(ctx, userID, &req)
 	if err != nil {
@@ -320,6 +325,10 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	if updatedUser.ProfileCompleted() {
+		h.markOnboardingStep(ctx, userID, models.OnboardingProfileCompleted)
+	}
+
 	// Get user response with populated details
 	userResponse, err := h.userService.ToResponseWithDetails(ctx, updatedUser)
 	if err != nil {
@@ -351,14 +360,15 @@ func (h *AuthHandler) SetPin(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	if err := h.pinService.SetPin(ctx, user.ID, req.Pin); err != nil {
 		helpers.SendInternalError(c, err)
 		return
 	}
 
+	h.markOnboardingStep(ctx, user.ID, models.OnboardingPinSet)
+
 	helpers.SendSuccess(c, "PIN set successfully", nil)
 }
 
@@ -394,8 +404,7 @@ func (h *AuthHandler) VerifyPin(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Re-fetch user to get latest attempts/lock status
 	freshUser, err := h.userService.GetUserByID(ctx, user.ID)
@@ -427,8 +436,7 @@ func (h *AuthHandler) LoginWithPin(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Get user by email
 	user, err := h.userService.GetUserByEmail(ctx, req.Email)
@@ -489,8 +497,7 @@ func (h *AuthHandler) RevokeAllTokens(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	err := h.otpService.RevokeAllUserRefreshTokens(ctx, userID.Hex())
 	if err != nil {
@@ -514,8 +521,7 @@ func (h *AuthHandler) RegisterStep1(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Check if user already exists
 	existingUser, err := h.userService.GetUserByEmail(ctx, req.Email)
@@ -539,7 +545,7 @@ func (h *AuthHandler) RegisterStep1(c *gin.Context) {
 	}
 
 	// Send OTP via email
-	if err := h.emailService.SendRegistrationOTPEmail(req.Email, otp); err != nil {
+	if err := h.emailService.SendRegistrationOTPEmail(req.Email, otp, i18n.GetLanguageFromContext(c)); err != nil {
 		helpers.SendInternalError(c, err)
 		return
 	}
@@ -577,8 +583,7 @@ func (h *AuthHandler) RegisterStep2(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Get email from temporary token
 	email, err := h.otpService.GetEmailFromTemporaryToken(ctx, tempToken)
@@ -628,8 +633,12 @@ func (h *AuthHandler) RegisterStep3(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
+
+	// Emails are sent in the language used to register unless another one is chosen
+	if req.Language == "" {
+		req.Language = i18n.GetLanguageFromContext(c)
+	}
 
 	// Get email from registration token
 	email, err := h.otpService.GetEmailFromRegistrationToken(ctx, regToken)
@@ -657,7 +666,7 @@ func (h *AuthHandler) RegisterStep3(c *gin.Context) {
 
 	// Send registration pending email to admins
 	fullName := createdUser.FirstName + " " + createdUser.LastName
-	if err := h.emailService.SendRegistrationPendingEmail(createdUser.Email, fullName); err != nil {
+	if err := h.emailService.SendRegistrationPendingEmail(createdUser.Email, fullName, createdUser.Language); err != nil {
 		// Log error but don't fail the registration
 	}
 
@@ -716,8 +725,7 @@ func (h *AuthHandler) UploadAvatar(c *gin.Context) {
 	}
 	defer file.Close()
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Delete old avatar if exists
 	if user.Avatar != "" {
@@ -755,6 +763,8 @@ func (h *AuthHandler) UploadAvatar(c *gin.Context) {
 		return
 	}
 
+	h.markOnboardingStep(ctx, user.ID, models.OnboardingAvatarUploaded)
+
 	// Return success response
 	response := gin.H{
 		"userId":  updatedUser.ID.Hex(),
@@ -780,8 +790,7 @@ func (h *AuthHandler) DeleteAvatar(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Delete avatar from MinIO
 	err := h.minioService.DeleteAvatar(ctx, user.Avatar)
@@ -808,3 +817,10 @@ func (h *AuthHandler) DeleteAvatar(c *gin.Context) {
 
 	helpers.SendSuccess(c, "Profile picture deleted successfully", response)
 }
+
+// markOnboardingStep records an onboarding milestone without failing the request
+func (h *AuthHandler) markOnboardingStep(ctx context.Context, userID primitive.ObjectID, step models.OnboardingStep) {
+	if err := h.userService.MarkOnboardingStep(ctx, userID, step); err != nil {
+		fmt.Printf("⚠️  Failed to mark onboarding step %s: %v\n", step, err)
+	}
+}
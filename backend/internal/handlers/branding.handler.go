@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// maxLogoSizeMB bounds the size of an uploaded logo
+const maxLogoSizeMB = 2
+
+// BrandingHandler handles the organization branding settings
+type BrandingHandler struct {
+	brandingService    *services.BrandingService
+	activityLogService *services.ActivityLogService
+}
+
+// NewBrandingHandler creates a new branding handler instance
+func NewBrandingHandler(brandingService *services.BrandingService, activityLogService *services.ActivityLogService) *BrandingHandler {
+	return &BrandingHandler{
+		brandingService:    brandingService,
+		activityLogService: activityLogService,
+	}
+}
+
+// GetBranding returns the organization branding
+// GET /api/branding
+func (h *BrandingHandler) GetBranding(c *gin.Context) {
+	helpers.SendSuccess(c, "Branding retrieved successfully", h.brandingService.Get(c.Request.Context()))
+}
+
+// UpdateBranding updates the organization branding
+// PUT /api/branding
+func (h *BrandingHandler) UpdateBranding(c *gin.Context) {
+	var req models.UpdateBrandingRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	branding, err := h.brandingService.Update(ctx, &req, userID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	h.logBrandingUpdate(c, "Updated organization branding")
+
+	helpers.SendSuccess(c, "Branding updated successfully", branding)
+}
+
+// UploadLogo replaces the organization logo
+// POST /api/branding/logo
+func (h *BrandingHandler) UploadLogo(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	fileHeader, err := c.FormFile("logo")
+	if err != nil {
+		helpers.SendBadRequest(c, "No file provided. Please include 'logo' field in form")
+		return
+	}
+
+	validation := helpers.ValidateImageUpload(fileHeader, maxLogoSizeMB)
+	if !validation.Valid {
+		helpers.SendBadRequest(c, validation.Error)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		helpers.SendInternalError(c, models.ErrServiceUnavailable)
+		return
+	}
+	defer file.Close()
+
+	branding, err := h.brandingService.UploadLogo(c.Request.Context(), file, fileHeader.Size, validation.ContentType, validation.Filename, userID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	h.logBrandingUpdate(c, "Uploaded organization logo")
+
+	helpers.SendSuccess(c, "Logo uploaded successfully", branding)
+}
+
+// DeleteLogo removes the organization logo
+// DELETE /api/branding/logo
+func (h *BrandingHandler) DeleteLogo(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	branding, err := h.brandingService.DeleteLogo(c.Request.Context(), userID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	h.logBrandingUpdate(c, "Removed organization logo")
+
+	helpers.SendSuccess(c, "Logo removed successfully", branding)
+}
+
+func (h *BrandingHandler) logBrandingUpdate(c *gin.Context, description string) {
+	activityReq := models.ActivityLogRequest{
+		Action:       models.ActionConfigUpdated,
+		Description:  description,
+		ResourceType: "branding",
+		Success:      true,
+	}
+	if logErr := h.activityLogService.LogActivity(c.Request.Context(), activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// CalendarHandler serves the calendar feed of the pending signature deadlines
+type CalendarHandler struct {
+	calendarService    *services.CalendarService
+	activityLogService *services.ActivityLogService
+}
+
+// NewCalendarHandler creates a new calendar handler instance
+func NewCalendarHandler(calendarService *services.CalendarService, activityLogService *services.ActivityLogService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService:    calendarService,
+		activityLogService: activityLogService,
+	}
+}
+
+// GetMyCalendar returns the pending signature deadlines of the user as an
+// iCalendar feed. Calendar apps, which cannot sign in, subscribe to it with
+// the feed token in the token query parameter.
+// GET /api/users/me/calendar.ics
+func (h *CalendarHandler) GetMyCalendar(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		var err error
+		user, err = h.calendarService.UserByFeedToken(ctx, c.Query("token"))
+		if err != nil {
+			if err.Error() == "invalid calendar token" {
+				helpers.SendUnauthorized(c, "Invalid or missing calendar token", "UNAUTHORIZED")
+				return
+			}
+			helpers.SendInternalError(c, err)
+			return
+		}
+	}
+
+	feed, err := h.calendarService.Feed(ctx, user.ID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
+
+// ResetCalendarToken issues the token of the calendar feed of the user,
+// revoking the previous one, and returns the URL to subscribe to
+// POST /api/users/me/calendar-token
+func (h *CalendarHandler) ResetCalendarToken(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	token, err := h.calendarService.IssueFeedToken(ctx, user.ID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			helpers.SendNotFound(c, "User not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	scheme := "https"
+	if c.Request.TLS == nil && c.GetHeader("X-Forwarded-Proto") != "https" {
+		scheme = "http"
+	}
+	feedURL := fmt.Sprintf("%s://%s/api/users/me/calendar.ics?token=%s", scheme, c.Request.Host, token)
+
+	activityReq := models.ActivityLogRequest{
+		Action:       models.ActionUserUpdated,
+		Description:  "Issued a new calendar feed token",
+		ResourceType: "user",
+		ResourceID:   &user.ID,
+		Success:      true,
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Calendar feed token issued successfully", gin.H{
+		"token": token,
+		"url":   feedURL,
+	})
+}
@@ -0,0 +1,561 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CommentHandler handles document review comments
+type CommentHandler struct {
+	commentService      *services.CommentService
+	documentService     *services.DocumentService
+	notificationService *services.NotificationService
+	pdfService          *services.PDFService
+	reactionService     *services.ReactionService
+	asyncRunner         *services.AsyncRunner
+}
+
+// NewCommentHandler creates a new comment handler instance
+func NewCommentHandler(commentService *services.CommentService, documentService *services.DocumentService, notificationService *services.NotificationService, pdfService *services.PDFService, reactionService *services.ReactionService, asyncRunner *services.AsyncRunner) *CommentHandler {
+	return &CommentHandler{
+		commentService:      commentService,
+		documentService:     documentService,
+		notificationService: notificationService,
+		pdfService:          pdfService,
+		reactionService:     reactionService,
+		asyncRunner:         asyncRunner,
+	}
+}
+
+// sendCommentError maps comment service errors to HTTP responses
+func sendCommentError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "comment not found":
+		helpers.SendNotFound(c, "Comment not found")
+	case err.Error() == "document not found":
+		helpers.SendNotFound(c, "Document not found")
+	case strings.HasPrefix(err.Error(), "only "):
+		helpers.SendForbidden(c, err.Error(), models.CodeForbidden)
+	case strings.HasPrefix(err.Error(), "invalid"):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
+
+// parseCommentParams extracts the document and comment IDs from the path
+func parseCommentParams(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	commentID, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid comment ID format")
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return documentID, commentID, true
+}
+
+// ListComments returns the discussion threads of a document
+// GET /api/documents/:id/comments?status=open|resolved
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	var status *models.CommentStatus
+	if s := c.Query("status"); s != "" {
+		commentStatus := models.CommentStatus(s)
+		if commentStatus != models.CommentStatusOpen && commentStatus != models.CommentStatusResolved {
+			helpers.SendBadRequest(c, "Invalid status: must be open or resolved")
+			return
+		}
+		status = &commentStatus
+	}
+
+	ctx := c.Request.Context()
+
+	threads, err := h.commentService.ListThreads(ctx, documentID, status)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	if err := h.attachReactions(ctx, threads, userID); err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Comments retrieved successfully", threads)
+}
+
+// ExportComments downloads the review comments report of a document
+// GET /api/documents/:id/comments/export?format=pdf|xlsx
+func (h *CommentHandler) ExportComments(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	format := c.DefaultQuery("format", "pdf")
+	if format != "pdf" && format != "xlsx" {
+		helpers.SendBadRequest(c, "Invalid format: must be pdf or xlsx")
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	// PDF rendering goes through headless Chrome, which needs a longer budget
+	ctx := c.Request.Context()
+
+	report, err := h.commentService.BuildReport(ctx, documentID, user.FirstName+" "+user.LastName)
+	if err != nil {
+		sendCommentError(c, err)
+		return
+	}
+
+	var (
+		data        []byte
+		contentType string
+	)
+	switch format {
+	case "xlsx":
+		data, err = helpers.WriteXLSX("Commentaires", commentReportHeader, commentReportRows(report))
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		data, err = h.pdfService.GenerateCommentsReportPDF(ctx, report)
+		contentType = "application/pdf"
+	}
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	fileName := fmt.Sprintf("%s_comments_%s.%s", report.Reference, report.GeneratedAt.Format("20060102_150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+var commentReportHeader = []string{
+	"Thread", "Type", "Author", "Created At", "Section", "Comment",
+	"Blocking", "Status", "Resolved By", "Resolved At", "Edits",
+}
+
+// commentReportRows flattens a comment report into spreadsheet rows
+func commentReportRows(report *models.CommentReport) [][]string {
+	rows := make([][]string, 0, len(report.Entries))
+	for _, entry := range report.Entries {
+		kind, blocking, status := "comment", "", ""
+		if entry.IsReply {
+			kind = "reply"
+		} else {
+			blocking = strconv.FormatBool(entry.Blocking)
+			status = string(entry.Status)
+		}
+		content := entry.Content
+		if entry.Deleted {
+			content = "[deleted]"
+		}
+		resolvedAt := ""
+		if entry.ResolvedAt != nil {
+			resolvedAt = entry.ResolvedAt.Format(time.RFC3339)
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(entry.ThreadNumber),
+			kind,
+			entry.AuthorName,
+			entry.CreatedAt.Format(time.RFC3339),
+			entry.Section,
+			content,
+			blocking,
+			status,
+			entry.ResolvedByName,
+			resolvedAt,
+			strconv.Itoa(entry.EditCount),
+		})
+	}
+	return rows
+}
+
+// GetMentionableUsers returns the users who can be @mentioned on a document
+// GET /api/documents/:id/mentionable?q=&limit=
+func (h *CommentHandler) GetMentionableUsers(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	limit := int64(20)
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = int64(l)
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	users, err := h.commentService.ListMentionable(ctx, documentID, c.Query("q"), limit)
+	if err != nil {
+		sendCommentError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Mentionable users retrieved successfully", users)
+}
+
+// CreateComment adds a comment to a document
+// POST /api/documents/:id/comments
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	comment, err := h.commentService.Create(ctx, documentID, &req, user)
+	if err != nil {
+		sendCommentError(c, err)
+		return
+	}
+
+	h.notifyMentions(comment, comment.Mentions, user.ID)
+
+	helpers.SendCreated(c, "Comment created successfully", comment)
+}
+
+// UpdateComment edits a comment, keeping its previous content in the edit history
+// PUT /api/documents/:id/comments/:commentId
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	documentID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	previous, err := h.commentService.GetByID(ctx, documentID, commentID)
+	if err != nil {
+		sendCommentError(c, err)
+		return
+	}
+
+	comment, err := h.commentService.Update(ctx, documentID, commentID, &req, userID)
+	if err != nil {
+		sendCommentError(c, err)
+		return
+	}
+
+	// Only notify users newly mentioned by the edit
+	newMentions := make([]primitive.ObjectID, 0)
+	for _, id := range comment.Mentions {
+		if !slices.Contains(previous.Mentions, id) {
+			newMentions = append(newMentions, id)
+		}
+	}
+	h.notifyMentions(comment, newMentions, userID)
+
+	helpers.SendSuccess(c, "Comment updated successfully", comment)
+}
+
+// DeleteComment deletes a comment
+// DELETE /api/documents/:id/comments/:commentId
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	documentID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if err := h.commentService.Delete(ctx, documentID, commentID, user); err != nil {
+		sendCommentError(c, err)
+		return
+	}
+
+	if err := h.reactionService.RemoveAll(ctx, models.ReactionTargetComment, commentID); err != nil {
+		fmt.Printf("Warning: Failed to remove reactions of comment %s: %v\n", commentID.Hex(), err)
+	}
+
+	helpers.SendSuccess(c, "Comment deleted successfully", nil)
+}
+
+// attachReactions fills in the reaction summaries of threads and their replies
+func (h *CommentHandler) attachReactions(ctx context.Context, threads []models.CommentThread, userID primitive.ObjectID) error {
+	ids := make([]primitive.ObjectID, 0, len(threads))
+	for _, thread := range threads {
+		ids = append(ids, thread.ID)
+		for _, reply := range thread.Replies {
+			ids = append(ids, reply.ID)
+		}
+	}
+
+	summaries, err := h.reactionService.Summaries(ctx, models.ReactionTargetComment, ids, userID)
+	if err != nil {
+		return err
+	}
+	for i := range threads {
+		threads[i].Reactions = summaries[threads[i].ID]
+		for j := range threads[i].Replies {
+			threads[i].Replies[j].Reactions = summaries[threads[i].Replies[j].ID]
+		}
+	}
+	return nil
+}
+
+// AddCommentReaction reacts to a comment
+// POST /api/documents/:id/comments/:commentId/reactions
+func (h *CommentHandler) AddCommentReaction(c *gin.Context) {
+	var req models.ReactRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+	h.setReaction(c, req.Type, true)
+}
+
+// RemoveCommentReaction withdraws a reaction from a comment
+// DELETE /api/documents/:id/comments/:commentId/reactions/:type
+func (h *CommentHandler) RemoveCommentReaction(c *gin.Context) {
+	h.setReaction(c, models.ReactionType(c.Param("type")), false)
+}
+
+func (h *CommentHandler) setReaction(c *gin.Context, reactionType models.ReactionType, add bool) {
+	documentID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	comment, err := h.commentService.GetByID(ctx, documentID, commentID)
+	if err != nil {
+		sendCommentError(c, err)
+		return
+	}
+	if comment.Deleted {
+		helpers.SendNotFound(c, "Comment not found")
+		return
+	}
+
+	if add {
+		err = h.reactionService.Add(ctx, models.ReactionTargetComment, commentID, userID, reactionType)
+	} else {
+		err = h.reactionService.Remove(ctx, models.ReactionTargetComment, commentID, userID, reactionType)
+	}
+	if err != nil {
+		sendCommentError(c, err)
+		return
+	}
+
+	summary, err := h.reactionService.Summary(ctx, models.ReactionTargetComment, commentID, userID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Reaction updated successfully", summary)
+}
+
+// notifyMentions notifies @mentioned users in the background
+func (h *CommentHandler) notifyMentions(comment *models.Comment, mentions []primitive.ObjectID, senderID primitive.ObjectID) {
+	userIDs := make([]string, 0, len(mentions))
+	for _, id := range mentions {
+		if id != senderID {
+			userIDs = append(userIDs, id.Hex())
+		}
+	}
+	if len(userIDs) == 0 || h.notificationService == nil {
+		return
+	}
+
+	h.asyncRunner.Go("comment_mentions", func(ctx context.Context) error {
+		title := "You were mentioned in a comment"
+		if document, err := h.documentService.GetByID(ctx, comment.DocumentID); err == nil {
+			title = fmt.Sprintf("You were mentioned on '%s'", document.Title)
+		}
+
+		notificationReq := &models.SendNotificationRequest{
+			UserIDs:  userIDs,
+			Title:    title,
+			Body:     fmt.Sprintf("%s: %s", comment.AuthorName, truncate(comment.Content, 140)),
+			Category: "document",
+			Data: map[string]interface{}{
+				"documentId": comment.DocumentID.Hex(),
+				"commentId":  comment.ID.Hex(),
+				"action":     "comment_mention",
+			},
+		}
+		if _, err := h.notificationService.SendNotification(ctx, notificationReq, senderID); err != nil {
+			return fmt.Errorf("failed to send mention notifications: %w", err)
+		}
+		return nil
+	})
+}
+
+// truncate shortens text to at most n runes
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n]) + "…"
+}
+
+// ResolveComment marks a comment as resolved
+// POST /api/documents/:id/comments/:commentId/resolve
+func (h *CommentHandler) ResolveComment(c *gin.Context) {
+	h.setResolved(c, true)
+}
+
+// UnresolveComment reopens a resolved comment
+// POST /api/documents/:id/comments/:commentId/unresolve
+func (h *CommentHandler) UnresolveComment(c *gin.Context) {
+	h.setResolved(c, false)
+}
+
+func (h *CommentHandler) setResolved(c *gin.Context, resolved bool) {
+	documentID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	comment, err := h.commentService.SetResolved(ctx, documentID, commentID, resolved, userID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "only thread root") {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		sendCommentError(c, err)
+		return
+	}
+
+	message := "Comment resolved successfully"
+	if !resolved {
+		message = "Comment reopened successfully"
+	}
+	helpers.SendSuccess(c, message, comment)
+}
+
+// GetDocumentSummary returns the review summary of a document: signature
+// progress per team and comment counts
+// GET /api/documents/:id/summary
+func (h *CommentHandler) GetDocumentSummary(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.documentService.GetByID(ctx, documentID)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	counts, err := h.commentService.GetCounts(ctx, documentID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	summary := models.DocumentSummaryResponse{
+		DocumentID: document.ID.Hex(),
+		Reference:  document.Reference,
+		Title:      document.Title,
+		Version:    document.Version,
+		Status:     document.Status,
+		Comments:   *counts,
+		UpdatedAt:  document.UpdatedAt,
+	}
+	summary.Signatures.Authors = signatureProgress(document.Contributors.Authors)
+	summary.Signatures.Verifiers = signatureProgress(document.Contributors.Verifiers)
+	summary.Signatures.Validators = signatureProgress(document.Contributors.Validators)
+
+	helpers.SendSuccess(c, "Document summary retrieved successfully", summary)
+}
+
+// signatureProgress counts signed contributors of a team
+func signatureProgress(contributors []models.Contributor) models.SignatureProgress {
+	progress := models.SignatureProgress{Required: len(contributors)}
+	for _, contributor := range contributors {
+		if contributor.Status == models.SignatureStatusSigned {
+			progress.Signed++
+		}
+	}
+	return progress
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"context"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,21 +15,22 @@ import (
 
 // DepartmentHandler handles department-related HTTP requests
 type DepartmentHandler struct {
-	db *services.DatabaseService
+	db              *services.DatabaseService
+	templateService *services.ContributorTemplateService
 }
 
 // NewDepartmentHandler creates a new department handler instance
-func NewDepartmentHandler(db *services.DatabaseService) *DepartmentHandler {
+func NewDepartmentHandler(db *services.DatabaseService, templateService *services.ContributorTemplateService) *DepartmentHandler {
 	return &DepartmentHandler{
-		db: db,
+		db:              db,
+		templateService: templateService,
 	}
 }
 
 // GetDepartments returns all departments with optional filtering
 // GET /api/departments
 func (h *DepartmentHandler) GetDepartments(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Build filter based on query parameters
 	filter := bson.M{}
@@ -91,8 +92,7 @@ func (h *DepartmentHandler) GetDepartment(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	collection := h.db.Collection("departments")
 	var department models.Department
@@ -121,8 +121,7 @@ func (h *DepartmentHandler) CreateDepartment(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Check if department code already exists
 	collection := h.db.Collection("departments")
@@ -204,8 +203,7 @@ func (h *DepartmentHandler) UpdateDepartment(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	collection := h.db.Collection("departments")
 
@@ -313,8 +311,7 @@ func (h *DepartmentHandler) DeleteDepartment(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	collection := h.db.Collection("departments")
 
@@ -366,3 +363,90 @@ func (h *DepartmentHandler) DeleteDepartment(c *gin.Context) {
 
 	helpers.SendSuccess(c, "Department deleted successfully", gin.H{"deleted_id": departmentID})
 }
+
+// GetDefaultContributors returns the contributors the department template adds to new documents
+// GET /api/departments/:id/default-contributors
+func (h *DepartmentHandler) GetDefaultContributors(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid department ID format")
+		return
+	}
+
+	contributors, template, err := h.templateService.Resolve(c.Request.Context(), objID)
+	if err != nil {
+		sendContributorTemplateError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Default contributors retrieved successfully", gin.H{
+		"template":     template,
+		"contributors": contributors,
+	})
+}
+
+// SetContributorTemplate replaces the default verifiers and validators of a department
+// PUT /api/departments/:id/contributor-template
+func (h *DepartmentHandler) SetContributorTemplate(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid department ID format")
+		return
+	}
+
+	var req models.ContributorTemplate
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	department, err := h.templateService.Set(c.Request.Context(), objID, &req, userID)
+	if err != nil {
+		sendContributorTemplateError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Contributor template updated successfully", department.ToResponse())
+}
+
+// ClearContributorTemplate removes the default contributors of a department
+// DELETE /api/departments/:id/contributor-template
+func (h *DepartmentHandler) ClearContributorTemplate(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid department ID format")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	department, err := h.templateService.Clear(c.Request.Context(), objID, userID)
+	if err != nil {
+		sendContributorTemplateError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Contributor template removed successfully", department.ToResponse())
+}
+
+// sendContributorTemplateError maps contributor template errors to HTTP responses
+func sendContributorTemplateError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "department not found":
+		helpers.SendNotFound(c, "Department not found")
+	case strings.HasPrefix(err.Error(), "each template entry"), strings.HasPrefix(err.Error(), "template "):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// DisplayHandler handles QR login for meeting-room and NOC displays
+type DisplayHandler struct {
+	displayService  *services.DisplaySessionService
+	jwtService      *services.JWTService
+	userService     *services.UserService
+	documentService *services.DocumentService
+}
+
+// NewDisplayHandler creates a new display handler instance
+func NewDisplayHandler(displayService *services.DisplaySessionService, jwtService *services.JWTService, userService *services.UserService, documentService *services.DocumentService) *DisplayHandler {
+	return &DisplayHandler{
+		displayService:  displayService,
+		jwtService:      jwtService,
+		userService:     userService,
+		documentService: documentService,
+	}
+}
+
+// RequestCode creates a new display login code to be rendered as a QR code
+// POST /api/auth/display/code
+func (h *DisplayHandler) RequestCode(c *gin.Context) {
+	var req models.DisplayCodeRequest
+	if c.Request.ContentLength > 0 {
+		if err := helpers.BindAndValidate(c, &req); err != nil {
+			helpers.SendValidationErrors(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	session, err := h.displayService.CreateSession(ctx, req.DisplayName)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Display code created", models.DisplayCodeResponse{
+		DeviceCode:      session.DeviceCode,
+		UserCode:        session.UserCode,
+		VerificationURI: h.displayService.GetVerificationURI(session.UserCode),
+		ExpiresIn:       int(h.displayService.GetCodeExpiry().Seconds()),
+		Interval:        int(h.displayService.GetPollInterval().Seconds()),
+	})
+}
+
+// ApproveCode lets a logged-in user approve (or deny) a display code scanned from the screen
+// POST /api/auth/display/approve
+func (h *DisplayHandler) ApproveCode(c *gin.Context) {
+	var req models.ApproveDisplayRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	approve := req.Approve == nil || *req.Approve
+	session, err := h.displayService.Resolve(ctx, req.UserCode, user.ID, approve)
+	if err != nil {
+		switch err {
+		case models.ErrDisplayCodeNotFound:
+			helpers.SendNotFound(c, "Display code not found or expired")
+		case models.ErrDisplayCodeUsed:
+			helpers.SendConflict(c, "Display code has already been used")
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	fmt.Printf("📺 [DISPLAY] Code %s %s by %s\n", session.UserCode, session.Status, user.Email)
+
+	helpers.SendSuccess(c, "Display code "+string(session.Status), gin.H{
+		"userCode":    session.UserCode,
+		"displayName": session.DisplayName,
+		"status":      session.Status,
+	})
+}
+
+// PollToken is called by the display until the code is approved or denied
+// POST /api/auth/display/token
+func (h *DisplayHandler) PollToken(c *gin.Context) {
+	var req models.DisplayTokenRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	session, err := h.displayService.GetByDeviceCode(ctx, req.DeviceCode)
+	if err != nil {
+		if err == models.ErrDisplayCodeNotFound {
+			helpers.SendNotFound(c, "Display code not found or expired")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	if session.Status == models.DisplaySessionStatusPending {
+		c.JSON(http.StatusAccepted, models.NewSuccessResponse("Waiting for approval", models.DisplayTokenResponse{
+			Status: session.Status,
+		}))
+		return
+	}
+
+	// Device codes are single use: the resolved session is taken out of
+	// Redis before a token is issued, a concurrent poll finds it gone
+	session, err = h.displayService.Consume(ctx, req.DeviceCode)
+	if err != nil {
+		if err == models.ErrDisplayCodeNotFound {
+			helpers.SendNotFound(c, "Display code not found or expired")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+	if session.Status == models.DisplaySessionStatusDenied {
+		helpers.SendForbidden(c, "Display code was denied", models.CodeForbidden)
+		return
+	}
+
+	user, err := h.userService.GetUserByID(ctx, *session.ApprovedBy)
+	if err != nil {
+		helpers.SendError(c, err)
+		return
+	}
+	if !user.CanLogin() {
+		helpers.SendError(c, models.GetAccountStatusError(user.Status))
+		return
+	}
+
+	token, expiresAt, err := h.jwtService.GenerateDisplayToken(user)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Display session created", models.DisplayTokenResponse{
+		Status:      session.Status,
+		AccessToken: token,
+		ExpiresAt:   &expiresAt,
+		TokenType:   "Bearer",
+	})
+}
+
+// GetDashboard returns procedure statuses for wallboards, restricted to the
+// documents the user who approved the display can access
+// GET /api/display/dashboard
+func (h *DisplayHandler) GetDashboard(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	counts, err := h.documentService.GetStatusCounts(ctx, user.ID, user.Role)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	page, limit := helpers.GetPaginationParams(c)
+	filter := &models.DocumentFilter{Page: page, Limit: limit}
+	if status := c.Query("status"); status != "" {
+		docStatus := models.DocumentStatus(status)
+		filter.Status = &docStatus
+	}
+
+	documents, _, err := h.documentService.ListUserAccessible(ctx, user.ID, user.Role, filter)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	items := make([]models.DisplayDocumentStatus, 0, len(documents))
+	for _, doc := range documents {
+		items = append(items, models.DisplayDocumentStatus{
+			ID:          doc.ID.Hex(),
+			ProcessCode: doc.ProcessCode,
+			Reference:   doc.Reference,
+			Title:       doc.Title,
+			Version:     doc.Version,
+			Status:      doc.Status,
+			UpdatedAt:   doc.UpdatedAt,
+		})
+	}
+
+	helpers.SendSuccess(c, "Dashboard retrieved successfully", models.DisplayDashboardResponse{
+		StatusCounts: counts,
+		Documents:    items,
+		GeneratedAt:  time.Now(),
+	})
+}
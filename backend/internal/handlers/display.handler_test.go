@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// matchesFilter evaluates the subset of MongoDB filters built by the document
+// lists ($and, $or, $in, $exists and equality) against a flat document
+func matchesFilter(filter bson.M, doc bson.M) bool {
+	for key, cond := range filter {
+		switch key {
+		case "$and", "$or":
+			clauses := cond.(bson.A)
+			matched := key == "$and"
+			for _, clause := range clauses {
+				ok := matchesFilter(clause.(bson.M), doc)
+				if key == "$and" && !ok {
+					matched = false
+				}
+				if key == "$or" && ok {
+					matched = true
+				}
+			}
+			if !matched {
+				return false
+			}
+		default:
+			value, exists := doc[key]
+			if ops, ok := cond.(bson.M); ok {
+				if want, ok := ops["$exists"]; ok && want.(bool) != exists {
+					return false
+				}
+				if in, ok := ops["$in"]; ok {
+					found := false
+					for _, v := range in.(bson.A) {
+						if v == value {
+							found = true
+						}
+					}
+					if !found {
+						return false
+					}
+				}
+				continue
+			}
+			if !exists || value != cond {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestGetDashboardScopesDocumentsToApprovingUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("non-admin display", func(mt *mtest.T) {
+		viewer := &models.User{ID: primitive.NewObjectID(), Role: models.RoleUser, Active: true}
+		otherAuthor := primitive.NewObjectID()
+
+		ns := mt.DB.Name() + ".documents"
+		invitations := mt.DB.Name() + ".invitations"
+		mt.AddMockResponses(
+			// Status counts: accepted invitations, then the aggregation
+			mtest.CreateCursorResponse(0, invitations, mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: string(models.DocumentStatusApproved)}, {Key: "count", Value: int64(1)}}),
+			// Document list: accepted invitations, count, then the page
+			mtest.CreateCursorResponse(0, invitations, mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(0)}}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch),
+		)
+
+		documentService := services.NewDocumentService(mt.DB, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		handler := NewDisplayHandler(nil, nil, nil, documentService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/display/dashboard?status=draft", nil)
+		c.Set("user", viewer)
+		c.Set("read_only", true)
+
+		handler.GetDashboard(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		// Draft of another department, which the viewer neither created nor contributes to
+		otherDraft := bson.M{"created_by": otherAuthor, "status": string(models.DocumentStatusDraft)}
+		ownDraft := bson.M{"created_by": viewer.ID, "status": string(models.DocumentStatusDraft)}
+
+		var countsMatch, listFilter bson.M
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName == "aggregate" && countsMatch == nil {
+				var cmd struct {
+					Pipeline []bson.M `bson:"pipeline"`
+				}
+				if err := bson.Unmarshal(event.Command, &cmd); err != nil {
+					t.Fatal(err)
+				}
+				countsMatch = cmd.Pipeline[0]["$match"].(bson.M)
+			}
+			if event.CommandName == "find" && event.Command.Lookup("find").StringValue() == "documents" {
+				var cmd struct {
+					Filter bson.M `bson:"filter"`
+				}
+				if err := bson.Unmarshal(event.Command, &cmd); err != nil {
+					t.Fatal(err)
+				}
+				listFilter = cmd.Filter
+			}
+		}
+		if countsMatch == nil || listFilter == nil {
+			t.Fatalf("expected a status aggregation and a document find, got %d commands", len(mt.GetAllStartedEvents()))
+		}
+
+		for name, filter := range map[string]bson.M{"status counts": countsMatch, "document list": listFilter} {
+			if matchesFilter(filter, otherDraft) {
+				t.Errorf("%s: the draft of another department is visible to the display", name)
+			}
+			if !matchesFilter(filter, ownDraft) {
+				t.Errorf("%s: the draft of the approving user is hidden from the display", name)
+			}
+		}
+	})
+}
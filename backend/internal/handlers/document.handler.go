@@ -1,8 +1,17 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,6 +21,7 @@ import (
 	"github.com/kodesonik/process-manager/internal/models"
 	"github.com/kodesonik/process-manager/internal/services"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gopkg.in/yaml.v3"
 )
 
 type DocumentHandler struct {
@@ -19,14 +29,24 @@ type DocumentHandler struct {
 	activityLogService   *services.ActivityLogService
 	minioService         *services.MinIOService
 	notificationService  *services.NotificationService
+	analyticsService     *services.AnalyticsService
+	publicationService   *services.PublicationService
+	favoriteService      *services.FavoriteService
+	watchService         *services.WatchService
+	asyncRunner          *services.AsyncRunner
 }
 
-func NewDocumentHandler(documentService *services.DocumentService, activityLogService *services.ActivityLogService, minioService *services.MinIOService, notificationService *services.NotificationService) *DocumentHandler {
+func NewDocumentHandler(documentService *services.DocumentService, activityLogService *services.ActivityLogService, minioService *services.MinIOService, notificationService *services.NotificationService, analyticsService *services.AnalyticsService, publicationService *services.PublicationService, favoriteService *services.FavoriteService, watchService *services.WatchService, asyncRunner *services.AsyncRunner) *DocumentHandler {
 	return &DocumentHandler{
 		documentService:     documentService,
 		activityLogService:  activityLogService,
 		minioService:        minioService,
 		notificationService: notificationService,
+		analyticsService:    analyticsService,
+		publicationService:  publicationService,
+		favoriteService:     favoriteService,
+		watchService:        watchService,
+		asyncRunner:         asyncRunner,
 	}
 }
 
@@ -57,7 +77,10 @@ func (h *DocumentHandler) CreateDocument(c *gin.Context) {
 	document, err := h.documentService.Create(ctx, &req, user.ID)
 	if err != nil {
 		fmt.Printf("❌ [DOCUMENT] Failed to create document: %v\n", err)
-		if err.Error() == "document reference already exists" {
+		if err.Error() == "document reference already exists" || err.Error() == "department not found" ||
+			strings.HasPrefix(err.Error(), "invalid department ID") || err == services.ErrInvalidEffectiveDates ||
+			err == services.ErrInvalidClassification ||
+			strings.HasPrefix(err.Error(), "invalid process code") || err.Error() == "process code already exists in this macro" {
 			helpers.SendBadRequest(c, err.Error())
 			return
 		}
@@ -114,12 +137,43 @@ func (h *DocumentHandler) GetDocument(c *gin.Context) {
 		return
 	}
 
+	// Track the document in the user's recently viewed list
+	if userID, exists := middleware.GetCurrentUserID(c); exists {
+		h.asyncRunner.Go("document_view", func(ctx context.Context) error {
+			if err := h.favoriteService.RecordView(ctx, userID, id); err != nil {
+				return fmt.Errorf("failed to record document view: %w", err)
+			}
+			return nil
+		})
+	}
+
+	setDocumentETag(c, document)
 	helpers.SendSuccess(c, "Document retrieved successfully", document.ToResponse())
 }
 
+// setDocumentETag exposes the document revision so clients can send it back in If-Match
+func setDocumentETag(c *gin.Context, document *models.Document) {
+	c.Header("ETag", fmt.Sprintf("\"%d\"", document.Revision))
+}
+
+// parseIfMatchRevision reads the document revision from the If-Match header.
+// It returns nil when the header is absent.
+func parseIfMatchRevision(c *gin.Context) (*int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return nil, nil
+	}
+	header = strings.Trim(strings.TrimPrefix(header, "W/"), "\"")
+	revision, err := strconv.ParseInt(header, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
 // ListDocuments retrieves documents with filtering and pagination
 // Only returns documents that the user has access to
-// GET /api/documents
+// GET /api/documents?sortBy=updatedAt|createdAt|title|reference|status|signatureProgress&sortDir=asc|desc
 func (h *DocumentHandler) ListDocuments(c *gin.Context) {
 	// Get current user
 	user, exists := middleware.GetCurrentUser(c)
@@ -141,6 +195,12 @@ func (h *DocumentHandler) ListDocuments(c *gin.Context) {
 	if search := c.Query("search"); search != "" {
 		filter.Search = &search
 	}
+	sort, err := models.ParseDocumentSort(c.Query("sortBy"), c.Query("sortDir"))
+	if err != nil {
+		helpers.SendBadRequest(c, err.Error())
+		return
+	}
+	filter.Sort = sort
 
 	// Parse pagination
 	page := 1
@@ -169,6 +229,13 @@ func (h *DocumentHandler) ListDocuments(c *gin.Context) {
 		return
 	}
 
+	// Record the search once per query, not for every page
+	if filter.Search != nil && page == 1 {
+		if searchID, err := h.analyticsService.RecordSearch(ctx, models.SearchSourceDocuments, *filter.Search, total); err == nil {
+			c.Header("X-Search-ID", searchID.Hex())
+		}
+	}
+
 	// Convert to response
 	responses := make([]models.DocumentResponse, 0, len(documents))
 	for _, doc := range documents {
@@ -186,7 +253,41 @@ func (h *DocumentHandler) ListDocuments(c *gin.Context) {
 	})
 }
 
-// UpdateDocument updates a document
+// sendUpdateError sends the response of a failed document update
+func (h *DocumentHandler) sendUpdateError(c *gin.Context, id primitive.ObjectID, err error) {
+	if err.Error() == "document not found" {
+		helpers.SendNotFound(c, "Document not found")
+		return
+	}
+	var accessErr *models.SectionAccessError
+	if errors.As(err, &accessErr) {
+		helpers.SendFieldAuthorizationErrors(c, "You are not allowed to modify some sections of this document", accessErr.Fields)
+		return
+	}
+	if err == services.ErrDocumentRevisionConflict {
+		// Return the server state so the client can merge its changes
+		current, getErr := h.documentService.GetByID(c.Request.Context(), id)
+		if getErr != nil {
+			helpers.SendInternalError(c, getErr)
+			return
+		}
+		setDocumentETag(c, current)
+		helpers.SendRevisionConflict(c, "Document was modified by another user, merge your changes with the current version", current.ToResponse())
+		return
+	}
+	if strings.HasPrefix(err.Error(), "unknown metadata section") || strings.HasPrefix(err.Error(), "duplicate metadata section") ||
+		strings.HasPrefix(err.Error(), "invalid reference") || err == services.ErrInvalidEffectiveDates ||
+		err == services.ErrInvalidClassification || strings.HasPrefix(err.Error(), "page ") ||
+		strings.HasPrefix(err.Error(), "task validation failed") || strings.HasPrefix(err.Error(), "cannot modify document") {
+		helpers.SendBadRequest(c, err.Error())
+		return
+	}
+	helpers.SendInternalError(c, err)
+}
+
+// UpdateDocument updates a document. Autosaves are written to the draft of
+// the user instead, see SaveDraft. The other writes must send the revision
+// they are based on, in the body or the If-Match header.
 // PUT /api/documents/:id
 func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 	idParam := c.Param("id")
@@ -202,6 +303,23 @@ func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 		return
 	}
 
+	// The revision in the body takes precedence over the If-Match header
+	if req.Revision == nil {
+		revision, err := parseIfMatchRevision(c)
+		if err != nil {
+			helpers.SendBadRequest(c, "Invalid If-Match header, expected the document revision")
+			return
+		}
+		req.Revision = revision
+	}
+	// Writes that do not tell which revision they are based on could
+	// silently overwrite the changes of another user, autosaves only touch
+	// the draft of the user
+	if req.Revision == nil && (req.IsAutosave == nil || !*req.IsAutosave) {
+		helpers.SendRevisionRequired(c, "The document revision is required, send it in the revision field or the If-Match header")
+		return
+	}
+
 	// Get current user
 	user, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -210,347 +328,293 @@ func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	document, err := h.documentService.Update(ctx, id, &req, user.ID)
-	if err != nil {
-		if err.Error() == "document not found" {
-			helpers.SendNotFound(c, "Document not found")
+
+	// Autosaves go to the draft of the user, the document is only modified
+	// when the draft is committed
+	if req.IsAutosave != nil && *req.IsAutosave {
+		draft, err := h.documentService.SaveDraft(ctx, id, &req, user.ID)
+		if err != nil {
+			h.sendDraftError(c, err)
 			return
 		}
-		helpers.SendInternalError(c, err)
+		helpers.SendSuccess(c, "Draft saved successfully", draft)
 		return
 	}
 
-	// Log activity (skip for autosave operations)
-	if req.IsAutosave == nil || !*req.IsAutosave {
-		activityReq := models.ActivityLogRequest{
-			Action:       "document_updated",
-			Description:  fmt.Sprintf("Updated document '%s' (%s)", document.Title, document.Reference),
-			ResourceType: "document",
-			ResourceID:   &document.ID,
-			Success:      true,
-			Details: map[string]interface{}{
-				"documentId": document.ID.Hex(),
-				"reference":  document.Reference,
-				"title":      document.Title,
-				"version":    document.Version,
-				"status":     string(document.Status),
-			},
-		}
-		if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
-			fmt.Printf("Failed to log activity: %v\n", logErr)
+	// The previous version tells whether the watchers get a new version
+	var previousVersion string
+	if req.Version != nil {
+		if current, err := h.documentService.GetByID(ctx, id); err == nil {
+			previousVersion = current.Version
 		}
 	}
 
-	helpers.SendSuccess(c, "Document updated successfully", document.ToResponse())
-}
-
-// DeleteDocument deletes a document
-// DELETE /api/documents/:id
-func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
-	if err != nil {
-		helpers.SendBadRequest(c, "Invalid document ID format")
-		return
-	}
-
-	ctx := c.Request.Context()
-
-	// Get document details before deleting for activity log
-	document, err := h.documentService.GetByID(ctx, id)
+	document, err := h.documentService.Update(ctx, id, &req, user.ID, user.Role)
 	if err != nil {
-		if err.Error() == "document not found" {
-			helpers.SendNotFound(c, "Document not found")
-			return
-		}
-		helpers.SendInternalError(c, err)
+		h.sendUpdateError(c, id, err)
 		return
 	}
 
-	err = h.documentService.Delete(ctx, id)
-	if err != nil {
-		if err.Error() == "document not found" {
-			helpers.SendNotFound(c, "Document not found")
-			return
-		}
-		helpers.SendInternalError(c, err)
-		return
+	if req.Version != nil && previousVersion != "" && document.Version != previousVersion {
+		h.asyncRunner.Go("watch_notifications", func(ctx context.Context) error {
+			return h.watchService.Notify(ctx, document.ID, document, models.WatchEventNewVersion,
+				"New Version of a Watched Document",
+				fmt.Sprintf("Document '%s' (%s) was updated from version %s to %s.", document.Title, document.Reference, previousVersion, document.Version),
+				user.ID)
+		})
 	}
 
 	// Log activity
 	activityReq := models.ActivityLogRequest{
-		Action:       "document_deleted",
-		Description:  fmt.Sprintf("Deleted document '%s' (%s)", document.Title, document.Reference),
+		Action:       "document_updated",
+		Description:  fmt.Sprintf("Updated document '%s' (%s)", document.Title, document.Reference),
 		ResourceType: "document",
 		ResourceID:   &document.ID,
 		Success:      true,
 		Details: map[string]interface{}{
-			"documentId":   document.ID.Hex(),
-			"reference":    document.Reference,
-			"title":        document.Title,
-			"version":      document.Version,
+			"documentId": document.ID.Hex(),
+			"reference":  document.Reference,
+			"title":      document.Title,
+			"version":    document.Version,
+			"status":     string(document.Status),
 		},
 	}
 	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
 		fmt.Printf("Failed to log activity: %v\n", logErr)
 	}
 
-	helpers.SendSuccess(c, "Document deleted successfully", nil)
+	setDocumentETag(c, document)
+	helpers.SendSuccess(c, "Document updated successfully", document.ToResponse())
 }
 
-// DuplicateDocument duplicates a document
-// POST /api/documents/:id/duplicate
-func (h *DocumentHandler) DuplicateDocument(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
+// GetDraft returns the autosaved draft of the current user on a document
+// GET /api/documents/:id/draft
+func (h *DocumentHandler) GetDraft(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		helpers.SendBadRequest(c, "Invalid document ID format")
 		return
 	}
 
-	// Get current user
-	user, exists := middleware.GetCurrentUser(c)
+	userID, exists := middleware.GetCurrentUserID(c)
 	if !exists {
 		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
 		return
 	}
 
-	ctx := c.Request.Context()
-	document, err := h.documentService.Duplicate(ctx, id, user.ID)
+	draft, err := h.documentService.GetDraft(c.Request.Context(), id, userID)
 	if err != nil {
-		if err.Error() == "document not found" {
-			helpers.SendNotFound(c, "Document not found")
-			return
-		}
-		helpers.SendInternalError(c, err)
+		h.sendDraftError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"success": true,
-		"message": "Document duplicated successfully",
-		"data":    document.ToResponse(),
-	})
+	helpers.SendSuccess(c, "Draft retrieved successfully", draft)
 }
 
-// PublishDocument publishes a document for signature
-// POST /api/documents/:id/publish
-func (h *DocumentHandler) PublishDocument(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
+// SaveDraft autosaves changes to the draft of the current user on a document,
+// without modifying the document other contributors see
+// PUT /api/documents/:id/draft
+func (h *DocumentHandler) SaveDraft(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		helpers.SendBadRequest(c, "Invalid document ID format")
 		return
 	}
 
-	// Get current user
-	user, exists := middleware.GetCurrentUser(c)
+	var req models.UpdateDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.SendBadRequest(c, "Invalid request body")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
 	if !exists {
 		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
 		return
 	}
 
-	ctx := c.Request.Context()
+	draft, err := h.documentService.SaveDraft(c.Request.Context(), id, &req, userID)
+	if err != nil {
+		h.sendDraftError(c, err)
+		return
+	}
 
-	fmt.Printf("📤 [PUBLISH] Publishing document ID: %s\n", id.Hex())
+	helpers.SendSuccess(c, "Draft saved successfully", draft)
+}
 
-	document, err := h.documentService.Publish(ctx, id)
+// CommitDraft applies the draft of the current user to the document. A draft
+// started before the last modification of the document is rejected with a
+// revision conflict unless force is set.
+// POST /api/documents/:id/draft/commit
+func (h *DocumentHandler) CommitDraft(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
-		fmt.Printf("❌ [PUBLISH] Error: %v\n", err)
-		if err.Error() == "document not found" {
-			helpers.SendNotFound(c, "Document not found")
-			return
-		}
-		// Handle status validation errors
-		if strings.Contains(err.Error(), "document cannot be published from status") {
-			helpers.SendBadRequest(c, err.Error())
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	var req models.CommitDocumentDraftRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.SendBadRequest(c, "Invalid request body")
 			return
 		}
-		helpers.SendInternalError(c, err)
-		return
 	}
 
-	fmt.Printf("✅ [PUBLISH] Document published successfully, status: %s\n", document.Status)
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
 
-	// Log activity with appropriate description
-	var activityDescription string
-	if document.Status == models.DocumentStatusArchived {
-		activityDescription = fmt.Sprintf("Published approved document '%s' (%s) to the organization", document.Title, document.Reference)
-	} else {
-		activityDescription = fmt.Sprintf("Published document '%s' (%s) for signature", document.Title, document.Reference)
+	ctx := c.Request.Context()
+	document, err := h.documentService.CommitDraft(ctx, id, req.Force, user.ID, user.Role)
+	if err != nil {
+		if err.Error() == "draft not found" {
+			helpers.SendNotFound(c, "Draft not found")
+			return
+		}
+		h.sendUpdateError(c, id, err)
+		return
 	}
 
 	activityReq := models.ActivityLogRequest{
-		Action:       "document_published",
-		Description:  activityDescription,
+		Action:       "document_updated",
+		Description:  fmt.Sprintf("Committed draft of document '%s' (%s)", document.Title, document.Reference),
 		ResourceType: "document",
 		ResourceID:   &document.ID,
 		Success:      true,
 		Details: map[string]interface{}{
 			"documentId": document.ID.Hex(),
 			"reference":  document.Reference,
-			"title":      document.Title,
-			"status":     string(document.Status),
+			"version":    document.Version,
+			"forced":     req.Force,
 		},
 	}
 	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
 		fmt.Printf("Failed to log activity: %v\n", logErr)
 	}
 
-	// Send notifications to all contributors who need to sign
-	go func() {
-		// Collect all contributor user IDs as strings
-		var userIDStrings []string
-		var roleTitle string
+	setDocumentETag(c, document)
+	helpers.SendSuccess(c, "Draft committed successfully", document.ToResponse())
+}
 
-		// Determine which role needs to sign based on document status
-		switch document.Status {
-		case models.DocumentStatusAuthorReview:
-			roleTitle = "Authors"
-			for _, author := range document.Contributors.Authors {
-				if author.Status == models.SignatureStatusPending {
-					userIDStrings = append(userIDStrings, author.UserID.Hex())
-				}
-			}
-		case models.DocumentStatusVerifierReview:
-			roleTitle = "Verifiers"
-			for _, verifier := range document.Contributors.Verifiers {
-				if verifier.Status == models.SignatureStatusPending {
-					userIDStrings = append(userIDStrings, verifier.UserID.Hex())
-				}
-			}
-		case models.DocumentStatusValidatorReview:
-			roleTitle = "Validators"
-			for _, validator := range document.Contributors.Validators {
-				if validator.Status == models.SignatureStatusPending {
-					userIDStrings = append(userIDStrings, validator.UserID.Hex())
-				}
-			}
-		}
+// DiscardDraft deletes the draft of the current user on a document
+// DELETE /api/documents/:id/draft
+func (h *DocumentHandler) DiscardDraft(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
 
-		if len(userIDStrings) == 0 {
-			return
-		}
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
 
-		// Send notification
-		notificationReq := &models.SendNotificationRequest{
-			UserIDs:  userIDStrings,
-			Title:    fmt.Sprintf("Document Ready for %s Signature", roleTitle),
-			Body:     fmt.Sprintf("Document '%s' (%s) has been published and is ready for your signature.", document.Title, document.Reference),
-			Category: "document",
-			Data: map[string]interface{}{
-				"documentId": document.ID.Hex(),
-				"reference":  document.Reference,
-				"title":      document.Title,
-				"action":     "signature_required",
-				"role":       roleTitle,
-			},
-		}
+	if err := h.documentService.DiscardDraft(c.Request.Context(), id, userID); err != nil {
+		h.sendDraftError(c, err)
+		return
+	}
 
-		_, err := h.notificationService.SendNotification(ctx, notificationReq, user.ID)
-		if err != nil {
-			fmt.Printf("⚠️  Failed to send notifications for published document: %v\n", err)
-		} else {
-			fmt.Printf("✅ Sent signature notifications to %d %s\n", len(userIDStrings), roleTitle)
-		}
-	}()
+	helpers.SendSuccess(c, "Draft discarded successfully", nil)
+}
 
-	helpers.SendSuccess(c, "Document published successfully", document.ToResponse())
+// sendDraftError sends the response of a failed draft operation
+func (h *DocumentHandler) sendDraftError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "document not found":
+		helpers.SendNotFound(c, "Document not found")
+	case err.Error() == "draft not found":
+		helpers.SendNotFound(c, "Draft not found")
+	case strings.HasPrefix(err.Error(), "cannot modify document"):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
 }
 
-// ExportPDF exports document as PDF
-// GET /api/documents/:id/export-pdf
-func (h *DocumentHandler) ExportPDF(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
+// GetWorkflow returns the signature workflow driving a document and where it is defined
+// GET /api/documents/:id/workflow
+func (h *DocumentHandler) GetWorkflow(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		helpers.SendBadRequest(c, "Invalid document ID format")
 		return
 	}
 
-	ctx := c.Request.Context()
-
-	fmt.Printf("📥 [EXPORT] Exporting PDF for document ID: %s\n", id.Hex())
-
-	pdfURL, err := h.documentService.ExportPDF(ctx, id)
+	workflow, err := h.documentService.GetWorkflow(c.Request.Context(), id)
 	if err != nil {
-		fmt.Printf("❌ [EXPORT] Error: %v\n", err)
-		if err.Error() == "document not found" {
-			helpers.SendNotFound(c, "Document not found")
-			return
-		}
-		if strings.Contains(err.Error(), "PDF service not available") {
-			helpers.SendInternalError(c, fmt.Errorf("PDF generation service is not available"))
-			return
-		}
-		helpers.SendInternalError(c, err)
+		sendWorkflowError(c, err)
 		return
 	}
 
-	helpers.SendSuccess(c, "PDF exported successfully", gin.H{
-		"pdfUrl": pdfURL,
-	})
+	helpers.SendSuccess(c, "Workflow retrieved successfully", workflow)
 }
 
-// ViewDocument returns the document as HTML view (same design as PDF)
-// GET /api/documents/:id/view
-func (h *DocumentHandler) ViewDocument(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
+// SetWorkflow sets the signature workflow of a draft document, overriding the one of its macro
+// PUT /api/documents/:id/workflow
+func (h *DocumentHandler) SetWorkflow(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		helpers.SendBadRequest(c, "Invalid document ID format")
 		return
 	}
 
-	ctx := c.Request.Context()
-
-	fmt.Printf("👁️  [VIEW] Rendering HTML view for document ID: %s\n", id.Hex())
+	var req models.WorkflowDefinition
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
 
-	html, err := h.documentService.RenderDocumentView(ctx, id)
+	ctx := c.Request.Context()
+	document, err := h.documentService.SetWorkflow(ctx, id, &req)
 	if err != nil {
-		fmt.Printf("❌ [VIEW] Error: %v\n", err)
-		if err.Error() == "document not found" {
-			helpers.SendNotFound(c, "Document not found")
-			return
-		}
-		helpers.SendInternalError(c, err)
+		sendWorkflowError(c, err)
 		return
 	}
 
-	// Return HTML with proper content type
-	c.Header("Content-Type", "text/html; charset=utf-8")
-	c.String(http.StatusOK, html)
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_workflow_updated",
+		Description:  fmt.Sprintf("Updated the workflow of document '%s' (%s)", document.Title, document.Reference),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"stages":     len(req.Stages),
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Workflow updated successfully", document.ToResponse())
 }
 
-// GetDocumentVersions retrieves all versions of a document
-// GET /api/documents/:id/versions
-func (h *DocumentHandler) GetDocumentVersions(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
+// ClearWorkflow removes the workflow of a draft document, which follows the one of its macro again
+// DELETE /api/documents/:id/workflow
+func (h *DocumentHandler) ClearWorkflow(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		helpers.SendBadRequest(c, "Invalid document ID format")
 		return
 	}
 
-	ctx := c.Request.Context()
-	versions, err := h.documentService.GetVersions(ctx, id)
+	document, err := h.documentService.ClearWorkflow(c.Request.Context(), id)
 	if err != nil {
-		helpers.SendInternalError(c, err)
+		sendWorkflowError(c, err)
 		return
 	}
 
-	// Convert to response
-	responses := make([]models.DocumentVersionResponse, 0, len(versions))
-	for _, version := range versions {
-		responses = append(responses, version.ToResponse())
-	}
-
-	helpers.SendSuccess(c, "Document versions retrieved successfully", responses)
+	helpers.SendSuccess(c, "Workflow removed successfully", document.ToResponse())
 }
 
-// UpdateMetadata updates document metadata
-// PATCH /api/documents/:id/metadata
-func (h *DocumentHandler) UpdateMetadata(c *gin.Context) {
+// DeleteDocument moves a document to the trash
+// DELETE /api/documents/:id
+func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
@@ -558,14 +622,8 @@ func (h *DocumentHandler) UpdateMetadata(c *gin.Context) {
 		return
 	}
 
-	var req models.UpdateMetadataRequest
-	if err := helpers.BindAndValidate(c, &req); err != nil {
-		helpers.SendValidationErrors(c, err)
-		return
-	}
-
 	// Get current user
-	_, exists := middleware.GetCurrentUser(c)
+	user, exists := middleware.GetCurrentUser(c)
 	if !exists {
 		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
 		return
@@ -573,11 +631,9 @@ func (h *DocumentHandler) UpdateMetadata(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
-	fmt.Printf("📝 [DOCUMENT] Updating metadata for document ID: %s\n", id.Hex())
-
-	document, err := h.documentService.UpdateMetadata(ctx, id, &req)
+	// Get document details before deleting for activity log
+	document, err := h.documentService.GetByID(ctx, id)
 	if err != nil {
-		fmt.Printf("❌ [DOCUMENT] Failed to update metadata: %v\n", err)
 		if err.Error() == "document not found" {
 			helpers.SendNotFound(c, "Document not found")
 			return
@@ -586,442 +642,2270 @@ func (h *DocumentHandler) UpdateMetadata(c *gin.Context) {
 		return
 	}
 
-	fmt.Printf("✅ [DOCUMENT] Metadata updated successfully\n")
+	err = h.documentService.Delete(ctx, id, user.ID)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
 
 	// Log activity
 	activityReq := models.ActivityLogRequest{
-		Action:       "metadata_updated",
-		Description:  fmt.Sprintf("Updated metadata for document '%s'", document.Title),
+		Action:       "document_deleted",
+		Description:  fmt.Sprintf("Deleted document '%s' (%s)", document.Title, document.Reference),
 		ResourceType: "document",
 		ResourceID:   &document.ID,
 		Success:      true,
 		Details: map[string]interface{}{
-			"documentId": document.ID.Hex(),
-			"reference":  document.Reference,
-			"title":      document.Title,
+			"documentId":   document.ID.Hex(),
+			"reference":    document.Reference,
+			"title":        document.Title,
+			"version":      document.Version,
 		},
 	}
 	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
 		fmt.Printf("Failed to log activity: %v\n", logErr)
 	}
 
-	helpers.SendSuccess(c, "Metadata updated successfully", document.ToResponse())
+	helpers.SendSuccess(c, "Document deleted successfully", nil)
 }
 
-// CreateAnnex creates a new annex for a document
-// POST /api/documents/:id/annexes
-func (h *DocumentHandler) CreateAnnex(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
+// GetDocumentStats returns the document statistics of the organization
+// GET /api/documents/stats
+func (h *DocumentHandler) GetDocumentStats(c *gin.Context) {
+	stats, err := h.documentService.GetStatistics(c.Request.Context())
 	if err != nil {
-		helpers.SendBadRequest(c, "Invalid document ID format")
+		helpers.SendInternalError(c, err)
 		return
 	}
 
-	var req models.CreateAnnexRequest
-	if err := helpers.BindAndValidate(c, &req); err != nil {
-		helpers.SendValidationErrors(c, err)
-		return
-	}
+	helpers.SendSuccess(c, "Document statistics retrieved successfully", stats)
+}
 
-	// Get current user
-	_, exists := middleware.GetCurrentUser(c)
+// maxDocumentImportSizeMB bounds the size of an import spreadsheet
+const maxDocumentImportSizeMB = 10
+
+// ImportDocuments creates draft documents from an uploaded XLSX or CSV
+// spreadsheet. With dryRun=true the file is only validated; otherwise the
+// documents are created when no row has an error.
+// POST /api/documents/import
+func (h *DocumentHandler) ImportDocuments(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
 	if !exists {
 		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
 		return
 	}
 
-	ctx := c.Request.Context()
-
-	fmt.Printf("📎 [DOCUMENT] Creating annex for document ID: %s\n", id.Hex())
-	fmt.Printf("   - Title: %s\n", req.Title)
-	fmt.Printf("   - Type: %s\n", req.Type)
-
-	annex, err := h.documentService.CreateAnnex(ctx, id, &req)
+	fileHeader, err := c.FormFile("file")
 	if err != nil {
-		fmt.Printf("❌ [DOCUMENT] Failed to create annex: %v\n", err)
-		if err.Error() == "document not found" {
-			helpers.SendNotFound(c, "Document not found")
-			return
-		}
-		helpers.SendInternalError(c, err)
+		helpers.SendBadRequest(c, "No file provided. Please include 'file' field in form")
 		return
 	}
-
-	fmt.Printf("✅ [DOCUMENT] Annex created successfully - ID: %s\n", annex.ID)
-
-	// Log activity
-	document, _ := h.documentService.GetByID(ctx, id)
-	activityReq := models.ActivityLogRequest{
-		Action:       "annex_created",
-		Description:  fmt.Sprintf("Created annex '%s' for document '%s'", annex.Title, document.Title),
-		ResourceType: "document",
-		ResourceID:   &id,
-		Success:      true,
-		Details: map[string]interface{}{
-			"documentId": id.Hex(),
-			"annexId":    annex.ID,
-			"annexTitle": annex.Title,
-			"annexType":  string(annex.Type),
-		},
-	}
-	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
-		fmt.Printf("Failed to log activity: %v\n", logErr)
+	if fileHeader.Size > maxDocumentImportSizeMB*1024*1024 {
+		helpers.SendBadRequest(c, fmt.Sprintf("File size exceeds %dMB limit", maxDocumentImportSizeMB))
+		return
 	}
 
-	helpers.SendSuccess(c, "Annex created successfully", annex)
-}
+	dryRunValue := c.Query("dryRun")
+	if dryRunValue == "" {
+		dryRunValue = c.PostForm("dryRun")
+	}
+	dryRun, _ := strconv.ParseBool(dryRunValue)
 
-// UpdateAnnex updates an existing annex
-// PATCH /api/documents/:id/annexes/:annexId
-func (h *DocumentHandler) UpdateAnnex(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
+	file, err := fileHeader.Open()
 	if err != nil {
-		helpers.SendBadRequest(c, "Invalid document ID format")
+		helpers.SendInternalError(c, models.ErrServiceUnavailable)
 		return
 	}
+	defer file.Close()
 
-	annexID := c.Param("annexId")
-
-	var req models.UpdateAnnexRequest
-	if err := helpers.BindAndValidate(c, &req); err != nil {
-		helpers.SendValidationErrors(c, err)
+	data, err := io.ReadAll(file)
+	if err != nil {
+		helpers.SendInternalError(c, err)
 		return
 	}
 
-	// Get current user
-	_, exists := middleware.GetCurrentUser(c)
-	if !exists {
-		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".xlsx":
+		rows, err = helpers.ReadXLSX(data)
+	case ".csv":
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		rows, err = reader.ReadAll()
+	default:
+		helpers.SendBadRequest(c, "Unsupported file type: must be .xlsx or .csv")
+		return
+	}
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid spreadsheet", err.Error())
 		return
 	}
 
-	ctx := c.Request.Context()
-
-	fmt.Printf("📝 [DOCUMENT] Updating annex %s for document ID: %s\n", annexID, id.Hex())
+	defaultMacro := c.PostForm("macroId")
+	if defaultMacro == "" {
+		defaultMacro = c.PostForm("macro")
+	}
 
-	annex, err := h.documentService.UpdateAnnex(ctx, id, annexID, &req)
+	result, err := h.documentService.ImportDocuments(c.Request.Context(), rows, defaultMacro, dryRun, userID)
 	if err != nil {
-		fmt.Printf("❌ [DOCUMENT] Failed to update annex: %v\n", err)
-		if err.Error() == "document not found" || err.Error() == "annex not found" {
-			helpers.SendNotFound(c, err.Error())
-			return
-		}
 		helpers.SendInternalError(c, err)
 		return
 	}
 
-	fmt.Printf("✅ [DOCUMENT] Annex updated successfully\n")
+	if !result.Valid {
+		resp := models.NewErrorResponse("The import file has errors", models.CodeValidationFailed)
+		resp.Data = result
+		c.JSON(http.StatusUnprocessableEntity, resp)
+		return
+	}
+	if dryRun {
+		helpers.SendSuccess(c, "Import file is valid", result)
+		return
+	}
 
-	// Log activity
-	document, _ := h.documentService.GetByID(ctx, id)
 	activityReq := models.ActivityLogRequest{
-		Action:       "annex_updated",
-		Description:  fmt.Sprintf("Updated annex '%s' for document '%s'", annex.Title, document.Title),
+		Action:       "documents_imported",
+		Description:  fmt.Sprintf("Imported %d draft documents from %s", result.CreatedCount, fileHeader.Filename),
 		ResourceType: "document",
-		ResourceID:   &id,
-		Success:      true,
+		Success:      result.CreatedCount == len(result.Documents),
 		Details: map[string]interface{}{
-			"documentId": id.Hex(),
-			"annexId":    annex.ID,
-			"annexTitle": annex.Title,
+			"fileName":     fileHeader.Filename,
+			"totalRows":    result.TotalRows,
+			"createdCount": result.CreatedCount,
 		},
 	}
-	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+	if logErr := h.activityLogService.LogActivity(c.Request.Context(), activityReq, c); logErr != nil {
 		fmt.Printf("Failed to log activity: %v\n", logErr)
 	}
 
-	helpers.SendSuccess(c, "Annex updated successfully", annex)
+	helpers.SendCreated(c, fmt.Sprintf("%d documents imported", result.CreatedCount), result)
 }
 
-// DeleteAnnex deletes an annex from a document
-// DELETE /api/documents/:id/annexes/:annexId
-func (h *DocumentHandler) DeleteAnnex(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
-	if err != nil {
-		helpers.SendBadRequest(c, "Invalid document ID format")
-		return
-	}
-
-	annexID := c.Param("annexId")
-
+// ListTrash lists the documents in the trash
+// GET /api/documents/trash
+func (h *DocumentHandler) ListTrash(c *gin.Context) {
 	// Get current user
-	_, exists := middleware.GetCurrentUser(c)
+	user, exists := middleware.GetCurrentUser(c)
 	if !exists {
 		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
 		return
 	}
 
+	page, limit := helpers.GetPaginationParams(c)
+
 	ctx := c.Request.Context()
 
-	// Get annex title before deleting
-	document, err := h.documentService.GetByID(ctx, id)
+	documents, total, err := h.documentService.ListTrash(ctx, user.ID, user.Role, page, limit)
 	if err != nil {
-		helpers.SendNotFound(c, "Document not found")
+		helpers.SendInternalError(c, err)
 		return
 	}
 
-	var annexTitle string
-	for _, annex := range document.Annexes {
-		if annex.ID == annexID {
-			annexTitle = annex.Title
-			break
-		}
+	responses := make([]models.DocumentResponse, 0, len(documents))
+	for _, doc := range documents {
+		responses = append(responses, doc.ToResponse())
 	}
 
-	fmt.Printf("🗑️ [DOCUMENT] Deleting annex %s from document ID: %s\n", annexID, id.Hex())
+	helpers.SendSuccessWithPagination(c, "Trashed documents retrieved successfully", responses, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
 
-	err = h.documentService.DeleteAnnex(ctx, id, annexID)
-	if err != nil {
-		fmt.Printf("❌ [DOCUMENT] Failed to delete annex: %v\n", err)
-		if err.Error() == "document not found" || err.Error() == "annex not found" {
-			helpers.SendNotFound(c, err.Error())
+// ListLibrary lists the documents published to the organization with their
+// current, upcoming or expired badge, sorted like the document list
+// GET /api/documents/library
+func (h *DocumentHandler) ListLibrary(c *gin.Context) {
+	var availability *models.DocumentAvailability
+	if availabilityStr := c.Query("availability"); availabilityStr != "" {
+		if !models.IsValidDocumentAvailability(availabilityStr) {
+			helpers.SendBadRequest(c, "Invalid availability, expected current, upcoming or expired")
 			return
 		}
-		helpers.SendInternalError(c, err)
+		value := models.DocumentAvailability(availabilityStr)
+		availability = &value
+	}
+
+	sort, err := models.ParseDocumentSort(c.Query("sortBy"), c.Query("sortDir"))
+	if err != nil {
+		helpers.SendBadRequest(c, err.Error())
 		return
 	}
 
-	fmt.Printf("✅ [DOCUMENT] Annex deleted successfully\n")
+	page, limit := helpers.GetPaginationParams(c)
 
-	// Log activity
-	activityReq := models.ActivityLogRequest{
-		Action:       "annex_deleted",
-		Description:  fmt.Sprintf("Deleted annex '%s' from document '%s'", annexTitle, document.Title),
-		ResourceType: "document",
-		ResourceID:   &id,
-		Success:      true,
-		Details: map[string]interface{}{
-			"documentId": id.Hex(),
-			"annexId":    annexID,
-			"annexTitle": annexTitle,
-		},
+	documents, total, err := h.documentService.ListLibrary(c.Request.Context(), availability, c.Query("search"), sort, page, limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
 	}
-	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
-		fmt.Printf("Failed to log activity: %v\n", logErr)
+
+	responses := make([]models.DocumentResponse, 0, len(documents))
+	for _, doc := range documents {
+		responses = append(responses, doc.ToResponse())
 	}
 
-	helpers.SendSuccess(c, "Annex deleted successfully", nil)
+	helpers.SendSuccessWithPagination(c, "Library documents retrieved successfully", responses, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
 }
 
-// UploadAnnexFiles handles file uploads for an annex
-// POST /api/documents/:id/annexes/:annexId/files
-func (h *DocumentHandler) UploadAnnexFiles(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
+// UpdateEffectiveDates replaces the transition period of a document
+// PUT /api/documents/:id/effective-dates
+func (h *DocumentHandler) UpdateEffectiveDates(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		helpers.SendBadRequest(c, "Invalid document ID format")
 		return
 	}
 
-	annexID := c.Param("annexId")
-
-	// Get current user
+	var req models.UpdateEffectiveDatesRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.documentService.UpdateEffectiveDates(ctx, id, &req)
+	if err != nil {
+		switch {
+		case err.Error() == "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case err == services.ErrInvalidEffectiveDates:
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_effective_dates_updated",
+		Description:  fmt.Sprintf("Updated the effective dates of document '%s' (%s)", document.Title, document.Reference),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId":       document.ID.Hex(),
+			"reference":        document.Reference,
+			"effectiveDate":    document.EffectiveDate,
+			"supersessionDate": document.SupersessionDate,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Effective dates updated successfully", document.ToResponse())
+}
+
+// RestoreDocument moves a document out of the trash
+// POST /api/documents/:id/restore
+func (h *DocumentHandler) RestoreDocument(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.documentService.Restore(ctx, id)
+	if err != nil {
+		if err.Error() == "document not found in trash" {
+			helpers.SendNotFound(c, "Document not found in trash")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_restored",
+		Description:  fmt.Sprintf("Restored document '%s' (%s) from the trash", document.Title, document.Reference),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"reference":  document.Reference,
+			"title":      document.Title,
+			"version":    document.Version,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Document restored successfully", document.ToResponse())
+}
+
+// DuplicateDocument duplicates a document
+// POST /api/documents/:id/duplicate
+func (h *DocumentHandler) DuplicateDocument(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	// Get current user
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	// Options are optional, everything is copied by default
+	var req models.DuplicateDocumentRequest
+	if c.Request.ContentLength > 0 {
+		if err := helpers.BindAndValidate(c, &req); err != nil {
+			helpers.SendValidationErrors(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	document, err := h.documentService.Duplicate(ctx, id, &req, user.ID)
+	if err != nil {
+		switch err.Error() {
+		case "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case "document reference already exists":
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_duplicated",
+		Description:  fmt.Sprintf("Duplicated document '%s' as %s", document.Title, document.Reference),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId":   document.ID.Hex(),
+			"duplicatedId": id.Hex(),
+			"reference":    document.Reference,
+			"version":      document.Version,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Document duplicated successfully",
+		"data":    document.ToResponse(),
+	})
+}
+
+// ReviseDocument drafts a new version of an archived document
+// POST /api/documents/:id/revise
+func (h *DocumentHandler) ReviseDocument(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	// Get current user
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	// The body is optional, it only sets the version and change note
+	var req models.ReviseDocumentRequest
+	if c.Request.ContentLength > 0 {
+		if err := helpers.BindAndValidate(c, &req); err != nil {
+			helpers.SendValidationErrors(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	document, err := h.documentService.Revise(ctx, id, &req, user.ID)
+	if err != nil {
+		switch {
+		case err.Error() == "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case err.Error() == "document already has a revision in progress":
+			helpers.SendConflict(c, err.Error())
+		case strings.HasPrefix(err.Error(), "only archived documents"),
+			strings.HasPrefix(err.Error(), "revision version"):
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_revised",
+		Description:  fmt.Sprintf("Drafted version %s of document '%s' (%s)", document.Version, document.Title, document.Reference),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId":   document.ID.Hex(),
+			"supersedesId": id.Hex(),
+			"reference":    document.Reference,
+			"version":      document.Version,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	// The watchers of the superseded version learn about the new one
+	h.asyncRunner.Go("watch_notifications", func(ctx context.Context) error {
+		return h.watchService.Notify(ctx, id, document, models.WatchEventNewVersion,
+			"New Version of a Watched Document",
+			fmt.Sprintf("Version %s of document '%s' (%s) is being drafted.", document.Version, document.Title, document.Reference),
+			user.ID)
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Document revision created successfully",
+		"data":    document.ToResponse(),
+	})
+}
+
+// PublishDocument publishes a document for signature
+// POST /api/documents/:id/publish
+func (h *DocumentHandler) PublishDocument(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	// Get current user
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	// The body is optional, it only overrides the signature deadlines
+	var req models.PublishDocumentRequest
+	if c.Request.ContentLength > 0 {
+		if err := helpers.BindAndValidate(c, &req); err != nil {
+			helpers.SendValidationErrors(c, err)
+			return
+		}
+	}
+
+	// An approved document can be published at a later effective date
+	if req.ScheduledPublishAt != nil {
+		h.schedulePublish(c, id, *req.ScheduledPublishAt, user.ID)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	fmt.Printf("📤 [PUBLISH] Publishing document ID: %s\n", id.Hex())
+
+	document, err := h.documentService.Publish(ctx, id, req.Deadlines)
+	if err != nil {
+		fmt.Printf("❌ [PUBLISH] Error: %v\n", err)
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		// Handle status validation errors
+		if strings.Contains(err.Error(), "document cannot be published") {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	fmt.Printf("✅ [PUBLISH] Document published successfully, status: %s\n", document.Status)
+
+	// Log activity with appropriate description
+	var activityDescription string
+	if document.Status == models.DocumentStatusArchived {
+		activityDescription = fmt.Sprintf("Published approved document '%s' (%s) to the organization", document.Title, document.Reference)
+	} else {
+		activityDescription = fmt.Sprintf("Published document '%s' (%s) for signature", document.Title, document.Reference)
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_published",
+		Description:  activityDescription,
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"reference":  document.Reference,
+			"title":      document.Title,
+			"status":     string(document.Status),
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	// Send notifications to all contributors who need to sign
+	h.asyncRunner.Go("publish_notifications", func(ctx context.Context) error {
+		// Collect all contributor user IDs as strings
+		var userIDStrings []string
+		var roleTitle string
+
+		// Determine which role needs to sign based on document status
+		switch document.Status {
+		case models.DocumentStatusAuthorReview:
+			roleTitle = "Authors"
+			for _, author := range document.Contributors.Authors {
+				if author.Status == models.SignatureStatusPending {
+					userIDStrings = append(userIDStrings, author.UserID.Hex())
+				}
+			}
+		case models.DocumentStatusVerifierReview:
+			roleTitle = "Verifiers"
+			for _, verifier := range document.Contributors.Verifiers {
+				if verifier.Status == models.SignatureStatusPending {
+					userIDStrings = append(userIDStrings, verifier.UserID.Hex())
+				}
+			}
+		case models.DocumentStatusValidatorReview:
+			roleTitle = "Validators"
+			for _, validator := range document.Contributors.Validators {
+				if validator.Status == models.SignatureStatusPending {
+					userIDStrings = append(userIDStrings, validator.UserID.Hex())
+				}
+			}
+		}
+
+		if len(userIDStrings) == 0 {
+			return nil
+		}
+
+		// Send notification
+		notificationReq := &models.SendNotificationRequest{
+			UserIDs:  userIDStrings,
+			Title:    fmt.Sprintf("Document Ready for %s Signature", roleTitle),
+			Body:     fmt.Sprintf("Document '%s' (%s) has been published and is ready for your signature.", document.Title, document.Reference),
+			Category: "document",
+			Data: map[string]interface{}{
+				"documentId": document.ID.Hex(),
+				"reference":  document.Reference,
+				"title":      document.Title,
+				"action":     "signature_required",
+				"role":       roleTitle,
+			},
+		}
+
+		if _, err := h.notificationService.SendNotification(ctx, notificationReq, user.ID); err != nil {
+			return fmt.Errorf("failed to send notifications for published document: %w", err)
+		}
+		fmt.Printf("✅ Sent signature notifications to %d %s\n", len(userIDStrings), roleTitle)
+		return nil
+	})
+
+	h.asyncRunner.Go("watch_notifications", func(ctx context.Context) error {
+		return h.watchService.Notify(ctx, document.ID, document, models.WatchEventStatusChanged,
+			"Watched Document Status Changed",
+			fmt.Sprintf("Document '%s' (%s) is now %s.", document.Title, document.Reference, document.Status),
+			user.ID)
+	})
+
+	// Notify owners of documents referencing a version this one supersedes
+	h.publicationService.Published(document, user.ID)
+
+	helpers.SendSuccess(c, "Document published successfully", document.ToResponse())
+}
+
+// RejectDocument returns a document under review to its authors
+// POST /api/documents/:id/reject
+func (h *DocumentHandler) RejectDocument(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	var req models.RejectDocumentRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.documentService.Reject(ctx, id, &req, user)
+	if err != nil {
+		switch {
+		case err.Error() == "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case err == services.ErrDocumentRevisionConflict:
+			helpers.SendConflict(c, "Document was modified by another user, retry")
+		case strings.HasPrefix(err.Error(), "only "):
+			helpers.SendForbidden(c, err.Error(), models.CodeForbidden)
+		case strings.HasPrefix(err.Error(), "document cannot be"):
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	rejection := document.LastRejection
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_rejected",
+		Description:  fmt.Sprintf("Rejected document '%s' (%s), returned to %s: %s", document.Title, document.Reference, rejection.ToStatus, rejection.Reason),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"reference":  document.Reference,
+			"reason":     rejection.Reason,
+			"team":       string(rejection.Team),
+			"fromStatus": string(rejection.FromStatus),
+			"toStatus":   string(rejection.ToStatus),
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	// Notify every other contributor of the document
+	h.asyncRunner.Go("rejection_notifications", func(ctx context.Context) error {
+		var userIDs []string
+		for _, team := range []models.ContributorTeam{models.ContributorTeamAuthors, models.ContributorTeamVerifiers, models.ContributorTeamValidators} {
+			for _, contributor := range document.Contributors.Team(team) {
+				if contributor.UserID != user.ID {
+					userIDs = append(userIDs, contributor.UserID.Hex())
+				}
+			}
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		notificationReq := &models.SendNotificationRequest{
+			UserIDs:  userIDs,
+			Title:    "Document Returned to Authors",
+			Body:     fmt.Sprintf("%s rejected '%s' (%s): %s", rejection.RejectedByName, document.Title, document.Reference, rejection.Reason),
+			Category: "document",
+			Priority: models.NotificationPriorityHigh,
+			Data: map[string]interface{}{
+				"documentId": document.ID.Hex(),
+				"reference":  document.Reference,
+				"title":      document.Title,
+				"action":     "document_rejected",
+				"status":     string(document.Status),
+			},
+		}
+		if _, err := h.notificationService.SendNotification(ctx, notificationReq, user.ID); err != nil {
+			return fmt.Errorf("failed to send rejection notifications: %w", err)
+		}
+		return nil
+	})
+
+	h.asyncRunner.Go("watch_notifications", func(ctx context.Context) error {
+		return h.watchService.Notify(ctx, document.ID, document, models.WatchEventStatusChanged,
+			"Watched Document Status Changed",
+			fmt.Sprintf("Document '%s' (%s) was returned to %s: %s", document.Title, document.Reference, document.Status, rejection.Reason),
+			user.ID)
+	})
+
+	helpers.SendSuccess(c, "Document rejected successfully", document.ToResponse())
+}
+
+// SchedulePublish sets or moves the date an approved document goes live
+// PUT /api/documents/:id/scheduled-publish
+func (h *DocumentHandler) SchedulePublish(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	var req models.SchedulePublishRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	h.schedulePublish(c, id, req.PublishAt, user.ID)
+}
+
+// schedulePublish defers the publication of an approved document to the given date
+func (h *DocumentHandler) schedulePublish(c *gin.Context, id primitive.ObjectID, publishAt time.Time, userID primitive.ObjectID) {
+	ctx := c.Request.Context()
+
+	document, err := h.documentService.SchedulePublish(ctx, id, publishAt, userID)
+	if err != nil {
+		switch {
+		case err.Error() == "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case strings.HasPrefix(err.Error(), "only approved documents"),
+			strings.HasPrefix(err.Error(), "scheduled publication date"):
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_publish_scheduled",
+		Description:  fmt.Sprintf("Scheduled the publication of document '%s' (%s) for %s", document.Title, document.Reference, publishAt.Format(time.RFC3339)),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId":         document.ID.Hex(),
+			"reference":          document.Reference,
+			"scheduledPublishAt": publishAt,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Document publication scheduled successfully", document.ToResponse())
+}
+
+// CancelScheduledPublish removes the scheduled publication of a document
+// DELETE /api/documents/:id/scheduled-publish
+func (h *DocumentHandler) CancelScheduledPublish(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.documentService.CancelScheduledPublish(ctx, id)
+	if err != nil {
+		switch err.Error() {
+		case "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case "document has no scheduled publication":
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_publish_unscheduled",
+		Description:  fmt.Sprintf("Cancelled the scheduled publication of document '%s' (%s)", document.Title, document.Reference),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"reference":  document.Reference,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Scheduled publication cancelled successfully", document.ToResponse())
+}
+
+// LintDocument runs the quality linter on a document
+// GET /api/documents/:id/lint
+func (h *DocumentHandler) LintDocument(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	result, err := h.documentService.Lint(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Document lint completed", result)
+}
+
+// ExportPDF exports document as PDF, with a draft watermark when requested
+// GET /api/documents/:id/export-pdf?watermark=true&force=true&pageSize=A3&orientation=landscape
+func (h *DocumentHandler) ExportPDF(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	fmt.Printf("📥 [EXPORT] Exporting PDF for document ID: %s\n", id.Hex())
+
+	pageLayout, err := models.ParsePDFPageLayout(c.Query("pageSize"), c.Query("orientation"))
+	if err != nil {
+		helpers.SendBadRequest(c, err.Error())
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	pdfURL, err := h.documentService.ExportPDF(ctx, id, models.PDFExportOptions{Watermark: c.Query("watermark") == "true", Archival: c.Query("archival") == "true", Force: c.Query("force") == "true", PageLayout: pageLayout, RequestedBy: userID})
+	if err != nil {
+		fmt.Printf("❌ [EXPORT] Error: %v\n", err)
+		var blocked *models.ExportBlockedError
+		if errors.As(err, &blocked) {
+			helpers.SendForbidden(c, blocked.Error(), models.CodeForbidden)
+			return
+		}
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		if errors.Is(err, services.ErrPDFArchivalUnsupported) {
+			helpers.SendErrorWithCode(c, http.StatusNotImplemented, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "PDF service not available") {
+			helpers.SendInternalError(c, fmt.Errorf("PDF generation service is not available"))
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "PDF exported successfully", gin.H{
+		"pdfUrl": pdfURL,
+	})
+}
+
+// pdfStreamKeepAlive is the interval of the comments keeping the PDF export
+// stream open through proxies while a stage runs
+const pdfStreamKeepAlive = 15 * time.Second
+
+// ExportPDFStream exports a document as PDF like ExportPDF, reporting the
+// stages of the generation as Server-Sent Events: "progress" events carry the
+// stage, the stream ends with a "done" event carrying the PDF URL or a
+// "failed" event carrying the error
+// GET /api/documents/:id/export-pdf/stream?watermark=true&force=true&pageSize=A3&orientation=landscape
+func (h *DocumentHandler) ExportPDFStream(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	pageLayout, err := models.ParsePDFPageLayout(c.Query("pageSize"), c.Query("orientation"))
+	if err != nil {
+		helpers.SendBadRequest(c, err.Error())
+		return
+	}
+	userID, _ := middleware.GetCurrentUserID(c)
+	opts := models.PDFExportOptions{
+		Watermark:   c.Query("watermark") == "true",
+		Archival:    c.Query("archival") == "true",
+		Force:       c.Query("force") == "true",
+		PageLayout:  pageLayout,
+		RequestedBy: userID,
+	}
+
+	// Stages are dropped rather than blocking the export when the client is slow
+	stages := make(chan models.PDFExportStage, 16)
+	ctx := services.WithPDFProgress(c.Request.Context(), func(stage models.PDFExportStage) {
+		select {
+		case stages <- stage:
+		default:
+		}
+	})
+
+	type exportResult struct {
+		pdfURL string
+		err    error
+	}
+	done := make(chan exportResult, 1)
+	go func() {
+		// A panic of the renderer fails the export instead of the process. The
+		// export is not run by the async runner as it must stop with the request.
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				fmt.Printf("❌ [EXPORT] PDF export of %s panicked: %v\n%s\n", id.Hex(), recovered, debug.Stack())
+				done <- exportResult{err: errors.New("PDF generation failed")}
+			}
+		}()
+		pdfURL, err := h.documentService.ExportPDF(ctx, id, opts)
+		done <- exportResult{pdfURL: pdfURL, err: err}
+	}()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable the buffering of nginx
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(pdfStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case stage := <-stages:
+			c.SSEvent("progress", models.PDFExportProgress{Stage: stage})
+			c.Writer.Flush()
+
+		case <-keepAlive.C:
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+			c.Writer.Flush()
+
+		case result := <-done:
+			// Stages reported right before the end come first
+			for len(stages) > 0 {
+				c.SSEvent("progress", models.PDFExportProgress{Stage: <-stages})
+			}
+			if result.err != nil {
+				fmt.Printf("❌ [EXPORT] Error: %v\n", result.err)
+				c.SSEvent(string(models.PDFExportStageFailed), models.PDFExportProgress{
+					Stage: models.PDFExportStageFailed,
+					Error: pdfStreamError(result.err),
+				})
+			} else {
+				c.SSEvent(string(models.PDFExportStageDone), models.PDFExportProgress{
+					Stage:  models.PDFExportStageDone,
+					PdfURL: result.pdfURL,
+				})
+			}
+			c.Writer.Flush()
+			return
+
+		case <-c.Request.Context().Done():
+			// The export stops with the request, the client is gone or the
+			// deadline passed
+			if c.Request.Context().Err() == context.DeadlineExceeded {
+				c.SSEvent(string(models.PDFExportStageFailed), models.PDFExportProgress{
+					Stage: models.PDFExportStageFailed,
+					Error: "PDF generation timed out",
+				})
+				c.Writer.Flush()
+			}
+			return
+		}
+	}
+}
+
+// pdfStreamError returns the message of a failed PDF export sent to the client
+func pdfStreamError(err error) string {
+	var blocked *models.ExportBlockedError
+	switch {
+	case errors.As(err, &blocked):
+		return blocked.Error()
+	case err.Error() == "document not found":
+		return "Document not found"
+	case strings.Contains(err.Error(), "PDF service not available"):
+		return "PDF generation service is not available"
+	}
+	return err.Error()
+}
+
+// maxDocumentStructureSize bounds the size of an imported document structure
+const maxDocumentStructureSize = 5 << 20
+
+// ExportStructure downloads the canonical structure of a document, without
+// database IDs, to move it to another environment or store it in Git
+// GET /api/documents/:id/export?format=json|yaml
+func (h *DocumentHandler) ExportStructure(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "yaml" {
+		helpers.SendBadRequest(c, "invalid format: must be json or yaml")
+		return
+	}
+
+	structure, err := h.documentService.ExportStructure(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	var data []byte
+	contentType := "application/json"
+	if format == "yaml" {
+		data, err = yaml.Marshal(structure)
+		contentType = "application/yaml"
+	} else {
+		data, err = json.MarshalIndent(structure, "", "  ")
+	}
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	name := structure.Reference
+	if name == "" {
+		name = id.Hex()
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+format))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ImportStructure creates a draft document from an exported structure. The
+// format is taken from the format parameter, or from the content type.
+// POST /api/documents/import-structure?format=json|yaml
+func (h *DocumentHandler) ImportStructure(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = "json"
+		if strings.Contains(c.ContentType(), "yaml") {
+			format = "yaml"
+		}
+	}
+	if format != "json" && format != "yaml" {
+		helpers.SendBadRequest(c, "invalid format: must be json or yaml")
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxDocumentStructureSize+1))
+	if err != nil {
+		helpers.SendBadRequest(c, "Failed to read request body")
+		return
+	}
+	if len(data) > maxDocumentStructureSize {
+		helpers.SendBadRequest(c, "Document structure is too large")
+		return
+	}
+
+	var structure models.DocumentStructure
+	if format == "yaml" {
+		err = yaml.Unmarshal(data, &structure)
+	} else {
+		err = json.Unmarshal(data, &structure)
+	}
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document structure", err.Error())
+		return
+	}
+
+	document, err := h.documentService.ImportStructure(c.Request.Context(), &structure, userID)
+	if err != nil {
+		if err.Error() == "document reference already exists" || err.Error() == "title is required" ||
+			err.Error() == "macro code is required" || strings.HasPrefix(err.Error(), "macro ") ||
+			strings.HasPrefix(err.Error(), "unsupported schema version") || strings.HasPrefix(err.Error(), "task validation failed") {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       models.ActionDocumentCreated,
+		Description:  fmt.Sprintf("Imported document structure: %s", document.Title),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"reference": document.Reference,
+			"format":    format,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(c.Request.Context(), activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendCreated(c, "Document imported successfully", document.ToResponse())
+}
+
+// ExportDocuments bundles the PDFs of several documents into a ZIP archive
+// POST /api/documents/export
+func (h *DocumentHandler) ExportDocuments(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	var req models.BulkExportRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	export, err := h.documentService.ExportBundle(ctx, &req, user.ID, user.Role)
+	if err != nil {
+		fmt.Printf("❌ [EXPORT] Bulk export error: %v\n", err)
+		var blocked *models.ExportBlockedError
+		switch {
+		case errors.As(err, &blocked):
+			helpers.SendForbidden(c, blocked.Error(), models.CodeForbidden)
+		case err.Error() == "no documents match the export criteria":
+			helpers.SendNotFound(c, "No documents match the export criteria")
+		case strings.HasPrefix(err.Error(), "invalid "),
+			strings.HasPrefix(err.Error(), "too many documents"),
+			strings.HasPrefix(err.Error(), "document IDs or at least one filter"):
+			helpers.SendBadRequest(c, err.Error())
+		case strings.Contains(err.Error(), "PDF service not available"):
+			helpers.SendInternalError(c, fmt.Errorf("PDF generation service is not available"))
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_exported",
+		Description:  fmt.Sprintf("Exported %d document(s) as a ZIP archive", export.DocumentCount),
+		ResourceType: "document",
+		Success:      true,
+		Details: map[string]interface{}{
+			"fileName":      export.FileName,
+			"documentCount": export.DocumentCount,
+			"skippedCount":  len(export.Skipped),
+			"documentIds":   req.DocumentIDs,
+			"status":        req.Status,
+			"macroId":       req.MacroID,
+			"departmentId":  req.DepartmentID,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Documents exported successfully", export)
+}
+
+// ViewDocument returns the document as HTML view (same design as PDF)
+// GET /api/documents/:id/view
+func (h *DocumentHandler) ViewDocument(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	fmt.Printf("👁️  [VIEW] Rendering HTML view for document ID: %s\n", id.Hex())
+
+	html, err := h.documentService.RenderDocumentView(ctx, id)
+	if err != nil {
+		fmt.Printf("❌ [VIEW] Error: %v\n", err)
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	// Return HTML with proper content type
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.String(http.StatusOK, html)
+}
+
+// GetDocumentVersions retrieves all versions of a document
+// GET /api/documents/:id/versions
+func (h *DocumentHandler) GetDocumentVersions(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+	versions, err := h.documentService.GetVersions(ctx, id)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	// Convert to response
+	responses := make([]models.DocumentVersionResponse, 0, len(versions))
+	for _, version := range versions {
+		responses = append(responses, version.ToResponse())
+	}
+
+	helpers.SendSuccess(c, "Document versions retrieved successfully", responses)
+}
+
+// OpenShortLink redirects the short link printed on a PDF to the live version
+// of the document in the web application, which asks the user to sign in
+// GET /d/:reference
+func (h *DocumentHandler) OpenShortLink(c *gin.Context) {
+	target, err := h.documentService.ResolveShortLink(c.Request.Context(), c.Param("reference"))
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, target)
+}
+
+// GetVersionPDF returns the PDF of a version snapshot, rendered from the
+// snapshot on the first request when the version has none yet
+// GET /api/documents/:id/versions/:versionId/pdf
+func (h *DocumentHandler) GetVersionPDF(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+	versionID, err := primitive.ObjectIDFromHex(c.Param("versionId"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid version ID format")
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	pdfURL, err := h.documentService.VersionPDF(c.Request.Context(), id, versionID, userID)
+	if err != nil {
+		var blocked *models.ExportBlockedError
+		if errors.As(err, &blocked) {
+			helpers.SendForbidden(c, blocked.Error(), models.CodeForbidden)
+			return
+		}
+		if err.Error() == "version not found" {
+			helpers.SendNotFound(c, "Version not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Version PDF retrieved successfully", gin.H{
+		"pdfUrl": pdfURL,
+	})
+}
+
+// GetPDFHistory lists the PDFs generated for a document, the most recent first
+// GET /api/documents/:id/pdf-history
+func (h *DocumentHandler) GetPDFHistory(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	history, err := h.documentService.PDFHistory(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "PDF history retrieved successfully", history)
+}
+
+// UpdateMetadata updates document metadata
+// PATCH /api/documents/:id/metadata
+func (h *DocumentHandler) UpdateMetadata(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	var req models.UpdateMetadataRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	// Get current user
+	_, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if !h.checkSectionAccess(c, id, models.DocumentSectionMetadata) {
+		return
+	}
+
+	fmt.Printf("📝 [DOCUMENT] Updating metadata for document ID: %s\n", id.Hex())
+
+	document, err := h.documentService.UpdateMetadata(ctx, id, &req)
+	if err != nil {
+		fmt.Printf("❌ [DOCUMENT] Failed to update metadata: %v\n", err)
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		if strings.HasPrefix(err.Error(), "unknown metadata section") || strings.HasPrefix(err.Error(), "duplicate metadata section") {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	fmt.Printf("✅ [DOCUMENT] Metadata updated successfully\n")
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "metadata_updated",
+		Description:  fmt.Sprintf("Updated metadata for document '%s'", document.Title),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"reference":  document.Reference,
+			"title":      document.Title,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Metadata updated successfully", document.ToResponse())
+}
+
+// CreateAnnex creates a new annex for a document
+// POST /api/documents/:id/annexes
+func (h *DocumentHandler) CreateAnnex(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	var req models.CreateAnnexRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	// Get current user
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if !h.checkSectionAccess(c, id, models.DocumentSectionAnnexes) {
+		return
+	}
+
+	fmt.Printf("📎 [DOCUMENT] Creating annex for document ID: %s\n", id.Hex())
+	fmt.Printf("   - Title: %s\n", req.Title)
+	fmt.Printf("   - Type: %s\n", req.Type)
+
+	annex, err := h.documentService.CreateAnnex(ctx, id, &req)
+	if err != nil {
+		fmt.Printf("❌ [DOCUMENT] Failed to create annex: %v\n", err)
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	fmt.Printf("✅ [DOCUMENT] Annex created successfully - ID: %s\n", annex.ID)
+
+	// Log activity
+	document, _ := h.documentService.GetByID(ctx, id)
+	activityReq := models.ActivityLogRequest{
+		Action:       "annex_created",
+		Description:  fmt.Sprintf("Created annex '%s' for document '%s'", annex.Title, document.Title),
+		ResourceType: "document",
+		ResourceID:   &id,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": id.Hex(),
+			"annexId":    annex.ID,
+			"annexTitle": annex.Title,
+			"annexType":  string(annex.Type),
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	h.asyncRunner.Go("watch_notifications", func(ctx context.Context) error {
+		return h.watchService.Notify(ctx, id, document, models.WatchEventNewAnnex,
+			"New Annex on a Watched Document",
+			fmt.Sprintf("Annex '%s' was added to document '%s' (%s).", annex.Title, document.Title, document.Reference),
+			user.ID)
+	})
+
+	helpers.SendSuccess(c, "Annex created successfully", annex)
+}
+
+// UpdateAnnex updates an existing annex
+// PATCH /api/documents/:id/annexes/:annexId
+func (h *DocumentHandler) UpdateAnnex(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	annexID := c.Param("annexId")
+
+	var req models.UpdateAnnexRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	// Get current user
+	_, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if !h.checkSectionAccess(c, id, models.DocumentSectionAnnexes) {
+		return
+	}
+
+	fmt.Printf("📝 [DOCUMENT] Updating annex %s for document ID: %s\n", annexID, id.Hex())
+
+	annex, err := h.documentService.UpdateAnnex(ctx, id, annexID, &req)
+	if err != nil {
+		fmt.Printf("❌ [DOCUMENT] Failed to update annex: %v\n", err)
+		if err.Error() == "document not found" || err.Error() == "annex not found" {
+			helpers.SendNotFound(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	fmt.Printf("✅ [DOCUMENT] Annex updated successfully\n")
+
+	// Log activity
+	document, _ := h.documentService.GetByID(ctx, id)
+	activityReq := models.ActivityLogRequest{
+		Action:       "annex_updated",
+		Description:  fmt.Sprintf("Updated annex '%s' for document '%s'", annex.Title, document.Title),
+		ResourceType: "document",
+		ResourceID:   &id,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": id.Hex(),
+			"annexId":    annex.ID,
+			"annexTitle": annex.Title,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Annex updated successfully", annex)
+}
+
+// DeleteAnnex deletes an annex from a document
+// DELETE /api/documents/:id/annexes/:annexId
+func (h *DocumentHandler) DeleteAnnex(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	annexID := c.Param("annexId")
+
+	// Get current user
+	_, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if !h.checkSectionAccess(c, id, models.DocumentSectionAnnexes) {
+		return
+	}
+
+	// Get annex title before deleting
+	document, err := h.documentService.GetByID(ctx, id)
+	if err != nil {
+		helpers.SendNotFound(c, "Document not found")
+		return
+	}
+
+	var annexTitle string
+	for _, annex := range document.Annexes {
+		if annex.ID == annexID {
+			annexTitle = annex.Title
+			break
+		}
+	}
+
+	fmt.Printf("🗑️ [DOCUMENT] Deleting annex %s from document ID: %s\n", annexID, id.Hex())
+
+	err = h.documentService.DeleteAnnex(ctx, id, annexID)
+	if err != nil {
+		fmt.Printf("❌ [DOCUMENT] Failed to delete annex: %v\n", err)
+		if err.Error() == "document not found" || err.Error() == "annex not found" {
+			helpers.SendNotFound(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	fmt.Printf("✅ [DOCUMENT] Annex deleted successfully\n")
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "annex_deleted",
+		Description:  fmt.Sprintf("Deleted annex '%s' from document '%s'", annexTitle, document.Title),
+		ResourceType: "document",
+		ResourceID:   &id,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": id.Hex(),
+			"annexId":    annexID,
+			"annexTitle": annexTitle,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Annex deleted successfully", nil)
+}
+
+// UploadAnnexFiles handles file uploads for an annex
+// POST /api/documents/:id/annexes/:annexId/files
+func (h *DocumentHandler) UploadAnnexFiles(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	annexID := c.Param("annexId")
+
+	// Get current user
+	_, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if !h.checkSectionAccess(c, id, models.DocumentSectionAnnexes) {
+		return
+	}
+
+	// Get the multipart form
+	form, err := c.MultipartForm()
+	if err != nil {
+		helpers.SendBadRequest(c, "Failed to parse multipart form")
+		return
+	}
+
+	files := form.File["files"]
+	if len(files) == 0 {
+		helpers.SendBadRequest(c, "No files uploaded")
+		return
+	}
+
+	fmt.Printf("📎 [UPLOAD] Uploading %d files for annex %s\n", len(files), annexID)
+
+	uploadedFiles := []map[string]interface{}{}
+
+	for _, fileHeader := range files {
+		// Open the uploaded file
+		file, err := fileHeader.Open()
+		if err != nil {
+			helpers.SendBadRequest(c, fmt.Sprintf("Failed to open file %s: %v", fileHeader.Filename, err))
+			return
+		}
+		defer file.Close()
+
+		// Generate unique file ID
+		fileID := primitive.NewObjectID().Hex()
+
+		// Upload to MinIO
+		fileURL, err := h.minioService.UploadAnnexFile(
+			ctx,
+			id.Hex(),
+			annexID,
+			fileID,
+			file,
+			fileHeader.Size,
+			fileHeader.Header.Get("Content-Type"),
+			fileHeader.Filename,
+		)
+		if err != nil {
+			helpers.SendInternalError(c, fmt.Errorf("failed to upload file %s: %w", fileHeader.Filename, err))
+			return
+		}
+
+		uploadedFile := map[string]interface{}{
+			"id":         fileID,
+			"name":       fileHeader.Filename,
+			"type":       fileHeader.Header.Get("Content-Type"),
+			"size":       fileHeader.Size,
+			"url":        fileURL,
+			"uploadedAt": time.Now().Format(time.RFC3339),
+		}
+		uploadedFiles = append(uploadedFiles, uploadedFile)
+	}
+
+	// Get current annex content
+	document, err := h.documentService.GetByID(ctx, id)
+	if err != nil {
+		helpers.SendNotFound(c, "Document not found")
+		return
+	}
+
+	// Find the annex and update its files
+	var annexFound bool
+	for i, annex := range document.Annexes {
+		if annex.ID == annexID {
+			annexFound = true
+
+			// Get existing files or initialize empty array
+			existingFiles, ok := annex.Content["files"].([]interface{})
+			if !ok {
+				existingFiles = []interface{}{}
+			}
+
+			// Append new files
+			for _, file := range uploadedFiles {
+				existingFiles = append(existingFiles, file)
+			}
+
+			// Update annex content
+			document.Annexes[i].Content["files"] = existingFiles
+			break
+		}
+	}
+
+	if !annexFound {
+		helpers.SendNotFound(c, "Annex not found")
+		return
+	}
+
+	// Update the document with new files
+	_, err = h.documentService.UpdateAnnex(ctx, id, annexID, &models.UpdateAnnexRequest{
+		Content: &document.Annexes[0].Content,
+	})
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	fmt.Printf("✅ [UPLOAD] Files uploaded successfully\n")
+
+	c.JSON(http.StatusOK, uploadedFiles)
+}
+
+// DeleteAnnexFile handles file deletion from an annex
+// DELETE /api/documents/:id/annexes/:annexId/files/:fileId
+func (h *DocumentHandler) DeleteAnnexFile(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	annexID := c.Param("annexId")
+	fileID := c.Param("fileId")
+
+	// Get current user
 	_, exists := middleware.GetCurrentUser(c)
 	if !exists {
 		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
 		return
 	}
 
-	ctx := c.Request.Context()
+	ctx := c.Request.Context()
+
+	if !h.checkSectionAccess(c, id, models.DocumentSectionAnnexes) {
+		return
+	}
+
+	fmt.Printf("🗑️ [DELETE] Deleting file %s from annex %s\n", fileID, annexID)
+
+	// Get current document
+	document, err := h.documentService.GetByID(ctx, id)
+	if err != nil {
+		helpers.SendNotFound(c, "Document not found")
+		return
+	}
+
+	// Find the annex and remove the file
+	var annexFound bool
+	var fileURL string
+	for i, annex := range document.Annexes {
+		if annex.ID == annexID {
+			annexFound = true
+
+			// Get existing files
+			existingFiles, ok := annex.Content["files"].([]interface{})
+			if !ok {
+				existingFiles = []interface{}{}
+			}
+
+			// Remove the file with matching ID and get its URL for MinIO deletion
+			updatedFiles := []interface{}{}
+			for _, file := range existingFiles {
+				fileMap, ok := file.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if fileMap["id"] == fileID {
+					// Store the URL for MinIO deletion
+					if url, ok := fileMap["url"].(string); ok {
+						fileURL = url
+					}
+				} else {
+					updatedFiles = append(updatedFiles, file)
+				}
+			}
+
+			// Update annex content
+			document.Annexes[i].Content["files"] = updatedFiles
+			break
+		}
+	}
+
+	if !annexFound {
+		helpers.SendNotFound(c, "Annex not found")
+		return
+	}
+
+	// Delete file from MinIO if URL exists
+	if fileURL != "" {
+		if err := h.minioService.DeleteAnnexFile(ctx, fileURL); err != nil {
+			// Log the error but don't fail the request
+			fmt.Printf("⚠️  [WARNING] Failed to delete file from MinIO: %v\n", err)
+		}
+	}
+
+	// Update the document
+	_, err = h.documentService.UpdateAnnex(ctx, id, annexID, &models.UpdateAnnexRequest{
+		Content: &document.Annexes[0].Content,
+	})
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	fmt.Printf("✅ [DELETE] File deleted successfully\n")
+
+	helpers.SendSuccess(c, "File deleted successfully", nil)
+}
+
+// AttachToMacro moves a document to a macro and assigns it the next process code of the macro
+// PUT /api/documents/:id/macro
+func (h *DocumentHandler) AttachToMacro(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	var req models.AttachMacroRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	macroID, err := primitive.ObjectIDFromHex(req.MacroID)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid macro ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.documentService.AttachToMacro(ctx, id, macroID)
+	if err != nil {
+		switch {
+		case err.Error() == "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case strings.HasSuffix(err.Error(), "macro not found"):
+			helpers.SendNotFound(c, "Macro not found")
+		case err == services.ErrDocumentRevisionConflict:
+			helpers.SendConflict(c, "Document is being modified by another user, please retry")
+		case strings.HasSuffix(err.Error(), "document is locked"):
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_macro_changed",
+		Description:  fmt.Sprintf("Attached document '%s' to a macro as process %s", document.Title, document.ProcessCode),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId":  document.ID.Hex(),
+			"macroId":     macroID.Hex(),
+			"processCode": document.ProcessCode,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Document attached to macro successfully", document.ToResponse())
+}
+
+// ReorderAnnexes changes the order of the annexes of a document
+// PATCH /api/documents/:id/annexes/reorder
+func (h *DocumentHandler) ReorderAnnexes(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	var req models.ReorderAnnexesRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	if !h.checkSectionAccess(c, id, models.DocumentSectionAnnexes) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	annexes, err := h.documentService.ReorderAnnexes(ctx, id, req.AnnexIDs)
+	if err != nil {
+		switch {
+		case err.Error() == "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case err == services.ErrDocumentRevisionConflict:
+			helpers.SendConflict(c, "Document is being modified by another user, please retry")
+		case strings.HasPrefix(err.Error(), "annex order must list"),
+			strings.HasSuffix(err.Error(), "document is locked"):
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       "annexes_reordered",
+		Description:  "Reordered the annexes of the document",
+		ResourceType: "document",
+		ResourceID:   &id,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": id.Hex(),
+			"annexIds":   req.AnnexIDs,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Annexes reordered successfully", annexes)
+}
 
-	// Get the multipart form
-	form, err := c.MultipartForm()
+// ExportAnnex downloads the cells of a table or rich text annex as a spreadsheet
+// GET /api/documents/:id/annexes/:annexId/export?format=xlsx|csv
+func (h *DocumentHandler) ExportAnnex(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
-		helpers.SendBadRequest(c, "Failed to parse multipart form")
+		helpers.SendBadRequest(c, "Invalid document ID format")
 		return
 	}
 
-	files := form.File["files"]
-	if len(files) == 0 {
-		helpers.SendBadRequest(c, "No files uploaded")
+	format := c.DefaultQuery("format", "xlsx")
+	if format != "xlsx" && format != "csv" {
+		helpers.SendBadRequest(c, "Invalid format: must be xlsx or csv")
 		return
 	}
 
-	fmt.Printf("📎 [UPLOAD] Uploading %d files for annex %s\n", len(files), annexID)
+	table, err := h.documentService.AnnexTable(c.Request.Context(), id, c.Param("annexId"))
+	if err != nil {
+		switch {
+		case err.Error() == "document not found" || err.Error() == "annex not found":
+			helpers.SendNotFound(c, err.Error())
+		case strings.HasPrefix(err.Error(), "annex has no table"):
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
 
-	uploadedFiles := []map[string]interface{}{}
+	var (
+		data        []byte
+		contentType string
+	)
+	switch format {
+	case "csv":
+		data, err = writeCSV(table.Header, table.Rows)
+		contentType = "text/csv; charset=utf-8"
+	default:
+		data, err = helpers.WriteXLSX(annexSheetName(table.Title), table.Header, table.Rows)
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
 
-	for _, fileHeader := range files {
-		// Open the uploaded file
-		file, err := fileHeader.Open()
-		if err != nil {
-			helpers.SendBadRequest(c, fmt.Sprintf("Failed to open file %s: %v", fileHeader.Filename, err))
-			return
+	name := strings.NewReplacer("/", "-", "\\", "-").Replace(table.Reference + "_" + table.Title)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+format))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// annexSheetName makes an annex title a valid sheet name: at most 31
+// characters, without the ones Excel forbids
+func annexSheetName(title string) string {
+	name := strings.TrimSpace(strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
 		}
-		defer file.Close()
+		return r
+	}, title))
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" {
+		return "Annexe"
+	}
+	return name
+}
 
-		// Generate unique file ID
-		fileID := primitive.NewObjectID().Hex()
+// ReorderSteps changes the order of the steps of a process group
+// PATCH /api/documents/:id/process-groups/:groupId/steps/reorder
+func (h *DocumentHandler) ReorderSteps(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
 
-		// Upload to MinIO
-		fileURL, err := h.minioService.UploadAnnexFile(
-			ctx,
-			id.Hex(),
-			annexID,
-			fileID,
-			file,
-			fileHeader.Size,
-			fileHeader.Header.Get("Content-Type"),
-			fileHeader.Filename,
-		)
-		if err != nil {
-			helpers.SendInternalError(c, fmt.Errorf("failed to upload file %s: %w", fileHeader.Filename, err))
-			return
-		}
+	var req models.ReorderStepsRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
 
-		uploadedFile := map[string]interface{}{
-			"id":         fileID,
-			"name":       fileHeader.Filename,
-			"type":       fileHeader.Header.Get("Content-Type"),
-			"size":       fileHeader.Size,
-			"url":        fileURL,
-			"uploadedAt": time.Now().Format(time.RFC3339),
-		}
-		uploadedFiles = append(uploadedFiles, uploadedFile)
+	if !h.checkSectionAccess(c, id, models.DocumentSectionProcessGroups) {
+		return
 	}
 
-	// Get current annex content
-	document, err := h.documentService.GetByID(ctx, id)
+	ctx := c.Request.Context()
+	groupID := c.Param("groupId")
+
+	group, err := h.documentService.ReorderSteps(ctx, id, groupID, req.StepIDs)
 	if err != nil {
-		helpers.SendNotFound(c, "Document not found")
+		sendStepEditError(c, err)
 		return
 	}
 
-	// Find the annex and update its files
-	var annexFound bool
-	for i, annex := range document.Annexes {
-		if annex.ID == annexID {
-			annexFound = true
+	activityReq := models.ActivityLogRequest{
+		Action:       "process_steps_reordered",
+		Description:  fmt.Sprintf("Reordered the steps of process group '%s'", group.Title),
+		ResourceType: "document",
+		ResourceID:   &id,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": id.Hex(),
+			"groupId":    groupID,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
 
-			// Get existing files or initialize empty array
-			existingFiles, ok := annex.Content["files"].([]interface{})
-			if !ok {
-				existingFiles = []interface{}{}
-			}
+	helpers.SendSuccess(c, "Process steps reordered successfully", group)
+}
 
-			// Append new files
-			for _, file := range uploadedFiles {
-				existingFiles = append(existingFiles, file)
-			}
+// BulkEditSteps creates, updates and deletes steps of a process group at once
+// POST /api/documents/:id/process-groups/:groupId/steps/bulk
+func (h *DocumentHandler) BulkEditSteps(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
 
-			// Update annex content
-			document.Annexes[i].Content["files"] = existingFiles
-			break
-		}
+	var req models.BulkStepsRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+	if len(req.Create) == 0 && len(req.Update) == 0 && len(req.Delete) == 0 {
+		helpers.SendBadRequest(c, "At least one step to create, update or delete is required")
+		return
 	}
 
-	if !annexFound {
-		helpers.SendNotFound(c, "Annex not found")
+	if !h.checkSectionAccess(c, id, models.DocumentSectionProcessGroups) {
 		return
 	}
 
-	// Update the document with new files
-	_, err = h.documentService.UpdateAnnex(ctx, id, annexID, &models.UpdateAnnexRequest{
-		Content: &document.Annexes[0].Content,
-	})
+	ctx := c.Request.Context()
+	groupID := c.Param("groupId")
+
+	group, err := h.documentService.BulkEditSteps(ctx, id, groupID, &req)
 	if err != nil {
-		helpers.SendInternalError(c, err)
+		sendStepEditError(c, err)
 		return
 	}
 
-	fmt.Printf("✅ [UPLOAD] Files uploaded successfully\n")
+	activityReq := models.ActivityLogRequest{
+		Action:       "process_steps_updated",
+		Description:  fmt.Sprintf("Edited the steps of process group '%s'", group.Title),
+		ResourceType: "document",
+		ResourceID:   &id,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": id.Hex(),
+			"groupId":    groupID,
+			"created":    len(req.Create),
+			"updated":    len(req.Update),
+			"deleted":    len(req.Delete),
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
 
-	c.JSON(http.StatusOK, uploadedFiles)
+	helpers.SendSuccess(c, "Process steps updated successfully", group)
 }
 
-// DeleteAnnexFile handles file deletion from an annex
-// DELETE /api/documents/:id/annexes/:annexId/files/:fileId
-func (h *DocumentHandler) DeleteAnnexFile(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := primitive.ObjectIDFromHex(idParam)
+// sendStepEditError maps the errors of process step edits to responses
+func sendStepEditError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "document not found":
+		helpers.SendNotFound(c, "Document not found")
+	case err.Error() == "process group not found":
+		helpers.SendNotFound(c, "Process group not found")
+	case err == services.ErrDocumentRevisionConflict:
+		helpers.SendConflict(c, "Document is being modified by another user, please retry")
+	case strings.HasPrefix(err.Error(), "process step not found"),
+		strings.HasPrefix(err.Error(), "duplicate process step ID"),
+		strings.HasPrefix(err.Error(), "step order must list"),
+		strings.HasSuffix(err.Error(), "document is locked"):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
+
+// checkSectionAccess sends a per-field authorization error and returns false
+// when the current user may not modify a locked section of the document
+func (h *DocumentHandler) checkSectionAccess(c *gin.Context, id primitive.ObjectID, section models.DocumentSection) bool {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return false
+	}
+
+	err := h.documentService.CheckSectionAccess(c.Request.Context(), id, section, user.ID, user.Role)
+	if err == nil {
+		return true
+	}
+
+	var accessErr *models.SectionAccessError
+	switch {
+	case errors.As(err, &accessErr):
+		helpers.SendFieldAuthorizationErrors(c, "You are not allowed to modify this section of the document", accessErr.Fields)
+	case err.Error() == "document not found":
+		helpers.SendNotFound(c, "Document not found")
+	default:
+		helpers.SendInternalError(c, err)
+	}
+	return false
+}
+
+// SetSectionLock restricts the edition of a section to some users and contributor teams
+// PUT /api/documents/:id/section-locks/:section
+func (h *DocumentHandler) SetSectionLock(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		helpers.SendBadRequest(c, "Invalid document ID format")
 		return
 	}
 
-	annexID := c.Param("annexId")
-	fileID := c.Param("fileId")
+	var req models.SectionLockRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
 
-	// Get current user
-	_, exists := middleware.GetCurrentUser(c)
+	user, exists := middleware.GetCurrentUser(c)
 	if !exists {
 		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
 		return
 	}
 
 	ctx := c.Request.Context()
+	section := models.DocumentSection(c.Param("section"))
 
-	fmt.Printf("🗑️ [DELETE] Deleting file %s from annex %s\n", fileID, annexID)
-
-	// Get current document
-	document, err := h.documentService.GetByID(ctx, id)
+	document, err := h.documentService.SetSectionLock(ctx, id, section, &req, user.ID, user.Role)
 	if err != nil {
-		helpers.SendNotFound(c, "Document not found")
+		sendSectionLockError(c, err)
 		return
 	}
 
-	// Find the annex and remove the file
-	var annexFound bool
-	var fileURL string
-	for i, annex := range document.Annexes {
-		if annex.ID == annexID {
-			annexFound = true
-
-			// Get existing files
-			existingFiles, ok := annex.Content["files"].([]interface{})
-			if !ok {
-				existingFiles = []interface{}{}
-			}
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "section_locked",
+		Description:  fmt.Sprintf("Locked the %s section of document '%s'", section, document.Title),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"section":    string(section),
+			"userIds":    req.UserIDs,
+			"teams":      req.Teams,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
 
-			// Remove the file with matching ID and get its URL for MinIO deletion
-			updatedFiles := []interface{}{}
-			for _, file := range existingFiles {
-				fileMap, ok := file.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				if fileMap["id"] == fileID {
-					// Store the URL for MinIO deletion
-					if url, ok := fileMap["url"].(string); ok {
-						fileURL = url
-					}
-				} else {
-					updatedFiles = append(updatedFiles, file)
-				}
-			}
+	setDocumentETag(c, document)
+	helpers.SendSuccess(c, "Section locked successfully", document.ToResponse())
+}
 
-			// Update annex content
-			document.Annexes[i].Content["files"] = updatedFiles
-			break
-		}
+// RemoveSectionLock opens a locked section again
+// DELETE /api/documents/:id/section-locks/:section
+func (h *DocumentHandler) RemoveSectionLock(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
 	}
 
-	if !annexFound {
-		helpers.SendNotFound(c, "Annex not found")
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
 		return
 	}
 
-	// Delete file from MinIO if URL exists
-	if fileURL != "" {
-		if err := h.minioService.DeleteAnnexFile(ctx, fileURL); err != nil {
-			// Log the error but don't fail the request
-			fmt.Printf("⚠️  [WARNING] Failed to delete file from MinIO: %v\n", err)
-		}
-	}
+	ctx := c.Request.Context()
+	section := models.DocumentSection(c.Param("section"))
 
-	// Update the document
-	_, err = h.documentService.UpdateAnnex(ctx, id, annexID, &models.UpdateAnnexRequest{
-		Content: &document.Annexes[0].Content,
-	})
+	document, err := h.documentService.RemoveSectionLock(ctx, id, section, user.ID, user.Role)
 	if err != nil {
-		helpers.SendInternalError(c, err)
+		sendSectionLockError(c, err)
 		return
 	}
 
-	fmt.Printf("✅ [DELETE] File deleted successfully\n")
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "section_unlocked",
+		Description:  fmt.Sprintf("Unlocked the %s section of document '%s'", section, document.Title),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"section":    string(section),
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
 
-	helpers.SendSuccess(c, "File deleted successfully", nil)
+	setDocumentETag(c, document)
+	helpers.SendSuccess(c, "Section unlocked successfully", document.ToResponse())
+}
+
+// sendSectionLockError maps section lock errors to HTTP responses
+func sendSectionLockError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "document not found", err.Error() == "section lock not found":
+		helpers.SendNotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "only the document owner"):
+		helpers.SendForbidden(c, err.Error(), models.CodeForbidden)
+	case strings.HasPrefix(err.Error(), "invalid"):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
 }
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestUpdateDocumentRequiresCurrentRevision(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("revision precondition", func(mt *mtest.T) {
+		user := &models.User{ID: primitive.NewObjectID(), Role: models.RoleAdmin, Active: true}
+		documentID := primitive.NewObjectID()
+		document := bson.D{
+			{Key: "_id", Value: documentID},
+			{Key: "reference", Value: "PRO-ACH-001"},
+			{Key: "title", Value: "Purchasing procedure"},
+			{Key: "status", Value: string(models.DocumentStatusDraft)},
+			{Key: "created_by", Value: user.ID},
+			{Key: "revision", Value: int64(5)},
+		}
+
+		documentService := services.NewDocumentService(mt.DB, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		handler := NewDocumentHandler(documentService, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		update := func(ifMatch string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/api/documents/"+documentID.Hex(), strings.NewReader(`{"title":"Purchasing procedure v2"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if ifMatch != "" {
+				c.Request.Header.Set("If-Match", ifMatch)
+			}
+			c.Params = gin.Params{{Key: "id", Value: documentID.Hex()}}
+			c.Set("user", user)
+			handler.UpdateDocument(c)
+			return w
+		}
+
+		// Neither a revision nor If-Match: the write could overwrite another user's changes
+		if w := update(""); w.Code != http.StatusPreconditionRequired {
+			t.Fatalf("expected 428 without a revision, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(mt.GetAllStartedEvents()) != 0 {
+			t.Fatalf("expected the document not to be read without a revision")
+		}
+
+		// An outdated revision is a conflict, answered with the current document
+		ns := mt.DB.Name() + ".documents"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, document),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, document),
+		)
+		w := update(`"3"`)
+		if w.Code != http.StatusConflict {
+			t.Fatalf("expected 409 for an outdated revision, got %d: %s", w.Code, w.Body.String())
+		}
+		if etag := w.Header().Get("ETag"); etag != `"5"` {
+			t.Errorf("expected the ETag of the current revision, got %q", etag)
+		}
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName == "update" || event.CommandName == "findAndModify" {
+				t.Errorf("expected the document not to be written on a conflict, got %s", event.CommandName)
+			}
+		}
+	})
+}
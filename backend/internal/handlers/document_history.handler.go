@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DocumentHistoryHandler handles the audit trail of documents
+type DocumentHistoryHandler struct {
+	historyService *services.DocumentHistoryService
+}
+
+// NewDocumentHistoryHandler creates a new document history handler instance
+func NewDocumentHistoryHandler(historyService *services.DocumentHistoryService) *DocumentHistoryHandler {
+	return &DocumentHistoryHandler{
+		historyService: historyService,
+	}
+}
+
+// GetDocumentHistory returns the chronological timeline of a document
+// GET /api/documents/:id/history
+func (h *DocumentHistoryHandler) GetDocumentHistory(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	filter := &models.DocumentHistoryFilter{
+		Desc: c.Query("order") == "desc",
+	}
+
+	if sourcesStr := c.Query("sources"); sourcesStr != "" {
+		for _, source := range strings.Split(sourcesStr, ",") {
+			source = strings.TrimSpace(source)
+			if !models.IsValidDocumentHistorySource(source) {
+				helpers.SendBadRequest(c, "Invalid history source: "+source)
+				return
+			}
+			filter.Sources = append(filter.Sources, models.DocumentHistorySource(source))
+		}
+	}
+
+	if dateFromStr := c.Query("dateFrom"); dateFromStr != "" {
+		if dateFrom, err := time.Parse(time.RFC3339, dateFromStr); err == nil {
+			filter.DateFrom = &dateFrom
+		}
+	}
+
+	if dateToStr := c.Query("dateTo"); dateToStr != "" {
+		if dateTo, err := time.Parse(time.RFC3339, dateToStr); err == nil {
+			filter.DateTo = &dateTo
+		}
+	}
+
+	entries, err := h.historyService.GetHistory(c.Request.Context(), documentID, filter)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	page, limit := helpers.GetPaginationParams(c)
+	total := len(entries)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	helpers.SendSuccessWithPagination(c, "Document history retrieved successfully", entries[start:end], helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -28,8 +27,7 @@ func NewDomainHandler(db *services.DatabaseService) *DomainHandler {
 // GetDomains returns all domains with optional filtering
 // GET /api/domains
 func (h *DomainHandler) GetDomains(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	filter := bson.M{}
 
@@ -73,8 +71,7 @@ func (h *DomainHandler) GetDomain(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	collection := h.db.Collection("domains")
 	var domain models.Domain
@@ -102,8 +99,7 @@ func (h *DomainHandler) CreateDomain(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Check if domain code already exists
 	collection := h.db.Collection("domains")
@@ -153,8 +149,7 @@ func (h *DomainHandler) UpdateDomain(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	collection := h.db.Collection("domains")
 
@@ -221,8 +216,7 @@ func (h *DomainHandler) DeleteDomain(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Check if domain has departments
 	deptCollection := h.db.Collection("departments")
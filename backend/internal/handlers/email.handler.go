@@ -1,30 +1,44 @@
 package handlers
 
 import (
-	"context"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/i18n"
 	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
 	"github.com/kodesonik/process-manager/internal/services"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // EmailHandler handles email-related operations (SMTP)
 type EmailHandler struct {
-	emailService *services.EmailService
-	userService  *services.UserService
+	emailService    *services.EmailService
+	userService     *services.UserService
+	campaignService *services.EmailCampaignService
+	outboxService   *services.EmailOutboxService
 }
 
 // NewEmailHandler creates a new email handler
-func NewEmailHandler(emailService *services.EmailService, userService *services.UserService) *EmailHandler {
+func NewEmailHandler(emailService *services.EmailService, userService *services.UserService, campaignService *services.EmailCampaignService, outboxService *services.EmailOutboxService) *EmailHandler {
 	return &EmailHandler{
-		emailService: emailService,
-		userService:  userService,
+		emailService:    emailService,
+		userService:     userService,
+		campaignService: campaignService,
+		outboxService:   outboxService,
 	}
 }
 
+// emailLanguage returns the language of the emails sent to a user: the one
+// of their profile, else the language of the current request
+func emailLanguage(c *gin.Context, user *models.User) string {
+	if user != nil && user.Language != "" {
+		return user.Language
+	}
+	return i18n.GetLanguageFromContext(c)
+}
+
 // SendTestEmail tests email configuration
 func (h *EmailHandler) SendTestEmail(c *gin.Context) {
 	currentUser, _ := middleware.GetCurrentUser(c)
@@ -39,12 +53,12 @@ func (h *EmailHandler) SendTestEmail(c *gin.Context) {
 	}
 
 	// Send test email
-	subject := "Test Email - Process Manager"
-	body := `<p>This is a test email from Process Manager to verify email configuration is working properly.</p>
-	<p>If you received this email, it means the email service is configured correctly.</p>`
+	lang := emailLanguage(c, currentUser)
+	subject := i18n.T(lang, "email.test.subject")
+	body := i18n.T(lang, "email.test.body")
 
 	fullName := currentUser.FirstName + " " + currentUser.LastName
-	if err := h.emailService.SendCustomEmail(input.Email, fullName, subject, body); err != nil {
+	if err := h.emailService.DeliverCustomEmail(input.Email, fullName, subject, body, lang); err != nil {
 		helpers.SendInternalError(c, err)
 		return
 	}
@@ -57,8 +71,7 @@ func (h *EmailHandler) SendTestEmail(c *gin.Context) {
 
 // SendEmailToUser sends a custom email to a specific user (Admin only)
 func (h *EmailHandler) SendEmailToUser(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	var input struct {
 		UserID  string `json:"userId" binding:"required"`
@@ -95,7 +108,7 @@ func (h *EmailHandler) SendEmailToUser(c *gin.Context) {
 
 	// Send email
 	fullName := user.FirstName + " " + user.LastName
-	if err := h.emailService.SendCustomEmail(user.Email, fullName, input.Subject, emailBody); err != nil {
+	if err := h.emailService.SendCustomEmail(user.Email, fullName, input.Subject, emailBody, user.Language); err != nil {
 		helpers.SendInternalError(c, err)
 		return
 	}
@@ -149,7 +162,7 @@ func (h *EmailHandler) SendBroadcastEmail(c *gin.Context) {
 
 	for _, user := range users {
 		fullName := user.FirstName + " " + user.LastName
-		if err := h.emailService.SendCustomEmail(user.Email, fullName, input.Subject, emailBody); err != nil {
+		if err := h.emailService.SendCustomEmail(user.Email, fullName, input.Subject, emailBody, user.Language); err != nil {
 			failed++
 			errors = append(errors, user.Email+": "+err.Error())
 		} else {
@@ -180,8 +193,7 @@ func (h *EmailHandler) SendBroadcastEmail(c *gin.Context) {
 
 // SendEmailToGroup sends an email to a group of users (Admin only)
 func (h *EmailHandler) SendEmailToGroup(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	var input struct {
 		UserIDs []string `json:"userIds" binding:"required"`
@@ -228,7 +240,7 @@ func (h *EmailHandler) SendEmailToGroup(c *gin.Context) {
 		}
 
 		fullName := user.FirstName + " " + user.LastName
-		if err := h.emailService.SendCustomEmail(user.Email, fullName, input.Subject, emailBody); err != nil {
+		if err := h.emailService.SendCustomEmail(user.Email, fullName, input.Subject, emailBody, user.Language); err != nil {
 			failed++
 			errors = append(errors, user.Email+": "+err.Error())
 		} else {
@@ -261,4 +273,269 @@ func (h *EmailHandler) SendEmailToGroup(c *gin.Context) {
 	} else {
 		helpers.SendSuccess(c, "Group email sent successfully", response)
 	}
-}
\ No newline at end of file
+}
+
+// PreviewCampaign renders a campaign for a sample recipient and counts its segment (Admin only)
+// POST /api/emails/campaigns/preview
+func (h *EmailHandler) PreviewCampaign(c *gin.Context) {
+	var req models.PreviewEmailCampaignRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	preview, err := h.campaignService.Preview(c.Request.Context(), &req)
+	if err != nil {
+		sendCampaignError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Campaign preview generated successfully", preview)
+}
+
+// CreateCampaign schedules an email to a user segment (Admin only)
+// POST /api/emails/campaigns
+// POST /api/admin/emails/broadcast
+func (h *EmailHandler) CreateCampaign(c *gin.Context) {
+	var req models.CreateEmailCampaignRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	currentUser, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	campaign, err := h.campaignService.Create(c.Request.Context(), &req, currentUser.ID)
+	if err != nil {
+		sendCampaignError(c, err)
+		return
+	}
+
+	helpers.SendCreated(c, "Campaign scheduled successfully", campaign)
+}
+
+// ListCampaigns lists email campaigns, most recent first (Admin only)
+// GET /api/emails/campaigns
+func (h *EmailHandler) ListCampaigns(c *gin.Context) {
+	page, limit := helpers.GetPaginationParams(c)
+
+	campaigns, total, err := h.campaignService.List(c.Request.Context(), page, limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Campaigns retrieved successfully", campaigns, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
+
+// ListOutbox returns the emails of the outbox with their delivery status, to
+// inspect the failed sends (Admin only)
+// GET /api/admin/emails?status=failed&kind=otp&email=john
+func (h *EmailHandler) ListOutbox(c *gin.Context) {
+	var filter models.OutboxEmailFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		helpers.SendBadRequest(c, "Invalid query parameters")
+		return
+	}
+	switch filter.Status {
+	case "", models.EmailDeliveryStatusPending, models.EmailDeliveryStatusSending, models.EmailDeliveryStatusSent, models.EmailDeliveryStatusFailed:
+	default:
+		helpers.SendBadRequest(c, "Invalid status: must be pending, sending, sent or failed")
+		return
+	}
+	switch filter.Event {
+	case "", models.EmailEventDelivered, models.EmailEventOpened, models.EmailEventSoftBounce, models.EmailEventHardBounce,
+		models.EmailEventSpam, models.EmailEventBlocked, models.EmailEventInvalidEmail:
+	default:
+		helpers.SendBadRequest(c, "Invalid event: must be delivered, opened, soft_bounce, hard_bounce, spam, blocked or invalid_email")
+		return
+	}
+	filter.Page, filter.Limit = helpers.GetPaginationParams(c)
+
+	emails, total, err := h.outboxService.List(c.Request.Context(), filter)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Emails retrieved successfully", emails, helpers.PaginationInfo{
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		Total:      int(total),
+		TotalPages: (int(total) + filter.Limit - 1) / filter.Limit,
+	})
+}
+
+// GetCampaign returns a campaign with its delivery counters (Admin only)
+// GET /api/emails/campaigns/:id
+func (h *EmailHandler) GetCampaign(c *gin.Context) {
+	campaignID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid campaign ID format")
+		return
+	}
+
+	campaign, err := h.campaignService.GetByID(c.Request.Context(), campaignID)
+	if err != nil {
+		sendCampaignError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Campaign retrieved successfully", campaign)
+}
+
+// GetCampaignRecipients reports the delivery status of each recipient (Admin only)
+// GET /api/emails/campaigns/:id/recipients?status=failed
+func (h *EmailHandler) GetCampaignRecipients(c *gin.Context) {
+	campaignID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid campaign ID format")
+		return
+	}
+
+	page, limit := helpers.GetPaginationParams(c)
+	ctx := c.Request.Context()
+
+	if _, err := h.campaignService.GetByID(ctx, campaignID); err != nil {
+		sendCampaignError(c, err)
+		return
+	}
+
+	recipients, total, counts, err := h.campaignService.GetRecipients(ctx, campaignID, c.Query("status"), page, limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Campaign recipients retrieved successfully", gin.H{
+		"recipients": recipients,
+		"counts":     counts,
+	}, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
+
+// CancelCampaign stops a scheduled or sending campaign (Admin only)
+// POST /api/emails/campaigns/:id/cancel
+func (h *EmailHandler) CancelCampaign(c *gin.Context) {
+	campaignID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid campaign ID format")
+		return
+	}
+
+	campaign, err := h.campaignService.Cancel(c.Request.Context(), campaignID)
+	if err != nil {
+		sendCampaignError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Campaign cancelled successfully", campaign)
+}
+
+// sendCampaignError maps campaign service errors to HTTP responses
+func sendCampaignError(c *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case msg == "campaign not found":
+		helpers.SendNotFound(c, "Campaign not found")
+	case msg == "campaign is already finished":
+		helpers.SendConflict(c, msg)
+	case strings.HasPrefix(msg, "invalid") || msg == "scheduled time must be in the future":
+		helpers.SendBadRequest(c, msg)
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
+
+// ListUndeliverable returns the addresses flagged after a bounce, a block or
+// a spam report
+// GET /api/admin/emails/undeliverable
+func (h *EmailHandler) ListUndeliverable(c *gin.Context) {
+	page, limit := helpers.GetPaginationParams(c)
+
+	emails, total, err := h.outboxService.ListUndeliverable(c.Request.Context(), page, limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Undeliverable emails retrieved successfully", emails, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
+
+// ClearUndeliverable lifts the flag of an address once it has been fixed
+// DELETE /api/admin/emails/undeliverable/:email
+func (h *EmailHandler) ClearUndeliverable(c *gin.Context) {
+	if err := h.outboxService.ClearUndeliverable(c.Request.Context(), c.Param("email")); err != nil {
+		if err.Error() == "undeliverable email not found" {
+			helpers.SendNotFound(c, "Undeliverable email not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Undeliverable email cleared successfully", nil)
+}
+
+// TestEmailProvider sends a test email through the primary provider, or the
+// one named in the request, and returns the diagnostics of the delivery. A
+// failed delivery is reported in the diagnostics rather than as an error.
+// POST /api/admin/emails/test
+func (h *EmailHandler) TestEmailProvider(c *gin.Context) {
+	var req models.SendTestEmailRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	currentUser, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+	if req.Email == "" {
+		req.Email = currentUser.Email
+	}
+
+	lang := emailLanguage(c, currentUser)
+	subject := i18n.T(lang, "email.test.subject")
+	body := i18n.T(lang, "email.test.body")
+	fullName := currentUser.FirstName + " " + currentUser.LastName
+
+	report, err := h.emailService.SendTestEmail(req.Email, fullName, subject, body, lang, strings.ToLower(strings.TrimSpace(req.Provider)))
+	if err != nil {
+		switch {
+		case err.Error() == "no email provider configured":
+			helpers.SendServiceUnavailable(c, err.Error())
+		case strings.HasPrefix(err.Error(), "email provider "):
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	message := "Test email sent successfully"
+	if !report.Success {
+		message = "Test email could not be sent"
+	}
+	helpers.SendSuccess(c, message, report)
+}
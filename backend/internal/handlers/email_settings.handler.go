@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// EmailSettingsHandler handles which system events send emails in the organization
+type EmailSettingsHandler struct {
+	emailSettingsService *services.EmailSettingsService
+	activityLogService   *services.ActivityLogService
+}
+
+// NewEmailSettingsHandler creates a new email settings handler instance
+func NewEmailSettingsHandler(emailSettingsService *services.EmailSettingsService, activityLogService *services.ActivityLogService) *EmailSettingsHandler {
+	return &EmailSettingsHandler{
+		emailSettingsService: emailSettingsService,
+		activityLogService:   activityLogService,
+	}
+}
+
+// GetEmailSettings returns the events sending emails in the organization
+// GET /api/admin/email-settings
+func (h *EmailSettingsHandler) GetEmailSettings(c *gin.Context) {
+	helpers.SendSuccess(c, "Email settings retrieved successfully", h.emailSettingsService.Get(c.Request.Context()))
+}
+
+// UpdateEmailSettings turns the emails of system events on or off
+// PUT /api/admin/email-settings
+func (h *EmailSettingsHandler) UpdateEmailSettings(c *gin.Context) {
+	var req models.UpdateEmailEventSettingsRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	settings, err := h.emailSettingsService.Update(ctx, &req, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidEmailEvent) {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       models.ActionConfigUpdated,
+		Description:  "Updated the email settings of system events",
+		ResourceType: "email_settings",
+		Success:      true,
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Email settings updated successfully", settings)
+}
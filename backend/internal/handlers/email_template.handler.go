@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/i18n"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// EmailTemplateHandler handles the email templates edited by the admins
+type EmailTemplateHandler struct {
+	emailTemplateService *services.EmailTemplateService
+	activityLogService   *services.ActivityLogService
+}
+
+// NewEmailTemplateHandler creates a new email template handler instance
+func NewEmailTemplateHandler(emailTemplateService *services.EmailTemplateService, activityLogService *services.ActivityLogService) *EmailTemplateHandler {
+	return &EmailTemplateHandler{
+		emailTemplateService: emailTemplateService,
+		activityLogService:   activityLogService,
+	}
+}
+
+// sendEmailTemplateError maps email template service errors to HTTP responses
+func sendEmailTemplateError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "email template not found":
+		helpers.SendNotFound(c, "Email template not found")
+	case err.Error() == "email template version not found":
+		helpers.SendNotFound(c, "Email template version not found")
+	case err.Error() == "email template is not customized":
+		helpers.SendBadRequest(c, "Email template already uses the built-in template")
+	case strings.HasPrefix(err.Error(), "email template was modified concurrently"):
+		helpers.SendConflict(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid email template"):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
+
+// emailTemplateLanguage reads the language of the template from the query,
+// the default language when it is omitted
+func emailTemplateLanguage(c *gin.Context) (string, bool) {
+	lang := c.DefaultQuery("language", i18n.DefaultLanguage)
+	if !i18n.IsSupported(lang) {
+		helpers.SendBadRequest(c, "Unsupported language, expected one of: "+strings.Join(i18n.SupportedLanguages, ", "))
+		return "", false
+	}
+	return lang, true
+}
+
+// GetEmailTemplates returns the template in use for every email kind and language
+// GET /api/admin/email-templates
+func (h *EmailTemplateHandler) GetEmailTemplates(c *gin.Context) {
+	templates, err := h.emailTemplateService.List(c.Request.Context())
+	if err != nil {
+		sendEmailTemplateError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Email templates retrieved successfully", templates)
+}
+
+// GetEmailTemplate returns the template in use for an email kind in a language
+// GET /api/admin/email-templates/:kind?language=fr|en
+func (h *EmailTemplateHandler) GetEmailTemplate(c *gin.Context) {
+	lang, ok := emailTemplateLanguage(c)
+	if !ok {
+		return
+	}
+
+	tmpl, err := h.emailTemplateService.Get(c.Request.Context(), c.Param("kind"), lang)
+	if err != nil {
+		sendEmailTemplateError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Email template retrieved successfully", tmpl)
+}
+
+// SaveEmailTemplate stores a new version of the template of an email kind in a language
+// PUT /api/admin/email-templates/:kind?language=fr|en
+func (h *EmailTemplateHandler) SaveEmailTemplate(c *gin.Context) {
+	lang, ok := emailTemplateLanguage(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveEmailTemplateRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	tmpl, err := h.emailTemplateService.Save(c.Request.Context(), c.Param("kind"), lang, &req, userID)
+	if err != nil {
+		sendEmailTemplateError(c, err)
+		return
+	}
+
+	h.logTemplateActivity(c, "email_template_updated", fmt.Sprintf("Updated %s email template (%s) to version %d", tmpl.Kind, tmpl.Language, tmpl.Version), tmpl.Kind, tmpl.Language, tmpl.Version)
+
+	helpers.SendSuccess(c, "Email template saved successfully", tmpl)
+}
+
+// ResetEmailTemplate restores the built-in template of an email kind in a language
+// DELETE /api/admin/email-templates/:kind?language=fr|en
+func (h *EmailTemplateHandler) ResetEmailTemplate(c *gin.Context) {
+	lang, ok := emailTemplateLanguage(c)
+	if !ok {
+		return
+	}
+
+	kind := c.Param("kind")
+	if err := h.emailTemplateService.Reset(c.Request.Context(), kind, lang); err != nil {
+		sendEmailTemplateError(c, err)
+		return
+	}
+
+	h.logTemplateActivity(c, "email_template_reset", fmt.Sprintf("Reset %s email template (%s) to the built-in template", kind, lang), kind, lang, 0)
+
+	helpers.SendSuccess(c, "Email template reset successfully", nil)
+}
+
+// GetEmailTemplateVersions returns the saved versions of the template of an email kind in a language
+// GET /api/admin/email-templates/:kind/versions?language=fr|en
+func (h *EmailTemplateHandler) GetEmailTemplateVersions(c *gin.Context) {
+	lang, ok := emailTemplateLanguage(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.emailTemplateService.Versions(c.Request.Context(), c.Param("kind"), lang)
+	if err != nil {
+		sendEmailTemplateError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Email template versions retrieved successfully", versions)
+}
+
+// RestoreEmailTemplateVersion makes a former version the template in use
+// POST /api/admin/email-templates/:kind/versions/:version/restore?language=fr|en
+func (h *EmailTemplateHandler) RestoreEmailTemplateVersion(c *gin.Context) {
+	lang, ok := emailTemplateLanguage(c)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		helpers.SendBadRequest(c, "Invalid email template version")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	tmpl, err := h.emailTemplateService.Restore(c.Request.Context(), c.Param("kind"), lang, version, userID)
+	if err != nil {
+		sendEmailTemplateError(c, err)
+		return
+	}
+
+	h.logTemplateActivity(c, "email_template_restored", fmt.Sprintf("Restored version %d of %s email template (%s)", version, tmpl.Kind, tmpl.Language), tmpl.Kind, tmpl.Language, tmpl.Version)
+
+	helpers.SendSuccess(c, "Email template version restored successfully", tmpl)
+}
+
+// PreviewEmailTemplate renders the template of an email kind in a language,
+// or the unsaved fields of the request, with sample data
+// POST /api/admin/email-templates/:kind/preview?language=fr|en
+func (h *EmailTemplateHandler) PreviewEmailTemplate(c *gin.Context) {
+	lang, ok := emailTemplateLanguage(c)
+	if !ok {
+		return
+	}
+
+	var req models.PreviewEmailTemplateRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	preview, err := h.emailTemplateService.Preview(c.Request.Context(), c.Param("kind"), lang, &req)
+	if err != nil {
+		sendEmailTemplateError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Email template rendered successfully", preview)
+}
+
+// logTemplateActivity records a change of an email template
+func (h *EmailTemplateHandler) logTemplateActivity(c *gin.Context, action models.ActivityAction, description, kind, lang string, version int) {
+	activityReq := models.ActivityLogRequest{
+		Action:       action,
+		Description:  description,
+		ResourceType: "email_template",
+		Success:      true,
+		Details: map[string]interface{}{
+			"kind":     kind,
+			"language": lang,
+			"version":  version,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(c.Request.Context(), activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportHandler handles the CSV and NDJSON exports of the user, document and
+// activity log lists. Exports are streamed to the response row by row, or to
+// MinIO in the background when async=true.
+type ExportHandler struct {
+	exportService      *services.ExportService
+	userService        *services.UserService
+	documentService    *services.DocumentService
+	activityLogService *services.ActivityLogService
+}
+
+// NewExportHandler creates a new export handler instance
+func NewExportHandler(exportService *services.ExportService, userService *services.UserService, documentService *services.DocumentService, activityLogService *services.ActivityLogService) *ExportHandler {
+	return &ExportHandler{
+		exportService:      exportService,
+		userService:        userService,
+		documentService:    documentService,
+		activityLogService: activityLogService,
+	}
+}
+
+// exportStreamFunc writes the rows of an export
+type exportStreamFunc func(ctx context.Context, w *helpers.ExportWriter) error
+
+var userExportHeader = []string{
+	"ID", "First Name", "Last Name", "Email", "Role", "Status", "Verified", "Department ID", "Last Login", "Created At",
+}
+
+// ExportUsers exports the users, optionally filtered by status, role and department
+// GET /api/users/export?format=csv|ndjson&async=true
+func (h *ExportHandler) ExportUsers(c *gin.Context) {
+	filter := bson.M{}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if role := c.Query("role"); role != "" {
+		filter["role"] = role
+	}
+	if departmentIDStr := c.Query("departmentId"); departmentIDStr != "" {
+		departmentID, err := primitive.ObjectIDFromHex(departmentIDStr)
+		if err != nil {
+			helpers.SendBadRequest(c, "Invalid department ID format")
+			return
+		}
+		filter["department_id"] = departmentID
+	}
+
+	h.export(c, models.ExportKindUsers, userExportHeader, func(ctx context.Context, w *helpers.ExportWriter) error {
+		return h.userService.StreamUsers(ctx, filter, func(user *models.User) error {
+			return w.Write(user.ToResponse(), []string{
+				user.ID.Hex(),
+				user.FirstName,
+				user.LastName,
+				user.Email,
+				string(user.Role),
+				string(user.Status),
+				strconv.FormatBool(user.Verified),
+				exportObjectID(user.DepartmentID),
+				exportTime(user.LastLogin),
+				user.CreatedAt.Format(time.RFC3339),
+			})
+		})
+	})
+}
+
+var documentExportHeader = []string{
+	"ID", "Reference", "Title", "Version", "Status", "Created By", "Created At", "Updated At", "Approved At", "Next Review Date",
+}
+
+// ExportDocuments exports the documents the user can access, optionally
+// filtered like the document list
+// GET /api/documents/export?format=csv|ndjson&async=true
+func (h *ExportHandler) ExportDocuments(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	var filter models.DocumentFilter
+	if status := c.Query("status"); status != "" {
+		docStatus := models.DocumentStatus(status)
+		filter.Status = &docStatus
+	}
+	if createdBy := c.Query("createdBy"); createdBy != "" {
+		if _, err := primitive.ObjectIDFromHex(createdBy); err != nil {
+			helpers.SendBadRequest(c, "invalid createdBy ID")
+			return
+		}
+		filter.CreatedBy = &createdBy
+	}
+	if search := c.Query("search"); search != "" {
+		filter.Search = &search
+	}
+
+	userID, userRole := user.ID, user.Role
+	h.export(c, models.ExportKindDocuments, documentExportHeader, func(ctx context.Context, w *helpers.ExportWriter) error {
+		return h.documentService.StreamUserAccessible(ctx, userID, userRole, &filter, func(document *models.Document) error {
+			return w.Write(document.ToResponse(), []string{
+				document.ID.Hex(),
+				document.Reference,
+				document.Title,
+				document.Version,
+				string(document.Status),
+				document.CreatedBy.Hex(),
+				document.CreatedAt.Format(time.RFC3339),
+				document.UpdatedAt.Format(time.RFC3339),
+				exportTime(document.ApprovedAt),
+				exportTime(document.NextReviewDate),
+			})
+		})
+	})
+}
+
+var activityLogExportHeader = []string{
+	"ID", "Timestamp", "Actor", "Actor Email", "Action", "Category", "Level", "Description", "Resource Type", "Resource ID", "Success", "IP Address",
+}
+
+// ExportActivityLogs exports the activity logs matching the list filters
+// GET /api/activity-logs/export?format=csv|ndjson&async=true
+func (h *ExportHandler) ExportActivityLogs(c *gin.Context) {
+	var filters models.ActivityLogFilters
+	parseActivityLogFilters(c, &filters)
+
+	h.export(c, models.ExportKindActivityLogs, activityLogExportHeader, func(ctx context.Context, w *helpers.ExportWriter) error {
+		return h.activityLogService.StreamActivityLogs(ctx, filters, func(activityLog *models.ActivityLog) error {
+			return w.Write(activityLog.ToResponse(), []string{
+				activityLog.ID.Hex(),
+				activityLog.Timestamp.Format(time.RFC3339),
+				activityLog.ActorName,
+				activityLog.ActorEmail,
+				string(activityLog.Action),
+				string(activityLog.Category),
+				string(activityLog.Level),
+				activityLog.Description,
+				activityLog.ResourceType,
+				exportObjectID(activityLog.ResourceID),
+				strconv.FormatBool(activityLog.Success),
+				activityLog.IPAddress,
+			})
+		})
+	})
+}
+
+// ListExports returns the latest background exports of the current user
+// GET /api/exports
+func (h *ExportHandler) ListExports(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	jobs, err := h.exportService.ListByUser(c.Request.Context(), userID, 20)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Exports retrieved successfully", jobs)
+}
+
+// GetExport returns the status of a background export, with its download URL once completed
+// GET /api/exports/:id
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid export ID format")
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	job, err := h.exportService.GetByID(c.Request.Context(), id, user.ID, user.Role)
+	if err != nil {
+		if err.Error() == "export job not found" {
+			helpers.SendNotFound(c, "Export not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Export retrieved successfully", job)
+}
+
+// export streams the rows of an export to the response, or starts a
+// background export to MinIO when async=true
+func (h *ExportHandler) export(c *gin.Context, kind models.ExportKind, header []string, stream exportStreamFunc) {
+	format, err := helpers.ParseExportFormat(c.Query("format"))
+	if err != nil {
+		helpers.SendBadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	write := func(ctx context.Context, out io.Writer) (int64, error) {
+		w := helpers.NewExportWriter(out, format, header)
+		if err := stream(ctx, w); err != nil {
+			return w.Rows(), err
+		}
+		return w.Rows(), w.Flush()
+	}
+
+	ctx := c.Request.Context()
+	if async, _ := strconv.ParseBool(c.Query("async")); async {
+		job, err := h.exportService.Start(ctx, kind, string(format), format.ContentType(), write, userID)
+		if err != nil {
+			helpers.SendInternalError(c, err)
+			return
+		}
+
+		h.logExport(c, kind, format, -1, true)
+		c.JSON(http.StatusAccepted, models.NewSuccessResponse("Export started", job))
+		return
+	}
+
+	fileName := fmt.Sprintf("%s_%s.%s", kind, time.Now().Format("20060102_150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Header("Content-Type", format.ContentType())
+	c.Status(http.StatusOK)
+
+	rows, err := write(ctx, c.Writer)
+	if err != nil {
+		// The status is already sent: the connection is aborted so that the
+		// client does not save the truncated file as a complete export
+		fmt.Printf("❌ [EXPORT] %s export failed after %d rows: %v\n", kind, rows, err)
+		panic(http.ErrAbortHandler)
+	}
+
+	h.logExport(c, kind, format, rows, false)
+}
+
+// logExport records an export in the activity logs
+func (h *ExportHandler) logExport(c *gin.Context, kind models.ExportKind, format helpers.ExportFormat, rows int64, async bool) {
+	details := map[string]interface{}{
+		"kind":   kind,
+		"format": format,
+		"async":  async,
+		"query":  c.Request.URL.RawQuery,
+	}
+	description := fmt.Sprintf("Started a background %s export", kind)
+	if !async {
+		details["rowCount"] = rows
+		description = fmt.Sprintf("Exported %d %s rows", rows, kind)
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       "data_exported",
+		Description:  description,
+		ResourceType: string(kind),
+		Success:      true,
+		Details:      details,
+	}
+	if logErr := h.activityLogService.LogActivity(c.Request.Context(), activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+}
+
+// exportObjectID formats an optional ID for an export column
+func exportObjectID(id *primitive.ObjectID) string {
+	if id == nil {
+		return ""
+	}
+	return id.Hex()
+}
+
+// exportTime formats an optional date for an export column
+func exportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FavoriteHandler handles the starred and recently viewed documents of users
+type FavoriteHandler struct {
+	favoriteService *services.FavoriteService
+}
+
+// NewFavoriteHandler creates a new favorite handler instance
+func NewFavoriteHandler(favoriteService *services.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{
+		favoriteService: favoriteService,
+	}
+}
+
+// ListFavorites returns the documents starred by the current user
+// GET /api/documents/favorites
+func (h *FavoriteHandler) ListFavorites(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	favorites, err := h.favoriteService.ListFavorites(c.Request.Context(), user.ID, user.Role)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	page, limit := helpers.GetPaginationParams(c)
+	total := len(favorites)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	helpers.SendSuccessWithPagination(c, "Favorite documents retrieved successfully", favorites[start:end], helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	})
+}
+
+// ListRecent returns the documents recently viewed by the current user
+// GET /api/documents/recent?limit=20
+func (h *FavoriteHandler) ListRecent(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if v, err := strconv.Atoi(limitStr); err == nil && v > 0 {
+			limit = min(v, 50)
+		}
+	}
+
+	recent, err := h.favoriteService.ListRecent(c.Request.Context(), user.ID, user.Role, limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Recently viewed documents retrieved successfully", recent)
+}
+
+// GetFavoriteStatus tells whether the current user starred a document
+// GET /api/documents/:id/favorite
+func (h *FavoriteHandler) GetFavoriteStatus(c *gin.Context) {
+	h.favoriteStatus(c, func(userID, documentID primitive.ObjectID) error { return nil })
+}
+
+// AddFavorite stars a document for the current user
+// POST /api/documents/:id/favorite
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	h.favoriteStatus(c, func(userID, documentID primitive.ObjectID) error {
+		return h.favoriteService.AddFavorite(c.Request.Context(), userID, documentID)
+	})
+}
+
+// RemoveFavorite unstars a document for the current user
+// DELETE /api/documents/:id/favorite
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	h.favoriteStatus(c, func(userID, documentID primitive.ObjectID) error {
+		return h.favoriteService.RemoveFavorite(c.Request.Context(), userID, documentID)
+	})
+}
+
+// favoriteStatus applies a change to the favorite of the document and
+// responds with its resulting status
+func (h *FavoriteHandler) favoriteStatus(c *gin.Context, change func(userID, documentID primitive.ObjectID) error) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	if err := change(userID, documentID); err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	isFavorite, err := h.favoriteService.IsFavorite(c.Request.Context(), userID, documentID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Favorite status retrieved successfully", models.FavoriteStatusResponse{
+		DocumentID: documentID.Hex(),
+		IsFavorite: isFavorite,
+	})
+}
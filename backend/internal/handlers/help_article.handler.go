@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/i18n"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HelpArticleHandler handles the contextual help content
+type HelpArticleHandler struct {
+	helpArticleService *services.HelpArticleService
+}
+
+// NewHelpArticleHandler creates a new help article handler instance
+func NewHelpArticleHandler(helpArticleService *services.HelpArticleService) *HelpArticleHandler {
+	return &HelpArticleHandler{
+		helpArticleService: helpArticleService,
+	}
+}
+
+// helpLocale returns the locale requested with ?locale=, or the language of the request
+func helpLocale(c *gin.Context) string {
+	if locale := c.Query("locale"); slices.Contains(models.HelpLocales, locale) {
+		return locale
+	}
+	return i18n.GetLanguageFromContext(c)
+}
+
+// GetArticles lists help articles. Users get the active articles of their
+// locale, admins get every locale unless one is requested.
+// GET /api/help-articles?locale=fr&search=&tag=&includeInactive=true
+func (h *HelpArticleHandler) GetArticles(c *gin.Context) {
+	page, limit := helpers.GetPaginationParams(c)
+	filter := &models.HelpArticleFilter{
+		Search: c.Query("search"),
+		Tag:    c.Query("tag"),
+		Page:   page,
+		Limit:  limit,
+	}
+
+	userRole, _ := middleware.GetCurrentUserRole(c)
+	if userRole == models.RoleAdmin {
+		filter.IncludeInactive = c.Query("includeInactive") == "true"
+		if c.Query("locale") != "" {
+			filter.Locale = helpLocale(c)
+		}
+	} else {
+		filter.Locale = helpLocale(c)
+	}
+
+	articles, total, err := h.helpArticleService.List(c.Request.Context(), filter)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Help articles retrieved successfully", articles, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
+
+// GetArticleByKey returns the article of a key in the request locale, or in
+// another locale when it has not been translated yet
+// GET /api/help-articles/key/:key?locale=fr
+func (h *HelpArticleHandler) GetArticleByKey(c *gin.Context) {
+	article, err := h.helpArticleService.GetByKey(c.Request.Context(), c.Param("key"), helpLocale(c))
+	if err != nil {
+		if err.Error() == "help article not found" {
+			helpers.SendNotFound(c, "Help article not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Help article retrieved successfully", article)
+}
+
+// LookupArticles resolves several keys at once, keyed by help key
+// GET /api/help-articles/lookup?keys=publishing_workflow,signature_pin&locale=fr
+func (h *HelpArticleHandler) LookupArticles(c *gin.Context) {
+	var keys []string
+	for _, key := range strings.Split(c.Query("keys"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		helpers.SendBadRequest(c, "At least one key is required")
+		return
+	}
+
+	articles, err := h.helpArticleService.Lookup(c.Request.Context(), keys, helpLocale(c))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "too many keys") {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Help articles retrieved successfully", articles)
+}
+
+// GetArticle returns a help article by ID
+// GET /api/help-articles/:id
+func (h *HelpArticleHandler) GetArticle(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid help article ID format")
+		return
+	}
+
+	article, err := h.helpArticleService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "help article not found" {
+			helpers.SendNotFound(c, "Help article not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Help article retrieved successfully", article)
+}
+
+// CreateArticle creates a help article
+// POST /api/help-articles
+func (h *HelpArticleHandler) CreateArticle(c *gin.Context) {
+	var req models.CreateHelpArticleRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	article, err := h.helpArticleService.Create(c.Request.Context(), &req, userID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "already exists"):
+			helpers.SendConflict(c, err.Error())
+		case strings.HasPrefix(err.Error(), "invalid"):
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	helpers.SendCreated(c, "Help article created successfully", article)
+}
+
+// UpdateArticle updates a help article
+// PUT /api/help-articles/:id
+func (h *HelpArticleHandler) UpdateArticle(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid help article ID format")
+		return
+	}
+
+	var req models.UpdateHelpArticleRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	article, err := h.helpArticleService.Update(c.Request.Context(), id, &req, userID)
+	if err != nil {
+		if err.Error() == "help article not found" {
+			helpers.SendNotFound(c, "Help article not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Help article updated successfully", article)
+}
+
+// DeleteArticle deletes a help article
+// DELETE /api/help-articles/:id
+func (h *HelpArticleHandler) DeleteArticle(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid help article ID format")
+		return
+	}
+
+	if err := h.helpArticleService.Delete(c.Request.Context(), id); err != nil {
+		if err.Error() == "help article not found" {
+			helpers.SendNotFound(c, "Help article not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Help article deleted successfully", nil)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImpactHandler handles impact analysis requests
+type ImpactHandler struct {
+	impactService *services.ImpactService
+}
+
+// NewImpactHandler creates a new impact handler instance
+func NewImpactHandler(impactService *services.ImpactService) *ImpactHandler {
+	return &ImpactHandler{
+		impactService: impactService,
+	}
+}
+
+// GetImpact lists documents, steps and contributor assignments referencing a
+// department or job position
+// GET /api/impact?departmentId=|jobPositionId=
+func (h *ImpactHandler) GetImpact(c *gin.Context) {
+	departmentID := c.Query("departmentId")
+	jobPositionID := c.Query("jobPositionId")
+	if (departmentID == "") == (jobPositionID == "") {
+		helpers.SendBadRequest(c, "Exactly one of departmentId or jobPositionId is required")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var result *models.ImpactAnalysisResponse
+	if departmentID != "" {
+		objID, err := primitive.ObjectIDFromHex(departmentID)
+		if err != nil {
+			helpers.SendBadRequest(c, "Invalid departmentId format")
+			return
+		}
+		result, err = h.impactService.AnalyzeDepartment(ctx, objID)
+		if err != nil {
+			sendImpactError(c, err)
+			return
+		}
+	} else {
+		objID, err := primitive.ObjectIDFromHex(jobPositionID)
+		if err != nil {
+			helpers.SendBadRequest(c, "Invalid jobPositionId format")
+			return
+		}
+		result, err = h.impactService.AnalyzeJobPosition(ctx, objID)
+		if err != nil {
+			sendImpactError(c, err)
+			return
+		}
+	}
+
+	helpers.SendSuccess(c, "Impact analysis completed successfully", result)
+}
+
+// sendImpactError maps impact service errors to HTTP responses
+func sendImpactError(c *gin.Context, err error) {
+	if strings.HasSuffix(err.Error(), "not found") {
+		helpers.SendNotFound(c, err.Error())
+		return
+	}
+	helpers.SendInternalError(c, err)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/i18n"
 	"github.com/kodesonik/process-manager/internal/middleware"
 	"github.com/kodesonik/process-manager/internal/models"
 	"github.com/kodesonik/process-manager/internal/services"
@@ -24,6 +26,7 @@ type InvitationHandler struct {
 	emailService         *services.EmailService
 	notificationService  *services.NotificationService
 	activityLogService   *services.ActivityLogService
+	asyncRunner          *services.AsyncRunner
 }
 
 func NewInvitationHandler(
@@ -31,6 +34,7 @@ func NewInvitationHandler(
 	emailService *services.EmailService,
 	notificationService *services.NotificationService,
 	activityLogService *services.ActivityLogService,
+	asyncRunner *services.AsyncRunner,
 ) *InvitationHandler {
 	return &InvitationHandler{
 		invitationCollection: db.Collection("invitations"),
@@ -39,6 +43,7 @@ func NewInvitationHandler(
 		emailService:         emailService,
 		notificationService:  notificationService,
 		activityLogService:   activityLogService,
+		asyncRunner:          asyncRunner,
 	}
 }
 
@@ -51,6 +56,19 @@ func generateInvitationToken() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
+// invitationTeamName returns the name of a team in the language of the invitation email
+func invitationTeamName(team models.ContributorTeam, lang string) string {
+	switch team {
+	case models.ContributorTeamAuthors:
+		return i18n.T(lang, "email.team.authors")
+	case models.ContributorTeamVerifiers:
+		return i18n.T(lang, "email.team.verifiers")
+	case models.ContributorTeamValidators:
+		return i18n.T(lang, "email.team.validators")
+	}
+	return string(team)
+}
+
 // getAllowedTeamsForStatus returns which teams can be invited based on document status
 func getAllowedTeamsForStatus(status models.DocumentStatus) []models.ContributorTeam {
 	switch status {
@@ -105,7 +123,7 @@ func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
 
 	// Check if document exists
 	var document models.Document
-	err = h.documentCollection.FindOne(ctx, bson.M{"_id": documentID}).Decode(&document)
+	err = h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			helpers.SendNotFound(c, "Document not found")
@@ -187,21 +205,16 @@ func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
 
 	// Prepare data for async operations
 	invitedUserName := req.InvitedEmail
+	lang := i18n.GetLanguageFromContext(c)
 	if invitedUserID != nil {
 		invitedUserName = invitedUser.FirstName + " " + invitedUser.LastName
+		lang = emailLanguage(c, &invitedUser)
 	}
 
-	teamName := string(req.Team)
-	if req.Team == models.ContributorTeamAuthors {
-		teamName = "Authors"
-	} else if req.Team == models.ContributorTeamVerifiers {
-		teamName = "Verifiers"
-	} else if req.Team == models.ContributorTeamValidators {
-		teamName = "Validators"
-	}
+	teamName := invitationTeamName(req.Team, lang)
 
 	// Send invitation email and push notification asynchronously (don't block response)
-	go func() {
+	h.asyncRunner.Go("invitation_notifications", func(ctx context.Context) error {
 		// Send invitation email
 		emailErr := h.emailService.SendInvitationEmail(
 			req.InvitedEmail,
@@ -211,6 +224,7 @@ func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
 			document.Reference,
 			teamName,
 			token,
+			lang,
 		)
 		if emailErr != nil {
 			fmt.Printf("Failed to send invitation email: %v\n", emailErr)
@@ -239,7 +253,8 @@ func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
 				fmt.Printf("Failed to send push notification: %v\n", notifErr)
 			}
 		}
-	}()
+		return nil
+	})
 
 	// Log activity (keep synchronous for now to ensure it's logged before response)
 	activityDescription := fmt.Sprintf("Invited %s to collaborate on document '%s' (%s) as %s",
@@ -360,7 +375,7 @@ func (h *InvitationHandler) ListInvitations(c *gin.Context) {
 
 		// Fetch document title
 		var doc models.Document
-		if err := h.documentCollection.FindOne(ctx, bson.M{"_id": inv.DocumentID}).Decode(&doc); err == nil {
+		if err := h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": inv.DocumentID})).Decode(&doc); err == nil {
 			response.DocumentTitle = doc.Title
 		}
 
@@ -443,7 +458,7 @@ func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
 
 	// Get document and update contributor status
 	var document models.Document
-	err = h.documentCollection.FindOne(ctx, bson.M{"_id": invitation.DocumentID}).Decode(&document)
+	err = h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": invitation.DocumentID})).Decode(&document)
 	if err != nil {
 		helpers.SendInternalError(c, err)
 		return
@@ -588,7 +603,7 @@ func (h *InvitationHandler) DeclineInvitation(c *gin.Context) {
 
 	// Get document details for activity log
 	var document models.Document
-	err = h.documentCollection.FindOne(ctx, bson.M{"_id": invitation.DocumentID}).Decode(&document)
+	err = h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": invitation.DocumentID})).Decode(&document)
 	if err != nil {
 		helpers.SendInternalError(c, err)
 		return
@@ -667,9 +682,9 @@ func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
 		return
 	}
 
-	// Only the inviter can resend
-	if invitation.InvitedBy != user.ID {
-		helpers.SendForbidden(c, "Only the inviter can resend this invitation", "FORBIDDEN")
+	// Only the inviter or an admin can resend
+	if invitation.InvitedBy != user.ID && user.Role != models.RoleAdmin {
+		helpers.SendForbidden(c, "Only the inviter or an admin can resend this invitation", "FORBIDDEN")
 		return
 	}
 
@@ -681,7 +696,7 @@ func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
 
 	// Get document details
 	var document models.Document
-	err = h.documentCollection.FindOne(ctx, bson.M{"_id": invitation.DocumentID}).Decode(&document)
+	err = h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": invitation.DocumentID})).Decode(&document)
 	if err != nil {
 		helpers.SendInternalError(c, err)
 		return
@@ -711,22 +726,17 @@ func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
 
 	// Resend email
 	invitedUserName := invitation.InvitedEmail
+	lang := i18n.GetLanguageFromContext(c)
 	if invitation.InvitedUserID != nil {
 		var invitedUser models.User
 		err = h.userCollection.FindOne(ctx, bson.M{"_id": invitation.InvitedUserID}).Decode(&invitedUser)
 		if err == nil {
 			invitedUserName = invitedUser.FirstName + " " + invitedUser.LastName
+			lang = emailLanguage(c, &invitedUser)
 		}
 	}
 
-	teamName := string(invitation.Team)
-	if invitation.Team == models.ContributorTeamAuthors {
-		teamName = "Authors"
-	} else if invitation.Team == models.ContributorTeamVerifiers {
-		teamName = "Verifiers"
-	} else if invitation.Team == models.ContributorTeamValidators {
-		teamName = "Validators"
-	}
+	teamName := invitationTeamName(invitation.Team, lang)
 
 	err = h.emailService.SendInvitationEmail(
 		invitation.InvitedEmail,
@@ -736,6 +746,7 @@ func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
 		document.Reference,
 		teamName,
 		token,
+		lang,
 	)
 	if err != nil {
 		fmt.Printf("Failed to resend invitation email: %v\n", err)
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JobHandler handles the background jobs queued by users, such as the PDF
+// generation of large documents
+type JobHandler struct {
+	jobQueueService    *services.JobQueueService
+	activityLogService *services.ActivityLogService
+}
+
+// NewJobHandler creates a new job handler instance
+func NewJobHandler(jobQueueService *services.JobQueueService, activityLogService *services.ActivityLogService) *JobHandler {
+	return &JobHandler{
+		jobQueueService:    jobQueueService,
+		activityLogService: activityLogService,
+	}
+}
+
+// QueueDocumentPDF queues the PDF generation of a document and returns the
+// job to poll, the user is notified when the PDF is ready
+// POST /api/documents/:id/export-pdf?watermark=true&archival=true&force=true&pageSize=A3&orientation=landscape
+func (h *JobHandler) QueueDocumentPDF(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	pageLayout, err := models.ParsePDFPageLayout(c.Query("pageSize"), c.Query("orientation"))
+	if err != nil {
+		helpers.SendBadRequest(c, err.Error())
+		return
+	}
+	opts := models.PDFExportOptions{
+		Watermark:  c.Query("watermark") == "true",
+		Archival:   c.Query("archival") == "true",
+		Force:      c.Query("force") == "true",
+		PageLayout: pageLayout,
+	}
+
+	ctx := c.Request.Context()
+
+	job, err := h.jobQueueService.EnqueueDocumentPDF(ctx, id, user.ID, opts)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_pdf_queued",
+		Description:  fmt.Sprintf("Queued the PDF generation of document %s", job.Reference),
+		ResourceType: "document",
+		ResourceID:   &id,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": id.Hex(),
+			"jobId":      job.ID,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	c.JSON(http.StatusAccepted, models.NewSuccessResponse("PDF generation queued", job))
+}
+
+// GetJob returns the progress of a background job
+// GET /api/jobs/:id
+func (h *JobHandler) GetJob(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	job, err := h.jobQueueService.Get(c.Request.Context(), c.Param("id"), user.ID, user.Role)
+	if err != nil {
+		if err == models.ErrJobNotFound {
+			helpers.SendNotFound(c, "Job not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Job retrieved successfully", job)
+}
+
+// RegeneratePDFs queues the regeneration of the stored PDFs of the documents
+// matching the status and last update filters, such as after a template change
+// POST /api/admin/documents/regenerate-pdfs
+func (h *JobHandler) RegeneratePDFs(c *gin.Context) {
+	var req models.RegeneratePDFsRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	batch, err := h.jobQueueService.EnqueuePDFRegeneration(ctx, &req, user.ID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid date range") || strings.HasPrefix(err.Error(), "too many documents") {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_pdfs_regeneration_queued",
+		Description:  fmt.Sprintf("Queued the PDF regeneration of %d document(s)", batch.Total),
+		ResourceType: "document",
+		Success:      true,
+		Details: map[string]interface{}{
+			"batchId":  batch.ID,
+			"total":    batch.Total,
+			"statuses": req.Statuses,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	c.JSON(http.StatusAccepted, models.NewSuccessResponse("PDF regeneration queued", batch))
+}
+
+// GetRegenerationBatch returns the outcome of each document of a PDF regeneration batch
+// GET /api/admin/documents/regenerate-pdfs/:id
+func (h *JobHandler) GetRegenerationBatch(c *gin.Context) {
+	batch, err := h.jobQueueService.GetBatch(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == models.ErrBatchNotFound {
+			helpers.SendNotFound(c, "Regeneration batch not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Regeneration batch retrieved successfully", batch)
+}
+
+// GetQueueStats returns the queued jobs count and the jobs being run
+// GET /api/admin/jobs
+func (h *JobHandler) GetQueueStats(c *gin.Context) {
+	stats, err := h.jobQueueService.Stats(c.Request.Context())
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Job queue retrieved successfully", stats)
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -27,8 +26,7 @@ func NewJobPositionHandler(db *services.DatabaseService) *JobPositionHandler {
 // GetJobPositions returns all job positions with optional filtering
 // GET /api/job-positions
 func (h *JobPositionHandler) GetJobPositions(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Build filter based on query parameters
 	filter := bson.M{}
@@ -95,8 +93,7 @@ func (h *JobPositionHandler) GetJobPosition(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	collection := h.db.Collection("job_positions")
 	var jobPosition models.JobPosition
@@ -118,8 +115,7 @@ func (h *JobPositionHandler) CreateJobPosition(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Verify department exists
 	departmentObjID, err := primitive.ObjectIDFromHex(req.DepartmentID)
@@ -191,8 +187,7 @@ func (h *JobPositionHandler) UpdateJobPosition(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	collection := h.db.Collection("job_positions")
 
@@ -290,8 +285,7 @@ func (h *JobPositionHandler) DeleteJobPosition(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	collection := h.db.Collection("job_positions")
 
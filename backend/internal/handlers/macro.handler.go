@@ -1,9 +1,8 @@
 package handlers
 
 import (
-	"context"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kodesonik/process-manager/internal/helpers"
@@ -28,8 +27,7 @@ func NewMacroHandler(macroService *services.MacroService) *MacroHandler {
 // GetMacros returns all macros with optional filtering and pagination
 // GET /api/macros?search=&page=1&limit=20
 func (h *MacroHandler) GetMacros(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Build filter from query parameters
 	filter := &models.MacroFilter{
@@ -108,8 +106,7 @@ func (h *MacroHandler) GetMacro(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	macro, err := h.macroService.GetMacroByID(ctx, objID)
 	if err != nil {
@@ -145,8 +142,7 @@ func (h *MacroHandler) CreateMacro(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Create macro
 	macro, err := h.macroService.CreateMacro(ctx, &req, userID)
@@ -174,8 +170,7 @@ func (h *MacroHandler) UpdateMacro(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Update macro
 	macro, err := h.macroService.UpdateMacro(ctx, objID, &req)
@@ -187,6 +182,88 @@ func (h *MacroHandler) UpdateMacro(c *gin.Context) {
 	helpers.SendSuccess(c, "Macro updated successfully", macro.ToResponse())
 }
 
+// SetWorkflow sets the signature workflow of the documents of a macro
+// PUT /api/macros/:id/workflow
+func (h *MacroHandler) SetWorkflow(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid macro ID format")
+		return
+	}
+
+	var req models.WorkflowDefinition
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	macro, err := h.macroService.SetWorkflow(c.Request.Context(), objID, &req)
+	if err != nil {
+		sendWorkflowError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Macro workflow updated successfully", macro.ToResponse())
+}
+
+// ClearWorkflow restores the default signature workflow of a macro
+// DELETE /api/macros/:id/workflow
+func (h *MacroHandler) ClearWorkflow(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid macro ID format")
+		return
+	}
+
+	macro, err := h.macroService.ClearWorkflow(c.Request.Context(), objID)
+	if err != nil {
+		sendWorkflowError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Macro workflow removed successfully", macro.ToResponse())
+}
+
+// SimulateWorkflow evaluates a proposed workflow and signature deadlines of a
+// macro against its documents in signature, reporting the documents which
+// would be stuck or change signers, without applying the configuration
+// POST /api/macros/:id/workflow/simulate
+func (h *MacroHandler) SimulateWorkflow(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid macro ID format")
+		return
+	}
+
+	var req models.WorkflowSimulationRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	result, err := h.macroService.SimulateWorkflow(c.Request.Context(), objID, &req)
+	if err != nil {
+		sendWorkflowError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Macro workflow simulated successfully", result)
+}
+
+// sendWorkflowError maps workflow errors to HTTP responses
+func sendWorkflowError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "macro not found":
+		helpers.SendNotFound(c, "Macro not found")
+	case err.Error() == "document not found":
+		helpers.SendNotFound(c, "Document not found")
+	case strings.HasPrefix(err.Error(), "workflow "):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
+
 // DeleteMacro deletes a macro by ID
 // DELETE /api/macros/:id
 func (h *MacroHandler) DeleteMacro(c *gin.Context) {
@@ -197,8 +274,7 @@ func (h *MacroHandler) DeleteMacro(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Delete macro
 	err = h.macroService.DeleteMacro(ctx, objID)
@@ -220,8 +296,7 @@ func (h *MacroHandler) GetMacroProcesses(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Verify macro exists
 	_, err = h.macroService.GetMacroByID(ctx, objID)
@@ -286,8 +361,7 @@ func (h *MacroHandler) ReorderProcesses(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	err = h.macroService.ReorderProcesses(ctx, objID, req.ProcessIDs)
 	if err != nil {
@@ -308,8 +382,7 @@ func (h *MacroHandler) ExportPDF(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second) // Longer timeout for PDF generation
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Export PDF
 	pdfURL, err := h.macroService.ExportPDF(ctx, objID)
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MetadataSectionHandler handles custom metadata section definitions
+type MetadataSectionHandler struct {
+	sectionService *services.MetadataSectionService
+}
+
+// NewMetadataSectionHandler creates a new metadata section handler instance
+func NewMetadataSectionHandler(sectionService *services.MetadataSectionService) *MetadataSectionHandler {
+	return &MetadataSectionHandler{
+		sectionService: sectionService,
+	}
+}
+
+// GetSections returns section definitions, optionally those applicable to a macro
+// GET /api/metadata-sections?macroId=
+func (h *MetadataSectionHandler) GetSections(c *gin.Context) {
+	var macroID *primitive.ObjectID
+	if macroIDStr := c.Query("macroId"); macroIDStr != "" {
+		objID, err := primitive.ObjectIDFromHex(macroIDStr)
+		if err != nil {
+			helpers.SendBadRequest(c, "Invalid macro ID format")
+			return
+		}
+		macroID = &objID
+	}
+
+	// Only admins see deactivated sections
+	userRole, _ := middleware.GetCurrentUserRole(c)
+	includeInactive := userRole == models.RoleAdmin && c.Query("includeInactive") == "true"
+
+	ctx := c.Request.Context()
+
+	sections, err := h.sectionService.List(ctx, macroID, includeInactive)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Metadata sections retrieved successfully", sections)
+}
+
+// CreateSection defines a new custom metadata section
+// POST /api/metadata-sections
+func (h *MetadataSectionHandler) CreateSection(c *gin.Context) {
+	var req models.CreateMetadataSectionRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	section, err := h.sectionService.Create(ctx, &req, userID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "already exists"):
+			helpers.SendConflict(c, err.Error())
+		case strings.HasPrefix(err.Error(), "invalid"), strings.HasPrefix(err.Error(), "table sections"):
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	helpers.SendCreated(c, "Metadata section created successfully", section)
+}
+
+// UpdateSection updates a custom metadata section
+// PUT /api/metadata-sections/:id
+func (h *MetadataSectionHandler) UpdateSection(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid section ID format")
+		return
+	}
+
+	var req models.UpdateMetadataSectionRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	section, err := h.sectionService.Update(ctx, id, &req)
+	if err != nil {
+		if err.Error() == "metadata section not found" {
+			helpers.SendNotFound(c, "Metadata section not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Metadata section updated successfully", section)
+}
+
+// DeleteSection deletes a custom metadata section
+// DELETE /api/metadata-sections/:id
+func (h *MetadataSectionHandler) DeleteSection(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid section ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if err := h.sectionService.Delete(ctx, id); err != nil {
+		if err.Error() == "metadata section not found" {
+			helpers.SendNotFound(c, "Metadata section not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Metadata section deleted successfully", nil)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// ModuleHandler handles module rollout configuration
+type ModuleHandler struct {
+	rolloutService *services.ModuleRolloutService
+}
+
+// NewModuleHandler creates a new module handler instance
+func NewModuleHandler(rolloutService *services.ModuleRolloutService) *ModuleHandler {
+	return &ModuleHandler{
+		rolloutService: rolloutService,
+	}
+}
+
+// GetMyModules returns the modules enabled for the current user's department
+// GET /api/modules/me
+func (h *ModuleHandler) GetMyModules(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	modules, err := h.rolloutService.GetEnabledModules(ctx, user)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Enabled modules retrieved successfully", gin.H{
+		"modules": modules,
+	})
+}
+
+// GetModuleAccess confirms that a module is enabled for the current user's
+// department, the module gate answering 403 otherwise
+// GET /api/feedback/access, /api/incidents/access, /api/checklist-runs/access
+func (h *ModuleHandler) GetModuleAccess(c *gin.Context) {
+	module, exists := middleware.GetCurrentModule(c)
+	if !exists {
+		helpers.SendBadRequest(c, "Unknown module")
+		return
+	}
+
+	helpers.SendSuccess(c, "Module is enabled", gin.H{
+		"module":  module,
+		"enabled": true,
+	})
+}
+
+// GetRollouts returns the rollout configuration of all modules
+// GET /api/modules
+func (h *ModuleHandler) GetRollouts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	rollouts, err := h.rolloutService.ListRollouts(ctx)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	responses := make([]models.ModuleRolloutResponse, len(rollouts))
+	for i, rollout := range rollouts {
+		responses[i] = rollout.ToResponse()
+	}
+
+	helpers.SendSuccess(c, "Module rollouts retrieved successfully", responses)
+}
+
+// UpdateRollout configures which departments have a module enabled
+// PUT /api/modules/:module
+func (h *ModuleHandler) UpdateRollout(c *gin.Context) {
+	module := models.ModuleKey(c.Param("module"))
+	if !models.IsValidModuleKey(module) {
+		helpers.SendBadRequest(c, "Unknown module")
+		return
+	}
+
+	var req models.UpdateModuleRolloutRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	rollout, err := h.rolloutService.UpdateRollout(ctx, module, &req, userID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid department ID") {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Module rollout updated successfully", rollout.ToResponse())
+}
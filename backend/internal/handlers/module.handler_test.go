@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestGatedModuleRouteRequiresDepartmentRollout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("pilot department", func(mt *mtest.T) {
+		pilot := primitive.NewObjectID()
+		other := primitive.NewObjectID()
+
+		ns := mt.DB.Name() + ".module_rollouts"
+		rollout := bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "module", Value: string(models.ModuleFeedback)},
+			{Key: "enabled_for_all", Value: false},
+			{Key: "department_ids", Value: bson.A{pilot}},
+		}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(), // Indexes
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, rollout),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, rollout),
+		)
+
+		rolloutService := services.NewModuleRolloutService(&services.DatabaseService{Client: mt.Client, Database: mt.DB})
+		handler := NewModuleHandler(rolloutService)
+		moduleMiddleware := middleware.NewModuleMiddleware(rolloutService)
+
+		request := func(departmentID primitive.ObjectID) *httptest.ResponseRecorder {
+			user := &models.User{ID: primitive.NewObjectID(), Role: models.RoleUser, Active: true, DepartmentID: &departmentID}
+			r := gin.New()
+			r.GET("/api/feedback/access", func(c *gin.Context) {
+				c.Set("user", user)
+				c.Next()
+			}, moduleMiddleware.RequireModule(models.ModuleFeedback), handler.GetModuleAccess)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/feedback/access", nil))
+			return w
+		}
+
+		if w := request(other); w.Code != http.StatusForbidden {
+			t.Fatalf("expected 403 for a department without the module, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := request(pilot); w.Code != http.StatusOK {
+			t.Fatalf("expected 200 for the pilot department, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
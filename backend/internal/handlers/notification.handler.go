@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"context"
+	"fmt"
 	"net"
 	"time"
 
@@ -18,21 +18,22 @@ type NotificationHandler struct {
 	userService         *services.UserService
 	notificationService *services.NotificationService
 	deviceService       *services.DeviceService
+	reactionService     *services.ReactionService
 }
 
 // NewNotificationHandler creates a new notification handler
-func NewNotificationHandler(userService *services.UserService, notificationService *services.NotificationService, deviceService *services.DeviceService) *NotificationHandler {
+func NewNotificationHandler(userService *services.UserService, notificationService *services.NotificationService, deviceService *services.DeviceService, reactionService *services.ReactionService) *NotificationHandler {
 	return &NotificationHandler{
 		userService:         userService,
 		notificationService: notificationService,
 		deviceService:       deviceService,
+		reactionService:     reactionService,
 	}
 }
 
 // RegisterDevice registers a device for push notifications
 func (h *NotificationHandler) RegisterDevice(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -56,13 +57,16 @@ func (h *NotificationHandler) RegisterDevice(c *gin.Context) {
 		return
 	}
 
+	if err := h.userService.MarkOnboardingStep(ctx, currentUser.ID, models.OnboardingPushEnabled); err != nil {
+		fmt.Printf("⚠️  Failed to mark onboarding step %s: %v\n", models.OnboardingPushEnabled, err)
+	}
+
 	helpers.SendSuccess(c, "Device registered successfully", device.ToResponse())
 }
 
 // UpdateDeviceToken updates the FCM token for a device
 func (h *NotificationHandler) UpdateDeviceToken(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -85,6 +89,10 @@ func (h *NotificationHandler) UpdateDeviceToken(c *gin.Context) {
 		return
 	}
 
+	if err := h.userService.MarkOnboardingStep(ctx, currentUser.ID, models.OnboardingPushEnabled); err != nil {
+		fmt.Printf("⚠️  Failed to mark onboarding step %s: %v\n", models.OnboardingPushEnabled, err)
+	}
+
 	helpers.SendSuccess(c, "Device token updated successfully", gin.H{
 		"deviceUuid": deviceUUID,
 	})
@@ -92,8 +100,7 @@ func (h *NotificationHandler) UpdateDeviceToken(c *gin.Context) {
 
 // GetUserDevices returns all registered devices for the current user
 func (h *NotificationHandler) GetUserDevices(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -122,8 +129,7 @@ func (h *NotificationHandler) GetUserDevices(c *gin.Context) {
 
 // DeregisterDevice removes a device from push notifications
 func (h *NotificationHandler) DeregisterDevice(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -147,8 +153,7 @@ func (h *NotificationHandler) DeregisterDevice(c *gin.Context) {
 
 // GetUserNotifications returns user's notifications
 func (h *NotificationHandler) GetUserNotifications(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -183,8 +188,7 @@ func (h *NotificationHandler) GetUserNotifications(c *gin.Context) {
 
 // MarkNotificationsAsRead marks notifications as read
 func (h *NotificationHandler) MarkNotificationsAsRead(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -222,8 +226,7 @@ func (h *NotificationHandler) MarkNotificationsAsRead(c *gin.Context) {
 
 // GetNotificationPreferences returns user's notification preferences
 func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -243,8 +246,7 @@ func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
 
 // UpdateNotificationPreferences updates user's notification preferences
 func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -260,6 +262,10 @@ func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
 
 	// Update preferences
 	updatedPrefs, err := h.notificationService.UpdateUserPreferences(ctx, currentUser.ID, &req)
+	if err == models.ErrInvalidEmailFrequency || err == models.ErrInvalidEmailEvent {
+		helpers.SendErrorWithCode(c, 400, err.Error())
+		return
+	}
 	if err != nil {
 		helpers.SendErrorWithCode(c, 500, "Failed to update preferences: "+err.Error())
 		return
@@ -270,8 +276,7 @@ func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
 
 // SendPushNotification sends a push notification to specific users (Admin only)
 func (h *NotificationHandler) SendPushNotification(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -284,6 +289,7 @@ func (h *NotificationHandler) SendPushNotification(c *gin.Context) {
 		helpers.SendValidationError(c, "Invalid input", err)
 		return
 	}
+	req.Announcement = true
 
 	// Send notification
 	summary, err := h.notificationService.SendNotification(ctx, &req, currentUser.ID)
@@ -295,10 +301,119 @@ func (h *NotificationHandler) SendPushNotification(c *gin.Context) {
 	helpers.SendSuccess(c, "Push notification sent successfully", summary)
 }
 
+// AddNotificationReaction reacts to an announcement the current user received
+// POST /api/notifications/:id/reactions
+func (h *NotificationHandler) AddNotificationReaction(c *gin.Context) {
+	var req models.ReactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.SendValidationError(c, "Invalid input", err)
+		return
+	}
+	h.setReaction(c, req.Type, true)
+}
+
+// RemoveNotificationReaction withdraws a reaction from an announcement
+// DELETE /api/notifications/:id/reactions/:type
+func (h *NotificationHandler) RemoveNotificationReaction(c *gin.Context) {
+	h.setReaction(c, models.ReactionType(c.Param("type")), false)
+}
+
+func (h *NotificationHandler) setReaction(c *gin.Context, reactionType models.ReactionType, add bool) {
+	ctx := c.Request.Context()
+
+	currentUser, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendErrorWithCode(c, 401, "User not authenticated")
+		return
+	}
+
+	notificationID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendErrorWithCode(c, 400, "Invalid notification ID")
+		return
+	}
+
+	if !models.IsValidReactionType(reactionType) {
+		helpers.SendErrorWithCode(c, 400, "Invalid reaction type")
+		return
+	}
+
+	// Reactions are shared across all copies of the announcement
+	broadcastID, err := h.notificationService.GetBroadcastID(ctx, currentUser.ID, notificationID)
+	if err != nil {
+		switch err {
+		case models.ErrNotificationNotFound:
+			helpers.SendErrorWithCode(c, 404, "Notification not found")
+		case models.ErrNotAnnouncement:
+			helpers.SendErrorWithCode(c, 400, "Only announcements accept reactions")
+		default:
+			helpers.SendErrorWithCode(c, 500, "Failed to get notification: "+err.Error())
+		}
+		return
+	}
+
+	if add {
+		err = h.reactionService.Add(ctx, models.ReactionTargetBroadcast, broadcastID, currentUser.ID, reactionType)
+	} else {
+		err = h.reactionService.Remove(ctx, models.ReactionTargetBroadcast, broadcastID, currentUser.ID, reactionType)
+	}
+	if err != nil {
+		helpers.SendErrorWithCode(c, 500, "Failed to update reaction: "+err.Error())
+		return
+	}
+
+	summary, err := h.reactionService.Summary(ctx, models.ReactionTargetBroadcast, broadcastID, currentUser.ID)
+	if err != nil {
+		helpers.SendErrorWithCode(c, 500, "Failed to get reactions: "+err.Error())
+		return
+	}
+
+	helpers.SendSuccess(c, "Reaction updated successfully", summary)
+}
+
+// GetBroadcastReactions returns the acknowledgment counts of an announcement (Admin only)
+// GET /api/notifications/admin/broadcasts/:broadcastId/reactions
+func (h *NotificationHandler) GetBroadcastReactions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	currentUser, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendErrorWithCode(c, 401, "User not authenticated")
+		return
+	}
+
+	broadcastID, err := primitive.ObjectIDFromHex(c.Param("broadcastId"))
+	if err != nil {
+		helpers.SendErrorWithCode(c, 400, "Invalid broadcast ID")
+		return
+	}
+
+	recipients, err := h.notificationService.CountBroadcastRecipients(ctx, broadcastID)
+	if err != nil {
+		helpers.SendErrorWithCode(c, 500, "Failed to get broadcast: "+err.Error())
+		return
+	}
+	if recipients == 0 {
+		helpers.SendErrorWithCode(c, 404, "Broadcast not found")
+		return
+	}
+
+	summary, err := h.reactionService.Summary(ctx, models.ReactionTargetBroadcast, broadcastID, currentUser.ID)
+	if err != nil {
+		helpers.SendErrorWithCode(c, 500, "Failed to get reactions: "+err.Error())
+		return
+	}
+
+	helpers.SendSuccess(c, "Broadcast reactions retrieved successfully", models.BroadcastReactionsResponse{
+		BroadcastID: broadcastID,
+		Recipients:  recipients,
+		Reactions:   summary,
+	})
+}
+
 // TestPushNotification sends a test push notification to current user
 func (h *NotificationHandler) TestPushNotification(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -332,8 +447,7 @@ func (h *NotificationHandler) TestPushNotification(c *gin.Context) {
 
 // GetNotificationStats returns notification statistics for the current user
 func (h *NotificationHandler) GetNotificationStats(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	currentUser, exists := middleware.GetCurrentUser(c)
 	if !exists {
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OffboardingHandler handles the offboarding reports of departing users
+type OffboardingHandler struct {
+	offboardingService *services.OffboardingService
+}
+
+// NewOffboardingHandler creates a new offboarding handler instance
+func NewOffboardingHandler(offboardingService *services.OffboardingService) *OffboardingHandler {
+	return &OffboardingHandler{
+		offboardingService: offboardingService,
+	}
+}
+
+// GetOffboardingReport lists the owned documents, pending signatures and open
+// invitations to hand over before a user leaves
+// GET /api/admin/offboarding/:userId
+func (h *OffboardingHandler) GetOffboardingReport(c *gin.Context) {
+	userID, err := primitive.ObjectIDFromHex(c.Param("userId"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid user ID format")
+		return
+	}
+
+	report, err := h.offboardingService.Report(c.Request.Context(), userID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			helpers.SendNotFound(c, "User not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Offboarding report retrieved successfully", report)
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PDFTemplateHandler handles the corporate layouts of the document and macro PDFs
+type PDFTemplateHandler struct {
+	pdfTemplateService *services.PDFTemplateService
+	pdfService         *services.PDFService
+	documentService    *services.DocumentService
+	macroService       *services.MacroService
+	activityLogService *services.ActivityLogService
+}
+
+// NewPDFTemplateHandler creates a new PDF template handler instance
+func NewPDFTemplateHandler(pdfTemplateService *services.PDFTemplateService, pdfService *services.PDFService, documentService *services.DocumentService, macroService *services.MacroService, activityLogService *services.ActivityLogService) *PDFTemplateHandler {
+	return &PDFTemplateHandler{
+		pdfTemplateService: pdfTemplateService,
+		pdfService:         pdfService,
+		documentService:    documentService,
+		macroService:       macroService,
+		activityLogService: activityLogService,
+	}
+}
+
+// sendPDFTemplateError maps PDF template service errors to HTTP responses
+func sendPDFTemplateError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "pdf template not found":
+		helpers.SendNotFound(c, "PDF template not found")
+	case err.Error() == "document not found":
+		helpers.SendNotFound(c, "Document not found")
+	case err.Error() == "macro not found":
+		helpers.SendNotFound(c, "Macro not found")
+	case strings.HasPrefix(err.Error(), "invalid pdf template"),
+		strings.HasPrefix(err.Error(), "invalid department ID"):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
+
+// GetPDFTemplates returns the PDF templates
+// GET /api/pdf-templates?kind=document|macro
+func (h *PDFTemplateHandler) GetPDFTemplates(c *gin.Context) {
+	templates, err := h.pdfTemplateService.List(c.Request.Context(), models.PDFTemplateKind(c.Query("kind")))
+	if err != nil {
+		sendPDFTemplateError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "PDF templates retrieved successfully", templates)
+}
+
+// GetPDFTemplate returns a specific PDF template
+// GET /api/pdf-templates/:id
+func (h *PDFTemplateHandler) GetPDFTemplate(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid PDF template ID format")
+		return
+	}
+
+	tmpl, err := h.pdfTemplateService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		sendPDFTemplateError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "PDF template retrieved successfully", tmpl)
+}
+
+// CreatePDFTemplate adds a PDF template
+// POST /api/pdf-templates
+func (h *PDFTemplateHandler) CreatePDFTemplate(c *gin.Context) {
+	var req models.CreatePDFTemplateRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tmpl, err := h.pdfTemplateService.Create(ctx, &req, userID)
+	if err != nil {
+		sendPDFTemplateError(c, err)
+		return
+	}
+
+	h.logTemplateActivity(c, "pdf_template_created", fmt.Sprintf("Created %s PDF template '%s'", tmpl.Kind, tmpl.Name), tmpl)
+
+	helpers.SendCreated(c, "PDF template created successfully", tmpl)
+}
+
+// UpdatePDFTemplate updates a PDF template, its source or selection
+// PUT /api/pdf-templates/:id
+func (h *PDFTemplateHandler) UpdatePDFTemplate(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid PDF template ID format")
+		return
+	}
+
+	var req models.UpdatePDFTemplateRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	tmpl, err := h.pdfTemplateService.Update(c.Request.Context(), id, &req, userID)
+	if err != nil {
+		sendPDFTemplateError(c, err)
+		return
+	}
+
+	h.logTemplateActivity(c, "pdf_template_updated", fmt.Sprintf("Updated %s PDF template '%s'", tmpl.Kind, tmpl.Name), tmpl)
+
+	helpers.SendSuccess(c, "PDF template updated successfully", tmpl)
+}
+
+// DeletePDFTemplate removes a PDF template
+// DELETE /api/pdf-templates/:id
+func (h *PDFTemplateHandler) DeletePDFTemplate(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid PDF template ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tmpl, err := h.pdfTemplateService.GetByID(ctx, id)
+	if err != nil {
+		sendPDFTemplateError(c, err)
+		return
+	}
+	if err := h.pdfTemplateService.Delete(ctx, id); err != nil {
+		sendPDFTemplateError(c, err)
+		return
+	}
+
+	h.logTemplateActivity(c, "pdf_template_deleted", fmt.Sprintf("Deleted %s PDF template '%s'", tmpl.Kind, tmpl.Name), tmpl)
+
+	helpers.SendSuccess(c, "PDF template deleted successfully", nil)
+}
+
+// PreviewPDFTemplate renders a saved template with a document or macro
+// GET /api/pdf-templates/:id/preview?documentId=|macroId=&format=html|pdf
+func (h *PDFTemplateHandler) PreviewPDFTemplate(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid PDF template ID format")
+		return
+	}
+
+	tmpl, err := h.pdfTemplateService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		sendPDFTemplateError(c, err)
+		return
+	}
+
+	h.preview(c, &models.PreviewPDFTemplateRequest{
+		Kind:       tmpl.Kind,
+		Source:     tmpl.Source,
+		DocumentID: c.Query("documentId"),
+		MacroID:    c.Query("macroId"),
+		Format:     c.Query("format"),
+	})
+}
+
+// PreviewPDFTemplateSource renders an unsaved template source, so layouts
+// can be checked while they are edited
+// POST /api/pdf-templates/preview
+func (h *PDFTemplateHandler) PreviewPDFTemplateSource(c *gin.Context) {
+	var req models.PreviewPDFTemplateRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	h.preview(c, &req)
+}
+
+// preview renders a template source with the document or macro of the request
+func (h *PDFTemplateHandler) preview(c *gin.Context, req *models.PreviewPDFTemplateRequest) {
+	ctx := c.Request.Context()
+
+	var document *models.Document
+	var macro *models.Macro
+	var processes []models.Document
+	switch req.Kind {
+	case models.PDFTemplateKindDocument:
+		id, err := primitive.ObjectIDFromHex(req.DocumentID)
+		if err != nil {
+			helpers.SendBadRequest(c, "A valid documentId is required to preview a document template")
+			return
+		}
+		if document, err = h.documentService.GetByID(ctx, id); err != nil {
+			sendPDFTemplateError(c, err)
+			return
+		}
+	case models.PDFTemplateKindMacro:
+		id, err := primitive.ObjectIDFromHex(req.MacroID)
+		if err != nil {
+			helpers.SendBadRequest(c, "A valid macroId is required to preview a macro template")
+			return
+		}
+		if macro, err = h.macroService.GetMacroByID(ctx, id); err != nil {
+			sendPDFTemplateError(c, err)
+			return
+		}
+		if processes, err = h.macroService.ActiveProcesses(ctx, id); err != nil {
+			sendPDFTemplateError(c, err)
+			return
+		}
+	}
+
+	asPDF := req.Format == "pdf"
+	content, err := h.pdfService.RenderTemplatePreview(ctx, req.Kind, req.Source, document, macro, processes, asPDF)
+	if err != nil {
+		sendPDFTemplateError(c, err)
+		return
+	}
+
+	if asPDF {
+		c.Header("Content-Disposition", `inline; filename="preview.pdf"`)
+		c.Data(http.StatusOK, "application/pdf", content)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", content)
+}
+
+// logTemplateActivity records a change of a PDF template
+func (h *PDFTemplateHandler) logTemplateActivity(c *gin.Context, action models.ActivityAction, description string, tmpl *models.PDFTemplate) {
+	departmentIDs := make([]string, 0, len(tmpl.DepartmentIDs))
+	for _, id := range tmpl.DepartmentIDs {
+		departmentIDs = append(departmentIDs, id.Hex())
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       action,
+		Description:  description,
+		ResourceType: "pdf_template",
+		ResourceID:   &tmpl.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"templateId":    tmpl.ID.Hex(),
+			"kind":          string(tmpl.Kind),
+			"isDefault":     tmpl.IsDefault,
+			"departmentIds": departmentIDs,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(c.Request.Context(), activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// PerfHandler exposes the latency profiling data
+type PerfHandler struct {
+	perfService *services.PerfService
+	asyncRunner *services.AsyncRunner
+	pdfService  *services.PDFService
+}
+
+// NewPerfHandler creates a new profiling handler instance
+func NewPerfHandler(perfService *services.PerfService, asyncRunner *services.AsyncRunner, pdfService *services.PDFService) *PerfHandler {
+	return &PerfHandler{
+		perfService: perfService,
+		asyncRunner: asyncRunner,
+		pdfService:  pdfService,
+	}
+}
+
+// GetReport returns endpoint latency percentiles and the slow query log
+// GET /api/admin/perf
+func (h *PerfHandler) GetReport(c *gin.Context) {
+	helpers.SendSuccess(c, "Performance report retrieved successfully", h.perfService.Report())
+}
+
+// ResetStats clears the collected data to start a new measurement window
+// DELETE /api/admin/perf
+func (h *PerfHandler) ResetStats(c *gin.Context) {
+	h.perfService.Reset()
+	helpers.SendSuccess(c, "Performance statistics reset successfully", nil)
+}
+
+// GetAsyncStats returns the in-flight, queued and finished background tasks of the handlers
+// GET /api/admin/perf/async
+func (h *PerfHandler) GetAsyncStats(c *gin.Context) {
+	helpers.SendSuccess(c, "Background task statistics retrieved successfully", h.asyncRunner.Stats())
+}
+
+// GetPDFRenderStats returns the queue depth and the render durations of the PDFs
+// GET /api/admin/perf/pdf
+func (h *PerfHandler) GetPDFRenderStats(c *gin.Context) {
+	helpers.SendSuccess(c, "PDF render statistics retrieved successfully", h.pdfService.RenderStats())
+}
@@ -47,7 +47,7 @@ func (h *PermissionHandler) GetDocumentPermissions(c *gin.Context) {
 
 	// Check if document exists
 	var document models.Document
-	err = h.documentCollection.FindOne(ctx, bson.M{"_id": documentID}).Decode(&document)
+	err = h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			helpers.SendNotFound(c, "Document not found")
@@ -148,7 +148,7 @@ func (h *PermissionHandler) AddDocumentPermission(c *gin.Context) {
 
 	// Check if document exists
 	var document models.Document
-	err = h.documentCollection.FindOne(ctx, bson.M{"_id": documentID}).Decode(&document)
+	err = h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			helpers.SendNotFound(c, "Document not found")
@@ -262,7 +262,7 @@ func (h *PermissionHandler) UpdateDocumentPermission(c *gin.Context) {
 
 	// Check if document exists
 	var document models.Document
-	err = h.documentCollection.FindOne(ctx, bson.M{"_id": documentID}).Decode(&document)
+	err = h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			helpers.SendNotFound(c, "Document not found")
@@ -350,7 +350,7 @@ func (h *PermissionHandler) DeleteDocumentPermission(c *gin.Context) {
 
 	// Check if document exists
 	var document models.Document
-	err = h.documentCollection.FindOne(ctx, bson.M{"_id": documentID}).Decode(&document)
+	err = h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			helpers.SendNotFound(c, "Document not found")
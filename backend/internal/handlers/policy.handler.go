@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PolicyHandler handles policy documents and their acceptance
+type PolicyHandler struct {
+	policyService      *services.PolicyService
+	activityLogService *services.ActivityLogService
+}
+
+// NewPolicyHandler creates a new policy handler instance
+func NewPolicyHandler(policyService *services.PolicyService, activityLogService *services.ActivityLogService) *PolicyHandler {
+	return &PolicyHandler{
+		policyService:      policyService,
+		activityLogService: activityLogService,
+	}
+}
+
+// GetCurrentPolicies returns the published version of each policy
+// GET /api/policies/current
+func (h *PolicyHandler) GetCurrentPolicies(c *gin.Context) {
+	policies, err := h.policyService.GetCurrent(c.Request.Context())
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Policies retrieved successfully", policies)
+}
+
+// GetPendingPolicies returns the published policies the current user still has to accept
+// GET /api/policies/pending
+func (h *PolicyHandler) GetPendingPolicies(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	pending, err := h.policyService.PendingForUser(c.Request.Context(), user)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Pending policies retrieved successfully", gin.H{
+		"policyAcceptanceRequired": len(pending) > 0,
+		"policies":                 pending,
+	})
+}
+
+// AcceptPolicies records the acceptance of published policies by the current user
+// POST /api/policies/accept
+func (h *PolicyHandler) AcceptPolicies(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	var req models.AcceptPoliciesRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	policyIDs := make([]primitive.ObjectID, 0, len(req.PolicyIDs))
+	for _, idStr := range req.PolicyIDs {
+		id, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			helpers.SendBadRequest(c, "Invalid policy ID format")
+			return
+		}
+		policyIDs = append(policyIDs, id)
+	}
+
+	ctx := c.Request.Context()
+
+	accepted, err := h.policyService.Accept(ctx, user, policyIDs, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		if strings.Contains(err.Error(), "is not a published policy") {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	// Log activity
+	for _, policy := range accepted {
+		activityReq := models.ActivityLogRequest{
+			Action:       models.ActionPolicyAccepted,
+			Description:  fmt.Sprintf("Accepted %s version %s", policy.Title, policy.Version),
+			ResourceType: "policy",
+			ResourceID:   &policy.ID,
+			Success:      true,
+			Details: map[string]interface{}{
+				"type":    string(policy.Type),
+				"version": policy.Version,
+			},
+		}
+		if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+			fmt.Printf("Failed to log activity: %v\n", logErr)
+		}
+	}
+
+	helpers.SendSuccess(c, "Policies accepted successfully", accepted)
+}
+
+// ListPolicies lists every version of the policies
+// GET /api/policies?type=terms_of_service
+func (h *PolicyHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.policyService.List(c.Request.Context(), c.Query("type"))
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Policies retrieved successfully", policies)
+}
+
+// GetPolicy returns a policy version
+// GET /api/policies/:id
+func (h *PolicyHandler) GetPolicy(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid policy ID format")
+		return
+	}
+
+	policy, err := h.policyService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		sendPolicyError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Policy retrieved successfully", policy)
+}
+
+// CreatePolicy drafts a new policy version
+// POST /api/policies
+func (h *PolicyHandler) CreatePolicy(c *gin.Context) {
+	var req models.CreatePolicyRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	policy, err := h.policyService.Create(c.Request.Context(), &req, userID)
+	if err != nil {
+		sendPolicyError(c, err)
+		return
+	}
+
+	helpers.SendCreated(c, "Policy created successfully", policy)
+}
+
+// UpdatePolicy updates a draft policy version
+// PUT /api/policies/:id
+func (h *PolicyHandler) UpdatePolicy(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid policy ID format")
+		return
+	}
+
+	var req models.UpdatePolicyRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	policy, err := h.policyService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		sendPolicyError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Policy updated successfully", policy)
+}
+
+// DeletePolicy deletes a draft policy version
+// DELETE /api/policies/:id
+func (h *PolicyHandler) DeletePolicy(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid policy ID format")
+		return
+	}
+
+	if err := h.policyService.Delete(c.Request.Context(), id); err != nil {
+		sendPolicyError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Policy deleted successfully", nil)
+}
+
+// PublishPolicy publishes a draft, every user has to accept it
+// POST /api/policies/:id/publish
+func (h *PolicyHandler) PublishPolicy(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid policy ID format")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	policy, err := h.policyService.Publish(ctx, id, userID)
+	if err != nil {
+		sendPolicyError(c, err)
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       models.ActionPolicyPublished,
+		Description:  fmt.Sprintf("Published %s version %s", policy.Title, policy.Version),
+		ResourceType: "policy",
+		ResourceID:   &policy.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"type":    string(policy.Type),
+			"version": policy.Version,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Policy published successfully", policy)
+}
+
+// GetPolicyStats returns the acceptance completion rate of the published policies
+// GET /api/policies/stats
+func (h *PolicyHandler) GetPolicyStats(c *gin.Context) {
+	stats, err := h.policyService.GetStats(c.Request.Context())
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Policy acceptance statistics retrieved successfully", stats)
+}
+
+// ListPolicyAcceptances lists who accepted a policy version
+// GET /api/policies/:id/acceptances
+func (h *PolicyHandler) ListPolicyAcceptances(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid policy ID format")
+		return
+	}
+
+	page, limit := helpers.GetPaginationParams(c)
+
+	acceptances, total, err := h.policyService.ListAcceptances(c.Request.Context(), id, page, limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Policy acceptances retrieved successfully", acceptances, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
+
+// sendPolicyError maps policy errors to HTTP responses
+func sendPolicyError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "policy not found":
+		helpers.SendNotFound(c, "Policy not found")
+	case strings.Contains(err.Error(), "already exists"):
+		helpers.SendConflict(c, err.Error())
+	case strings.HasPrefix(err.Error(), "only draft policies"):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PresenceHandler handles the users who currently have a document open
+type PresenceHandler struct {
+	presenceService *services.PresenceService
+}
+
+// NewPresenceHandler creates a new presence handler instance
+func NewPresenceHandler(presenceService *services.PresenceService) *PresenceHandler {
+	return &PresenceHandler{
+		presenceService: presenceService,
+	}
+}
+
+// GetPresence lists the users who currently have a document open
+// GET /api/documents/:id/presence
+func (h *PresenceHandler) GetPresence(c *gin.Context) {
+	h.presence(c, func(userID, documentID primitive.ObjectID) error { return nil })
+}
+
+// Heartbeat tells that the current user still has a document open, sent by
+// the clients at the returned heartbeat interval
+// POST /api/documents/:id/presence
+func (h *PresenceHandler) Heartbeat(c *gin.Context) {
+	var req models.PresenceHeartbeatRequest
+	if c.Request.ContentLength > 0 {
+		if err := helpers.BindAndValidate(c, &req); err != nil {
+			helpers.SendValidationErrors(c, err)
+			return
+		}
+	}
+
+	h.presence(c, func(userID, documentID primitive.ObjectID) error {
+		return h.presenceService.Heartbeat(c.Request.Context(), documentID, userID, req.Mode)
+	})
+}
+
+// LeaveDocument tells that the current user closed a document
+// DELETE /api/documents/:id/presence
+func (h *PresenceHandler) LeaveDocument(c *gin.Context) {
+	h.presence(c, func(userID, documentID primitive.ObjectID) error {
+		return h.presenceService.Leave(c.Request.Context(), documentID, userID)
+	})
+}
+
+// presence applies a change to the presence of the current user and
+// responds with the users present on the document
+func (h *PresenceHandler) presence(c *gin.Context, change func(userID, documentID primitive.ObjectID) error) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	if err := change(userID, documentID); err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	presence, err := h.presenceService.List(c.Request.Context(), documentID, userID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Presence retrieved successfully", presence)
+}
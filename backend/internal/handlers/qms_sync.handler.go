@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QMSSyncHandler handles the connector to the external quality-management system
+type QMSSyncHandler struct {
+	qmsSyncService     *services.QMSSyncService
+	activityLogService *services.ActivityLogService
+}
+
+// NewQMSSyncHandler creates a new QMS sync handler instance
+func NewQMSSyncHandler(qmsSyncService *services.QMSSyncService, activityLogService *services.ActivityLogService) *QMSSyncHandler {
+	return &QMSSyncHandler{
+		qmsSyncService:     qmsSyncService,
+		activityLogService: activityLogService,
+	}
+}
+
+// GetConnector returns the QMS connector settings
+// GET /api/integrations/qms
+func (h *QMSSyncHandler) GetConnector(c *gin.Context) {
+	connector, err := h.qmsSyncService.GetConnector(c.Request.Context())
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "QMS connector retrieved successfully", connector)
+}
+
+// UpdateConnector updates the QMS connector settings
+// PUT /api/integrations/qms
+func (h *QMSSyncHandler) UpdateConnector(c *gin.Context) {
+	var req models.UpdateQMSConnectorRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+	connector, err := h.qmsSyncService.UpdateConnector(ctx, &req, userID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid QMS field mapping") ||
+			strings.HasPrefix(err.Error(), "an endpoint is required") {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "qms_connector_updated",
+		Description:  "Updated the QMS connector settings",
+		ResourceType: "settings",
+		Success:      true,
+		Details: map[string]interface{}{
+			"enabled":  connector.Enabled,
+			"endpoint": connector.Endpoint,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "QMS connector updated successfully", connector)
+}
+
+// ListSyncs returns the sync status of documents, optionally filtered by status
+// GET /api/integrations/qms/syncs
+func (h *QMSSyncHandler) ListSyncs(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" && !models.IsValidQMSSyncStatus(status) {
+		helpers.SendBadRequest(c, "Invalid sync status: "+status)
+		return
+	}
+
+	page, limit := helpers.GetPaginationParams(c)
+	syncs, total, err := h.qmsSyncService.List(c.Request.Context(), status, page, limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "QMS syncs retrieved successfully", syncs, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	})
+}
+
+// GetDocumentSync returns the QMS sync status of a document
+// GET /api/documents/:id/qms-sync
+func (h *QMSSyncHandler) GetDocumentSync(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	sync, err := h.qmsSyncService.GetByDocument(c.Request.Context(), documentID)
+	if err != nil {
+		if err.Error() == "document has not been synced" {
+			helpers.SendNotFound(c, "Document has not been synced")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "QMS sync retrieved successfully", sync)
+}
+
+// SyncDocument queues a published document for an immediate QMS sync,
+// resetting the attempts of a failed sync
+// POST /api/documents/:id/qms-sync
+func (h *QMSSyncHandler) SyncDocument(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	ctx := c.Request.Context()
+	sync, err := h.qmsSyncService.Sync(ctx, documentID)
+	if err != nil {
+		switch err.Error() {
+		case "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case "QMS connector is disabled", "only published documents can be synced":
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_qms_sync_requested",
+		Description:  fmt.Sprintf("Requested the QMS sync of document %s", sync.Reference),
+		ResourceType: "document",
+		ResourceID:   &documentID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": documentID.Hex(),
+			"reference":  sync.Reference,
+			"version":    sync.Version,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Document queued for QMS sync", sync)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReferenceHandler handles the document reference scheme
+type ReferenceHandler struct {
+	referenceService *services.ReferenceService
+}
+
+// NewReferenceHandler creates a new reference handler instance
+func NewReferenceHandler(referenceService *services.ReferenceService) *ReferenceHandler {
+	return &ReferenceHandler{
+		referenceService: referenceService,
+	}
+}
+
+// PreviewReference returns the reference a new document of the macro would receive
+// POST /api/documents/preview-reference
+func (h *ReferenceHandler) PreviewReference(c *gin.Context) {
+	var req models.PreviewReferenceRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	macroID, err := primitive.ObjectIDFromHex(req.MacroID)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid macro ID format")
+		return
+	}
+
+	var departmentID *primitive.ObjectID
+	if req.DepartmentID != "" {
+		objID, err := primitive.ObjectIDFromHex(req.DepartmentID)
+		if err != nil {
+			helpers.SendBadRequest(c, "Invalid department ID format")
+			return
+		}
+		departmentID = &objID
+	}
+
+	preview, err := h.referenceService.Preview(c.Request.Context(), macroID, departmentID, userID)
+	if err != nil {
+		switch {
+		case err.Error() == "department not found":
+			helpers.SendNotFound(c, "Department not found")
+		case strings.HasSuffix(err.Error(), "macro not found"):
+			helpers.SendNotFound(c, "Macro not found")
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	helpers.SendSuccess(c, "Reference preview generated successfully", preview)
+}
+
+// GetReferenceScheme returns the document reference scheme
+// GET /api/documents/reference-scheme
+func (h *ReferenceHandler) GetReferenceScheme(c *gin.Context) {
+	scheme, err := h.referenceService.GetScheme(c.Request.Context())
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Reference scheme retrieved successfully", scheme)
+}
+
+// UpdateReferenceScheme updates the document reference scheme
+// PUT /api/documents/reference-scheme
+func (h *ReferenceHandler) UpdateReferenceScheme(c *gin.Context) {
+	var req models.UpdateReferenceSchemeRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	scheme, err := h.referenceService.UpdateScheme(c.Request.Context(), &req, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReferencePattern) {
+			helpers.SendBadRequest(c, err.Error())
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Reference scheme updated successfully", scheme)
+}
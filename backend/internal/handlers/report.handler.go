@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// maxReportPeriod bounds the period covered by one register export
+const maxReportPeriod = 366 * 24 * time.Hour
+
+// ReportHandler handles the registers exported for quality reviews
+type ReportHandler struct {
+	reportService *services.ReportService
+}
+
+// NewReportHandler creates a new report handler instance
+func NewReportHandler(reportService *services.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// ExportSignatures downloads the register of the signatures made during a period.
+// Dates are YYYY-MM-DD, both included, and default to the current month.
+// GET /api/reports/signatures?from=2025-01-01&to=2025-01-31&format=xlsx|csv
+func (h *ReportHandler) ExportSignatures(c *gin.Context) {
+	format := c.DefaultQuery("format", "xlsx")
+	if format != "xlsx" && format != "csv" {
+		helpers.SendBadRequest(c, "Invalid format: must be xlsx or csv")
+		return
+	}
+
+	from, to, err := parseReportPeriod(c.Query("from"), c.Query("to"))
+	if err != nil {
+		helpers.SendBadRequest(c, err.Error())
+		return
+	}
+
+	entries, err := h.reportService.SignatureRegister(c.Request.Context(), from, to)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	var (
+		data        []byte
+		contentType string
+	)
+	rows := signatureRegisterRows(entries)
+	switch format {
+	case "csv":
+		data, err = writeCSV(signatureRegisterHeader, rows)
+		contentType = "text/csv; charset=utf-8"
+	default:
+		data, err = helpers.WriteXLSX("Signatures", signatureRegisterHeader, rows)
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	fileName := fmt.Sprintf("signatures_%s_%s.%s", from.Format("20060102"), to.Add(-time.Second).Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// parseReportPeriod converts inclusive YYYY-MM-DD dates into a [from, to) range
+func parseReportPeriod(fromStr, toStr string) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 1, 0)
+
+	if fromStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", fromStr, now.Location())
+		if err != nil {
+			return from, to, fmt.Errorf("invalid from date: use YYYY-MM-DD")
+		}
+		from = parsed
+	}
+	if toStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", toStr, now.Location())
+		if err != nil {
+			return from, to, fmt.Errorf("invalid to date: use YYYY-MM-DD")
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from date must not be after to date")
+	}
+	if to.Sub(from) > maxReportPeriod {
+		return from, to, fmt.Errorf("period must not exceed one year")
+	}
+	return from, to, nil
+}
+
+var signatureRegisterHeader = []string{
+	"Signed At", "Reference", "Document", "Version", "Signer", "Email", "Role", "Method", "IP Address",
+}
+
+// signatureRegisterRows flattens the signatures register into spreadsheet rows
+func signatureRegisterRows(entries []models.SignatureRegisterEntry) [][]string {
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, []string{
+			entry.SignedAt.Format(time.RFC3339),
+			entry.DocumentReference,
+			entry.DocumentTitle,
+			entry.Version,
+			entry.SignerName,
+			entry.SignerEmail,
+			string(entry.Role),
+			entry.Method,
+			entry.IPAddress,
+		})
+	}
+	return rows
+}
+
+// writeCSV builds a CSV file with a header row
+func writeCSV(header []string, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewHandler handles periodic document review requests
+type ReviewHandler struct {
+	reviewService      *services.ReviewService
+	documentService    *services.DocumentService
+	activityLogService *services.ActivityLogService
+}
+
+// NewReviewHandler creates a new review handler instance
+func NewReviewHandler(reviewService *services.ReviewService, documentService *services.DocumentService, activityLogService *services.ActivityLogService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService:      reviewService,
+		documentService:    documentService,
+		activityLogService: activityLogService,
+	}
+}
+
+// ListUpcomingReviews lists the published documents due for review soon, overdue ones first
+// GET /api/documents/reviews?days=30
+func (h *ReviewHandler) ListUpcomingReviews(c *gin.Context) {
+	days := 30
+	if daysStr := c.Query("days"); daysStr != "" {
+		if d, err := strconv.Atoi(daysStr); err == nil && d >= 0 && d <= 365 {
+			days = d
+		}
+	}
+
+	documents, err := h.reviewService.ListUpcoming(c.Request.Context(), days)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	responses := make([]models.DocumentResponse, 0, len(documents))
+	for _, doc := range documents {
+		responses = append(responses, doc.ToResponse())
+	}
+
+	helpers.SendSuccess(c, "Upcoming reviews retrieved successfully", responses)
+}
+
+// CompleteReview confirms that a published document is still valid and
+// schedules its next review. Allowed to managers and the document authors.
+// POST /api/documents/:id/review
+func (h *ReviewHandler) CompleteReview(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.documentService.GetByID(ctx, documentID)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	if user.Role != models.RoleAdmin && user.Role != models.RoleManager && !isDocumentAuthor(document, user.ID) {
+		helpers.SendForbidden(c, "Only managers and the document authors can complete a review", models.CodeForbidden)
+		return
+	}
+
+	document, err = h.reviewService.CompleteReview(ctx, documentID, user.ID)
+	if err != nil {
+		switch {
+		case err.Error() == "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case strings.HasPrefix(err.Error(), "only published documents"):
+			helpers.SendBadRequest(c, err.Error())
+		case err.Error() == "document status changed, please retry":
+			helpers.SendConflict(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	// Log activity
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_reviewed",
+		Description:  fmt.Sprintf("Completed periodic review of document '%s' (%s)", document.Title, document.Reference),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId":     document.ID.Hex(),
+			"reference":      document.Reference,
+			"version":        document.Version,
+			"nextReviewDate": document.NextReviewDate,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Document review completed successfully", document.ToResponse())
+}
+
+// isDocumentAuthor reports whether the user created or co-authored the document
+func isDocumentAuthor(document *models.Document, userID primitive.ObjectID) bool {
+	if document.CreatedBy == userID {
+		return true
+	}
+	for _, author := range document.Contributors.Authors {
+		if author.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SearchHandler handles cross-document search requests
+type SearchHandler struct {
+	documentService  *services.DocumentService
+	actorService     *services.ActorService
+	analyticsService *services.AnalyticsService
+}
+
+// NewSearchHandler creates a new search handler instance
+func NewSearchHandler(documentService *services.DocumentService, actorService *services.ActorService, analyticsService *services.AnalyticsService) *SearchHandler {
+	return &SearchHandler{
+		documentService:  documentService,
+		actorService:     actorService,
+		analyticsService: analyticsService,
+	}
+}
+
+// SearchByActor returns every step of published documents where an actor or
+// job position is responsible
+// GET /api/search/by-actor?actor=&jobPositionId=
+func (h *SearchHandler) SearchByActor(c *gin.Context) {
+	actor := c.Query("actor")
+
+	var jobPositionID *primitive.ObjectID
+	if jobPositionIDStr := c.Query("jobPositionId"); jobPositionIDStr != "" {
+		objID, err := primitive.ObjectIDFromHex(jobPositionIDStr)
+		if err != nil {
+			helpers.SendBadRequest(c, "Invalid jobPositionId format")
+			return
+		}
+		jobPositionID = &objID
+	}
+
+	if actor == "" && jobPositionID == nil {
+		helpers.SendBadRequest(c, "Either actor or jobPositionId is required")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	keys, canonical, err := h.actorService.ResolveKeys(ctx, actor, jobPositionID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	result, err := h.documentService.SearchByActor(ctx, keys, jobPositionID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+	result.Actor = canonical
+	if jobPositionID != nil {
+		result.JobPositionID = jobPositionID.Hex()
+	}
+
+	if actor != "" {
+		if searchID, err := h.analyticsService.RecordSearch(ctx, models.SearchSourceActor, actor, int64(result.DocumentCount)); err == nil {
+			c.Header("X-Search-ID", searchID.Hex())
+		}
+	}
+
+	helpers.SendSuccess(c, "Actor search completed successfully", result)
+}
+
+// RecordSearchClick records that a search result was opened
+// POST /api/search/clicks
+func (h *SearchHandler) RecordSearchClick(c *gin.Context) {
+	var req models.RecordSearchClickRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	searchID, err := primitive.ObjectIDFromHex(req.SearchID)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid searchId format")
+		return
+	}
+	documentID, err := primitive.ObjectIDFromHex(req.DocumentID)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid documentId format")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if err := h.analyticsService.RecordSearchClick(ctx, searchID, documentID); err != nil {
+		if err.Error() == "search not found" {
+			helpers.SendNotFound(c, "Search not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Search click recorded successfully", nil)
+}
+
+// GetSearchAnalytics returns top, zero-result and clicked-through searches (Admin only)
+// GET /api/search/analytics?days=30&limit=20
+func (h *SearchHandler) GetSearchAnalytics(c *gin.Context) {
+	var from *time.Time
+	if daysStr := c.Query("days"); daysStr != "" {
+		if days, err := strconv.Atoi(daysStr); err == nil && days > 0 && days <= 365 {
+			since := time.Now().AddDate(0, 0, -days)
+			from = &since
+		}
+	}
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	analytics, err := h.analyticsService.GetSearchAnalytics(ctx, from, limit)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Search analytics retrieved successfully", analytics)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/kodesonik/process-manager/internal/helpers"
 	"github.com/kodesonik/process-manager/internal/middleware"
 	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -18,15 +19,21 @@ import (
 type SignatureHandler struct {
 	signatureCollection *mongo.Collection
 	documentCollection  *mongo.Collection
-	versionCollection   *mongo.Collection
 	userCollection      *mongo.Collection
+	commentService      *services.CommentService
+	documentService     *services.DocumentService
+	watchService        *services.WatchService
+	asyncRunner         *services.AsyncRunner
 }
 
-func NewSignatureHandler(db *mongo.Database) *SignatureHandler {
+func NewSignatureHandler(db *mongo.Database, commentService *services.CommentService, documentService *services.DocumentService, watchService *services.WatchService, asyncRunner *services.AsyncRunner) *SignatureHandler {
 	return &SignatureHandler{
+		commentService:      commentService,
+		documentService:     documentService,
+		watchService:        watchService,
+		asyncRunner:         asyncRunner,
 		signatureCollection: db.Collection("signatures"),
 		documentCollection:  db.Collection("documents"),
-		versionCollection:   db.Collection("document_versions"),
 		userCollection:      db.Collection("users"),
 	}
 }
@@ -45,7 +52,7 @@ func (h *SignatureHandler) GetDocumentSignatures(c *gin.Context) {
 
 	// Check if document exists
 	var document models.Document
-	err = h.documentCollection.FindOne(ctx, bson.M{"_id": documentID}).Decode(&document)
+	err = h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			helpers.SendNotFound(c, "Document not found")
@@ -56,7 +63,7 @@ func (h *SignatureHandler) GetDocumentSignatures(c *gin.Context) {
 	}
 
 	// Find all signatures for this document
-	cursor, err := h.signatureCollection.Find(ctx, bson.M{"document_id": documentID})
+	cursor, err := h.signatureCollection.Find(ctx, models.ActiveSignatures(bson.M{"document_id": documentID}))
 	if err != nil {
 		helpers.SendInternalError(c, err)
 		return
@@ -119,7 +126,7 @@ func (h *SignatureHandler) AddDocumentSignature(c *gin.Context) {
 
 	// Check if document exists
 	var document models.Document
-	err = h.documentCollection.FindOne(ctx, bson.M{"_id": documentID}).Decode(&document)
+	err = h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			helpers.SendNotFound(c, "Document not found")
@@ -165,13 +172,25 @@ func (h *SignatureHandler) AddDocumentSignature(c *gin.Context) {
 		return
 	}
 
+	// Reviewers cannot sign while blocking comments are open (when the gate is enabled)
+	if req.Type == models.SignatureTypeVerifier || req.Type == models.SignatureTypeValidator {
+		if err := h.commentService.CheckSigningAllowed(ctx, documentID); err != nil {
+			if err == models.ErrBlockingCommentsOpen {
+				helpers.SendConflict(c, err.Error())
+				return
+			}
+			helpers.SendInternalError(c, err)
+			return
+		}
+	}
+
 	// Check if user has already signed
 	var existingSignature models.Signature
-	err = h.signatureCollection.FindOne(ctx, bson.M{
+	err = h.signatureCollection.FindOne(ctx, models.ActiveSignatures(bson.M{
 		"document_id": documentID,
 		"user_id":     user.ID,
 		"type":        req.Type,
-	}).Decode(&existingSignature)
+	})).Decode(&existingSignature)
 	if err == nil {
 		helpers.SendBadRequest(c, "You have already signed this document")
 		return
@@ -190,6 +209,7 @@ func (h *SignatureHandler) AddDocumentSignature(c *gin.Context) {
 		Comments:      req.Comments,
 		IPAddress:     ipAddress,
 		UserAgent:     userAgent,
+		Version:       document.Version,
 	}
 	signature.BeforeCreate()
 
@@ -236,7 +256,7 @@ func (h *SignatureHandler) AddDocumentSignature(c *gin.Context) {
 
 	_, err = h.documentCollection.UpdateOne(ctx,
 		bson.M{"_id": documentID},
-		bson.M{"$set": updateDoc},
+		models.InvalidatePDF(bson.M{"$set": updateDoc, "$inc": bson.M{"revision": 1}}),
 	)
 	if err != nil {
 		// Don't fail the signature creation if contributor update fails
@@ -245,7 +265,7 @@ func (h *SignatureHandler) AddDocumentSignature(c *gin.Context) {
 	}
 
 	// Check if all signatures are complete and update document status if needed
-	h.updateDocumentStatus(ctx, documentID)
+	h.updateDocumentStatus(ctx, documentID, user.ID)
 
 	response := signature.ToResponse()
 	response.UserName = user.FirstName + " " + user.LastName
@@ -260,10 +280,10 @@ func (h *SignatureHandler) AddDocumentSignature(c *gin.Context) {
 
 // updateDocumentStatus updates the document status based on signatures
 // Implements automatic workflow transitions for Issue #47
-func (h *SignatureHandler) updateDocumentStatus(ctx context.Context, documentID primitive.ObjectID) {
+func (h *SignatureHandler) updateDocumentStatus(ctx context.Context, documentID, signerID primitive.ObjectID) {
 	// Get document
 	var document models.Document
-	err := h.documentCollection.FindOne(ctx, bson.M{"_id": documentID}).Decode(&document)
+	err := h.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
 	if err != nil {
 		fmt.Printf("❌ [updateDocumentStatus] Failed to fetch document: %v\n", err)
 		return
@@ -272,18 +292,18 @@ func (h *SignatureHandler) updateDocumentStatus(ctx context.Context, documentID
 	fmt.Printf("🔍 [updateDocumentStatus] Document ID: %s, Current Status: %s\n", documentID.Hex(), document.Status)
 
 	// Count signatures by type
-	authorSigs, _ := h.signatureCollection.CountDocuments(ctx, bson.M{
+	authorSigs, _ := h.signatureCollection.CountDocuments(ctx, models.ActiveSignatures(bson.M{
 		"document_id": documentID,
 		"type":        models.SignatureTypeAuthor,
-	})
-	verifierSigs, _ := h.signatureCollection.CountDocuments(ctx, bson.M{
+	}))
+	verifierSigs, _ := h.signatureCollection.CountDocuments(ctx, models.ActiveSignatures(bson.M{
 		"document_id": documentID,
 		"type":        models.SignatureTypeVerifier,
-	})
-	validatorSigs, _ := h.signatureCollection.CountDocuments(ctx, bson.M{
+	}))
+	validatorSigs, _ := h.signatureCollection.CountDocuments(ctx, models.ActiveSignatures(bson.M{
 		"document_id": documentID,
 		"type":        models.SignatureTypeValidator,
-	})
+	}))
 
 	// Count required signatures
 	authorsCount := len(document.Contributors.Authors)
@@ -293,78 +313,50 @@ func (h *SignatureHandler) updateDocumentStatus(ctx context.Context, documentID
 	fmt.Printf("📊 [updateDocumentStatus] Signature counts - Authors: %d/%d, Verifiers: %d/%d, Validators: %d/%d\n",
 		authorSigs, authorsCount, verifierSigs, verifiersCount, validatorSigs, validatorsCount)
 
+	teamSigs := map[models.ContributorTeam]int64{
+		models.ContributorTeamAuthors:    authorSigs,
+		models.ContributorTeamVerifiers:  verifierSigs,
+		models.ContributorTeamValidators: validatorSigs,
+	}
+
+	// The workflow of the document drives the transitions
+	workflow, err := h.documentService.EffectiveWorkflow(ctx, &document)
+	if err != nil {
+		fmt.Printf("❌ [updateDocumentStatus] Failed to resolve workflow: %v\n", err)
+		return
+	}
+
 	// Determine new status based on current status and signature completion
 	var newStatus models.DocumentStatus
+	var stage *models.WorkflowStage
 	shouldUpdate := false
 
 	switch document.Status {
 	case models.DocumentStatusDraft:
-		// Auto-publish to author_review when first author signs
-		// This allows authors to sign immediately without manual publish
-		if authorSigs > 0 {
-			newStatus = models.DocumentStatusAuthorReview
-			// Set all authors to pending
-			for i := range document.Contributors.Authors {
-				if document.Contributors.Authors[i].Status == models.SignatureStatusJoined {
-					document.Contributors.Authors[i].Status = models.SignatureStatusPending
-				}
-			}
-			shouldUpdate = true
-			fmt.Printf("✅ [updateDocumentStatus] Auto-publishing: draft → author_review (first author signature)\n")
-		}
-
-	case models.DocumentStatusAuthorReview:
-		// All authors have signed -> automatically transition to verifier_signed or verifier_review
-		if authorSigs >= int64(authorsCount) && authorsCount > 0 {
-			if verifiersCount > 0 {
-				newStatus = models.DocumentStatusVerifierReview
-				// Set verifiers to pending
-				for i := range document.Contributors.Verifiers {
-					if document.Contributors.Verifiers[i].Status == models.SignatureStatusJoined {
-						document.Contributors.Verifiers[i].Status = models.SignatureStatusPending
-					}
-				}
-				fmt.Printf("✅ [updateDocumentStatus] Transitioning: author_review → verifier_review\n")
-			} else {
-				// No verifiers, go straight to author_signed
-				newStatus = models.DocumentStatusAuthorSigned
-				fmt.Printf("✅ [updateDocumentStatus] Transitioning: author_review → author_signed (no verifiers)\n")
-			}
+		// Auto-publish to the first stage when its team signs first
+		// This allows signers to sign immediately without manual publish
+		if first := workflow.Workflow.FirstStage(&document); first != nil && teamSigs[first.Team] > 0 {
+			newStatus = first.ReviewStatus()
+			stage = first
 			shouldUpdate = true
-		} else {
-			fmt.Printf("⏳ [updateDocumentStatus] Not all authors signed yet (%d/%d)\n", authorSigs, authorsCount)
+			fmt.Printf("✅ [updateDocumentStatus] Auto-publishing: draft → %s (first signature)\n", newStatus)
 		}
 
-	case models.DocumentStatusVerifierReview:
-		// All verifiers have signed -> automatically transition to validator_signed or validator_review
-		if verifierSigs >= int64(verifiersCount) && verifiersCount > 0 {
-			if validatorsCount > 0 {
-				newStatus = models.DocumentStatusValidatorReview
-				// Set validators to pending
-				for i := range document.Contributors.Validators {
-					if document.Contributors.Validators[i].Status == models.SignatureStatusJoined {
-						document.Contributors.Validators[i].Status = models.SignatureStatusPending
-					}
-				}
-				fmt.Printf("✅ [updateDocumentStatus] Transitioning: verifier_review → validator_review\n")
-			} else {
-				// No validators, go straight to verifier_signed
-				newStatus = models.DocumentStatusVerifierSigned
-				fmt.Printf("✅ [updateDocumentStatus] Transitioning: verifier_review → verifier_signed (no validators)\n")
-			}
-			shouldUpdate = true
-		} else {
-			fmt.Printf("⏳ [updateDocumentStatus] Not all verifiers signed yet (%d/%d)\n", verifierSigs, verifiersCount)
+	case models.DocumentStatusAuthorReview, models.DocumentStatusVerifierReview, models.DocumentStatusValidatorReview:
+		// The stage is complete -> automatically transition to the next stage, signed or approved
+		current := workflow.Workflow.StageIndex(document.Status)
+		if current < 0 {
+			fmt.Printf("ℹ️ [updateDocumentStatus] Status '%s' is not a stage of the %s workflow\n", document.Status, workflow.Source)
+			break
 		}
-
-	case models.DocumentStatusValidatorReview:
-		// All validators have signed -> approve document
-		if validatorSigs >= int64(validatorsCount) && validatorsCount > 0 {
-			newStatus = models.DocumentStatusApproved
+		team := workflow.Workflow.Stages[current].Team
+		if status, next, ok := workflow.Workflow.SignTransition(&document, int(teamSigs[team])); ok {
+			newStatus = status
+			stage = next
 			shouldUpdate = true
-			fmt.Printf("✅ [updateDocumentStatus] Transitioning: validator_review → approved\n")
+			fmt.Printf("✅ [updateDocumentStatus] Transitioning: %s → %s\n", document.Status, newStatus)
 		} else {
-			fmt.Printf("⏳ [updateDocumentStatus] Not all validators signed yet (%d/%d)\n", validatorSigs, validatorsCount)
+			fmt.Printf("⏳ [updateDocumentStatus] Not enough %s signed yet (%d/%d)\n", team, teamSigs[team], len(document.Contributors.Team(team)))
 		}
 
 	default:
@@ -375,21 +367,20 @@ func (h *SignatureHandler) updateDocumentStatus(ctx context.Context, documentID
 	if shouldUpdate {
 		fmt.Printf("💾 [updateDocumentStatus] Updating document status to: %s\n", newStatus)
 		updateDoc := bson.M{
-			"status": newStatus,
+			"status":         newStatus,
+			"stage_deadline": services.NewStageDeadline(&document, newStatus, time.Now()),
 		}
 
-		// Update contributor arrays if they were modified
-		if newStatus == models.DocumentStatusAuthorReview {
-			updateDoc["contributors.authors"] = document.Contributors.Authors
-			fmt.Printf("📝 [updateDocumentStatus] Updating authors to pending status\n")
-		} else if newStatus == models.DocumentStatusVerifierReview || newStatus == models.DocumentStatusValidatorReview {
-			if newStatus == models.DocumentStatusVerifierReview {
-				updateDoc["contributors.verifiers"] = document.Contributors.Verifiers
-				fmt.Printf("📝 [updateDocumentStatus] Updating verifiers to pending status\n")
-			} else {
-				updateDoc["contributors.validators"] = document.Contributors.Validators
-				fmt.Printf("📝 [updateDocumentStatus] Updating validators to pending status\n")
+		// Set the signers of the stage entered to pending
+		if stage != nil {
+			signers := document.Contributors.Team(stage.Team)
+			for i := range signers {
+				if signers[i].Status == models.SignatureStatusJoined {
+					signers[i].Status = models.SignatureStatusPending
+				}
 			}
+			updateDoc["contributors."+string(stage.Team)] = signers
+			fmt.Printf("📝 [updateDocumentStatus] Updating %s to pending status\n", stage.Team)
 		}
 
 		// Set approved_at timestamp if document is approved
@@ -408,12 +399,20 @@ func (h *SignatureHandler) updateDocumentStatus(ctx context.Context, documentID
 
 		_, err = h.documentCollection.UpdateOne(ctx,
 			bson.M{"_id": documentID},
-			bson.M{"$set": updateDoc},
+			models.InvalidatePDF(bson.M{"$set": updateDoc, "$inc": bson.M{"revision": 1}}),
 		)
 		if err != nil {
 			fmt.Printf("❌ [updateDocumentStatus] Failed to update document status: %v\n", err)
 		} else {
 			fmt.Printf("✅ [updateDocumentStatus] Document status updated successfully to: %s\n", newStatus)
+
+			document.Status = newStatus
+			h.asyncRunner.Go("watch_notifications", func(ctx context.Context) error {
+				return h.watchService.Notify(ctx, document.ID, &document, models.WatchEventStatusChanged,
+					"Watched Document Status Changed",
+					fmt.Sprintf("Document '%s' (%s) is now %s.", document.Title, document.Reference, document.Status),
+					signerID)
+			})
 		}
 	} else {
 		fmt.Printf("⏭️ [updateDocumentStatus] No status update needed\n")
@@ -422,20 +421,5 @@ func (h *SignatureHandler) updateDocumentStatus(ctx context.Context, documentID
 
 // createVersionSnapshot creates an immutable snapshot of the document
 func (h *SignatureHandler) createVersionSnapshot(ctx context.Context, document *models.Document, changeNote string) error {
-	version := &models.DocumentVersion{
-		ID:         primitive.NewObjectID(),
-		DocumentID: document.ID,
-		Version:    document.Version,
-		Data:       *document,
-		CreatedBy:  document.CreatedBy,
-		CreatedAt:  time.Now(),
-		ChangeNote: changeNote,
-	}
-
-	_, err := h.versionCollection.InsertOne(ctx, version)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return h.documentService.CreateVersion(ctx, document, document.CreatedBy, changeNote)
 }
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SignatureReminderHandler lets document owners manage the reminders sent to
+// the contributors who have not signed yet
+type SignatureReminderHandler struct {
+	approvalDeadlineService *services.ApprovalDeadlineService
+	documentService         *services.DocumentService
+	activityLogService      *services.ActivityLogService
+}
+
+// NewSignatureReminderHandler creates a new signature reminder handler instance
+func NewSignatureReminderHandler(approvalDeadlineService *services.ApprovalDeadlineService, documentService *services.DocumentService, activityLogService *services.ActivityLogService) *SignatureReminderHandler {
+	return &SignatureReminderHandler{
+		approvalDeadlineService: approvalDeadlineService,
+		documentService:         documentService,
+		activityLogService:      activityLogService,
+	}
+}
+
+// UpdateSignatureReminders mutes or unmutes the scheduled signature reminders
+// of a document. Allowed to the document owner and admins.
+// PUT /api/documents/:id/signature-reminders
+func (h *SignatureReminderHandler) UpdateSignatureReminders(c *gin.Context) {
+	var req models.UpdateSignatureRemindersRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	document, ok := h.ownedDocument(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.approvalDeadlineService.SetRemindersMuted(ctx, document.ID, *req.Muted)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return
+		}
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	description := fmt.Sprintf("Resumed signature reminders of document '%s' (%s)", document.Title, document.Reference)
+	if *req.Muted {
+		description = fmt.Sprintf("Muted signature reminders of document '%s' (%s)", document.Title, document.Reference)
+	}
+	h.logReminderActivity(c, document, description, map[string]interface{}{"muted": *req.Muted})
+
+	helpers.SendSuccess(c, "Signature reminders updated successfully", document.ToResponse())
+}
+
+// SendSignatureReminders reminds the pending signers of a document right
+// away, even when its scheduled reminders are muted. Allowed to the document
+// owner and admins.
+// POST /api/documents/:id/signature-reminders/send
+func (h *SignatureReminderHandler) SendSignatureReminders(c *gin.Context) {
+	document, ok := h.ownedDocument(c)
+	if !ok {
+		return
+	}
+
+	reminded, err := h.approvalDeadlineService.SendRemindersNow(c.Request.Context(), document.ID)
+	if err != nil {
+		switch err.Error() {
+		case "document not found":
+			helpers.SendNotFound(c, "Document not found")
+		case "document is not waiting for signatures", "document has no pending signatures":
+			helpers.SendBadRequest(c, err.Error())
+		default:
+			helpers.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.logReminderActivity(c, document,
+		fmt.Sprintf("Sent signature reminders of document '%s' (%s)", document.Title, document.Reference),
+		map[string]interface{}{"reminded": reminded})
+
+	helpers.SendSuccess(c, "Signature reminders sent successfully", gin.H{"reminded": reminded})
+}
+
+// ownedDocument returns the document of the request when the current user
+// owns it or is an admin, sending the error response otherwise
+func (h *SignatureReminderHandler) ownedDocument(c *gin.Context) (*models.Document, bool) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return nil, false
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return nil, false
+	}
+
+	document, err := h.documentService.GetByID(c.Request.Context(), documentID)
+	if err != nil {
+		if err.Error() == "document not found" {
+			helpers.SendNotFound(c, "Document not found")
+			return nil, false
+		}
+		helpers.SendInternalError(c, err)
+		return nil, false
+	}
+
+	if user.Role != models.RoleAdmin && document.CreatedBy != user.ID {
+		helpers.SendForbidden(c, "Only the document owner can manage its signature reminders", models.CodeForbidden)
+		return nil, false
+	}
+	return document, true
+}
+
+func (h *SignatureReminderHandler) logReminderActivity(c *gin.Context, document *models.Document, description string, details map[string]interface{}) {
+	details["documentId"] = document.ID.Hex()
+	details["reference"] = document.Reference
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_signature_reminders",
+		Description:  description,
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details:      details,
+	}
+	if logErr := h.activityLogService.LogActivity(c.Request.Context(), activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SnippetHandler handles the library of shared process step snippets
+type SnippetHandler struct {
+	snippetService     *services.SnippetService
+	activityLogService *services.ActivityLogService
+}
+
+// NewSnippetHandler creates a new snippet handler instance
+func NewSnippetHandler(snippetService *services.SnippetService, activityLogService *services.ActivityLogService) *SnippetHandler {
+	return &SnippetHandler{
+		snippetService:     snippetService,
+		activityLogService: activityLogService,
+	}
+}
+
+// sendSnippetError maps snippet service errors to HTTP responses
+func sendSnippetError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "snippet not found":
+		helpers.SendNotFound(c, "Snippet not found")
+	case err.Error() == "document not found":
+		helpers.SendNotFound(c, "Document not found")
+	case err.Error() == "process group not found":
+		helpers.SendNotFound(c, "Process group not found")
+	case err == services.ErrDocumentRevisionConflict:
+		helpers.SendConflict(c, "Document was modified by another user, retry")
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
+
+// GetSnippets returns the snippets of the library
+// GET /api/snippets?search=&page=1&limit=20
+func (h *SnippetHandler) GetSnippets(c *gin.Context) {
+	page, limit := helpers.GetPaginationParams(c)
+	filter := &models.SnippetFilter{
+		Page:  page,
+		Limit: limit,
+	}
+	if search := c.Query("search"); search != "" {
+		filter.Search = &search
+	}
+
+	snippets, total, err := h.snippetService.List(c.Request.Context(), filter)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Snippets retrieved successfully", snippets, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
+
+// GetSnippet returns a specific snippet
+// GET /api/snippets/:id
+func (h *SnippetHandler) GetSnippet(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid snippet ID format")
+		return
+	}
+
+	snippet, err := h.snippetService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		sendSnippetError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Snippet retrieved successfully", snippet)
+}
+
+// CreateSnippet adds a snippet to the library
+// POST /api/snippets
+func (h *SnippetHandler) CreateSnippet(c *gin.Context) {
+	var req models.CreateSnippetRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	snippet, err := h.snippetService.Create(c.Request.Context(), &req, userID)
+	if err != nil {
+		sendSnippetError(c, err)
+		return
+	}
+
+	helpers.SendCreated(c, "Snippet created successfully", snippet)
+}
+
+// UpdateSnippet updates a snippet, a change of its steps creates a new version
+// PUT /api/snippets/:id
+func (h *SnippetHandler) UpdateSnippet(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid snippet ID format")
+		return
+	}
+
+	var req models.UpdateSnippetRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	snippet, err := h.snippetService.Update(c.Request.Context(), id, &req, userID)
+	if err != nil {
+		sendSnippetError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Snippet updated successfully", snippet)
+}
+
+// DeleteSnippet removes a snippet from the library
+// DELETE /api/snippets/:id
+func (h *SnippetHandler) DeleteSnippet(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid snippet ID format")
+		return
+	}
+
+	if err := h.snippetService.Delete(c.Request.Context(), id); err != nil {
+		sendSnippetError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Snippet deleted successfully", nil)
+}
+
+// GetSnippetUsages lists the documents embedding a snippet
+// GET /api/snippets/:id/usages?outdated=true
+func (h *SnippetHandler) GetSnippetUsages(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid snippet ID format")
+		return
+	}
+	outdated, _ := strconv.ParseBool(c.Query("outdated"))
+
+	usages, err := h.snippetService.Usages(c.Request.Context(), &id, outdated)
+	if err != nil {
+		sendSnippetError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Snippet usages retrieved successfully", usages)
+}
+
+// GetOutdatedReport lists the documents embedding an outdated version of a snippet
+// GET /api/snippets/outdated
+func (h *SnippetHandler) GetOutdatedReport(c *gin.Context) {
+	usages, err := h.snippetService.Usages(c.Request.Context(), nil, true)
+	if err != nil {
+		sendSnippetError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Outdated snippet report retrieved successfully", usages)
+}
+
+// EmbedSnippet copies the steps of a snippet into a process group of a document
+// POST /api/documents/:id/snippets/:snippetId
+func (h *SnippetHandler) EmbedSnippet(c *gin.Context) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+	snippetID, err := primitive.ObjectIDFromHex(c.Param("snippetId"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid snippet ID format")
+		return
+	}
+
+	var req models.EmbedSnippetRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	document, err := h.snippetService.Embed(ctx, snippetID, documentID, &req, user.ID, user.Role)
+	if err != nil {
+		sendSnippetError(c, err)
+		return
+	}
+
+	activityReq := models.ActivityLogRequest{
+		Action:       "document_snippet_embedded",
+		Description:  fmt.Sprintf("Embedded a snippet into document '%s' (%s)", document.Title, document.Reference),
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"snippetId":  snippetID.Hex(),
+			"groupId":    req.GroupID,
+		},
+	}
+	if logErr := h.activityLogService.LogActivity(ctx, activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+
+	helpers.SendSuccess(c, "Snippet embedded successfully", document.ToResponse())
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// StatusHandler handles the system status page
+type StatusHandler struct {
+	statusService *services.StatusService
+}
+
+// NewStatusHandler creates a new status handler instance
+func NewStatusHandler(statusService *services.StatusService) *StatusHandler {
+	return &StatusHandler{
+		statusService: statusService,
+	}
+}
+
+// maxPublicStatusHours caps the window of the public status page
+const maxPublicStatusHours = 24
+
+// GetStatus returns uptime by hour, component health and recent incidents.
+// Public, the raw samples are served by GetStatusHistory.
+// GET /api/status?hours=24
+func (h *StatusHandler) GetStatus(c *gin.Context) {
+	hours := statusWindowHours(c, maxPublicStatusHours)
+
+	ctx := c.Request.Context()
+
+	status, err := h.statusService.GetStatus(ctx, hours)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "System status retrieved successfully", status)
+}
+
+// GetStatusHistory returns the status page with the raw health samples and
+// the error details (admin only)
+// GET /api/admin/status?hours=168
+func (h *StatusHandler) GetStatusHistory(c *gin.Context) {
+	hours := statusWindowHours(c, 168)
+
+	ctx := c.Request.Context()
+
+	status, err := h.statusService.GetStatusHistory(ctx, hours)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "System status history retrieved successfully", status)
+}
+
+// statusWindowHours returns the window requested by the hours query
+// parameter, 24 hours by default and capped at maxHours
+func statusWindowHours(c *gin.Context, maxHours int) int {
+	hours := 24
+	if v, err := strconv.Atoi(c.Query("hours")); err == nil && v > 0 {
+		hours = v
+	}
+	return min(hours, maxHours)
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TriageHandler handles the triage queue of the documents created in bulk
+type TriageHandler struct {
+	triageService      *services.TriageService
+	activityLogService *services.ActivityLogService
+}
+
+// NewTriageHandler creates a new triage handler instance
+func NewTriageHandler(triageService *services.TriageService, activityLogService *services.ActivityLogService) *TriageHandler {
+	return &TriageHandler{
+		triageService:      triageService,
+		activityLogService: activityLogService,
+	}
+}
+
+// sendTriageError maps triage service errors to HTTP responses
+func sendTriageError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "document not found":
+		helpers.SendNotFound(c, "Document not found")
+	case models.IsNotFoundError(err):
+		helpers.SendNotFound(c, "User not found")
+	case strings.HasSuffix(err.Error(), "macro not found"):
+		helpers.SendNotFound(c, "Macro not found")
+	case err == services.ErrNotInTriage:
+		helpers.SendConflict(c, err.Error())
+	case err == services.ErrDocumentRevisionConflict:
+		helpers.SendConflict(c, "Document is being modified by another user, please retry")
+	case strings.HasPrefix(err.Error(), "assignee must be"),
+		strings.HasPrefix(err.Error(), "owner must be"),
+		strings.HasSuffix(err.Error(), "document is locked"):
+		helpers.SendBadRequest(c, err.Error())
+	default:
+		helpers.SendInternalError(c, err)
+	}
+}
+
+// GetTriageQueue returns the documents waiting for triage, the closest
+// deadline first
+// GET /api/triage?assigneeId=&overdue=true&page=&limit=
+func (h *TriageHandler) GetTriageQueue(c *gin.Context) {
+	var assigneeID *primitive.ObjectID
+	if value := c.Query("assigneeId"); value != "" {
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			helpers.SendBadRequest(c, "Invalid assigneeId format")
+			return
+		}
+		assigneeID = &id
+	}
+	page, limit := helpers.GetPaginationParams(c)
+
+	items, total, err := h.triageService.List(c.Request.Context(), assigneeID, c.Query("overdue") == "true", page, limit)
+	if err != nil {
+		sendTriageError(c, err)
+		return
+	}
+
+	helpers.SendSuccessWithPagination(c, "Triage queue retrieved successfully", items, helpers.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
+
+// GetTriageStats returns the size of the queue and the SLA compliance
+// GET /api/triage/stats
+func (h *TriageHandler) GetTriageStats(c *gin.Context) {
+	stats, err := h.triageService.Stats(c.Request.Context())
+	if err != nil {
+		sendTriageError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Triage statistics retrieved successfully", stats)
+}
+
+// AssignTriage gives the triage of a document to a quality manager
+// PUT /api/triage/:id/assignee
+func (h *TriageHandler) AssignTriage(c *gin.Context) {
+	id, userID, req, ok := h.bindUserAction(c)
+	if !ok {
+		return
+	}
+	assigneeID, err := primitive.ObjectIDFromHex(req.UserID)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid user ID format")
+		return
+	}
+
+	document, err := h.triageService.Assign(c.Request.Context(), id, assigneeID, userID)
+	if err != nil {
+		sendTriageError(c, err)
+		return
+	}
+
+	h.logTriageActivity(c, "document_triage_assigned", fmt.Sprintf("Assigned the triage of document '%s'", document.Title), document, map[string]interface{}{
+		"assigneeId": assigneeID.Hex(),
+	})
+
+	helpers.SendSuccess(c, "Triage assigned successfully", document.ToResponse())
+}
+
+// AssignTriageMacro attaches a document in triage to its macro
+// PUT /api/triage/:id/macro
+func (h *TriageHandler) AssignTriageMacro(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	var req models.TriageMacroRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return
+	}
+	macroID, err := primitive.ObjectIDFromHex(req.MacroID)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid macro ID format")
+		return
+	}
+
+	document, err := h.triageService.AssignMacro(c.Request.Context(), id, macroID)
+	if err != nil {
+		sendTriageError(c, err)
+		return
+	}
+
+	h.logTriageActivity(c, "document_triage_macro_assigned", fmt.Sprintf("Attached document '%s' in triage to a macro as process %s", document.Title, document.ProcessCode), document, map[string]interface{}{
+		"macroId":     macroID.Hex(),
+		"processCode": document.ProcessCode,
+	})
+
+	helpers.SendSuccess(c, "Macro assigned successfully", document.ToResponse())
+}
+
+// AssignTriageOwner makes a user the owner of a document in triage
+// PUT /api/triage/:id/owner
+func (h *TriageHandler) AssignTriageOwner(c *gin.Context) {
+	id, _, req, ok := h.bindUserAction(c)
+	if !ok {
+		return
+	}
+	ownerID, err := primitive.ObjectIDFromHex(req.UserID)
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid user ID format")
+		return
+	}
+
+	document, err := h.triageService.AssignOwner(c.Request.Context(), id, ownerID)
+	if err != nil {
+		sendTriageError(c, err)
+		return
+	}
+
+	h.logTriageActivity(c, "document_triage_owner_assigned", fmt.Sprintf("Changed the owner of document '%s' in triage", document.Title), document, map[string]interface{}{
+		"ownerId": ownerID.Hex(),
+	})
+
+	helpers.SendSuccess(c, "Owner assigned successfully", document.ToResponse())
+}
+
+// AcceptTriage ends the triage of a document, which continues as a draft
+// POST /api/triage/:id/accept
+func (h *TriageHandler) AcceptTriage(c *gin.Context) {
+	h.resolve(c, models.TriageResolutionAccepted)
+}
+
+// DiscardTriage ends the triage of a document by moving it to the trash
+// POST /api/triage/:id/discard
+func (h *TriageHandler) DiscardTriage(c *gin.Context) {
+	h.resolve(c, models.TriageResolutionDiscarded)
+}
+
+// resolve accepts or discards a document in triage
+func (h *TriageHandler) resolve(c *gin.Context, resolution models.TriageResolution) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var document *models.Document
+	if resolution == models.TriageResolutionDiscarded {
+		document, err = h.triageService.Discard(ctx, id, userID)
+	} else {
+		document, err = h.triageService.Accept(ctx, id, userID)
+	}
+	if err != nil {
+		sendTriageError(c, err)
+		return
+	}
+
+	h.logTriageActivity(c, models.ActivityAction("document_triage_"+string(resolution)), fmt.Sprintf("Triage of document '%s' %s", document.Title, resolution), document, map[string]interface{}{
+		"withinSla": !document.Triage.ResolvedAt.After(document.Triage.DueAt),
+	})
+
+	helpers.SendSuccess(c, "Triage completed successfully", document.ToResponse())
+}
+
+// bindUserAction reads the document ID, the current user and the user of a
+// triage action
+func (h *TriageHandler) bindUserAction(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, *models.TriageUserRequest, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return id, primitive.NilObjectID, nil, false
+	}
+
+	var req models.TriageUserRequest
+	if err := helpers.BindAndValidate(c, &req); err != nil {
+		helpers.SendValidationErrors(c, err)
+		return id, primitive.NilObjectID, nil, false
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return id, primitive.NilObjectID, nil, false
+	}
+	return id, userID, &req, true
+}
+
+// logTriageActivity records a triage action on a document
+func (h *TriageHandler) logTriageActivity(c *gin.Context, action models.ActivityAction, description string, document *models.Document, details map[string]interface{}) {
+	details["documentId"] = document.ID.Hex()
+	details["reference"] = document.Reference
+
+	activityReq := models.ActivityLogRequest{
+		Action:       action,
+		Description:  description,
+		ResourceType: "document",
+		ResourceID:   &document.ID,
+		Success:      true,
+		Details:      details,
+	}
+	if logErr := h.activityLogService.LogActivity(c.Request.Context(), activityReq, c); logErr != nil {
+		fmt.Printf("Failed to log activity: %v\n", logErr)
+	}
+}
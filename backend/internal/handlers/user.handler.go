@@ -1,9 +1,6 @@
 package handlers
 
 import (
-	"context"
-	"time"
-
 	"github.com/gin-gonic/gin"
 	"github.com/kodesonik/process-manager/internal/helpers"
 	"github.com/kodesonik/process-manager/internal/middleware"
@@ -30,8 +27,7 @@ func NewUserHandler(userService *services.UserService, emailService *services.Em
 // GetAllUsers returns all users with pagination and filters (admin only)
 // GET /api/users
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Parse pagination parameters
 	page, limit := helpers.GetPaginationParams(c)
@@ -80,8 +76,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 
 	userID, _ := primitive.ObjectIDFromHex(idStr)
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	user, err := h.userService.GetUserByID(ctx, userID)
 	if err != nil {
@@ -108,8 +103,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Check if user already exists
 	existingUser, err := h.userService.GetUserByEmail(ctx, req.Email)
@@ -154,8 +148,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	updatedUser, err := h.userService.UpdateUser(ctx, userID, &req)
 	if err != nil {
@@ -186,8 +179,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 
 	userID, _ := primitive.ObjectIDFromHex(idStr)
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	err = h.userService.SoftDeleteUser(ctx, userID)
 	if err != nil {
@@ -211,8 +203,7 @@ func (h *UserHandler) ActivateUser(c *gin.Context) {
 
 	userID, _ := primitive.ObjectIDFromHex(idStr)
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	err = h.userService.SetUserActiveStatus(ctx, userID, true)
 	if err != nil {
@@ -236,8 +227,7 @@ func (h *UserHandler) DeactivateUser(c *gin.Context) {
 
 	userID, _ := primitive.ObjectIDFromHex(idStr)
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	err = h.userService.SetUserActiveStatus(ctx, userID, false)
 	if err != nil {
@@ -273,8 +263,7 @@ func (h *UserHandler) UpdateUserRole(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	err = h.userService.UpdateUserRole(ctx, userID, req.Role)
 	if err != nil {
@@ -304,8 +293,7 @@ func (h *UserHandler) ValidateUser(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
 	// Get current admin user
 	currentUser, exists := middleware.GetCurrentUser(c)
@@ -350,7 +338,7 @@ func (h *UserHandler) ValidateUser(c *gin.Context) {
 		}
 
 		// Send approval email
-		if err := h.emailService.SendAccountApprovedEmail(user.Email, user.FirstName + " " + user.LastName); err != nil {
+		if err := h.emailService.SendAccountApprovedEmail(user.Email, user.FirstName + " " + user.LastName, user.Language); err != nil {
 			// Log error but don't fail the approval
 		}
 
@@ -375,7 +363,7 @@ func (h *UserHandler) ValidateUser(c *gin.Context) {
 		}
 
 		// Send rejection email
-		if err := h.emailService.SendAccountRejectedEmail(user.Email, user.FirstName + " " + user.LastName, req.Reason); err != nil {
+		if err := h.emailService.SendAccountRejectedEmail(user.Email, user.FirstName + " " + user.LastName, req.Reason, user.Language); err != nil {
 			// Log error but don't fail the rejection
 		}
 
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WatchHandler handles the subscriptions of users to document changes
+type WatchHandler struct {
+	watchService *services.WatchService
+}
+
+// NewWatchHandler creates a new watch handler instance
+func NewWatchHandler(watchService *services.WatchService) *WatchHandler {
+	return &WatchHandler{
+		watchService: watchService,
+	}
+}
+
+// GetWatchStatus tells whether the current user watches a document
+// GET /api/documents/:id/watch
+func (h *WatchHandler) GetWatchStatus(c *gin.Context) {
+	h.watchStatus(c, func(userID, documentID primitive.ObjectID) error { return nil })
+}
+
+// WatchDocument subscribes the current user to the status changes, new
+// versions and new annexes of a document
+// POST /api/documents/:id/watch
+func (h *WatchHandler) WatchDocument(c *gin.Context) {
+	h.watchStatus(c, func(userID, documentID primitive.ObjectID) error {
+		return h.watchService.Watch(c.Request.Context(), userID, documentID)
+	})
+}
+
+// UnwatchDocument unsubscribes the current user from a document
+// DELETE /api/documents/:id/watch
+func (h *WatchHandler) UnwatchDocument(c *gin.Context) {
+	h.watchStatus(c, func(userID, documentID primitive.ObjectID) error {
+		return h.watchService.Unwatch(c.Request.Context(), userID, documentID)
+	})
+}
+
+// watchStatus applies a change to the watch of the document and responds
+// with its resulting status
+func (h *WatchHandler) watchStatus(c *gin.Context, change func(userID, documentID primitive.ObjectID) error) {
+	documentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid document ID format")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		helpers.SendUnauthorized(c, "User not found in context", "UNAUTHORIZED")
+		return
+	}
+
+	if err := change(userID, documentID); err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	status, err := h.watchService.Status(c.Request.Context(), userID, documentID)
+	if err != nil {
+		helpers.SendInternalError(c, err)
+		return
+	}
+
+	helpers.SendSuccess(c, "Watch status retrieved successfully", status)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// maxWebhookBodySize bounds the payloads accepted from the providers
+const maxWebhookBodySize = 1 << 20
+
+// WebhookHandler receives the callbacks of external providers
+type WebhookHandler struct {
+	outboxService *services.EmailOutboxService
+	brevoSecret   string
+}
+
+// NewWebhookHandler creates a new webhook handler instance. BREVO_WEBHOOK_SECRET
+// is the token Brevo must send, the Brevo webhook is disabled without it.
+func NewWebhookHandler(outboxService *services.EmailOutboxService) *WebhookHandler {
+	return &WebhookHandler{
+		outboxService: outboxService,
+		brevoSecret:   os.Getenv("BREVO_WEBHOOK_SECRET"),
+	}
+}
+
+// BrevoWebhook records the delivery events of the transactional emails:
+// deliveries, opens, bounces, blocks and spam reports. Brevo sends the secret
+// as a bearer token or in the secret query parameter, and one event or a
+// batch of events.
+// POST /api/webhooks/brevo
+func (h *WebhookHandler) BrevoWebhook(c *gin.Context) {
+	if h.brevoSecret == "" {
+		helpers.SendServiceUnavailable(c, "Brevo webhook is not configured")
+		return
+	}
+	secret := strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+	if secret == "" {
+		secret = c.Query("secret")
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(h.brevoSecret)) != 1 {
+		helpers.SendUnauthorized(c, "Invalid webhook secret", "UNAUTHORIZED")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize)
+	body, err := c.GetRawData()
+	if err != nil {
+		helpers.SendBadRequest(c, "Failed to read webhook payload")
+		return
+	}
+
+	var events []models.BrevoWebhookEvent
+	body = bytes.TrimSpace(body)
+	if bytes.HasPrefix(body, []byte("[")) {
+		err = json.Unmarshal(body, &events)
+	} else {
+		var event models.BrevoWebhookEvent
+		err = json.Unmarshal(body, &event)
+		events = append(events, event)
+	}
+	if err != nil {
+		helpers.SendBadRequest(c, "Invalid webhook payload")
+		return
+	}
+
+	recorded := 0
+	for i := range events {
+		event, ok := events[i].ToProviderEvent()
+		if !ok {
+			continue
+		}
+		if err := h.outboxService.RecordEvent(c.Request.Context(), event); err != nil {
+			// Brevo retries the webhooks that fail
+			helpers.SendInternalError(c, err)
+			return
+		}
+		recorded++
+	}
+
+	helpers.SendSuccess(c, "Webhook processed successfully", gin.H{"recorded": recorded})
+}
@@ -0,0 +1,115 @@
+package helpers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ExportFormat is the file format of a list export
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson" // One JSON object per line
+)
+
+// exportFlushInterval is the number of rows written between two flushes
+const exportFlushInterval = 500
+
+// ParseExportFormat validates an export format, CSV being the default
+func ParseExportFormat(format string) (ExportFormat, error) {
+	switch ExportFormat(format) {
+	case "", ExportFormatCSV:
+		return ExportFormatCSV, nil
+	case ExportFormatNDJSON:
+		return ExportFormatNDJSON, nil
+	}
+	return "", fmt.Errorf("invalid format: must be csv or ndjson")
+}
+
+// ContentType returns the MIME type of the export format
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportWriter writes export rows one at a time, so exports never hold more
+// than a row in memory. CSV exports start with a header row; NDJSON exports
+// encode the record of each row instead.
+type ExportWriter struct {
+	format  ExportFormat
+	out     io.Writer
+	csv     *csv.Writer
+	json    *json.Encoder
+	header  []string
+	started bool
+	rows    int64
+}
+
+// NewExportWriter creates an export writer on top of out
+func NewExportWriter(out io.Writer, format ExportFormat, header []string) *ExportWriter {
+	w := &ExportWriter{format: format, out: out, header: header}
+	if format == ExportFormatNDJSON {
+		w.json = json.NewEncoder(out)
+	} else {
+		w.csv = csv.NewWriter(out)
+	}
+	return w
+}
+
+// Write appends a row: record is encoded in NDJSON, columns in CSV
+func (w *ExportWriter) Write(record interface{}, columns []string) error {
+	if w.json != nil {
+		if err := w.json.Encode(record); err != nil {
+			return err
+		}
+	} else {
+		if !w.started {
+			w.started = true
+			if err := w.csv.Write(w.header); err != nil {
+				return err
+			}
+		}
+		if err := w.csv.Write(columns); err != nil {
+			return err
+		}
+	}
+
+	w.rows++
+	if w.rows%exportFlushInterval == 0 {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush pushes the buffered rows to the underlying writer, and to the client
+// when writing an HTTP response
+func (w *ExportWriter) Flush() error {
+	if w.csv != nil {
+		if !w.started {
+			// Empty exports still have a header
+			w.started = true
+			if err := w.csv.Write(w.header); err != nil {
+				return err
+			}
+		}
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := w.out.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// Rows returns the number of rows written
+func (w *ExportWriter) Rows() int64 {
+	return w.rows
+}
@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// RedactionCategory groups the sensitive response fields hidden together
+type RedactionCategory string
+
+const (
+	RedactionContact RedactionCategory = "contact" // Emails and phone numbers
+	RedactionNetwork RedactionCategory = "network" // IP addresses
+)
+
+// redactedFields maps the JSON fields of the responses to their category
+var redactedFields = map[string]RedactionCategory{
+	"email":        RedactionContact,
+	"actorEmail":   RedactionContact,
+	"userEmail":    RedactionContact,
+	"signerEmail":  RedactionContact,
+	"invitedEmail": RedactionContact,
+	"toEmail":      RedactionContact,
+	"phone":        RedactionContact,
+	"phoneNumber":  RedactionContact,
+	"ipAddress":    RedactionNetwork,
+}
+
+// visibleCategories lists the categories each role may see on other users.
+// Admins see everything.
+var visibleCategories = map[models.UserRole][]RedactionCategory{
+	models.RoleManager: {RedactionContact},
+	models.RoleUser:    {},
+}
+
+// RedactForCaller removes from data the sensitive fields of other users the
+// caller's role may not see: the fields are emptied, except in the objects
+// of the caller (id or userId matching). Admins and unauthenticated requests
+// get data unchanged; otherwise data is returned as its generic JSON form.
+func RedactForCaller(c *gin.Context, data interface{}) interface{} {
+	if data == nil {
+		return data
+	}
+	value, exists := c.Get("user")
+	if !exists {
+		return data
+	}
+	user, ok := value.(*models.User)
+	if !ok || user.Role == models.RoleAdmin {
+		return data
+	}
+
+	hidden := make(map[RedactionCategory]bool)
+	for _, category := range []RedactionCategory{RedactionContact, RedactionNetwork} {
+		hidden[category] = true
+	}
+	for _, category := range visibleCategories[user.Role] {
+		hidden[category] = false
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber() // Keeps numbers as sent
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return data
+	}
+
+	redactValue(generic, user.ID.Hex(), hidden)
+	return generic
+}
+
+// redactValue empties the hidden fields of the objects in value, except in
+// the objects of the caller
+func redactValue(value interface{}, callerID string, hidden map[RedactionCategory]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		own := v["id"] == callerID || v["userId"] == callerID
+		for key, field := range v {
+			if category, sensitive := redactedFields[key]; sensitive && hidden[category] && !own {
+				if _, isString := field.(string); isString {
+					v[key] = ""
+				}
+				continue
+			}
+			redactValue(field, callerID, hidden)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactValue(item, callerID, hidden)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package helpers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRedactForCallerHidesOtherUsersFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	caller := &models.User{ID: primitive.NewObjectID(), Role: models.RoleUser}
+	otherID := primitive.NewObjectID().Hex()
+
+	tests := []struct {
+		field string
+		value string
+	}{
+		{"email", "jane.doe@example.com"},
+		{"actorEmail", "jane.doe@example.com"},
+		{"userEmail", "jane.doe@example.com"},
+		{"signerEmail", "jane.doe@example.com"},
+		{"invitedEmail", "jane.doe@example.com"},
+		{"toEmail", "jane.doe@example.com"},
+		{"phone", "+22890000000"},
+		{"phoneNumber", "+22890000000"},
+		{"ipAddress", "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Set("user", caller)
+
+			data := []map[string]interface{}{
+				{"id": otherID, tt.field: tt.value},
+				{"id": caller.ID.Hex(), tt.field: tt.value},
+			}
+			redacted := RedactForCaller(c, data).([]interface{})
+
+			if got := redacted[0].(map[string]interface{})[tt.field]; got != "" {
+				t.Errorf("%s of another user: expected it to be hidden, got %q", tt.field, got)
+			}
+			if got := redacted[1].(map[string]interface{})[tt.field]; got != tt.value {
+				t.Errorf("%s of the caller: expected %q, got %q", tt.field, tt.value, got)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package helpers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -13,19 +15,19 @@ import (
 // Success Response Handlers
 // ============================================
 
-// SendSuccess sends a success response with data
+// SendSuccess sends a success response with data, redacted for the caller's role
 func SendSuccess(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusOK, models.NewSuccessResponse(message, data))
+	c.JSON(http.StatusOK, models.NewSuccessResponse(message, RedactForCaller(c, data)))
 }
 
-// SendCreated sends a created response with data
+// SendCreated sends a created response with data, redacted for the caller's role
 func SendCreated(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusCreated, models.NewSuccessResponse(message, data))
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(message, RedactForCaller(c, data)))
 }
 
-// SendPaginated sends a paginated response
+// SendPaginated sends a paginated response, redacted for the caller's role
 func SendPaginated(c *gin.Context, data interface{}, page, limit int, total int64) {
-	c.JSON(http.StatusOK, models.NewPaginatedResponse(data, page, limit, total))
+	c.JSON(http.StatusOK, models.NewPaginatedResponse(RedactForCaller(c, data), page, limit, total))
 }
 
 // SendNoContent sends a no content response
@@ -65,12 +67,13 @@ func GetPaginationParams(c *gin.Context) (page, limit int) {
 	return page, limit
 }
 
-// SendSuccessWithPagination sends a success response with pagination info
+// SendSuccessWithPagination sends a success response with pagination info,
+// redacted for the caller's role
 func SendSuccessWithPagination(c *gin.Context, message string, data interface{}, pagination PaginationInfo) {
 	response := gin.H{
 		"success": true,
 		"message": message,
-		"data":    data,
+		"data":    RedactForCaller(c, data),
 		"pagination": pagination,
 	}
 	c.JSON(http.StatusOK, response)
@@ -166,6 +169,28 @@ func SendConflict(c *gin.Context, message string) {
 	))
 }
 
+// SendRevisionConflict sends a conflict error response carrying the current
+// state of a resource modified since the client read it
+func SendRevisionConflict(c *gin.Context, message string, current interface{}) {
+	resp := models.NewErrorResponse(message, models.CodeRevisionConflict)
+	resp.Data = current
+	c.JSON(http.StatusConflict, resp)
+}
+
+// SendRevisionRequired sends a precondition required error response for the
+// writes that do not tell which revision of a resource they are based on
+func SendRevisionRequired(c *gin.Context, message string) {
+	c.JSON(http.StatusPreconditionRequired, models.NewErrorResponse(message, models.CodeRevisionRequired))
+}
+
+// SendFieldAuthorizationErrors sends a forbidden error response listing the
+// fields of the request the caller may not modify
+func SendFieldAuthorizationErrors(c *gin.Context, message string, fields []models.FieldAuthorizationError) {
+	resp := models.NewErrorResponse(message, models.CodeSectionLocked)
+	resp.Data = fields
+	c.JSON(http.StatusForbidden, resp)
+}
+
 // SendTooManyRequests sends a rate limit error response
 func SendTooManyRequests(c *gin.Context, message string) {
 	c.JSON(http.StatusTooManyRequests, models.NewErrorResponse(
@@ -174,10 +199,27 @@ func SendTooManyRequests(c *gin.Context, message string) {
 	))
 }
 
+// SendServiceUnavailable sends an overload error response asking the client to retry
+func SendServiceUnavailable(c *gin.Context, message string) {
+	c.Header("Retry-After", "5")
+	c.JSON(http.StatusServiceUnavailable, models.NewErrorResponse(
+		message,
+		models.CodeServiceBusy,
+	))
+}
+
 // SendInternalError sends an internal server error response
 func SendInternalError(c *gin.Context, err error) {
-	// Log the actual error for debugging
-	// Log error for debugging
+	// Errors caused by the request deadline are reported as timeouts
+	if c.Request.Context().Err() == context.DeadlineExceeded {
+		SendGatewayTimeout(c)
+		return
+	}
+	// Renders refused by the PDF render pool are retried by the client
+	if errors.Is(err, models.ErrPDFRenderBusy) {
+		SendServiceUnavailable(c, err.Error())
+		return
+	}
 
 	c.JSON(http.StatusInternalServerError, models.NewErrorResponse(
 		"An internal error occurred",
@@ -186,6 +228,24 @@ func SendInternalError(c *gin.Context, err error) {
 	))
 }
 
+// RequestIDKey is the context key holding the ID assigned to each request
+const RequestIDKey = "request_id"
+
+// GetRequestID returns the ID assigned to the request, if any
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
+
+// SendGatewayTimeout sends a timeout error response carrying the request ID
+func SendGatewayTimeout(c *gin.Context) {
+	resp := models.NewErrorResponse(
+		"The request took too long to complete",
+		models.CodeTimeout,
+	)
+	resp.RequestID = GetRequestID(c)
+	c.JSON(http.StatusGatewayTimeout, resp)
+}
+
 // SendValidationError sends a validation error response
 func SendValidationError(c *gin.Context, message string, err error) {
 	c.JSON(http.StatusBadRequest, gin.H{
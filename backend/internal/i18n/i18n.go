@@ -15,8 +15,35 @@ var frJSON []byte
 //go:embed locales/en.json
 var enJSON []byte
 
+// DefaultLanguage is used when no supported language is requested
+const DefaultLanguage = "fr"
+
+// SupportedLanguages lists the languages with translations
+var SupportedLanguages = []string{"fr", "en"}
+
+// IsSupported tells whether a language has translations
+func IsSupported(lang string) bool {
+	for _, supported := range SupportedLanguages {
+		if lang == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize returns the supported language of a code such as "en-US", the
+// default language otherwise
+func Normalize(lang string) string {
+	lang = strings.TrimSpace(strings.ToLower(strings.Split(lang, "-")[0]))
+	if IsSupported(lang) {
+		return lang
+	}
+	return DefaultLanguage
+}
+
 type I18n struct {
 	translations map[string]map[string]interface{}
+	messages     map[string]map[string]string // Translations flattened by dotted key, built once
 	defaultLang  string
 }
 
@@ -26,7 +53,7 @@ var instance *I18n
 func Initialize() error {
 	instance = &I18n{
 		translations: make(map[string]map[string]interface{}),
-		defaultLang:  "fr", // French as default
+		defaultLang:  DefaultLanguage,
 	}
 
 	// Load French translations
@@ -43,9 +70,31 @@ func Initialize() error {
 	}
 	instance.translations["en"] = enTranslations
 
+	instance.messages = make(map[string]map[string]string, len(instance.translations))
+	for lang, translations := range instance.translations {
+		messages := make(map[string]string)
+		flatten("", translations, messages)
+		instance.messages[lang] = messages
+	}
+
 	return nil
 }
 
+// flatten indexes the string translations of a nested bundle by dotted key
+func flatten(prefix string, translations map[string]interface{}, messages map[string]string) {
+	for key, value := range translations {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case string:
+			messages[key] = v
+		case map[string]interface{}:
+			flatten(key, v, messages)
+		}
+	}
+}
+
 // GetInstance returns the i18n instance
 func GetInstance() *I18n {
 	if instance == nil {
@@ -62,39 +111,24 @@ func T(lang, key string, args ...interface{}) string {
 // Translate translates a key for the given language
 func (i *I18n) Translate(lang, key string, args ...interface{}) string {
 	// Default to French if language not found
-	if _, ok := i.translations[lang]; !ok {
+	if _, ok := i.messages[lang]; !ok {
 		lang = i.defaultLang
 	}
 
-	// Get the translation map for the language
-	langMap := i.translations[lang]
-
-	// Navigate through nested keys (e.g., "auth.login.success")
-	keys := strings.Split(key, ".")
-	var value interface{} = langMap
-
-	for _, k := range keys {
-		if m, ok := value.(map[string]interface{}); ok {
-			value = m[k]
-		} else {
-			// Key not found, try fallback language
-			if lang != i.defaultLang {
-				return i.Translate(i.defaultLang, key, args...)
-			}
-			return key // Return the key itself if translation not found
+	// Nested keys (e.g., "auth.login.success") are looked up in the flattened bundle
+	str, ok := i.messages[lang][key]
+	if !ok {
+		// Key not found, try fallback language
+		if lang != i.defaultLang {
+			return i.Translate(i.defaultLang, key, args...)
 		}
+		return key // Return the key itself if translation not found
 	}
 
-	// Convert to string and format with arguments if provided
-	if str, ok := value.(string); ok {
-		if len(args) > 0 {
-			return fmt.Sprintf(str, args...)
-		}
-		return str
+	if len(args) > 0 {
+		return fmt.Sprintf(str, args...)
 	}
-
-	// If not a string, return the key
-	return key
+	return str
 }
 
 // GetLanguageFromContext extracts language from Gin context
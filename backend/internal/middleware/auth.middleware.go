@@ -11,15 +11,17 @@ import (
 
 // AuthMiddleware handles JWT authentication
 type AuthMiddleware struct {
-	jwtService  *services.JWTService
-	userService *services.UserService
+	jwtService    *services.JWTService
+	userService   *services.UserService
+	policyService *services.PolicyService
 }
 
 // NewAuthMiddleware creates a new auth middleware instance
-func NewAuthMiddleware(jwtService *services.JWTService, userService *services.UserService) *AuthMiddleware {
+func NewAuthMiddleware(jwtService *services.JWTService, userService *services.UserService, policyService *services.PolicyService) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService:  jwtService,
-		userService: userService,
+		jwtService:    jwtService,
+		userService:   userService,
+		policyService: policyService,
 	}
 }
 
@@ -118,6 +120,12 @@ func (am *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		c.Set("user_role", user.Role)
 		c.Set("claims", claims)
 
+		// Flag users who have not accepted the current version of the policies
+		if pending, err := am.policyService.PendingForUser(c.Request.Context(), user); err == nil && len(pending) > 0 {
+			c.Set(PolicyAcceptanceRequiredKey, true)
+			c.Header("X-Policy-Acceptance-Required", "true")
+		}
+
 		c.Next()
 	}
 }
@@ -244,6 +252,61 @@ func (am *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	}
 }
 
+// RequireDisplay middleware that accepts either a regular access token or a
+// read-only display token issued through the QR login flow. Display tokens
+// may only perform read (GET/HEAD) requests.
+func (am *AuthMiddleware) RequireDisplay() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := am.jwtService.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Authorization header is required",
+				"code":    "MISSING_AUTH_HEADER",
+			})
+			c.Abort()
+			return
+		}
+
+		// Regular users go through the standard authentication
+		claims, err := am.jwtService.ValidateDisplayToken(token)
+		if err != nil {
+			am.RequireAuth()(c)
+			return
+		}
+
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Display sessions are read-only",
+				"code":    "READ_ONLY_SESSION",
+			})
+			c.Abort()
+			return
+		}
+
+		// The approving user must still be allowed to log in
+		user, err := am.userService.GetUserByID(c.Request.Context(), claims.UserID)
+		if err != nil || !user.CanLogin() {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Display session is no longer valid",
+				"code":    "INVALID_TOKEN",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("user", user)
+		c.Set("user_id", user.ID)
+		c.Set("user_role", user.Role)
+		c.Set("claims", claims)
+		c.Set("read_only", true)
+
+		c.Next()
+	}
+}
+
 // Helper functions to extract user information from context
 
 // GetCurrentUser extracts the current user from the Gin context
@@ -273,6 +336,19 @@ func GetCurrentUserRole(c *gin.Context) (models.UserRole, bool) {
 	return role.(models.UserRole), true
 }
 
+// PolicyAcceptanceRequiredKey is the context key set when the user has policies to accept
+const PolicyAcceptanceRequiredKey = "policy_acceptance_required"
+
+// IsPolicyAcceptanceRequired reports whether the user still has to accept a published policy
+func IsPolicyAcceptanceRequired(c *gin.Context) bool {
+	return c.GetBool(PolicyAcceptanceRequiredKey)
+}
+
+// IsReadOnlySession reports whether the request was authenticated with a display token
+func IsReadOnlySession(c *gin.Context) bool {
+	return c.GetBool("read_only")
+}
+
 // GetCurrentClaims extracts the JWT claims from the Gin context
 func GetCurrentClaims(c *gin.Context) (*services.JWTCustomClaims, bool) {
 	claims, exists := c.Get("claims")
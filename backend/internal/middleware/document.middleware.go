@@ -205,16 +205,13 @@ func (m *DocumentMiddleware) hasAcceptedInvitation(ctx context.Context, docID, u
 	return true, nil
 }
 
-// isDocumentPublic checks if the document is in a public status (Approved or Archived)
+// isDocumentPublic checks if the document is in a public status (Approved, Archived or Review due)
 func (m *DocumentMiddleware) isDocumentPublic(ctx context.Context, docID primitive.ObjectID) (bool, error) {
 	var document models.Document
 	err := m.documentCollection.FindOne(ctx, bson.M{
 		"_id": docID,
 		"status": bson.M{
-			"$in": []models.DocumentStatus{
-				models.DocumentStatusApproved,
-				models.DocumentStatusArchived,
-			},
+			"$in": models.PublishedDocumentStatuses,
 		},
 	}).Decode(&document)
 
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// ModuleMiddleware enforces per-department module rollout
+type ModuleMiddleware struct {
+	rolloutService *services.ModuleRolloutService
+}
+
+// NewModuleMiddleware creates a new module middleware instance
+func NewModuleMiddleware(rolloutService *services.ModuleRolloutService) *ModuleMiddleware {
+	return &ModuleMiddleware{
+		rolloutService: rolloutService,
+	}
+}
+
+// RequireModule rejects requests from users whose department does not have the module enabled.
+// Must be used after RequireAuth.
+func (m *ModuleMiddleware) RequireModule(module models.ModuleKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := GetCurrentUser(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "User not found in context",
+				"code":    "UNAUTHORIZED",
+			})
+			c.Abort()
+			return
+		}
+
+		enabled, err := m.rolloutService.IsEnabledForUser(c.Request.Context(), module, user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Failed to verify module access",
+				"code":    "INTERNAL_ERROR",
+			})
+			c.Abort()
+			return
+		}
+
+		if !enabled {
+			fmt.Printf("Module %s not enabled for user %s\n", module, user.ID.Hex())
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("Module '%s' is not enabled for your department", module),
+				"code":    "MODULE_NOT_ENABLED",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("module", module)
+		c.Next()
+	}
+}
+
+// GetCurrentModule extracts the module checked by RequireModule from the Gin context
+func GetCurrentModule(c *gin.Context) (models.ModuleKey, bool) {
+	module, exists := c.Get("module")
+	if !exists {
+		return "", false
+	}
+	return module.(models.ModuleKey), true
+}
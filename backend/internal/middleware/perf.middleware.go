@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/services"
+)
+
+// PerfMiddleware records per-endpoint latencies when profiling is enabled
+type PerfMiddleware struct {
+	perfService *services.PerfService
+}
+
+// NewPerfMiddleware creates a new profiling middleware instance
+func NewPerfMiddleware(perfService *services.PerfService) *PerfMiddleware {
+	return &PerfMiddleware{
+		perfService: perfService,
+	}
+}
+
+// Track measures the handling time of each request, grouped by route pattern
+func (m *PerfMiddleware) Track() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.perfService.IsEnabled() {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		// Unmatched routes are grouped together instead of by raw URL
+		path := c.FullPath()
+		if path == "" {
+			path = "<unmatched>"
+		}
+		m.perfService.RecordRequest(c.Request.Method, path, c.Writer.Status(), time.Since(start))
+	}
+}
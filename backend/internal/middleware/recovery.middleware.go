@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery answers 500 to the requests whose handler panicked, like
+// gin.Recovery. http.ErrAbortHandler is passed on to net/http instead, so
+// that a handler can abort a response it already started, such as a
+// streamed export failing halfway, and the client sees the download fail.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, err any) {
+		if err == http.ErrAbortHandler {
+			panic(err)
+		}
+		fmt.Printf("❌ [PANIC] %s %s: %v\n%s\n", c.Request.Method, c.Request.URL.Path, err, debug.Stack())
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/helpers"
+)
+
+// RequestIDHeader carries the request ID from the client and back in the response
+const RequestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// defaultRouteTimeouts holds the budget of the route groups whose handlers
+// need more (or less) time than the default, keyed by route pattern prefix
+var defaultRouteTimeouts = map[string]time.Duration{
+	"/api/auth":                                 30 * time.Second, // OTP emails
+	"/api/invitations":                          30 * time.Second, // Invitation emails
+	"/api/notifications/admin/send":             30 * time.Second, // Push fan-out
+	"/api/activity-logs":                        30 * time.Second,
+	"/api/activity-logs/cleanup":                60 * time.Second,
+	"/api/activity-logs/export":                 10 * time.Minute, // Streamed exports
+	"/api/users/export":                         10 * time.Minute, // Streamed exports
+	"/api/actors/autocomplete":                  5 * time.Second,
+	"/api/actors/normalize":                     2 * time.Minute,
+	"/api/impact":                               30 * time.Second,
+	"/api/search":                               30 * time.Second,
+	"/api/chat":                                 2 * time.Minute, // OpenAI completions
+	"/api/documentation":                        2 * time.Minute,
+	"/api/macros/:id/export-pdf":                2 * time.Minute,
+	"/api/documents/export":                     10 * time.Minute, // Bulk PDF generation
+	"/api/documents/:id/publish":                2 * time.Minute,  // PDF generation
+	"/api/documents/:id/export-pdf":             2 * time.Minute,
+	"/api/documents/:id/signatures":             60 * time.Second,
+	"/api/documents/:id/comments/export":        60 * time.Second,
+	"/api/documents/:id/analytics":              15 * time.Second,
+	"/api/documents/:id/annexes/:annexId/files": 2 * time.Minute, // File uploads
+}
+
+type routeTimeout struct {
+	prefix  string
+	timeout time.Duration
+}
+
+// TimeoutMiddleware bounds the time spent handling each request. The deadline
+// is set on the request context so that it propagates into the services.
+type TimeoutMiddleware struct {
+	defaultTimeout time.Duration
+	routes         []routeTimeout // Longest prefix first
+}
+
+// NewTimeoutMiddleware creates a new timeout middleware instance.
+// REQUEST_TIMEOUT sets the default budget (10s) and REQUEST_TIMEOUT_ROUTES
+// overrides route groups, e.g. "/api/chat=3m,/api/documents=20s".
+func NewTimeoutMiddleware() *TimeoutMiddleware {
+	m := &TimeoutMiddleware{defaultTimeout: 10 * time.Second}
+	if d, err := time.ParseDuration(os.Getenv("REQUEST_TIMEOUT")); err == nil && d > 0 {
+		m.defaultTimeout = d
+	}
+
+	timeouts := make(map[string]time.Duration, len(defaultRouteTimeouts))
+	for prefix, d := range defaultRouteTimeouts {
+		timeouts[prefix] = d
+	}
+	if overrides := os.Getenv("REQUEST_TIMEOUT_ROUTES"); overrides != "" {
+		for _, entry := range strings.Split(overrides, ",") {
+			prefix, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+			d, err := time.ParseDuration(strings.TrimSpace(value))
+			if !found || err != nil || d <= 0 {
+				fmt.Printf("Warning: Ignoring invalid REQUEST_TIMEOUT_ROUTES entry %q\n", entry)
+				continue
+			}
+			timeouts[strings.TrimSpace(prefix)] = d
+		}
+	}
+
+	for prefix, d := range timeouts {
+		m.routes = append(m.routes, routeTimeout{prefix: prefix, timeout: d})
+	}
+	sort.Slice(m.routes, func(i, j int) bool {
+		return len(m.routes[i].prefix) > len(m.routes[j].prefix)
+	})
+
+	return m
+}
+
+// RequestID assigns an ID to each request, reusing the client's one when valid
+func (m *TimeoutMiddleware) RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			buf := make([]byte, 8)
+			_, _ = rand.Read(buf)
+			requestID = hex.EncodeToString(buf)
+		}
+
+		c.Set(helpers.RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// Timeout applies the budget of the matched route group to the request
+// context and answers 504 when the handler gives up after the deadline
+func (m *TimeoutMiddleware) Timeout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), m.timeoutFor(c.FullPath()))
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if ctx.Err() == context.DeadlineExceeded && !c.Writer.Written() {
+			helpers.SendGatewayTimeout(c)
+		}
+	}
+}
+
+// timeoutFor returns the budget of the longest route prefix matching the path
+func (m *TimeoutMiddleware) timeoutFor(path string) time.Duration {
+	for _, route := range m.routes {
+		if path == route.prefix || strings.HasPrefix(path, route.prefix+"/") {
+			return route.timeout
+		}
+	}
+	return m.defaultTimeout
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AccountDeletionStatus represents the lifecycle of a self-service account deletion
+type AccountDeletionStatus string
+
+const (
+	AccountDeletionPendingConfirmation AccountDeletionStatus = "pending_confirmation" // Waiting for the OTP
+	AccountDeletionScheduled           AccountDeletionStatus = "scheduled"            // Confirmed, waiting for the grace period to end
+	AccountDeletionCompleted           AccountDeletionStatus = "completed"            // Account anonymized
+	AccountDeletionCancelled           AccountDeletionStatus = "cancelled"
+)
+
+// AccountDeletionRequest represents a user's request to delete their account
+type AccountDeletionRequest struct {
+	ID           primitive.ObjectID    `json:"id" bson:"_id,omitempty"`
+	UserID       primitive.ObjectID    `json:"userId" bson:"user_id"`
+	Email        string                `json:"email" bson:"email"`
+	Name         string                `json:"name" bson:"name"`
+	Reason       string                `json:"reason,omitempty" bson:"reason,omitempty"`
+	Status       AccountDeletionStatus `json:"status" bson:"status"`
+	ConfirmedAt  *time.Time            `json:"confirmedAt,omitempty" bson:"confirmed_at,omitempty"`
+	ScheduledFor *time.Time            `json:"scheduledFor,omitempty" bson:"scheduled_for,omitempty"`
+	CompletedAt  *time.Time            `json:"completedAt,omitempty" bson:"completed_at,omitempty"`
+	CancelledAt  *time.Time            `json:"cancelledAt,omitempty" bson:"cancelled_at,omitempty"`
+	CancelledBy  *primitive.ObjectID   `json:"cancelledBy,omitempty" bson:"cancelled_by,omitempty"`
+	CreatedAt    time.Time             `json:"createdAt" bson:"created_at"`
+	UpdatedAt    time.Time             `json:"updatedAt" bson:"updated_at"`
+}
+
+// DeleteAccountRequest represents the request payload to start an account deletion
+type DeleteAccountRequest struct {
+	Reason string `json:"reason,omitempty" validate:"max=500"`
+}
+
+// ConfirmAccountDeletionRequest represents the request payload to confirm an account deletion
+type ConfirmAccountDeletionRequest struct {
+	OTP string `json:"otp" validate:"required,len=6"`
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AcknowledgmentCampaignStatus represents the status of an acknowledgment campaign
+type AcknowledgmentCampaignStatus string
+
+const (
+	AcknowledgmentCampaignActive AcknowledgmentCampaignStatus = "active"
+	AcknowledgmentCampaignClosed AcknowledgmentCampaignStatus = "closed" // No more acknowledgments are accepted
+)
+
+// AcknowledgmentStatus represents the read receipt of a recipient
+type AcknowledgmentStatus string
+
+const (
+	AcknowledgmentPending      AcknowledgmentStatus = "pending"
+	AcknowledgmentAcknowledged AcknowledgmentStatus = "acknowledged"
+)
+
+// IsValidAcknowledgmentStatus checks if an acknowledgment status is valid
+func IsValidAcknowledgmentStatus(status string) bool {
+	switch AcknowledgmentStatus(status) {
+	case AcknowledgmentPending, AcknowledgmentAcknowledged:
+		return true
+	}
+	return false
+}
+
+// AcknowledgmentCampaign asks the members of departments to confirm they have
+// read a published document
+type AcknowledgmentCampaign struct {
+	ID                primitive.ObjectID           `json:"id" bson:"_id,omitempty"`
+	DocumentID        primitive.ObjectID           `json:"documentId" bson:"document_id"`
+	Reference         string                       `json:"reference" bson:"reference"`
+	Title             string                       `json:"title" bson:"title"`
+	Version           string                       `json:"version" bson:"version"` // Version the recipients acknowledge
+	DepartmentIDs     []primitive.ObjectID         `json:"departmentIds" bson:"department_ids"`
+	Message           string                       `json:"message,omitempty" bson:"message,omitempty"`
+	DueDate           *time.Time                   `json:"dueDate,omitempty" bson:"due_date,omitempty"`
+	Status            AcknowledgmentCampaignStatus `json:"status" bson:"status"`
+	TotalRecipients   int                          `json:"totalRecipients" bson:"total_recipients"`
+	AcknowledgedCount int                          `json:"acknowledgedCount" bson:"acknowledged_count"`
+	CreatedBy         primitive.ObjectID           `json:"createdBy" bson:"created_by"`
+	CreatedAt         time.Time                    `json:"createdAt" bson:"created_at"`
+	UpdatedAt         time.Time                    `json:"updatedAt" bson:"updated_at"`
+	ClosedAt          *time.Time                   `json:"closedAt,omitempty" bson:"closed_at,omitempty"`
+}
+
+// CompletionPercent returns the share of recipients who acknowledged the document
+func (c *AcknowledgmentCampaign) CompletionPercent() float64 {
+	if c.TotalRecipients == 0 {
+		return 0
+	}
+	return float64(c.AcknowledgedCount) * 100 / float64(c.TotalRecipients)
+}
+
+// Acknowledgment is the read receipt of one recipient of a campaign
+type Acknowledgment struct {
+	ID             primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	CampaignID     primitive.ObjectID   `json:"campaignId" bson:"campaign_id"`
+	DocumentID     primitive.ObjectID   `json:"documentId" bson:"document_id"`
+	UserID         primitive.ObjectID   `json:"userId" bson:"user_id"`
+	DepartmentID   primitive.ObjectID   `json:"departmentId" bson:"department_id"`
+	Status         AcknowledgmentStatus `json:"status" bson:"status"`
+	AcknowledgedAt *time.Time           `json:"acknowledgedAt,omitempty" bson:"acknowledged_at,omitempty"`
+	CreatedAt      time.Time            `json:"createdAt" bson:"created_at"`
+}
+
+// CreateAcknowledgmentCampaignRequest represents the request to launch a campaign
+type CreateAcknowledgmentCampaignRequest struct {
+	DepartmentIDs []string   `json:"departmentIds" validate:"required,min=1,dive,required"`
+	Message       string     `json:"message" validate:"omitempty,max=1000"`
+	DueDate       *time.Time `json:"dueDate,omitempty"`
+}
+
+// AcknowledgmentCampaignResponse represents a campaign with its progress
+type AcknowledgmentCampaignResponse struct {
+	AcknowledgmentCampaign
+	PendingCount      int     `json:"pendingCount"`
+	CompletionPercent float64 `json:"completionPercent"`
+}
+
+// ToResponse converts a campaign to its response with progress
+func (c *AcknowledgmentCampaign) ToResponse() AcknowledgmentCampaignResponse {
+	return AcknowledgmentCampaignResponse{
+		AcknowledgmentCampaign: *c,
+		PendingCount:           c.TotalRecipients - c.AcknowledgedCount,
+		CompletionPercent:      c.CompletionPercent(),
+	}
+}
+
+// AcknowledgmentRecipientResponse represents a recipient and their read receipt
+type AcknowledgmentRecipientResponse struct {
+	UserID         string               `json:"userId"`
+	FirstName      string               `json:"firstName"`
+	LastName       string               `json:"lastName"`
+	Email          string               `json:"email"`
+	DepartmentID   string               `json:"departmentId"`
+	Status         AcknowledgmentStatus `json:"status"`
+	AcknowledgedAt *time.Time           `json:"acknowledgedAt,omitempty"`
+}
+
+// PendingAcknowledgmentResponse represents a document the current user still has to acknowledge
+type PendingAcknowledgmentResponse struct {
+	CampaignID string     `json:"campaignId"`
+	DocumentID string     `json:"documentId"`
+	Reference  string     `json:"reference"`
+	Title      string     `json:"title"`
+	Version    string     `json:"version"`
+	Message    string     `json:"message,omitempty"`
+	DueDate    *time.Time `json:"dueDate,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
@@ -28,6 +28,14 @@ const (
 	ActionUserAvatarUploaded ActivityAction = "user_avatar_uploaded"
 	ActionUserAvatarDeleted  ActivityAction = "user_avatar_deleted"
 
+	// Account Deletion Actions
+	ActionAccountDeletionScheduled ActivityAction = "account_deletion_scheduled"
+	ActionAccountDeletionCancelled ActivityAction = "account_deletion_cancelled"
+
+	// Policy Actions
+	ActionPolicyPublished ActivityAction = "policy_published"
+	ActionPolicyAccepted  ActivityAction = "policy_accepted"
+
 	// Department Management Actions
 	ActionDepartmentCreated ActivityAction = "department_created"
 	ActionDepartmentUpdated ActivityAction = "department_updated"
@@ -226,7 +234,8 @@ func GetCategoryFromAction(action ActivityAction) ActivityCategory {
 
 	case ActionUserRegistered, ActionUserApproved, ActionUserRejected, ActionUserActivated,
 		ActionUserDeactivated, ActionUserUpdated, ActionUserRoleChanged, ActionUserDeleted,
-		ActionUserAvatarUploaded, ActionUserAvatarDeleted:
+		ActionUserAvatarUploaded, ActionUserAvatarDeleted, ActionAccountDeletionScheduled,
+		ActionAccountDeletionCancelled, ActionPolicyAccepted:
 		return CategoryUser
 
 	case ActionDepartmentCreated, ActionDepartmentUpdated, ActionDepartmentDeleted:
@@ -243,7 +252,7 @@ func GetCategoryFromAction(action ActivityAction) ActivityCategory {
 		ActionProcessSubmitted, ActionProcessApproved, ActionProcessRejected:
 		return CategoryProcess
 
-	case ActionSystemMaintenance, ActionSystemBackup, ActionConfigUpdated:
+	case ActionSystemMaintenance, ActionSystemBackup, ActionConfigUpdated, ActionPolicyPublished:
 		return CategorySystem
 
 	default:
@@ -254,7 +263,8 @@ func GetCategoryFromAction(action ActivityAction) ActivityCategory {
 // GetLevelFromAction returns the appropriate level for an action
 func GetLevelFromAction(action ActivityAction) ActivityLevel {
 	switch action {
-	case ActionLoginFailed, ActionUserRejected, ActionUserDeleted:
+	case ActionLoginFailed, ActionUserRejected, ActionUserDeleted, ActionAccountDeletionScheduled,
+		ActionAccountDeletionCancelled:
 		return LevelWarning
 
 	case ActionUserLogin, ActionUserLogout, ActionUserRegistered, ActionUserApproved,
@@ -262,7 +272,8 @@ func GetLevelFromAction(action ActivityAction) ActivityLevel {
 		ActionUserAvatarUploaded, ActionUserAvatarDeleted, ActionDepartmentCreated,
 		ActionDepartmentUpdated, ActionDepartmentDeleted, ActionJobPositionCreated,
 		ActionJobPositionUpdated, ActionJobPositionDeleted, ActionTokenRefreshed,
-		ActionEmailVerified, ActionOTPRequested, ActionOTPVerified:
+		ActionEmailVerified, ActionOTPRequested, ActionOTPVerified, ActionPolicyPublished,
+		ActionPolicyAccepted:
 		return LevelAudit
 
 	case ActionDocumentCreated, ActionDocumentUpdated, ActionDocumentDeleted,
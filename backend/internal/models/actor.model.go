@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Actor represents an entry of the implicated actors registry
+// Documents reference actors by their canonical name so the same actor is
+// spelled identically across every procedure.
+type Actor struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Name           string              `bson:"name" json:"name"`         // Canonical name
+	NormalizedName string              `bson:"normalized_name" json:"-"` // Lowercased, accent-free name used for matching
+	Aliases        []string            `bson:"aliases" json:"aliases"`   // Alternative spellings resolved to the canonical name
+	NormalizedKeys []string            `bson:"normalized_keys" json:"-"` // Normalized name and aliases
+	Description    string              `bson:"description,omitempty" json:"description,omitempty"`
+	JobPositionID  *primitive.ObjectID `bson:"job_position_id,omitempty" json:"jobPositionId,omitempty"`
+	DepartmentID   *primitive.ObjectID `bson:"department_id,omitempty" json:"departmentId,omitempty"`
+	Active         bool                `bson:"active" json:"active"`
+	CreatedBy      primitive.ObjectID  `bson:"created_by" json:"createdBy"`
+	CreatedAt      time.Time           `bson:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updatedAt"`
+}
+
+// ActorSuggestion represents an autocomplete entry
+type ActorSuggestion struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	JobPositionID string `json:"jobPositionId,omitempty"`
+	DepartmentID  string `json:"departmentId,omitempty"`
+}
+
+// ToSuggestion converts an Actor to an autocomplete entry
+func (a *Actor) ToSuggestion() ActorSuggestion {
+	suggestion := ActorSuggestion{
+		ID:   a.ID.Hex(),
+		Name: a.Name,
+	}
+	if a.JobPositionID != nil {
+		suggestion.JobPositionID = a.JobPositionID.Hex()
+	}
+	if a.DepartmentID != nil {
+		suggestion.DepartmentID = a.DepartmentID.Hex()
+	}
+	return suggestion
+}
+
+// CreateActorRequest represents the request to register an actor
+type CreateActorRequest struct {
+	Name          string   `json:"name" binding:"required,min=2,max=100"`
+	Aliases       []string `json:"aliases"`
+	Description   string   `json:"description"`
+	JobPositionID *string  `json:"jobPositionId"`
+	DepartmentID  *string  `json:"departmentId"`
+}
+
+// UpdateActorRequest represents the request to update an actor
+type UpdateActorRequest struct {
+	Name          *string   `json:"name" binding:"omitempty,min=2,max=100"`
+	Aliases       *[]string `json:"aliases"`
+	Description   *string   `json:"description"`
+	JobPositionID *string   `json:"jobPositionId"`
+	DepartmentID  *string   `json:"departmentId"`
+	Active        *bool     `json:"active"`
+}
+
+// ActorFilter represents filtering options for actors
+type ActorFilter struct {
+	Search       *string
+	DepartmentID *primitive.ObjectID
+	Active       *bool
+	Page         int
+	Limit        int
+}
+
+// ActorStepMatch represents a process step where an actor is responsible
+type ActorStepMatch struct {
+	DocumentID  string `json:"documentId"`
+	ProcessCode string `json:"processCode,omitempty"`
+	Reference   string `json:"reference"`
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	GroupID     string `json:"groupId"`
+	GroupTitle  string `json:"groupTitle"`
+	StepID      string `json:"stepId"`
+	StepTitle   string `json:"stepTitle"`
+	StepOrder   int    `json:"stepOrder"`
+	Responsible string `json:"responsible"`
+}
+
+// ActorTaskMatch represents a task where a job position is an intervenant
+type ActorTaskMatch struct {
+	DocumentID  string `json:"documentId"`
+	ProcessCode string `json:"processCode,omitempty"`
+	Reference   string `json:"reference"`
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	TaskCode    string `json:"taskCode"`
+	Description string `json:"description"`
+}
+
+// ActorSearchResponse represents the procedures involving an actor or job position
+type ActorSearchResponse struct {
+	Actor         string           `json:"actor,omitempty"`
+	JobPositionID string           `json:"jobPositionId,omitempty"`
+	DocumentCount int              `json:"documentCount"`
+	Steps         []ActorStepMatch `json:"steps"`
+	Tasks         []ActorTaskMatch `json:"tasks"`
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DocumentView represents one consultation of a published document
+type DocumentView struct {
+	ID              primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	DocumentID      primitive.ObjectID  `json:"documentId" bson:"document_id"`
+	Version         string              `json:"version" bson:"version"`
+	UserID          primitive.ObjectID  `json:"userId" bson:"user_id"`
+	DepartmentID    *primitive.ObjectID `json:"departmentId,omitempty" bson:"department_id,omitempty"`
+	DurationSeconds int                 `json:"durationSeconds" bson:"duration_seconds"`
+	ViewedAt        time.Time           `json:"viewedAt" bson:"viewed_at"`
+}
+
+// RecordViewRequest represents a view reported by the client when the reader leaves the document
+type RecordViewRequest struct {
+	DurationSeconds int `json:"durationSeconds" binding:"min=0,max=86400"`
+}
+
+// ViewDurationBucket counts views whose duration falls within a range
+type ViewDurationBucket struct {
+	Label      string `json:"label"`
+	MinSeconds int    `json:"minSeconds"`
+	MaxSeconds int    `json:"maxSeconds,omitempty"` // Zero for the open-ended last bucket
+	Count      int64  `json:"count"`
+}
+
+// DepartmentViews aggregates the views of one department
+type DepartmentViews struct {
+	DepartmentID   *primitive.ObjectID `json:"departmentId,omitempty"` // Nil for the grouped small departments
+	DepartmentName string              `json:"departmentName"`
+	UniqueViewers  int64               `json:"uniqueViewers"`
+	Views          int64               `json:"views"`
+}
+
+// TopViewer represents an individual reader, only exposed to admins
+type TopViewer struct {
+	UserID               primitive.ObjectID `json:"userId"`
+	Name                 string             `json:"name"`
+	Views                int64              `json:"views"`
+	TotalDurationSeconds int64              `json:"totalDurationSeconds"`
+}
+
+// DocumentAnalyticsResponse represents the consultation statistics of a document
+type DocumentAnalyticsResponse struct {
+	DocumentID             primitive.ObjectID   `json:"documentId"`
+	From                   *time.Time           `json:"from,omitempty"`
+	TotalViews             int64                `json:"totalViews"`
+	UniqueViewers          int64                `json:"uniqueViewers"`
+	AverageDurationSeconds float64              `json:"averageDurationSeconds"`
+	DurationBuckets        []ViewDurationBucket `json:"durationBuckets"`
+	TopDepartments         []DepartmentViews    `json:"topDepartments"`
+	TopViewers             []TopViewer          `json:"topViewers,omitempty"`
+	MinGroupSize           int                  `json:"minGroupSize"` // Departments with fewer viewers are grouped together
+}
+
+// SearchSource identifies which search feature produced a search event
+type SearchSource string
+
+const (
+	SearchSourceDocuments SearchSource = "documents"
+	SearchSourceActor     SearchSource = "actor"
+)
+
+// SearchEvent represents an anonymized search: no user is recorded
+type SearchEvent struct {
+	ID                 primitive.ObjectID   `json:"id" bson:"_id"`
+	Query              string               `json:"query" bson:"query"` // Normalized query text
+	Source             SearchSource         `json:"source" bson:"source"`
+	ResultCount        int64                `json:"resultCount" bson:"result_count"`
+	Clicks             int64                `json:"clicks" bson:"clicks"`
+	ClickedDocumentIDs []primitive.ObjectID `json:"clickedDocumentIds,omitempty" bson:"clicked_document_ids,omitempty"`
+	CreatedAt          time.Time            `json:"createdAt" bson:"created_at"`
+}
+
+// RecordSearchClickRequest represents a click-through from a search result
+type RecordSearchClickRequest struct {
+	SearchID   string `json:"searchId" binding:"required"`
+	DocumentID string `json:"documentId" binding:"required"`
+}
+
+// SearchQueryStats aggregates the searches of one query
+type SearchQueryStats struct {
+	Query            string       `json:"query"`
+	Source           SearchSource `json:"source"`
+	Searches         int64        `json:"searches"`
+	AverageResults   float64      `json:"averageResults"`
+	ClickThroughRate float64      `json:"clickThroughRate"` // Share of searches followed by a click
+	LastSearchedAt   time.Time    `json:"lastSearchedAt"`
+}
+
+// ClickedDocumentStats counts the click-throughs to a document
+type ClickedDocumentStats struct {
+	DocumentID primitive.ObjectID `json:"documentId"`
+	Reference  string             `json:"reference"`
+	Title      string             `json:"title"`
+	Clicks     int64              `json:"clicks"`
+}
+
+// SearchAnalyticsResponse represents the search aggregates shown to admins
+type SearchAnalyticsResponse struct {
+	From                *time.Time             `json:"from,omitempty"`
+	TotalSearches       int64                  `json:"totalSearches"`
+	ZeroResultSearches  int64                  `json:"zeroResultSearches"`
+	ClickThroughRate    float64                `json:"clickThroughRate"`
+	TopQueries          []SearchQueryStats     `json:"topQueries"`
+	ZeroResultQueries   []SearchQueryStats     `json:"zeroResultQueries"`
+	TopClickedDocuments []ClickedDocumentStats `json:"topClickedDocuments"`
+}
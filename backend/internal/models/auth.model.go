@@ -35,6 +35,7 @@ type Step3RegistrationRequest struct {
 	Phone         string `json:"phone,omitempty"`
 	DepartmentID  string `json:"departmentId" validate:"required"`
 	JobPositionID string `json:"jobPositionId" validate:"required"`
+	Language      string `json:"language,omitempty" validate:"omitempty,oneof=fr en"`
 }
 
 // VerifyOTPRequest represents the request payload for OTP verification
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BrandingSettingsID is the key of the single branding settings document
+const BrandingSettingsID = "branding"
+
+// Branding represents the organization identity used in emails, PDFs and HTML views
+type Branding struct {
+	ID             string              `json:"-" bson:"_id"`
+	AppName        string              `json:"appName" bson:"app_name"`
+	CompanyName    string              `json:"companyName" bson:"company_name"`
+	HeaderLines    []string            `json:"headerLines" bson:"header_lines"` // Shown under the company name in the document header
+	Tagline        string              `json:"tagline" bson:"tagline"`
+	AddressLines   []string            `json:"addressLines" bson:"address_lines"`
+	Phone          string              `json:"phone" bson:"phone"`
+	ContactEmail   string              `json:"contactEmail" bson:"contact_email"`
+	Website        string              `json:"website" bson:"website"`
+	SupportEmail   string              `json:"supportEmail" bson:"support_email"`
+	FooterText     string              `json:"footerText" bson:"footer_text"` // Legal mentions appended to footers
+	LogoURL        string              `json:"logoUrl" bson:"logo_url"`
+	LogoVariants   map[string]string   `json:"logoVariants,omitempty" bson:"logo_variants,omitempty"` // Alternate logos chosen by the document headers
+	PrimaryColor   string              `json:"primaryColor" bson:"primary_color"`
+	SecondaryColor string              `json:"secondaryColor" bson:"secondary_color"`
+	UpdatedBy      *primitive.ObjectID `json:"updatedBy,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt      time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// DefaultBranding returns the branding used until an administrator configures one
+func DefaultBranding() *Branding {
+	return &Branding{
+		ID:          BrandingSettingsID,
+		AppName:     "Process Manager",
+		CompanyName: "TOGOCOM",
+		HeaderLines: []string{"TOGOCEL | TOGO TELECOM", "Filiales du Groupe Togocom"},
+		Tagline:     "Avancer. Pour vous. Pour Tous.",
+		AddressLines: []string{
+			"Place de la Réconciliation – (Quartier Atchanté)",
+			"Boîte postale : 333 – Lomé – Togo",
+		},
+		Phone:          "+228 22 53 44 01",
+		ContactEmail:   "spdgtgt@togotelecom.tg",
+		Website:        "togocom.tg",
+		SupportEmail:   "support@process-manager.com",
+		PrimaryColor:   "#FF9500",
+		SecondaryColor: "#2c3e50",
+	}
+}
+
+// UpdateBrandingRequest represents the request to update the branding settings
+type UpdateBrandingRequest struct {
+	AppName        *string            `json:"appName" validate:"omitempty,min=1,max=100"`
+	CompanyName    *string            `json:"companyName" validate:"omitempty,min=1,max=100"`
+	HeaderLines    *[]string          `json:"headerLines" validate:"omitempty,max=3,dive,max=100"`
+	Tagline        *string            `json:"tagline" validate:"omitempty,max=200"`
+	AddressLines   *[]string          `json:"addressLines" validate:"omitempty,max=3,dive,max=150"`
+	Phone          *string            `json:"phone" validate:"omitempty,max=50"`
+	ContactEmail   *string            `json:"contactEmail" validate:"omitempty,email"`
+	Website        *string            `json:"website" validate:"omitempty,max=200"`
+	SupportEmail   *string            `json:"supportEmail" validate:"omitempty,email"`
+	FooterText     *string            `json:"footerText" validate:"omitempty,max=500"`
+	PrimaryColor   *string            `json:"primaryColor" validate:"omitempty,hexcolor"`
+	SecondaryColor *string            `json:"secondaryColor" validate:"omitempty,hexcolor"`
+	LogoVariants   *map[string]string `json:"logoVariants" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,url"`
+}
+
+// Logo returns the URL of a logo variant, the main logo when the variant is unknown
+func (b *Branding) Logo(variant string) string {
+	if url, ok := b.LogoVariants[variant]; ok && variant != "" {
+		return url
+	}
+	return b.LogoURL
+}
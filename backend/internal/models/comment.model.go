@@ -0,0 +1,139 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CommentStatus represents the resolution state of a review comment
+type CommentStatus string
+
+const (
+	CommentStatusOpen     CommentStatus = "open"
+	CommentStatusResolved CommentStatus = "resolved"
+)
+
+// ErrBlockingCommentsOpen is returned when signing is gated by unresolved blocking comments
+var ErrBlockingCommentsOpen = errors.New("blocking comments must be resolved before signing")
+
+// CommentEdit represents a previous revision of a comment
+type CommentEdit struct {
+	Content  string    `json:"content" bson:"content"`
+	EditedAt time.Time `json:"editedAt" bson:"edited_at"`
+}
+
+// Comment represents a review comment on a document
+// Replies reference their thread root through ParentID; only roots carry
+// blocking and resolution state.
+type Comment struct {
+	ID          primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	DocumentID  primitive.ObjectID   `json:"documentId" bson:"document_id"`
+	ParentID    *primitive.ObjectID  `json:"parentId,omitempty" bson:"parent_id,omitempty"`
+	AuthorID    primitive.ObjectID   `json:"authorId" bson:"author_id"`
+	AuthorName  string               `json:"authorName" bson:"author_name"`
+	Content     string               `json:"content" bson:"content"`
+	Section     string               `json:"section,omitempty" bson:"section,omitempty"` // Linked document section, e.g. processGroups.<id>
+	Mentions    []primitive.ObjectID `json:"mentions,omitempty" bson:"mentions,omitempty"`
+	Blocking    bool                 `json:"blocking" bson:"blocking"` // Must be resolved before reviewers can sign
+	Status      CommentStatus        `json:"status" bson:"status"`
+	ResolvedBy  *primitive.ObjectID  `json:"resolvedBy,omitempty" bson:"resolved_by,omitempty"`
+	ResolvedAt  *time.Time           `json:"resolvedAt,omitempty" bson:"resolved_at,omitempty"`
+	EditHistory []CommentEdit        `json:"editHistory,omitempty" bson:"edit_history,omitempty"`
+	Deleted     bool                 `json:"deleted,omitempty" bson:"deleted,omitempty"` // Removed but kept to preserve replies
+	Reactions   *ReactionSummary     `json:"reactions,omitempty" bson:"-"`
+	CreatedAt   time.Time            `json:"createdAt" bson:"created_at"`
+	UpdatedAt   time.Time            `json:"updatedAt" bson:"updated_at"`
+}
+
+// CommentThread represents a root comment with its replies
+type CommentThread struct {
+	Comment
+	Replies []Comment `json:"replies"`
+}
+
+// CommentCounts summarizes the comments of a document
+type CommentCounts struct {
+	Total          int64 `json:"total"`
+	Open           int64 `json:"open"`
+	Resolved       int64 `json:"resolved"`
+	BlockingOpen   int64 `json:"blockingOpen"`
+	SigningBlocked bool  `json:"signingBlocked"` // Resolution gate is enabled and blocking comments are open
+}
+
+// CreateCommentRequest represents the request to comment on a document
+type CreateCommentRequest struct {
+	Content  string   `json:"content" binding:"required,min=1,max=5000"`
+	Section  string   `json:"section"`
+	Blocking bool     `json:"blocking"`
+	ParentID *string  `json:"parentId"` // Reply to an existing thread
+	Mentions []string `json:"mentions"` // IDs of @mentioned users
+}
+
+// UpdateCommentRequest represents the request to edit a comment
+type UpdateCommentRequest struct {
+	Content  string   `json:"content" binding:"required,min=1,max=5000"`
+	Mentions []string `json:"mentions"`
+}
+
+// DocumentSummaryResponse represents the review summary of a document
+type DocumentSummaryResponse struct {
+	DocumentID string         `json:"documentId"`
+	Reference  string         `json:"reference"`
+	Title      string         `json:"title"`
+	Version    string         `json:"version"`
+	Status     DocumentStatus `json:"status"`
+	Signatures struct {
+		Authors    SignatureProgress `json:"authors"`
+		Verifiers  SignatureProgress `json:"verifiers"`
+		Validators SignatureProgress `json:"validators"`
+	} `json:"signatures"`
+	Comments  CommentCounts `json:"comments"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// SignatureProgress represents how many contributors of a team have signed
+type SignatureProgress struct {
+	Signed   int `json:"signed"`
+	Required int `json:"required"`
+}
+
+// MentionableUser is the minimal user profile exposed to mention autocomplete
+type MentionableUser struct {
+	ID           primitive.ObjectID  `json:"id" bson:"_id"`
+	FirstName    string              `json:"firstName" bson:"first_name"`
+	LastName     string              `json:"lastName" bson:"last_name"`
+	Email        string              `json:"email" bson:"email"`
+	Avatar       string              `json:"avatar,omitempty" bson:"avatar,omitempty"`
+	DepartmentID *primitive.ObjectID `json:"departmentId,omitempty" bson:"department_id,omitempty"`
+}
+
+// CommentReportEntry is one line of the review comments report
+type CommentReportEntry struct {
+	ThreadNumber   int           `json:"threadNumber"`
+	IsReply        bool          `json:"isReply"`
+	AuthorName     string        `json:"authorName"`
+	Content        string        `json:"content"`
+	Section        string        `json:"section,omitempty"`
+	Blocking       bool          `json:"blocking"`
+	Status         CommentStatus `json:"status,omitempty"` // Only set on thread roots
+	ResolvedByName string        `json:"resolvedByName,omitempty"`
+	ResolvedAt     *time.Time    `json:"resolvedAt,omitempty"`
+	EditCount      int           `json:"editCount"`
+	Deleted        bool          `json:"deleted"`
+	CreatedAt      time.Time     `json:"createdAt"`
+}
+
+// CommentReport is the review evidence exported for auditors
+type CommentReport struct {
+	DocumentID  primitive.ObjectID   `json:"documentId"`
+	Reference   string               `json:"reference"`
+	Title       string               `json:"title"`
+	Version     string               `json:"version"`
+	Status      DocumentStatus       `json:"status"`
+	Counts      CommentCounts        `json:"counts"`
+	Entries     []CommentReportEntry `json:"entries"`
+	GeneratedAt time.Time            `json:"generatedAt"`
+	GeneratedBy string               `json:"generatedBy"`
+}
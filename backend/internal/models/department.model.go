@@ -20,6 +20,24 @@ type Department struct {
 	UpdatedAt   time.Time           `bson:"updated_at" json:"updatedAt"`
 	CreatedBy   primitive.ObjectID  `bson:"created_by,omitempty" json:"createdBy,omitempty"`
 	UpdatedBy   primitive.ObjectID  `bson:"updated_by,omitempty" json:"updatedBy,omitempty"`
+
+	// Default verification and validation teams of the documents created under the department
+	ContributorTemplate *ContributorTemplate `bson:"contributor_template,omitempty" json:"contributorTemplate,omitempty"`
+}
+
+// ContributorTemplateEntry designates a default contributor: a user, or every
+// active user holding a job position
+type ContributorTemplateEntry struct {
+	UserID        *primitive.ObjectID `bson:"user_id,omitempty" json:"userId,omitempty"`
+	JobPositionID *primitive.ObjectID `bson:"job_position_id,omitempty" json:"jobPositionId,omitempty"`
+}
+
+// ContributorTemplate lists the default verifiers and validators of a department.
+// When enforced, they are added even if the document creator picked other contributors.
+type ContributorTemplate struct {
+	Verifiers  []ContributorTemplateEntry `bson:"verifiers" json:"verifiers" validate:"dive"`
+	Validators []ContributorTemplateEntry `bson:"validators" json:"validators" validate:"dive"`
+	Enforced   bool                       `bson:"enforced" json:"enforced"`
 }
 
 // DepartmentResponse represents the API response for a department
@@ -34,6 +52,8 @@ type DepartmentResponse struct {
 	ManagerID   string    `json:"managerId,omitempty"`
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
+
+	ContributorTemplate *ContributorTemplate `json:"contributorTemplate,omitempty"`
 }
 
 // ToResponse converts Department to DepartmentResponse
@@ -46,6 +66,8 @@ func (d *Department) ToResponse() DepartmentResponse {
 		Active:      d.Active,
 		CreatedAt:   d.CreatedAt,
 		UpdatedAt:   d.UpdatedAt,
+
+		ContributorTemplate: d.ContributorTemplate,
 	}
 
 	if d.DomainID != nil {
@@ -0,0 +1,65 @@
+package models
+
+// DiagramShapeType represents the kind of a shape of a diagram annex
+type DiagramShapeType string
+
+const (
+	DiagramShapeRectangle DiagramShapeType = "rectangle"
+	DiagramShapeCircle    DiagramShapeType = "circle"
+	DiagramShapeHexagon   DiagramShapeType = "hexagon"
+	DiagramShapeDiamond   DiagramShapeType = "diamond" // Decision node
+	DiagramShapeArrow     DiagramShapeType = "arrow"
+	DiagramShapeConnector DiagramShapeType = "connector"
+	DiagramShapeSwimlane  DiagramShapeType = "swimlane"
+)
+
+// IsConnector tells whether the shape links two points or shapes
+func (t DiagramShapeType) IsConnector() bool {
+	return t == DiagramShapeArrow || t == DiagramShapeConnector
+}
+
+// ConnectorRouting represents how a connector is drawn between its ends
+type ConnectorRouting string
+
+const (
+	ConnectorRoutingStraight   ConnectorRouting = "straight"
+	ConnectorRoutingOrthogonal ConnectorRouting = "orthogonal"
+	ConnectorRoutingCurved     ConnectorRouting = "curved"
+)
+
+// DiagramPoint is a point of a diagram
+type DiagramPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// DiagramShape is one shape of a diagram annex, stored in its content under
+// "shapes". Nodes are placed with their top left corner and size, connectors
+// either link two nodes by ID or go from (x, y) to (endX, endY) through the
+// optional points. Swimlanes are drawn behind the other shapes.
+type DiagramShape struct {
+	ID          string           `json:"id,omitempty"`
+	Type        DiagramShapeType `json:"type"`
+	X           float64          `json:"x"`
+	Y           float64          `json:"y"`
+	Width       float64          `json:"width,omitempty"`
+	Height      float64          `json:"height,omitempty"`
+	Color       string           `json:"color,omitempty"`       // Fill of nodes, stroke of connectors
+	StrokeColor string           `json:"strokeColor,omitempty"` // Border of nodes and swimlanes
+	TextColor   string           `json:"textColor,omitempty"`
+	FontSize    float64          `json:"fontSize,omitempty"`
+	Label       string           `json:"label,omitempty"`
+
+	// Connectors only
+	EndX    float64          `json:"endX,omitempty"`
+	EndY    float64          `json:"endY,omitempty"`
+	From    string           `json:"from,omitempty"`
+	To      string           `json:"to,omitempty"`
+	Routing ConnectorRouting `json:"routing,omitempty"`
+	Points  []DiagramPoint   `json:"points,omitempty"`
+}
+
+// Center returns the center of a node
+func (s *DiagramShape) Center() DiagramPoint {
+	return DiagramPoint{X: s.X + s.Width/2, Y: s.Y + s.Height/2}
+}
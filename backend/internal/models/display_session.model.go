@@ -0,0 +1,90 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DisplaySessionStatus represents the state of a display login request
+type DisplaySessionStatus string
+
+const (
+	DisplaySessionStatusPending  DisplaySessionStatus = "pending"  // Waiting for a user to approve the code
+	DisplaySessionStatusApproved DisplaySessionStatus = "approved" // Approved, token can be collected by the display
+	DisplaySessionStatusDenied   DisplaySessionStatus = "denied"   // Rejected by the user
+)
+
+// Display session errors
+var (
+	ErrDisplayCodeNotFound = errors.New("display code not found or expired")
+	ErrDisplayCodeUsed     = errors.New("display code has already been used")
+)
+
+// DisplaySession represents a device-code login request stored in Redis
+type DisplaySession struct {
+	DeviceCode  string               `json:"deviceCode"`
+	UserCode    string               `json:"userCode"`
+	DisplayName string               `json:"displayName,omitempty"`
+	Status      DisplaySessionStatus `json:"status"`
+	ApprovedBy  *primitive.ObjectID  `json:"approvedBy,omitempty"`
+	ApprovedAt  *time.Time           `json:"approvedAt,omitempty"`
+	ExpiresAt   time.Time            `json:"expiresAt"`
+	CreatedAt   time.Time            `json:"createdAt"`
+}
+
+// ============================================
+// Display Session Request/Response Models
+// ============================================
+
+// DisplayCodeRequest represents a display asking for a login code
+type DisplayCodeRequest struct {
+	DisplayName string `json:"displayName,omitempty" validate:"omitempty,max=100"`
+}
+
+// DisplayCodeResponse is returned to the display so it can render the QR code and poll
+type DisplayCodeResponse struct {
+	DeviceCode      string `json:"deviceCode"`
+	UserCode        string `json:"userCode"`
+	VerificationURI string `json:"verificationUri"`
+	ExpiresIn       int    `json:"expiresIn"` // Seconds
+	Interval        int    `json:"interval"`  // Recommended polling interval in seconds
+}
+
+// ApproveDisplayRequest represents a logged-in user approving a display code
+type ApproveDisplayRequest struct {
+	UserCode string `json:"userCode" validate:"required"`
+	Approve  *bool  `json:"approve,omitempty"` // Defaults to true
+}
+
+// DisplayTokenRequest represents the display polling for its session token
+type DisplayTokenRequest struct {
+	DeviceCode string `json:"deviceCode" validate:"required"`
+}
+
+// DisplayTokenResponse represents the display polling result
+type DisplayTokenResponse struct {
+	Status      DisplaySessionStatus `json:"status"`
+	AccessToken string               `json:"accessToken,omitempty"`
+	ExpiresAt   *time.Time           `json:"expiresAt,omitempty"`
+	TokenType   string               `json:"tokenType,omitempty"`
+}
+
+// DisplayDocumentStatus is the compact document summary shown on wallboards
+type DisplayDocumentStatus struct {
+	ID          string         `json:"id"`
+	ProcessCode string         `json:"processCode,omitempty"`
+	Reference   string         `json:"reference"`
+	Title       string         `json:"title"`
+	Version     string         `json:"version"`
+	Status      DocumentStatus `json:"status"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+// DisplayDashboardResponse aggregates procedure statuses for a wallboard
+type DisplayDashboardResponse struct {
+	StatusCounts map[DocumentStatus]int64 `json:"statusCounts"`
+	Documents    []DisplayDocumentStatus  `json:"documents"`
+	GeneratedAt  time.Time                `json:"generatedAt"`
+}
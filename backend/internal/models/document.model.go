@@ -1,8 +1,13 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -18,8 +23,116 @@ const (
 	DocumentStatusValidatorReview DocumentStatus = "validator_review"
 	DocumentStatusApproved        DocumentStatus = "approved"
 	DocumentStatusArchived        DocumentStatus = "archived"
+	DocumentStatusReviewDue       DocumentStatus = "review_due" // Archived document whose periodic review date has passed
+	DocumentStatusSuperseded      DocumentStatus = "superseded" // Archived document replaced by an archived revision
 )
 
+// PublishedDocumentStatuses lists the statuses of documents visible to the whole organization.
+// Superseded versions stay visible for traceability.
+var PublishedDocumentStatuses = []DocumentStatus{
+	DocumentStatusApproved,
+	DocumentStatusArchived,
+	DocumentStatusReviewDue,
+	DocumentStatusSuperseded,
+}
+
+// IsPublished reports whether the status makes a document visible to the whole organization.
+// Published documents are locked against edits.
+func (s DocumentStatus) IsPublished() bool {
+	return s == DocumentStatusApproved || s == DocumentStatusArchived || s == DocumentStatusReviewDue ||
+		s == DocumentStatusSuperseded
+}
+
+// IsRevisable reports whether a revision can be drafted from a document with the status
+func (s DocumentStatus) IsRevisable() bool {
+	return s == DocumentStatusArchived || s == DocumentStatusReviewDue
+}
+
+// DocumentAvailability is the library badge of a published document
+type DocumentAvailability string
+
+const (
+	DocumentAvailabilityCurrent  DocumentAvailability = "current"  // In force
+	DocumentAvailabilityUpcoming DocumentAvailability = "upcoming" // Approved or not yet effective
+	DocumentAvailabilityExpired  DocumentAvailability = "expired"  // Past its supersession date
+)
+
+// IsValidDocumentAvailability checks if the availability is valid
+func IsValidDocumentAvailability(availability string) bool {
+	switch DocumentAvailability(availability) {
+	case DocumentAvailabilityCurrent, DocumentAvailabilityUpcoming, DocumentAvailabilityExpired:
+		return true
+	}
+	return false
+}
+
+// DocumentClassification is the confidentiality level printed on the PDFs of a document
+type DocumentClassification string
+
+const (
+	DocumentClassificationPublic       DocumentClassification = "public"
+	DocumentClassificationInternal     DocumentClassification = "internal"
+	DocumentClassificationConfidential DocumentClassification = "confidential"
+	DocumentClassificationRestricted   DocumentClassification = "restricted"
+)
+
+// IsValidDocumentClassification checks if the classification is valid, an
+// empty classification prints no banner
+func IsValidDocumentClassification(classification DocumentClassification) bool {
+	switch classification {
+	case "", DocumentClassificationPublic, DocumentClassificationInternal,
+		DocumentClassificationConfidential, DocumentClassificationRestricted:
+		return true
+	}
+	return false
+}
+
+// Label returns the banner text of the classification
+func (c DocumentClassification) Label() string {
+	switch c {
+	case DocumentClassificationPublic:
+		return "PUBLIC"
+	case DocumentClassificationInternal:
+		return "USAGE INTERNE / INTERNAL USE"
+	case DocumentClassificationConfidential:
+		return "CONFIDENTIEL / CONFIDENTIAL"
+	case DocumentClassificationRestricted:
+		return "DIFFUSION RESTREINTE / RESTRICTED"
+	}
+	return ""
+}
+
+// PDFExportOptions customizes the PDF exported for a document
+type PDFExportOptions struct {
+	Watermark   bool               // Stamp a draft watermark when the document is not approved yet
+	Archival    bool               // Produce a PDF/A file with embedded metadata for regulatory archiving
+	Force       bool               // Generate a new PDF even when the stored one is current
+	Snapshot    bool               // Render the snapshot of a version, stored with the versions
+	PageLayout  *PDFPageLayout     // Paper of this export only, the one of the document when nil
+	RequestedBy primitive.ObjectID // User the PDF is served to, zero for the platform
+}
+
+// PDFExportStage is a step of the generation of a PDF, reported to the
+// clients following an export
+type PDFExportStage string
+
+const (
+	PDFExportStageQueued           PDFExportStage = "queued" // Waiting for a free renderer
+	PDFExportStageRenderingHTML    PDFExportStage = "rendering_html"
+	PDFExportStageLaunchingBrowser PDFExportStage = "launching_browser"
+	PDFExportStagePrinting         PDFExportStage = "printing"
+	PDFExportStageUploading        PDFExportStage = "uploading"
+	PDFExportStageDone             PDFExportStage = "done"
+	PDFExportStageFailed           PDFExportStage = "failed"
+)
+
+// PDFExportProgress is an event of the PDF export stream
+type PDFExportProgress struct {
+	Stage  PDFExportStage `json:"stage"`
+	PdfURL string         `json:"pdfUrl,omitempty"` // Once done
+	Error  string         `json:"error,omitempty"`  // Once failed
+}
+
 // ContributorTeam represents the team a contributor belongs to
 type ContributorTeam string
 
@@ -59,6 +172,9 @@ type Contributor struct {
 	Status        SignatureStatus    `json:"status" bson:"status"`
 	SignatureDate *time.Time         `json:"signatureDate,omitempty" bson:"signature_date,omitempty"`
 	InvitedAt     time.Time          `json:"invitedAt" bson:"invited_at"`
+
+	// Captured signature, an image URL or data URL, set when rendering the PDF
+	SignatureImage string `json:"-" bson:"-"`
 }
 
 // Contributors represents all contributors of a document
@@ -68,6 +184,215 @@ type Contributors struct {
 	Validators []Contributor `json:"validators" bson:"validators"`
 }
 
+// Team returns the contributors of a team
+func (c *Contributors) Team(team ContributorTeam) []Contributor {
+	switch team {
+	case ContributorTeamAuthors:
+		return c.Authors
+	case ContributorTeamVerifiers:
+		return c.Verifiers
+	case ContributorTeamValidators:
+		return c.Validators
+	}
+	return nil
+}
+
+// PDFPageSize is a paper size of the generated PDFs
+type PDFPageSize string
+
+const (
+	PDFPageSizeA4 PDFPageSize = "A4"
+	PDFPageSizeA3 PDFPageSize = "A3"
+)
+
+// PDFPageOrientation is the orientation of the pages of the generated PDFs
+type PDFPageOrientation string
+
+const (
+	PDFPageOrientationPortrait  PDFPageOrientation = "portrait"
+	PDFPageOrientationLandscape PDFPageOrientation = "landscape"
+)
+
+// PDFPageLayout sets the paper of the PDFs of a document, wide process tables
+// being easier to read in landscape or on A3. Empty fields keep A4 portrait.
+type PDFPageLayout struct {
+	Size        PDFPageSize        `json:"size,omitempty" bson:"size,omitempty" validate:"omitempty,oneof=A4 A3"`
+	Orientation PDFPageOrientation `json:"orientation,omitempty" bson:"orientation,omitempty" validate:"omitempty,oneof=portrait landscape"`
+}
+
+// ParsePDFPageLayout reads a page layout from export parameters, nil when none is given
+func ParsePDFPageLayout(size, orientation string) (*PDFPageLayout, error) {
+	if size == "" && orientation == "" {
+		return nil, nil
+	}
+	layout := &PDFPageLayout{
+		Size:        PDFPageSize(strings.ToUpper(size)),
+		Orientation: PDFPageOrientation(strings.ToLower(orientation)),
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// Validate checks the paper size and orientation
+func (l *PDFPageLayout) Validate() error {
+	if l.Size != "" && l.Size != PDFPageSizeA4 && l.Size != PDFPageSizeA3 {
+		return errors.New("page size must be A4 or A3")
+	}
+	if l.Orientation != "" && l.Orientation != PDFPageOrientationPortrait && l.Orientation != PDFPageOrientationLandscape {
+		return errors.New("page orientation must be portrait or landscape")
+	}
+	return nil
+}
+
+// Override returns the layout with the fields set by another one replaced
+func (l *PDFPageLayout) Override(other *PDFPageLayout) *PDFPageLayout {
+	layout := PDFPageLayout{}
+	if l != nil {
+		layout = *l
+	}
+	if other != nil {
+		if other.Size != "" {
+			layout.Size = other.Size
+		}
+		if other.Orientation != "" {
+			layout.Orientation = other.Orientation
+		}
+	}
+	return &layout
+}
+
+// Landscape reports whether the pages are printed in landscape
+func (l *PDFPageLayout) Landscape() bool {
+	return l != nil && l.Orientation == PDFPageOrientationLandscape
+}
+
+// Dimensions returns the width and height of the pages in millimeters
+func (l *PDFPageLayout) Dimensions() (width, height float64) {
+	width, height = 210, 297
+	if l != nil && l.Size == PDFPageSizeA3 {
+		width, height = 297, 420
+	}
+	if l.Landscape() {
+		width, height = height, width
+	}
+	return width, height
+}
+
+// CSSSize returns the value of the CSS @page size property of the layout
+func (l *PDFPageLayout) CSSSize() string {
+	size, orientation := PDFPageSizeA4, PDFPageOrientationPortrait
+	if l != nil && l.Size != "" {
+		size = l.Size
+	}
+	if l.Landscape() {
+		orientation = PDFPageOrientationLandscape
+	}
+	return fmt.Sprintf("%s %s", size, orientation)
+}
+
+// ApprovalDeadlines sets the number of days each team has to sign once a
+// document is published to it. Zero uses the default deadline.
+type ApprovalDeadlines struct {
+	AuthorDays    int `json:"authorDays" bson:"author_days" validate:"min=0,max=365"`
+	VerifierDays  int `json:"verifierDays" bson:"verifier_days" validate:"min=0,max=365"`
+	ValidatorDays int `json:"validatorDays" bson:"validator_days" validate:"min=0,max=365"`
+}
+
+// StageDeadline tracks the signature deadline of the current review stage
+type StageDeadline struct {
+	Stage          DocumentStatus `json:"stage" bson:"stage"`
+	StartedAt      time.Time      `json:"startedAt" bson:"started_at"`
+	DueAt          time.Time      `json:"dueAt" bson:"due_at"`
+	LastReminderAt *time.Time     `json:"lastReminderAt,omitempty" bson:"last_reminder_at,omitempty"`
+	RemindersSent  int            `json:"remindersSent" bson:"reminders_sent"`
+	LastEmailAt    *time.Time     `json:"lastEmailAt,omitempty" bson:"last_email_at,omitempty"` // Last reminder emailed to the pending signers
+	InvitedAt      *time.Time     `json:"invitedAt,omitempty" bson:"invited_at,omitempty"`      // Set once the signers were emailed the calendar invite
+	EscalatedAt    *time.Time     `json:"escalatedAt,omitempty" bson:"escalated_at,omitempty"`  // Set once the owner and department managers were alerted
+}
+
+// UpdateSignatureRemindersRequest mutes or unmutes the scheduled signature
+// reminders of a document
+type UpdateSignatureRemindersRequest struct {
+	Muted *bool `json:"muted" binding:"required"`
+}
+
+// DocumentSection represents a part of a document that can be locked for editing
+type DocumentSection string
+
+const (
+	DocumentSectionMetadata      DocumentSection = "metadata"
+	DocumentSectionProcessGroups DocumentSection = "process_groups"
+	DocumentSectionAnnexes       DocumentSection = "annexes"
+)
+
+// IsValid checks if the section can be locked
+func (s DocumentSection) IsValid() bool {
+	switch s {
+	case DocumentSectionMetadata, DocumentSectionProcessGroups, DocumentSectionAnnexes:
+		return true
+	}
+	return false
+}
+
+// SectionLock restricts the edition of a section to some users and contributor teams.
+// The document owner and admins can always edit it.
+type SectionLock struct {
+	Section  DocumentSection      `json:"section" bson:"section"`
+	UserIDs  []primitive.ObjectID `json:"userIds,omitempty" bson:"user_ids,omitempty"`
+	Teams    []ContributorTeam    `json:"teams,omitempty" bson:"teams,omitempty"`
+	LockedBy primitive.ObjectID   `json:"lockedBy" bson:"locked_by"`
+	LockedAt time.Time            `json:"lockedAt" bson:"locked_at"`
+}
+
+// SectionLockRequest represents the request to lock a section of a document
+type SectionLockRequest struct {
+	UserIDs []string          `json:"userIds"`
+	Teams   []ContributorTeam `json:"teams" validate:"dive,oneof=authors verifiers validators"`
+}
+
+// FieldAuthorizationError reports a field of an update the caller may not modify
+type FieldAuthorizationError struct {
+	Field   string          `json:"field"`
+	Section DocumentSection `json:"section"`
+	Message string          `json:"message"`
+}
+
+// SectionAccessError is returned when an update touches locked sections
+type SectionAccessError struct {
+	Fields []FieldAuthorizationError
+}
+
+func (e *SectionAccessError) Error() string {
+	return "not allowed to modify locked document sections"
+}
+
+// CanEditSection reports whether a user may modify a section of the document.
+// Sections without a lock can be edited by anyone with write access.
+func (d *Document) CanEditSection(section DocumentSection, userID primitive.ObjectID, role UserRole) bool {
+	if role == RoleAdmin || d.CreatedBy == userID {
+		return true
+	}
+	for _, lock := range d.SectionLocks {
+		if lock.Section != section {
+			continue
+		}
+		if slices.Contains(lock.UserIDs, userID) {
+			return true
+		}
+		for _, team := range lock.Teams {
+			for _, contributor := range d.Contributors.Team(team) {
+				if contributor.UserID == userID {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return true
+}
+
 // ProcessDescription represents a single description within a process step
 type ProcessDescription struct {
 	Title         string   `json:"title" bson:"title"`
@@ -86,6 +411,7 @@ type ProcessStep struct {
 	Durations    []string             `json:"durations" bson:"durations"`
 	Responsible  string               `json:"responsible" bson:"responsible"`
 	Descriptions []ProcessDescription `json:"descriptions" bson:"descriptions"`
+	Snippet      *SnippetRef          `json:"snippet,omitempty" bson:"snippet,omitempty"` // Set on the steps embedded from a snippet
 }
 
 // ProcessGroup represents a major group of process steps
@@ -118,6 +444,14 @@ type Annex struct {
 	Files   []FileAttachment       `json:"files,omitempty" bson:"files,omitempty"`
 }
 
+// AnnexTable is the content of an annex exported to a spreadsheet
+type AnnexTable struct {
+	Reference string
+	Title     string
+	Header    []string
+	Rows      [][]string
+}
+
 // Task represents a single task within a process
 type Task struct {
 	Code         string               `json:"code" bson:"code"`                                     // M1_P1_T1, M1_P1_T2, etc.
@@ -127,6 +461,17 @@ type Task struct {
 	Intervenants []primitive.ObjectID `json:"intervenants,omitempty" bson:"intervenants,omitempty"` // Job position IDs responsible for this task
 }
 
+// DocumentReference represents a link from a document to another procedure version
+type DocumentReference struct {
+	DocumentID     primitive.ObjectID `json:"documentId" bson:"document_id"`
+	Reference      string             `json:"reference" bson:"reference"`
+	Title          string             `json:"title" bson:"title"`
+	Version        string             `json:"version" bson:"version"`                                    // Referenced version
+	Stale          bool               `json:"stale" bson:"stale"`                                        // Referenced version has been superseded
+	CurrentVersion string             `json:"currentVersion,omitempty" bson:"current_version,omitempty"` // Version that superseded it
+	StaleSince     *time.Time         `json:"staleSince,omitempty" bson:"stale_since,omitempty"`
+}
+
 // ChangeHistoryEntry represents a single change in the document history
 type ChangeHistoryEntry struct {
 	Version     string    `json:"version" bson:"version"`
@@ -137,63 +482,167 @@ type ChangeHistoryEntry struct {
 
 // DocumentMetadata represents the metadata section of a document
 type DocumentMetadata struct {
-	Objectives       []string             `json:"objectives" bson:"objectives"`
-	ImplicatedActors []string             `json:"implicatedActors" bson:"implicated_actors"`
-	ManagementRules  []string             `json:"managementRules" bson:"management_rules"`
-	Terminology      []string             `json:"terminology" bson:"terminology"`
-	ChangeHistory    []ChangeHistoryEntry `json:"changeHistory" bson:"change_history"`
+	Objectives       []string                `json:"objectives" bson:"objectives"`
+	ImplicatedActors []string                `json:"implicatedActors" bson:"implicated_actors"`
+	ManagementRules  []string                `json:"managementRules" bson:"management_rules"`
+	Terminology      []string                `json:"terminology" bson:"terminology"`
+	ChangeHistory    []ChangeHistoryEntry    `json:"changeHistory" bson:"change_history"`
+	CustomSections   []CustomMetadataSection `json:"customSections,omitempty" bson:"custom_sections,omitempty"`
+	Header           *DocumentHeader         `json:"header,omitempty" bson:"header,omitempty"` // Fields of the repeated PDF page header
+}
+
+// DocumentHeader customizes the page header of the document PDF, next to the
+// branding of the organization
+type DocumentHeader struct {
+	ProjectName string                `json:"projectName,omitempty" bson:"project_name,omitempty"`
+	Stamp       string                `json:"stamp,omitempty" bson:"stamp,omitempty"`              // Confidentiality stamp, such as "CONFIDENTIEL"
+	LogoVariant string                `json:"logoVariant,omitempty" bson:"logo_variant,omitempty"` // Branding logo variant, the main logo when unknown
+	Fields      []DocumentHeaderField `json:"fields,omitempty" bson:"fields,omitempty"`
+}
+
+// DocumentHeaderField is a labeled value of the PDF page header
+type DocumentHeaderField struct {
+	Label string `json:"label" bson:"label"`
+	Value string `json:"value" bson:"value"`
 }
 
 // Document represents a process document (Micro-processus)
 type Document struct {
-	ID               primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
-	MacroID          *primitive.ObjectID `json:"macroId,omitempty" bson:"macro_id,omitempty"`         // Link to Macro (M1, M2, etc.)
-	ProcessCode      string              `json:"processCode,omitempty" bson:"process_code,omitempty"` // New format: M1_P1, M2_P1, etc.
-	Reference        string              `json:"reference" bson:"reference"`                          // Legacy reference
-	Title            string              `json:"title" bson:"title"`
-	ShortDescription string              `json:"shortDescription,omitempty" bson:"short_description,omitempty"` // Brief description
-	Description      string              `json:"description,omitempty" bson:"description,omitempty"`            // Detailed description
-	IsActive         bool                `json:"isActive" bson:"is_active"`                                     // Active status
-	Stakeholders     []string            `json:"stakeholders" bson:"stakeholders"`                              // Implicated departments/stakeholders
-	Tasks            []Task              `json:"tasks" bson:"tasks"`                                            // Process tasks
-	Version          string              `json:"version" bson:"version"`
-	Status           DocumentStatus      `json:"status" bson:"status"`
-	CreatedBy        primitive.ObjectID  `json:"createdBy" bson:"created_by"`
-	Contributors     Contributors        `json:"contributors" bson:"contributors"`
-	Metadata         DocumentMetadata    `json:"metadata" bson:"metadata"`
-	ProcessGroups    []ProcessGroup      `json:"processGroups" bson:"process_groups"`
-	Annexes          []Annex             `json:"annexes" bson:"annexes"`
-	PdfUrl           string              `json:"pdfUrl,omitempty" bson:"pdf_url,omitempty"`
-	Order            int                 `json:"order" bson:"order"`
-	CreatedAt        time.Time           `json:"createdAt" bson:"created_at"`
-	UpdatedAt        time.Time           `json:"updatedAt" bson:"updated_at"`
-	ApprovedAt       *time.Time          `json:"approvedAt,omitempty" bson:"approved_at,omitempty"`
+	ID               primitive.ObjectID     `json:"id" bson:"_id,omitempty"`
+	MacroID          *primitive.ObjectID    `json:"macroId,omitempty" bson:"macro_id,omitempty"`         // Link to Macro (M1, M2, etc.)
+	ProcessCode      string                 `json:"processCode,omitempty" bson:"process_code,omitempty"` // New format: M1_P1, M2_P1, etc.
+	Reference        string                 `json:"reference" bson:"reference"`                          // Legacy reference
+	Title            string                 `json:"title" bson:"title"`
+	ShortDescription string                 `json:"shortDescription,omitempty" bson:"short_description,omitempty"` // Brief description
+	Description      string                 `json:"description,omitempty" bson:"description,omitempty"`            // Detailed description
+	IsActive         bool                   `json:"isActive" bson:"is_active"`                                     // Active status
+	Stakeholders     []string               `json:"stakeholders" bson:"stakeholders"`                              // Implicated departments/stakeholders
+	Tasks            []Task                 `json:"tasks" bson:"tasks"`                                            // Process tasks
+	Version          string                 `json:"version" bson:"version"`
+	Status           DocumentStatus         `json:"status" bson:"status"`
+	Classification   DocumentClassification `json:"classification,omitempty" bson:"classification,omitempty"` // Confidentiality banner of the PDFs
+	CreatedBy        primitive.ObjectID     `json:"createdBy" bson:"created_by"`
+	Contributors     Contributors           `json:"contributors" bson:"contributors"`
+	Metadata         DocumentMetadata       `json:"metadata" bson:"metadata"`
+	ProcessGroups    []ProcessGroup         `json:"processGroups" bson:"process_groups"`
+	Annexes          []Annex                `json:"annexes" bson:"annexes"`
+	References       []DocumentReference    `json:"references,omitempty" bson:"references,omitempty"` // Other procedures referenced by this one
+	PdfUrl           string                 `json:"pdfUrl,omitempty" bson:"pdf_url,omitempty"`
+	PDFHistory       []GeneratedPDF         `json:"-" bson:"pdf_history,omitempty"` // PDFs generated for the revisions and versions, oldest first
+	Order            int                    `json:"order" bson:"order"`
+	CreatedAt        time.Time              `json:"createdAt" bson:"created_at"`
+	UpdatedAt        time.Time              `json:"updatedAt" bson:"updated_at"`
+	ApprovedAt       *time.Time             `json:"approvedAt,omitempty" bson:"approved_at,omitempty"`
+	NextReviewDate   *time.Time             `json:"nextReviewDate,omitempty" bson:"next_review_date,omitempty"` // Periodic re-validation deadline of archived documents
+	LastReviewedAt   *time.Time             `json:"lastReviewedAt,omitempty" bson:"last_reviewed_at,omitempty"`
+	LastReviewedBy   *primitive.ObjectID    `json:"lastReviewedBy,omitempty" bson:"last_reviewed_by,omitempty"`
+	DeletedAt        *time.Time             `json:"deletedAt,omitempty" bson:"deleted_at,omitempty"` // Set when the document is moved to the trash
+	DeletedBy        *primitive.ObjectID    `json:"deletedBy,omitempty" bson:"deleted_by,omitempty"`
+	Deadlines        *ApprovalDeadlines     `json:"approvalDeadlines,omitempty" bson:"approval_deadlines,omitempty"`
+	PageLayout       *PDFPageLayout         `json:"pageLayout,omitempty" bson:"page_layout,omitempty"`       // Paper of the PDFs, A4 portrait when nil
+	StageDeadline    *StageDeadline         `json:"stageDeadline,omitempty" bson:"stage_deadline,omitempty"` // Deadline of the current author, verifier or validator review
+	Workflow         *WorkflowDefinition    `json:"workflow,omitempty" bson:"workflow,omitempty"`            // Overrides the workflow of the macro when set
+	LastRejection    *DocumentRejection     `json:"lastRejection,omitempty" bson:"last_rejection,omitempty"`
+	Revision         int64                  `json:"revision" bson:"revision"` // Incremented on every write, used for optimistic locking
+	SectionLocks     []SectionLock          `json:"sectionLocks,omitempty" bson:"section_locks,omitempty"`
+	Supersedes       *primitive.ObjectID    `json:"supersedes,omitempty" bson:"supersedes,omitempty"`      // Archived document this one revises
+	SupersededBy     *primitive.ObjectID    `json:"supersededBy,omitempty" bson:"superseded_by,omitempty"` // Archived revision replacing this one
+	SupersededAt     *time.Time             `json:"supersededAt,omitempty" bson:"superseded_at,omitempty"`
+
+	// Effective date at which an approved document is published to the organization
+	ScheduledPublishAt *time.Time          `json:"scheduledPublishAt,omitempty" bson:"scheduled_publish_at,omitempty"`
+	ScheduledPublishBy *primitive.ObjectID `json:"scheduledPublishBy,omitempty" bson:"scheduled_publish_by,omitempty"`
+
+	// Transition period of the published version. A superseded version stays
+	// in force until its supersession date.
+	EffectiveDate    *time.Time `json:"effectiveDate,omitempty" bson:"effective_date,omitempty"`
+	SupersessionDate *time.Time `json:"supersessionDate,omitempty" bson:"supersession_date,omitempty"`
+
+	// Review of the documents created in bulk, such as imported ones
+	Triage *DocumentTriage `json:"triage,omitempty" bson:"triage,omitempty"`
+
+	// Set by the owner to stop the scheduled signature reminders
+	SignatureRemindersMuted bool `json:"signatureRemindersMuted,omitempty" bson:"signature_reminders_muted,omitempty"`
+}
+
+// NotDeleted adds the condition excluding trashed documents to a document filter
+func NotDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+// InvalidatePDF adds the removal of the stored PDF, which no longer matches
+// the content of the document, to a document update
+func InvalidatePDF(update bson.M) bson.M {
+	unset, ok := update["$unset"].(bson.M)
+	if !ok {
+		unset = bson.M{}
+		update["$unset"] = unset
+	}
+	unset["pdf_url"] = ""
+	return update
+}
+
+// GeneratedPDF is a PDF stored for a document, kept once the document changes
+type GeneratedPDF struct {
+	Version     string    `json:"version" bson:"version"`
+	Revision    int64     `json:"revision" bson:"revision"` // Revision of the document the PDF was generated from
+	URL         string    `json:"url" bson:"url"`
+	GeneratedAt time.Time `json:"generatedAt" bson:"generated_at"`
 }
 
 // DocumentResponse represents the API response for a document
 type DocumentResponse struct {
-	ID               string           `json:"id"`
-	MacroID          string           `json:"macroId,omitempty"`
-	ProcessCode      string           `json:"processCode,omitempty"`
-	Reference        string           `json:"reference"`
-	Title            string           `json:"title"`
-	ShortDescription string           `json:"shortDescription,omitempty"`
-	Description      string           `json:"description,omitempty"`
-	IsActive         bool             `json:"isActive"`
-	Stakeholders     []string         `json:"stakeholders"`
-	Tasks            []Task           `json:"tasks"`
-	Version          string           `json:"version"`
-	Status           DocumentStatus   `json:"status"`
-	CreatedBy        string           `json:"createdBy"`
-	Contributors     Contributors     `json:"contributors"`
-	Metadata         DocumentMetadata `json:"metadata"`
-	ProcessGroups    []ProcessGroup   `json:"processGroups"`
-	Annexes          []Annex          `json:"annexes"`
-	PdfUrl           string           `json:"pdfUrl,omitempty"`
-	Order            int              `json:"order"`
-	CreatedAt        time.Time        `json:"createdAt"`
-	UpdatedAt        time.Time        `json:"updatedAt"`
-	ApprovedAt       *time.Time       `json:"approvedAt,omitempty"`
+	ID               string                 `json:"id"`
+	MacroID          string                 `json:"macroId,omitempty"`
+	ProcessCode      string                 `json:"processCode,omitempty"`
+	Reference        string                 `json:"reference"`
+	Title            string                 `json:"title"`
+	ShortDescription string                 `json:"shortDescription,omitempty"`
+	Description      string                 `json:"description,omitempty"`
+	IsActive         bool                   `json:"isActive"`
+	Stakeholders     []string               `json:"stakeholders"`
+	Tasks            []Task                 `json:"tasks"`
+	Version          string                 `json:"version"`
+	Status           DocumentStatus         `json:"status"`
+	Classification   DocumentClassification `json:"classification,omitempty"`
+	CreatedBy        string                 `json:"createdBy"`
+	Contributors     Contributors           `json:"contributors"`
+	Metadata         DocumentMetadata       `json:"metadata"`
+	ProcessGroups    []ProcessGroup         `json:"processGroups"`
+	Annexes          []Annex                `json:"annexes"`
+	References       []DocumentReference    `json:"references,omitempty"`
+	PdfUrl           string                 `json:"pdfUrl,omitempty"`
+	Order            int                    `json:"order"`
+	CreatedAt        time.Time              `json:"createdAt"`
+	UpdatedAt        time.Time              `json:"updatedAt"`
+	ApprovedAt       *time.Time             `json:"approvedAt,omitempty"`
+	NextReviewDate   *time.Time             `json:"nextReviewDate,omitempty"`
+	LastReviewedAt   *time.Time             `json:"lastReviewedAt,omitempty"`
+	LastReviewedBy   string                 `json:"lastReviewedBy,omitempty"`
+	DeletedAt        *time.Time             `json:"deletedAt,omitempty"`
+	DeletedBy        string                 `json:"deletedBy,omitempty"`
+	Deadlines        *ApprovalDeadlines     `json:"approvalDeadlines,omitempty"`
+	PageLayout       *PDFPageLayout         `json:"pageLayout,omitempty"`
+	StageDeadline    *StageDeadline         `json:"stageDeadline,omitempty"`
+	Workflow         *WorkflowDefinition    `json:"workflow,omitempty"`
+	LastRejection    *DocumentRejection     `json:"lastRejection,omitempty"`
+	Revision         int64                  `json:"revision"`
+	SectionLocks     []SectionLock          `json:"sectionLocks,omitempty"`
+	Supersedes       string                 `json:"supersedes,omitempty"`
+	SupersededBy     string                 `json:"supersededBy,omitempty"`
+	SupersededAt     *time.Time             `json:"supersededAt,omitempty"`
+
+	ScheduledPublishAt *time.Time `json:"scheduledPublishAt,omitempty"`
+	ScheduledPublishBy string     `json:"scheduledPublishBy,omitempty"`
+
+	EffectiveDate    *time.Time           `json:"effectiveDate,omitempty"`
+	SupersessionDate *time.Time           `json:"supersessionDate,omitempty"`
+	Availability     DocumentAvailability `json:"availability,omitempty"`
+
+	Triage *DocumentTriage `json:"triage,omitempty"`
+
+	SignatureRemindersMuted bool `json:"signatureRemindersMuted,omitempty"`
 }
 
 // ToResponse converts a Document to DocumentResponse
@@ -210,16 +659,39 @@ func (d *Document) ToResponse() DocumentResponse {
 		Tasks:            d.Tasks,
 		Version:          d.Version,
 		Status:           d.Status,
+		Classification:   d.Classification,
 		CreatedBy:        d.CreatedBy.Hex(),
 		Contributors:     d.Contributors,
 		Metadata:         d.Metadata,
 		ProcessGroups:    d.ProcessGroups,
 		Annexes:          d.Annexes,
+		References:       d.References,
 		PdfUrl:           d.PdfUrl,
 		Order:            d.Order,
 		CreatedAt:        d.CreatedAt,
 		UpdatedAt:        d.UpdatedAt,
 		ApprovedAt:       d.ApprovedAt,
+		NextReviewDate:   d.NextReviewDate,
+		LastReviewedAt:   d.LastReviewedAt,
+		DeletedAt:        d.DeletedAt,
+		Deadlines:        d.Deadlines,
+		PageLayout:       d.PageLayout,
+		StageDeadline:    d.StageDeadline,
+		Workflow:         d.Workflow,
+		LastRejection:    d.LastRejection,
+		Revision:         d.Revision,
+		SectionLocks:     d.SectionLocks,
+		SupersededAt:     d.SupersededAt,
+
+		ScheduledPublishAt: d.ScheduledPublishAt,
+
+		EffectiveDate:    d.EffectiveDate,
+		SupersessionDate: d.SupersessionDate,
+		Availability:     d.Availability(time.Now()),
+
+		Triage: d.Triage,
+
+		SignatureRemindersMuted: d.SignatureRemindersMuted,
 	}
 
 	// Include MacroID if present
@@ -227,43 +699,116 @@ func (d *Document) ToResponse() DocumentResponse {
 		resp.MacroID = d.MacroID.Hex()
 	}
 
+	if d.LastReviewedBy != nil {
+		resp.LastReviewedBy = d.LastReviewedBy.Hex()
+	}
+
+	if d.DeletedBy != nil {
+		resp.DeletedBy = d.DeletedBy.Hex()
+	}
+
+	if d.Supersedes != nil {
+		resp.Supersedes = d.Supersedes.Hex()
+	}
+
+	if d.SupersededBy != nil {
+		resp.SupersededBy = d.SupersededBy.Hex()
+	}
+
+	if d.ScheduledPublishBy != nil {
+		resp.ScheduledPublishBy = d.ScheduledPublishBy.Hex()
+	}
+
 	return resp
 }
 
+// Availability returns the library badge of the document at the given time,
+// or an empty value when the document is not published
+func (d *Document) Availability(now time.Time) DocumentAvailability {
+	switch {
+	case !d.Status.IsPublished():
+		return ""
+	case d.Status == DocumentStatusApproved:
+		return DocumentAvailabilityUpcoming
+	case d.EffectiveDate != nil && d.EffectiveDate.After(now):
+		return DocumentAvailabilityUpcoming
+	case d.SupersessionDate != nil && !d.SupersessionDate.After(now):
+		return DocumentAvailabilityExpired
+	case d.SupersessionDate == nil && d.Status == DocumentStatusSuperseded:
+		return DocumentAvailabilityExpired
+	}
+	return DocumentAvailabilityCurrent
+}
+
 // CreateDocumentRequest represents the request to create a document
 type CreateDocumentRequest struct {
-	MacroID          *string          `json:"macroId" binding:"required"` // Required: Link to macro
-	ProcessCode      string           `json:"processCode"`                // Optional: Auto-generated if not provided
-	Reference        string           `json:"reference"`                  // Optional: Legacy reference
-	Title            string           `json:"title" binding:"required"`
-	ShortDescription string           `json:"shortDescription"`
-	Description      string           `json:"description" binding:"required"`
-	IsActive         bool             `json:"isActive"`
-	Stakeholders     []string         `json:"stakeholders"`
-	Tasks            []Task           `json:"tasks" binding:"required,min=1"` // At least 1 task required
-	Version          string           `json:"version"`
-	Contributors     Contributors     `json:"contributors"`
-	Metadata         DocumentMetadata `json:"metadata"`
-	ProcessGroups    []ProcessGroup   `json:"processGroups"`
-	Annexes          []Annex          `json:"annexes"`
-	PdfUrl           string           `json:"pdfUrl"`
+	MacroID          *string                `json:"macroId" binding:"required"` // Required: Link to macro
+	ProcessCode      string                 `json:"processCode"`                // Optional: Auto-generated if not provided
+	Reference        string                 `json:"reference"`                  // Optional: Legacy reference
+	AutoReference    bool                   `json:"autoReference"`              // Generate the reference from the reference scheme
+	DepartmentID     string                 `json:"departmentId"`               // Department used by the scheme, defaults to the creator's
+	Title            string                 `json:"title" binding:"required"`
+	ShortDescription string                 `json:"shortDescription"`
+	Description      string                 `json:"description" binding:"required"`
+	IsActive         bool                   `json:"isActive"`
+	Stakeholders     []string               `json:"stakeholders"`
+	Tasks            []Task                 `json:"tasks" binding:"required,min=1"` // At least 1 task required
+	Version          string                 `json:"version"`
+	Contributors     Contributors           `json:"contributors"`
+	Metadata         DocumentMetadata       `json:"metadata"`
+	ProcessGroups    []ProcessGroup         `json:"processGroups"`
+	Annexes          []Annex                `json:"annexes"`
+	PdfUrl           string                 `json:"pdfUrl"`
+	EffectiveDate    *time.Time             `json:"effectiveDate"`    // Date the published version comes into force, defaults to the publication date
+	SupersessionDate *time.Time             `json:"supersessionDate"` // Date the published version stops being in force
+	Classification   DocumentClassification `json:"classification"`
+}
+
+// DuplicateDocumentRequest represents the optional settings of a duplication.
+// Unset options keep the content of the original.
+type DuplicateDocumentRequest struct {
+	Reference            string `json:"reference"` // Defaults to the original reference with a -COPY suffix
+	Title                string `json:"title"`     // Defaults to the original title with a (Copy) suffix
+	IncludeContributors  *bool  `json:"includeContributors"`
+	IncludeAnnexes       *bool  `json:"includeAnnexes"`
+	IncludeChangeHistory *bool  `json:"includeChangeHistory"`
+	ResetVersion         *bool  `json:"resetVersion"` // Restart at version 1.0, the default
+}
+
+// ReviseDocumentRequest represents the request to draft a revision of an archived document
+type ReviseDocumentRequest struct {
+	Version    string `json:"version" validate:"max=20"` // Optional: next minor version by default
+	ChangeNote string `json:"changeNote" validate:"max=500"`
 }
 
 // UpdateDocumentRequest represents the request to update a document
 type UpdateDocumentRequest struct {
-	Title            *string           `json:"title"`
-	ShortDescription *string           `json:"shortDescription"`
-	Description      *string           `json:"description"`
-	IsActive         *bool             `json:"isActive"`
-	Stakeholders     *[]string         `json:"stakeholders"`
-	Tasks            *[]Task           `json:"tasks"`
-	Version          *string           `json:"version"`
-	Status           *DocumentStatus   `json:"status"`
-	Contributors     *Contributors     `json:"contributors"`
-	Metadata         *DocumentMetadata `json:"metadata"`
-	ProcessGroups    *[]ProcessGroup   `json:"processGroups"`
-	Annexes          *[]Annex          `json:"annexes"`
-	IsAutosave       *bool             `json:"isAutosave"` // Skip activity logging for autosave operations
+	Title            *string                 `json:"title"`
+	ShortDescription *string                 `json:"shortDescription"`
+	Description      *string                 `json:"description"`
+	IsActive         *bool                   `json:"isActive"`
+	Stakeholders     *[]string               `json:"stakeholders"`
+	Tasks            *[]Task                 `json:"tasks"`
+	Version          *string                 `json:"version"`
+	Status           *DocumentStatus         `json:"status"`
+	Contributors     *Contributors           `json:"contributors"`
+	Metadata         *DocumentMetadata       `json:"metadata"`
+	ProcessGroups    *[]ProcessGroup         `json:"processGroups"`
+	Annexes          *[]Annex                `json:"annexes"`
+	References       *[]DocumentReference    `json:"references"`
+	EffectiveDate    *time.Time              `json:"effectiveDate"`
+	SupersessionDate *time.Time              `json:"supersessionDate"`
+	Classification   *DocumentClassification `json:"classification"`
+	PageLayout       *PDFPageLayout          `json:"pageLayout"` // Paper of the PDFs, an empty layout restores A4 portrait
+	IsAutosave       *bool                   `json:"isAutosave"` // Write the changes to the draft of the user instead of the document
+	Revision         *int64                  `json:"revision"`   // Revision the changes are based on, the If-Match header can be used instead
+}
+
+// UpdateEffectiveDatesRequest replaces the transition period of a document,
+// a missing date is cleared
+type UpdateEffectiveDatesRequest struct {
+	EffectiveDate    *time.Time `json:"effectiveDate"`
+	SupersessionDate *time.Time `json:"supersessionDate"`
 }
 
 // DocumentFilter represents filtering options for documents
@@ -271,16 +816,122 @@ type DocumentFilter struct {
 	Status    *DocumentStatus `json:"status"`
 	CreatedBy *string         `json:"createdBy"`
 	Search    *string         `json:"search"`
+	Sort      *DocumentSort   `json:"sort"` // Defaults to the most recently updated first
 	Page      int             `json:"page"`
 	Limit     int             `json:"limit"`
 }
 
+// DocumentSortField is a sort key of the document lists
+type DocumentSortField string
+
+const (
+	DocumentSortUpdatedAt         DocumentSortField = "updatedAt"
+	DocumentSortCreatedAt         DocumentSortField = "createdAt"
+	DocumentSortTitle             DocumentSortField = "title"
+	DocumentSortReference         DocumentSortField = "reference"
+	DocumentSortStatus            DocumentSortField = "status"
+	DocumentSortSignatureProgress DocumentSortField = "signatureProgress" // Share of contributors who signed
+)
+
+// DocumentSort represents the requested order of a document list
+type DocumentSort struct {
+	Field      DocumentSortField `json:"field"`
+	Descending bool              `json:"descending"`
+}
+
+// ParseDocumentSort validates the sortBy and sortDir query parameters. It
+// returns nil when no sort is requested. Dates and signature progress sort
+// descending by default, the other fields ascending.
+func ParseDocumentSort(sortBy, sortDir string) (*DocumentSort, error) {
+	if sortBy == "" {
+		if sortDir != "" {
+			return nil, errors.New("sortDir requires sortBy")
+		}
+		return nil, nil
+	}
+
+	sort := &DocumentSort{Field: DocumentSortField(sortBy)}
+	switch sort.Field {
+	case DocumentSortUpdatedAt, DocumentSortCreatedAt, DocumentSortSignatureProgress:
+		sort.Descending = true
+	case DocumentSortTitle, DocumentSortReference, DocumentSortStatus:
+	default:
+		return nil, errors.New("invalid sortBy: must be updatedAt, createdAt, title, reference, status or signatureProgress")
+	}
+
+	switch sortDir {
+	case "":
+	case "asc":
+		sort.Descending = false
+	case "desc":
+		sort.Descending = true
+	default:
+		return nil, errors.New("invalid sortDir: must be asc or desc")
+	}
+	return sort, nil
+}
+
+// PublishDocumentRequest represents the optional settings of a publish request
+type PublishDocumentRequest struct {
+	Deadlines          *ApprovalDeadlines `json:"approvalDeadlines,omitempty"`  // Replaces the signature deadlines of the document when set
+	ScheduledPublishAt *time.Time         `json:"scheduledPublishAt,omitempty"` // Defers the publication of an approved document to this date
+}
+
+// SchedulePublishRequest represents the request to publish an approved
+// document at a given date, such as a shift change
+type SchedulePublishRequest struct {
+	PublishAt time.Time `json:"publishAt" validate:"required"`
+}
+
+// RejectDocumentRequest represents the rejection of a document by a verifier or validator
+type RejectDocumentRequest struct {
+	Reason string         `json:"reason" validate:"required,min=3,max=2000"`
+	Target DocumentStatus `json:"target" validate:"omitempty,oneof=draft author_review"` // Status the document returns to, author_review by default
+}
+
+// DocumentRejection records the last return of a document to its authors
+type DocumentRejection struct {
+	Reason         string             `json:"reason" bson:"reason"`
+	FromStatus     DocumentStatus     `json:"fromStatus" bson:"from_status"`
+	ToStatus       DocumentStatus     `json:"toStatus" bson:"to_status"`
+	Team           ContributorTeam    `json:"team" bson:"team"`
+	RejectedBy     primitive.ObjectID `json:"rejectedBy" bson:"rejected_by"`
+	RejectedByName string             `json:"rejectedByName" bson:"rejected_by_name"`
+	RejectedAt     time.Time          `json:"rejectedAt" bson:"rejected_at"`
+}
+
+// BulkExportRequest selects the documents bundled into a ZIP export.
+// Explicit document IDs take precedence over the filter criteria.
+type BulkExportRequest struct {
+	DocumentIDs  []string        `json:"documentIds,omitempty"`
+	Status       *DocumentStatus `json:"status,omitempty"`
+	MacroID      string          `json:"macroId,omitempty"`
+	DepartmentID string          `json:"departmentId,omitempty"` // Documents created or authored by members of the department
+	Search       string          `json:"search,omitempty"`
+}
+
+// BulkExportSkipped represents a document left out of an export
+type BulkExportSkipped struct {
+	DocumentID string `json:"documentId"`
+	Reference  string `json:"reference,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+// BulkExportResponse represents a generated ZIP export
+type BulkExportResponse struct {
+	DownloadURL   string              `json:"downloadUrl"`
+	FileName      string              `json:"fileName"`
+	DocumentCount int                 `json:"documentCount"`
+	Skipped       []BulkExportSkipped `json:"skipped"`
+}
+
 // UpdateMetadataRequest represents the request to update document metadata
 type UpdateMetadataRequest struct {
-	Objectives       *[]string `json:"objectives"`
-	ImplicatedActors *[]string `json:"implicatedActors"`
-	ManagementRules  *[]string `json:"managementRules"`
-	Terminology      *[]string `json:"terminology"`
+	Objectives       *[]string                `json:"objectives"`
+	ImplicatedActors *[]string                `json:"implicatedActors"`
+	ManagementRules  *[]string                `json:"managementRules"`
+	Terminology      *[]string                `json:"terminology"`
+	CustomSections   *[]CustomMetadataSection `json:"customSections"`
 }
 
 // CreateAnnexRequest represents the request to create an annex
@@ -298,6 +949,50 @@ type UpdateAnnexRequest struct {
 	Order   *int                    `json:"order"`
 }
 
+// AttachMacroRequest represents the request to move a document to a macro
+type AttachMacroRequest struct {
+	MacroID string `json:"macroId" binding:"required"`
+}
+
+// ReorderAnnexesRequest represents the new order of the annexes of a document
+type ReorderAnnexesRequest struct {
+	AnnexIDs []string `json:"annexIds" binding:"required,min=1"` // Every annex of the document, in the new order
+}
+
+// ReorderStepsRequest represents the new order of the steps of a process group
+type ReorderStepsRequest struct {
+	StepIDs []string `json:"stepIds" binding:"required,min=1"` // Every step of the group, in the new order
+}
+
+// CreateStepRequest represents a step to add to a process group
+type CreateStepRequest struct {
+	ID           string               `json:"id"`       // Optional: Generated if not provided
+	Position     *int                 `json:"position"` // Optional: 1-based position, the step is appended if not provided
+	Title        string               `json:"title" binding:"required"`
+	Outputs      []string             `json:"outputs"`
+	Durations    []string             `json:"durations"`
+	Responsible  string               `json:"responsible"`
+	Descriptions []ProcessDescription `json:"descriptions"`
+}
+
+// UpdateStepRequest represents the changes to a step of a process group
+type UpdateStepRequest struct {
+	ID           string                `json:"id" binding:"required"`
+	Title        *string               `json:"title"`
+	Outputs      *[]string             `json:"outputs"`
+	Durations    *[]string             `json:"durations"`
+	Responsible  *string               `json:"responsible"`
+	Descriptions *[]ProcessDescription `json:"descriptions"`
+}
+
+// BulkStepsRequest creates, updates and deletes steps of a process group at once.
+// Deletions are applied first, then updates, then creations.
+type BulkStepsRequest struct {
+	Create []CreateStepRequest `json:"create" binding:"dive"`
+	Update []UpdateStepRequest `json:"update" binding:"dive"`
+	Delete []string            `json:"delete"`
+}
+
 // ReorderProcessesRequest represents the request to reorder processes
 type ReorderProcessesRequest struct {
 	ProcessIDs []string `json:"processIds" binding:"required"`
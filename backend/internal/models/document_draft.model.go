@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DocumentDraft is the autosave buffer of a contributor on a document. The
+// autosaved changes stay out of the document, which other contributors keep
+// seeing as reviewed, until the draft is committed.
+type DocumentDraft struct {
+	ID           primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	DocumentID   primitive.ObjectID   `json:"documentId" bson:"document_id"`
+	UserID       primitive.ObjectID   `json:"userId" bson:"user_id"`
+	BaseRevision int64                `json:"baseRevision" bson:"base_revision"` // Document revision the draft was started from
+	Changes      DocumentDraftChanges `json:"changes" bson:"changes"`
+	Stale        bool                 `json:"stale" bson:"-"` // The document was modified since the draft was started
+	CreatedAt    time.Time            `json:"createdAt" bson:"created_at"`
+	UpdatedAt    time.Time            `json:"updatedAt" bson:"updated_at"`
+}
+
+// DocumentDraftChanges are the content changes of a draft, unset fields keep
+// the content of the document
+type DocumentDraftChanges struct {
+	Title            *string              `json:"title,omitempty" bson:"title,omitempty"`
+	ShortDescription *string              `json:"shortDescription,omitempty" bson:"short_description,omitempty"`
+	Description      *string              `json:"description,omitempty" bson:"description,omitempty"`
+	IsActive         *bool                `json:"isActive,omitempty" bson:"is_active,omitempty"`
+	Stakeholders     *[]string            `json:"stakeholders,omitempty" bson:"stakeholders,omitempty"`
+	Tasks            *[]Task              `json:"tasks,omitempty" bson:"tasks,omitempty"`
+	Version          *string              `json:"version,omitempty" bson:"version,omitempty"`
+	Metadata         *DocumentMetadata    `json:"metadata,omitempty" bson:"metadata,omitempty"`
+	ProcessGroups    *[]ProcessGroup      `json:"processGroups,omitempty" bson:"process_groups,omitempty"`
+	Annexes          *[]Annex             `json:"annexes,omitempty" bson:"annexes,omitempty"`
+	References       *[]DocumentReference `json:"references,omitempty" bson:"references,omitempty"`
+	EffectiveDate    *time.Time           `json:"effectiveDate,omitempty" bson:"effective_date,omitempty"`
+	SupersessionDate *time.Time           `json:"supersessionDate,omitempty" bson:"supersession_date,omitempty"`
+}
+
+// NewDocumentDraftChanges keeps the content changes of an update request.
+// Status and contributor changes are workflow operations, not autosaved.
+func NewDocumentDraftChanges(req *UpdateDocumentRequest) DocumentDraftChanges {
+	return DocumentDraftChanges{
+		Title:            req.Title,
+		ShortDescription: req.ShortDescription,
+		Description:      req.Description,
+		IsActive:         req.IsActive,
+		Stakeholders:     req.Stakeholders,
+		Tasks:            req.Tasks,
+		Version:          req.Version,
+		Metadata:         req.Metadata,
+		ProcessGroups:    req.ProcessGroups,
+		Annexes:          req.Annexes,
+		References:       req.References,
+		EffectiveDate:    req.EffectiveDate,
+		SupersessionDate: req.SupersessionDate,
+	}
+}
+
+// ToUpdateRequest converts the changes of a draft into the update applying them
+func (c *DocumentDraftChanges) ToUpdateRequest() *UpdateDocumentRequest {
+	return &UpdateDocumentRequest{
+		Title:            c.Title,
+		ShortDescription: c.ShortDescription,
+		Description:      c.Description,
+		IsActive:         c.IsActive,
+		Stakeholders:     c.Stakeholders,
+		Tasks:            c.Tasks,
+		Version:          c.Version,
+		Metadata:         c.Metadata,
+		ProcessGroups:    c.ProcessGroups,
+		Annexes:          c.Annexes,
+		References:       c.References,
+		EffectiveDate:    c.EffectiveDate,
+		SupersessionDate: c.SupersessionDate,
+	}
+}
+
+// CommitDocumentDraftRequest represents the options of a draft commit
+type CommitDocumentDraftRequest struct {
+	Force bool `json:"force"` // Apply the draft even if the document was modified since it was started
+}
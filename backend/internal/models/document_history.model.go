@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// DocumentHistorySource identifies the record an history entry comes from
+type DocumentHistorySource string
+
+const (
+	DocumentHistorySourceActivity   DocumentHistorySource = "activity"
+	DocumentHistorySourceVersion    DocumentHistorySource = "version"
+	DocumentHistorySourceSignature  DocumentHistorySource = "signature"
+	DocumentHistorySourceInvitation DocumentHistorySource = "invitation"
+)
+
+// IsValidDocumentHistorySource checks if the source is valid
+func IsValidDocumentHistorySource(source string) bool {
+	switch DocumentHistorySource(source) {
+	case DocumentHistorySourceActivity, DocumentHistorySourceVersion,
+		DocumentHistorySourceSignature, DocumentHistorySourceInvitation:
+		return true
+	}
+	return false
+}
+
+// DocumentHistoryActor describes the user behind an history entry
+type DocumentHistoryActor struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Email  string `json:"email,omitempty"`
+	Avatar string `json:"avatar,omitempty"`
+}
+
+// DocumentHistoryEntry is a single event of the lifecycle of a document
+type DocumentHistoryEntry struct {
+	Timestamp   time.Time              `json:"timestamp"`
+	Source      DocumentHistorySource  `json:"source"`
+	Event       string                 `json:"event"`
+	Description string                 `json:"description"`
+	Actor       *DocumentHistoryActor  `json:"actor,omitempty"`
+	Version     string                 `json:"version,omitempty"`
+	RecordID    string                 `json:"recordId"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+}
+
+// DocumentHistoryFilter narrows the timeline of a document
+type DocumentHistoryFilter struct {
+	Sources  []DocumentHistorySource
+	DateFrom *time.Time
+	DateTo   *time.Time
+	Desc     bool
+}
@@ -0,0 +1,34 @@
+package models
+
+// DocumentImportRowError reports an invalid cell or row of an import spreadsheet.
+// Rows are numbered like in the spreadsheet, the header being row 1.
+type DocumentImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// DocumentImportItem represents a draft document read from an import spreadsheet
+type DocumentImportItem struct {
+	FirstRow   int    `json:"firstRow"`
+	LastRow    int    `json:"lastRow"`
+	Reference  string `json:"reference,omitempty"` // Empty when generated on creation
+	Title      string `json:"title"`
+	MacroCode  string `json:"macroCode"`
+	GroupCount int    `json:"groupCount"`
+	StepCount  int    `json:"stepCount"`
+	TaskCount  int    `json:"taskCount"`
+	DocumentID string `json:"documentId,omitempty"` // Set once created
+	Error      string `json:"error,omitempty"`      // Creation failure
+}
+
+// DocumentImportResult summarizes an import. Documents are only created when
+// no row has an error; a dry run validates the file without creating anything.
+type DocumentImportResult struct {
+	DryRun       bool                     `json:"dryRun"`
+	Valid        bool                     `json:"valid"`
+	TotalRows    int                      `json:"totalRows"`
+	CreatedCount int                      `json:"createdCount"`
+	Documents    []DocumentImportItem     `json:"documents"`
+	Errors       []DocumentImportRowError `json:"errors"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// LintSeverity represents the severity of a quality-linter finding
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// Quality-linter rule identifiers
+const (
+	LintRuleStaleReference    = "stale_reference"
+	LintRuleMissingObjectives = "missing_objectives"
+	LintRuleMissingActors     = "missing_actors"
+	LintRuleStepNoResponsible = "step_without_responsible"
+	LintRuleMissingValidators = "missing_validators"
+)
+
+// LintIssue represents a single quality-linter finding
+type LintIssue struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+	Field    string       `json:"field,omitempty"` // Path of the offending element, e.g. references[0]
+}
+
+// DocumentLintResponse represents the quality-linter results of a document
+type DocumentLintResponse struct {
+	DocumentID   string      `json:"documentId"`
+	ErrorCount   int         `json:"errorCount"`
+	WarningCount int         `json:"warningCount"`
+	Issues       []LintIssue `json:"issues"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DepartmentDocumentCount counts the documents created by the members of a department
+type DepartmentDocumentCount struct {
+	DepartmentID   *primitive.ObjectID `json:"departmentId,omitempty" bson:"_id"` // Nil for creators without department
+	DepartmentName string              `json:"departmentName" bson:"name"`
+	Total          int64               `json:"total" bson:"total"`
+	Published      int64               `json:"published" bson:"published"`
+}
+
+// PendingSignatureCount counts the signatures a contributor still owes
+type PendingSignatureCount struct {
+	UserID  primitive.ObjectID `json:"userId" bson:"_id"`
+	Name    string             `json:"name" bson:"name"`
+	Email   string             `json:"email" bson:"email"`
+	Pending int64              `json:"pending" bson:"pending"`
+}
+
+// OverdueReviewDocument represents a published document past its periodic review date
+type OverdueReviewDocument struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id"`
+	Reference      string             `json:"reference" bson:"reference"`
+	Title          string             `json:"title" bson:"title"`
+	Version        string             `json:"version" bson:"version"`
+	NextReviewDate *time.Time         `json:"nextReviewDate,omitempty" bson:"next_review_date,omitempty"`
+}
+
+// DocumentStatsResponse summarizes the documents of the organization
+type DocumentStatsResponse struct {
+	Total                       int64                     `json:"total"`
+	ByStatus                    map[DocumentStatus]int64  `json:"byStatus"`
+	ByDepartment                []DepartmentDocumentCount `json:"byDepartment"`
+	AverageDraftToApprovedHours float64                   `json:"averageDraftToApprovedHours"`
+	ApprovedSampleSize          int64                     `json:"approvedSampleSize"` // Documents the average is computed on
+	PendingSignatures           []PendingSignatureCount   `json:"pendingSignatures"`
+	OverdueReviewCount          int64                     `json:"overdueReviewCount"`
+	OverdueReviews              []OverdueReviewDocument   `json:"overdueReviews"` // Most overdue first, bounded
+	GeneratedAt                 time.Time                 `json:"generatedAt"`
+}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"strings"
+)
+
+// DocumentStructureSchemaVersion is the version of the document structure format
+const DocumentStructureSchemaVersion = 1
+
+// DocumentStructure is the canonical, environment independent form of a
+// document, exported as JSON or YAML to move documents between environments
+// or keep them in Git. It carries no database IDs: the macro is referred to by
+// its code, task codes are relative to the process (T1, T2, ...), and the
+// contributors, links to other documents and annex files are left out.
+type DocumentStructure struct {
+	SchemaVersion    int                       `json:"schemaVersion" yaml:"schemaVersion"`
+	MacroCode        string                    `json:"macroCode" yaml:"macroCode"`
+	ProcessCode      string                    `json:"processCode,omitempty" yaml:"processCode,omitempty"`
+	Reference        string                    `json:"reference,omitempty" yaml:"reference,omitempty"`
+	Title            string                    `json:"title" yaml:"title"`
+	ShortDescription string                    `json:"shortDescription,omitempty" yaml:"shortDescription,omitempty"`
+	Description      string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	Version          string                    `json:"version,omitempty" yaml:"version,omitempty"`
+	IsActive         bool                      `json:"isActive" yaml:"isActive"`
+	Stakeholders     []string                  `json:"stakeholders" yaml:"stakeholders"`
+	Tasks            []DocumentStructureTask   `json:"tasks" yaml:"tasks"`
+	Metadata         DocumentStructureMetadata `json:"metadata" yaml:"metadata"`
+	ProcessGroups    []DocumentStructureGroup  `json:"processGroups" yaml:"processGroups"`
+	Annexes          []DocumentStructureAnnex  `json:"annexes" yaml:"annexes"`
+}
+
+// DocumentStructureTask is a task of an exported document
+type DocumentStructureTask struct {
+	Code        string `json:"code" yaml:"code"` // Relative to the process code: T1, T2, ...
+	Description string `json:"description" yaml:"description"`
+	IsActive    bool   `json:"isActive" yaml:"isActive"`
+	Order       int    `json:"order" yaml:"order"`
+}
+
+// DocumentStructureMetadata is the metadata section of an exported document
+type DocumentStructureMetadata struct {
+	Objectives       []string                `json:"objectives" yaml:"objectives"`
+	ImplicatedActors []string                `json:"implicatedActors" yaml:"implicatedActors"`
+	ManagementRules  []string                `json:"managementRules" yaml:"managementRules"`
+	Terminology      []string                `json:"terminology" yaml:"terminology"`
+	ChangeHistory    []ChangeHistoryEntry    `json:"changeHistory" yaml:"changeHistory"`
+	CustomSections   []CustomMetadataSection `json:"customSections,omitempty" yaml:"customSections,omitempty"`
+}
+
+// DocumentStructureGroup is a process group of an exported document
+type DocumentStructureGroup struct {
+	Title string                  `json:"title" yaml:"title"`
+	Order int                     `json:"order" yaml:"order"`
+	Steps []DocumentStructureStep `json:"steps" yaml:"steps"`
+}
+
+// DocumentStructureStep is a process step of an exported document
+type DocumentStructureStep struct {
+	Title        string                         `json:"title" yaml:"title"`
+	Order        int                            `json:"order" yaml:"order"`
+	Outputs      []string                       `json:"outputs" yaml:"outputs"`
+	Durations    []string                       `json:"durations" yaml:"durations"`
+	Responsible  string                         `json:"responsible,omitempty" yaml:"responsible,omitempty"`
+	Descriptions []DocumentStructureDescription `json:"descriptions" yaml:"descriptions"`
+}
+
+// DocumentStructureDescription is a description of a process step
+type DocumentStructureDescription struct {
+	Title         string   `json:"title,omitempty" yaml:"title,omitempty"`
+	Instructions  []string `json:"instructions" yaml:"instructions"`
+	Order         int      `json:"order" yaml:"order"`
+	OutputIndex   int      `json:"outputIndex" yaml:"outputIndex"`
+	DurationIndex int      `json:"durationIndex" yaml:"durationIndex"`
+}
+
+// DocumentStructureAnnex is an annex of an exported document, without its files
+type DocumentStructureAnnex struct {
+	Title   string                 `json:"title" yaml:"title"`
+	Type    AnnexType              `json:"type" yaml:"type"`
+	Content map[string]interface{} `json:"content,omitempty" yaml:"content,omitempty"`
+	Order   int                    `json:"order" yaml:"order"`
+}
+
+// NewDocumentStructure builds the canonical structure of a document
+func NewDocumentStructure(document *Document, macroCode string) *DocumentStructure {
+	structure := &DocumentStructure{
+		SchemaVersion:    DocumentStructureSchemaVersion,
+		MacroCode:        macroCode,
+		ProcessCode:      document.ProcessCode,
+		Reference:        document.Reference,
+		Title:            document.Title,
+		ShortDescription: document.ShortDescription,
+		Description:      document.Description,
+		Version:          document.Version,
+		IsActive:         document.IsActive,
+		Stakeholders:     nonNilList(document.Stakeholders),
+		Tasks:            make([]DocumentStructureTask, 0, len(document.Tasks)),
+		Metadata: DocumentStructureMetadata{
+			Objectives:       nonNilList(document.Metadata.Objectives),
+			ImplicatedActors: nonNilList(document.Metadata.ImplicatedActors),
+			ManagementRules:  nonNilList(document.Metadata.ManagementRules),
+			Terminology:      nonNilList(document.Metadata.Terminology),
+			ChangeHistory:    document.Metadata.ChangeHistory,
+			CustomSections:   document.Metadata.CustomSections,
+		},
+		ProcessGroups: make([]DocumentStructureGroup, 0, len(document.ProcessGroups)),
+		Annexes:       make([]DocumentStructureAnnex, 0, len(document.Annexes)),
+	}
+	if structure.Metadata.ChangeHistory == nil {
+		structure.Metadata.ChangeHistory = []ChangeHistoryEntry{}
+	}
+
+	for _, task := range document.Tasks {
+		structure.Tasks = append(structure.Tasks, DocumentStructureTask{
+			Code:        strings.TrimPrefix(task.Code, document.ProcessCode+"_"),
+			Description: task.Description,
+			IsActive:    task.IsActive,
+			Order:       task.Order,
+		})
+	}
+
+	for _, group := range document.ProcessGroups {
+		structureGroup := DocumentStructureGroup{
+			Title: group.Title,
+			Order: group.Order,
+			Steps: make([]DocumentStructureStep, 0, len(group.ProcessSteps)),
+		}
+		for _, step := range group.ProcessSteps {
+			structureStep := DocumentStructureStep{
+				Title:        step.Title,
+				Order:        step.Order,
+				Outputs:      nonNilList(step.Outputs),
+				Durations:    nonNilList(step.Durations),
+				Responsible:  step.Responsible,
+				Descriptions: make([]DocumentStructureDescription, 0, len(step.Descriptions)),
+			}
+			for _, description := range step.Descriptions {
+				structureStep.Descriptions = append(structureStep.Descriptions, DocumentStructureDescription{
+					Title:         description.Title,
+					Instructions:  nonNilList(description.Instructions),
+					Order:         description.Order,
+					OutputIndex:   description.OutputIndex,
+					DurationIndex: description.DurationIndex,
+				})
+			}
+			structureGroup.Steps = append(structureGroup.Steps, structureStep)
+		}
+		structure.ProcessGroups = append(structure.ProcessGroups, structureGroup)
+	}
+
+	for _, annex := range document.Annexes {
+		structure.Annexes = append(structure.Annexes, DocumentStructureAnnex{
+			Title:   annex.Title,
+			Type:    annex.Type,
+			Content: annex.Content,
+			Order:   annex.Order,
+		})
+	}
+
+	return structure
+}
+
+// ToCreateRequest converts an imported structure into the creation request of
+// a draft document. Process groups, steps and annexes get new IDs through
+// newID.
+func (s *DocumentStructure) ToCreateRequest(macroID, processCode string, newID func() string) *CreateDocumentRequest {
+	req := &CreateDocumentRequest{
+		MacroID:          &macroID,
+		ProcessCode:      processCode,
+		Reference:        s.Reference,
+		Title:            s.Title,
+		ShortDescription: s.ShortDescription,
+		Description:      s.Description,
+		IsActive:         s.IsActive,
+		Stakeholders:     nonNilList(s.Stakeholders),
+		Tasks:            make([]Task, 0, len(s.Tasks)),
+		Version:          s.Version,
+		Metadata: DocumentMetadata{
+			Objectives:       nonNilList(s.Metadata.Objectives),
+			ImplicatedActors: nonNilList(s.Metadata.ImplicatedActors),
+			ManagementRules:  nonNilList(s.Metadata.ManagementRules),
+			Terminology:      nonNilList(s.Metadata.Terminology),
+			ChangeHistory:    s.Metadata.ChangeHistory,
+			CustomSections:   s.Metadata.CustomSections,
+		},
+		ProcessGroups: make([]ProcessGroup, 0, len(s.ProcessGroups)),
+		Annexes:       make([]Annex, 0, len(s.Annexes)),
+	}
+	if req.Metadata.ChangeHistory == nil {
+		req.Metadata.ChangeHistory = []ChangeHistoryEntry{}
+	}
+
+	for _, task := range s.Tasks {
+		req.Tasks = append(req.Tasks, Task{
+			Code:        task.Code, // Prefixed with the process code on creation
+			Description: task.Description,
+			IsActive:    task.IsActive,
+			Order:       task.Order,
+		})
+	}
+
+	for _, group := range s.ProcessGroups {
+		processGroup := ProcessGroup{
+			ID:           newID(),
+			Title:        group.Title,
+			Order:        group.Order,
+			ProcessSteps: make([]ProcessStep, 0, len(group.Steps)),
+		}
+		for _, step := range group.Steps {
+			processStep := ProcessStep{
+				ID:           newID(),
+				Title:        step.Title,
+				Order:        step.Order,
+				Outputs:      nonNilList(step.Outputs),
+				Durations:    nonNilList(step.Durations),
+				Responsible:  step.Responsible,
+				Descriptions: make([]ProcessDescription, 0, len(step.Descriptions)),
+			}
+			for _, description := range step.Descriptions {
+				processStep.Descriptions = append(processStep.Descriptions, ProcessDescription{
+					Title:         description.Title,
+					Instructions:  nonNilList(description.Instructions),
+					Order:         description.Order,
+					OutputIndex:   description.OutputIndex,
+					DurationIndex: description.DurationIndex,
+				})
+			}
+			processGroup.ProcessSteps = append(processGroup.ProcessSteps, processStep)
+		}
+		req.ProcessGroups = append(req.ProcessGroups, processGroup)
+	}
+
+	for _, annex := range s.Annexes {
+		req.Annexes = append(req.Annexes, Annex{
+			ID:      newID(),
+			Title:   annex.Title,
+			Type:    annex.Type,
+			Content: annex.Content,
+			Order:   annex.Order,
+		})
+	}
+
+	return req
+}
+
+// nonNilList returns an empty list instead of nil, so exports and imports
+// keep empty lists
+func nonNilList(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -15,6 +15,7 @@ type DocumentVersion struct {
 	CreatedBy  primitive.ObjectID `json:"createdBy" bson:"created_by"`
 	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
 	ChangeNote string             `json:"changeNote" bson:"change_note"`
+	PdfUrl     string             `json:"pdfUrl,omitempty" bson:"pdf_url,omitempty"` // PDF rendered from the snapshot, kept through later edits
 }
 
 // DocumentVersionResponse represents the API response for a document version
@@ -26,6 +27,7 @@ type DocumentVersionResponse struct {
 	CreatedBy  string           `json:"createdBy"`
 	CreatedAt  time.Time        `json:"createdAt"`
 	ChangeNote string           `json:"changeNote"`
+	PdfUrl     string           `json:"pdfUrl,omitempty"`
 }
 
 // ToResponse converts a DocumentVersion to DocumentVersionResponse
@@ -38,5 +40,6 @@ func (dv *DocumentVersion) ToResponse() DocumentVersionResponse {
 		CreatedBy:  dv.CreatedBy.Hex(),
 		CreatedAt:  dv.CreatedAt,
 		ChangeNote: dv.ChangeNote,
+		PdfUrl:     dv.PdfUrl,
 	}
 }
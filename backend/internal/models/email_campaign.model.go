@@ -0,0 +1,109 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailCampaignStatus represents the lifecycle of a bulk email campaign
+type EmailCampaignStatus string
+
+const (
+	EmailCampaignStatusScheduled EmailCampaignStatus = "scheduled" // Waiting for its send time
+	EmailCampaignStatusSending   EmailCampaignStatus = "sending"   // Recipients resolved and queued
+	EmailCampaignStatusCompleted EmailCampaignStatus = "completed"
+	EmailCampaignStatusCancelled EmailCampaignStatus = "cancelled"
+)
+
+// EmailDeliveryStatus represents the delivery state of one campaign recipient
+type EmailDeliveryStatus string
+
+const (
+	EmailDeliveryStatusPending   EmailDeliveryStatus = "pending"
+	EmailDeliveryStatusSending   EmailDeliveryStatus = "sending"
+	EmailDeliveryStatusQueued    EmailDeliveryStatus = "queued" // Handed to the outbox, which reports the outcome
+	EmailDeliveryStatusSent      EmailDeliveryStatus = "sent"
+	EmailDeliveryStatusFailed    EmailDeliveryStatus = "failed"
+	EmailDeliveryStatusCancelled EmailDeliveryStatus = "cancelled"
+)
+
+// CampaignTemplateVariables lists the placeholders replaced per recipient, used as {{firstName}}
+var CampaignTemplateVariables = []string{"firstName", "lastName", "fullName", "email", "role", "department"}
+
+// UserSegment selects campaign recipients. Empty criteria match everyone;
+// only active accounts are targeted when no status is given.
+type UserSegment struct {
+	Roles         []UserRole           `json:"roles,omitempty" bson:"roles,omitempty"`
+	DepartmentIDs []primitive.ObjectID `json:"departmentIds,omitempty" bson:"department_ids,omitempty"`
+	Statuses      []UserStatus         `json:"statuses,omitempty" bson:"statuses,omitempty"`
+}
+
+// EmailCampaign represents an email sent to a segment of users
+type EmailCampaign struct {
+	ID              primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Subject         string              `json:"subject" bson:"subject"`
+	Body            string              `json:"body" bson:"body"`
+	IsHTML          bool                `json:"isHtml" bson:"is_html"`
+	Segment         UserSegment         `json:"segment" bson:"segment"`
+	Status          EmailCampaignStatus `json:"status" bson:"status"`
+	ScheduledAt     time.Time           `json:"scheduledAt" bson:"scheduled_at"`
+	StartedAt       *time.Time          `json:"startedAt,omitempty" bson:"started_at,omitempty"`
+	CompletedAt     *time.Time          `json:"completedAt,omitempty" bson:"completed_at,omitempty"`
+	TotalRecipients int64               `json:"totalRecipients" bson:"total_recipients"`
+	SentCount       int64               `json:"sentCount" bson:"sent_count"`
+	FailedCount     int64               `json:"failedCount" bson:"failed_count"`
+	CreatedBy       primitive.ObjectID  `json:"createdBy" bson:"created_by"`
+	CreatedAt       time.Time           `json:"createdAt" bson:"created_at"`
+	UpdatedAt       time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// EmailCampaignRecipient is one queued delivery of a campaign
+type EmailCampaignRecipient struct {
+	ID         primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	CampaignID primitive.ObjectID  `json:"campaignId" bson:"campaign_id"`
+	UserID     primitive.ObjectID  `json:"userId" bson:"user_id"`
+	Email      string              `json:"email" bson:"email"`
+	Name       string              `json:"name" bson:"name"`
+	Status     EmailDeliveryStatus `json:"status" bson:"status"`
+	Error      string              `json:"error,omitempty" bson:"error,omitempty"`
+	SentAt     *time.Time          `json:"sentAt,omitempty" bson:"sent_at,omitempty"`
+	CreatedAt  time.Time           `json:"createdAt" bson:"created_at"`
+	UpdatedAt  time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// UserSegmentRequest represents a segment in campaign requests
+type UserSegmentRequest struct {
+	Roles         []string `json:"roles,omitempty"`
+	DepartmentIDs []string `json:"departmentIds,omitempty"`
+	Statuses      []string `json:"statuses,omitempty"`
+}
+
+// CreateEmailCampaignRequest represents the request to create a campaign
+type CreateEmailCampaignRequest struct {
+	Subject     string             `json:"subject" binding:"required,max=200"`
+	Body        string             `json:"body" binding:"required"`
+	IsHTML      bool               `json:"isHtml"`
+	Segment     UserSegmentRequest `json:"segment"`
+	ScheduledAt *time.Time         `json:"scheduledAt,omitempty"` // Sent right away when empty
+}
+
+// PreviewEmailCampaignRequest represents the request to preview a campaign
+type PreviewEmailCampaignRequest struct {
+	Subject string             `json:"subject" binding:"required,max=200"`
+	Body    string             `json:"body" binding:"required"`
+	IsHTML  bool               `json:"isHtml"`
+	Segment UserSegmentRequest `json:"segment"`
+	UserID  string             `json:"userId,omitempty"` // Sample recipient, defaults to the first match
+}
+
+// EmailCampaignPreview represents a campaign rendered for a sample recipient
+type EmailCampaignPreview struct {
+	Subject        string `json:"subject"`
+	Body           string `json:"body"`
+	RecipientCount int64  `json:"recipientCount"`
+	SampleEmail    string `json:"sampleEmail,omitempty"`
+}
+
+// EmailCampaignStatusCounts counts the recipients of a campaign by delivery status
+type EmailCampaignStatusCounts map[EmailDeliveryStatus]int64
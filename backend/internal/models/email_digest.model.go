@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailDigestItem is a notification waiting for the next email digest of a
+// user who chose an hourly or daily email frequency
+type EmailDigestItem struct {
+	ID        primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID   `json:"userId" bson:"user_id"`
+	Category  NotificationCategory `json:"category" bson:"category"`
+	Priority  NotificationPriority `json:"priority" bson:"priority"`
+	Title     string               `json:"title" bson:"title"`
+	Body      string               `json:"body" bson:"body"`
+	ActionURL string               `json:"actionUrl,omitempty" bson:"action_url,omitempty"`
+	DigestID  *primitive.ObjectID  `json:"-" bson:"digest_id,omitempty"` // Set while the digest containing it is sent
+	CreatedAt time.Time            `json:"createdAt" bson:"created_at"`
+}
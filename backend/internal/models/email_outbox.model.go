@@ -0,0 +1,180 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxEmail is an email waiting in the outbox or already delivered. The
+// bodies are kept until the email is sent, they can hold one-time codes and
+// are never returned by the API.
+type OutboxEmail struct {
+	ID            primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Kind          string              `json:"kind" bson:"kind"` // Template of the email, e.g. otp or invitation
+	Language      string              `json:"language,omitempty" bson:"language,omitempty"`
+	CampaignID    *primitive.ObjectID `json:"campaignId,omitempty" bson:"campaign_id,omitempty"` // Campaign the email belongs to, its ID being the recipient ID
+	ToEmail       string              `json:"toEmail" bson:"to_email"`
+	ToName        string              `json:"toName,omitempty" bson:"to_name,omitempty"`
+	Subject       string              `json:"subject" bson:"subject"`
+	HTMLBody      string              `json:"-" bson:"html_body,omitempty"`
+	TextBody      string              `json:"-" bson:"text_body,omitempty"`
+	Attachments   []EmailAttachment   `json:"-" bson:"attachments,omitempty"`
+	Status        EmailDeliveryStatus `json:"status" bson:"status"`
+	Attempts      int                 `json:"attempts" bson:"attempts"`
+	LastError     string              `json:"lastError,omitempty" bson:"last_error,omitempty"`
+	Provider      string              `json:"provider,omitempty" bson:"provider,omitempty"`    // Provider that delivered the email
+	MessageID     string              `json:"messageId,omitempty" bson:"message_id,omitempty"` // ID given by the provider, matched by its webhook events
+	NextAttemptAt time.Time           `json:"nextAttemptAt" bson:"next_attempt_at"`
+	SentAt        *time.Time          `json:"sentAt,omitempty" bson:"sent_at,omitempty"`
+	LastEvent     EmailEventType      `json:"lastEvent,omitempty" bson:"last_event,omitempty"`
+	Events        []EmailEvent        `json:"events,omitempty" bson:"events,omitempty"` // Reported by the provider once sent
+	CreatedAt     time.Time           `json:"createdAt" bson:"created_at"`
+	UpdatedAt     time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// EmailAttachment is a file attached to an email, such as a calendar event
+type EmailAttachment struct {
+	Filename    string `json:"filename" bson:"filename"`
+	ContentType string `json:"contentType" bson:"content_type"`
+	Content     []byte `json:"-" bson:"content"`
+}
+
+// EmailEventType is an event reported by the email provider about a sent email
+type EmailEventType string
+
+const (
+	EmailEventDelivered    EmailEventType = "delivered"
+	EmailEventOpened       EmailEventType = "opened"
+	EmailEventSoftBounce   EmailEventType = "soft_bounce" // Temporary failure, the provider retries
+	EmailEventHardBounce   EmailEventType = "hard_bounce"
+	EmailEventSpam         EmailEventType = "spam" // Reported as spam by the recipient
+	EmailEventBlocked      EmailEventType = "blocked"
+	EmailEventInvalidEmail EmailEventType = "invalid_email"
+)
+
+// IsUndeliverable tells whether the event shows the address does not receive
+// the emails of the platform
+func (t EmailEventType) IsUndeliverable() bool {
+	switch t {
+	case EmailEventHardBounce, EmailEventSpam, EmailEventBlocked, EmailEventInvalidEmail:
+		return true
+	}
+	return false
+}
+
+// EmailEvent is an event reported by the email provider about a sent email
+type EmailEvent struct {
+	Type   EmailEventType `json:"type" bson:"type"`
+	Reason string         `json:"reason,omitempty" bson:"reason,omitempty"`
+	At     time.Time      `json:"at" bson:"at"`
+}
+
+// ProviderEmailEvent is an event received from the webhook of the email
+// provider, matched to the outbox by message or outbox ID
+type ProviderEmailEvent struct {
+	Type      EmailEventType
+	Email     string
+	MessageID string
+	OutboxID  string
+	Reason    string
+	At        time.Time
+}
+
+// UndeliverableEmail is an address flagged after a bounce, a block or a spam
+// report, so admins can see who does not receive their OTP or invitations.
+// The flag is lifted when a later email is delivered.
+type UndeliverableEmail struct {
+	ID       primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Email    string              `json:"email" bson:"email"`
+	UserID   *primitive.ObjectID `json:"userId,omitempty" bson:"user_id,omitempty"`
+	Event    EmailEventType      `json:"event" bson:"event"`
+	Reason   string              `json:"reason,omitempty" bson:"reason,omitempty"`
+	LastKind string              `json:"lastKind,omitempty" bson:"last_kind,omitempty"` // Kind of the email that failed last
+	Count    int                 `json:"count" bson:"count"`
+	FirstAt  time.Time           `json:"firstAt" bson:"first_at"`
+	LastAt   time.Time           `json:"lastAt" bson:"last_at"`
+}
+
+// OutboxEmailFilter represents the filters of the outbox listing
+type OutboxEmailFilter struct {
+	Status EmailDeliveryStatus `form:"status"`
+	Event  EmailEventType      `form:"event"`
+	Kind   string              `form:"kind"`
+	Email  string              `form:"email"`
+	Page   int                 `form:"page"`
+	Limit  int                 `form:"limit"`
+}
+
+// BrevoWebhookEvent is the payload of a transactional email event sent by
+// the Brevo webhook
+type BrevoWebhookEvent struct {
+	Event     string `json:"event"`
+	Email     string `json:"email"`
+	MessageID string `json:"message-id"`
+	Reason    string `json:"reason"`
+	Custom    string `json:"X-Mailin-custom"` // Outbox ID sent in the headers of the email
+	TsEvent   int64  `json:"ts_event"`        // Unix time of the event
+}
+
+// brevoEventTypes maps the Brevo events to the recorded ones, the others are ignored
+var brevoEventTypes = map[string]EmailEventType{
+	"delivered":     EmailEventDelivered,
+	"opened":        EmailEventOpened,
+	"unique_opened": EmailEventOpened,
+	"soft_bounce":   EmailEventSoftBounce,
+	"hard_bounce":   EmailEventHardBounce,
+	"spam":          EmailEventSpam,
+	"blocked":       EmailEventBlocked,
+	"invalid_email": EmailEventInvalidEmail,
+}
+
+// ToProviderEvent converts a Brevo event, false when it is not recorded
+func (e *BrevoWebhookEvent) ToProviderEvent() (ProviderEmailEvent, bool) {
+	eventType, ok := brevoEventTypes[e.Event]
+	if !ok {
+		return ProviderEmailEvent{}, false
+	}
+	at := time.Now()
+	if e.TsEvent > 0 {
+		at = time.Unix(e.TsEvent, 0)
+	}
+	return ProviderEmailEvent{
+		Type:      eventType,
+		Email:     e.Email,
+		MessageID: e.MessageID,
+		OutboxID:  e.Custom,
+		Reason:    e.Reason,
+		At:        at,
+	}, true
+}
+
+// EmailDiagnosticStep is a step of the delivery of a test email, such as the
+// SMTP handshake or the request to the provider API
+type EmailDiagnosticStep struct {
+	Name       string `json:"name"`
+	Success    bool   `json:"success"`
+	Detail     string `json:"detail,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// EmailTestReport is the outcome of a test email sent through a provider,
+// with the steps of its delivery to find out what is misconfigured
+type EmailTestReport struct {
+	Provider   string                `json:"provider"`
+	Address    string                `json:"address"` // Host and port of the provider
+	Providers  []string              `json:"providers"`
+	ToEmail    string                `json:"toEmail"`
+	Success    bool                  `json:"success"`
+	MessageID  string                `json:"messageId,omitempty"`
+	Error      string                `json:"error,omitempty"`
+	DurationMs int64                 `json:"durationMs"`
+	Steps      []EmailDiagnosticStep `json:"steps"`
+}
+
+// SendTestEmailRequest sends a test email, to the current user when no
+// address is given and through the primary provider when none is named
+type SendTestEmailRequest struct {
+	Email    string `json:"email" validate:"omitempty,email"`
+	Provider string `json:"provider"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailEventSettingsID is the key of the single email event settings document
+const EmailEventSettingsID = "email_events"
+
+// SystemEmailEvent is a system event whose emails can be turned off, for the
+// whole organization or by each user
+type SystemEmailEvent string
+
+const (
+	SystemEmailEventInvitation        SystemEmailEvent = "invitation"         // Invitations to contribute to a document
+	SystemEmailEventPublish           SystemEmailEvent = "publish"            // Documents published
+	SystemEmailEventSignatureReminder SystemEmailEvent = "signature_reminder" // Pending and overdue signatures
+	SystemEmailEventApproval          SystemEmailEvent = "approval"           // Signatures requested and accounts approved
+	SystemEmailEventRejection         SystemEmailEvent = "rejection"          // Documents and accounts rejected
+)
+
+// SystemEmailEvents lists the events whose emails can be turned off
+var SystemEmailEvents = []SystemEmailEvent{
+	SystemEmailEventInvitation,
+	SystemEmailEventPublish,
+	SystemEmailEventSignatureReminder,
+	SystemEmailEventApproval,
+	SystemEmailEventRejection,
+}
+
+// EmailKindEvents maps the kinds of the emails sent by the platform to their
+// event. The other kinds, such as one-time codes, cannot be turned off.
+var EmailKindEvents = map[string]SystemEmailEvent{
+	"invitation":         SystemEmailEventInvitation,
+	"account_approved":   SystemEmailEventApproval,
+	"account_rejected":   SystemEmailEventRejection,
+	"signature_reminder": SystemEmailEventSignatureReminder,
+	"signature_request":  SystemEmailEventApproval,
+}
+
+// NotificationActionEvents maps the actions of the notifications, emailed in
+// the digests, to their event
+var NotificationActionEvents = map[string]SystemEmailEvent{
+	"document_published": SystemEmailEventPublish,
+	"signature_reminder": SystemEmailEventSignatureReminder,
+	"signature_overdue":  SystemEmailEventSignatureReminder,
+	"signature_required": SystemEmailEventApproval,
+	"document_rejected":  SystemEmailEventRejection,
+}
+
+// IsSystemEmailEvent tells whether the emails of an event can be turned off
+func IsSystemEmailEvent(event SystemEmailEvent) bool {
+	for _, e := range SystemEmailEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailEventSettings tells which events send emails in the organization.
+// Events missing from the map send emails.
+type EmailEventSettings struct {
+	ID        string                    `json:"-" bson:"_id"`
+	Events    map[SystemEmailEvent]bool `json:"events" bson:"events"`
+	UpdatedBy *primitive.ObjectID       `json:"updatedBy,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt time.Time                 `json:"updatedAt" bson:"updated_at"`
+}
+
+// DefaultEmailEventSettings returns the settings used until an administrator
+// changes them: every event sends emails
+func DefaultEmailEventSettings() *EmailEventSettings {
+	events := make(map[SystemEmailEvent]bool, len(SystemEmailEvents))
+	for _, event := range SystemEmailEvents {
+		events[event] = true
+	}
+	return &EmailEventSettings{ID: EmailEventSettingsID, Events: events}
+}
+
+// Enabled tells whether an event sends emails in the organization
+func (s *EmailEventSettings) Enabled(event SystemEmailEvent) bool {
+	enabled, ok := s.Events[event]
+	return !ok || enabled
+}
+
+// UpdateEmailEventSettingsRequest turns the emails of events on or off, the
+// events left out keep their setting
+type UpdateEmailEventSettingsRequest struct {
+	Events map[SystemEmailEvent]bool `json:"events" binding:"required"`
+}
+
+// ErrInvalidEmailEvent is returned for an event whose emails cannot be turned off
+var ErrInvalidEmailEvent = errors.New("invalid email event: must be invitation, publish, signature_reminder, approval or rejection")
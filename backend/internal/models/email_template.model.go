@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailTemplateKinds are the emails whose template can be edited, keyed like
+// the kind of the outbox emails
+var EmailTemplateKinds = []string{
+	"welcome",
+	"verification",
+	"otp",
+	"registration_otp",
+	"registration_pending",
+	"account_approved",
+	"account_rejected",
+	"invitation",
+	"review_due",
+	"notification_digest",
+	"signature_reminder",
+	"signature_request",
+}
+
+// IsEmailTemplateKind tells whether an email kind has an editable template
+func IsEmailTemplateKind(kind string) bool {
+	for _, k := range EmailTemplateKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// CustomEmailTemplate replaces the built-in template of an email kind in a
+// language. The subject and bodies are Go templates receiving the email data,
+// such as {{.UserName}} or {{.AppName}}. Removing it restores the built-in
+// template.
+type CustomEmailTemplate struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Kind      string             `json:"kind" bson:"kind"`
+	Language  string             `json:"language" bson:"language"` // fr or en
+	Subject   string             `json:"subject" bson:"subject"`
+	HTMLBody  string             `json:"htmlBody" bson:"html_body"`
+	TextBody  string             `json:"textBody" bson:"text_body"`
+	Version   int                `json:"version" bson:"version"` // Version of the history currently in use
+	UpdatedBy primitive.ObjectID `json:"updatedBy" bson:"updated_by"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// EmailTemplateVersion is a saved revision of the template of an email kind
+// in a language
+type EmailTemplateVersion struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Kind       string             `json:"kind" bson:"kind"`
+	Language   string             `json:"language" bson:"language"`
+	Version    int                `json:"version" bson:"version"`
+	Subject    string             `json:"subject" bson:"subject"`
+	HTMLBody   string             `json:"htmlBody" bson:"html_body"`
+	TextBody   string             `json:"textBody" bson:"text_body"`
+	ChangeNote string             `json:"changeNote,omitempty" bson:"change_note,omitempty"`
+	CreatedBy  primitive.ObjectID `json:"createdBy" bson:"created_by"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// EmailTemplateSummary describes the template in use for an email kind in a language
+type EmailTemplateSummary struct {
+	Kind       string     `json:"kind"`
+	Language   string     `json:"language"`
+	Customized bool       `json:"customized"` // False when the built-in template is used
+	Version    int        `json:"version,omitempty"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+}
+
+// EmailTemplateDetail is the template in use for an email kind in a language
+// with the variables it can use
+type EmailTemplateDetail struct {
+	EmailTemplateSummary
+	Subject   string   `json:"subject"`
+	HTMLBody  string   `json:"htmlBody"`
+	TextBody  string   `json:"textBody"`
+	Variables []string `json:"variables"`
+}
+
+// SaveEmailTemplateRequest represents the request to customize the template of an email kind in a language
+type SaveEmailTemplateRequest struct {
+	Subject    string `json:"subject" validate:"required,max=300"`
+	HTMLBody   string `json:"htmlBody" validate:"required,max=262144"`
+	TextBody   string `json:"textBody" validate:"required,max=65536"`
+	ChangeNote string `json:"changeNote" validate:"max=500"`
+}
+
+// PreviewEmailTemplateRequest renders the template of an email kind with
+// sample data, the fields given replacing those of the template in use
+type PreviewEmailTemplateRequest struct {
+	Subject  *string `json:"subject" validate:"omitempty,max=300"`
+	HTMLBody *string `json:"htmlBody" validate:"omitempty,max=262144"`
+	TextBody *string `json:"textBody" validate:"omitempty,max=65536"`
+}
+
+// EmailTemplatePreview is an email rendered with sample data
+type EmailTemplatePreview struct {
+	Subject  string `json:"subject"`
+	HTMLBody string `json:"htmlBody"`
+	TextBody string `json:"textBody"`
+}
@@ -29,6 +29,11 @@ var (
 	ErrAccountInactive = errors.New("account is inactive")
 	ErrCannotLogin     = errors.New("user cannot login")
 
+	// Account deletion errors
+	ErrDeletionAlreadyScheduled = errors.New("account deletion is already scheduled")
+	ErrDeletionRequestNotFound  = errors.New("account deletion request not found")
+	ErrLastAdminDeletion        = errors.New("the last active admin cannot delete their account")
+
 	// Permission errors
 	ErrInsufficientPermissions = errors.New("insufficient permissions")
 	ErrForbidden               = errors.New("forbidden access")
@@ -46,6 +51,8 @@ var (
 	ErrServiceUnavailable = errors.New("service temporarily unavailable")
 	ErrEmailSendFailed    = errors.New("failed to send email")
 	ErrRedisOperation     = errors.New("redis operation failed")
+	ErrPDFRenderBusy      = errors.New("too many PDF renders in progress, please retry later")
+	ErrEmailRateLimited   = errors.New("too many emails sent to this recipient, please retry later")
 )
 
 // ============================================
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboundExportKind is the kind of file leaving the platform
+type OutboundExportKind string
+
+const (
+	OutboundExportDocumentPDF     OutboundExportKind = "document_pdf"
+	OutboundExportDocumentArchive OutboundExportKind = "document_archive"
+)
+
+// OutboundExport describes a file about to be served, checked by the export
+// hooks such as virus scanners and DLP integrations. The file is stored at
+// URL and its link is only returned when every hook allows it.
+type OutboundExport struct {
+	Kind        OutboundExportKind `json:"kind"`
+	URL         string             `json:"url"`
+	Documents   []*Document        `json:"-"`
+	RequestedBy primitive.ObjectID `json:"requestedBy"` // Zero for exports run by the platform, such as the QMS sync
+}
+
+// ExportBlockedError is returned when an export hook refuses an export
+type ExportBlockedError struct {
+	Hook   string
+	Reason string
+}
+
+func (e *ExportBlockedError) Error() string {
+	return fmt.Sprintf("export blocked by %s: %s", e.Hook, e.Reason)
+}
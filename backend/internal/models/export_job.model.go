@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportKind is the list exported by an export job
+type ExportKind string
+
+const (
+	ExportKindUsers        ExportKind = "users"
+	ExportKindDocuments    ExportKind = "documents"
+	ExportKindActivityLogs ExportKind = "activity_logs"
+)
+
+// ExportJobStatus represents the progress of an asynchronous export
+type ExportJobStatus string
+
+const (
+	ExportJobRunning   ExportJobStatus = "running"
+	ExportJobCompleted ExportJobStatus = "completed"
+	ExportJobFailed    ExportJobStatus = "failed"
+)
+
+// ExportJob is a list export streamed to MinIO in the background, for
+// exports too large to download in a single request
+type ExportJob struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Kind        ExportKind         `json:"kind" bson:"kind"`
+	Format      string             `json:"format" bson:"format"`
+	Status      ExportJobStatus    `json:"status" bson:"status"`
+	FileURL     string             `json:"fileUrl,omitempty" bson:"file_url,omitempty"`
+	RowCount    int64              `json:"rowCount" bson:"row_count"`
+	Error       string             `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedBy   primitive.ObjectID `json:"createdBy" bson:"created_by"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"created_at"`
+	CompletedAt *time.Time         `json:"completedAt,omitempty" bson:"completed_at,omitempty"`
+}
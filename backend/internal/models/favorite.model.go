@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DocumentFavorite represents a document starred by a user
+type DocumentFavorite struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"userId" bson:"user_id"`
+	DocumentID primitive.ObjectID `json:"documentId" bson:"document_id"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// RecentlyViewed records the last time a user opened a document
+type RecentlyViewed struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"userId" bson:"user_id"`
+	DocumentID primitive.ObjectID `json:"documentId" bson:"document_id"`
+	ViewedAt   time.Time          `json:"viewedAt" bson:"viewed_at"`
+	ViewCount  int64              `json:"viewCount" bson:"view_count"`
+}
+
+// FavoriteDocumentResponse represents a starred document
+type FavoriteDocumentResponse struct {
+	FavoritedAt time.Time        `json:"favoritedAt"`
+	Document    DocumentResponse `json:"document"`
+}
+
+// RecentDocumentResponse represents a recently viewed document
+type RecentDocumentResponse struct {
+	ViewedAt  time.Time        `json:"viewedAt"`
+	ViewCount int64            `json:"viewCount"`
+	Document  DocumentResponse `json:"document"`
+}
+
+// FavoriteStatusResponse tells whether the current user starred a document
+type FavoriteStatusResponse struct {
+	DocumentID string `json:"documentId"`
+	IsFavorite bool   `json:"isFavorite"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HelpLocales lists the locales help content can be written in, the first one is the fallback
+var HelpLocales = []string{"fr", "en"}
+
+// HelpArticle represents managed help content shown in tooltips and the help panel.
+// An article is identified by its key and locale, e.g. "publishing_workflow" in "fr".
+type HelpArticle struct {
+	ID        primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Key       string              `json:"key" bson:"key"`       // Stable identifier used by the frontend
+	Locale    string              `json:"locale" bson:"locale"` // fr or en
+	Title     string              `json:"title" bson:"title"`
+	Summary   string              `json:"summary,omitempty" bson:"summary,omitempty"` // Short text for tooltips
+	Content   string              `json:"content" bson:"content"`                     // Full article for the help panel
+	Tags      []string            `json:"tags,omitempty" bson:"tags,omitempty"`
+	IsActive  bool                `json:"isActive" bson:"is_active"`
+	CreatedBy primitive.ObjectID  `json:"createdBy" bson:"created_by"`
+	UpdatedBy *primitive.ObjectID `json:"updatedBy,omitempty" bson:"updated_by,omitempty"`
+	CreatedAt time.Time           `json:"createdAt" bson:"created_at"`
+	UpdatedAt time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// CreateHelpArticleRequest represents the request to create a help article
+type CreateHelpArticleRequest struct {
+	Key     string   `json:"key" validate:"required,max=100"`
+	Locale  string   `json:"locale" validate:"required,oneof=fr en"`
+	Title   string   `json:"title" validate:"required,max=200"`
+	Summary string   `json:"summary" validate:"max=500"`
+	Content string   `json:"content" validate:"required"`
+	Tags    []string `json:"tags"`
+}
+
+// UpdateHelpArticleRequest represents the request to update a help article
+type UpdateHelpArticleRequest struct {
+	Title    *string   `json:"title" validate:"omitempty,max=200"`
+	Summary  *string   `json:"summary" validate:"omitempty,max=500"`
+	Content  *string   `json:"content"`
+	Tags     *[]string `json:"tags"`
+	IsActive *bool     `json:"isActive"`
+}
+
+// HelpArticleFilter represents filtering options for help articles
+type HelpArticleFilter struct {
+	Locale          string
+	Search          string
+	Tag             string
+	IncludeInactive bool
+	Page            int
+	Limit           int
+}
@@ -0,0 +1,66 @@
+package models
+
+// ImpactTargetType identifies the organizational entity being changed
+type ImpactTargetType string
+
+const (
+	ImpactTargetDepartment  ImpactTargetType = "department"
+	ImpactTargetJobPosition ImpactTargetType = "job_position"
+)
+
+// ImpactTarget describes the department or job position under analysis
+type ImpactTarget struct {
+	Type ImpactTargetType `json:"type"`
+	ID   string           `json:"id"`
+	Name string           `json:"name"`
+	Code string           `json:"code,omitempty"`
+}
+
+// ImpactedStep represents a process step whose responsible references the target
+type ImpactedStep struct {
+	GroupTitle  string `json:"groupTitle"`
+	StepID      string `json:"stepId"`
+	StepTitle   string `json:"stepTitle"`
+	Responsible string `json:"responsible"`
+}
+
+// ImpactedTask represents a task with an intervenant belonging to the target
+type ImpactedTask struct {
+	TaskCode      string `json:"taskCode"`
+	Description   string `json:"description"`
+	JobPositionID string `json:"jobPositionId"`
+}
+
+// ImpactedContributor represents a contributor assignment referencing the target
+type ImpactedContributor struct {
+	UserID string          `json:"userId"`
+	Name   string          `json:"name"`
+	Team   ContributorTeam `json:"team"`
+	Status SignatureStatus `json:"status"`
+}
+
+// ImpactedDocument lists every reference to the target found in a document
+type ImpactedDocument struct {
+	DocumentID       string                `json:"documentId"`
+	ProcessCode      string                `json:"processCode,omitempty"`
+	Reference        string                `json:"reference"`
+	Title            string                `json:"title"`
+	Version          string                `json:"version"`
+	Status           DocumentStatus        `json:"status"`
+	Stakeholders     []string              `json:"stakeholders,omitempty"`
+	ImplicatedActors []string              `json:"implicatedActors,omitempty"`
+	Steps            []ImpactedStep        `json:"steps,omitempty"`
+	Tasks            []ImpactedTask        `json:"tasks,omitempty"`
+	Contributors     []ImpactedContributor `json:"contributors,omitempty"`
+}
+
+// ImpactAnalysisResponse represents the procedures requiring revision after a reorganization
+type ImpactAnalysisResponse struct {
+	Target           ImpactTarget       `json:"target"`
+	DocumentCount    int                `json:"documentCount"`
+	StepCount        int                `json:"stepCount"`
+	TaskCount        int                `json:"taskCount"`
+	ContributorCount int                `json:"contributorCount"`
+	Documents        []ImpactedDocument `json:"documents"`
+	RelatedPositions []string           `json:"relatedPositions,omitempty"` // Job positions of the department
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// JobType is the kind of work run by a background job
+type JobType string
+
+const (
+	JobTypeDocumentPDF JobType = "document_pdf"
+)
+
+// JobStatus represents the progress of a background job
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// Job is a unit of work queued in Redis and run by a background worker, such
+// as the PDF generation of a large document. Clients poll it by ID until it
+// completes.
+type Job struct {
+	ID          string         `json:"id"`
+	Type        JobType        `json:"type"`
+	Status      JobStatus      `json:"status"`
+	DocumentID  string         `json:"documentId,omitempty"`
+	Reference   string         `json:"reference,omitempty"`
+	Watermark   bool           `json:"watermark,omitempty"`  // Stamp a draft watermark on the PDF of a document not approved yet
+	Archival    bool           `json:"archival,omitempty"`   // Produce a PDF/A file for regulatory archiving
+	Force       bool           `json:"force,omitempty"`      // Generate a new PDF even when the stored one is current
+	PageLayout  *PDFPageLayout `json:"pageLayout,omitempty"` // Paper of this export, the one of the document when nil
+	BatchID     string         `json:"batchId,omitempty"`    // Batch the job belongs to, reported as a whole
+	ResultURL   string         `json:"resultUrl,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	Attempts    int            `json:"attempts"`
+	CreatedBy   string         `json:"createdBy"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	StartedAt   *time.Time     `json:"startedAt,omitempty"`
+	CompletedAt *time.Time     `json:"completedAt,omitempty"`
+}
+
+// IsFinished tells whether the job completed or failed
+func (j *Job) IsFinished() bool {
+	return j.Status == JobStatusCompleted || j.Status == JobStatusFailed
+}
+
+// JobQueueStats represents the state of the background job queue
+type JobQueueStats struct {
+	Workers        int   `json:"workers"`
+	TimeoutSeconds int   `json:"timeoutSeconds"`
+	Queued         int64 `json:"queued"`  // Jobs waiting for a worker
+	Running        []Job `json:"running"` // Jobs taken by a worker
+}
+
+// RegeneratePDFsRequest selects the documents whose stored PDF is generated
+// again, such as after a template change. Only documents with a stored PDF
+// are selected.
+type RegeneratePDFsRequest struct {
+	Statuses    []DocumentStatus `json:"statuses" validate:"omitempty,dive,oneof=draft author_review author_signed verifier_review verifier_signed validator_review approved archived review_due superseded"`
+	UpdatedFrom *time.Time       `json:"updatedFrom"` // Documents last updated at or after this date
+	UpdatedTo   *time.Time       `json:"updatedTo"`   // Documents last updated before this date
+}
+
+// PDFRegenerationItem is the regeneration of the PDF of one document of a batch
+type PDFRegenerationItem struct {
+	DocumentID string    `json:"documentId"`
+	Reference  string    `json:"reference"`
+	JobID      string    `json:"jobId,omitempty"`
+	Status     JobStatus `json:"status"`
+	ResultURL  string    `json:"resultUrl,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// PDFRegenerationBatch groups the jobs regenerating the PDFs of many documents
+type PDFRegenerationBatch struct {
+	ID        string                `json:"id"`
+	Total     int                   `json:"total"`
+	Queued    int                   `json:"queued"` // Jobs waiting for or taken by a worker
+	Completed int                   `json:"completed"`
+	Failed    int                   `json:"failed"`
+	Items     []PDFRegenerationItem `json:"items"`
+	CreatedBy string                `json:"createdBy"`
+	CreatedAt time.Time             `json:"createdAt"`
+}
+
+// Job error types
+var (
+	ErrJobNotFound   = errors.New("job not found")
+	ErrBatchNotFound = errors.New("batch not found")
+)
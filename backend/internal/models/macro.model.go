@@ -16,6 +16,7 @@ type Macro struct {
 	Description      string              `json:"description" bson:"description"`                // Detailed description
 	DomainID         *primitive.ObjectID `json:"domainId,omitempty" bson:"domain_id,omitempty"` // Link to Domain
 	IsActive         bool                `json:"isActive" bson:"is_active"`                     // Active status
+	Workflow         *WorkflowDefinition `json:"workflow,omitempty" bson:"workflow,omitempty"`  // Workflow of the documents of the macro, the default one when nil
 	CreatedBy        primitive.ObjectID  `json:"createdBy" bson:"created_by"`                   // User who created the macro
 	CreatedAt        time.Time           `json:"createdAt" bson:"created_at"`
 	UpdatedAt        time.Time           `json:"updatedAt" bson:"updated_at"`
@@ -23,17 +24,18 @@ type Macro struct {
 
 // MacroResponse represents the API response for a macro
 type MacroResponse struct {
-	ID               string    `json:"id"`
-	Code             string    `json:"code"`
-	Name             string    `json:"name"`
-	ShortDescription string    `json:"shortDescription"`
-	Description      string    `json:"description"`
-	DomainID         string    `json:"domainId,omitempty"`
-	IsActive         bool      `json:"isActive"`
-	CreatedBy        string    `json:"createdBy"`
-	ProcessCount     int       `json:"processCount,omitempty"` // Number of processes in this macro
-	CreatedAt        time.Time `json:"createdAt"`
-	UpdatedAt        time.Time `json:"updatedAt"`
+	ID               string              `json:"id"`
+	Code             string              `json:"code"`
+	Name             string              `json:"name"`
+	ShortDescription string              `json:"shortDescription"`
+	Description      string              `json:"description"`
+	DomainID         string              `json:"domainId,omitempty"`
+	IsActive         bool                `json:"isActive"`
+	CreatedBy        string              `json:"createdBy"`
+	ProcessCount     int                 `json:"processCount,omitempty"` // Number of processes in this macro
+	Workflow         *WorkflowDefinition `json:"workflow,omitempty"`
+	CreatedAt        time.Time           `json:"createdAt"`
+	UpdatedAt        time.Time           `json:"updatedAt"`
 }
 
 // ToResponse converts a Macro to MacroResponse
@@ -45,6 +47,7 @@ func (m *Macro) ToResponse() MacroResponse {
 		ShortDescription: m.ShortDescription,
 		Description:      m.Description,
 		IsActive:         m.IsActive,
+		Workflow:         m.Workflow,
 		CreatedBy:        m.CreatedBy.Hex(),
 		CreatedAt:        m.CreatedAt,
 		UpdatedAt:        m.UpdatedAt,
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MetadataSectionType represents how a custom metadata section is edited and rendered
+type MetadataSectionType string
+
+const (
+	MetadataSectionTypeList     MetadataSectionType = "list"
+	MetadataSectionTypeRichText MetadataSectionType = "richtext"
+	MetadataSectionTypeTable    MetadataSectionType = "table"
+)
+
+// IsValid checks if the section type is supported
+func (t MetadataSectionType) IsValid() bool {
+	switch t {
+	case MetadataSectionTypeList, MetadataSectionTypeRichText, MetadataSectionTypeTable:
+		return true
+	}
+	return false
+}
+
+// MetadataSectionDefinition represents an admin-defined metadata section
+// Sections without a macro belong to the default template and apply to every process
+type MetadataSectionDefinition struct {
+	ID        primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Key       string              `json:"key" bson:"key"`                              // Stable identifier, e.g. "kpis"
+	Title     string              `json:"title" bson:"title"`                          // Section title rendered in the PDF
+	Type      MetadataSectionType `json:"type" bson:"type"`                            // list, richtext or table
+	Columns   []string            `json:"columns,omitempty" bson:"columns,omitempty"`  // Table headers (table type only)
+	MacroID   *primitive.ObjectID `json:"macroId,omitempty" bson:"macro_id,omitempty"` // Restrict to processes of a macro
+	Order     int                 `json:"order" bson:"order"`
+	IsActive  bool                `json:"isActive" bson:"is_active"`
+	CreatedBy primitive.ObjectID  `json:"createdBy" bson:"created_by"`
+	CreatedAt time.Time           `json:"createdAt" bson:"created_at"`
+	UpdatedAt time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// CustomMetadataSection represents the content of a custom section in a document
+type CustomMetadataSection struct {
+	Key     string              `json:"key" bson:"key"`
+	Title   string              `json:"title" bson:"title"`
+	Type    MetadataSectionType `json:"type" bson:"type"`
+	Items   []string            `json:"items,omitempty" bson:"items,omitempty"`     // list type
+	Content string              `json:"content,omitempty" bson:"content,omitempty"` // richtext type
+	Columns []string            `json:"columns,omitempty" bson:"columns,omitempty"` // table type
+	Rows    [][]string          `json:"rows,omitempty" bson:"rows,omitempty"`       // table type
+	Order   int                 `json:"order" bson:"order"`
+}
+
+// CreateMetadataSectionRequest represents the request to define a custom metadata section
+type CreateMetadataSectionRequest struct {
+	Key     string              `json:"key" binding:"required"`
+	Title   string              `json:"title" binding:"required"`
+	Type    MetadataSectionType `json:"type" binding:"required"`
+	Columns []string            `json:"columns"`
+	MacroID *string             `json:"macroId"`
+	Order   int                 `json:"order"`
+}
+
+// UpdateMetadataSectionRequest represents the request to update a custom metadata section
+type UpdateMetadataSectionRequest struct {
+	Title    *string   `json:"title"`
+	Columns  *[]string `json:"columns"`
+	Order    *int      `json:"order"`
+	IsActive *bool     `json:"isActive"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModuleKey identifies a subsystem that can be rolled out progressively
+type ModuleKey string
+
+const (
+	ModuleFeedback      ModuleKey = "feedback"
+	ModuleIncidents     ModuleKey = "incidents"
+	ModuleChecklistRuns ModuleKey = "checklist_runs"
+)
+
+// KnownModules lists the modules that can be configured for rollout
+var KnownModules = []ModuleKey{
+	ModuleFeedback,
+	ModuleIncidents,
+	ModuleChecklistRuns,
+}
+
+// IsValidModuleKey checks if the module key is known
+func IsValidModuleKey(key ModuleKey) bool {
+	for _, m := range KnownModules {
+		if m == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ModuleRollout represents the rollout configuration of a module
+type ModuleRollout struct {
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	Module        ModuleKey            `bson:"module" json:"module"`
+	EnabledForAll bool                 `bson:"enabled_for_all" json:"enabledForAll"` // Company-wide rollout
+	DepartmentIDs []primitive.ObjectID `bson:"department_ids" json:"departmentIds"`  // Pilot departments
+	Description   string               `bson:"description,omitempty" json:"description,omitempty"`
+	UpdatedAt     time.Time            `bson:"updated_at" json:"updatedAt"`
+	UpdatedBy     primitive.ObjectID   `bson:"updated_by,omitempty" json:"updatedBy,omitempty"`
+}
+
+// IsEnabledFor reports whether the module is enabled for a department
+func (m *ModuleRollout) IsEnabledFor(departmentID *primitive.ObjectID) bool {
+	if m.EnabledForAll {
+		return true
+	}
+	if departmentID == nil {
+		return false
+	}
+	for _, id := range m.DepartmentIDs {
+		if id == *departmentID {
+			return true
+		}
+	}
+	return false
+}
+
+// ModuleRolloutResponse represents the API response for a module rollout
+type ModuleRolloutResponse struct {
+	Module        ModuleKey  `json:"module"`
+	EnabledForAll bool       `json:"enabledForAll"`
+	DepartmentIDs []string   `json:"departmentIds"`
+	Description   string     `json:"description,omitempty"`
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
+}
+
+// ToResponse converts ModuleRollout to ModuleRolloutResponse
+func (m *ModuleRollout) ToResponse() ModuleRolloutResponse {
+	resp := ModuleRolloutResponse{
+		Module:        m.Module,
+		EnabledForAll: m.EnabledForAll,
+		DepartmentIDs: make([]string, 0, len(m.DepartmentIDs)),
+		Description:   m.Description,
+	}
+	for _, id := range m.DepartmentIDs {
+		resp.DepartmentIDs = append(resp.DepartmentIDs, id.Hex())
+	}
+	if !m.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &m.UpdatedAt
+	}
+	return resp
+}
+
+// UpdateModuleRolloutRequest represents request to configure a module rollout
+type UpdateModuleRolloutRequest struct {
+	EnabledForAll *bool     `json:"enabledForAll,omitempty"`
+	DepartmentIDs *[]string `json:"departmentIds,omitempty"`
+	Description   *string   `json:"description,omitempty"`
+}
@@ -40,6 +40,16 @@ const (
 	NotificationCategoryMeeting   NotificationCategory = "meeting"
 	NotificationCategoryUpdate    NotificationCategory = "update"
 	NotificationCategoryAlert     NotificationCategory = "alert"
+	NotificationCategoryDocument  NotificationCategory = "document"
+)
+
+// EmailFrequency tells how often a user receives the emails of their notifications
+type EmailFrequency string
+
+const (
+	EmailFrequencyImmediate EmailFrequency = "immediate" // One email per notification
+	EmailFrequencyHourly    EmailFrequency = "hourly"    // A digest at the top of every hour
+	EmailFrequencyDaily     EmailFrequency = "daily"     // A digest once a day
 )
 
 // NotificationPriority represents the priority of a notification
@@ -66,6 +76,7 @@ type Notification struct {
 	ImageURL     string               `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"`   // Optional image
 	ActionURL    string               `bson:"actionUrl,omitempty" json:"actionUrl,omitempty"` // Click action URL
 	Status       NotificationStatus   `bson:"status" json:"status"`
+	BroadcastID  *primitive.ObjectID  `bson:"broadcastId,omitempty" json:"broadcastId,omitempty"` // Shared by all copies of an announcement
 
 	// Delivery tracking
 	SentAt      *time.Time `bson:"sentAt,omitempty" json:"sentAt,omitempty"`
@@ -96,6 +107,10 @@ type NotificationPreferences struct {
 	InAppEnabled     bool               `bson:"inAppEnabled" json:"inAppEnabled"`
 	SoundEnabled     bool               `bson:"soundEnabled" json:"soundEnabled"`
 	BadgeEnabled     bool               `bson:"badgeEnabled" json:"badgeEnabled"`
+	EmailFrequency   EmailFrequency     `bson:"emailFrequency,omitempty" json:"emailFrequency"` // Empty means immediate
+
+	// Events whose emails the user turned on or off, missing ones send emails
+	EmailEvents map[SystemEmailEvent]bool `bson:"emailEvents,omitempty" json:"emailEvents,omitempty"`
 
 	// Category preferences
 	Categories map[NotificationCategory]bool `bson:"categories" json:"categories"`
@@ -130,6 +145,7 @@ type SendNotificationRequest struct {
 	Sound       string                   `json:"sound,omitempty"`
 	Badge       *int                     `json:"badge,omitempty"`
 	ExpiresIn   *int                     `json:"expiresIn,omitempty"`   // Expiration in seconds
+	Announcement bool                    `json:"-"`                     // Group the copies under a broadcast ID so recipients can react
 }
 
 // UpdatePreferencesRequest represents a request to update notification preferences
@@ -139,6 +155,8 @@ type UpdatePreferencesRequest struct {
 	InAppEnabled      *bool                            `json:"inAppEnabled,omitempty"`
 	SoundEnabled      *bool                            `json:"soundEnabled,omitempty"`
 	BadgeEnabled      *bool                            `json:"badgeEnabled,omitempty"`
+	EmailFrequency    *EmailFrequency                  `json:"emailFrequency,omitempty"`
+	EmailEvents       map[SystemEmailEvent]bool        `json:"emailEvents,omitempty"` // The events left out keep their setting
 	Categories        map[NotificationCategory]bool    `json:"categories,omitempty"`
 	DevicePreferences map[string]DevicePreferences     `json:"devicePreferences,omitempty"`
 	QuietHoursEnabled *bool                            `json:"quietHoursEnabled,omitempty"`
@@ -154,6 +172,7 @@ type NotificationSummary struct {
 	Today      int64 `json:"today"`
 	ThisWeek   int64 `json:"thisWeek"`
 	Failed     int64 `json:"failed"`
+	BroadcastID *primitive.ObjectID `json:"broadcastId,omitempty"` // Set when an announcement was sent
 }
 
 // Helper methods
@@ -173,7 +192,7 @@ func IsValidNotificationCategory(category NotificationCategory) bool {
 	switch category {
 	case NotificationCategoryLogin, NotificationCategoryActivity, NotificationCategorySystem,
 		 NotificationCategoryReminder, NotificationCategoryApproval, NotificationCategoryMeeting,
-		 NotificationCategoryUpdate, NotificationCategoryAlert:
+		 NotificationCategoryUpdate, NotificationCategoryAlert, NotificationCategoryDocument:
 		return true
 	default:
 		return false
@@ -190,6 +209,28 @@ func IsValidNotificationPriority(priority NotificationPriority) bool {
 	}
 }
 
+// IsValidEmailFrequency checks if an email frequency is valid
+func IsValidEmailFrequency(frequency EmailFrequency) bool {
+	switch frequency {
+	case EmailFrequencyImmediate, EmailFrequencyHourly, EmailFrequencyDaily:
+		return true
+	default:
+		return false
+	}
+}
+
+// UsesEmailDigest tells whether the notification emails of the user are
+// grouped in a digest instead of being sent one by one
+func (p *NotificationPreferences) UsesEmailDigest() bool {
+	return p.EmailEnabled && (p.EmailFrequency == EmailFrequencyHourly || p.EmailFrequency == EmailFrequencyDaily)
+}
+
+// EmailEventEnabled tells whether the user receives the emails of an event
+func (p *NotificationPreferences) EmailEventEnabled(event SystemEmailEvent) bool {
+	enabled, ok := p.EmailEvents[event]
+	return !ok || enabled
+}
+
 // MarkAsRead marks the notification as read
 func (n *Notification) MarkAsRead() {
 	if n.Status != NotificationStatusRead {
@@ -234,6 +275,7 @@ func GetDefaultNotificationPreferences(userID primitive.ObjectID) *NotificationP
 		InAppEnabled:  true,
 		SoundEnabled:  true,
 		BadgeEnabled:  true,
+		EmailFrequency: EmailFrequencyImmediate,
 		Categories: map[NotificationCategory]bool{
 			NotificationCategoryLogin:    true,
 			NotificationCategoryActivity: true,
@@ -243,6 +285,7 @@ func GetDefaultNotificationPreferences(userID primitive.ObjectID) *NotificationP
 			NotificationCategoryMeeting:  true,
 			NotificationCategoryUpdate:   true,
 			NotificationCategoryAlert:    true,
+			NotificationCategoryDocument: true,
 		},
 		QuietHoursEnabled: false,
 		CreatedAt:         time.Now(),
@@ -258,4 +301,6 @@ var (
 	ErrInvalidPriority         = errors.New("invalid notification priority")
 	ErrNotificationExpired     = errors.New("notification has expired")
 	ErrPreferencesNotFound     = errors.New("notification preferences not found")
+	ErrNotAnnouncement         = errors.New("notification is not an announcement")
+	ErrInvalidEmailFrequency   = errors.New("invalid email frequency: must be immediate, hourly or daily")
 )
\ No newline at end of file
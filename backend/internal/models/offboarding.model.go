@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OffboardingAction is an endpoint resolving an item blocking an offboarding
+type OffboardingAction struct {
+	Method      string `json:"method"`
+	Href        string `json:"href"`
+	Description string `json:"description"`
+}
+
+// OffboardingDocument is a document created by the departing user
+type OffboardingDocument struct {
+	ID        primitive.ObjectID  `json:"id"`
+	Reference string              `json:"reference"`
+	Title     string              `json:"title"`
+	Status    DocumentStatus      `json:"status"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+	Actions   []OffboardingAction `json:"actions"`
+}
+
+// OffboardingSignature is a signature the departing user still owes on a
+// published document
+type OffboardingSignature struct {
+	DocumentID primitive.ObjectID  `json:"documentId"`
+	Reference  string              `json:"reference"`
+	Title      string              `json:"title"`
+	Status     DocumentStatus      `json:"status"`
+	Team       ContributorTeam     `json:"team"`
+	Actions    []OffboardingAction `json:"actions"`
+}
+
+// OffboardingInvitation is a pending invitation sent by the departing user
+type OffboardingInvitation struct {
+	ID           primitive.ObjectID  `json:"id"`
+	DocumentID   primitive.ObjectID  `json:"documentId"`
+	InvitedEmail string              `json:"invitedEmail"`
+	Team         ContributorTeam     `json:"team"`
+	ExpiresAt    time.Time           `json:"expiresAt"`
+	Actions      []OffboardingAction `json:"actions"`
+}
+
+// OffboardingReport lists everything to hand over before a user leaves
+type OffboardingReport struct {
+	UserID            primitive.ObjectID      `json:"userId"`
+	Name              string                  `json:"name"`
+	Email             string                  `json:"email"`
+	Clean             bool                    `json:"clean"` // Nothing blocks the offboarding
+	OwnedDocuments    []OffboardingDocument   `json:"ownedDocuments"`
+	PendingSignatures []OffboardingSignature  `json:"pendingSignatures"`
+	OpenInvitations   []OffboardingInvitation `json:"openInvitations"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PDFTemplateKind is the kind of PDF a template lays out
+type PDFTemplateKind string
+
+const (
+	PDFTemplateKindDocument PDFTemplateKind = "document"
+	PDFTemplateKindMacro    PDFTemplateKind = "macro"
+)
+
+// PDFTemplate is a corporate layout of the document or macro PDFs. The source
+// is an HTML/CSS Go template receiving the same data and functions as the
+// built-in layout, such as {{.Title}} or {{branding.CompanyName}}. The PDF of
+// a document uses the template of the department of its creator, then the
+// default template of the organization, then the built-in layout.
+type PDFTemplate struct {
+	ID            primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Name          string               `json:"name" bson:"name"`
+	Description   string               `json:"description,omitempty" bson:"description,omitempty"`
+	Kind          PDFTemplateKind      `json:"kind" bson:"kind"`
+	Source        string               `json:"source" bson:"source"`
+	IsDefault     bool                 `json:"isDefault" bson:"is_default"`         // Used by the organization when no department template applies
+	DepartmentIDs []primitive.ObjectID `json:"departmentIds" bson:"department_ids"` // Departments using this template, one template per kind and department
+	CreatedBy     primitive.ObjectID   `json:"createdBy" bson:"created_by"`
+	UpdatedBy     primitive.ObjectID   `json:"updatedBy" bson:"updated_by"`
+	CreatedAt     time.Time            `json:"createdAt" bson:"created_at"`
+	UpdatedAt     time.Time            `json:"updatedAt" bson:"updated_at"`
+}
+
+// CreatePDFTemplateRequest represents the request to create a PDF template
+type CreatePDFTemplateRequest struct {
+	Name          string          `json:"name" validate:"required,min=2,max=200"`
+	Description   string          `json:"description" validate:"max=1000"`
+	Kind          PDFTemplateKind `json:"kind" validate:"required,oneof=document macro"`
+	Source        string          `json:"source" validate:"required,max=524288"`
+	IsDefault     bool            `json:"isDefault"`
+	DepartmentIDs []string        `json:"departmentIds" validate:"omitempty,dive,len=24,hexadecimal"`
+}
+
+// UpdatePDFTemplateRequest represents the request to update a PDF template
+type UpdatePDFTemplateRequest struct {
+	Name          *string   `json:"name" validate:"omitempty,min=2,max=200"`
+	Description   *string   `json:"description" validate:"omitempty,max=1000"`
+	Source        *string   `json:"source" validate:"omitempty,max=524288"`
+	IsDefault     *bool     `json:"isDefault"`
+	DepartmentIDs *[]string `json:"departmentIds" validate:"omitempty,dive,len=24,hexadecimal"`
+}
+
+// PreviewPDFTemplateRequest renders an unsaved template source with a
+// document, or a macro and its processes
+type PreviewPDFTemplateRequest struct {
+	Kind       PDFTemplateKind `json:"kind" validate:"required,oneof=document macro"`
+	Source     string          `json:"source" validate:"required,max=524288"`
+	DocumentID string          `json:"documentId" validate:"omitempty,len=24,hexadecimal"`
+	MacroID    string          `json:"macroId" validate:"omitempty,len=24,hexadecimal"`
+	Format     string          `json:"format" validate:"omitempty,oneof=html pdf"`
+}
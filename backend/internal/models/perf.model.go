@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// EndpointLatency represents the latency percentiles of one route
+type EndpointLatency struct {
+	Method string  `json:"method"`
+	Path   string  `json:"path"`
+	Count  int64   `json:"count"`
+	Errors int64   `json:"errors"` // Responses with a 5xx status
+	P50Ms  float64 `json:"p50Ms"`
+	P95Ms  float64 `json:"p95Ms"`
+	P99Ms  float64 `json:"p99Ms"`
+	MaxMs  float64 `json:"maxMs"`
+}
+
+// SlowQuery represents a MongoDB command that exceeded the slow query threshold
+type SlowQuery struct {
+	Command    string    `json:"command"`
+	Database   string    `json:"database"`
+	Collection string    `json:"collection,omitempty"`
+	DurationMs float64   `json:"durationMs"`
+	Failed     bool      `json:"failed"`
+	Error      string    `json:"error,omitempty"`
+	Query      string    `json:"query,omitempty"` // Truncated command document
+	At         time.Time `json:"at"`
+}
+
+// PerfReport represents the profiling data collected since the last reset
+type PerfReport struct {
+	Enabled              bool              `json:"enabled"`
+	Since                time.Time         `json:"since"`
+	SlowQueryThresholdMs int64             `json:"slowQueryThresholdMs"`
+	Endpoints            []EndpointLatency `json:"endpoints"`
+	SlowQueries          []SlowQuery       `json:"slowQueries"` // Most recent first
+}
+
+// AsyncRunnerStats represents the counters of the background tasks of the handlers
+type AsyncRunnerStats struct {
+	MaxConcurrency int              `json:"maxConcurrency"`
+	MaxQueued      int64            `json:"maxQueued"`
+	TimeoutSeconds int              `json:"timeoutSeconds"`
+	InFlight       int64            `json:"inFlight"`
+	Queued         int64            `json:"queued"`
+	Tasks          []AsyncTaskStats `json:"tasks"` // By task name
+}
+
+// AsyncTaskStats represents the counters of the background tasks of a name
+type AsyncTaskStats struct {
+	Name      string `json:"name"`
+	InFlight  int64  `json:"inFlight"`
+	Queued    int64  `json:"queued"`
+	Completed int64  `json:"completed"`
+	Failed    int64  `json:"failed"`
+	Panicked  int64  `json:"panicked"`
+	Dropped   int64  `json:"dropped"`
+}
+
+// PDFRenderStats represents the counters of the PDF render pool
+type PDFRenderStats struct {
+	Renderer       string  `json:"renderer"`
+	MaxConcurrency int     `json:"maxConcurrency"`
+	MaxQueued      int64   `json:"maxQueued"`
+	InFlight       int64   `json:"inFlight"`
+	Queued         int64   `json:"queued"` // Renders waiting for a slot
+	Completed      int64   `json:"completed"`
+	Failed         int64   `json:"failed"`
+	Rejected       int64   `json:"rejected"` // Refused because the queue was full or the wait too long
+	P50Ms          float64 `json:"p50Ms"`    // Render durations of the recent renders
+	P95Ms          float64 `json:"p95Ms"`
+	MaxMs          float64 `json:"maxMs"`
+	AvgWaitMs      float64 `json:"avgWaitMs"` // Time spent waiting for a slot by the recent renders
+}
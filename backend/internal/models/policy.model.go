@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PolicyType represents a kind of policy users must accept
+type PolicyType string
+
+const (
+	PolicyTermsOfService PolicyType = "terms_of_service"
+	PolicyPrivacy        PolicyType = "privacy_policy"
+)
+
+// PolicyStatus represents the lifecycle of a policy version
+type PolicyStatus string
+
+const (
+	PolicyStatusDraft      PolicyStatus = "draft"
+	PolicyStatusPublished  PolicyStatus = "published"  // Current version, users must accept it
+	PolicyStatusSuperseded PolicyStatus = "superseded" // Replaced by a newer published version
+)
+
+// Policy represents one version of a policy document
+type Policy struct {
+	ID          primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Type        PolicyType          `json:"type" bson:"type"`
+	Version     string              `json:"version" bson:"version"`
+	Title       string              `json:"title" bson:"title"`
+	Content     string              `json:"content" bson:"content"`
+	Summary     string              `json:"summary,omitempty" bson:"summary,omitempty"` // What changed since the previous version
+	Status      PolicyStatus        `json:"status" bson:"status"`
+	PublishedAt *time.Time          `json:"publishedAt,omitempty" bson:"published_at,omitempty"`
+	PublishedBy *primitive.ObjectID `json:"publishedBy,omitempty" bson:"published_by,omitempty"`
+	CreatedBy   primitive.ObjectID  `json:"createdBy" bson:"created_by"`
+	CreatedAt   time.Time           `json:"createdAt" bson:"created_at"`
+	UpdatedAt   time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// PolicyAcceptance records that a user accepted a policy version
+type PolicyAcceptance struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"userId" bson:"user_id"`
+	PolicyID   primitive.ObjectID `json:"policyId" bson:"policy_id"`
+	Type       PolicyType         `json:"type" bson:"type"`
+	Version    string             `json:"version" bson:"version"`
+	IPAddress  string             `json:"ipAddress,omitempty" bson:"ip_address,omitempty"`
+	UserAgent  string             `json:"userAgent,omitempty" bson:"user_agent,omitempty"`
+	AcceptedAt time.Time          `json:"acceptedAt" bson:"accepted_at"`
+}
+
+// CreatePolicyRequest represents the request to draft a policy version
+type CreatePolicyRequest struct {
+	Type    PolicyType `json:"type" validate:"required,oneof=terms_of_service privacy_policy"`
+	Version string     `json:"version" validate:"required,max=20"`
+	Title   string     `json:"title" validate:"required,max=200"`
+	Content string     `json:"content" validate:"required"`
+	Summary string     `json:"summary" validate:"max=1000"`
+}
+
+// UpdatePolicyRequest represents the request to update a draft policy version
+type UpdatePolicyRequest struct {
+	Title   *string `json:"title" validate:"omitempty,max=200"`
+	Content *string `json:"content"`
+	Summary *string `json:"summary" validate:"omitempty,max=1000"`
+}
+
+// AcceptPoliciesRequest represents the request to accept published policy versions
+type AcceptPoliciesRequest struct {
+	PolicyIDs []string `json:"policyIds" validate:"required,min=1"`
+}
+
+// PolicyAcceptanceStats represents the acceptance completion of a published policy
+type PolicyAcceptanceStats struct {
+	PolicyID       primitive.ObjectID `json:"policyId"`
+	Type           PolicyType         `json:"type"`
+	Version        string             `json:"version"`
+	PublishedAt    *time.Time         `json:"publishedAt,omitempty"`
+	AcceptedCount  int64              `json:"acceptedCount"`
+	TotalUsers     int64              `json:"totalUsers"`
+	CompletionRate float64            `json:"completionRate"` // Percentage of active users who accepted
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PresenceMode is what a user does on an open document
+type PresenceMode string
+
+const (
+	PresenceModeViewing PresenceMode = "viewing"
+	PresenceModeEditing PresenceMode = "editing"
+)
+
+// DocumentPresence is a user who currently has a document open
+type DocumentPresence struct {
+	UserID     primitive.ObjectID `json:"userId"`
+	FirstName  string             `json:"firstName"`
+	LastName   string             `json:"lastName"`
+	Avatar     string             `json:"avatar,omitempty"`
+	Mode       PresenceMode       `json:"mode"`
+	LastSeenAt time.Time          `json:"lastSeenAt"`
+	IsCurrent  bool               `json:"isCurrent"` // The user making the request
+}
+
+// DocumentPresenceResponse lists the users who have a document open
+type DocumentPresenceResponse struct {
+	DocumentID        string             `json:"documentId"`
+	Users             []DocumentPresence `json:"users"`
+	HeartbeatInterval int                `json:"heartbeatInterval"` // Seconds between two heartbeats of the clients
+}
+
+// PresenceHeartbeatRequest tells that the current user still has a document open
+type PresenceHeartbeatRequest struct {
+	Mode PresenceMode `json:"mode" validate:"omitempty,oneof=viewing editing"`
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QMSConnectorSettingsID is the key of the single QMS connector settings document
+const QMSConnectorSettingsID = "qms_connector"
+
+// QMSSourceFields lists the document fields that can be mapped to the fields of the external QMS
+var QMSSourceFields = []string{
+	"id", "reference", "processCode", "macroId", "title", "shortDescription", "description",
+	"version", "status", "stakeholders", "effectiveDate", "supersessionDate", "approvedAt",
+	"nextReviewDate", "supersedes", "supersededBy", "authors", "verifiers", "validators", "pdfUrl",
+}
+
+// DefaultQMSFieldMapping sends the document fields under their own names
+func DefaultQMSFieldMapping() map[string]string {
+	return map[string]string{
+		"id":            "id",
+		"reference":     "reference",
+		"title":         "title",
+		"version":       "version",
+		"status":        "status",
+		"effectiveDate": "effectiveDate",
+		"pdfUrl":        "pdfUrl",
+	}
+}
+
+// QMSConnector configures the outbound sync of published documents to an external quality-management system
+type QMSConnector struct {
+	ID           string              `json:"-" bson:"_id"`
+	Enabled      bool                `json:"enabled" bson:"enabled"`
+	Endpoint     string              `json:"endpoint" bson:"endpoint"`          // Documents are POSTed as JSON to this URL
+	AuthHeader   string              `json:"authHeader" bson:"auth_header"`     // Header carrying the API key, Authorization by default
+	APIKey       string              `json:"-" bson:"api_key"`                  // Never returned, see HasAPIKey
+	HasAPIKey    bool                `json:"hasApiKey" bson:"-"`                // Set when an API key is configured
+	FieldMapping map[string]string   `json:"fieldMapping" bson:"field_mapping"` // QMS field name -> document field, see QMSSourceFields
+	IncludePDF   bool                `json:"includePdf" bson:"include_pdf"`     // Embed the PDF in the payload, base64 encoded
+	MaxAttempts  int                 `json:"maxAttempts" bson:"max_attempts"`   // Failed syncs are retried until this many attempts
+	UpdatedBy    *primitive.ObjectID `json:"updatedBy,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt    time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// DefaultQMSConnector returns the connector used until an administrator configures one
+func DefaultQMSConnector() *QMSConnector {
+	return &QMSConnector{
+		ID:           QMSConnectorSettingsID,
+		AuthHeader:   "Authorization",
+		FieldMapping: DefaultQMSFieldMapping(),
+		IncludePDF:   true,
+		MaxAttempts:  5,
+	}
+}
+
+// UpdateQMSConnectorRequest represents the request to update the QMS connector settings
+type UpdateQMSConnectorRequest struct {
+	Enabled      *bool              `json:"enabled"`
+	Endpoint     *string            `json:"endpoint" validate:"omitempty,url"`
+	AuthHeader   *string            `json:"authHeader" validate:"omitempty,max=100"`
+	APIKey       *string            `json:"apiKey" validate:"omitempty,max=500"` // An empty string removes the key
+	FieldMapping *map[string]string `json:"fieldMapping"`
+	IncludePDF   *bool              `json:"includePdf"`
+	MaxAttempts  *int               `json:"maxAttempts" validate:"omitempty,min=1,max=20"`
+}
+
+// QMSSyncStatus represents the sync state of a document with the external QMS
+type QMSSyncStatus string
+
+const (
+	QMSSyncStatusPending QMSSyncStatus = "pending" // Waiting for its next attempt
+	QMSSyncStatusSyncing QMSSyncStatus = "syncing"
+	QMSSyncStatusSynced  QMSSyncStatus = "synced"
+	QMSSyncStatusFailed  QMSSyncStatus = "failed" // Last attempt failed, retried at NextAttemptAt unless attempts are exhausted
+)
+
+// IsValidQMSSyncStatus checks if the status is valid
+func IsValidQMSSyncStatus(status string) bool {
+	switch QMSSyncStatus(status) {
+	case QMSSyncStatusPending, QMSSyncStatusSyncing, QMSSyncStatusSynced, QMSSyncStatusFailed:
+		return true
+	}
+	return false
+}
+
+// QMSSync tracks the sync of one document with the external QMS
+type QMSSync struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DocumentID    primitive.ObjectID `json:"documentId" bson:"document_id"`
+	Reference     string             `json:"reference" bson:"reference"`
+	Version       string             `json:"version" bson:"version"`
+	Status        QMSSyncStatus      `json:"status" bson:"status"`
+	Attempts      int                `json:"attempts" bson:"attempts"`
+	LastError     string             `json:"lastError,omitempty" bson:"last_error,omitempty"`
+	LastResponse  int                `json:"lastResponse,omitempty" bson:"last_response,omitempty"` // HTTP status of the last attempt
+	NextAttemptAt *time.Time         `json:"nextAttemptAt,omitempty" bson:"next_attempt_at,omitempty"`
+	LastAttemptAt *time.Time         `json:"lastAttemptAt,omitempty" bson:"last_attempt_at,omitempty"`
+	SyncedAt      *time.Time         `json:"syncedAt,omitempty" bson:"synced_at,omitempty"`
+	SyncedVersion string             `json:"syncedVersion,omitempty" bson:"synced_version,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
+	UpdatedAt     time.Time          `json:"updatedAt" bson:"updated_at"`
+}
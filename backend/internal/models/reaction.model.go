@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReactionType represents a lightweight acknowledgment reaction
+type ReactionType string
+
+const (
+	ReactionAck      ReactionType = "ack"
+	ReactionQuestion ReactionType = "question"
+	ReactionThumbsUp ReactionType = "thumbs_up"
+)
+
+// IsValidReactionType checks if a reaction type is supported
+func IsValidReactionType(reactionType ReactionType) bool {
+	switch reactionType {
+	case ReactionAck, ReactionQuestion, ReactionThumbsUp:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReactionTargetType identifies what a reaction is attached to
+type ReactionTargetType string
+
+const (
+	ReactionTargetComment   ReactionTargetType = "comment"
+	ReactionTargetBroadcast ReactionTargetType = "broadcast" // Announcement sent to many users
+)
+
+// Reaction represents one user's reaction on a comment or broadcast announcement
+type Reaction struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TargetType ReactionTargetType `json:"targetType" bson:"target_type"`
+	TargetID   primitive.ObjectID `json:"targetId" bson:"target_id"`
+	UserID     primitive.ObjectID `json:"userId" bson:"user_id"`
+	Type       ReactionType       `json:"type" bson:"type"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// ReactionSummary represents the aggregate reactions of a target
+type ReactionSummary struct {
+	Counts map[ReactionType]int64 `json:"counts"`
+	Total  int64                  `json:"total"`
+	Mine   []ReactionType         `json:"mine"` // Reactions of the current user
+}
+
+// NewReactionSummary returns an empty summary
+func NewReactionSummary() *ReactionSummary {
+	return &ReactionSummary{
+		Counts: map[ReactionType]int64{},
+		Mine:   []ReactionType{},
+	}
+}
+
+// ReactRequest represents the request to add a reaction
+type ReactRequest struct {
+	Type ReactionType `json:"type" binding:"required"`
+}
+
+// BroadcastReactionsResponse represents the acknowledgment of an announcement
+type BroadcastReactionsResponse struct {
+	BroadcastID primitive.ObjectID `json:"broadcastId"`
+	Recipients  int64              `json:"recipients"`
+	Reactions   *ReactionSummary   `json:"reactions"`
+}
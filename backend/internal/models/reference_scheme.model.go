@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReferenceSchemeSettingsID is the key of the single reference scheme settings document
+const ReferenceSchemeSettingsID = "reference_scheme"
+
+// Reference pattern tokens
+const (
+	ReferenceTokenDepartment = "{DEPT}"  // Code of the department of the document
+	ReferenceTokenMacro      = "{MACRO}" // Code of the macro process
+	ReferenceTokenYear       = "{YEAR}"  // Year of creation
+	ReferenceTokenSequence   = "{SEQ}"   // Counter, one per value of the other tokens
+)
+
+// ReferenceScheme configures how document references are generated, e.g.
+// "{DEPT}_{MACRO}_{SEQ}" gives DSI_M1_001 then DSI_M1_002.
+type ReferenceScheme struct {
+	ID              string              `json:"-" bson:"_id"`
+	Pattern         string              `json:"pattern" bson:"pattern"`
+	SequencePadding int                 `json:"sequencePadding" bson:"sequence_padding"` // Minimum number of digits of {SEQ}
+	DefaultDept     string              `json:"defaultDept" bson:"default_dept"`         // Used for {DEPT} when no department is known
+	UpdatedBy       *primitive.ObjectID `json:"updatedBy,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt       time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// DefaultReferenceScheme returns the scheme used until an administrator configures one
+func DefaultReferenceScheme() *ReferenceScheme {
+	return &ReferenceScheme{
+		ID:              ReferenceSchemeSettingsID,
+		Pattern:         ReferenceTokenDepartment + "_" + ReferenceTokenMacro + "_" + ReferenceTokenSequence,
+		SequencePadding: 3,
+		DefaultDept:     "GEN",
+	}
+}
+
+// ReferenceCounter holds the last sequence number issued for a reference prefix
+type ReferenceCounter struct {
+	ID        string    `json:"key" bson:"_id"` // Pattern with every token but {SEQ} resolved
+	Sequence  int64     `json:"sequence" bson:"sequence"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updated_at"`
+}
+
+// UpdateReferenceSchemeRequest represents the request to update the reference scheme
+type UpdateReferenceSchemeRequest struct {
+	Pattern         *string `json:"pattern" validate:"omitempty,min=5,max=100"`
+	SequencePadding *int    `json:"sequencePadding" validate:"omitempty,min=1,max=8"`
+	DefaultDept     *string `json:"defaultDept" validate:"omitempty,min=1,max=20"`
+}
+
+// PreviewReferenceRequest represents the request to preview the reference of a new document
+type PreviewReferenceRequest struct {
+	MacroID      string `json:"macroId" validate:"required"`
+	DepartmentID string `json:"departmentId"` // Defaults to the department of the current user
+}
+
+// ReferencePreview is the reference the next document would receive.
+// It is not reserved, a concurrent creation may take it first.
+type ReferencePreview struct {
+	Reference string `json:"reference"`
+	Pattern   string `json:"pattern"`
+}
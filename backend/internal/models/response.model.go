@@ -13,10 +13,12 @@ type SuccessResponse struct {
 
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error"`
-	Code    string `json:"code,omitempty"`
-	Details string `json:"details,omitempty"`
+	Success   bool        `json:"success"`
+	Error     string      `json:"error"`
+	Code      string      `json:"code,omitempty"`
+	Details   string      `json:"details,omitempty"`
+	RequestID string      `json:"requestId,omitempty"`
+	Data      interface{} `json:"data,omitempty"` // Current state of the resource on conflicts
 }
 
 // PaginatedResponse represents a paginated response
@@ -79,11 +81,18 @@ const (
 	// Permission error codes
 	CodeForbidden        = "FORBIDDEN"
 	CodeInsufficientRole = "INSUFFICIENT_ROLE"
+	CodeSectionLocked    = "SECTION_LOCKED"
+
+	// Concurrency error codes
+	CodeRevisionConflict = "REVISION_CONFLICT"
+	CodeRevisionRequired = "REVISION_REQUIRED"
 
 	// Server error codes
 	CodeInternalError = "INTERNAL_ERROR"
 	CodeDatabaseError = "DATABASE_ERROR"
 	CodeServiceError  = "SERVICE_ERROR"
+	CodeTimeout       = "REQUEST_TIMEOUT"
+	CodeServiceBusy   = "SERVICE_BUSY"
 )
 
 // ============================================
@@ -1,8 +1,10 @@
 package models
 
 import (
+	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -25,8 +27,17 @@ type Signature struct {
 	Comments      string             `bson:"comments,omitempty" json:"comments,omitempty"`
 	IPAddress     string             `bson:"ip_address" json:"ipAddress"`
 	UserAgent     string             `bson:"user_agent" json:"userAgent"`
+	Version       string             `bson:"version,omitempty" json:"version,omitempty"` // Document version signed
 	SignedAt      time.Time          `bson:"signed_at" json:"signedAt"`
 	CreatedAt     time.Time          `bson:"created_at" json:"createdAt"`
+	InvalidatedAt *time.Time         `bson:"invalidated_at,omitempty" json:"invalidatedAt,omitempty"` // Set when a rejection returned the document to its authors
+}
+
+// ActiveSignatures restricts a signature filter to the signatures not
+// invalidated by a rejection
+func ActiveSignatures(filter bson.M) bson.M {
+	filter["invalidated_at"] = bson.M{"$exists": false}
+	return filter
 }
 
 // SignatureResponse represents the API response for a signature
@@ -41,6 +52,7 @@ type SignatureResponse struct {
 	Comments      string        `json:"comments,omitempty"`
 	IPAddress     string        `json:"ipAddress"`
 	UserAgent     string        `json:"userAgent"`
+	Version       string        `json:"version,omitempty"`
 	SignedAt      time.Time     `json:"signedAt"`
 	CreatedAt     time.Time     `json:"createdAt"`
 }
@@ -63,6 +75,7 @@ func (s *Signature) ToResponse() SignatureResponse {
 		Comments:      s.Comments,
 		IPAddress:     s.IPAddress,
 		UserAgent:     s.UserAgent,
+		Version:       s.Version,
 		SignedAt:      s.SignedAt,
 		CreatedAt:     s.CreatedAt,
 	}
@@ -78,6 +91,35 @@ func IsValidSignatureType(sigType SignatureType) bool {
 	}
 }
 
+// Signature methods reported in the signatures register
+const (
+	SignatureMethodImage = "image" // Drawn or uploaded signature image
+	SignatureMethodTyped = "typed" // Typed name
+)
+
+// Method returns how the signature was captured
+func (s *Signature) Method() string {
+	if strings.HasPrefix(s.SignatureData, "data:image/") {
+		return SignatureMethodImage
+	}
+	return SignatureMethodTyped
+}
+
+// SignatureRegisterEntry represents one line of the signatures register
+type SignatureRegisterEntry struct {
+	SignatureID       primitive.ObjectID `json:"signatureId"`
+	SignedAt          time.Time          `json:"signedAt"`
+	DocumentID        primitive.ObjectID `json:"documentId"`
+	DocumentReference string             `json:"documentReference"`
+	DocumentTitle     string             `json:"documentTitle"`
+	Version           string             `json:"version"`
+	SignerName        string             `json:"signerName"`
+	SignerEmail       string             `json:"signerEmail"`
+	Role              SignatureType      `json:"role"`
+	Method            string             `json:"method"`
+	IPAddress         string             `json:"ipAddress,omitempty"`
+}
+
 // BeforeCreate sets timestamps before creating a signature
 func (s *Signature) BeforeCreate() {
 	now := time.Now()
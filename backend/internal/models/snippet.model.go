@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Snippet is a sequence of process steps shared by several documents, such as
+// the standard escalation steps. Documents embed a copy of the steps which
+// keeps a reference to the snippet and the version it was copied from.
+type Snippet struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	Steps       []SnippetStep      `json:"steps" bson:"steps"`
+	Version     int                `json:"version" bson:"version"` // Incremented whenever the steps change
+	CreatedBy   primitive.ObjectID `json:"createdBy" bson:"created_by"`
+	UpdatedBy   primitive.ObjectID `json:"updatedBy" bson:"updated_by"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// SnippetStep is a process step of a snippet
+type SnippetStep struct {
+	Title        string               `json:"title" bson:"title" validate:"required"`
+	Outputs      []string             `json:"outputs" bson:"outputs"`
+	Durations    []string             `json:"durations" bson:"durations"`
+	Responsible  string               `json:"responsible" bson:"responsible"`
+	Descriptions []ProcessDescription `json:"descriptions" bson:"descriptions"`
+}
+
+// SnippetRef links an embedded process step to the snippet it was copied from
+type SnippetRef struct {
+	SnippetID primitive.ObjectID `json:"snippetId" bson:"snippet_id"`
+	Version   int                `json:"version" bson:"version"`
+}
+
+// CreateSnippetRequest represents the request to create a snippet
+type CreateSnippetRequest struct {
+	Name        string        `json:"name" validate:"required,min=2,max=200"`
+	Description string        `json:"description" validate:"max=1000"`
+	Steps       []SnippetStep `json:"steps" validate:"required,min=1,dive"`
+}
+
+// UpdateSnippetRequest represents the request to update a snippet. Changing
+// the steps creates a new version of the snippet.
+type UpdateSnippetRequest struct {
+	Name        *string        `json:"name" validate:"omitempty,min=2,max=200"`
+	Description *string        `json:"description" validate:"omitempty,max=1000"`
+	Steps       *[]SnippetStep `json:"steps" validate:"omitempty,min=1,dive"`
+}
+
+// EmbedSnippetRequest represents the request to embed a snippet into a
+// process group of a document
+type EmbedSnippetRequest struct {
+	GroupID  string `json:"groupId" validate:"required"`
+	Position *int   `json:"position" validate:"omitempty,min=0"` // Index of the first embedded step, appended when nil
+}
+
+// SnippetFilter represents filtering options for snippets
+type SnippetFilter struct {
+	Search *string
+	Page   int
+	Limit  int
+}
+
+// SnippetUsage is a document embedding a snippet, with the versions of the
+// snippet its steps were copied from
+type SnippetUsage struct {
+	SnippetID      string         `json:"snippetId"`
+	SnippetName    string         `json:"snippetName"`
+	CurrentVersion int            `json:"currentVersion"`
+	DocumentID     string         `json:"documentId"`
+	Reference      string         `json:"reference"`
+	Title          string         `json:"title"`
+	Status         DocumentStatus `json:"status"`
+	Versions       []int          `json:"versions"` // Embedded versions, oldest first
+	StepCount      int            `json:"stepCount"`
+	Outdated       bool           `json:"outdated"` // Some steps were copied from an older version
+}
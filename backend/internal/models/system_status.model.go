@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SystemComponent identifies a dependency monitored by the status page
+type SystemComponent string
+
+const (
+	ComponentDatabase SystemComponent = "database"
+	ComponentRedis    SystemComponent = "redis"
+	ComponentMinIO    SystemComponent = "minio"
+	ComponentEmail    SystemComponent = "email"
+	ComponentPush     SystemComponent = "push"
+)
+
+// ComponentState represents the current state of a component
+type ComponentState string
+
+const (
+	ComponentStateOperational ComponentState = "operational"
+	ComponentStateDegraded    ComponentState = "degraded"
+	ComponentStateDown        ComponentState = "down"
+	ComponentStateDisabled    ComponentState = "disabled" // Optional dependency not configured
+)
+
+// HealthSample represents a single periodic health check result
+type HealthSample struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Component SystemComponent    `bson:"component" json:"component"`
+	State     ComponentState     `bson:"state" json:"state"`
+	LatencyMs int64              `bson:"latency_ms" json:"latencyMs"`
+	Error     string             `bson:"error,omitempty" json:"error,omitempty"`
+	CheckedAt time.Time          `bson:"checked_at" json:"checkedAt"`
+}
+
+// StatusIncident represents a period during which a component was unavailable
+type StatusIncident struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Component  SystemComponent    `bson:"component" json:"component"`
+	State      ComponentState     `bson:"state" json:"state"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`
+	StartedAt  time.Time          `bson:"started_at" json:"startedAt"`
+	ResolvedAt *time.Time         `bson:"resolved_at,omitempty" json:"resolvedAt,omitempty"`
+}
+
+// StatusAlertEvent identifies why a status alert is sent
+type StatusAlertEvent string
+
+const (
+	StatusAlertDown      StatusAlertEvent = "down"      // Component failed several consecutive checks
+	StatusAlertRecovered StatusAlertEvent = "recovered" // Component is back after an alert
+)
+
+// StatusAlert is the payload sent to administrators and alert webhooks
+type StatusAlert struct {
+	Event     StatusAlertEvent `json:"event"`
+	Component SystemComponent  `json:"component"`
+	State     ComponentState   `json:"state"`
+	Error     string           `json:"error,omitempty"`
+	DownSince time.Time        `json:"downSince"`
+	Failures  int              `json:"consecutiveFailures"`
+	Message   string           `json:"message"`
+	At        time.Time        `json:"at"`
+}
+
+// ============================================
+// Status Page Response Models
+// ============================================
+
+// UptimeBucket aggregates the health samples of a component over one hour
+type UptimeBucket struct {
+	Start         time.Time      `json:"start"`
+	Samples       int            `json:"samples"`
+	UptimePercent float64        `json:"uptimePercent"`
+	AvgLatencyMs  int64          `json:"avgLatencyMs"`
+	State         ComponentState `json:"state"` // Worst state of the hour
+}
+
+// ComponentStatus summarizes the health of a component over the requested window
+type ComponentStatus struct {
+	Component     SystemComponent `json:"component"`
+	State         ComponentState  `json:"state"`
+	LatencyMs     int64           `json:"latencyMs"`
+	UptimePercent float64         `json:"uptimePercent"`
+	LastCheckedAt *time.Time      `json:"lastCheckedAt,omitempty"`
+	Uptime        []UptimeBucket  `json:"uptime"`
+	History       []HealthSample  `json:"history,omitempty"` // Raw samples, admin only
+}
+
+// SystemStatusResponse represents the public status page payload
+type SystemStatusResponse struct {
+	Status          ComponentState    `json:"status"`
+	Version         string            `json:"version"`
+	StartedAt       time.Time         `json:"startedAt"`
+	UptimeSeconds   int64             `json:"uptimeSeconds"`
+	WindowHours     int               `json:"windowHours"`
+	Components      []ComponentStatus `json:"components"`
+	RecentIncidents []StatusIncident  `json:"recentIncidents"`
+	GeneratedAt     time.Time         `json:"generatedAt"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TriageSource is how a document waiting for triage entered the platform
+type TriageSource string
+
+const (
+	TriageSourceImport TriageSource = "import" // Spreadsheet bulk import
+)
+
+// TriageResolution is the outcome of the triage of a document
+type TriageResolution string
+
+const (
+	TriageResolutionAccepted  TriageResolution = "accepted"
+	TriageResolutionDiscarded TriageResolution = "discarded"
+)
+
+// DocumentTriage tracks the review of a document created in bulk, until a
+// quality manager checks its macro and owner or discards it
+type DocumentTriage struct {
+	Source     TriageSource        `json:"source" bson:"source"`
+	QueuedAt   time.Time           `json:"queuedAt" bson:"queued_at"`
+	DueAt      time.Time           `json:"dueAt" bson:"due_at"` // Triage SLA deadline
+	AssigneeID *primitive.ObjectID `json:"assigneeId,omitempty" bson:"assignee_id,omitempty"`
+	AssignedAt *time.Time          `json:"assignedAt,omitempty" bson:"assigned_at,omitempty"`
+	ResolvedAt *time.Time          `json:"resolvedAt,omitempty" bson:"resolved_at,omitempty"`
+	ResolvedBy *primitive.ObjectID `json:"resolvedBy,omitempty" bson:"resolved_by,omitempty"`
+	Resolution TriageResolution    `json:"resolution,omitempty" bson:"resolution,omitempty"`
+}
+
+// IsOpen tells whether the document still waits for its triage
+func (t *DocumentTriage) IsOpen() bool {
+	return t != nil && t.ResolvedAt == nil
+}
+
+// IsOverdue tells whether an open triage passed its SLA deadline
+func (t *DocumentTriage) IsOverdue(now time.Time) bool {
+	return t.IsOpen() && now.After(t.DueAt)
+}
+
+// NeedsTriage adds the condition matching the documents waiting for their
+// triage to a document filter
+func NeedsTriage(filter bson.M) bson.M {
+	filter["triage.queued_at"] = bson.M{"$exists": true}
+	filter["triage.resolved_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+// TriageItem is a document of the triage queue
+type TriageItem struct {
+	DocumentID  primitive.ObjectID  `json:"documentId"`
+	Reference   string              `json:"reference"`
+	Title       string              `json:"title"`
+	MacroID     *primitive.ObjectID `json:"macroId,omitempty"`
+	ProcessCode string              `json:"processCode,omitempty"`
+	OwnerID     primitive.ObjectID  `json:"ownerId"`
+	Triage      DocumentTriage      `json:"triage"`
+	Overdue     bool                `json:"overdue"`
+}
+
+// TriageStats measures the triage queue against its SLA
+type TriageStats struct {
+	Open                   int64   `json:"open"`
+	Unassigned             int64   `json:"unassigned"`
+	Overdue                int64   `json:"overdue"`
+	ResolvedLast30Days     int64   `json:"resolvedLast30Days"`
+	WithinSLARate          float64 `json:"withinSlaRate"`          // Share of the triages of the last 30 days resolved before their deadline
+	AverageResolutionHours float64 `json:"averageResolutionHours"` // Over the triages of the last 30 days
+	SLAHours               int     `json:"slaHours"`
+}
+
+// TriageUserRequest assigns a user to a document in triage
+type TriageUserRequest struct {
+	UserID string `json:"userId" validate:"required,len=24,hexadecimal"`
+}
+
+// TriageMacroRequest attaches a document in triage to a macro
+type TriageMacroRequest struct {
+	MacroID string `json:"macroId" validate:"required,len=24,hexadecimal"`
+}
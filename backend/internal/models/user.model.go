@@ -32,6 +32,26 @@ const (
 	StatusRejected UserStatus = "rejected" // Registration rejected
 )
 
+// OnboardingStep represents a milestone of the guided onboarding
+type OnboardingStep string
+
+const (
+	OnboardingProfileCompleted    OnboardingStep = "profile_completed"
+	OnboardingAvatarUploaded      OnboardingStep = "avatar_uploaded"
+	OnboardingFirstDocumentViewed OnboardingStep = "first_document_viewed"
+	OnboardingPinSet              OnboardingStep = "pin_set"
+	OnboardingPushEnabled         OnboardingStep = "push_enabled"
+)
+
+// OnboardingSteps lists the onboarding milestones in the order they are presented
+var OnboardingSteps = []OnboardingStep{
+	OnboardingProfileCompleted,
+	OnboardingAvatarUploaded,
+	OnboardingFirstDocumentViewed,
+	OnboardingPinSet,
+	OnboardingPushEnabled,
+}
+
 // User represents a user in the system
 type User struct {
 	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
@@ -44,6 +64,7 @@ type User struct {
 	Verified        bool                `bson:"verified" json:"verified"`
 	Avatar          string              `bson:"avatar,omitempty" json:"avatar,omitempty"`
 	Phone           string              `bson:"phone,omitempty" json:"phone,omitempty"`
+	Language        string              `bson:"language,omitempty" json:"language,omitempty"` // fr or en, language of the emails sent to the user
 	DepartmentID    *primitive.ObjectID `bson:"department_id,omitempty" json:"departmentId,omitempty"`
 	JobPositionID   *primitive.ObjectID `bson:"job_position_id,omitempty" json:"jobPositionId,omitempty"`
 	LastLogin       *time.Time          `bson:"last_login,omitempty" json:"lastLogin,omitempty"`
@@ -61,6 +82,15 @@ type User struct {
 	PinAttempts int        `bson:"pin_attempts" json:"-"`            // Failed PIN attempts
 	PinLockedAt *time.Time `bson:"pin_locked_at,omitempty" json:"-"` // When PIN was locked due to failed attempts
 
+	// Onboarding milestones, set once when first reached
+	Onboarding map[OnboardingStep]time.Time `bson:"onboarding,omitempty" json:"-"`
+
+	// Version of each policy the user accepted
+	AcceptedPolicies map[PolicyType]string `bson:"accepted_policies,omitempty" json:"-"`
+
+	// Hash of the secret token of the calendar feed of the signature deadlines
+	CalendarTokenHash string `bson:"calendar_token_hash,omitempty" json:"-"`
+
 	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
 }
@@ -98,6 +128,7 @@ type UpdateProfileRequest struct {
 	DepartmentID  string `json:"departmentId,omitempty"`
 	JobPositionID string `json:"jobPositionId,omitempty"`
 	Avatar        string `json:"avatar,omitempty"`
+	Language      string `json:"language,omitempty" validate:"omitempty,oneof=fr en"`
 }
 
 // ValidateUserRequest represents the request payload for admin user validation
@@ -128,6 +159,7 @@ type UserResponse struct {
 	Verified        bool                 `json:"verified"`
 	Avatar          string               `json:"avatar,omitempty"`
 	Phone           string               `json:"phone,omitempty"`
+	Language        string               `json:"language,omitempty"`
 	DepartmentID    *primitive.ObjectID  `json:"departmentId,omitempty"`
 	JobPositionID   *primitive.ObjectID  `json:"jobPositionId,omitempty"`
 	Department      *DepartmentResponse  `json:"department,omitempty"`
@@ -139,10 +171,27 @@ type UserResponse struct {
 	RejectedAt      *time.Time           `json:"rejectedAt,omitempty"`
 	RejectionReason string               `json:"rejectionReason,omitempty"`
 	HasPin          bool                 `json:"hasPin"`
+	Onboarding      *OnboardingResponse  `json:"onboarding,omitempty"`
+	PendingPolicies []*Policy            `json:"pendingPolicies,omitempty"` // Published policies the user still has to accept
 	CreatedAt       time.Time            `json:"createdAt"`
 	UpdatedAt       time.Time            `json:"updatedAt"`
 }
 
+// OnboardingStepState represents the state of one onboarding milestone
+type OnboardingStepState struct {
+	Step        OnboardingStep `json:"step"`
+	Completed   bool           `json:"completed"`
+	CompletedAt *time.Time     `json:"completedAt,omitempty"`
+}
+
+// OnboardingResponse represents the onboarding checklist of a user
+type OnboardingResponse struct {
+	Steps          []OnboardingStepState `json:"steps"`
+	CompletedSteps int                   `json:"completedSteps"`
+	TotalSteps     int                   `json:"totalSteps"`
+	Completed      bool                  `json:"completed"`
+}
+
 // ============================================
 // User Filter Options
 // ============================================
@@ -211,6 +260,47 @@ func (u *User) IsRejected() bool {
 	return u.Status == StatusRejected
 }
 
+// ProfileCompleted checks if the user has filled in all the profile fields
+func (u *User) ProfileCompleted() bool {
+	return u.FirstName != "" && u.LastName != "" && u.Phone != "" &&
+		u.DepartmentID != nil && u.JobPositionID != nil
+}
+
+// OnboardingState returns the onboarding checklist of the user. Milestones
+// reached before they were tracked are inferred from the account itself.
+func (u *User) OnboardingState() *OnboardingResponse {
+	state := &OnboardingResponse{
+		Steps:      make([]OnboardingStepState, 0, len(OnboardingSteps)),
+		TotalSteps: len(OnboardingSteps),
+	}
+
+	for _, step := range OnboardingSteps {
+		stepState := OnboardingStepState{Step: step}
+		if completedAt, ok := u.Onboarding[step]; ok {
+			stepState.Completed = true
+			stepState.CompletedAt = &completedAt
+		} else {
+			switch step {
+			case OnboardingProfileCompleted:
+				stepState.Completed = u.ProfileCompleted()
+			case OnboardingAvatarUploaded:
+				stepState.Completed = u.Avatar != ""
+			case OnboardingPinSet:
+				stepState.Completed = u.HasPin
+				stepState.CompletedAt = u.PinSetAt
+			}
+		}
+
+		if stepState.Completed {
+			state.CompletedSteps++
+		}
+		state.Steps = append(state.Steps, stepState)
+	}
+	state.Completed = state.CompletedSteps == state.TotalSteps
+
+	return state
+}
+
 // ToResponse converts User to UserResponse (excludes sensitive fields)
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
@@ -224,6 +314,7 @@ func (u *User) ToResponse() UserResponse {
 		Verified:        u.Verified,
 		Avatar:          u.Avatar,
 		Phone:           u.Phone,
+		Language:        u.Language,
 		DepartmentID:    u.DepartmentID,
 		JobPositionID:   u.JobPositionID,
 		LastLogin:       u.LastLogin,
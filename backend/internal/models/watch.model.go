@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DocumentWatch subscribes a user to the changes of a document
+type DocumentWatch struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"userId" bson:"user_id"`
+	DocumentID primitive.ObjectID `json:"documentId" bson:"document_id"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// WatchEvent is a change of a document notified to its watchers
+type WatchEvent string
+
+const (
+	WatchEventStatusChanged WatchEvent = "status_changed"
+	WatchEventNewVersion    WatchEvent = "new_version"
+	WatchEventNewAnnex      WatchEvent = "new_annex"
+)
+
+// WatchStatusResponse tells whether the current user watches a document
+type WatchStatusResponse struct {
+	DocumentID   string `json:"documentId"`
+	IsWatching   bool   `json:"isWatching"`
+	WatcherCount int64  `json:"watcherCount"`
+}
@@ -0,0 +1,245 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WorkflowSource tells where the workflow of a document is defined
+type WorkflowSource string
+
+const (
+	WorkflowSourceDocument WorkflowSource = "document" // Set on the document itself
+	WorkflowSourceMacro    WorkflowSource = "macro"    // Inherited from the macro of the document
+	WorkflowSourceDefault  WorkflowSource = "default"  // Built-in author → verifier → validator pipeline
+)
+
+// WorkflowStage is a signature stage of a workflow: the contributors of the
+// team sign the document in turn
+type WorkflowStage struct {
+	Team          ContributorTeam `json:"team" bson:"team" validate:"required,oneof=authors verifiers validators"`
+	MinSignatures int             `json:"minSignatures" bson:"min_signatures" validate:"min=0"` // 0 requires every contributor of the team
+	SkipWhenEmpty bool            `json:"skipWhenEmpty" bson:"skip_when_empty"`                 // Skip the stage when the team has no contributors
+}
+
+// WorkflowDefinition is the ordered list of signature stages a document goes
+// through before being approved. It is set on a macro for all its documents
+// or on a single document.
+type WorkflowDefinition struct {
+	Stages []WorkflowStage `json:"stages" bson:"stages" validate:"required,min=1,max=3,dive"`
+}
+
+// EffectiveWorkflow is the workflow driving a document and where it is defined
+type EffectiveWorkflow struct {
+	Source   WorkflowSource      `json:"source"`
+	Workflow *WorkflowDefinition `json:"workflow"`
+}
+
+// workflowTeamOrder is the order of the teams in a workflow. Each team has its
+// own review and signed statuses, so a team appears at most once and the
+// validators, whose signatures approve the document, come last.
+var workflowTeamOrder = map[ContributorTeam]int{
+	ContributorTeamAuthors:    0,
+	ContributorTeamVerifiers:  1,
+	ContributorTeamValidators: 2,
+}
+
+// DefaultWorkflow returns the built-in workflow: every author, then every
+// verifier, then every validator signs
+func DefaultWorkflow() *WorkflowDefinition {
+	return &WorkflowDefinition{
+		Stages: []WorkflowStage{
+			{Team: ContributorTeamAuthors},
+			{Team: ContributorTeamVerifiers},
+			{Team: ContributorTeamValidators},
+		},
+	}
+}
+
+// Validate checks that the stages can be mapped to document statuses
+func (w *WorkflowDefinition) Validate() error {
+	if len(w.Stages) == 0 {
+		return errors.New("workflow must have at least one stage")
+	}
+	last := -1
+	for _, stage := range w.Stages {
+		order, ok := workflowTeamOrder[stage.Team]
+		if !ok {
+			return fmt.Errorf("workflow stage has an invalid team: %s", stage.Team)
+		}
+		if order <= last {
+			return errors.New("workflow stages must follow the authors, verifiers, validators order, each team once")
+		}
+		if stage.MinSignatures < 0 {
+			return errors.New("workflow stage minimum signatures cannot be negative")
+		}
+		last = order
+	}
+	return nil
+}
+
+// ReviewStatus returns the status of a document signed by the team of the stage
+func (s WorkflowStage) ReviewStatus() DocumentStatus {
+	switch s.Team {
+	case ContributorTeamVerifiers:
+		return DocumentStatusVerifierReview
+	case ContributorTeamValidators:
+		return DocumentStatusValidatorReview
+	}
+	return DocumentStatusAuthorReview
+}
+
+// SignedStatus returns the status of a document fully signed by the team of
+// the stage while the next stage has no contributors yet
+func (s WorkflowStage) SignedStatus() DocumentStatus {
+	switch s.Team {
+	case ContributorTeamVerifiers:
+		return DocumentStatusVerifierSigned
+	case ContributorTeamValidators:
+		return DocumentStatusApproved
+	}
+	return DocumentStatusAuthorSigned
+}
+
+// RequiredSignatures returns the number of signatures completing the stage
+// for the given number of contributors of its team
+func (s WorkflowStage) RequiredSignatures(contributors int) int {
+	if s.MinSignatures > 0 && s.MinSignatures < contributors {
+		return s.MinSignatures
+	}
+	return contributors
+}
+
+// skipped reports whether the stage is skipped for the document
+func (s WorkflowStage) skipped(document *Document) bool {
+	return s.SkipWhenEmpty && len(document.Contributors.Team(s.Team)) == 0
+}
+
+// StageIndex returns the index of the stage the status belongs to, or -1
+func (w *WorkflowDefinition) StageIndex(status DocumentStatus) int {
+	for i, stage := range w.Stages {
+		if stage.ReviewStatus() == status || stage.SignedStatus() == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// NextStage returns the index of the first stage after the given one which
+// is not skipped for the document, or -1 when there is none
+func (w *WorkflowDefinition) NextStage(document *Document, after int) int {
+	for i := after + 1; i < len(w.Stages); i++ {
+		if !w.Stages[i].skipped(document) {
+			return i
+		}
+	}
+	return -1
+}
+
+// PublishTransition returns the stage a document is published to from its
+// current status: the first stage from a draft, the next one once a stage is
+// signed. Approved documents are published to the organization, with a nil
+// stage.
+func (w *WorkflowDefinition) PublishTransition(document *Document) (DocumentStatus, *WorkflowStage, error) {
+	if document.Status == DocumentStatusApproved {
+		return DocumentStatusArchived, nil, nil
+	}
+
+	from := -1
+	if document.Status != DocumentStatusDraft {
+		from = w.StageIndex(document.Status)
+		if from < 0 || w.Stages[from].SignedStatus() != document.Status {
+			return "", nil, fmt.Errorf("document cannot be published from status: %s", document.Status)
+		}
+	}
+
+	next := w.NextStage(document, from)
+	if next < 0 {
+		if from < 0 {
+			return "", nil, errors.New("document cannot be published: no workflow stage has contributors")
+		}
+		return DocumentStatusApproved, nil, nil
+	}
+	return w.Stages[next].ReviewStatus(), &w.Stages[next], nil
+}
+
+// SignTransition returns the status a document in review moves to once its
+// stage has the given number of signatures, and the stage it enters when it
+// is published to the next team. ok is false when the stage is not complete.
+func (w *WorkflowDefinition) SignTransition(document *Document, signatures int) (status DocumentStatus, stage *WorkflowStage, ok bool) {
+	current := w.StageIndex(document.Status)
+	if current < 0 || w.Stages[current].ReviewStatus() != document.Status {
+		return "", nil, false
+	}
+
+	contributors := len(document.Contributors.Team(w.Stages[current].Team))
+	if contributors == 0 || signatures < w.Stages[current].RequiredSignatures(contributors) {
+		return "", nil, false
+	}
+
+	next := w.NextStage(document, current)
+	if next < 0 {
+		return DocumentStatusApproved, nil, true
+	}
+	// The document waits in the signed status until the next team is staffed
+	if len(document.Contributors.Team(w.Stages[next].Team)) == 0 {
+		return w.Stages[current].SignedStatus(), nil, true
+	}
+	return w.Stages[next].ReviewStatus(), &w.Stages[next], true
+}
+
+// FirstStage returns the first stage of the workflow not skipped for the
+// document, or nil
+func (w *WorkflowDefinition) FirstStage(document *Document) *WorkflowStage {
+	if first := w.NextStage(document, -1); first >= 0 {
+		return &w.Stages[first]
+	}
+	return nil
+}
+
+// WorkflowSimulationRequest is a macro configuration evaluated against the
+// documents in signature before it is applied
+type WorkflowSimulationRequest struct {
+	Workflow  *WorkflowDefinition `json:"workflow,omitempty"`          // Proposed workflow of the macro, nil simulates restoring the default one
+	Deadlines *ApprovalDeadlines  `json:"approvalDeadlines,omitempty"` // Proposed signature deadlines, the ones of each document when nil
+}
+
+// WorkflowImpact is a change the simulated configuration brings to a document
+type WorkflowImpact string
+
+const (
+	WorkflowImpactStuck           WorkflowImpact = "stuck"             // The document can no longer be signed or published
+	WorkflowImpactSignersChanged  WorkflowImpact = "signers_changed"   // The current stage requires another number of signatures
+	WorkflowImpactNextStepChanged WorkflowImpact = "next_step_changed" // The document moves to another status once the current stage completes
+	WorkflowImpactDeadlineChanged WorkflowImpact = "deadline_changed"  // The current stage is due at another date
+)
+
+// WorkflowSimulationDocument reports the impacts of the simulated
+// configuration on a document in signature
+type WorkflowSimulationDocument struct {
+	DocumentID            string           `json:"documentId"`
+	Reference             string           `json:"reference"`
+	Title                 string           `json:"title"`
+	Status                DocumentStatus   `json:"status"`
+	Impacts               []WorkflowImpact `json:"impacts"`
+	StuckReason           string           `json:"stuckReason,omitempty"`
+	SignaturesCollected   int              `json:"signaturesCollected"`
+	RequiredSignatures    int              `json:"requiredSignatures"`    // Signatures completing the current stage today
+	NewRequiredSignatures int              `json:"newRequiredSignatures"` // Signatures completing the current stage with the simulated workflow
+	NextStatus            DocumentStatus   `json:"nextStatus,omitempty"`
+	NewNextStatus         DocumentStatus   `json:"newNextStatus,omitempty"`
+	DueAt                 *time.Time       `json:"dueAt,omitempty"`
+	NewDueAt              *time.Time       `json:"newDueAt,omitempty"`
+	Overdue               bool             `json:"overdue"` // Already past the simulated deadline, escalated on the next check
+}
+
+// WorkflowSimulationResult is the outcome of a workflow dry run on a macro.
+// Only the affected documents are listed.
+type WorkflowSimulationResult struct {
+	MacroID   string                       `json:"macroId"`
+	Evaluated int                          `json:"evaluated"` // Documents in signature following the macro workflow
+	Stuck     int                          `json:"stuck"`
+	Changed   int                          `json:"changed"` // Affected documents which are not stuck
+	Documents []WorkflowSimulationDocument `json:"documents"`
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupAccountDeletionRoutes configures self-service account deletion routes
+func SetupAccountDeletionRoutes(router *gin.RouterGroup, accountDeletionHandler *handlers.AccountDeletionHandler, authMiddleware *middleware.AuthMiddleware) {
+	deletion := router.Group("/auth/delete-account")
+	deletion.Use(authMiddleware.RequireAuth())
+	{
+		deletion.POST("", accountDeletionHandler.RequestDeletion)         // Send the confirmation OTP
+		deletion.POST("/confirm", accountDeletionHandler.ConfirmDeletion) // Verify the OTP and schedule the deletion
+		deletion.GET("", accountDeletionHandler.GetDeletion)              // Current pending or scheduled deletion
+		deletion.DELETE("", accountDeletionHandler.CancelDeletion)        // Cancel during the grace period
+	}
+
+	// Admin routes
+	admin := router.Group("/admin/account-deletions")
+	admin.Use(authMiddleware.RequireAdmin())
+	{
+		admin.GET("", accountDeletionHandler.ListDeletions)
+		admin.POST("/:id/cancel", accountDeletionHandler.CancelDeletionAdmin)
+	}
+}
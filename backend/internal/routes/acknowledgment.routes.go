@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupAcknowledgmentRoutes configures the read acknowledgment campaign routes
+func SetupAcknowledgmentRoutes(router *gin.RouterGroup, acknowledgmentHandler *handlers.AcknowledgmentHandler, authMiddleware *middleware.AuthMiddleware, documentMiddleware *middleware.DocumentMiddleware) {
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		documents.GET("/:id/acknowledgment-campaigns", authMiddleware.RequireManager(), documentMiddleware.RequireDocumentAccess(), acknowledgmentHandler.ListCampaigns)
+		documents.POST("/:id/acknowledgment-campaigns", authMiddleware.RequireAdmin(), acknowledgmentHandler.CreateCampaign)
+	}
+
+	campaigns := router.Group("/acknowledgment-campaigns")
+	campaigns.Use(authMiddleware.RequireAuth())
+	{
+		campaigns.GET("/:campaignId", authMiddleware.RequireManager(), acknowledgmentHandler.GetCampaign)
+		campaigns.GET("/:campaignId/recipients", authMiddleware.RequireManager(), acknowledgmentHandler.GetRecipients)
+		campaigns.POST("/:campaignId/close", authMiddleware.RequireAdmin(), acknowledgmentHandler.CloseCampaign)
+		campaigns.POST("/:campaignId/acknowledge", acknowledgmentHandler.Acknowledge) // Any recipient
+	}
+
+	router.GET("/acknowledgments/pending", authMiddleware.RequireAuth(), acknowledgmentHandler.ListPending)
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupActorRoutes configures implicated actors registry routes
+func SetupActorRoutes(router *gin.RouterGroup, actorHandler *handlers.ActorHandler, authMiddleware *middleware.AuthMiddleware) {
+	actors := router.Group("/actors")
+	{
+		// Authenticated users can browse the registry while editing metadata
+		actors.Use(authMiddleware.RequireAuth())
+		actors.GET("", actorHandler.GetActors)                 // List actors
+		actors.GET("/autocomplete", actorHandler.Autocomplete) // Actor suggestions
+		actors.GET("/:id", actorHandler.GetActor)              // Get specific actor
+
+		// Manager-level operations - require manager or admin role
+		managerOps := actors.Group("").Use(authMiddleware.RequireManager())
+		{
+			managerOps.POST("", actorHandler.CreateActor)    // Register actor
+			managerOps.PUT("/:id", actorHandler.UpdateActor) // Update actor
+		}
+
+		// Admin-only operations
+		adminOps := actors.Group("").Use(authMiddleware.RequireAdmin())
+		{
+			adminOps.DELETE("/:id", actorHandler.DeleteActor)            // Delete actor
+			adminOps.POST("/normalize", actorHandler.NormalizeDocuments) // Normalize existing documents
+		}
+	}
+}
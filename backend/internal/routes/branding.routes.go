@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupBrandingRoutes configures the organization branding routes
+func SetupBrandingRoutes(router *gin.RouterGroup, brandingHandler *handlers.BrandingHandler, authMiddleware *middleware.AuthMiddleware) {
+	branding := router.Group("/branding")
+	{
+		// Public so the login page can be branded
+		branding.GET("", brandingHandler.GetBranding)
+
+		// Admin-only operations
+		adminOps := branding.Group("").Use(authMiddleware.RequireAdmin())
+		{
+			adminOps.PUT("", brandingHandler.UpdateBranding)     // Update identity, colors and footer
+			adminOps.POST("/logo", brandingHandler.UploadLogo)   // Replace the logo
+			adminOps.DELETE("/logo", brandingHandler.DeleteLogo) // Remove the logo
+		}
+	}
+}
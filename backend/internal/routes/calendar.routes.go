@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupCalendarRoutes configures the calendar feed routes of the current user
+func SetupCalendarRoutes(router *gin.RouterGroup, calendarHandler *handlers.CalendarHandler, authMiddleware *middleware.AuthMiddleware) {
+	me := router.Group("/users/me")
+	{
+		// Signed-in users or calendar apps with the feed token
+		me.GET("/calendar.ics", authMiddleware.OptionalAuth(), calendarHandler.GetMyCalendar)
+		me.POST("/calendar-token", authMiddleware.RequireAuth(), calendarHandler.ResetCalendarToken)
+	}
+}
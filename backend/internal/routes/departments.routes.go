@@ -13,12 +13,15 @@ func SetupDepartmentRoutes(router *gin.RouterGroup, departmentHandler *handlers.
 		// Public read access (for forms, dropdowns, etc.)
 		departments.GET("/", departmentHandler.GetDepartments)             // List all departments
 		departments.GET("/:id", departmentHandler.GetDepartment)           // Get specific department
+		departments.GET("/:id/default-contributors", authMiddleware.RequireAuth(), departmentHandler.GetDefaultContributors) // Contributors added to new documents
 
 		// Manager-level operations - require manager or admin role
 		managerOps := departments.Group("").Use(authMiddleware.RequireManager())
 		{
 			managerOps.POST("/", departmentHandler.CreateDepartment)       // Create new department
 			managerOps.PUT("/:id", departmentHandler.UpdateDepartment)     // Update department
+			managerOps.PUT("/:id/contributor-template", departmentHandler.SetContributorTemplate)      // Set default contributors
+			managerOps.DELETE("/:id/contributor-template", departmentHandler.ClearContributorTemplate) // Remove default contributors
 		}
 
 		// Admin-only operations - high-risk operations
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupDisplayRoutes configures QR login and read-only wallboard routes
+func SetupDisplayRoutes(router *gin.RouterGroup, displayHandler *handlers.DisplayHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Device-code login flow
+	displayAuth := router.Group("/auth/display")
+	{
+		displayAuth.POST("/code", displayHandler.RequestCode)                                  // Display requests a code to render as QR
+		displayAuth.POST("/token", displayHandler.PollToken)                                   // Display polls until the code is approved
+		displayAuth.POST("/approve", authMiddleware.RequireAuth(), displayHandler.ApproveCode) // Logged-in user approves the scanned code
+	}
+
+	// Read-only dashboards (display or regular access tokens)
+	display := router.Group("/display")
+	display.Use(authMiddleware.RequireDisplay())
+	{
+		display.GET("/dashboard", displayHandler.GetDashboard) // Procedure status wallboard
+	}
+}
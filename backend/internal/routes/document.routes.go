@@ -12,6 +12,8 @@ func SetupDocumentRoutes(
 	documentHandler *handlers.DocumentHandler,
 	permissionHandler *handlers.PermissionHandler,
 	signatureHandler *handlers.SignatureHandler,
+	commentHandler *handlers.CommentHandler,
+	analyticsHandler *handlers.AnalyticsHandler,
 	authMiddleware *middleware.AuthMiddleware,
 	documentMiddleware *middleware.DocumentMiddleware,
 ) {
@@ -29,17 +31,42 @@ func SetupDocumentRoutes(
 		// List and create documents (no document-specific permission check needed)
 		documents.GET("", documentHandler.ListDocuments)
 		documents.POST("", documentHandler.CreateDocument)
+		documents.GET("/trash", documentHandler.ListTrash)
+		documents.GET("/library", documentHandler.ListLibrary)
+		documents.GET("/stats", authMiddleware.RequireManager(), documentHandler.GetDocumentStats)
+		documents.POST("/import", authMiddleware.RequireManager(), documentHandler.ImportDocuments)
+		documents.POST("/import-structure", authMiddleware.RequireManager(), documentHandler.ImportStructure)
+		documents.POST("/export", authMiddleware.RequireManager(), documentHandler.ExportDocuments)
 
 		// Document operations (require document access)
 		documents.GET("/:id", documentMiddleware.RequireDocumentAccess(), documentHandler.GetDocument)
 		documents.PUT("/:id", documentMiddleware.RequireDocumentAccess(), documentHandler.UpdateDocument)
+		documents.GET("/:id/draft", documentMiddleware.RequireDocumentAccess(), documentHandler.GetDraft)
+		documents.PUT("/:id/draft", documentMiddleware.RequireDocumentAccess(), documentHandler.SaveDraft)
+		documents.DELETE("/:id/draft", documentMiddleware.RequireDocumentAccess(), documentHandler.DiscardDraft)
+		documents.POST("/:id/draft/commit", documentMiddleware.RequireDocumentAccess(), documentHandler.CommitDraft)
 		documents.DELETE("/:id", authMiddleware.RequireManager(), documentMiddleware.RequireDocumentAccess(), documentHandler.DeleteDocument)
+		documents.POST("/:id/restore", authMiddleware.RequireManager(), documentMiddleware.RequireDocumentAccess(), documentHandler.RestoreDocument)
 
 		// Document actions (require document access)
 		documents.POST("/:id/duplicate", documentMiddleware.RequireDocumentAccess(), documentHandler.DuplicateDocument)
+		documents.PUT("/:id/macro", documentMiddleware.RequireDocumentAccess(), documentHandler.AttachToMacro)
+		documents.POST("/:id/revise", authMiddleware.RequireManager(), documentMiddleware.RequireDocumentAccess(), documentHandler.ReviseDocument)
 		documents.POST("/:id/publish", documentMiddleware.RequireDocumentAccess(), documentHandler.PublishDocument)
+		documents.POST("/:id/reject", documentMiddleware.RequireDocumentAccess(), documentHandler.RejectDocument)
+		documents.GET("/:id/workflow", documentMiddleware.RequireDocumentAccess(), documentHandler.GetWorkflow)
+		documents.PUT("/:id/workflow", authMiddleware.RequireManager(), documentMiddleware.RequireDocumentAccess(), documentHandler.SetWorkflow)
+		documents.DELETE("/:id/workflow", authMiddleware.RequireManager(), documentMiddleware.RequireDocumentAccess(), documentHandler.ClearWorkflow)
+		documents.PUT("/:id/scheduled-publish", documentMiddleware.RequireDocumentAccess(), documentHandler.SchedulePublish)
+		documents.DELETE("/:id/scheduled-publish", documentMiddleware.RequireDocumentAccess(), documentHandler.CancelScheduledPublish)
+		documents.PUT("/:id/effective-dates", authMiddleware.RequireManager(), documentMiddleware.RequireDocumentAccess(), documentHandler.UpdateEffectiveDates)
 		documents.GET("/:id/export-pdf", documentMiddleware.RequireDocumentAccess(), documentHandler.ExportPDF)
+		documents.GET("/:id/export-pdf/stream", documentMiddleware.RequireDocumentAccess(), documentHandler.ExportPDFStream)
+		documents.GET("/:id/export", documentMiddleware.RequireDocumentAccess(), documentHandler.ExportStructure)
 		documents.GET("/:id/versions", documentMiddleware.RequireDocumentAccess(), documentHandler.GetDocumentVersions)
+		documents.GET("/:id/versions/:versionId/pdf", documentMiddleware.RequireDocumentAccess(), documentHandler.GetVersionPDF)
+		documents.GET("/:id/pdf-history", documentMiddleware.RequireDocumentAccess(), documentHandler.GetPDFHistory)
+		documents.GET("/:id/lint", documentMiddleware.RequireDocumentAccess(), documentHandler.LintDocument)
 
 		// Permissions (require document access)
 		documents.GET("/:id/permissions", documentMiddleware.RequireDocumentAccess(), permissionHandler.GetDocumentPermissions)
@@ -51,13 +78,40 @@ func SetupDocumentRoutes(
 		documents.GET("/:id/signatures", documentMiddleware.RequireDocumentAccess(), signatureHandler.GetDocumentSignatures)
 		documents.POST("/:id/signatures", documentMiddleware.RequireDocumentAccess(), signatureHandler.AddDocumentSignature)
 
+		// Comments (require document access)
+		documents.GET("/:id/summary", documentMiddleware.RequireDocumentAccess(), commentHandler.GetDocumentSummary)
+		documents.GET("/:id/comments", documentMiddleware.RequireDocumentAccess(), commentHandler.ListComments)
+		documents.GET("/:id/mentionable", documentMiddleware.RequireDocumentAccess(), commentHandler.GetMentionableUsers)
+		documents.POST("/:id/comments", documentMiddleware.RequireDocumentAccess(), commentHandler.CreateComment)
+		documents.GET("/:id/comments/export", documentMiddleware.RequireDocumentAccess(), commentHandler.ExportComments)
+		documents.PUT("/:id/comments/:commentId", documentMiddleware.RequireDocumentAccess(), commentHandler.UpdateComment)
+		documents.DELETE("/:id/comments/:commentId", documentMiddleware.RequireDocumentAccess(), commentHandler.DeleteComment)
+		documents.POST("/:id/comments/:commentId/resolve", documentMiddleware.RequireDocumentAccess(), commentHandler.ResolveComment)
+		documents.POST("/:id/comments/:commentId/unresolve", documentMiddleware.RequireDocumentAccess(), commentHandler.UnresolveComment)
+		documents.POST("/:id/comments/:commentId/reactions", documentMiddleware.RequireDocumentAccess(), commentHandler.AddCommentReaction)
+		documents.DELETE("/:id/comments/:commentId/reactions/:type", documentMiddleware.RequireDocumentAccess(), commentHandler.RemoveCommentReaction)
+
+		// Consultation analytics (require document access)
+		documents.POST("/:id/views", documentMiddleware.RequireDocumentAccess(), analyticsHandler.RecordDocumentView)
+		documents.GET("/:id/analytics", documentMiddleware.RequireDocumentAccess(), analyticsHandler.GetDocumentAnalytics)
+
 		// Metadata (require document access)
 		documents.PATCH("/:id/metadata", documentMiddleware.RequireDocumentAccess(), documentHandler.UpdateMetadata)
 
+		// Process steps (require document access)
+		documents.PATCH("/:id/process-groups/:groupId/steps/reorder", documentMiddleware.RequireDocumentAccess(), documentHandler.ReorderSteps)
+		documents.POST("/:id/process-groups/:groupId/steps/bulk", documentMiddleware.RequireDocumentAccess(), documentHandler.BulkEditSteps)
+
 		// Annexes (require document access)
 		documents.POST("/:id/annexes", documentMiddleware.RequireDocumentAccess(), documentHandler.CreateAnnex)
+		documents.PATCH("/:id/annexes/reorder", documentMiddleware.RequireDocumentAccess(), documentHandler.ReorderAnnexes)
 		documents.PATCH("/:id/annexes/:annexId", documentMiddleware.RequireDocumentAccess(), documentHandler.UpdateAnnex)
 		documents.DELETE("/:id/annexes/:annexId", documentMiddleware.RequireDocumentAccess(), documentHandler.DeleteAnnex)
+		documents.GET("/:id/annexes/:annexId/export", documentMiddleware.RequireDocumentAccess(), documentHandler.ExportAnnex)
+
+		// Section locks (owner or admin)
+		documents.PUT("/:id/section-locks/:section", documentMiddleware.RequireDocumentAccess(), documentHandler.SetSectionLock)
+		documents.DELETE("/:id/section-locks/:section", documentMiddleware.RequireDocumentAccess(), documentHandler.RemoveSectionLock)
 
 		// Annex Files (require document access)
 		documents.POST("/:id/annexes/:annexId/files", documentMiddleware.RequireDocumentAccess(), documentHandler.UploadAnnexFiles)
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupDocumentHistoryRoutes configures the document audit trail routes
+func SetupDocumentHistoryRoutes(router *gin.RouterGroup, historyHandler *handlers.DocumentHistoryHandler, authMiddleware *middleware.AuthMiddleware, documentMiddleware *middleware.DocumentMiddleware) {
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		documents.GET("/:id/history", documentMiddleware.RequireDocumentAccess(), historyHandler.GetDocumentHistory) // Full lifecycle timeline
+	}
+}
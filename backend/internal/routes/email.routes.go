@@ -28,6 +28,25 @@ func SetupEmailRoutes(router *gin.RouterGroup, emailHandler *handlers.EmailHandl
 
 			// Broadcast email to all users (with optional filters)
 			admin.POST("/broadcast", emailHandler.SendBroadcastEmail)
+
+			// Campaigns to user segments, delivered by the throttled queue
+			admin.POST("/campaigns/preview", emailHandler.PreviewCampaign)
+			admin.POST("/campaigns", emailHandler.CreateCampaign)
+			admin.GET("/campaigns", emailHandler.ListCampaigns)
+			admin.GET("/campaigns/:id", emailHandler.GetCampaign)
+			admin.GET("/campaigns/:id/recipients", emailHandler.GetCampaignRecipients)
+			admin.POST("/campaigns/:id/cancel", emailHandler.CancelCampaign)
 		}
 	}
+
+	// Outbox of the emails sent by the platform, with their delivery status
+	outbox := router.Group("/admin/emails")
+	outbox.Use(authMiddleware.RequireAdmin())
+	{
+		outbox.GET("", emailHandler.ListOutbox)
+		outbox.POST("/test", emailHandler.TestEmailProvider)                    // Send through a provider and report each step
+		outbox.POST("/broadcast", emailHandler.CreateCampaign)                  // Campaign to an audience, queued through the outbox
+		outbox.GET("/undeliverable", emailHandler.ListUndeliverable)            // Addresses that bounced or reported spam
+		outbox.DELETE("/undeliverable/:email", emailHandler.ClearUndeliverable) // Lift the flag once fixed
+	}
 }
\ No newline at end of file
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupEmailSettingsRoutes configures the routes turning the emails of system events on or off
+func SetupEmailSettingsRoutes(router *gin.RouterGroup, emailSettingsHandler *handlers.EmailSettingsHandler, authMiddleware *middleware.AuthMiddleware) {
+	settings := router.Group("/admin/email-settings")
+	{
+		// Admin-only operations
+		settings.Use(authMiddleware.RequireAdmin())
+		settings.GET("", emailSettingsHandler.GetEmailSettings)    // Events sending emails
+		settings.PUT("", emailSettingsHandler.UpdateEmailSettings) // Turn events on or off
+	}
+}
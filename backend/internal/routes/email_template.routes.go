@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupEmailTemplateRoutes configures the email template editing routes
+func SetupEmailTemplateRoutes(router *gin.RouterGroup, emailTemplateHandler *handlers.EmailTemplateHandler, authMiddleware *middleware.AuthMiddleware) {
+	templates := router.Group("/admin/email-templates")
+	{
+		// Admin-only operations
+		templates.Use(authMiddleware.RequireAdmin())
+		templates.GET("", emailTemplateHandler.GetEmailTemplates)                                            // List templates by kind
+		templates.GET("/:kind", emailTemplateHandler.GetEmailTemplate)                                       // Get template in use
+		templates.PUT("/:kind", emailTemplateHandler.SaveEmailTemplate)                                      // Save a new version
+		templates.DELETE("/:kind", emailTemplateHandler.ResetEmailTemplate)                                  // Back to the built-in template
+		templates.POST("/:kind/preview", emailTemplateHandler.PreviewEmailTemplate)                          // Render with sample data
+		templates.GET("/:kind/versions", emailTemplateHandler.GetEmailTemplateVersions)                      // Version history
+		templates.POST("/:kind/versions/:version/restore", emailTemplateHandler.RestoreEmailTemplateVersion) // Restore a former version
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupExportRoutes configures the streamed list export routes
+func SetupExportRoutes(router *gin.RouterGroup, exportHandler *handlers.ExportHandler, authMiddleware *middleware.AuthMiddleware) {
+	router.GET("/users/export", authMiddleware.RequireAdmin(), exportHandler.ExportUsers)
+	router.GET("/documents/export", authMiddleware.RequireManager(), exportHandler.ExportDocuments)
+	router.GET("/activity-logs/export", authMiddleware.RequireAdmin(), exportHandler.ExportActivityLogs)
+
+	// Background exports started with async=true
+	exports := router.Group("/exports")
+	exports.Use(authMiddleware.RequireAuth())
+	{
+		exports.GET("", exportHandler.ListExports)
+		exports.GET("/:id", exportHandler.GetExport)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupFavoriteRoutes configures the starred and recently viewed document routes
+func SetupFavoriteRoutes(router *gin.RouterGroup, favoriteHandler *handlers.FavoriteHandler, authMiddleware *middleware.AuthMiddleware, documentMiddleware *middleware.DocumentMiddleware) {
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		documents.GET("/favorites", favoriteHandler.ListFavorites)
+		documents.GET("/recent", favoriteHandler.ListRecent)
+
+		documents.GET("/:id/favorite", documentMiddleware.RequireDocumentAccess(), favoriteHandler.GetFavoriteStatus)
+		documents.POST("/:id/favorite", documentMiddleware.RequireDocumentAccess(), favoriteHandler.AddFavorite)
+		documents.DELETE("/:id/favorite", favoriteHandler.RemoveFavorite) // Allowed after access was lost
+	}
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupHelpArticleRoutes configures contextual help routes
+func SetupHelpArticleRoutes(router *gin.RouterGroup, helpArticleHandler *handlers.HelpArticleHandler, authMiddleware *middleware.AuthMiddleware) {
+	help := router.Group("/help-articles")
+	help.Use(authMiddleware.RequireAuth())
+	{
+		// Tooltips and the help panel
+		help.GET("", helpArticleHandler.GetArticles)
+		help.GET("/lookup", helpArticleHandler.LookupArticles)
+		help.GET("/key/:key", helpArticleHandler.GetArticleByKey)
+
+		// Admin-only operations
+		adminOps := help.Group("").Use(authMiddleware.RequireAdmin())
+		{
+			adminOps.GET("/:id", helpArticleHandler.GetArticle)
+			adminOps.POST("", helpArticleHandler.CreateArticle)
+			adminOps.PUT("/:id", helpArticleHandler.UpdateArticle)
+			adminOps.DELETE("/:id", helpArticleHandler.DeleteArticle)
+		}
+	}
+}
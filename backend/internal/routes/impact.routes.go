@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupImpactRoutes configures impact analysis routes
+func SetupImpactRoutes(router *gin.RouterGroup, impactHandler *handlers.ImpactHandler, authMiddleware *middleware.AuthMiddleware) {
+	impact := router.Group("/impact")
+	{
+		// Manager-level: used when planning reorganizations
+		impact.GET("", authMiddleware.RequireManager(), impactHandler.GetImpact)
+	}
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupJobRoutes configures the background job routes
+func SetupJobRoutes(router *gin.RouterGroup, jobHandler *handlers.JobHandler, authMiddleware *middleware.AuthMiddleware, documentMiddleware *middleware.DocumentMiddleware) {
+	jobs := router.Group("/jobs")
+	jobs.Use(authMiddleware.RequireAuth())
+	{
+		jobs.GET("/:id", jobHandler.GetJob) // Poll the progress of a job
+	}
+
+	admin := router.Group("/admin/jobs")
+	admin.Use(authMiddleware.RequireAdmin())
+	{
+		admin.GET("", jobHandler.GetQueueStats) // Queue depth and running jobs
+	}
+
+	adminDocuments := router.Group("/admin/documents")
+	adminDocuments.Use(authMiddleware.RequireAdmin())
+	{
+		adminDocuments.POST("/regenerate-pdfs", jobHandler.RegeneratePDFs)          // Regenerate the stored PDFs in bulk
+		adminDocuments.GET("/regenerate-pdfs/:id", jobHandler.GetRegenerationBatch) // Outcome of each document
+	}
+
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		documents.POST("/:id/export-pdf", documentMiddleware.RequireDocumentAccess(), jobHandler.QueueDocumentPDF)
+	}
+}
@@ -20,9 +20,12 @@ func SetupMacroRoutes(router *gin.RouterGroup, macroHandler *handlers.MacroHandl
 		// Manager-level operations - require manager or admin role
 		managerOps := macros.Group("").Use(authMiddleware.RequireManager())
 		{
-			managerOps.POST("/", macroHandler.CreateMacro)                          // Create new macro
-			managerOps.PUT("/:id", macroHandler.UpdateMacro)                        // Update macro
-			managerOps.PUT("/:id/reorder-processes", macroHandler.ReorderProcesses) // Reorder processes
+			managerOps.POST("/", macroHandler.CreateMacro)                           // Create new macro
+			managerOps.PUT("/:id", macroHandler.UpdateMacro)                         // Update macro
+			managerOps.PUT("/:id/reorder-processes", macroHandler.ReorderProcesses)  // Reorder processes
+			managerOps.PUT("/:id/workflow", macroHandler.SetWorkflow)                // Set signature workflow of the documents
+			managerOps.DELETE("/:id/workflow", macroHandler.ClearWorkflow)           // Restore the default workflow
+			managerOps.POST("/:id/workflow/simulate", macroHandler.SimulateWorkflow) // Dry run a workflow on the documents in signature
 		}
 
 		// Admin-only operations - high-risk operations
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupMetadataSectionRoutes configures custom metadata section routes
+func SetupMetadataSectionRoutes(router *gin.RouterGroup, sectionHandler *handlers.MetadataSectionHandler, authMiddleware *middleware.AuthMiddleware) {
+	sections := router.Group("/metadata-sections")
+	{
+		// Authenticated users need the definitions to edit document metadata
+		sections.GET("", authMiddleware.RequireAuth(), sectionHandler.GetSections)
+
+		// Admin-only operations
+		adminOps := sections.Group("").Use(authMiddleware.RequireAdmin())
+		{
+			adminOps.POST("", sectionHandler.CreateSection)       // Define a custom section
+			adminOps.PUT("/:id", sectionHandler.UpdateSection)    // Update a custom section
+			adminOps.DELETE("/:id", sectionHandler.DeleteSection) // Delete a custom section
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// moduleRoutePaths maps the modules under rollout to the prefix of their routes
+var moduleRoutePaths = map[models.ModuleKey]string{
+	models.ModuleFeedback:      "/feedback",
+	models.ModuleIncidents:     "/incidents",
+	models.ModuleChecklistRuns: "/checklist-runs",
+}
+
+// ModuleGroup returns the route group of a module under rollout. Its routes
+// are only served to the authenticated users whose department has the
+// module enabled.
+func ModuleGroup(router *gin.RouterGroup, module models.ModuleKey, authMiddleware *middleware.AuthMiddleware, moduleMiddleware *middleware.ModuleMiddleware) *gin.RouterGroup {
+	return router.Group(moduleRoutePaths[module], authMiddleware.RequireAuth(), moduleMiddleware.RequireModule(module))
+}
+
+// SetupModuleRoutes configures module rollout routes
+func SetupModuleRoutes(router *gin.RouterGroup, moduleHandler *handlers.ModuleHandler, authMiddleware *middleware.AuthMiddleware, moduleMiddleware *middleware.ModuleMiddleware) {
+	modules := router.Group("/modules")
+	{
+		// Any authenticated user can see which modules are enabled for them
+		modules.GET("/me", authMiddleware.RequireAuth(), moduleHandler.GetMyModules)
+
+		// Admin-only rollout configuration
+		adminOps := modules.Group("").Use(authMiddleware.RequireAdmin())
+		{
+			adminOps.GET("", moduleHandler.GetRollouts)           // List module rollouts
+			adminOps.PUT("/:module", moduleHandler.UpdateRollout) // Enable module per department or company-wide
+		}
+	}
+
+	// Gated module routes, the subsystems register their routes on the same groups
+	for _, module := range models.KnownModules {
+		ModuleGroup(router, module, authMiddleware, moduleMiddleware).GET("/access", moduleHandler.GetModuleAccess)
+	}
+}
@@ -18,6 +18,10 @@ func SetupNotificationRoutes(router *gin.RouterGroup, notificationHandler *handl
 		notifications.POST("/mark-read", notificationHandler.MarkNotificationsAsRead) // Mark notifications as read
 		notifications.GET("/stats", notificationHandler.GetNotificationStats)        // Get notification statistics
 
+		// Acknowledgment reactions on announcements
+		notifications.POST("/:id/reactions", notificationHandler.AddNotificationReaction)               // React to an announcement
+		notifications.DELETE("/:id/reactions/:type", notificationHandler.RemoveNotificationReaction)    // Withdraw a reaction
+
 		// User notification preferences
 		notifications.GET("/preferences", notificationHandler.GetNotificationPreferences)       // Get preferences
 		notifications.PUT("/preferences", notificationHandler.UpdateNotificationPreferences)   // Update preferences
@@ -40,6 +44,9 @@ func SetupNotificationRoutes(router *gin.RouterGroup, notificationHandler *handl
 		{
 			// Send push notifications
 			admin.POST("/send", notificationHandler.SendPushNotification) // Send push notification
+
+			// Announcement acknowledgment
+			admin.GET("/broadcasts/:broadcastId/reactions", notificationHandler.GetBroadcastReactions) // Reaction counts
 		}
 	}
 }
\ No newline at end of file
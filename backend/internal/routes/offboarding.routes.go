@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupOffboardingRoutes configures the offboarding report routes
+func SetupOffboardingRoutes(router *gin.RouterGroup, offboardingHandler *handlers.OffboardingHandler, authMiddleware *middleware.AuthMiddleware) {
+	offboarding := router.Group("/admin/offboarding")
+	offboarding.Use(authMiddleware.RequireAdmin())
+	{
+		offboarding.GET("/:userId", offboardingHandler.GetOffboardingReport) // Everything to hand over before a user leaves
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupPDFTemplateRoutes configures the PDF layout management routes
+func SetupPDFTemplateRoutes(router *gin.RouterGroup, pdfTemplateHandler *handlers.PDFTemplateHandler, authMiddleware *middleware.AuthMiddleware) {
+	templates := router.Group("/pdf-templates")
+	{
+		// Admin-only operations
+		templates.Use(authMiddleware.RequireAdmin())
+		templates.GET("", pdfTemplateHandler.GetPDFTemplates)                   // List templates
+		templates.POST("", pdfTemplateHandler.CreatePDFTemplate)                // Create template
+		templates.POST("/preview", pdfTemplateHandler.PreviewPDFTemplateSource) // Render an unsaved source
+		templates.GET("/:id", pdfTemplateHandler.GetPDFTemplate)                // Get specific template
+		templates.PUT("/:id", pdfTemplateHandler.UpdatePDFTemplate)             // Update template, default or departments
+		templates.DELETE("/:id", pdfTemplateHandler.DeletePDFTemplate)          // Delete template
+		templates.GET("/:id/preview", pdfTemplateHandler.PreviewPDFTemplate)    // Render with a document or macro
+	}
+}
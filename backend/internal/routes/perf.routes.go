@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupPerfRoutes configures latency profiling routes (admin only)
+func SetupPerfRoutes(router *gin.RouterGroup, perfHandler *handlers.PerfHandler, authMiddleware *middleware.AuthMiddleware) {
+	perf := router.Group("/admin/perf")
+	{
+		perf.Use(authMiddleware.RequireAdmin())
+		perf.GET("", perfHandler.GetReport)             // Endpoint percentiles and slow queries
+		perf.DELETE("", perfHandler.ResetStats)         // Start a new measurement window
+		perf.GET("/async", perfHandler.GetAsyncStats)   // Background tasks of the handlers
+		perf.GET("/pdf", perfHandler.GetPDFRenderStats) // PDF render queue and durations
+	}
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupPolicyRoutes configures policy and policy acceptance routes
+func SetupPolicyRoutes(router *gin.RouterGroup, policyHandler *handlers.PolicyHandler, authMiddleware *middleware.AuthMiddleware) {
+	policies := router.Group("/policies")
+	{
+		// Public so the terms can be read before signing in
+		policies.GET("/current", policyHandler.GetCurrentPolicies)
+
+		// Acceptance by the current user
+		policies.GET("/pending", authMiddleware.RequireAuth(), policyHandler.GetPendingPolicies)
+		policies.POST("/accept", authMiddleware.RequireAuth(), policyHandler.AcceptPolicies)
+
+		// Admin-only operations
+		adminOps := policies.Group("").Use(authMiddleware.RequireAdmin())
+		{
+			adminOps.GET("", policyHandler.ListPolicies)                          // Every version
+			adminOps.GET("/stats", policyHandler.GetPolicyStats)                  // Acceptance completion rates
+			adminOps.GET("/:id", policyHandler.GetPolicy)                         // Get a version
+			adminOps.GET("/:id/acceptances", policyHandler.ListPolicyAcceptances) // Who accepted a version
+			adminOps.POST("", policyHandler.CreatePolicy)                         // Draft a version
+			adminOps.PUT("/:id", policyHandler.UpdatePolicy)                      // Update a draft
+			adminOps.DELETE("/:id", policyHandler.DeletePolicy)                   // Delete a draft
+			adminOps.POST("/:id/publish", policyHandler.PublishPolicy)            // Publish a draft
+		}
+	}
+}
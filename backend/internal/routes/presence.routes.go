@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupPresenceRoutes configures the routes of the users present on a document
+func SetupPresenceRoutes(router *gin.RouterGroup, presenceHandler *handlers.PresenceHandler, authMiddleware *middleware.AuthMiddleware, documentMiddleware *middleware.DocumentMiddleware) {
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		documents.GET("/:id/presence", documentMiddleware.RequireDocumentAccess(), presenceHandler.GetPresence)
+		documents.POST("/:id/presence", documentMiddleware.RequireDocumentAccess(), presenceHandler.Heartbeat)
+		documents.DELETE("/:id/presence", presenceHandler.LeaveDocument) // Allowed after access was lost
+	}
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupQMSSyncRoutes configures the external QMS connector routes
+func SetupQMSSyncRoutes(router *gin.RouterGroup, qmsSyncHandler *handlers.QMSSyncHandler, authMiddleware *middleware.AuthMiddleware, documentMiddleware *middleware.DocumentMiddleware) {
+	// Admin-only connector settings
+	qms := router.Group("/integrations/qms")
+	qms.Use(authMiddleware.RequireAdmin())
+	{
+		qms.GET("", qmsSyncHandler.GetConnector)
+		qms.PUT("", qmsSyncHandler.UpdateConnector)
+		qms.GET("/syncs", qmsSyncHandler.ListSyncs) // Sync status of every document
+	}
+
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		documents.GET("/:id/qms-sync", documentMiddleware.RequireDocumentAccess(), qmsSyncHandler.GetDocumentSync)
+		documents.POST("/:id/qms-sync", authMiddleware.RequireManager(), documentMiddleware.RequireDocumentAccess(), qmsSyncHandler.SyncDocument) // Sync now or retry
+	}
+}
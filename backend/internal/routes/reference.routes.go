@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupReferenceRoutes configures the document reference scheme routes
+func SetupReferenceRoutes(router *gin.RouterGroup, referenceHandler *handlers.ReferenceHandler, authMiddleware *middleware.AuthMiddleware) {
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		documents.POST("/preview-reference", referenceHandler.PreviewReference)                                   // Next reference of a macro
+		documents.GET("/reference-scheme", referenceHandler.GetReferenceScheme)                                   // Current scheme
+		documents.PUT("/reference-scheme", authMiddleware.RequireAdmin(), referenceHandler.UpdateReferenceScheme) // Change the scheme
+	}
+}
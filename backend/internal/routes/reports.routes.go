@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupReportRoutes configures the register export routes
+func SetupReportRoutes(router *gin.RouterGroup, reportHandler *handlers.ReportHandler, authMiddleware *middleware.AuthMiddleware) {
+	reports := router.Group("/reports")
+	reports.Use(authMiddleware.RequireManager())
+	{
+		reports.GET("/signatures", reportHandler.ExportSignatures) // Signatures register of a period
+	}
+}
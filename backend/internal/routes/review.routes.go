@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupReviewRoutes configures periodic document review routes
+func SetupReviewRoutes(
+	router *gin.RouterGroup,
+	reviewHandler *handlers.ReviewHandler,
+	authMiddleware *middleware.AuthMiddleware,
+	documentMiddleware *middleware.DocumentMiddleware,
+) {
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		// Manager-level: review planning across all published procedures
+		documents.GET("/reviews", authMiddleware.RequireManager(), reviewHandler.ListUpcomingReviews)
+
+		// Managers and authors confirm that a published procedure is still valid
+		documents.POST("/:id/review", documentMiddleware.RequireDocumentAccess(), reviewHandler.CompleteReview)
+	}
+}
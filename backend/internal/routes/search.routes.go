@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupSearchRoutes configures cross-document search routes
+func SetupSearchRoutes(router *gin.RouterGroup, searchHandler *handlers.SearchHandler, authMiddleware *middleware.AuthMiddleware) {
+	search := router.Group("/search")
+	{
+		search.Use(authMiddleware.RequireAuth())
+		search.GET("/by-actor", searchHandler.SearchByActor)    // Steps where an actor is responsible
+		search.POST("/clicks", searchHandler.RecordSearchClick) // Click-through from a search result
+
+		// Admin-only routes
+		adminOps := search.Group("").Use(authMiddleware.RequireAdmin())
+		{
+			adminOps.GET("/analytics", searchHandler.GetSearchAnalytics) // Search aggregates
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+)
+
+// SetupShortLinkRoutes configures the public short links printed on the PDFs,
+// outside the API group so they stay short
+func SetupShortLinkRoutes(router *gin.RouterGroup, documentHandler *handlers.DocumentHandler) {
+	router.GET("/d/:reference", documentHandler.OpenShortLink) // Redirect to the live version of a document
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupSignatureReminderRoutes configures the signature reminder routes of the documents
+func SetupSignatureReminderRoutes(router *gin.RouterGroup, signatureReminderHandler *handlers.SignatureReminderHandler, authMiddleware *middleware.AuthMiddleware) {
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		// Owner-only: checked by the handler
+		documents.PUT("/:id/signature-reminders", signatureReminderHandler.UpdateSignatureReminders)     // Mute or unmute the scheduled reminders
+		documents.POST("/:id/signature-reminders/send", signatureReminderHandler.SendSignatureReminders) // Remind the pending signers now
+	}
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupSnippetRoutes configures the shared process step snippet routes
+func SetupSnippetRoutes(router *gin.RouterGroup, snippetHandler *handlers.SnippetHandler, authMiddleware *middleware.AuthMiddleware, documentMiddleware *middleware.DocumentMiddleware) {
+	snippets := router.Group("/snippets")
+	{
+		// Authenticated users can browse the library while editing documents
+		snippets.Use(authMiddleware.RequireAuth())
+		snippets.GET("", snippetHandler.GetSnippets)    // List snippets
+		snippets.GET("/:id", snippetHandler.GetSnippet) // Get specific snippet
+
+		// Manager-level operations - require manager or admin role
+		managerOps := snippets.Group("").Use(authMiddleware.RequireManager())
+		{
+			managerOps.POST("", snippetHandler.CreateSnippet)              // Create snippet
+			managerOps.PUT("/:id", snippetHandler.UpdateSnippet)           // Update snippet, new version when the steps change
+			managerOps.GET("/outdated", snippetHandler.GetOutdatedReport)  // Documents embedding outdated snippet versions
+			managerOps.GET("/:id/usages", snippetHandler.GetSnippetUsages) // Documents embedding a snippet
+		}
+
+		// Admin-only operations
+		adminOps := snippets.Group("").Use(authMiddleware.RequireAdmin())
+		{
+			adminOps.DELETE("/:id", snippetHandler.DeleteSnippet) // Delete snippet
+		}
+	}
+
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		documents.POST("/:id/snippets/:snippetId", documentMiddleware.RequireDocumentAccess(), snippetHandler.EmbedSnippet)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupStatusRoutes configures the status page routes
+func SetupStatusRoutes(router *gin.RouterGroup, statusHandler *handlers.StatusHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Public, uptime aggregated by hour
+	router.GET("/status", statusHandler.GetStatus)
+
+	// Raw health samples and error details
+	router.GET("/admin/status", authMiddleware.RequireAdmin(), statusHandler.GetStatusHistory)
+}
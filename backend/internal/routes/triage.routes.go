@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupTriageRoutes configures the triage queue of the documents created in bulk
+func SetupTriageRoutes(router *gin.RouterGroup, triageHandler *handlers.TriageHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Quality managers review the imported documents
+	triage := router.Group("/triage")
+	triage.Use(authMiddleware.RequireManager())
+	{
+		triage.GET("", triageHandler.GetTriageQueue)
+		triage.GET("/stats", triageHandler.GetTriageStats)        // Queue size and SLA compliance
+		triage.PUT("/:id/assignee", triageHandler.AssignTriage)   // Give the triage to a quality manager
+		triage.PUT("/:id/macro", triageHandler.AssignTriageMacro) // Attach the document to its macro
+		triage.PUT("/:id/owner", triageHandler.AssignTriageOwner) // Replace the importer as owner
+		triage.POST("/:id/accept", triageHandler.AcceptTriage)    // Continue as a regular draft
+		triage.POST("/:id/discard", triageHandler.DiscardTriage)  // Move the document to the trash
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+	"github.com/kodesonik/process-manager/internal/middleware"
+)
+
+// SetupWatchRoutes configures the document watch routes
+func SetupWatchRoutes(router *gin.RouterGroup, watchHandler *handlers.WatchHandler, authMiddleware *middleware.AuthMiddleware, documentMiddleware *middleware.DocumentMiddleware) {
+	documents := router.Group("/documents")
+	documents.Use(authMiddleware.RequireAuth())
+	{
+		documents.GET("/:id/watch", documentMiddleware.RequireDocumentAccess(), watchHandler.GetWatchStatus)
+		documents.POST("/:id/watch", documentMiddleware.RequireDocumentAccess(), watchHandler.WatchDocument)
+		documents.DELETE("/:id/watch", watchHandler.UnwatchDocument) // Allowed after access was lost
+	}
+}
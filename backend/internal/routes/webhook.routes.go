@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kodesonik/process-manager/internal/handlers"
+)
+
+// SetupWebhookRoutes configures the callbacks of external providers, which
+// authenticate with their own secret instead of a user token
+func SetupWebhookRoutes(router *gin.RouterGroup, webhookHandler *handlers.WebhookHandler) {
+	webhooks := router.Group("/webhooks")
+	{
+		webhooks.POST("/brevo", webhookHandler.BrevoWebhook) // Transactional email events
+	}
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AccountDeletionService handles self-service account deletion requests.
+// A request is confirmed by OTP, then the account is anonymized once the
+// grace period is over unless the user or an admin cancels it.
+type AccountDeletionService struct {
+	collection     *mongo.Collection
+	userCollection *mongo.Collection
+	userService    *UserService
+	otpService     *OTPService
+}
+
+// NewAccountDeletionService creates a new account deletion service
+func NewAccountDeletionService(db *DatabaseService, userService *UserService, otpService *OTPService) *AccountDeletionService {
+	service := &AccountDeletionService{
+		collection:     db.Collection("account_deletion_requests"),
+		userCollection: db.Collection("users"),
+		userService:    userService,
+		otpService:     otpService,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "scheduled_for", Value: 1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create account deletion indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// accountDeletionGracePeriod returns the delay between the confirmation of a
+// deletion and the anonymization of the account, set by ACCOUNT_DELETION_GRACE_DAYS
+func accountDeletionGracePeriod() time.Duration {
+	if v, err := strconv.Atoi(os.Getenv("ACCOUNT_DELETION_GRACE_DAYS")); err == nil && v >= 0 {
+		return time.Duration(v) * 24 * time.Hour
+	}
+	return 30 * 24 * time.Hour
+}
+
+// accountDeletionOTPKey keeps deletion OTPs apart from the login ones
+func accountDeletionOTPKey(email string) string {
+	return "account-deletion:" + email
+}
+
+// openAccountDeletionStatuses are the statuses of a deletion not yet carried out or cancelled
+var openAccountDeletionStatuses = []models.AccountDeletionStatus{
+	models.AccountDeletionPendingConfirmation,
+	models.AccountDeletionScheduled,
+}
+
+// RequestDeletion opens a deletion request for the user and returns the OTP
+// that confirms it. A previous unconfirmed request is replaced.
+func (s *AccountDeletionService) RequestDeletion(ctx context.Context, user *models.User, reason string) (*models.AccountDeletionRequest, string, error) {
+	existing, err := s.GetOpenRequest(ctx, user.ID)
+	if err != nil && err != models.ErrDeletionRequestNotFound {
+		return nil, "", err
+	}
+	if existing != nil && existing.Status == models.AccountDeletionScheduled {
+		return nil, "", models.ErrDeletionAlreadyScheduled
+	}
+	if err := s.checkLastAdmin(ctx, user); err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	request := models.AccountDeletionRequest{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Name:      fmt.Sprintf("%s %s", user.FirstName, user.LastName),
+		Reason:    reason,
+		Status:    models.AccountDeletionPendingConfirmation,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if existing != nil {
+		request.ID = existing.ID
+		request.CreatedAt = existing.CreatedAt
+		if _, err := s.collection.ReplaceOne(ctx, bson.M{"_id": existing.ID}, request); err != nil {
+			return nil, "", fmt.Errorf("failed to update deletion request: %w", err)
+		}
+	} else {
+		result, err := s.collection.InsertOne(ctx, request)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create deletion request: %w", err)
+		}
+		request.ID = result.InsertedID.(primitive.ObjectID)
+	}
+
+	otp, err := s.otpService.GenerateOTP(ctx, accountDeletionOTPKey(user.Email))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &request, otp, nil
+}
+
+// ConfirmDeletion verifies the OTP and schedules the anonymization of the account
+func (s *AccountDeletionService) ConfirmDeletion(ctx context.Context, user *models.User, otp string) (*models.AccountDeletionRequest, error) {
+	existing, err := s.GetOpenRequest(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == models.AccountDeletionScheduled {
+		return nil, models.ErrDeletionAlreadyScheduled
+	}
+
+	if err := s.otpService.VerifyOTP(ctx, accountDeletionOTPKey(user.Email), otp); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	scheduledFor := now.Add(accountDeletionGracePeriod())
+	var request models.AccountDeletionRequest
+	err = s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": existing.ID, "status": models.AccountDeletionPendingConfirmation},
+		bson.M{"$set": bson.M{
+			"status":        models.AccountDeletionScheduled,
+			"confirmed_at":  now,
+			"scheduled_for": scheduledFor,
+			"updated_at":    now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&request)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrDeletionRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to confirm deletion request: %w", err)
+	}
+
+	return &request, nil
+}
+
+// GetOpenRequest returns the pending or scheduled deletion request of a user
+func (s *AccountDeletionService) GetOpenRequest(ctx context.Context, userID primitive.ObjectID) (*models.AccountDeletionRequest, error) {
+	var request models.AccountDeletionRequest
+	err := s.collection.FindOne(ctx, bson.M{
+		"user_id": userID,
+		"status":  bson.M{"$in": openAccountDeletionStatuses},
+	}).Decode(&request)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrDeletionRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get deletion request: %w", err)
+	}
+
+	return &request, nil
+}
+
+// CancelForUser cancels the open deletion request of a user
+func (s *AccountDeletionService) CancelForUser(ctx context.Context, userID primitive.ObjectID) (*models.AccountDeletionRequest, error) {
+	return s.cancel(ctx, bson.M{"user_id": userID}, userID)
+}
+
+// Cancel cancels an open deletion request on behalf of an admin
+func (s *AccountDeletionService) Cancel(ctx context.Context, id, adminID primitive.ObjectID) (*models.AccountDeletionRequest, error) {
+	return s.cancel(ctx, bson.M{"_id": id}, adminID)
+}
+
+func (s *AccountDeletionService) cancel(ctx context.Context, filter bson.M, cancelledBy primitive.ObjectID) (*models.AccountDeletionRequest, error) {
+	filter["status"] = bson.M{"$in": openAccountDeletionStatuses}
+
+	now := time.Now()
+	var request models.AccountDeletionRequest
+	err := s.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		bson.M{"$set": bson.M{
+			"status":       models.AccountDeletionCancelled,
+			"cancelled_at": now,
+			"cancelled_by": cancelledBy,
+			"updated_at":   now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&request)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrDeletionRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to cancel deletion request: %w", err)
+	}
+
+	return &request, nil
+}
+
+// List returns deletion requests, soonest deletion first. Without a status,
+// the pending and scheduled requests are returned.
+func (s *AccountDeletionService) List(ctx context.Context, status string, page, limit int) ([]*models.AccountDeletionRequest, int64, error) {
+	filter := bson.M{"status": bson.M{"$in": openAccountDeletionStatuses}}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	total, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count deletion requests: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "scheduled_for", Value: 1}, {Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := s.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find deletion requests: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	requests := make([]*models.AccountDeletionRequest, 0)
+	if err = cursor.All(ctx, &requests); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode deletion requests: %w", err)
+	}
+
+	return requests, total, nil
+}
+
+// checkLastAdmin prevents the only active admin from deleting their account
+func (s *AccountDeletionService) checkLastAdmin(ctx context.Context, user *models.User) error {
+	if user.Role != models.RoleAdmin {
+		return nil
+	}
+
+	count, err := s.userCollection.CountDocuments(ctx, bson.M{
+		"role":   models.RoleAdmin,
+		"active": true,
+		"_id":    bson.M{"$ne": user.ID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count admins: %w", err)
+	}
+	if count == 0 {
+		return models.ErrLastAdminDeletion
+	}
+
+	return nil
+}
+
+// Start runs the anonymization of due accounts every hour until the context is cancelled
+func (s *AccountDeletionService) Start(ctx context.Context) {
+	run := func() {
+		runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		count, err := s.ProcessDue(runCtx)
+		if err != nil {
+			fmt.Printf("Warning: Failed to process account deletions: %v\n", err)
+			return
+		}
+		if count > 0 {
+			fmt.Printf("🗑️  %d account(s) anonymized\n", count)
+		}
+	}
+
+	go func() {
+		run()
+
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				run()
+			}
+		}
+	}()
+	fmt.Printf("🗑️  Account deletion job started (grace period: %s)\n", accountDeletionGracePeriod())
+}
+
+// ProcessDue anonymizes the accounts whose grace period is over and returns how many were processed
+func (s *AccountDeletionService) ProcessDue(ctx context.Context) (int, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{
+		"status":        models.AccountDeletionScheduled,
+		"scheduled_for": bson.M{"$lte": time.Now()},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find due deletion requests: %w", err)
+	}
+	var due []*models.AccountDeletionRequest
+	if err := cursor.All(ctx, &due); err != nil {
+		return 0, fmt.Errorf("failed to decode due deletion requests: %w", err)
+	}
+
+	count := 0
+	for _, request := range due {
+		if err := s.userService.AnonymizeUser(ctx, request.UserID); err != nil && err != models.ErrUserNotFound {
+			return count, fmt.Errorf("failed to anonymize user %s: %w", request.UserID.Hex(), err)
+		}
+		if err := s.otpService.RevokeAllUserRefreshTokens(ctx, request.UserID.Hex()); err != nil {
+			fmt.Printf("⚠️  Failed to revoke tokens of deleted user %s: %v\n", request.UserID.Hex(), err)
+		}
+
+		// Personal data is dropped from the request too, only the trace of the deletion remains
+		now := time.Now()
+		if _, err := s.collection.UpdateOne(ctx, bson.M{"_id": request.ID}, bson.M{
+			"$set": bson.M{
+				"status":       models.AccountDeletionCompleted,
+				"email":        "",
+				"name":         "",
+				"completed_at": now,
+				"updated_at":   now,
+			},
+			"$unset": bson.M{"reason": ""},
+		}); err != nil {
+			return count, fmt.Errorf("failed to complete deletion request: %w", err)
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AcknowledgmentService handles the campaigns asking the members of
+// departments to confirm they have read a published document
+type AcknowledgmentService struct {
+	campaignCollection       *mongo.Collection
+	acknowledgmentCollection *mongo.Collection
+	departmentCollection     *mongo.Collection
+	userCollection           *mongo.Collection
+	documentService          *DocumentService
+	notificationService      *NotificationService
+}
+
+// NewAcknowledgmentService creates a new acknowledgment service instance
+func NewAcknowledgmentService(db *DatabaseService, documentService *DocumentService, notificationService *NotificationService) *AcknowledgmentService {
+	service := &AcknowledgmentService{
+		campaignCollection:       db.Collection("acknowledgment_campaigns"),
+		acknowledgmentCollection: db.Collection("acknowledgments"),
+		departmentCollection:     db.Collection("departments"),
+		userCollection:           db.Collection("users"),
+		documentService:          documentService,
+		notificationService:      notificationService,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.campaignCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "document_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create acknowledgment campaign indexes: %v\n", err)
+	}
+	if _, err := service.acknowledgmentCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "campaign_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create acknowledgment indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// Create launches a campaign on an archived document: every active member of
+// the departments becomes a recipient and is notified
+func (s *AcknowledgmentService) Create(ctx context.Context, documentID primitive.ObjectID, req *models.CreateAcknowledgmentCampaignRequest, createdBy primitive.ObjectID) (*models.AcknowledgmentCampaign, error) {
+	document, err := s.documentService.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if document.Status != models.DocumentStatusArchived && document.Status != models.DocumentStatusReviewDue {
+		return nil, errors.New("only archived documents can be acknowledged")
+	}
+
+	departmentIDs, err := s.parseDepartmentIDs(ctx, req.DepartmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.campaignCollection.CountDocuments(ctx, bson.M{
+		"document_id": documentID,
+		"status":      models.AcknowledgmentCampaignActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check campaigns: %w", err)
+	}
+	if count > 0 {
+		return nil, errors.New("an acknowledgment campaign is already active for this document")
+	}
+
+	cursor, err := s.userCollection.Find(ctx, bson.M{
+		"department_id": bson.M{"$in": departmentIDs},
+		"status":        models.StatusActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find department members: %w", err)
+	}
+	var users []*models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode department members: %w", err)
+	}
+	if len(users) == 0 {
+		return nil, errors.New("the selected departments have no active members")
+	}
+
+	now := time.Now()
+	campaign := &models.AcknowledgmentCampaign{
+		ID:              primitive.NewObjectID(),
+		DocumentID:      documentID,
+		Reference:       document.Reference,
+		Title:           document.Title,
+		Version:         document.Version,
+		DepartmentIDs:   departmentIDs,
+		Message:         req.Message,
+		DueDate:         req.DueDate,
+		Status:          models.AcknowledgmentCampaignActive,
+		TotalRecipients: len(users),
+		CreatedBy:       createdBy,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	acknowledgments := make([]interface{}, 0, len(users))
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		acknowledgments = append(acknowledgments, models.Acknowledgment{
+			CampaignID:   campaign.ID,
+			DocumentID:   documentID,
+			UserID:       user.ID,
+			DepartmentID: *user.DepartmentID,
+			Status:       models.AcknowledgmentPending,
+			CreatedAt:    now,
+		})
+		userIDs = append(userIDs, user.ID.Hex())
+	}
+
+	if _, err := s.campaignCollection.InsertOne(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("failed to create acknowledgment campaign: %w", err)
+	}
+	if _, err := s.acknowledgmentCollection.InsertMany(ctx, acknowledgments); err != nil {
+		s.campaignCollection.DeleteOne(ctx, bson.M{"_id": campaign.ID})
+		s.acknowledgmentCollection.DeleteMany(ctx, bson.M{"campaign_id": campaign.ID})
+		return nil, fmt.Errorf("failed to create acknowledgments: %w", err)
+	}
+
+	s.notifyRecipients(ctx, campaign, userIDs, createdBy)
+
+	return campaign, nil
+}
+
+// parseDepartmentIDs converts and checks the departments targeted by a campaign
+func (s *AcknowledgmentService) parseDepartmentIDs(ctx context.Context, ids []string) ([]primitive.ObjectID, error) {
+	departmentIDs := make([]primitive.ObjectID, 0, len(ids))
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		departmentID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid department ID: %s", id)
+		}
+		if !seen[departmentID] {
+			seen[departmentID] = true
+			departmentIDs = append(departmentIDs, departmentID)
+		}
+	}
+
+	count, err := s.departmentCollection.CountDocuments(ctx, bson.M{"_id": bson.M{"$in": departmentIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check departments: %w", err)
+	}
+	if int(count) != len(departmentIDs) {
+		return nil, errors.New("department not found")
+	}
+
+	return departmentIDs, nil
+}
+
+// notifyRecipients asks the recipients of a campaign to read the document
+func (s *AcknowledgmentService) notifyRecipients(ctx context.Context, campaign *models.AcknowledgmentCampaign, userIDs []string, senderID primitive.ObjectID) {
+	if s.notificationService == nil {
+		return
+	}
+
+	body := fmt.Sprintf("Please read %s - %s (version %s) and confirm you have read it.", campaign.Reference, campaign.Title, campaign.Version)
+	if campaign.DueDate != nil {
+		body = fmt.Sprintf("%s Due by %s.", body, campaign.DueDate.Format("2006-01-02"))
+	}
+	if campaign.Message != "" {
+		body = fmt.Sprintf("%s\n%s", body, campaign.Message)
+	}
+
+	notificationReq := &models.SendNotificationRequest{
+		UserIDs:  userIDs,
+		Title:    "Read Acknowledgment Required",
+		Body:     body,
+		Category: models.NotificationCategoryReminder,
+		Priority: models.NotificationPriorityHigh,
+		Data: map[string]interface{}{
+			"documentId": campaign.DocumentID.Hex(),
+			"campaignId": campaign.ID.Hex(),
+			"action":     "acknowledgment_requested",
+		},
+	}
+	if _, err := s.notificationService.SendNotification(ctx, notificationReq, senderID); err != nil {
+		fmt.Printf("Failed to notify acknowledgment recipients: %v\n", err)
+	}
+}
+
+// GetByID retrieves a campaign by ID
+func (s *AcknowledgmentService) GetByID(ctx context.Context, id primitive.ObjectID) (*models.AcknowledgmentCampaign, error) {
+	var campaign models.AcknowledgmentCampaign
+	if err := s.campaignCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&campaign); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("acknowledgment campaign not found")
+		}
+		return nil, fmt.Errorf("failed to get acknowledgment campaign: %w", err)
+	}
+	return &campaign, nil
+}
+
+// ListByDocument returns the campaigns of a document, newest first
+func (s *AcknowledgmentService) ListByDocument(ctx context.Context, documentID primitive.ObjectID) ([]models.AcknowledgmentCampaign, error) {
+	cursor, err := s.campaignCollection.Find(ctx,
+		bson.M{"document_id": documentID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list acknowledgment campaigns: %w", err)
+	}
+
+	campaigns := []models.AcknowledgmentCampaign{}
+	if err := cursor.All(ctx, &campaigns); err != nil {
+		return nil, fmt.Errorf("failed to decode acknowledgment campaigns: %w", err)
+	}
+	return campaigns, nil
+}
+
+// GetRecipients returns the recipients of a campaign with their read receipt,
+// optionally filtered by acknowledgment status
+func (s *AcknowledgmentService) GetRecipients(ctx context.Context, campaignID primitive.ObjectID, status string, page, limit int) ([]models.AcknowledgmentRecipientResponse, int64, error) {
+	filter := bson.M{"campaign_id": campaignID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	total, err := s.acknowledgmentCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count acknowledgments: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "status", Value: 1}, {Key: "acknowledged_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := s.acknowledgmentCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list acknowledgments: %w", err)
+	}
+	var acknowledgments []models.Acknowledgment
+	if err := cursor.All(ctx, &acknowledgments); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode acknowledgments: %w", err)
+	}
+
+	userIDs := make([]primitive.ObjectID, 0, len(acknowledgments))
+	for _, acknowledgment := range acknowledgments {
+		userIDs = append(userIDs, acknowledgment.UserID)
+	}
+	users := make(map[primitive.ObjectID]models.User, len(userIDs))
+	if len(userIDs) > 0 {
+		userCursor, err := s.userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to find recipients: %w", err)
+		}
+		var found []models.User
+		if err := userCursor.All(ctx, &found); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode recipients: %w", err)
+		}
+		for _, user := range found {
+			users[user.ID] = user
+		}
+	}
+
+	recipients := make([]models.AcknowledgmentRecipientResponse, 0, len(acknowledgments))
+	for _, acknowledgment := range acknowledgments {
+		user := users[acknowledgment.UserID]
+		recipients = append(recipients, models.AcknowledgmentRecipientResponse{
+			UserID:         acknowledgment.UserID.Hex(),
+			FirstName:      user.FirstName,
+			LastName:       user.LastName,
+			Email:          user.Email,
+			DepartmentID:   acknowledgment.DepartmentID.Hex(),
+			Status:         acknowledgment.Status,
+			AcknowledgedAt: acknowledgment.AcknowledgedAt,
+		})
+	}
+
+	return recipients, total, nil
+}
+
+// Acknowledge records that the user has read the document of a campaign.
+// Acknowledging twice is a no-op.
+func (s *AcknowledgmentService) Acknowledge(ctx context.Context, campaignID, userID primitive.ObjectID) (*models.AcknowledgmentCampaign, error) {
+	campaign, err := s.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != models.AcknowledgmentCampaignActive {
+		return nil, errors.New("acknowledgment campaign is closed")
+	}
+
+	now := time.Now()
+	result, err := s.acknowledgmentCollection.UpdateOne(ctx,
+		bson.M{"campaign_id": campaignID, "user_id": userID, "status": models.AcknowledgmentPending},
+		bson.M{"$set": bson.M{"status": models.AcknowledgmentAcknowledged, "acknowledged_at": now}},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acknowledge document: %w", err)
+	}
+
+	if result.ModifiedCount == 0 {
+		count, err := s.acknowledgmentCollection.CountDocuments(ctx, bson.M{"campaign_id": campaignID, "user_id": userID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to check acknowledgment: %w", err)
+		}
+		if count == 0 {
+			return nil, errors.New("you are not a recipient of this campaign")
+		}
+		return campaign, nil
+	}
+
+	err = s.campaignCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": campaignID},
+		bson.M{"$inc": bson.M{"acknowledged_count": 1}, "$set": bson.M{"updated_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(campaign)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update acknowledgment campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// ListPendingForUser returns the documents the user still has to acknowledge
+// in active campaigns
+func (s *AcknowledgmentService) ListPendingForUser(ctx context.Context, userID primitive.ObjectID) ([]models.PendingAcknowledgmentResponse, error) {
+	cursor, err := s.acknowledgmentCollection.Find(ctx, bson.M{"user_id": userID, "status": models.AcknowledgmentPending})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending acknowledgments: %w", err)
+	}
+	var acknowledgments []models.Acknowledgment
+	if err := cursor.All(ctx, &acknowledgments); err != nil {
+		return nil, fmt.Errorf("failed to decode pending acknowledgments: %w", err)
+	}
+
+	pending := []models.PendingAcknowledgmentResponse{}
+	if len(acknowledgments) == 0 {
+		return pending, nil
+	}
+
+	campaignIDs := make([]primitive.ObjectID, 0, len(acknowledgments))
+	for _, acknowledgment := range acknowledgments {
+		campaignIDs = append(campaignIDs, acknowledgment.CampaignID)
+	}
+	campaignCursor, err := s.campaignCollection.Find(ctx,
+		bson.M{"_id": bson.M{"$in": campaignIDs}, "status": models.AcknowledgmentCampaignActive},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list acknowledgment campaigns: %w", err)
+	}
+	var campaigns []models.AcknowledgmentCampaign
+	if err := campaignCursor.All(ctx, &campaigns); err != nil {
+		return nil, fmt.Errorf("failed to decode acknowledgment campaigns: %w", err)
+	}
+
+	for _, campaign := range campaigns {
+		pending = append(pending, models.PendingAcknowledgmentResponse{
+			CampaignID: campaign.ID.Hex(),
+			DocumentID: campaign.DocumentID.Hex(),
+			Reference:  campaign.Reference,
+			Title:      campaign.Title,
+			Version:    campaign.Version,
+			Message:    campaign.Message,
+			DueDate:    campaign.DueDate,
+			CreatedAt:  campaign.CreatedAt,
+		})
+	}
+	return pending, nil
+}
+
+// Close ends a campaign, remaining recipients can no longer acknowledge
+func (s *AcknowledgmentService) Close(ctx context.Context, campaignID primitive.ObjectID) (*models.AcknowledgmentCampaign, error) {
+	now := time.Now()
+	var campaign models.AcknowledgmentCampaign
+	err := s.campaignCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": campaignID, "status": models.AcknowledgmentCampaignActive},
+		bson.M{"$set": bson.M{"status": models.AcknowledgmentCampaignClosed, "closed_at": now, "updated_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&campaign)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, getErr := s.GetByID(ctx, campaignID); getErr != nil {
+				return nil, getErr
+			}
+			return nil, errors.New("acknowledgment campaign is already closed")
+		}
+		return nil, fmt.Errorf("failed to close acknowledgment campaign: %w", err)
+	}
+	return &campaign, nil
+}
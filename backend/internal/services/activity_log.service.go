@@ -12,81 +12,35 @@ import (
 	"github.com/kodesonik/process-manager/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// ActivityLogService handles activity logging operations
+// ActivityLogService handles activity logging operations. Logs are stored
+// in monthly partitions, queries fan out to the months they cover.
 type ActivityLogService struct {
 	db         *DatabaseService
-	collection *mongo.Collection
+	partitions *activityLogPartitions
 }
 
 // NewActivityLogService creates a new activity log service instance
 func NewActivityLogService(db *DatabaseService) *ActivityLogService {
-	collection := db.Database.Collection("activity_logs")
+	partitions := newActivityLogPartitions(db.Database)
 
-	// Create indexes for better query performance
+	// Move the logs written before partitioning to their monthly partitions
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
 		defer cancel()
 
-		indexes := []mongo.IndexModel{
-			{
-				Keys: bson.D{
-					{Key: "user_id", Value: 1},
-					{Key: "timestamp", Value: -1},
-				},
-			},
-			{
-				Keys: bson.D{
-					{Key: "action", Value: 1},
-					{Key: "timestamp", Value: -1},
-				},
-			},
-			{
-				Keys: bson.D{
-					{Key: "category", Value: 1},
-					{Key: "timestamp", Value: -1},
-				},
-			},
-			{
-				Keys: bson.D{
-					{Key: "level", Value: 1},
-					{Key: "timestamp", Value: -1},
-				},
-			},
-			{
-				Keys: bson.D{
-					{Key: "resource_type", Value: 1},
-					{Key: "resource_id", Value: 1},
-					{Key: "timestamp", Value: -1},
-				},
-			},
-			{
-				Keys: bson.D{
-					{Key: "timestamp", Value: -1},
-				},
-			},
-			{
-				Keys: bson.D{
-					{Key: "target_user_id", Value: 1},
-					{Key: "timestamp", Value: -1},
-				},
-			},
-		}
-
-		_, err := collection.Indexes().CreateMany(ctx, indexes)
+		moved, err := partitions.migrateLegacy(ctx)
 		if err != nil {
-			log.Printf("Warning: Failed to create activity log indexes: %v", err)
-		} else {
-			log.Printf("✅ Activity log indexes created successfully")
+			log.Printf("Warning: Failed to partition activity logs: %v", err)
+		} else if moved > 0 {
+			log.Printf("✅ Moved %d activity logs to monthly partitions", moved)
 		}
 	}()
 
 	return &ActivityLogService{
 		db:         db,
-		collection: collection,
+		partitions: partitions,
 	}
 }
 
@@ -148,8 +102,8 @@ func (s *ActivityLogService) LogActivity(ctx context.Context, req models.Activit
 		CreatedAt:    now,
 	}
 
-	// Insert into database
-	_, err := s.collection.InsertOne(ctx, activityLog)
+	// Insert into the partition of the month
+	_, err := s.partitions.forWrite(now).InsertOne(ctx, activityLog)
 	if err != nil {
 		log.Printf("Failed to log activity: %v", err)
 		return fmt.Errorf("failed to log activity: %w", err)
@@ -195,8 +149,8 @@ func (s *ActivityLogService) LogActivitySimple(ctx context.Context, action model
 		CreatedAt:   now,
 	}
 
-	// Insert into database
-	_, err := s.collection.InsertOne(ctx, activityLog)
+	// Insert into the partition of the month
+	_, err := s.partitions.forWrite(now).InsertOne(ctx, activityLog)
 	if err != nil {
 		log.Printf("Failed to log activity: %v", err)
 		return fmt.Errorf("failed to log activity: %w", err)
@@ -207,7 +161,45 @@ func (s *ActivityLogService) LogActivitySimple(ctx context.Context, action model
 
 // GetActivityLogs retrieves activity logs with filters and pagination
 func (s *ActivityLogService) GetActivityLogs(ctx context.Context, filters models.ActivityLogFilters) ([]models.ActivityLog, int64, error) {
-	// Build filter query
+	filter := activityLogQuery(filters)
+
+	// Only the months of the date range are read
+	partitions, err := s.partitions.list(ctx, filters.DateFrom, filters.DateTo)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Get total count
+	counts, total, err := s.partitions.count(ctx, partitions, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Set pagination defaults
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.Limit < 1 {
+		filters.Limit = 20
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+
+	// Calculate skip
+	skip := (filters.Page - 1) * filters.Limit
+
+	// Read the page from the partitions holding it, newest first
+	activityLogs, err := s.partitions.findPage(ctx, partitions, counts, filter, int64(skip), int64(filters.Limit))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return activityLogs, total, nil
+}
+
+// activityLogQuery builds the query matching the activity log filters
+func activityLogQuery(filters models.ActivityLogFilters) bson.M {
 	filter := bson.M{}
 
 	if filters.UserID != nil {
@@ -258,60 +250,23 @@ func (s *ActivityLogService) GetActivityLogs(ctx context.Context, filters models
 		filter["timestamp"] = dateFilter
 	}
 
-	// Get total count
-	total, err := s.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count activity logs: %w", err)
-	}
-
-	// Set pagination defaults
-	if filters.Page < 1 {
-		filters.Page = 1
-	}
-	if filters.Limit < 1 {
-		filters.Limit = 20
-	}
-	if filters.Limit > 100 {
-		filters.Limit = 100
-	}
-
-	// Calculate skip
-	skip := (filters.Page - 1) * filters.Limit
-
-	// Find options with pagination and sorting
-	findOptions := options.Find().
-		SetSort(bson.D{{Key: "timestamp", Value: -1}}). // Sort by timestamp descending
-		SetSkip(int64(skip)).
-		SetLimit(int64(filters.Limit))
+	return filter
+}
 
-	// Execute query
-	cursor, err := s.collection.Find(ctx, filter, findOptions)
+// StreamActivityLogs calls fn for every activity log matching the filters,
+// newest first, reading them from a cursor instead of loading the whole list.
+// Pagination filters are ignored.
+func (s *ActivityLogService) StreamActivityLogs(ctx context.Context, filters models.ActivityLogFilters, fn func(*models.ActivityLog) error) error {
+	partitions, err := s.partitions.list(ctx, filters.DateFrom, filters.DateTo)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to find activity logs: %w", err)
-	}
-	defer cursor.Close(ctx)
-
-	// Decode results
-	var activityLogs []models.ActivityLog
-	if err = cursor.All(ctx, &activityLogs); err != nil {
-		return nil, 0, fmt.Errorf("failed to decode activity logs: %w", err)
+		return err
 	}
-
-	return activityLogs, total, nil
+	return s.partitions.stream(ctx, partitions, activityLogQuery(filters), fn)
 }
 
 // GetActivityLogByID retrieves a specific activity log by ID
 func (s *ActivityLogService) GetActivityLogByID(ctx context.Context, id primitive.ObjectID) (*models.ActivityLog, error) {
-	var activityLog models.ActivityLog
-	err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&activityLog)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("activity log not found")
-		}
-		return nil, fmt.Errorf("failed to find activity log: %w", err)
-	}
-
-	return &activityLog, nil
+	return s.partitions.findByID(ctx, id)
 }
 
 // GetUserActivitySummary gets activity summary for a specific user
@@ -337,11 +292,10 @@ func (s *ActivityLogService) GetUserActivitySummary(ctx context.Context, userID
 		},
 	}
 
-	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	partitions, err := s.partitions.list(ctx, &startDate, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to aggregate activity summary: %w", err)
+		return nil, err
 	}
-	defer cursor.Close(ctx)
 
 	// Process results
 	summary := map[string]interface{}{
@@ -354,8 +308,16 @@ func (s *ActivityLogService) GetUserActivitySummary(ctx context.Context, userID
 	}
 
 	var results []bson.M
-	if err = cursor.All(ctx, &results); err != nil {
-		return nil, fmt.Errorf("failed to decode activity summary: %w", err)
+	for _, partition := range partitions {
+		cursor, err := partition.collection.Aggregate(ctx, pipeline)
+		if err != nil {
+			return nil, fmt.Errorf("failed to aggregate activity summary: %w", err)
+		}
+		var partitionResults []bson.M
+		if err = cursor.All(ctx, &partitionResults); err != nil {
+			return nil, fmt.Errorf("failed to decode activity summary: %w", err)
+		}
+		results = append(results, partitionResults...)
 	}
 
 	for _, result := range results {
@@ -382,27 +344,28 @@ func (s *ActivityLogService) GetUserActivitySummary(ctx context.Context, userID
 func (s *ActivityLogService) DeleteOldActivityLogs(ctx context.Context, olderThanDays int) (int64, error) {
 	cutoffDate := time.Now().AddDate(0, 0, -olderThanDays)
 
-	result, err := s.collection.DeleteMany(ctx, bson.M{
-		"timestamp": bson.M{"$lt": cutoffDate},
-	})
-	if err != nil {
-		return 0, fmt.Errorf("failed to delete old activity logs: %w", err)
-	}
-
-	return result.DeletedCount, nil
+	return s.partitions.deleteBefore(ctx, cutoffDate)
 }
 
 // GetActivityLogStats returns general statistics about activity logs
 func (s *ActivityLogService) GetActivityLogStats(ctx context.Context) (map[string]interface{}, error) {
 	// Total count
-	total, err := s.collection.CountDocuments(ctx, bson.M{})
+	partitions, err := s.partitions.list(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, total, err := s.partitions.count(ctx, partitions, bson.M{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to count total activity logs: %w", err)
 	}
 
 	// Recent activity (last 24 hours)
 	last24h := time.Now().Add(-24 * time.Hour)
-	recent, err := s.collection.CountDocuments(ctx, bson.M{
+	recentPartitions, err := s.partitions.list(ctx, &last24h, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, recent, err := s.partitions.count(ctx, recentPartitions, bson.M{
 		"timestamp": bson.M{"$gte": last24h},
 	})
 	if err != nil {
@@ -410,7 +373,7 @@ func (s *ActivityLogService) GetActivityLogStats(ctx context.Context) (map[strin
 	}
 
 	// Failed actions (last 24 hours)
-	failed, err := s.collection.CountDocuments(ctx, bson.M{
+	_, failed, err := s.partitions.count(ctx, recentPartitions, bson.M{
 		"timestamp": bson.M{"$gte": last24h},
 		"success":   false,
 	})
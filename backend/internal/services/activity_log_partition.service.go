@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Activity logs are partitioned by month into activity_logs_YYYY_MM
+// collections, so that a query bounded by dates only reads the months it
+// covers and old months are dropped instead of deleted log by log. The former
+// unpartitioned collection is read as the oldest partition while its logs are
+// moved to the monthly ones, newest first, so it never holds a log more
+// recent than the partitions.
+const (
+	activityLogLegacyCollection   = "activity_logs"
+	activityLogPartitionPrefix    = "activity_logs_"
+	activityLogPartitionLayout    = "2006_01"
+	activityLogMigrationBatchSize = 500
+)
+
+// activityLogIndexes are created on every partition
+var activityLogIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+	{Keys: bson.D{{Key: "action", Value: 1}, {Key: "timestamp", Value: -1}}},
+	{Keys: bson.D{{Key: "category", Value: 1}, {Key: "timestamp", Value: -1}}},
+	{Keys: bson.D{{Key: "level", Value: 1}, {Key: "timestamp", Value: -1}}},
+	{Keys: bson.D{{Key: "resource_type", Value: 1}, {Key: "resource_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+	{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+	{Keys: bson.D{{Key: "target_user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+}
+
+// activityLogPartition is a collection holding the activity logs of a month,
+// or the legacy collection
+type activityLogPartition struct {
+	collection *mongo.Collection
+	month      time.Time // First instant of the month, zero for the legacy collection
+}
+
+// activityLogPartitions routes activity log reads and writes to the monthly
+// partitions
+type activityLogPartitions struct {
+	db      *mongo.Database
+	indexed sync.Map // Names of the partitions whose indexes were created
+}
+
+func newActivityLogPartitions(db *mongo.Database) *activityLogPartitions {
+	return &activityLogPartitions{db: db}
+}
+
+// activityLogPartitionName returns the partition holding the logs of t
+func activityLogPartitionName(t time.Time) string {
+	return activityLogPartitionPrefix + t.UTC().Format(activityLogPartitionLayout)
+}
+
+// forWrite returns the partition of t, creating its indexes on first use
+func (p *activityLogPartitions) forWrite(t time.Time) *mongo.Collection {
+	name := activityLogPartitionName(t)
+	collection := p.db.Collection(name)
+	if _, loaded := p.indexed.LoadOrStore(name, true); !loaded {
+		go p.ensureIndexes(collection)
+	}
+	return collection
+}
+
+// ensureIndexes creates the indexes of a partition
+func (p *activityLogPartitions) ensureIndexes(collection *mongo.Collection) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := collection.Indexes().CreateMany(ctx, activityLogIndexes); err != nil {
+		log.Printf("Warning: Failed to create activity log indexes on %s: %v", collection.Name(), err)
+		p.indexed.Delete(collection.Name()) // Retried on the next write
+	}
+}
+
+// list returns the partitions that may hold logs between from and to, newest
+// first. Open bounds are unbounded; the legacy collection comes last when it
+// still exists.
+func (p *activityLogPartitions) list(ctx context.Context, from, to *time.Time) ([]activityLogPartition, error) {
+	names, err := p.db.ListCollectionNames(ctx, bson.M{"name": bson.M{"$regex": "^" + activityLogLegacyCollection}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activity log partitions: %w", err)
+	}
+
+	partitions := make([]activityLogPartition, 0, len(names))
+	hasLegacy := false
+	for _, name := range names {
+		if name == activityLogLegacyCollection {
+			hasLegacy = true
+			continue
+		}
+		month, err := time.Parse(activityLogPartitionLayout, strings.TrimPrefix(name, activityLogPartitionPrefix))
+		if err != nil {
+			continue
+		}
+		if to != nil && month.After(*to) {
+			continue
+		}
+		if from != nil && !month.AddDate(0, 1, 0).After(*from) {
+			continue
+		}
+		partitions = append(partitions, activityLogPartition{collection: p.db.Collection(name), month: month})
+	}
+
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].month.After(partitions[j].month)
+	})
+	if hasLegacy {
+		partitions = append(partitions, activityLogPartition{collection: p.db.Collection(activityLogLegacyCollection)})
+	}
+	return partitions, nil
+}
+
+// count returns the number of logs matching filter in each partition and in total
+func (p *activityLogPartitions) count(ctx context.Context, partitions []activityLogPartition, filter bson.M) ([]int64, int64, error) {
+	counts := make([]int64, len(partitions))
+	var total int64
+	for i, partition := range partitions {
+		count, err := partition.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to count activity logs: %w", err)
+		}
+		counts[i] = count
+		total += count
+	}
+	return counts, total, nil
+}
+
+// findPage returns the logs matching filter from skip to skip+limit, newest
+// first. Partitions do not overlap in time, so the page is read from the
+// partitions holding it, located with their counts, without merging results.
+func (p *activityLogPartitions) findPage(ctx context.Context, partitions []activityLogPartition, counts []int64, filter bson.M, skip, limit int64) ([]models.ActivityLog, error) {
+	activityLogs := make([]models.ActivityLog, 0, limit)
+	for i, partition := range partitions {
+		if limit <= 0 {
+			break
+		}
+		if skip >= counts[i] {
+			skip -= counts[i]
+			continue
+		}
+
+		findOptions := options.Find().
+			SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+			SetSkip(skip).
+			SetLimit(limit)
+		cursor, err := partition.collection.Find(ctx, filter, findOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find activity logs: %w", err)
+		}
+		var page []models.ActivityLog
+		if err := cursor.All(ctx, &page); err != nil {
+			return nil, fmt.Errorf("failed to decode activity logs: %w", err)
+		}
+
+		activityLogs = append(activityLogs, page...)
+		limit -= int64(len(page))
+		skip = 0
+	}
+	return activityLogs, nil
+}
+
+// stream calls fn for every log matching filter in the partitions, newest first
+func (p *activityLogPartitions) stream(ctx context.Context, partitions []activityLogPartition, filter bson.M, fn func(*models.ActivityLog) error) error {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetBatchSize(exportBatchSize)
+
+	for _, partition := range partitions {
+		cursor, err := partition.collection.Find(ctx, filter, findOptions)
+		if err != nil {
+			return fmt.Errorf("failed to find activity logs: %w", err)
+		}
+		for cursor.Next(ctx) {
+			var activityLog models.ActivityLog
+			if err := cursor.Decode(&activityLog); err != nil {
+				cursor.Close(ctx)
+				return fmt.Errorf("failed to decode activity log: %w", err)
+			}
+			if err := fn(&activityLog); err != nil {
+				cursor.Close(ctx)
+				return err
+			}
+		}
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findByID looks a log up in the partition of the creation time of its ID,
+// then in the legacy collection
+func (p *activityLogPartitions) findByID(ctx context.Context, id primitive.ObjectID) (*models.ActivityLog, error) {
+	for _, name := range []string{activityLogPartitionName(id.Timestamp()), activityLogLegacyCollection} {
+		var activityLog models.ActivityLog
+		err := p.db.Collection(name).FindOne(ctx, bson.M{"_id": id}).Decode(&activityLog)
+		if err == nil {
+			return &activityLog, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("failed to find activity log: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("activity log not found")
+}
+
+// deleteBefore removes the logs older than cutoff. Partitions entirely
+// before the cutoff are dropped, the others are cleaned log by log.
+func (p *activityLogPartitions) deleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	partitions, err := p.list(ctx, nil, &cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, partition := range partitions {
+		if !partition.month.IsZero() && !partition.month.AddDate(0, 1, 0).After(cutoff) {
+			count, err := partition.collection.EstimatedDocumentCount(ctx)
+			if err != nil {
+				return deleted, fmt.Errorf("failed to count activity logs: %w", err)
+			}
+			if err := partition.collection.Drop(ctx); err != nil {
+				return deleted, fmt.Errorf("failed to drop activity log partition: %w", err)
+			}
+			p.indexed.Delete(partition.collection.Name())
+			deleted += count
+			continue
+		}
+
+		result, err := partition.collection.DeleteMany(ctx, bson.M{"timestamp": bson.M{"$lt": cutoff}})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete old activity logs: %w", err)
+		}
+		deleted += result.DeletedCount
+	}
+	return deleted, nil
+}
+
+// migrateLegacy moves the logs of the legacy collection to their monthly
+// partitions, newest first, and returns the number of logs moved
+func (p *activityLogPartitions) migrateLegacy(ctx context.Context) (int64, error) {
+	legacy := p.db.Collection(activityLogLegacyCollection)
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetLimit(activityLogMigrationBatchSize)
+
+	var moved int64
+	for {
+		cursor, err := legacy.Find(ctx, bson.M{}, findOptions)
+		if err != nil {
+			return moved, fmt.Errorf("failed to read legacy activity logs: %w", err)
+		}
+		var batch []bson.M
+		if err := cursor.All(ctx, &batch); err != nil {
+			return moved, fmt.Errorf("failed to decode legacy activity logs: %w", err)
+		}
+		if len(batch) == 0 {
+			if err := legacy.Drop(ctx); err != nil {
+				return moved, fmt.Errorf("failed to drop legacy activity logs: %w", err)
+			}
+			return moved, nil
+		}
+
+		byPartition := make(map[string][]interface{})
+		ids := make([]interface{}, 0, len(batch))
+		for _, doc := range batch {
+			timestamp := time.Now()
+			if value, ok := doc["timestamp"].(primitive.DateTime); ok {
+				timestamp = value.Time()
+			} else if id, ok := doc["_id"].(primitive.ObjectID); ok {
+				timestamp = id.Timestamp()
+			}
+			name := activityLogPartitionName(timestamp)
+			byPartition[name] = append(byPartition[name], doc)
+			ids = append(ids, doc["_id"])
+		}
+
+		for name, docs := range byPartition {
+			month, _ := time.Parse(activityLogPartitionLayout, strings.TrimPrefix(name, activityLogPartitionPrefix))
+			// Logs copied by an interrupted run are already there
+			_, err := p.forWrite(month).InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+			if err != nil && !mongo.IsDuplicateKeyError(err) {
+				return moved, fmt.Errorf("failed to copy legacy activity logs: %w", err)
+			}
+		}
+
+		if _, err := legacy.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+			return moved, fmt.Errorf("failed to delete migrated activity logs: %w", err)
+		}
+		moved += int64(len(batch))
+	}
+}
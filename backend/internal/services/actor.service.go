@@ -0,0 +1,453 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// accentReplacer folds accented characters commonly found in actor names
+var accentReplacer = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a", "á", "a",
+	"ç", "c",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "í", "i",
+	"ô", "o", "ö", "o", "ó", "o",
+	"ù", "u", "û", "u", "ü", "u", "ú", "u",
+	"ÿ", "y", "œ", "oe", "æ", "ae",
+)
+
+// NormalizeActorName returns the matching key of an actor name: lowercased,
+// accent-free, with punctuation and repeated whitespace collapsed
+func NormalizeActorName(name string) string {
+	key := accentReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+	key = strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '.', ',', '\'', '’', '/':
+			return ' '
+		}
+		return r
+	}, key)
+	return strings.Join(strings.Fields(key), " ")
+}
+
+// ActorService manages the implicated actors registry
+type ActorService struct {
+	collection *mongo.Collection
+}
+
+// NewActorService creates a new actor service instance
+func NewActorService(db *DatabaseService) *ActorService {
+	collection := db.Collection("actors")
+
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "normalized_name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "normalized_keys", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "department_id", Value: 1}},
+		},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		fmt.Printf("Warning: Failed to create actor indexes: %v\n", err)
+	}
+
+	return &ActorService{
+		collection: collection,
+	}
+}
+
+// buildKeys returns the normalized matching keys of a name and its aliases
+func buildKeys(name string, aliases []string) []string {
+	keys := []string{NormalizeActorName(name)}
+	seen := map[string]bool{keys[0]: true}
+	for _, alias := range aliases {
+		key := NormalizeActorName(alias)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// parseOptionalObjectID converts an optional hex string to an ObjectID
+func parseOptionalObjectID(value *string, field string) (*primitive.ObjectID, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	id, err := primitive.ObjectIDFromHex(*value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s format", field)
+	}
+	return &id, nil
+}
+
+// Create registers a new actor
+func (s *ActorService) Create(ctx context.Context, req *models.CreateActorRequest, createdBy primitive.ObjectID) (*models.Actor, error) {
+	name := strings.Join(strings.Fields(req.Name), " ")
+	aliases := req.Aliases
+	if aliases == nil {
+		aliases = make([]string, 0)
+	}
+
+	jobPositionID, err := parseOptionalObjectID(req.JobPositionID, "job position ID")
+	if err != nil {
+		return nil, err
+	}
+	departmentID, err := parseOptionalObjectID(req.DepartmentID, "department ID")
+	if err != nil {
+		return nil, err
+	}
+
+	keys := buildKeys(name, aliases)
+	if existing, err := s.findByKeys(ctx, keys, primitive.NilObjectID); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, fmt.Errorf("actor already exists: %s", existing.Name)
+	}
+
+	now := time.Now()
+	actor := &models.Actor{
+		Name:           name,
+		NormalizedName: keys[0],
+		Aliases:        aliases,
+		NormalizedKeys: keys,
+		Description:    req.Description,
+		JobPositionID:  jobPositionID,
+		DepartmentID:   departmentID,
+		Active:         true,
+		CreatedBy:      createdBy,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	result, err := s.collection.InsertOne(ctx, actor)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("actor already exists: %s", name)
+		}
+		return nil, fmt.Errorf("failed to create actor: %w", err)
+	}
+	actor.ID = result.InsertedID.(primitive.ObjectID)
+
+	return actor, nil
+}
+
+// findByKeys returns an actor whose name or alias matches one of the keys
+func (s *ActorService) findByKeys(ctx context.Context, keys []string, excludeID primitive.ObjectID) (*models.Actor, error) {
+	filter := bson.M{"normalized_keys": bson.M{"$in": keys}}
+	if !excludeID.IsZero() {
+		filter["_id"] = bson.M{"$ne": excludeID}
+	}
+
+	var actor models.Actor
+	if err := s.collection.FindOne(ctx, filter).Decode(&actor); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find actor: %w", err)
+	}
+	return &actor, nil
+}
+
+// GetByID retrieves an actor by ID
+func (s *ActorService) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Actor, error) {
+	var actor models.Actor
+	if err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&actor); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("actor not found")
+		}
+		return nil, fmt.Errorf("failed to get actor: %w", err)
+	}
+	return &actor, nil
+}
+
+// List returns actors matching the filter
+func (s *ActorService) List(ctx context.Context, filter *models.ActorFilter) ([]models.Actor, int64, error) {
+	query := bson.M{}
+	if filter.Search != nil && *filter.Search != "" {
+		query["normalized_keys"] = primitive.Regex{Pattern: regexp.QuoteMeta(NormalizeActorName(*filter.Search)), Options: "i"}
+	}
+	if filter.DepartmentID != nil {
+		query["department_id"] = *filter.DepartmentID
+	}
+	if filter.Active != nil {
+		query["active"] = *filter.Active
+	}
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count actors: %w", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+		if filter.Page > 0 {
+			opts.SetSkip(int64((filter.Page - 1) * filter.Limit))
+		}
+	}
+
+	cursor, err := s.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find actors: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	actors := make([]models.Actor, 0)
+	if err := cursor.All(ctx, &actors); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode actors: %w", err)
+	}
+
+	return actors, total, nil
+}
+
+// Autocomplete returns active actors whose name or alias starts with the query
+func (s *ActorService) Autocomplete(ctx context.Context, query string, limit int) ([]models.ActorSuggestion, error) {
+	filter := bson.M{"active": true}
+	if key := NormalizeActorName(query); key != "" {
+		filter["normalized_keys"] = primitive.Regex{Pattern: `(^|\s)` + regexp.QuoteMeta(key)}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find actors: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var actors []models.Actor
+	if err := cursor.All(ctx, &actors); err != nil {
+		return nil, fmt.Errorf("failed to decode actors: %w", err)
+	}
+
+	suggestions := make([]models.ActorSuggestion, 0, len(actors))
+	for i := range actors {
+		suggestions = append(suggestions, actors[i].ToSuggestion())
+	}
+	return suggestions, nil
+}
+
+// Update updates an actor
+func (s *ActorService) Update(ctx context.Context, id primitive.ObjectID, req *models.UpdateActorRequest) (*models.Actor, error) {
+	actor, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	setFields := bson.M{
+		"updated_at": time.Now(),
+	}
+
+	name := actor.Name
+	aliases := actor.Aliases
+	if req.Name != nil {
+		name = strings.Join(strings.Fields(*req.Name), " ")
+		setFields["name"] = name
+	}
+	if req.Aliases != nil {
+		aliases = *req.Aliases
+		setFields["aliases"] = aliases
+	}
+	if req.Name != nil || req.Aliases != nil {
+		keys := buildKeys(name, aliases)
+		existing, err := s.findByKeys(ctx, keys, id)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, fmt.Errorf("actor already exists: %s", existing.Name)
+		}
+		setFields["normalized_name"] = keys[0]
+		setFields["normalized_keys"] = keys
+	}
+	if req.Description != nil {
+		setFields["description"] = *req.Description
+	}
+	if req.JobPositionID != nil {
+		jobPositionID, err := parseOptionalObjectID(req.JobPositionID, "job position ID")
+		if err != nil {
+			return nil, err
+		}
+		setFields["job_position_id"] = jobPositionID
+	}
+	if req.DepartmentID != nil {
+		departmentID, err := parseOptionalObjectID(req.DepartmentID, "department ID")
+		if err != nil {
+			return nil, err
+		}
+		setFields["department_id"] = departmentID
+	}
+	if req.Active != nil {
+		setFields["active"] = *req.Active
+	}
+
+	result := s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": setFields},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+
+	var updated models.Actor
+	if err := result.Decode(&updated); err != nil {
+		return nil, fmt.Errorf("failed to update actor: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// Delete removes an actor from the registry
+func (s *ActorService) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete actor: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("actor not found")
+	}
+	return nil
+}
+
+// CanonicalNames maps normalized keys to the canonical name of the matching actor
+func (s *ActorService) CanonicalNames(ctx context.Context, keys []string) (map[string]string, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{"normalized_keys": bson.M{"$in": keys}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find actors: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var actors []models.Actor
+	if err := cursor.All(ctx, &actors); err != nil {
+		return nil, fmt.Errorf("failed to decode actors: %w", err)
+	}
+
+	canonical := make(map[string]string)
+	for _, actor := range actors {
+		for _, key := range actor.NormalizedKeys {
+			canonical[key] = actor.Name
+		}
+	}
+	return canonical, nil
+}
+
+// Spellings returns the canonical names and aliases of the actors matching
+// normalized keys
+func (s *ActorService) Spellings(ctx context.Context, keys []string) ([]string, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{"normalized_keys": bson.M{"$in": keys}},
+		options.Find().SetProjection(bson.M{"name": 1, "aliases": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find actors: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var actors []models.Actor
+	if err := cursor.All(ctx, &actors); err != nil {
+		return nil, fmt.Errorf("failed to decode actors: %w", err)
+	}
+
+	spellings := make([]string, 0, len(actors))
+	for _, actor := range actors {
+		spellings = append(spellings, actor.Name)
+		spellings = append(spellings, actor.Aliases...)
+	}
+	return spellings, nil
+}
+
+// NormalizeNames maps free-text actor names to their canonical registry names.
+// Unknown names are kept (trimmed) so authors are not blocked by the registry,
+// and duplicates are removed.
+func (s *ActorService) NormalizeNames(ctx context.Context, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return names, nil
+	}
+
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, NormalizeActorName(name))
+	}
+
+	canonical, err := s.CanonicalNames(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		if keys[i] == "" {
+			continue
+		}
+		resolved := strings.Join(strings.Fields(name), " ")
+		if actorName, ok := canonical[keys[i]]; ok {
+			resolved = actorName
+		}
+		if seen[resolved] {
+			continue
+		}
+		seen[resolved] = true
+		normalized = append(normalized, resolved)
+	}
+
+	return normalized, nil
+}
+
+// ResolveKeys returns the matching keys for an actor name and/or job position:
+// every spelling of the registry actor matching the name plus the actors
+// linked to the job position. The canonical name is returned when known.
+func (s *ActorService) ResolveKeys(ctx context.Context, name string, jobPositionID *primitive.ObjectID) ([]string, string, error) {
+	keys := make([]string, 0)
+	canonical := strings.Join(strings.Fields(name), " ")
+
+	var conditions []bson.M
+	if key := NormalizeActorName(name); key != "" {
+		keys = append(keys, key)
+		conditions = append(conditions, bson.M{"normalized_keys": key})
+	}
+	if jobPositionID != nil {
+		conditions = append(conditions, bson.M{"job_position_id": *jobPositionID})
+	}
+	if len(conditions) == 0 {
+		return keys, canonical, nil
+	}
+
+	cursor, err := s.collection.Find(ctx, bson.M{"$or": conditions})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find actors: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var actors []models.Actor
+	if err := cursor.All(ctx, &actors); err != nil {
+		return nil, "", fmt.Errorf("failed to decode actors: %w", err)
+	}
+
+	nameKey := NormalizeActorName(name)
+	for _, actor := range actors {
+		keys = append(keys, actor.NormalizedKeys...)
+		if nameKey != "" && slices.Contains(actor.NormalizedKeys, nameKey) {
+			canonical = actor.Name
+		}
+	}
+
+	return keys, canonical, nil
+}
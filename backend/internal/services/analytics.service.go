@@ -0,0 +1,537 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// viewDurationBuckets are the reading time ranges used by document analytics
+var viewDurationBuckets = []models.ViewDurationBucket{
+	{Label: "< 30s", MinSeconds: 0, MaxSeconds: 30},
+	{Label: "30s - 2min", MinSeconds: 30, MaxSeconds: 120},
+	{Label: "2 - 5min", MinSeconds: 120, MaxSeconds: 300},
+	{Label: "5 - 15min", MinSeconds: 300, MaxSeconds: 900},
+	{Label: "> 15min", MinSeconds: 900},
+}
+
+// AnalyticsService handles usage analytics of published documents
+type AnalyticsService struct {
+	viewCollection       *mongo.Collection
+	searchCollection     *mongo.Collection
+	documentCollection   *mongo.Collection
+	userCollection       *mongo.Collection
+	departmentCollection *mongo.Collection
+	minGroupSize         int
+}
+
+// NewAnalyticsService creates a new analytics service instance
+func NewAnalyticsService(db *DatabaseService) *AnalyticsService {
+	viewCollection := db.Collection("document_views")
+
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "document_id", Value: 1}, {Key: "viewed_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "document_id", Value: 1}, {Key: "user_id", Value: 1}},
+		},
+	}
+	if _, err := viewCollection.Indexes().CreateMany(ctx, indexes); err != nil {
+		fmt.Printf("Warning: Failed to create document view indexes: %v\n", err)
+	}
+
+	searchCollection := db.Collection("search_events")
+	searchIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "query", Value: 1}, {Key: "source", Value: 1}},
+		},
+		{
+			// Search events are kept for 180 days
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(180 * 24 * 3600),
+		},
+	}
+	if _, err := searchCollection.Indexes().CreateMany(ctx, searchIndexes); err != nil {
+		fmt.Printf("Warning: Failed to create search event indexes: %v\n", err)
+	}
+
+	// Departments with fewer unique viewers are merged so individuals cannot be singled out
+	minGroupSize := 3
+	if v, err := strconv.Atoi(os.Getenv("ANALYTICS_MIN_GROUP_SIZE")); err == nil && v > 0 {
+		minGroupSize = v
+	}
+
+	return &AnalyticsService{
+		viewCollection:       viewCollection,
+		searchCollection:     searchCollection,
+		documentCollection:   db.Collection("documents"),
+		userCollection:       db.Collection("users"),
+		departmentCollection: db.Collection("departments"),
+		minGroupSize:         minGroupSize,
+	}
+}
+
+// RecordDocumentView stores a consultation of a document
+func (s *AnalyticsService) RecordDocumentView(ctx context.Context, document *models.Document, user *models.User, durationSeconds int) error {
+	view := &models.DocumentView{
+		DocumentID:      document.ID,
+		Version:         document.Version,
+		UserID:          user.ID,
+		DepartmentID:    user.DepartmentID,
+		DurationSeconds: durationSeconds,
+		ViewedAt:        time.Now(),
+	}
+
+	if _, err := s.viewCollection.InsertOne(ctx, view); err != nil {
+		return fmt.Errorf("failed to record document view: %w", err)
+	}
+	return nil
+}
+
+// GetDocumentAnalytics aggregates the views of a document since an optional date.
+// Individual readers are only listed when includeViewers is set.
+func (s *AnalyticsService) GetDocumentAnalytics(ctx context.Context, documentID primitive.ObjectID, from *time.Time, includeViewers bool) (*models.DocumentAnalyticsResponse, error) {
+	match := bson.M{"document_id": documentID}
+	if from != nil {
+		match["viewed_at"] = bson.M{"$gte": *from}
+	}
+
+	boundaries := bson.A{}
+	for _, bucket := range viewDurationBuckets {
+		boundaries = append(boundaries, bucket.MinSeconds)
+	}
+	boundaries = append(boundaries, 86401)
+
+	facets := bson.M{
+		"totals": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":         nil,
+				"views":       bson.M{"$sum": 1},
+				"avgDuration": bson.M{"$avg": "$duration_seconds"},
+				"users":       bson.M{"$addToSet": "$user_id"},
+			}},
+			bson.M{"$project": bson.M{
+				"views":         1,
+				"avgDuration":   1,
+				"uniqueViewers": bson.M{"$size": "$users"},
+			}},
+		},
+		"durations": bson.A{
+			bson.M{"$bucket": bson.M{
+				"groupBy":    "$duration_seconds",
+				"boundaries": boundaries,
+				"default":    "other",
+				"output":     bson.M{"count": bson.M{"$sum": 1}},
+			}},
+		},
+		"departments": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":   "$department_id",
+				"views": bson.M{"$sum": 1},
+				"users": bson.M{"$addToSet": "$user_id"},
+			}},
+			bson.M{"$project": bson.M{
+				"views":         1,
+				"uniqueViewers": bson.M{"$size": "$users"},
+			}},
+			bson.M{"$sort": bson.D{{Key: "uniqueViewers", Value: -1}, {Key: "views", Value: -1}}},
+		},
+	}
+	if includeViewers {
+		facets["viewers"] = bson.A{
+			bson.M{"$group": bson.M{
+				"_id":           "$user_id",
+				"views":         bson.M{"$sum": 1},
+				"totalDuration": bson.M{"$sum": "$duration_seconds"},
+			}},
+			bson.M{"$sort": bson.D{{Key: "views", Value: -1}, {Key: "totalDuration", Value: -1}}},
+			bson.M{"$limit": 10},
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$facet", Value: facets}},
+	}
+
+	cursor, err := s.viewCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate document views: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Totals []struct {
+			Views         int64   `bson:"views"`
+			AvgDuration   float64 `bson:"avgDuration"`
+			UniqueViewers int64   `bson:"uniqueViewers"`
+		} `bson:"totals"`
+		Durations []struct {
+			ID    interface{} `bson:"_id"`
+			Count int64       `bson:"count"`
+		} `bson:"durations"`
+		Departments []struct {
+			ID            *primitive.ObjectID `bson:"_id"`
+			Views         int64               `bson:"views"`
+			UniqueViewers int64               `bson:"uniqueViewers"`
+		} `bson:"departments"`
+		Viewers []struct {
+			ID            primitive.ObjectID `bson:"_id"`
+			Views         int64              `bson:"views"`
+			TotalDuration int64              `bson:"totalDuration"`
+		} `bson:"viewers"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode document views: %w", err)
+	}
+
+	response := &models.DocumentAnalyticsResponse{
+		DocumentID:      documentID,
+		From:            from,
+		DurationBuckets: make([]models.ViewDurationBucket, len(viewDurationBuckets)),
+		TopDepartments:  make([]models.DepartmentViews, 0),
+		MinGroupSize:    s.minGroupSize,
+	}
+	copy(response.DurationBuckets, viewDurationBuckets)
+	if len(results) == 0 {
+		return response, nil
+	}
+	result := results[0]
+
+	if len(result.Totals) > 0 {
+		response.TotalViews = result.Totals[0].Views
+		response.UniqueViewers = result.Totals[0].UniqueViewers
+		response.AverageDurationSeconds = result.Totals[0].AvgDuration
+	}
+
+	for _, d := range result.Durations {
+		lower, ok := toInt(d.ID)
+		if !ok {
+			continue
+		}
+		for i := range response.DurationBuckets {
+			if response.DurationBuckets[i].MinSeconds == lower {
+				response.DurationBuckets[i].Count = d.Count
+			}
+		}
+	}
+
+	// Small departments are merged into a single anonymous group
+	departmentIDs := make([]primitive.ObjectID, 0)
+	other := models.DepartmentViews{DepartmentName: "Other"}
+	for _, d := range result.Departments {
+		if d.ID == nil || d.UniqueViewers < int64(s.minGroupSize) {
+			other.Views += d.Views
+			other.UniqueViewers += d.UniqueViewers
+			continue
+		}
+		departmentIDs = append(departmentIDs, *d.ID)
+		response.TopDepartments = append(response.TopDepartments, models.DepartmentViews{
+			DepartmentID:  d.ID,
+			UniqueViewers: d.UniqueViewers,
+			Views:         d.Views,
+		})
+	}
+	if other.Views > 0 {
+		response.TopDepartments = append(response.TopDepartments, other)
+	}
+
+	departmentNames, err := s.namesByID(ctx, s.departmentCollection, departmentIDs, func(raw bson.M) string {
+		name, _ := raw["name"].(string)
+		return name
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range response.TopDepartments {
+		if id := response.TopDepartments[i].DepartmentID; id != nil {
+			response.TopDepartments[i].DepartmentName = departmentNames[*id]
+		}
+	}
+
+	if includeViewers {
+		userIDs := make([]primitive.ObjectID, 0, len(result.Viewers))
+		for _, v := range result.Viewers {
+			userIDs = append(userIDs, v.ID)
+		}
+		userNames, err := s.namesByID(ctx, s.userCollection, userIDs, func(raw bson.M) string {
+			first, _ := raw["first_name"].(string)
+			last, _ := raw["last_name"].(string)
+			return first + " " + last
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		response.TopViewers = make([]models.TopViewer, 0, len(result.Viewers))
+		for _, v := range result.Viewers {
+			response.TopViewers = append(response.TopViewers, models.TopViewer{
+				UserID:               v.ID,
+				Name:                 userNames[v.ID],
+				Views:                v.Views,
+				TotalDurationSeconds: v.TotalDuration,
+			})
+		}
+	}
+
+	return response, nil
+}
+
+// anonymizeQuery normalizes a search query and masks personal data such as
+// email addresses and phone or ID numbers
+func anonymizeQuery(query string) string {
+	words := strings.Fields(NormalizeActorName(query))
+	for i, word := range words {
+		digits := 0
+		for _, r := range word {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		switch {
+		case strings.Contains(word, "@"):
+			words[i] = "<email>"
+		case digits >= 6:
+			words[i] = "<number>"
+		}
+	}
+
+	normalized := strings.Join(words, " ")
+	if runes := []rune(normalized); len(runes) > 100 {
+		normalized = string(runes[:100])
+	}
+	return normalized
+}
+
+// RecordSearch stores an anonymized search event and returns its ID so the
+// client can report click-throughs
+func (s *AnalyticsService) RecordSearch(ctx context.Context, source models.SearchSource, query string, resultCount int64) (primitive.ObjectID, error) {
+	event := &models.SearchEvent{
+		ID:          primitive.NewObjectID(),
+		Query:       anonymizeQuery(query),
+		Source:      source,
+		ResultCount: resultCount,
+		CreatedAt:   time.Now(),
+	}
+	if event.Query == "" {
+		return primitive.NilObjectID, fmt.Errorf("empty search query")
+	}
+
+	if _, err := s.searchCollection.InsertOne(ctx, event); err != nil {
+		return primitive.NilObjectID, fmt.Errorf("failed to record search: %w", err)
+	}
+	return event.ID, nil
+}
+
+// RecordSearchClick records that a search result was opened
+func (s *AnalyticsService) RecordSearchClick(ctx context.Context, searchID, documentID primitive.ObjectID) error {
+	result, err := s.searchCollection.UpdateOne(ctx,
+		bson.M{"_id": searchID},
+		bson.M{
+			"$inc":      bson.M{"clicks": 1},
+			"$addToSet": bson.M{"clicked_document_ids": documentID},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record search click: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("search not found")
+	}
+	return nil
+}
+
+// GetSearchAnalytics aggregates search events since an optional date
+func (s *AnalyticsService) GetSearchAnalytics(ctx context.Context, from *time.Time, limit int) (*models.SearchAnalyticsResponse, error) {
+	match := bson.M{}
+	if from != nil {
+		match["created_at"] = bson.M{"$gte": *from}
+	}
+
+	queryStats := func(extraMatch bson.M) bson.A {
+		stages := bson.A{}
+		if extraMatch != nil {
+			stages = append(stages, bson.M{"$match": extraMatch})
+		}
+		return append(stages,
+			bson.M{"$group": bson.M{
+				"_id":            bson.M{"query": "$query", "source": "$source"},
+				"searches":       bson.M{"$sum": 1},
+				"averageResults": bson.M{"$avg": "$result_count"},
+				"clicked":        bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$clicks", 0}}, 1, 0}}},
+				"lastSearchedAt": bson.M{"$max": "$created_at"},
+			}},
+			bson.M{"$sort": bson.D{{Key: "searches", Value: -1}, {Key: "lastSearchedAt", Value: -1}}},
+			bson.M{"$limit": limit},
+		)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$facet", Value: bson.M{
+			"totals": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":        nil,
+					"searches":   bson.M{"$sum": 1},
+					"zeroResult": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$result_count", 0}}, 1, 0}}},
+					"clicked":    bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$clicks", 0}}, 1, 0}}},
+				}},
+			},
+			"topQueries":        queryStats(nil),
+			"zeroResultQueries": queryStats(bson.M{"result_count": 0}),
+			"clickedDocuments": bson.A{
+				bson.M{"$unwind": "$clicked_document_ids"},
+				bson.M{"$group": bson.M{"_id": "$clicked_document_ids", "clicks": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.M{"clicks": -1}},
+				bson.M{"$limit": limit},
+			},
+		}}},
+	}
+
+	cursor, err := s.searchCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate search events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	type queryGroup struct {
+		ID struct {
+			Query  string              `bson:"query"`
+			Source models.SearchSource `bson:"source"`
+		} `bson:"_id"`
+		Searches       int64     `bson:"searches"`
+		AverageResults float64   `bson:"averageResults"`
+		Clicked        int64     `bson:"clicked"`
+		LastSearchedAt time.Time `bson:"lastSearchedAt"`
+	}
+	var results []struct {
+		Totals []struct {
+			Searches   int64 `bson:"searches"`
+			ZeroResult int64 `bson:"zeroResult"`
+			Clicked    int64 `bson:"clicked"`
+		} `bson:"totals"`
+		TopQueries        []queryGroup `bson:"topQueries"`
+		ZeroResultQueries []queryGroup `bson:"zeroResultQueries"`
+		ClickedDocuments  []struct {
+			ID     primitive.ObjectID `bson:"_id"`
+			Clicks int64              `bson:"clicks"`
+		} `bson:"clickedDocuments"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode search events: %w", err)
+	}
+
+	response := &models.SearchAnalyticsResponse{
+		From:                from,
+		TopQueries:          make([]models.SearchQueryStats, 0),
+		ZeroResultQueries:   make([]models.SearchQueryStats, 0),
+		TopClickedDocuments: make([]models.ClickedDocumentStats, 0),
+	}
+	if len(results) == 0 {
+		return response, nil
+	}
+	result := results[0]
+
+	if len(result.Totals) > 0 && result.Totals[0].Searches > 0 {
+		response.TotalSearches = result.Totals[0].Searches
+		response.ZeroResultSearches = result.Totals[0].ZeroResult
+		response.ClickThroughRate = float64(result.Totals[0].Clicked) / float64(result.Totals[0].Searches)
+	}
+
+	toStats := func(groups []queryGroup) []models.SearchQueryStats {
+		stats := make([]models.SearchQueryStats, 0, len(groups))
+		for _, g := range groups {
+			stats = append(stats, models.SearchQueryStats{
+				Query:            g.ID.Query,
+				Source:           g.ID.Source,
+				Searches:         g.Searches,
+				AverageResults:   g.AverageResults,
+				ClickThroughRate: float64(g.Clicked) / float64(g.Searches),
+				LastSearchedAt:   g.LastSearchedAt,
+			})
+		}
+		return stats
+	}
+	response.TopQueries = toStats(result.TopQueries)
+	response.ZeroResultQueries = toStats(result.ZeroResultQueries)
+
+	documentIDs := make([]primitive.ObjectID, 0, len(result.ClickedDocuments))
+	for _, d := range result.ClickedDocuments {
+		documentIDs = append(documentIDs, d.ID)
+	}
+	documents := make(map[primitive.ObjectID]models.Document, len(documentIDs))
+	if len(documentIDs) > 0 {
+		cursor, err := s.documentCollection.Find(ctx, bson.M{"_id": bson.M{"$in": documentIDs}},
+			options.Find().SetProjection(bson.M{"reference": 1, "title": 1}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load clicked documents: %w", err)
+		}
+		var docs []models.Document
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, fmt.Errorf("failed to decode clicked documents: %w", err)
+		}
+		for _, doc := range docs {
+			documents[doc.ID] = doc
+		}
+	}
+	for _, d := range result.ClickedDocuments {
+		doc := documents[d.ID]
+		response.TopClickedDocuments = append(response.TopClickedDocuments, models.ClickedDocumentStats{
+			DocumentID: d.ID,
+			Reference:  doc.Reference,
+			Title:      doc.Title,
+			Clicks:     d.Clicks,
+		})
+	}
+
+	return response, nil
+}
+
+// namesByID loads display names for a set of IDs from a collection
+func (s *AnalyticsService) namesByID(ctx context.Context, collection *mongo.Collection, ids []primitive.ObjectID, name func(bson.M) string) (map[primitive.ObjectID]string, error) {
+	names := make(map[primitive.ObjectID]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load names: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode names: %w", err)
+	}
+	for _, doc := range docs {
+		if id, ok := doc["_id"].(primitive.ObjectID); ok {
+			names[id] = name(doc)
+		}
+	}
+	return names, nil
+}
+
+// toInt converts numeric BSON values returned by aggregations
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,534 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// reviewStages are the statuses in which a team must sign the document
+var reviewStages = []models.DocumentStatus{
+	models.DocumentStatusAuthorReview,
+	models.DocumentStatusVerifierReview,
+	models.DocumentStatusValidatorReview,
+}
+
+// ApprovalDeadlineService emails the signers of a stage a calendar invite of
+// its deadline, reminds them of pending signatures, by notification and by
+// email, and escalates to the document owner and department managers once a
+// stage is overdue
+type ApprovalDeadlineService struct {
+	documentCollection   *mongo.Collection
+	userCollection       *mongo.Collection
+	departmentCollection *mongo.Collection
+	notificationService  *NotificationService
+	emailService         *EmailService
+	calendarService      *CalendarService
+}
+
+// NewApprovalDeadlineService creates a new approval deadline service
+func NewApprovalDeadlineService(db *DatabaseService, notificationService *NotificationService, emailService *EmailService, calendarService *CalendarService) *ApprovalDeadlineService {
+	service := &ApprovalDeadlineService{
+		documentCollection:   db.Collection("documents"),
+		userCollection:       db.Collection("users"),
+		departmentCollection: db.Collection("departments"),
+		notificationService:  notificationService,
+		emailService:         emailService,
+		calendarService:      calendarService,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.documentCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "stage_deadline.due_at", Value: 1}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create approval deadline indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// defaultApprovalDeadlineDays returns the days a team has to sign when the
+// document sets no deadline for it, set by APPROVAL_DEADLINE_DAYS
+func defaultApprovalDeadlineDays() int {
+	if v, err := strconv.Atoi(os.Getenv("APPROVAL_DEADLINE_DAYS")); err == nil && v > 0 {
+		return v
+	}
+	return 7
+}
+
+// approvalReminderInterval returns the time between two reminders to the
+// pending signers, set by APPROVAL_REMINDER_INTERVAL_HOURS
+func approvalReminderInterval() time.Duration {
+	if v, err := strconv.Atoi(os.Getenv("APPROVAL_REMINDER_INTERVAL_HOURS")); err == nil && v > 0 {
+		return time.Duration(v) * time.Hour
+	}
+	return 48 * time.Hour
+}
+
+// signatureReminderEmailInterval returns the time between two reminder
+// emails to the pending signers, set by SIGNATURE_REMINDER_EMAIL_DAYS
+func signatureReminderEmailInterval() time.Duration {
+	if v, err := strconv.Atoi(os.Getenv("SIGNATURE_REMINDER_EMAIL_DAYS")); err == nil && v > 0 {
+		return time.Duration(v) * 24 * time.Hour
+	}
+	return 3 * 24 * time.Hour
+}
+
+// signatureInviteWindow bounds how long after a stage started its signers
+// are still emailed the calendar invite, so that the stages started before
+// the invites existed do not all send one at once
+const signatureInviteWindow = 24 * time.Hour
+
+// reminderDue tells whether the interval passed since the last reminder, or
+// since the stage started when none was sent
+func reminderDue(startedAt time.Time, lastAt *time.Time, now time.Time, interval time.Duration) bool {
+	if lastAt != nil {
+		startedAt = *lastAt
+	}
+	return now.Sub(startedAt) >= interval
+}
+
+// NewStageDeadline returns the deadline of a document entering the given
+// status, or nil when the status is not a signature stage
+func NewStageDeadline(document *models.Document, stage models.DocumentStatus, from time.Time) *models.StageDeadline {
+	days := 0
+	switch stage {
+	case models.DocumentStatusAuthorReview:
+		if document.Deadlines != nil {
+			days = document.Deadlines.AuthorDays
+		}
+	case models.DocumentStatusVerifierReview:
+		if document.Deadlines != nil {
+			days = document.Deadlines.VerifierDays
+		}
+	case models.DocumentStatusValidatorReview:
+		if document.Deadlines != nil {
+			days = document.Deadlines.ValidatorDays
+		}
+	default:
+		return nil
+	}
+	if days <= 0 {
+		days = defaultApprovalDeadlineDays()
+	}
+
+	return &models.StageDeadline{
+		Stage:     stage,
+		StartedAt: from,
+		DueAt:     from.AddDate(0, 0, days),
+	}
+}
+
+// stageSigners returns the contributors who must sign in the given stage
+func stageSigners(document *models.Document, stage models.DocumentStatus) ([]models.Contributor, string) {
+	switch stage {
+	case models.DocumentStatusAuthorReview:
+		return document.Contributors.Authors, "Authors"
+	case models.DocumentStatusVerifierReview:
+		return document.Contributors.Verifiers, "Verifiers"
+	case models.DocumentStatusValidatorReview:
+		return document.Contributors.Validators, "Validators"
+	}
+	return nil, ""
+}
+
+// Start checks the signature deadlines every hour until the context is cancelled
+func (s *ApprovalDeadlineService) Start(ctx context.Context) {
+	check := func() {
+		checkCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		reminded, escalated, err := s.RunCheck(checkCtx)
+		if err != nil {
+			fmt.Printf("Warning: Failed to check approval deadlines: %v\n", err)
+			return
+		}
+		if reminded > 0 || escalated > 0 {
+			fmt.Printf("⏰ Approval deadlines: %d reminder(s) sent, %d escalation(s)\n", reminded, escalated)
+		}
+	}
+
+	go func() {
+		check()
+
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				check()
+			}
+		}
+	}()
+	fmt.Printf("⏰ Approval deadline worker started (default deadline: %d days, reminders every %s, emails every %s)\n", defaultApprovalDeadlineDays(), approvalReminderInterval(), signatureReminderEmailInterval())
+}
+
+// RunCheck emails the signers of the stages that just started their calendar
+// invite, reminds the pending signers of documents under review, unless
+// their owner muted the reminders, and escalates the overdue stages. It
+// returns the number of reminders and escalations sent.
+func (s *ApprovalDeadlineService) RunCheck(ctx context.Context) (int, int, error) {
+	cursor, err := s.documentCollection.Find(ctx, models.NotDeleted(bson.M{
+		"status":                      bson.M{"$in": reviewStages},
+		"stage_deadline.escalated_at": bson.M{"$exists": false},
+	}))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find documents under review: %w", err)
+	}
+	var documents []*models.Document
+	if err := cursor.All(ctx, &documents); err != nil {
+		return 0, 0, fmt.Errorf("failed to decode documents under review: %w", err)
+	}
+
+	now := time.Now()
+	reminded, escalated := 0, 0
+	for _, document := range documents {
+		deadline := document.StageDeadline
+
+		// Documents that entered their stage before deadlines existed start one now
+		if deadline == nil || deadline.Stage != document.Status {
+			if _, err := s.documentCollection.UpdateOne(ctx, bson.M{"_id": document.ID, "status": document.Status}, bson.M{
+				"$set": bson.M{"stage_deadline": NewStageDeadline(document, document.Status, now)},
+			}); err != nil {
+				return reminded, escalated, fmt.Errorf("failed to start deadline of %s: %w", document.Reference, err)
+			}
+			continue
+		}
+
+		pending := pendingSigners(document)
+		if len(pending) == 0 {
+			continue
+		}
+
+		if !now.Before(deadline.DueAt) {
+			if ok, err := s.markDeadline(ctx, document, bson.M{"stage_deadline.escalated_at": now}); err != nil {
+				return reminded, escalated, err
+			} else if ok {
+				s.escalate(ctx, document, pending)
+				escalated++
+			}
+			continue
+		}
+
+		if s.emailService != nil && deadline.InvitedAt == nil && now.Sub(deadline.StartedAt) < signatureInviteWindow {
+			if ok, err := s.markDeadline(ctx, document, bson.M{"stage_deadline.invited_at": now}); err != nil {
+				return reminded, escalated, err
+			} else if ok {
+				s.emailInvites(ctx, document, pending)
+			}
+		}
+
+		if document.SignatureRemindersMuted {
+			continue
+		}
+
+		if reminderDue(deadline.StartedAt, deadline.LastReminderAt, now, approvalReminderInterval()) {
+			if ok, err := s.markDeadline(ctx, document, bson.M{
+				"stage_deadline.last_reminder_at": now,
+				"stage_deadline.reminders_sent":   deadline.RemindersSent + 1,
+			}); err != nil {
+				return reminded, escalated, err
+			} else if ok {
+				s.remind(ctx, document, pending)
+				reminded++
+			}
+		}
+
+		if s.emailService != nil && reminderDue(deadline.StartedAt, deadline.LastEmailAt, now, signatureReminderEmailInterval()) {
+			if ok, err := s.markDeadline(ctx, document, bson.M{"stage_deadline.last_email_at": now}); err != nil {
+				return reminded, escalated, err
+			} else if ok {
+				s.emailReminders(ctx, document, pending, false)
+			}
+		}
+	}
+
+	return reminded, escalated, nil
+}
+
+// markDeadline updates the deadline of a document still in the same stage.
+// It returns false when the stage changed in the meantime.
+func (s *ApprovalDeadlineService) markDeadline(ctx context.Context, document *models.Document, set bson.M) (bool, error) {
+	result, err := s.documentCollection.UpdateOne(ctx, bson.M{
+		"_id":                       document.ID,
+		"status":                    document.Status,
+		"stage_deadline.started_at": document.StageDeadline.StartedAt,
+	}, bson.M{"$set": set})
+	if err != nil {
+		return false, fmt.Errorf("failed to update deadline of %s: %w", document.Reference, err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// pendingSigners returns the contributors of the current stage who have not signed yet
+func pendingSigners(document *models.Document) []models.Contributor {
+	signers, _ := stageSigners(document, document.Status)
+	pending := make([]models.Contributor, 0, len(signers))
+	for _, contributor := range signers {
+		if contributor.Status != models.SignatureStatusSigned {
+			pending = append(pending, contributor)
+		}
+	}
+	return pending
+}
+
+// remind notifies the pending signers that the deadline is approaching
+func (s *ApprovalDeadlineService) remind(ctx context.Context, document *models.Document, pending []models.Contributor) {
+	_, roleTitle := stageSigners(document, document.Status)
+	userIDs := make([]string, 0, len(pending))
+	for _, contributor := range pending {
+		userIDs = append(userIDs, contributor.UserID.Hex())
+	}
+
+	s.send(ctx, &models.SendNotificationRequest{
+		UserIDs:  userIDs,
+		Title:    "Signature reminder",
+		Body:     fmt.Sprintf("Document '%s' (%s) is waiting for your signature before %s.", document.Title, document.Reference, document.StageDeadline.DueAt.Format("2006-01-02")),
+		Category: "document",
+		Data: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"reference":  document.Reference,
+			"title":      document.Title,
+			"action":     "signature_reminder",
+			"role":       roleTitle,
+			"dueAt":      document.StageDeadline.DueAt,
+		},
+	})
+}
+
+// emailedSigners returns the active pending signers of a document who get
+// their emails right away. The users receiving their notifications as a
+// digest are left out, the notification being in their next digest.
+func (s *ApprovalDeadlineService) emailedSigners(ctx context.Context, document *models.Document, pending []models.Contributor) []models.User {
+	userIDs := make([]primitive.ObjectID, 0, len(pending))
+	for _, contributor := range pending {
+		if contributor.Status == models.SignatureStatusPending {
+			userIDs = append(userIDs, contributor.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	cursor, err := s.userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}, "active": true})
+	if err != nil {
+		fmt.Printf("⚠️  Failed to find pending signers of %s: %v\n", document.Reference, err)
+		return nil
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		fmt.Printf("⚠️  Failed to decode pending signers of %s: %v\n", document.Reference, err)
+		return nil
+	}
+
+	emailed := make([]models.User, 0, len(users))
+	for _, user := range users {
+		if s.notificationService != nil && s.notificationService.UsesEmailDigest(ctx, user.ID) {
+			continue
+		}
+		emailed = append(emailed, user)
+	}
+	return emailed
+}
+
+// emailInvites emails the pending signers that the document entered a stage
+// waiting for their signature, with the calendar invite of its deadline
+func (s *ApprovalDeadlineService) emailInvites(ctx context.Context, document *models.Document, pending []models.Contributor) {
+	var invite []byte
+	if s.calendarService != nil {
+		invite = s.calendarService.DeadlineInvite(document)
+	}
+	for _, user := range s.emailedSigners(ctx, document, pending) {
+		name := fmt.Sprintf("%s %s", user.FirstName, user.LastName)
+		if err := s.emailService.SendSignatureRequestEmail(user.Email, name, document.Title, document.Reference, document.ID.Hex(), &document.StageDeadline.DueAt, invite, user.Language); err != nil {
+			fmt.Printf("⚠️  Failed to email signature request of %s to %s: %v\n", document.Reference, user.Email, err)
+		}
+	}
+}
+
+// emailReminders emails the pending signers that the document is waiting for
+// their signature. Manual reminders are not dropped as duplicates of the
+// scheduled ones.
+func (s *ApprovalDeadlineService) emailReminders(ctx context.Context, document *models.Document, pending []models.Contributor, manual bool) {
+	var dueAt *time.Time
+	if document.StageDeadline != nil {
+		dueAt = &document.StageDeadline.DueAt
+	}
+	for _, user := range s.emailedSigners(ctx, document, pending) {
+		name := fmt.Sprintf("%s %s", user.FirstName, user.LastName)
+		if err := s.emailService.SendSignatureReminderEmail(user.Email, name, document.Title, document.Reference, document.ID.Hex(), dueAt, manual, user.Language); err != nil {
+			fmt.Printf("⚠️  Failed to email signature reminder of %s to %s: %v\n", document.Reference, user.Email, err)
+		}
+	}
+}
+
+// SendRemindersNow reminds the pending signers of a document under review
+// right away, by notification and email, even when its scheduled reminders
+// are muted. It returns the number of signers reminded.
+func (s *ApprovalDeadlineService) SendRemindersNow(ctx context.Context, documentID primitive.ObjectID) (int, error) {
+	var document models.Document
+	err := s.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, errors.New("document not found")
+		}
+		return 0, fmt.Errorf("failed to get document: %w", err)
+	}
+	if _, role := stageSigners(&document, document.Status); role == "" {
+		return 0, errors.New("document is not waiting for signatures")
+	}
+	pending := pendingSigners(&document)
+	if len(pending) == 0 {
+		return 0, errors.New("document has no pending signatures")
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"stage_deadline.last_reminder_at": now,
+			"stage_deadline.last_email_at":    now,
+		},
+		"$inc": bson.M{"stage_deadline.reminders_sent": 1},
+	}
+	// Documents that entered their stage before deadlines existed start one now
+	if document.StageDeadline == nil || document.StageDeadline.Stage != document.Status {
+		document.StageDeadline = NewStageDeadline(&document, document.Status, now)
+		document.StageDeadline.LastReminderAt = &now
+		document.StageDeadline.LastEmailAt = &now
+		document.StageDeadline.RemindersSent = 1
+		update = bson.M{"$set": bson.M{"stage_deadline": document.StageDeadline}}
+	}
+	if _, err := s.documentCollection.UpdateOne(ctx, bson.M{"_id": document.ID, "status": document.Status}, update); err != nil {
+		return 0, fmt.Errorf("failed to update deadline of %s: %w", document.Reference, err)
+	}
+
+	s.remind(ctx, &document, pending)
+	if s.emailService != nil {
+		s.emailReminders(ctx, &document, pending, true)
+	}
+	return len(pending), nil
+}
+
+// SetRemindersMuted stops or resumes the scheduled signature reminders of a
+// document. Escalations of overdue stages are still sent.
+func (s *ApprovalDeadlineService) SetRemindersMuted(ctx context.Context, documentID primitive.ObjectID, muted bool) (*models.Document, error) {
+	var document models.Document
+	err := s.documentCollection.FindOneAndUpdate(ctx,
+		models.NotDeleted(bson.M{"_id": documentID}),
+		bson.M{"$set": bson.M{"signature_reminders_muted": muted}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&document)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("document not found")
+		}
+		return nil, fmt.Errorf("failed to update signature reminders: %w", err)
+	}
+	return &document, nil
+}
+
+// escalate alerts the pending signers, the document owner and the managers
+// of the pending signers' departments that a stage is overdue
+func (s *ApprovalDeadlineService) escalate(ctx context.Context, document *models.Document, pending []models.Contributor) {
+	_, roleTitle := stageSigners(document, document.Status)
+
+	signerIDs := make([]string, 0, len(pending))
+	pendingIDs := make([]primitive.ObjectID, 0, len(pending))
+	names := make([]string, 0, len(pending))
+	for _, contributor := range pending {
+		signerIDs = append(signerIDs, contributor.UserID.Hex())
+		pendingIDs = append(pendingIDs, contributor.UserID)
+		names = append(names, contributor.Name)
+	}
+
+	data := map[string]interface{}{
+		"documentId":     document.ID.Hex(),
+		"reference":      document.Reference,
+		"title":          document.Title,
+		"action":         "signature_overdue",
+		"role":           roleTitle,
+		"dueAt":          document.StageDeadline.DueAt,
+		"pendingSigners": names,
+	}
+
+	s.send(ctx, &models.SendNotificationRequest{
+		UserIDs:  signerIDs,
+		Title:    "Signature overdue",
+		Body:     fmt.Sprintf("The signature deadline of document '%s' (%s) has passed. Please sign it as soon as possible.", document.Title, document.Reference),
+		Category: "document",
+		Priority: models.NotificationPriorityHigh,
+		Data:     data,
+	})
+
+	recipients := map[string]bool{document.CreatedBy.Hex(): true}
+	for _, managerID := range s.departmentManagers(ctx, pendingIDs) {
+		recipients[managerID.Hex()] = true
+	}
+	for _, id := range signerIDs {
+		delete(recipients, id)
+	}
+	if len(recipients) == 0 {
+		return
+	}
+	escalationIDs := make([]string, 0, len(recipients))
+	for id := range recipients {
+		escalationIDs = append(escalationIDs, id)
+	}
+
+	s.send(ctx, &models.SendNotificationRequest{
+		UserIDs:  escalationIDs,
+		Title:    "Approval deadline exceeded",
+		Body:     fmt.Sprintf("%d %s of document '%s' (%s) did not sign before %s.", len(pending), roleTitle, document.Title, document.Reference, document.StageDeadline.DueAt.Format("2006-01-02")),
+		Category: "document",
+		Priority: models.NotificationPriorityHigh,
+		Data:     data,
+	})
+}
+
+// departmentManagers returns the managers of the departments of the given users
+func (s *ApprovalDeadlineService) departmentManagers(ctx context.Context, userIDs []primitive.ObjectID) []primitive.ObjectID {
+	departmentIDs, err := s.userCollection.Distinct(ctx, "department_id", bson.M{
+		"_id":           bson.M{"$in": userIDs},
+		"department_id": bson.M{"$ne": nil},
+	})
+	if err != nil || len(departmentIDs) == 0 {
+		return nil
+	}
+
+	managerIDs, err := s.departmentCollection.Distinct(ctx, "manager_id", bson.M{
+		"_id":        bson.M{"$in": departmentIDs},
+		"manager_id": bson.M{"$ne": nil},
+	})
+	if err != nil {
+		return nil
+	}
+
+	managers := make([]primitive.ObjectID, 0, len(managerIDs))
+	for _, id := range managerIDs {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			managers = append(managers, oid)
+		}
+	}
+	return managers
+}
+
+func (s *ApprovalDeadlineService) send(ctx context.Context, req *models.SendNotificationRequest) {
+	if s.notificationService == nil || len(req.UserIDs) == 0 {
+		return
+	}
+	if _, err := s.notificationService.SendNotification(ctx, req, primitive.NilObjectID); err != nil {
+		fmt.Printf("⚠️  Failed to send approval deadline notification: %v\n", err)
+	}
+}
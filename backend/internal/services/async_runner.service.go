@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// asyncTaskStats are the counters of the tasks of a name
+type asyncTaskStats struct {
+	inFlight  int64
+	queued    int64
+	completed int64
+	failed    int64
+	panicked  int64
+	dropped   int64
+}
+
+// AsyncRunner runs the background work of the handlers (emails, push
+// notifications, ...) once the response is sent. Tasks get their own
+// context with a timeout, panics are recovered and logged, and at most
+// ASYNC_MAX_CONCURRENCY tasks run at once, up to ASYNC_MAX_QUEUED more
+// waiting for a slot; beyond that tasks are dropped.
+type AsyncRunner struct {
+	slots     chan struct{}
+	maxQueued int64
+	timeout   time.Duration
+
+	mu    sync.Mutex
+	tasks map[string]*asyncTaskStats
+}
+
+// NewAsyncRunner creates a new async runner instance
+func NewAsyncRunner() *AsyncRunner {
+	concurrency := 32
+	if v, err := strconv.Atoi(os.Getenv("ASYNC_MAX_CONCURRENCY")); err == nil && v > 0 {
+		concurrency = v
+	}
+	maxQueued := int64(1000)
+	if v, err := strconv.ParseInt(os.Getenv("ASYNC_MAX_QUEUED"), 10, 64); err == nil && v >= 0 {
+		maxQueued = v
+	}
+	timeout := 60 * time.Second
+	if v, err := strconv.Atoi(os.Getenv("ASYNC_TASK_TIMEOUT_SECONDS")); err == nil && v > 0 {
+		timeout = time.Duration(v) * time.Second
+	}
+
+	return &AsyncRunner{
+		slots:     make(chan struct{}, concurrency),
+		maxQueued: maxQueued,
+		timeout:   timeout,
+		tasks:     make(map[string]*asyncTaskStats),
+	}
+}
+
+// Go runs task in the background under name, which groups the task in the
+// statistics. The context given to the task is not tied to the request.
+func (r *AsyncRunner) Go(name string, task func(ctx context.Context) error) {
+	r.mu.Lock()
+	stats := r.statsFor(name)
+	if r.queuedLocked() >= r.maxQueued && len(r.slots) == cap(r.slots) {
+		stats.dropped++
+		r.mu.Unlock()
+		fmt.Printf("⚠️ [ASYNC] Dropped task %s: too many tasks queued\n", name)
+		return
+	}
+	stats.queued++
+	r.mu.Unlock()
+
+	go func() {
+		r.slots <- struct{}{}
+		defer func() { <-r.slots }()
+
+		r.mu.Lock()
+		stats.queued--
+		stats.inFlight++
+		r.mu.Unlock()
+
+		panicked, err := r.run(name, task)
+
+		r.mu.Lock()
+		stats.inFlight--
+		switch {
+		case panicked:
+			stats.panicked++
+		case err != nil:
+			stats.failed++
+		default:
+			stats.completed++
+		}
+		r.mu.Unlock()
+
+		if err != nil && !panicked {
+			fmt.Printf("⚠️ [ASYNC] Task %s failed: %v\n", name, err)
+		}
+	}()
+}
+
+// run runs a task, recovering its panic
+func (r *AsyncRunner) run(name string, task func(ctx context.Context) error) (panicked bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			fmt.Printf("❌ [ASYNC] Task %s panicked: %v\n%s\n", name, recovered, debug.Stack())
+			panicked = true
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return false, task(ctx)
+}
+
+// Stats returns the task counters, overall and by task name
+func (r *AsyncRunner) Stats() *models.AsyncRunnerStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &models.AsyncRunnerStats{
+		MaxConcurrency: cap(r.slots),
+		MaxQueued:      r.maxQueued,
+		TimeoutSeconds: int(r.timeout / time.Second),
+		Tasks:          make([]models.AsyncTaskStats, 0, len(r.tasks)),
+	}
+	for name, stats := range r.tasks {
+		report.InFlight += stats.inFlight
+		report.Queued += stats.queued
+		report.Tasks = append(report.Tasks, models.AsyncTaskStats{
+			Name:      name,
+			InFlight:  stats.inFlight,
+			Queued:    stats.queued,
+			Completed: stats.completed,
+			Failed:    stats.failed,
+			Panicked:  stats.panicked,
+			Dropped:   stats.dropped,
+		})
+	}
+	sort.Slice(report.Tasks, func(i, j int) bool { return report.Tasks[i].Name < report.Tasks[j].Name })
+
+	return report
+}
+
+// statsFor returns the counters of the tasks of a name, r.mu must be held
+func (r *AsyncRunner) statsFor(name string) *asyncTaskStats {
+	stats, ok := r.tasks[name]
+	if !ok {
+		stats = &asyncTaskStats{}
+		r.tasks[name] = stats
+	}
+	return stats
+}
+
+// queuedLocked returns the number of tasks waiting for a slot, r.mu must be held
+func (r *AsyncRunner) queuedLocked() int64 {
+	var queued int64
+	for _, stats := range r.tasks {
+		queued += stats.queued
+	}
+	return queued
+}
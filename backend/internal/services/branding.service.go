@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// brandingTTL bounds how long the branding is cached, so changes made
+// through another instance are picked up quickly
+const brandingTTL = time.Minute
+
+// BrandingService handles the organization branding used by emails, PDFs and HTML views
+type BrandingService struct {
+	collection   *mongo.Collection
+	minioService *MinIOService
+
+	mu       sync.RWMutex
+	current  *models.Branding
+	loadedAt time.Time
+	onChange []func() // Invalidation hooks of the renderers using the branding
+}
+
+// NewBrandingService creates a new branding service instance
+func NewBrandingService(db *DatabaseService, minioService *MinIOService) *BrandingService {
+	return &BrandingService{
+		collection:   db.Collection("settings"),
+		minioService: minioService,
+	}
+}
+
+// OnChange registers a hook called after the branding is updated through this instance
+func (s *BrandingService) OnChange(hook func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = append(s.onChange, hook)
+}
+
+// Get returns the configured branding, or the default one when none was saved.
+// It never fails so that emails and PDFs can always be rendered.
+func (s *BrandingService) Get(ctx context.Context) *models.Branding {
+	s.mu.RLock()
+	if s.current != nil && time.Since(s.loadedAt) < brandingTTL {
+		current := s.current
+		s.mu.RUnlock()
+		return current
+	}
+	s.mu.RUnlock()
+
+	branding := models.DefaultBranding()
+	err := s.collection.FindOne(ctx, bson.M{"_id": models.BrandingSettingsID}).Decode(branding)
+	if err != nil && err != mongo.ErrNoDocuments {
+		fmt.Printf("Warning: Failed to load branding, using defaults: %v\n", err)
+		return models.DefaultBranding()
+	}
+
+	s.mu.Lock()
+	s.current = branding
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	return branding
+}
+
+// Update saves the given branding fields
+func (s *BrandingService) Update(ctx context.Context, req *models.UpdateBrandingRequest, updatedBy primitive.ObjectID) (*models.Branding, error) {
+	set := bson.M{
+		"updated_by": updatedBy,
+		"updated_at": time.Now(),
+	}
+	if req.AppName != nil {
+		set["app_name"] = *req.AppName
+	}
+	if req.CompanyName != nil {
+		set["company_name"] = *req.CompanyName
+	}
+	if req.HeaderLines != nil {
+		set["header_lines"] = *req.HeaderLines
+	}
+	if req.Tagline != nil {
+		set["tagline"] = *req.Tagline
+	}
+	if req.AddressLines != nil {
+		set["address_lines"] = *req.AddressLines
+	}
+	if req.Phone != nil {
+		set["phone"] = *req.Phone
+	}
+	if req.ContactEmail != nil {
+		set["contact_email"] = *req.ContactEmail
+	}
+	if req.Website != nil {
+		set["website"] = *req.Website
+	}
+	if req.SupportEmail != nil {
+		set["support_email"] = *req.SupportEmail
+	}
+	if req.FooterText != nil {
+		set["footer_text"] = *req.FooterText
+	}
+	if req.PrimaryColor != nil {
+		set["primary_color"] = *req.PrimaryColor
+	}
+	if req.SecondaryColor != nil {
+		set["secondary_color"] = *req.SecondaryColor
+	}
+	if req.LogoVariants != nil {
+		set["logo_variants"] = *req.LogoVariants
+	}
+
+	return s.save(ctx, set)
+}
+
+// UploadLogo stores a new logo in MinIO and replaces the previous one
+func (s *BrandingService) UploadLogo(ctx context.Context, reader io.Reader, size int64, contentType, filename string, updatedBy primitive.ObjectID) (*models.Branding, error) {
+	previous := s.Get(ctx).LogoURL
+
+	// A new key on every upload so cached logos are not served after a change
+	objectKey := fmt.Sprintf("branding/logo_%d%s", time.Now().Unix(), filepath.Ext(filename))
+	logoURL, err := s.minioService.UploadFile(ctx, objectKey, reader, size, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	branding, err := s.save(ctx, bson.M{
+		"logo_url":   logoURL,
+		"updated_by": updatedBy,
+		"updated_at": time.Now(),
+	})
+	if err != nil {
+		s.minioService.DeleteFile(ctx, logoURL)
+		return nil, err
+	}
+
+	if err := s.minioService.DeleteFile(ctx, previous); err != nil {
+		fmt.Printf("Warning: Failed to delete previous logo: %v\n", err)
+	}
+
+	return branding, nil
+}
+
+// DeleteLogo removes the logo, documents and emails are rendered without one
+func (s *BrandingService) DeleteLogo(ctx context.Context, updatedBy primitive.ObjectID) (*models.Branding, error) {
+	previous := s.Get(ctx).LogoURL
+
+	branding, err := s.save(ctx, bson.M{
+		"logo_url":   "",
+		"updated_by": updatedBy,
+		"updated_at": time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.minioService.DeleteFile(ctx, previous); err != nil {
+		fmt.Printf("Warning: Failed to delete previous logo: %v\n", err)
+	}
+
+	return branding, nil
+}
+
+// save applies the update, the fields never saved keep their default value
+func (s *BrandingService) save(ctx context.Context, set bson.M) (*models.Branding, error) {
+	defaults := models.DefaultBranding()
+	setOnInsert := bson.M{}
+	for field, value := range map[string]interface{}{
+		"app_name":        defaults.AppName,
+		"company_name":    defaults.CompanyName,
+		"header_lines":    defaults.HeaderLines,
+		"tagline":         defaults.Tagline,
+		"address_lines":   defaults.AddressLines,
+		"phone":           defaults.Phone,
+		"contact_email":   defaults.ContactEmail,
+		"website":         defaults.Website,
+		"support_email":   defaults.SupportEmail,
+		"footer_text":     defaults.FooterText,
+		"logo_url":        defaults.LogoURL,
+		"primary_color":   defaults.PrimaryColor,
+		"secondary_color": defaults.SecondaryColor,
+	} {
+		if _, ok := set[field]; !ok {
+			setOnInsert[field] = value
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(setOnInsert) > 0 {
+		update["$setOnInsert"] = setOnInsert
+	}
+
+	branding := &models.Branding{}
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": models.BrandingSettingsID},
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(branding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update branding: %w", err)
+	}
+
+	s.mu.Lock()
+	s.current = branding
+	s.loadedAt = time.Now()
+	hooks := s.onChange
+	s.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	return branding, nil
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// calendarEventDuration is the length of the event of a signature deadline
+const calendarEventDuration = 30 * time.Minute
+
+// CalendarService builds the iCalendar (ICS) events of the signature
+// deadlines, attached to the signature request emails and served as a feed
+// of the pending deadlines of a user that calendar apps can subscribe to
+type CalendarService struct {
+	documentCollection *mongo.Collection
+	userCollection     *mongo.Collection
+	appURL             string
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(db *DatabaseService) *CalendarService {
+	service := &CalendarService{
+		documentCollection: db.Collection("documents"),
+		userCollection:     db.Collection("users"),
+		appURL:             appURL(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.userCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "calendar_token_hash", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create calendar token index: %v\n", err)
+	}
+
+	return service
+}
+
+// PendingDeadlines returns the documents under review waiting for the
+// signature of a user in their current stage, by deadline
+func (s *CalendarService) PendingDeadlines(ctx context.Context, userID primitive.ObjectID) ([]*models.Document, error) {
+	pending := bson.M{"$elemMatch": bson.M{"user_id": userID, "status": models.SignatureStatusPending}}
+	filter := models.NotDeleted(bson.M{
+		"stage_deadline": bson.M{"$ne": nil},
+		"$or": []bson.M{
+			{"status": models.DocumentStatusAuthorReview, "contributors.authors": pending},
+			{"status": models.DocumentStatusVerifierReview, "contributors.verifiers": pending},
+			{"status": models.DocumentStatusValidatorReview, "contributors.validators": pending},
+		},
+	})
+
+	cursor, err := s.documentCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "stage_deadline.due_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending signatures: %w", err)
+	}
+	var documents []*models.Document
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode pending signatures: %w", err)
+	}
+
+	// Deadlines of a previous stage are left out until the worker starts the new one
+	deadlines := make([]*models.Document, 0, len(documents))
+	for _, document := range documents {
+		if document.StageDeadline != nil && document.StageDeadline.Stage == document.Status {
+			deadlines = append(deadlines, document)
+		}
+	}
+	return deadlines, nil
+}
+
+// Feed returns the calendar of the pending signature deadlines of a user
+func (s *CalendarService) Feed(ctx context.Context, userID primitive.ObjectID) ([]byte, error) {
+	documents, err := s.PendingDeadlines(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildCalendar(documents, time.Now()), nil
+}
+
+// DeadlineInvite returns the calendar invite of the current stage deadline
+// of a document, nil when it has none
+func (s *CalendarService) DeadlineInvite(document *models.Document) []byte {
+	if document.StageDeadline == nil {
+		return nil
+	}
+	return s.buildCalendar([]*models.Document{document}, time.Now())
+}
+
+// IssueFeedToken creates the secret token giving access to the calendar
+// feed of a user without signing in, replacing the previous one. Only its
+// hash is stored.
+func (s *CalendarService) IssueFeedToken(ctx context.Context, userID primitive.ObjectID) (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	result, err := s.userCollection.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{
+		"$set": bson.M{"calendar_token_hash": hashCalendarToken(token), "updated_at": time.Now()},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to save calendar token: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return "", errors.New("user not found")
+	}
+	return token, nil
+}
+
+// UserByFeedToken returns the active user owning a calendar feed token
+func (s *CalendarService) UserByFeedToken(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, errors.New("invalid calendar token")
+	}
+	var user models.User
+	err := s.userCollection.FindOne(ctx, bson.M{"calendar_token_hash": hashCalendarToken(token), "active": true}).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("invalid calendar token")
+		}
+		return nil, fmt.Errorf("failed to find calendar token: %w", err)
+	}
+	return &user, nil
+}
+
+// hashCalendarToken returns the hash under which a feed token is stored
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// buildCalendar returns an iCalendar with an event per stage deadline. The
+// events keep the same UID for a stage so that calendar apps update them
+// instead of adding duplicates, and remind the signer the day before.
+func (s *CalendarService) buildCalendar(documents []*models.Document, now time.Time) []byte {
+	var b strings.Builder
+	line := func(content string) {
+		b.WriteString(foldICSLine(content))
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Process Manager//Signature deadlines//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICSText("Signature deadlines"))
+	for _, document := range documents {
+		deadline := document.StageDeadline
+		if deadline == nil {
+			continue
+		}
+		documentURL := fmt.Sprintf("%s/documents/%s", s.appURL, document.ID.Hex())
+		summary := fmt.Sprintf("Sign %s (%s)", document.Title, document.Reference)
+		_, role := stageSigners(document, deadline.Stage)
+
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:%s-%s@process-manager", document.ID.Hex(), deadline.Stage))
+		line("DTSTAMP:" + formatICSTime(now))
+		line("DTSTART:" + formatICSTime(deadline.DueAt))
+		line("DTEND:" + formatICSTime(deadline.DueAt.Add(calendarEventDuration)))
+		line("SUMMARY:" + escapeICSText(summary))
+		line("DESCRIPTION:" + escapeICSText(fmt.Sprintf("Signature deadline of the %s of document '%s' (%s).\n%s", strings.ToLower(role), document.Title, document.Reference, documentURL)))
+		line("URL:" + documentURL)
+		line("STATUS:CONFIRMED")
+		line("TRANSP:TRANSPARENT")
+		line("BEGIN:VALARM")
+		line("ACTION:DISPLAY")
+		line("DESCRIPTION:" + escapeICSText(summary))
+		line("TRIGGER:-P1D")
+		line("END:VALARM")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	return []byte(b.String())
+}
+
+// formatICSTime formats a time in UTC as an iCalendar date-time
+func formatICSTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeICSText escapes an iCalendar text value
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldICSLine ends a content line with CRLF, folding it into lines of at
+// most 75 octets without splitting a UTF-8 character
+func foldICSLine(content string) string {
+	var b strings.Builder
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+		// The leading space of the continuation lines counts
+		limit = 74
+	}
+	b.WriteString(content + "\r\n")
+	return b.String()
+}
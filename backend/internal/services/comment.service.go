@@ -0,0 +1,597 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CommentService handles document review comments
+type CommentService struct {
+	collection           *mongo.Collection
+	documentCollection   *mongo.Collection
+	invitationCollection *mongo.Collection
+	userCollection       *mongo.Collection
+	resolutionGate       bool
+}
+
+// NewCommentService creates a new comment service instance
+func NewCommentService(db *DatabaseService) *CommentService {
+	collection := db.Collection("comments")
+
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "document_id", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "document_id", Value: 1}, {Key: "blocking", Value: 1}, {Key: "status", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "parent_id", Value: 1}},
+		},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		fmt.Printf("Warning: Failed to create comment indexes: %v\n", err)
+	}
+
+	return &CommentService{
+		collection:           collection,
+		documentCollection:   db.Collection("documents"),
+		invitationCollection: db.Collection("invitations"),
+		userCollection:       db.Collection("users"),
+		// Optional gate: reviewers cannot sign while blocking comments are open
+		resolutionGate: os.Getenv("COMMENT_RESOLUTION_GATE") == "true",
+	}
+}
+
+// IsResolutionGateEnabled reports whether blocking comments prevent signing
+func (s *CommentService) IsResolutionGateEnabled() bool {
+	return s.resolutionGate
+}
+
+// parseMentions converts mentioned user IDs, ignoring duplicates
+func parseMentions(ids []string) ([]primitive.ObjectID, error) {
+	mentions := make([]primitive.ObjectID, 0, len(ids))
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for _, idStr := range ids {
+		id, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mentioned user ID: %s", idStr)
+		}
+		if !seen[id] {
+			seen[id] = true
+			mentions = append(mentions, id)
+		}
+	}
+	return mentions, nil
+}
+
+// Create adds a comment or a reply to a document
+func (s *CommentService) Create(ctx context.Context, documentID primitive.ObjectID, req *models.CreateCommentRequest, author *models.User) (*models.Comment, error) {
+	mentions, err := parseMentions(req.Mentions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMentionable(ctx, documentID, mentions); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comment := &models.Comment{
+		DocumentID: documentID,
+		AuthorID:   author.ID,
+		AuthorName: author.FirstName + " " + author.LastName,
+		Content:    req.Content,
+		Section:    req.Section,
+		Mentions:   mentions,
+		Blocking:   req.Blocking,
+		Status:     models.CommentStatusOpen,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if req.ParentID != nil && *req.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(*req.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parent comment ID")
+		}
+		parent, err := s.GetByID(ctx, documentID, parentID)
+		if err != nil {
+			return nil, err
+		}
+		// Threads are one level deep: replies to replies join the root thread
+		rootID := parent.ID
+		if parent.ParentID != nil {
+			rootID = *parent.ParentID
+		}
+		comment.ParentID = &rootID
+		comment.Section = parent.Section
+		comment.Blocking = false
+	}
+
+	result, err := s.collection.InsertOne(ctx, comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+	comment.ID = result.InsertedID.(primitive.ObjectID)
+
+	return comment, nil
+}
+
+// mentionableFilter builds the user filter matching people who can access a
+// document: its creator, contributors and accepted invitees, plus members of
+// their departments once the document is public.
+func (s *CommentService) mentionableFilter(ctx context.Context, documentID primitive.ObjectID) (bson.M, error) {
+	var document models.Document
+	err := s.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("document not found")
+		}
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	userIDs := []primitive.ObjectID{document.CreatedBy}
+	for _, group := range [][]models.Contributor{
+		document.Contributors.Authors,
+		document.Contributors.Verifiers,
+		document.Contributors.Validators,
+	} {
+		for _, contributor := range group {
+			userIDs = append(userIDs, contributor.UserID)
+		}
+	}
+
+	cursor, err := s.invitationCollection.Find(ctx, bson.M{
+		"document_id":     documentID,
+		"status":          models.InvitationStatusAccepted,
+		"invited_user_id": bson.M{"$exists": true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+	var invitations []models.Invitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, fmt.Errorf("failed to decode invitations: %w", err)
+	}
+	for _, invitation := range invitations {
+		if invitation.InvitedUserID != nil {
+			userIDs = append(userIDs, *invitation.InvitedUserID)
+		}
+	}
+
+	access := bson.A{bson.M{"_id": bson.M{"$in": userIDs}}}
+
+	// Public documents are readable by everyone, but suggestions stay scoped
+	// to the departments involved rather than the whole directory
+	if document.Status.IsPublished() {
+		departmentIDs, err := s.userCollection.Distinct(ctx, "department_id", bson.M{
+			"_id":           bson.M{"$in": userIDs},
+			"department_id": bson.M{"$ne": nil},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get departments: %w", err)
+		}
+		if len(departmentIDs) > 0 {
+			access = append(access, bson.M{"department_id": bson.M{"$in": departmentIDs}})
+		}
+	}
+
+	return bson.M{
+		"active": true,
+		"status": models.StatusActive,
+		"$or":    access,
+	}, nil
+}
+
+// checkMentionable rejects mentions of users who cannot access the document
+func (s *CommentService) checkMentionable(ctx context.Context, documentID primitive.ObjectID, mentions []primitive.ObjectID) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	filter, err := s.mentionableFilter(ctx, documentID)
+	if err != nil {
+		return err
+	}
+	filter["_id"] = bson.M{"$in": mentions}
+
+	count, err := s.userCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to verify mentions: %w", err)
+	}
+	if count != int64(len(mentions)) {
+		return fmt.Errorf("invalid mention: user cannot access this document")
+	}
+	return nil
+}
+
+// ListMentionable returns the users who can be mentioned on a document,
+// optionally filtered by name or email
+func (s *CommentService) ListMentionable(ctx context.Context, documentID primitive.ObjectID, query string, limit int64) ([]models.MentionableUser, error) {
+	filter, err := s.mentionableFilter(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if query = strings.TrimSpace(query); query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+		filter = bson.M{"$and": bson.A{filter, bson.M{"$or": bson.A{
+			bson.M{"first_name": pattern},
+			bson.M{"last_name": pattern},
+			bson.M{"email": pattern},
+		}}}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "first_name", Value: 1}, {Key: "last_name", Value: 1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"first_name": 1, "last_name": 1, "email": 1, "avatar": 1, "department_id": 1})
+
+	cursor, err := s.userCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mentionable users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	users := []models.MentionableUser{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+	return users, nil
+}
+
+// GetByID retrieves a comment of a document
+func (s *CommentService) GetByID(ctx context.Context, documentID, commentID primitive.ObjectID) (*models.Comment, error) {
+	var comment models.Comment
+	err := s.collection.FindOne(ctx, bson.M{"_id": commentID, "document_id": documentID}).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("comment not found")
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	return &comment, nil
+}
+
+// ListByDocument returns the comments of a document, optionally filtered by status
+func (s *CommentService) ListByDocument(ctx context.Context, documentID primitive.ObjectID, status *models.CommentStatus) ([]models.Comment, error) {
+	filter := bson.M{"document_id": documentID}
+	if status != nil {
+		filter["status"] = *status
+	}
+
+	cursor, err := s.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	comments := make([]models.Comment, 0)
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, fmt.Errorf("failed to decode comments: %w", err)
+	}
+	return comments, nil
+}
+
+// ListThreads returns the discussion threads of a document, optionally
+// filtered by the status of the root comment
+func (s *CommentService) ListThreads(ctx context.Context, documentID primitive.ObjectID, status *models.CommentStatus) ([]models.CommentThread, error) {
+	comments, err := s.ListByDocument(ctx, documentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := make([]models.CommentThread, 0)
+	index := make(map[primitive.ObjectID]int)
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			continue
+		}
+		if status != nil && comment.Status != *status {
+			continue
+		}
+		index[comment.ID] = len(threads)
+		threads = append(threads, models.CommentThread{Comment: comment, Replies: make([]models.Comment, 0)})
+	}
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			continue
+		}
+		if i, ok := index[*comment.ParentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, comment)
+		}
+	}
+
+	return threads, nil
+}
+
+// Update edits the content of a comment, keeping the previous revision
+func (s *CommentService) Update(ctx context.Context, documentID, commentID primitive.ObjectID, req *models.UpdateCommentRequest, userID primitive.ObjectID) (*models.Comment, error) {
+	comment, err := s.GetByID(ctx, documentID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, fmt.Errorf("only the author can edit this comment")
+	}
+	if comment.Deleted {
+		return nil, fmt.Errorf("comment not found")
+	}
+
+	mentions, err := parseMentions(req.Mentions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMentionable(ctx, documentID, mentions); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := s.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": commentID, "document_id": documentID},
+		bson.M{
+			"$set": bson.M{
+				"content":    req.Content,
+				"mentions":   mentions,
+				"updated_at": now,
+			},
+			"$push": bson.M{"edit_history": models.CommentEdit{
+				Content:  comment.Content,
+				EditedAt: now,
+			}},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+
+	var updated models.Comment
+	if err := result.Decode(&updated); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+	return &updated, nil
+}
+
+// Delete removes a comment. Thread roots with replies are blanked instead so
+// the discussion stays readable.
+func (s *CommentService) Delete(ctx context.Context, documentID, commentID primitive.ObjectID, user *models.User) error {
+	comment, err := s.GetByID(ctx, documentID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != user.ID && user.Role != models.RoleAdmin {
+		return fmt.Errorf("only the author can delete this comment")
+	}
+
+	replies, err := s.collection.CountDocuments(ctx, bson.M{"parent_id": commentID})
+	if err != nil {
+		return fmt.Errorf("failed to count replies: %w", err)
+	}
+
+	if replies > 0 {
+		_, err = s.collection.UpdateOne(ctx, bson.M{"_id": commentID}, bson.M{
+			"$set": bson.M{
+				"content":    "",
+				"deleted":    true,
+				"blocking":   false,
+				"updated_at": time.Now(),
+			},
+			"$unset": bson.M{"mentions": "", "edit_history": ""},
+		})
+	} else {
+		_, err = s.collection.DeleteOne(ctx, bson.M{"_id": commentID})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	return nil
+}
+
+// SetResolved resolves or reopens a comment
+func (s *CommentService) SetResolved(ctx context.Context, documentID, commentID primitive.ObjectID, resolved bool, userID primitive.ObjectID) (*models.Comment, error) {
+	comment, err := s.GetByID(ctx, documentID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.ParentID != nil {
+		return nil, fmt.Errorf("only thread root comments can be resolved")
+	}
+
+	now := time.Now()
+	update := bson.M{}
+	if resolved {
+		update["$set"] = bson.M{
+			"status":      models.CommentStatusResolved,
+			"resolved_by": userID,
+			"resolved_at": now,
+			"updated_at":  now,
+		}
+	} else {
+		update["$set"] = bson.M{
+			"status":     models.CommentStatusOpen,
+			"updated_at": now,
+		}
+		update["$unset"] = bson.M{"resolved_by": "", "resolved_at": ""}
+	}
+
+	result := s.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": commentID, "document_id": documentID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+
+	var updated models.Comment
+	if err := result.Decode(&updated); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("comment not found")
+		}
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+	return &updated, nil
+}
+
+// GetCounts returns the comment counts of a document
+func (s *CommentService) GetCounts(ctx context.Context, documentID primitive.ObjectID) (*models.CommentCounts, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"document_id": documentID,
+			"parent_id":   bson.M{"$exists": false},
+			"deleted":     bson.M{"$ne": true},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"status": "$status", "blocking": "$blocking"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID struct {
+			Status   models.CommentStatus `bson:"status"`
+			Blocking bool                 `bson:"blocking"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode comment counts: %w", err)
+	}
+
+	counts := &models.CommentCounts{}
+	for _, r := range results {
+		counts.Total += r.Count
+		if r.ID.Status == models.CommentStatusResolved {
+			counts.Resolved += r.Count
+			continue
+		}
+		counts.Open += r.Count
+		if r.ID.Blocking {
+			counts.BlockingOpen += r.Count
+		}
+	}
+	counts.SigningBlocked = s.resolutionGate && counts.BlockingOpen > 0
+
+	return counts, nil
+}
+
+// CheckSigningAllowed returns models.ErrBlockingCommentsOpen when the resolution gate
+// is enabled and the document still has open blocking comments
+func (s *CommentService) CheckSigningAllowed(ctx context.Context, documentID primitive.ObjectID) error {
+	if !s.resolutionGate {
+		return nil
+	}
+	count, err := s.collection.CountDocuments(ctx, bson.M{
+		"document_id": documentID,
+		"blocking":    true,
+		"status":      models.CommentStatusOpen,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count blocking comments: %w", err)
+	}
+	if count > 0 {
+		return models.ErrBlockingCommentsOpen
+	}
+	return nil
+}
+
+// BuildReport assembles every review comment of a document, threads first
+// and replies in order, as evidence of the review process
+func (s *CommentService) BuildReport(ctx context.Context, documentID primitive.ObjectID, generatedBy string) (*models.CommentReport, error) {
+	var document models.Document
+	err := s.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&document)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("document not found")
+		}
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	threads, err := s.ListThreads(ctx, documentID, nil)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.GetCounts(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the names of the users who resolved threads
+	resolverIDs := make([]primitive.ObjectID, 0)
+	for _, thread := range threads {
+		if thread.ResolvedBy != nil {
+			resolverIDs = append(resolverIDs, *thread.ResolvedBy)
+		}
+	}
+	resolverNames := make(map[primitive.ObjectID]string)
+	if len(resolverIDs) > 0 {
+		cursor, err := s.userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": resolverIDs}},
+			options.Find().SetProjection(bson.M{"first_name": 1, "last_name": 1}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resolvers: %w", err)
+		}
+		var users []models.User
+		if err := cursor.All(ctx, &users); err != nil {
+			return nil, fmt.Errorf("failed to decode resolvers: %w", err)
+		}
+		for _, user := range users {
+			resolverNames[user.ID] = user.FirstName + " " + user.LastName
+		}
+	}
+
+	entries := make([]models.CommentReportEntry, 0, len(threads))
+	for i, thread := range threads {
+		root := models.CommentReportEntry{
+			ThreadNumber: i + 1,
+			AuthorName:   thread.AuthorName,
+			Content:      thread.Content,
+			Section:      thread.Section,
+			Blocking:     thread.Blocking,
+			Status:       thread.Status,
+			ResolvedAt:   thread.ResolvedAt,
+			EditCount:    len(thread.EditHistory),
+			Deleted:      thread.Deleted,
+			CreatedAt:    thread.CreatedAt,
+		}
+		if thread.ResolvedBy != nil {
+			root.ResolvedByName = resolverNames[*thread.ResolvedBy]
+		}
+		entries = append(entries, root)
+
+		for _, reply := range thread.Replies {
+			entries = append(entries, models.CommentReportEntry{
+				ThreadNumber: i + 1,
+				IsReply:      true,
+				AuthorName:   reply.AuthorName,
+				Content:      reply.Content,
+				Section:      reply.Section,
+				EditCount:    len(reply.EditHistory),
+				CreatedAt:    reply.CreatedAt,
+			})
+		}
+	}
+
+	return &models.CommentReport{
+		DocumentID:  document.ID,
+		Reference:   document.Reference,
+		Title:       document.Title,
+		Version:     document.Version,
+		Status:      document.Status,
+		Counts:      *counts,
+		Entries:     entries,
+		GeneratedAt: time.Now(),
+		GeneratedBy: generatedBy,
+	}, nil
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ContributorTemplateService handles the default contributors of departments
+type ContributorTemplateService struct {
+	departmentCollection  *mongo.Collection
+	jobPositionCollection *mongo.Collection
+	userCollection        *mongo.Collection
+}
+
+// NewContributorTemplateService creates a new contributor template service instance
+func NewContributorTemplateService(db *DatabaseService) *ContributorTemplateService {
+	return &ContributorTemplateService{
+		departmentCollection:  db.Collection("departments"),
+		jobPositionCollection: db.Collection("job_positions"),
+		userCollection:        db.Collection("users"),
+	}
+}
+
+// Set replaces the contributor template of a department
+func (s *ContributorTemplateService) Set(ctx context.Context, departmentID primitive.ObjectID, template *models.ContributorTemplate, updatedBy primitive.ObjectID) (*models.Department, error) {
+	if template.Verifiers == nil {
+		template.Verifiers = make([]models.ContributorTemplateEntry, 0)
+	}
+	if template.Validators == nil {
+		template.Validators = make([]models.ContributorTemplateEntry, 0)
+	}
+	for _, entry := range append(append([]models.ContributorTemplateEntry{}, template.Verifiers...), template.Validators...) {
+		if err := s.validateEntry(ctx, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := s.departmentCollection.UpdateOne(ctx,
+		bson.M{"_id": departmentID},
+		bson.M{"$set": bson.M{
+			"contributor_template": template,
+			"updated_by":           updatedBy,
+			"updated_at":           time.Now(),
+		}},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update contributor template: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("department not found")
+	}
+
+	return s.getDepartment(ctx, departmentID)
+}
+
+// Clear removes the contributor template of a department
+func (s *ContributorTemplateService) Clear(ctx context.Context, departmentID primitive.ObjectID, updatedBy primitive.ObjectID) (*models.Department, error) {
+	result, err := s.departmentCollection.UpdateOne(ctx,
+		bson.M{"_id": departmentID},
+		bson.M{
+			"$unset": bson.M{"contributor_template": ""},
+			"$set":   bson.M{"updated_by": updatedBy, "updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear contributor template: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("department not found")
+	}
+
+	return s.getDepartment(ctx, departmentID)
+}
+
+// Resolve returns the contributors designated by the template of a department.
+// The template is nil when the department has none.
+func (s *ContributorTemplateService) Resolve(ctx context.Context, departmentID primitive.ObjectID) (*models.Contributors, *models.ContributorTemplate, error) {
+	department, err := s.getDepartment(ctx, departmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contributors := &models.Contributors{
+		Authors:    make([]models.Contributor, 0),
+		Verifiers:  make([]models.Contributor, 0),
+		Validators: make([]models.Contributor, 0),
+	}
+	if department.ContributorTemplate == nil {
+		return contributors, nil, nil
+	}
+
+	template := department.ContributorTemplate
+	if contributors.Verifiers, err = s.resolveEntries(ctx, template.Verifiers, models.ContributorTeamVerifiers, department.Name); err != nil {
+		return nil, nil, err
+	}
+	if contributors.Validators, err = s.resolveEntries(ctx, template.Validators, models.ContributorTeamValidators, department.Name); err != nil {
+		return nil, nil, err
+	}
+
+	return contributors, template, nil
+}
+
+// resolveEntries lists the active users designated by template entries, once each
+func (s *ContributorTemplateService) resolveEntries(ctx context.Context, entries []models.ContributorTemplateEntry, team models.ContributorTeam, departmentName string) ([]models.Contributor, error) {
+	contributors := make([]models.Contributor, 0)
+	if len(entries) == 0 {
+		return contributors, nil
+	}
+
+	var userIDs, positionIDs []primitive.ObjectID
+	for _, entry := range entries {
+		if entry.UserID != nil {
+			userIDs = append(userIDs, *entry.UserID)
+		}
+		if entry.JobPositionID != nil {
+			positionIDs = append(positionIDs, *entry.JobPositionID)
+		}
+	}
+
+	or := make([]bson.M, 0, 2)
+	if len(userIDs) > 0 {
+		or = append(or, bson.M{"_id": bson.M{"$in": userIDs}})
+	}
+	if len(positionIDs) > 0 {
+		or = append(or, bson.M{"job_position_id": bson.M{"$in": positionIDs}})
+	}
+
+	cursor, err := s.userCollection.Find(ctx, bson.M{
+		"$or":    or,
+		"status": models.StatusActive,
+		"active": true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find template contributors: %w", err)
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode template contributors: %w", err)
+	}
+
+	titles, err := s.positionTitles(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, user := range users {
+		title := ""
+		if user.JobPositionID != nil {
+			title = titles[*user.JobPositionID]
+		}
+		contributors = append(contributors, models.Contributor{
+			UserID:     user.ID,
+			Name:       fmt.Sprintf("%s %s", user.FirstName, user.LastName),
+			Title:      title,
+			Department: departmentName,
+			Team:       team,
+			Status:     models.SignatureStatusJoined,
+			InvitedAt:  now,
+		})
+	}
+
+	return contributors, nil
+}
+
+// positionTitles returns the titles of the job positions held by the users
+func (s *ContributorTemplateService) positionTitles(ctx context.Context, users []models.User) (map[primitive.ObjectID]string, error) {
+	titles := make(map[primitive.ObjectID]string)
+	ids := make([]primitive.ObjectID, 0, len(users))
+	for _, user := range users {
+		if user.JobPositionID != nil {
+			ids = append(ids, *user.JobPositionID)
+		}
+	}
+	if len(ids) == 0 {
+		return titles, nil
+	}
+
+	cursor, err := s.jobPositionCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find job positions: %w", err)
+	}
+	var positions []models.JobPosition
+	if err := cursor.All(ctx, &positions); err != nil {
+		return nil, fmt.Errorf("failed to decode job positions: %w", err)
+	}
+	for _, position := range positions {
+		titles[position.ID] = position.Title
+	}
+	return titles, nil
+}
+
+// validateEntry checks that an entry designates exactly one existing user or job position
+func (s *ContributorTemplateService) validateEntry(ctx context.Context, entry models.ContributorTemplateEntry) error {
+	switch {
+	case (entry.UserID == nil) == (entry.JobPositionID == nil):
+		return errors.New("each template entry must set either userId or jobPositionId")
+	case entry.UserID != nil:
+		count, err := s.userCollection.CountDocuments(ctx, bson.M{"_id": *entry.UserID})
+		if err != nil {
+			return fmt.Errorf("failed to check template user: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("template user %s not found", entry.UserID.Hex())
+		}
+	default:
+		count, err := s.jobPositionCollection.CountDocuments(ctx, bson.M{"_id": *entry.JobPositionID})
+		if err != nil {
+			return fmt.Errorf("failed to check template job position: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("template job position %s not found", entry.JobPositionID.Hex())
+		}
+	}
+	return nil
+}
+
+func (s *ContributorTemplateService) getDepartment(ctx context.Context, departmentID primitive.ObjectID) (*models.Department, error) {
+	var department models.Department
+	if err := s.departmentCollection.FindOne(ctx, bson.M{"_id": departmentID}).Decode(&department); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("department not found")
+		}
+		return nil, fmt.Errorf("failed to get department: %w", err)
+	}
+	return &department, nil
+}
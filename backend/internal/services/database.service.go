@@ -21,7 +21,8 @@ type DatabaseService struct {
 var dbService *DatabaseService
 
 // InitDatabase initializes the database connection
-func InitDatabase() (*DatabaseService, error) {
+// Extra client options (e.g. command monitors) are merged over the defaults
+func InitDatabase(extraOptions ...*options.ClientOptions) (*DatabaseService, error) {
 	if dbService != nil {
 		return dbService, nil
 	}
@@ -38,6 +39,11 @@ func InitDatabase() (*DatabaseService, error) {
 
 	// Set client options
 	clientOptions := options.Client().ApplyURI(mongoURI)
+	for _, opts := range extraOptions {
+		if opts != nil {
+			clientOptions = options.MergeClientOptions(clientOptions, opts)
+		}
+	}
 
 	// Set connection timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -199,6 +205,45 @@ func (ds *DatabaseService) createIndexes(ctx context.Context) error {
 		return err
 	}
 
+	// Document collection indexes, backing the sorts of the document lists.
+	// Each sort key is followed by updated_at, the tie-breaker of the lists.
+	documentCollection := ds.Database.Collection("documents")
+
+	documentIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "updated_at", Value: -1}}},
+		{Keys: bson.D{{Key: "title", Value: 1}, {Key: "updated_at", Value: -1}}},
+		{Keys: bson.D{{Key: "reference", Value: 1}, {Key: "updated_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: -1}}},
+		{Keys: bson.D{{Key: "reference", Value: 1}, {Key: "effective_date", Value: -1}}},
+		// Multikey indexes of the search by actor and job position
+		{Keys: bson.D{{Key: "process_groups.process_steps.responsible", Value: 1}}, Options: options.Index().SetCollation(actorNameCollation)},
+		{Keys: bson.D{{Key: "tasks.intervenants", Value: 1}}},
+	}
+
+	_, err = documentCollection.Indexes().CreateMany(ctx, documentIndexes)
+	if err != nil {
+		log.Printf("Failed to create document indexes: %v", err)
+		return err
+	}
+
+	// One autosave draft per document and contributor
+	draftCollection := ds.Database.Collection("documents_drafts")
+
+	draftIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "document_id", Value: 1},
+			{Key: "user_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err = draftCollection.Indexes().CreateOne(ctx, draftIndex)
+	if err != nil {
+		log.Printf("Failed to create document draft indexes: %v", err)
+		return err
+	}
+
 	log.Printf("✅ Database indexes created successfully")
 	return nil
 }
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// userCodeAlphabet excludes vowels and ambiguous characters so codes are easy to type
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// resolveSessionScript replaces a display session only if it is unchanged
+// since it was read, keeping its expiry, so that two approvers cannot both
+// resolve it
+var resolveSessionScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "KEEPTTL")
+return 1
+`)
+
+// DisplaySessionService handles device-code logins for meeting-room and NOC displays
+type DisplaySessionService struct {
+	redisClient     *redis.Client
+	codeExpiry      time.Duration
+	pollInterval    time.Duration
+	verificationURI string
+}
+
+// NewDisplaySessionService creates a new display session service instance
+func NewDisplaySessionService(redisClient *redis.Client) *DisplaySessionService {
+	verificationURI := os.Getenv("DISPLAY_VERIFICATION_URI")
+	if verificationURI == "" {
+		frontendURL := os.Getenv("FRONTEND_URL")
+		if frontendURL == "" {
+			frontendURL = "http://localhost:3000"
+		}
+		verificationURI = strings.TrimRight(frontendURL, "/") + "/display/approve"
+	}
+
+	return &DisplaySessionService{
+		redisClient:     redisClient,
+		codeExpiry:      10 * time.Minute, // Code must be approved within 10 minutes
+		pollInterval:    5 * time.Second,
+		verificationURI: verificationURI,
+	}
+}
+
+// CreateSession creates a new pending display session
+func (s *DisplaySessionService) CreateSession(ctx context.Context, displayName string) (*models.DisplaySession, error) {
+	deviceCode, err := s.generateDeviceCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate device code: %w", err)
+	}
+
+	userCode, err := s.generateUserCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate user code: %w", err)
+	}
+
+	now := time.Now()
+	session := &models.DisplaySession{
+		DeviceCode:  deviceCode,
+		UserCode:    userCode,
+		DisplayName: displayName,
+		Status:      models.DisplaySessionStatusPending,
+		ExpiresAt:   now.Add(s.codeExpiry),
+		CreatedAt:   now,
+	}
+
+	if err := s.saveSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	// Index the session by user code so the approving user can look it up
+	if err := s.redisClient.Set(ctx, s.getUserCodeKey(userCode), deviceCode, s.codeExpiry).Err(); err != nil {
+		return nil, fmt.Errorf("failed to store user code: %w", err)
+	}
+
+	return session, nil
+}
+
+// GetByUserCode retrieves a pending display session by its short user code
+func (s *DisplaySessionService) GetByUserCode(ctx context.Context, userCode string) (*models.DisplaySession, error) {
+	deviceCode, err := s.redisClient.Get(ctx, s.getUserCodeKey(normalizeUserCode(userCode))).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, models.ErrDisplayCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get user code from Redis: %w", err)
+	}
+
+	return s.GetByDeviceCode(ctx, deviceCode)
+}
+
+// GetByDeviceCode retrieves a display session by its device code
+func (s *DisplaySessionService) GetByDeviceCode(ctx context.Context, deviceCode string) (*models.DisplaySession, error) {
+	sessionJSON, err := s.redisClient.Get(ctx, s.getDeviceCodeKey(deviceCode)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, models.ErrDisplayCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get display session from Redis: %w", err)
+	}
+
+	var session models.DisplaySession
+	if err := json.Unmarshal([]byte(sessionJSON), &session); err != nil {
+		return nil, fmt.Errorf("failed to deserialize display session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// Resolve approves or denies a pending display session on behalf of a user
+func (s *DisplaySessionService) Resolve(ctx context.Context, userCode string, userID primitive.ObjectID, approve bool) (*models.DisplaySession, error) {
+	deviceCode, err := s.redisClient.Get(ctx, s.getUserCodeKey(normalizeUserCode(userCode))).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, models.ErrDisplayCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get user code from Redis: %w", err)
+	}
+
+	sessionJSON, err := s.redisClient.Get(ctx, s.getDeviceCodeKey(deviceCode)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, models.ErrDisplayCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get display session from Redis: %w", err)
+	}
+	var session models.DisplaySession
+	if err := json.Unmarshal([]byte(sessionJSON), &session); err != nil {
+		return nil, fmt.Errorf("failed to deserialize display session: %w", err)
+	}
+
+	if session.Status != models.DisplaySessionStatusPending {
+		return nil, models.ErrDisplayCodeUsed
+	}
+
+	now := time.Now()
+	if approve {
+		session.Status = models.DisplaySessionStatusApproved
+	} else {
+		session.Status = models.DisplaySessionStatusDenied
+	}
+	session.ApprovedBy = &userID
+	session.ApprovedAt = &now
+
+	resolvedJSON, err := json.Marshal(&session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize display session: %w", err)
+	}
+
+	// Only the first approver moves the session out of pending
+	swapped, err := resolveSessionScript.Run(ctx, s.redisClient, []string{s.getDeviceCodeKey(deviceCode)}, sessionJSON, string(resolvedJSON)).Int()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store display session in Redis: %w", err)
+	}
+	if swapped == 0 {
+		return nil, models.ErrDisplayCodeUsed
+	}
+
+	// The user code is single use
+	s.redisClient.Del(ctx, s.getUserCodeKey(session.UserCode))
+
+	return &session, nil
+}
+
+// Consume removes a resolved session once the display has collected its
+// result and returns it. The session is read and removed at once, so only
+// one poll of a device code gets it, the others get ErrDisplayCodeNotFound.
+func (s *DisplaySessionService) Consume(ctx context.Context, deviceCode string) (*models.DisplaySession, error) {
+	sessionJSON, err := s.redisClient.GetDel(ctx, s.getDeviceCodeKey(deviceCode)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, models.ErrDisplayCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to consume display session from Redis: %w", err)
+	}
+
+	var session models.DisplaySession
+	if err := json.Unmarshal([]byte(sessionJSON), &session); err != nil {
+		return nil, fmt.Errorf("failed to deserialize display session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// GetCodeExpiry returns how long a display code stays valid
+func (s *DisplaySessionService) GetCodeExpiry() time.Duration {
+	return s.codeExpiry
+}
+
+// GetPollInterval returns the recommended polling interval for displays
+func (s *DisplaySessionService) GetPollInterval() time.Duration {
+	return s.pollInterval
+}
+
+// GetVerificationURI returns the URL encoded in the QR code shown by displays
+func (s *DisplaySessionService) GetVerificationURI(userCode string) string {
+	return fmt.Sprintf("%s?code=%s", s.verificationURI, userCode)
+}
+
+// saveSession stores the session in Redis until the code expires
+func (s *DisplaySessionService) saveSession(ctx context.Context, session *models.DisplaySession) error {
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to serialize display session: %w", err)
+	}
+
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return models.ErrDisplayCodeNotFound
+	}
+
+	if err := s.redisClient.Set(ctx, s.getDeviceCodeKey(session.DeviceCode), sessionJSON, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store display session in Redis: %w", err)
+	}
+
+	return nil
+}
+
+// generateDeviceCode generates the secret code used by the display to poll
+func (s *DisplaySessionService) generateDeviceCode() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// generateUserCode generates a short code formatted as XXXX-XXXX
+func (s *DisplaySessionService) generateUserCode() (string, error) {
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			sb.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// normalizeUserCode accepts codes typed in lowercase or without the dash
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if len(code) == 8 {
+		return code[:4] + "-" + code[4:]
+	}
+	return code
+}
+
+// getDeviceCodeKey generates Redis key for display session storage
+func (s *DisplaySessionService) getDeviceCodeKey(deviceCode string) string {
+	return fmt.Sprintf("display_session:%s", deviceCode)
+}
+
+// getUserCodeKey generates Redis key for user code lookup
+func (s *DisplaySessionService) getUserCodeKey(userCode string) string {
+	return fmt.Sprintf("display_user_code:%s", userCode)
+}
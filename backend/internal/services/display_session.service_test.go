@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// stringRedisHook answers the commands used by the display sessions from
+// memory. beforeSwap runs once before the first compare-and-set, to let a
+// concurrent request slip in between the read and the write of a session.
+type stringRedisHook struct {
+	mu         sync.Mutex
+	values     map[string]string
+	beforeSwap func()
+}
+
+func (h *stringRedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *stringRedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		args := cmd.Args()
+		name := strings.ToLower(cmd.Name())
+		if name == "evalsha" && h.beforeSwap != nil {
+			swap := h.beforeSwap
+			h.beforeSwap = nil
+			swap()
+		}
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		switch name {
+		case "get", "getdel":
+			value, exists := h.values[fmt.Sprint(args[1])]
+			if !exists {
+				cmd.SetErr(redis.Nil)
+				return redis.Nil
+			}
+			if name == "getdel" {
+				delete(h.values, fmt.Sprint(args[1]))
+			}
+			cmd.(*redis.StringCmd).SetVal(value)
+		case "set":
+			h.values[fmt.Sprint(args[1])] = redisString(args[2])
+			cmd.(*redis.StatusCmd).SetVal("OK")
+		case "del":
+			delete(h.values, fmt.Sprint(args[1]))
+			cmd.(*redis.IntCmd).SetVal(1)
+		case "evalsha": // resolveSessionScript: EVALSHA sha 1 key expected replacement
+			key := fmt.Sprint(args[3])
+			if current, exists := h.values[key]; !exists || current != redisString(args[4]) {
+				cmd.(*redis.Cmd).SetVal(int64(0))
+				return nil
+			}
+			h.values[key] = redisString(args[5])
+			cmd.(*redis.Cmd).SetVal(int64(1))
+		default:
+			cmd.SetErr(fmt.Errorf("unexpected command %s", name))
+		}
+		return nil
+	}
+}
+
+func (h *stringRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func redisString(arg interface{}) string {
+	if b, ok := arg.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(arg)
+}
+
+func newTestDisplaySessionService(t *testing.T) (*DisplaySessionService, *stringRedisHook) {
+	hook := &stringRedisHook{values: map[string]string{}}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	t.Cleanup(func() { client.Close() })
+	return NewDisplaySessionService(client), hook
+}
+
+func TestResolveLetsOnlyOneApproverResolveASession(t *testing.T) {
+	service, hook := newTestDisplaySessionService(t)
+	ctx := context.Background()
+
+	session, err := service.CreateSession(ctx, "NOC wallboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second approver denies the code while the first one is approving it
+	denier := primitive.NewObjectID()
+	var denyErr error
+	hook.beforeSwap = func() {
+		_, denyErr = service.Resolve(ctx, session.UserCode, denier, false)
+	}
+	_, approveErr := service.Resolve(ctx, session.UserCode, primitive.NewObjectID(), true)
+
+	if denyErr != nil {
+		t.Fatalf("expected the first resolution to succeed, got %v", denyErr)
+	}
+	if approveErr != models.ErrDisplayCodeUsed {
+		t.Fatalf("expected the late approval to be rejected, got %v", approveErr)
+	}
+	resolved, err := service.GetByDeviceCode(ctx, session.DeviceCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Status != models.DisplaySessionStatusDenied || *resolved.ApprovedBy != denier {
+		t.Fatalf("expected the session to keep the first resolution, got %s by %v", resolved.Status, resolved.ApprovedBy)
+	}
+}
+
+func TestConsumeHandsAResolvedSessionToASinglePoll(t *testing.T) {
+	service, _ := newTestDisplaySessionService(t)
+	ctx := context.Background()
+
+	session, err := service.CreateSession(ctx, "Meeting room 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Resolve(ctx, session.UserCode, primitive.NewObjectID(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two polls both see the approved session, only one may collect it
+	for i := 0; i < 2; i++ {
+		if polled, err := service.GetByDeviceCode(ctx, session.DeviceCode); err != nil || polled.Status != models.DisplaySessionStatusApproved {
+			t.Fatalf("poll %d: expected the approved session, got %v", i+1, err)
+		}
+	}
+	first, err := service.Consume(ctx, session.DeviceCode)
+	if err != nil || first.Status != models.DisplaySessionStatusApproved {
+		t.Fatalf("expected the first poll to collect the approved session, got %v", err)
+	}
+	if _, err := service.Consume(ctx, session.DeviceCode); err != models.ErrDisplayCodeNotFound {
+		t.Fatalf("expected the second poll to find the session gone, got %v", err)
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,24 +20,84 @@ import (
 )
 
 type DocumentService struct {
-	collection           *mongo.Collection
-	versionCollection    *mongo.Collection
-	invitationCollection *mongo.Collection
-	userService          *UserService
-	pdfService           *PDFService
-	macroService         *MacroService
-	documentationService *DocumentationService
+	collection               *mongo.Collection
+	versionCollection        *mongo.Collection
+	draftCollection          *mongo.Collection
+	signatureCollection      *mongo.Collection
+	invitationCollection     *mongo.Collection
+	userCollection           *mongo.Collection
+	commentCollection        *mongo.Collection
+	favoriteCollection       *mongo.Collection
+	recentCollection         *mongo.Collection
+	acknowledgmentCollection *mongo.Collection
+	campaignCollection       *mongo.Collection
+	permissionCollection     *mongo.Collection
+	watchCollection          *mongo.Collection
+	viewCollection           *mongo.Collection
+	qmsSyncCollection        *mongo.Collection
+	reactionCollection       *mongo.Collection
+	userService              *UserService
+	pdfService               *PDFService
+	macroService             *MacroService
+	documentationService     *DocumentationService
+	sectionService           *MetadataSectionService
+	actorService             *ActorService
+	referenceService         *ReferenceService
+	templateService          *ContributorTemplateService
+	minioService             *MinIOService
+	exportHooks              *ExportHookService
+	versionPDFSnapshots      bool // Render the PDF of each version snapshot, set by VERSION_PDF_SNAPSHOTS
 }
 
-func NewDocumentService(db *mongo.Database, userService *UserService, pdfService *PDFService, macroService *MacroService, documentationService *DocumentationService) *DocumentService {
+// ErrDocumentRevisionConflict is returned when a document was modified since
+// the revision the client based its changes on
+var ErrDocumentRevisionConflict = errors.New("document was modified by another user")
+
+// ErrInvalidEffectiveDates is returned when a document would stop being in
+// force before it comes into force
+var ErrInvalidEffectiveDates = errors.New("supersession date must be after the effective date")
+
+// ErrInvalidClassification is returned for an unknown confidentiality level
+var ErrInvalidClassification = errors.New("classification must be public, internal, confidential or restricted")
+
+// revisionFilter matches a document at the given revision. Documents written
+// before revisions were tracked have no revision field and count as revision 0.
+func revisionFilter(revision int64) interface{} {
+	if revision == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return revision
+}
+
+func NewDocumentService(db *mongo.Database, userService *UserService, pdfService *PDFService, macroService *MacroService, documentationService *DocumentationService, sectionService *MetadataSectionService, actorService *ActorService, referenceService *ReferenceService, templateService *ContributorTemplateService, minioService *MinIOService, exportHooks *ExportHookService) *DocumentService {
 	return &DocumentService{
-		collection:           db.Collection("documents"),
-		versionCollection:    db.Collection("document_versions"),
-		invitationCollection: db.Collection("invitations"),
-		userService:          userService,
-		pdfService:           pdfService,
-		macroService:         macroService,
-		documentationService: documentationService,
+		collection:               db.Collection("documents"),
+		versionCollection:        db.Collection("document_versions"),
+		draftCollection:          db.Collection("documents_drafts"),
+		signatureCollection:      db.Collection("signatures"),
+		invitationCollection:     db.Collection("invitations"),
+		userCollection:           db.Collection("users"),
+		commentCollection:        db.Collection("comments"),
+		favoriteCollection:       db.Collection("document_favorites"),
+		recentCollection:         db.Collection("recently_viewed"),
+		acknowledgmentCollection: db.Collection("acknowledgments"),
+		campaignCollection:       db.Collection("acknowledgment_campaigns"),
+		permissionCollection:     db.Collection("permissions"),
+		watchCollection:          db.Collection("document_watches"),
+		viewCollection:           db.Collection("document_views"),
+		qmsSyncCollection:        db.Collection("qms_syncs"),
+		reactionCollection:       db.Collection("reactions"),
+		userService:              userService,
+		pdfService:               pdfService,
+		macroService:             macroService,
+		documentationService:     documentationService,
+		sectionService:           sectionService,
+		actorService:             actorService,
+		referenceService:         referenceService,
+		templateService:          templateService,
+		minioService:             minioService,
+		exportHooks:              exportHooks,
+		versionPDFSnapshots:      os.Getenv("VERSION_PDF_SNAPSHOTS") == "true",
 	}
 }
 
@@ -81,6 +145,13 @@ func validateTasks(processCode string, tasks []models.Task) error {
 
 // Create creates a new document
 func (s *DocumentService) Create(ctx context.Context, req *models.CreateDocumentRequest, userID primitive.ObjectID) (*models.Document, error) {
+	if err := validateEffectiveDates(req.EffectiveDate, req.SupersessionDate); err != nil {
+		return nil, err
+	}
+	if !models.IsValidDocumentClassification(req.Classification) {
+		return nil, ErrInvalidClassification
+	}
+
 	// Convert MacroID from string to ObjectID if provided
 	var macroID *primitive.ObjectID
 	if req.MacroID != nil && *req.MacroID != "" {
@@ -91,26 +162,41 @@ func (s *DocumentService) Create(ctx context.Context, req *models.CreateDocument
 		macroID = &objID
 	}
 
-	// Generate ProcessCode if not provided and MacroID exists
+	// Generate ProcessCode if not provided and MacroID exists, a provided one
+	// must be unique within the macro
 	processCode := req.ProcessCode
-	if processCode == "" && macroID != nil {
-		// Get macro to extract code
-		macro, err := s.macroService.GetMacroByID(ctx, *macroID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get macro: %w", err)
+	if macroID != nil {
+		if processCode == "" {
+			generated, err := s.macroService.NextProcessCode(ctx, *macroID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate process code: %w", err)
+			}
+			processCode = generated
+		} else if err := s.checkProcessCode(ctx, *macroID, processCode, nil); err != nil {
+			return nil, err
 		}
+	}
 
-		// Get next process number for this macro
-		nextNumber, err := s.macroService.GetNextProcessNumber(ctx, *macroID)
+	// Department the document is created under, the creator's one by default
+	var departmentID *primitive.ObjectID
+	if req.DepartmentID != "" {
+		objID, err := primitive.ObjectIDFromHex(req.DepartmentID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate process code: %w", err)
+			return nil, fmt.Errorf("invalid department ID: %w", err)
 		}
-
-		processCode = fmt.Sprintf("%s_P%d", macro.Code, nextNumber)
+		departmentID = &objID
 	}
 
-	// Generate reference if not provided (use process code)
+	// Generate reference if not provided, from the reference scheme when
+	// requested, otherwise use the process code
 	reference := req.Reference
+	if reference == "" && req.AutoReference && macroID != nil {
+		generated, err := s.referenceService.Next(ctx, *macroID, departmentID, userID)
+		if err != nil {
+			return nil, err
+		}
+		reference = generated
+	}
 	if reference == "" {
 		reference = processCode
 	}
@@ -156,12 +242,29 @@ func (s *DocumentService) Create(ctx context.Context, req *models.CreateDocument
 	}
 	req.Contributors.Authors = append(req.Contributors.Authors, ownerContributor)
 
+	// Pre-populate the verification and validation teams from the department template
+	if departmentID != nil {
+		if err := s.applyContributorTemplate(ctx, &req.Contributors, *departmentID); err != nil {
+			return nil, err
+		}
+	} else if user.DepartmentID != nil {
+		// A stale department of the creator must not block the creation
+		if err := s.applyContributorTemplate(ctx, &req.Contributors, *user.DepartmentID); err != nil && err.Error() != "department not found" {
+			return nil, err
+		}
+	}
+
 	if req.Metadata.Objectives == nil {
 		req.Metadata.Objectives = make([]string, 0)
 	}
 	if req.Metadata.ImplicatedActors == nil {
 		req.Metadata.ImplicatedActors = make([]string, 0)
 	}
+	actors, err := s.normalizeActors(ctx, req.Metadata.ImplicatedActors)
+	if err != nil {
+		return nil, err
+	}
+	req.Metadata.ImplicatedActors = actors
 	if req.Metadata.ManagementRules == nil {
 		req.Metadata.ManagementRules = make([]string, 0)
 	}
@@ -174,6 +277,9 @@ func (s *DocumentService) Create(ctx context.Context, req *models.CreateDocument
 	if req.ProcessGroups == nil {
 		req.ProcessGroups = make([]models.ProcessGroup, 0)
 	}
+	if err := s.normalizeStepResponsibles(ctx, req.ProcessGroups); err != nil {
+		return nil, err
+	}
 	if req.Annexes == nil {
 		req.Annexes = make([]models.Annex, 0)
 	}
@@ -240,6 +346,9 @@ func (s *DocumentService) Create(ctx context.Context, req *models.CreateDocument
 		Order:            order,
 		CreatedAt:        now,
 		UpdatedAt:        now,
+		EffectiveDate:    req.EffectiveDate,
+		SupersessionDate: req.SupersessionDate,
+		Classification:   req.Classification,
 	}
 
 	_, err = s.collection.InsertOne(ctx, document)
@@ -248,7 +357,7 @@ func (s *DocumentService) Create(ctx context.Context, req *models.CreateDocument
 	}
 
 	// Create initial version
-	err = s.createVersion(ctx, document, userID, "Initial version")
+	err = s.CreateVersion(ctx, document, userID, "Initial version")
 	if err != nil {
 		// Log error but don't fail the creation
 		fmt.Printf("Failed to create initial version: %v\n", err)
@@ -262,10 +371,58 @@ func (s *DocumentService) Create(ctx context.Context, req *models.CreateDocument
 	return document, nil
 }
 
+// applyContributorTemplate adds the default verifiers and validators of a
+// department to the teams left empty, or to every team when the template is enforced
+func (s *DocumentService) applyContributorTemplate(ctx context.Context, contributors *models.Contributors, departmentID primitive.ObjectID) error {
+	defaults, template, err := s.templateService.Resolve(ctx, departmentID)
+	if err != nil {
+		return err
+	}
+	if template == nil {
+		return nil
+	}
+
+	merge := func(team, defaults []models.Contributor) []models.Contributor {
+		if len(team) > 0 && !template.Enforced {
+			return team
+		}
+		for _, contributor := range defaults {
+			if !slices.ContainsFunc(team, func(c models.Contributor) bool { return c.UserID == contributor.UserID }) {
+				team = append(team, contributor)
+			}
+		}
+		return team
+	}
+	contributors.Verifiers = merge(contributors.Verifiers, defaults.Verifiers)
+	contributors.Validators = merge(contributors.Validators, defaults.Validators)
+
+	return nil
+}
+
+// checkProcessCode validates a process code given manually for a process of the macro
+func (s *DocumentService) checkProcessCode(ctx context.Context, macroID primitive.ObjectID, processCode string, exclude *primitive.ObjectID) error {
+	macro, err := s.macroService.GetMacroByID(ctx, macroID)
+	if err != nil {
+		return fmt.Errorf("failed to get macro: %w", err)
+	}
+	if !s.macroService.ValidateProcessCode(processCode) || !strings.HasPrefix(processCode, macro.Code+"_P") {
+		return fmt.Errorf("invalid process code '%s' (expected format: %s_P1)", processCode, macro.Code)
+	}
+
+	exists, err := s.macroService.ProcessCodeExists(ctx, macroID, processCode, exclude)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("process code already exists in this macro")
+	}
+	return nil
+}
+
 // GetByID retrieves a document by ID
 func (s *DocumentService) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Document, error) {
 	var document models.Document
-	err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&document)
+	err := s.collection.FindOne(ctx, models.NotDeleted(bson.M{"_id": id})).Decode(&document)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, errors.New("document not found")
@@ -279,7 +436,7 @@ func (s *DocumentService) GetByID(ctx context.Context, id primitive.ObjectID) (*
 // GetByReference retrieves a document by reference
 func (s *DocumentService) GetByReference(ctx context.Context, reference string) (*models.Document, error) {
 	var document models.Document
-	err := s.collection.FindOne(ctx, bson.M{"reference": reference}).Decode(&document)
+	err := s.collection.FindOne(ctx, models.NotDeleted(bson.M{"reference": reference})).Decode(&document)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, errors.New("document not found")
@@ -292,26 +449,9 @@ func (s *DocumentService) GetByReference(ctx context.Context, reference string)
 
 // List retrieves documents with filtering and pagination
 func (s *DocumentService) List(ctx context.Context, filter *models.DocumentFilter) ([]*models.Document, int64, error) {
-	// Build filter
-	query := bson.M{}
-
-	if filter.Status != nil {
-		query["status"] = *filter.Status
-	}
-
-	if filter.CreatedBy != nil {
-		createdByID, err := primitive.ObjectIDFromHex(*filter.CreatedBy)
-		if err != nil {
-			return nil, 0, errors.New("invalid createdBy ID")
-		}
-		query["created_by"] = createdByID
-	}
-
-	if filter.Search != nil && *filter.Search != "" {
-		query["$or"] = []bson.M{
-			{"title": bson.M{"$regex": *filter.Search, "$options": "i"}},
-			{"reference": bson.M{"$regex": *filter.Search, "$options": "i"}},
-		}
+	query, err := s.listQuery(ctx, filter, primitive.NilObjectID, models.RoleAdmin)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// Count total documents
@@ -332,20 +472,9 @@ func (s *DocumentService) List(ctx context.Context, filter *models.DocumentFilte
 	skip := (page - 1) * limit
 
 	// Find documents
-	findOptions := options.Find().
-		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
-		SetSkip(int64(skip)).
-		SetLimit(int64(limit))
-
-	cursor, err := s.collection.Find(ctx, query, findOptions)
+	documents, err := s.findSorted(ctx, query, filter.Sort, defaultDocumentSort, skip, limit)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to find documents: %w", err)
-	}
-	defer cursor.Close(ctx)
-
-	documents := make([]*models.Document, 0)
-	if err = cursor.All(ctx, &documents); err != nil {
-		return nil, 0, fmt.Errorf("failed to decode documents: %w", err)
+		return nil, 0, err
 	}
 
 	return documents, total, nil
@@ -363,28 +492,199 @@ func (s *DocumentService) ListUserAccessible(ctx context.Context, userID primiti
 		return s.List(ctx, filter)
 	}
 
-	// Build base filter
-	baseQuery := bson.M{}
+	finalQuery, err := s.listQuery(ctx, filter, userID, userRole)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Count total accessible documents
+	total, err := s.collection.CountDocuments(ctx, finalQuery)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	// Set pagination defaults
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := filter.Limit
+	if limit < 1 {
+		limit = 20
+	}
+	skip := (page - 1) * limit
+
+	// Find documents
+	documents, err := s.findSorted(ctx, finalQuery, filter.Sort, defaultDocumentSort, skip, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return documents, total, nil
+}
+
+// listQuery builds the query of a document list, restricted to the documents
+// the user can access unless they are an admin
+func (s *DocumentService) listQuery(ctx context.Context, filter *models.DocumentFilter, userID primitive.ObjectID, userRole models.UserRole) (bson.M, error) {
+	query := models.NotDeleted(bson.M{})
 
 	if filter.Status != nil {
-		baseQuery["status"] = *filter.Status
+		query["status"] = *filter.Status
 	}
 
 	if filter.CreatedBy != nil {
 		createdByID, err := primitive.ObjectIDFromHex(*filter.CreatedBy)
 		if err != nil {
-			return nil, 0, errors.New("invalid createdBy ID")
+			return nil, errors.New("invalid createdBy ID")
 		}
-		baseQuery["created_by"] = createdByID
+		query["created_by"] = createdByID
 	}
 
 	if filter.Search != nil && *filter.Search != "" {
-		baseQuery["$or"] = []bson.M{
+		query["$or"] = []bson.M{
 			{"title": bson.M{"$regex": *filter.Search, "$options": "i"}},
 			{"reference": bson.M{"$regex": *filter.Search, "$options": "i"}},
+			{"process_code": bson.M{"$regex": *filter.Search, "$options": "i"}},
+		}
+	}
+
+	if userRole == models.RoleAdmin {
+		return query, nil
+	}
+
+	// Combine base filter with access query
+	return bson.M{
+		"$and": []bson.M{
+			query,
+			s.accessQuery(ctx, userID),
+		},
+	}, nil
+}
+
+// defaultDocumentSort lists the most recently updated documents first
+var defaultDocumentSort = bson.D{{Key: "updated_at", Value: -1}}
+
+// librarySort lists the library by reference, the latest version first
+var librarySort = bson.D{{Key: "reference", Value: 1}, {Key: "effective_date", Value: -1}}
+
+// documentSortFields maps the sort keys of the document lists to their field
+var documentSortFields = map[models.DocumentSortField]string{
+	models.DocumentSortUpdatedAt:         "updated_at",
+	models.DocumentSortCreatedAt:         "created_at",
+	models.DocumentSortTitle:             "title",
+	models.DocumentSortReference:         "reference",
+	models.DocumentSortStatus:            "status",
+	models.DocumentSortSignatureProgress: "signature_progress",
+}
+
+// documentSortKeys returns the sort keys of a document list, fallback when no
+// sort is requested. Ties are broken by the most recent update, then by ID,
+// so that pages stay stable.
+func documentSortKeys(sort *models.DocumentSort, fallback bson.D) bson.D {
+	if sort == nil {
+		return append(append(bson.D{}, fallback...), bson.E{Key: "_id", Value: -1})
+	}
+
+	direction := 1
+	if sort.Descending {
+		direction = -1
+	}
+	field := documentSortFields[sort.Field]
+	keys := bson.D{{Key: field, Value: direction}}
+	if field != "updated_at" {
+		keys = append(keys, bson.E{Key: "updated_at", Value: -1})
+	}
+	return append(keys, bson.E{Key: "_id", Value: -1})
+}
+
+// signatureProgressExpr computes the share of the contributors of a document
+// who signed it, 0 for a document without contributors
+var signatureProgressExpr = bson.M{"$let": bson.M{
+	"vars": bson.M{"contributors": bson.M{"$concatArrays": bson.A{
+		bson.M{"$ifNull": bson.A{"$contributors.authors", bson.A{}}},
+		bson.M{"$ifNull": bson.A{"$contributors.verifiers", bson.A{}}},
+		bson.M{"$ifNull": bson.A{"$contributors.validators", bson.A{}}},
+	}}},
+	"in": bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$size": "$$contributors"}, 0}},
+		0,
+		bson.M{"$divide": bson.A{
+			bson.M{"$size": bson.M{"$filter": bson.M{
+				"input": "$$contributors",
+				"cond":  bson.M{"$eq": bson.A{"$$this.status", models.SignatureStatusSigned}},
+			}}},
+			bson.M{"$size": "$$contributors"},
+		}},
+	}},
+}}
+
+// findSorted returns a page of the documents matching query. Signature
+// progress is not stored, so sorting on it goes through an aggregation.
+func (s *DocumentService) findSorted(ctx context.Context, query bson.M, sort *models.DocumentSort, fallback bson.D, skip, limit int) ([]*models.Document, error) {
+	keys := documentSortKeys(sort, fallback)
+
+	var cursor *mongo.Cursor
+	var err error
+	if sort != nil && sort.Field == models.DocumentSortSignatureProgress {
+		cursor, err = s.collection.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: query}},
+			{{Key: "$addFields", Value: bson.M{"signature_progress": signatureProgressExpr}}},
+			{{Key: "$sort", Value: keys}},
+			{{Key: "$skip", Value: skip}},
+			{{Key: "$limit", Value: limit}},
+			{{Key: "$unset", Value: "signature_progress"}},
+		})
+	} else {
+		cursor, err = s.collection.Find(ctx, query, options.Find().
+			SetSort(keys).
+			SetSkip(int64(skip)).
+			SetLimit(int64(limit)))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	documents := make([]*models.Document, 0)
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode documents: %w", err)
+	}
+	return documents, nil
+}
+
+// StreamUserAccessible calls fn for every document of the list the user can
+// access, reading them from a cursor instead of loading the whole list
+func (s *DocumentService) StreamUserAccessible(ctx context.Context, userID primitive.ObjectID, userRole models.UserRole, filter *models.DocumentFilter, fn func(*models.Document) error) error {
+	query, err := s.listQuery(ctx, filter, userID, userRole)
+	if err != nil {
+		return err
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetBatchSize(exportBatchSize).
+		SetProjection(bson.M{"process_groups": 0, "annexes": 0, "tasks": 0}) // Content is not exported
+	cursor, err := s.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return fmt.Errorf("failed to find documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var document models.Document
+		if err := cursor.Decode(&document); err != nil {
+			return fmt.Errorf("failed to decode document: %w", err)
+		}
+		if err := fn(&document); err != nil {
+			return err
 		}
 	}
+	return cursor.Err()
+}
 
+// accessQuery matches the documents a user can access: the ones they created,
+// contribute to or were invited to, and the published ones
+func (s *DocumentService) accessQuery(ctx context.Context, userID primitive.ObjectID) bson.M {
 	// Get documents where user has accepted invitations
 	invitedDocIDs := []primitive.ObjectID{}
 	invCursor, err := s.invitationCollection.Find(ctx, bson.M{
@@ -408,8 +708,8 @@ func (s *DocumentService) ListUserAccessible(ctx context.Context, userID primiti
 			{"contributors.authors.user_id": userID},    // User is author
 			{"contributors.verifiers.user_id": userID},  // User is verifier
 			{"contributors.validators.user_id": userID}, // User is validator
-			// Public documents (Approved or Archived) are accessible to all authenticated users
-			{"status": bson.M{"$in": []models.DocumentStatus{models.DocumentStatusApproved, models.DocumentStatusArchived}}},
+			// Published documents (Approved, Archived or Review due) are accessible to all authenticated users
+			{"status": bson.M{"$in": models.PublishedDocumentStatuses}},
 		},
 	}
 
@@ -420,65 +720,82 @@ func (s *DocumentService) ListUserAccessible(ctx context.Context, userID primiti
 		})
 	}
 
-	// Combine base filter with access query
-	finalQuery := bson.M{
-		"$and": []bson.M{
-			baseQuery,
-			accessQuery,
-		},
-	}
+	return accessQuery
+}
 
-	// Count total accessible documents
-	total, err := s.collection.CountDocuments(ctx, finalQuery)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count documents: %w", err)
+// GetAccessibleByIDs returns the documents among the given IDs that the user
+// can access, keyed by ID. Trashed documents are left out.
+func (s *DocumentService) GetAccessibleByIDs(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, userRole models.UserRole) (map[primitive.ObjectID]*models.Document, error) {
+	documents := make(map[primitive.ObjectID]*models.Document, len(ids))
+	if len(ids) == 0 {
+		return documents, nil
 	}
 
-	// Set pagination defaults
-	page := filter.Page
-	if page < 1 {
-		page = 1
-	}
-	limit := filter.Limit
-	if limit < 1 {
-		limit = 20
+	query := models.NotDeleted(bson.M{"_id": bson.M{"$in": ids}})
+	if userRole != models.RoleAdmin {
+		query = bson.M{"$and": []bson.M{query, s.accessQuery(ctx, userID)}}
 	}
-	skip := (page - 1) * limit
-
-	// Find documents
-	findOptions := options.Find().
-		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
-		SetSkip(int64(skip)).
-		SetLimit(int64(limit))
 
-	cursor, err := s.collection.Find(ctx, finalQuery, findOptions)
+	cursor, err := s.collection.Find(ctx, query)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to find documents: %w", err)
+		return nil, fmt.Errorf("failed to find documents: %w", err)
 	}
 	defer cursor.Close(ctx)
 
-	documents := make([]*models.Document, 0)
-	if err = cursor.All(ctx, &documents); err != nil {
-		return nil, 0, fmt.Errorf("failed to decode documents: %w", err)
+	var found []*models.Document
+	if err = cursor.All(ctx, &found); err != nil {
+		return nil, fmt.Errorf("failed to decode documents: %w", err)
+	}
+	for _, document := range found {
+		documents[document.ID] = document
 	}
 
-	return documents, total, nil
+	return documents, nil
 }
 
 // Update updates a document
-func (s *DocumentService) Update(ctx context.Context, id primitive.ObjectID, req *models.UpdateDocumentRequest, userID primitive.ObjectID) (*models.Document, error) {
+func (s *DocumentService) Update(ctx context.Context, id primitive.ObjectID, req *models.UpdateDocumentRequest, userID primitive.ObjectID, userRole models.UserRole) (*models.Document, error) {
 	// Get existing document
 	document, err := s.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Document locking: Prevent editing approved or archived documents
+	// Document locking: Prevent editing published documents
 	// Only allow draft and review statuses to be edited
-	if document.Status == models.DocumentStatusApproved || document.Status == models.DocumentStatusArchived {
+	if document.Status.IsPublished() {
 		return nil, fmt.Errorf("cannot modify document in '%s' status - document is locked", document.Status)
 	}
 
+	// Section locks: every locked section touched by the update is reported
+	sectionFields := []struct {
+		field   string
+		section models.DocumentSection
+		set     bool
+	}{
+		{"metadata", models.DocumentSectionMetadata, req.Metadata != nil},
+		{"processGroups", models.DocumentSectionProcessGroups, req.ProcessGroups != nil},
+		{"annexes", models.DocumentSectionAnnexes, req.Annexes != nil},
+	}
+	var denied []models.FieldAuthorizationError
+	for _, f := range sectionFields {
+		if f.set && !document.CanEditSection(f.section, userID, userRole) {
+			denied = append(denied, sectionLockedError(f.field, f.section))
+		}
+	}
+	if len(denied) > 0 {
+		return nil, &models.SectionAccessError{Fields: denied}
+	}
+
+	// Optimistic locking: reject changes based on an outdated revision
+	filter := bson.M{"_id": id}
+	if req.Revision != nil {
+		if *req.Revision != document.Revision {
+			return nil, ErrDocumentRevisionConflict
+		}
+		filter["revision"] = revisionFilter(*req.Revision)
+	}
+
 	// Build update fields
 	update := bson.M{
 		"updated_at": time.Now(),
@@ -523,32 +840,90 @@ func (s *DocumentService) Update(ctx context.Context, id primitive.ObjectID, req
 		update["contributors"] = *req.Contributors
 	}
 	if req.Metadata != nil {
+		if req.Metadata.CustomSections != nil {
+			sections, err := s.normalizeCustomSections(ctx, document.MacroID, req.Metadata.CustomSections)
+			if err != nil {
+				return nil, err
+			}
+			req.Metadata.CustomSections = sections
+		}
+		actors, err := s.normalizeActors(ctx, req.Metadata.ImplicatedActors)
+		if err != nil {
+			return nil, err
+		}
+		req.Metadata.ImplicatedActors = actors
 		update["metadata"] = *req.Metadata
 	}
 	if req.ProcessGroups != nil {
+		if err := s.normalizeStepResponsibles(ctx, *req.ProcessGroups); err != nil {
+			return nil, err
+		}
 		update["process_groups"] = *req.ProcessGroups
 	}
+	if req.References != nil {
+		references, err := s.resolveReferences(ctx, id, *req.References)
+		if err != nil {
+			return nil, err
+		}
+		update["references"] = references
+	}
 	if req.Annexes != nil {
 		update["annexes"] = *req.Annexes
 	}
+	if req.EffectiveDate != nil || req.SupersessionDate != nil {
+		effectiveDate, supersessionDate := document.EffectiveDate, document.SupersessionDate
+		if req.EffectiveDate != nil {
+			effectiveDate = req.EffectiveDate
+			update["effective_date"] = *req.EffectiveDate
+		}
+		if req.SupersessionDate != nil {
+			supersessionDate = req.SupersessionDate
+			update["supersession_date"] = *req.SupersessionDate
+		}
+		if err := validateEffectiveDates(effectiveDate, supersessionDate); err != nil {
+			return nil, err
+		}
+	}
+	// The stored PDF no longer matches the updated content
+	changes := models.InvalidatePDF(bson.M{"$set": update, "$inc": bson.M{"revision": 1}})
+	if req.Classification != nil && *req.Classification != document.Classification {
+		if !models.IsValidDocumentClassification(*req.Classification) {
+			return nil, ErrInvalidClassification
+		}
+		update["classification"] = *req.Classification
+	}
+	if req.PageLayout != nil {
+		if err := req.PageLayout.Validate(); err != nil {
+			return nil, err
+		}
+		if *req.PageLayout == (models.PDFPageLayout{}) {
+			changes["$unset"].(bson.M)["page_layout"] = ""
+		} else {
+			update["page_layout"] = *req.PageLayout
+		}
+	}
 
 	// Update document
 	result := s.collection.FindOneAndUpdate(
 		ctx,
-		bson.M{"_id": id},
-		bson.M{"$set": update},
+		filter,
+		changes,
 		options.FindOneAndUpdate().SetReturnDocument(options.After),
 	)
 
 	var updatedDocument models.Document
 	if err := result.Decode(&updatedDocument); err != nil {
+		// The document was modified between the read and the write
+		if err == mongo.ErrNoDocuments && req.Revision != nil {
+			return nil, ErrDocumentRevisionConflict
+		}
 		return nil, fmt.Errorf("failed to update document: %w", err)
 	}
 
 	// Create version if version number changed
 	if req.Version != nil && *req.Version != document.Version {
 		changeNote := fmt.Sprintf("Updated to version %s", *req.Version)
-		err = s.createVersion(ctx, &updatedDocument, userID, changeNote)
+		err = s.CreateVersion(ctx, &updatedDocument, userID, changeNote)
 		if err != nil {
 			fmt.Printf("Failed to create version: %v\n", err)
 		}
@@ -562,68 +937,72 @@ func (s *DocumentService) Update(ctx context.Context, id primitive.ObjectID, req
 	return &updatedDocument, nil
 }
 
-// Publish publishes a document for signature
-// Sets all contributors with 'joined' status to 'pending' signature
-// Changes document status to 'author_review'
-func (s *DocumentService) Publish(ctx context.Context, id primitive.ObjectID) (*models.Document, error) {
+// Publish publishes a document for signature, to the next stage of its
+// workflow. The contributors of the team of the stage with 'joined' status
+// are set to 'pending' signature. Approved documents are published to the
+// organization.
+func (s *DocumentService) Publish(ctx context.Context, id primitive.ObjectID, deadlines *models.ApprovalDeadlines) (*models.Document, error) {
 	// Get existing document
 	document, err := s.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	now := time.Now()
-	var newStatus models.DocumentStatus
-
-	// Determine next status based on current status
-	switch document.Status {
-	case models.DocumentStatusDraft:
-		// Publish for author signatures
-		newStatus = models.DocumentStatusAuthorReview
-		// Update authors with 'joined' status to 'pending'
-		for i := range document.Contributors.Authors {
-			if document.Contributors.Authors[i].Status == models.SignatureStatusJoined {
-				document.Contributors.Authors[i].Status = models.SignatureStatusPending
-			}
-		}
+	workflow, err := s.EffectiveWorkflow(ctx, document)
+	if err != nil {
+		return nil, err
+	}
 
-	case models.DocumentStatusAuthorSigned:
-		// All authors have signed, publish for verifiers
-		newStatus = models.DocumentStatusVerifierReview
-		// Update verifiers with 'joined' status to 'pending'
-		for i := range document.Contributors.Verifiers {
-			if document.Contributors.Verifiers[i].Status == models.SignatureStatusJoined {
-				document.Contributors.Verifiers[i].Status = models.SignatureStatusPending
-			}
-		}
+	now := time.Now()
 
-	case models.DocumentStatusVerifierSigned:
-		// All verifiers have signed, publish for validators
-		newStatus = models.DocumentStatusValidatorReview
-		// Update validators with 'joined' status to 'pending'
-		for i := range document.Contributors.Validators {
-			if document.Contributors.Validators[i].Status == models.SignatureStatusJoined {
-				document.Contributors.Validators[i].Status = models.SignatureStatusPending
+	// Determine next status based on current status and the workflow
+	newStatus, stage, err := workflow.Workflow.PublishTransition(document)
+	if err != nil {
+		return nil, err
+	}
+	if stage != nil {
+		// Update the signers of the stage with 'joined' status to 'pending'
+		signers := document.Contributors.Team(stage.Team)
+		for i := range signers {
+			if signers[i].Status == models.SignatureStatusJoined {
+				signers[i].Status = models.SignatureStatusPending
 			}
 		}
-
+	}
+	switch newStatus {
 	case models.DocumentStatusApproved:
+		// No stage left to sign, the document is approved
+		document.ApprovedAt = &now
+	case models.DocumentStatusArchived:
 		// Publish approved document to organization (archive it as final version)
-		newStatus = models.DocumentStatusArchived
 		fmt.Printf("📢 [PUBLISH] Publishing approved document to organization\n")
-
-	default:
-		return nil, fmt.Errorf("document cannot be published from status: %s", document.Status)
 	}
 
 	// Update document status and timestamp
 	document.Status = newStatus
 	document.UpdatedAt = now
 
+	// Start the signature deadline of the team the document is published to
+	if deadlines != nil {
+		document.Deadlines = deadlines
+	}
+	document.StageDeadline = NewStageDeadline(document, newStatus, now)
+
+	// Schedule the periodic review of the version published to the organization
+	if newStatus == models.DocumentStatusArchived {
+		nextReview := NextReviewDate(now)
+		document.NextReviewDate = &nextReview
+		document.ScheduledPublishAt = nil
+		document.ScheduledPublishBy = nil
+		if document.EffectiveDate == nil {
+			document.EffectiveDate = &now
+		}
+	}
+
 	// Generate and upload PDF if archiving approved document
 	if newStatus == models.DocumentStatusArchived && s.pdfService != nil {
 		fmt.Printf("📄 [PUBLISH] Generating PDF for archived document...\n")
-		pdfURL, err := s.pdfService.GenerateDocumentPDF(ctx, document)
+		pdfURL, err := s.pdfService.GenerateDocumentPDF(ctx, document, models.PDFExportOptions{})
 		if err != nil {
 			fmt.Printf("⚠️ [PUBLISH] Failed to generate PDF: %v\n", err)
 			// Don't fail the entire publish operation if PDF generation fails
@@ -635,6 +1014,8 @@ func (s *DocumentService) Publish(ctx context.Context, id primitive.ObjectID) (*
 		}
 	}
 
+	document.Revision++
+
 	// Replace the entire document to avoid validation issues
 	_, err = s.collection.ReplaceOne(
 		ctx,
@@ -646,6 +1027,13 @@ func (s *DocumentService) Publish(ctx context.Context, id primitive.ObjectID) (*
 		return nil, fmt.Errorf("failed to publish document: %w", err)
 	}
 
+	// A revision published to the organization replaces the version it revises
+	if newStatus == models.DocumentStatusArchived && document.Supersedes != nil {
+		if err := s.supersede(ctx, *document.Supersedes, document, now); err != nil {
+			fmt.Printf("⚠️ [PUBLISH] Failed to mark revised document as superseded: %v\n", err)
+		}
+	}
+
 	// Trigger documentation update
 	if s.documentationService != nil {
 		s.documentationService.TriggerUpdate()
@@ -654,41 +1042,318 @@ func (s *DocumentService) Publish(ctx context.Context, id primitive.ObjectID) (*
 	return document, nil
 }
 
-// ExportPDF generates and exports the document as PDF
-// If PDF already exists, returns the existing URL
-// If not, generates a new PDF and stores the URL
-func (s *DocumentService) ExportPDF(ctx context.Context, id primitive.ObjectID) (string, error) {
-	// Get existing document
+// AttachToMacro moves a document to a macro. The document receives the next
+// process code of the macro and its task codes are renumbered accordingly.
+func (s *DocumentService) AttachToMacro(ctx context.Context, id, macroID primitive.ObjectID) (*models.Document, error) {
 	document, err := s.GetByID(ctx, id)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	// If PDF already exists, return the URL
-	if document.PdfUrl != "" {
-		fmt.Printf("📄 [EXPORT] PDF already exists for document %s: %s\n", document.Reference, document.PdfUrl)
-		return document.PdfUrl, nil
+	if document.MacroID != nil && *document.MacroID == macroID {
+		return document, nil
 	}
 
-	// Generate PDF if service is available
-	if s.pdfService == nil {
-		return "", fmt.Errorf("PDF service not available")
+	// Document locking: Published documents keep their code
+	if document.Status.IsPublished() {
+		return nil, fmt.Errorf("cannot move document in '%s' status - document is locked", document.Status)
 	}
 
-	fmt.Printf("📄 [EXPORT] Generating new PDF for document: %s (%s)\n", document.Title, document.Reference)
-	pdfURL, err := s.pdfService.GenerateDocumentPDF(ctx, document)
+	processCode, err := s.macroService.NextProcessCode(ctx, macroID)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.macroService.GetProcessCountByMacroID(ctx, macroID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process count: %w", err)
+	}
+
+	tasks := make([]models.Task, len(document.Tasks))
+	for i, task := range document.Tasks {
+		if document.ProcessCode != "" && strings.HasPrefix(task.Code, document.ProcessCode+"_") {
+			task.Code = processCode + strings.TrimPrefix(task.Code, document.ProcessCode)
+		}
+		tasks[i] = task
+	}
+
+	update := bson.M{
+		"macro_id":     macroID,
+		"process_code": processCode,
+		"order":        int(count) + 1,
+		"tasks":        tasks,
+		"updated_at":   time.Now(),
+	}
+	// A reference derived from the former code follows the new one
+	if document.ProcessCode != "" && document.Reference == document.ProcessCode {
+		exists, err := s.referenceExists(ctx, processCode)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			update["reference"] = processCode
+		}
+	}
+
+	var updated models.Document
+	err = s.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "revision": revisionFilter(document.Revision)},
+		models.InvalidatePDF(bson.M{"$set": update, "$inc": bson.M{"revision": 1}}),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrDocumentRevisionConflict
+		}
+		return nil, fmt.Errorf("failed to attach document to macro: %w", err)
+	}
+
+	if s.documentationService != nil {
+		s.documentationService.TriggerUpdate()
+	}
+
+	return &updated, nil
+}
+
+// SchedulePublish defers the publication of an approved document to the given date
+func (s *DocumentService) SchedulePublish(ctx context.Context, id primitive.ObjectID, publishAt time.Time, userID primitive.ObjectID) (*models.Document, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if document.Status != models.DocumentStatusApproved {
+		return nil, fmt.Errorf("only approved documents can be scheduled for publication, current status: %s", document.Status)
+	}
+
+	now := time.Now()
+	if !publishAt.After(now) {
+		return nil, errors.New("scheduled publication date must be in the future")
+	}
+
+	result, err := s.collection.UpdateOne(ctx,
+		models.NotDeleted(bson.M{"_id": id, "status": models.DocumentStatusApproved}),
+		bson.M{
+			"$set": bson.M{
+				"scheduled_publish_at": publishAt,
+				"scheduled_publish_by": userID,
+				"updated_at":           now,
+			},
+			"$inc": bson.M{"revision": 1},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule publication: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("document not found")
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// CancelScheduledPublish removes the scheduled publication of a document
+func (s *DocumentService) CancelScheduledPublish(ctx context.Context, id primitive.ObjectID) (*models.Document, error) {
+	result, err := s.collection.UpdateOne(ctx,
+		models.NotDeleted(bson.M{"_id": id, "scheduled_publish_at": bson.M{"$exists": true}}),
+		bson.M{
+			"$unset": bson.M{"scheduled_publish_at": "", "scheduled_publish_by": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+			"$inc":   bson.M{"revision": 1},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel scheduled publication: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		if _, err := s.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("document has no scheduled publication")
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// ListDueScheduledPublications returns the approved documents whose publication date has passed
+func (s *DocumentService) ListDueScheduledPublications(ctx context.Context, now time.Time) ([]*models.Document, error) {
+	cursor, err := s.collection.Find(ctx,
+		models.NotDeleted(bson.M{
+			"status":               models.DocumentStatusApproved,
+			"scheduled_publish_at": bson.M{"$lte": now},
+		}),
+		options.Find().SetSort(bson.D{{Key: "scheduled_publish_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find scheduled publications: %w", err)
+	}
+
+	var documents []*models.Document
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode scheduled publications: %w", err)
+	}
+	return documents, nil
+}
+
+// UpdateEffectiveDates replaces the transition period of a document, whatever its status
+func (s *DocumentService) UpdateEffectiveDates(ctx context.Context, id primitive.ObjectID, req *models.UpdateEffectiveDatesRequest) (*models.Document, error) {
+	if err := validateEffectiveDates(req.EffectiveDate, req.SupersessionDate); err != nil {
+		return nil, err
+	}
+
+	set := bson.M{"updated_at": time.Now()}
+	unset := bson.M{}
+	if req.EffectiveDate != nil {
+		set["effective_date"] = *req.EffectiveDate
+	} else {
+		unset["effective_date"] = ""
+	}
+	if req.SupersessionDate != nil {
+		set["supersession_date"] = *req.SupersessionDate
+	} else {
+		unset["supersession_date"] = ""
+	}
+
+	update := bson.M{"$set": set, "$inc": bson.M{"revision": 1}}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	// The PDF title table shows the dates
+	models.InvalidatePDF(update)
+
+	var document models.Document
+	err := s.collection.FindOneAndUpdate(ctx,
+		models.NotDeleted(bson.M{"_id": id}),
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&document)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("document not found")
+		}
+		return nil, fmt.Errorf("failed to update effective dates: %w", err)
+	}
+
+	return &document, nil
+}
+
+// ListLibrary retrieves the documents published to the organization, optionally
+// restricted to one availability badge. Documents are sorted by reference
+// unless another sort is requested.
+func (s *DocumentService) ListLibrary(ctx context.Context, availability *models.DocumentAvailability, search string, sort *models.DocumentSort, page, limit int) ([]*models.Document, int64, error) {
+	query := models.NotDeleted(bson.M{
+		"status": bson.M{"$in": models.PublishedDocumentStatuses},
+	})
+	conditions := make([]bson.M, 0, 2)
+
+	if availability != nil {
+		conditions = append(conditions, availabilityQuery(*availability, time.Now()))
+	}
+	if search != "" {
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"title": bson.M{"$regex": search, "$options": "i"}},
+			{"reference": bson.M{"$regex": search, "$options": "i"}},
+			{"process_code": bson.M{"$regex": search, "$options": "i"}},
+		}})
+	}
+	if len(conditions) > 0 {
+		query["$and"] = conditions
+	}
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	documents, err := s.findSorted(ctx, query, sort, librarySort, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return documents, total, nil
+}
+
+// availabilityQuery matches the published documents with the given badge, the
+// query counterpart of Document.Availability
+func availabilityQuery(availability models.DocumentAvailability, now time.Time) bson.M {
+	upcoming := []bson.M{
+		{"status": models.DocumentStatusApproved},
+		{"effective_date": bson.M{"$gt": now}},
+	}
+	expired := []bson.M{
+		{"supersession_date": bson.M{"$lte": now}},
+		{"supersession_date": bson.M{"$exists": false}, "status": models.DocumentStatusSuperseded},
+	}
+
+	switch availability {
+	case models.DocumentAvailabilityUpcoming:
+		return bson.M{"$or": upcoming}
+	case models.DocumentAvailabilityExpired:
+		return bson.M{"$nor": upcoming, "$or": expired}
+	default:
+		return bson.M{"$nor": append(upcoming, expired...)}
+	}
+}
+
+// validateEffectiveDates checks that a transition period ends after it starts
+func validateEffectiveDates(effectiveDate, supersessionDate *time.Time) error {
+	if effectiveDate != nil && supersessionDate != nil && !supersessionDate.After(*effectiveDate) {
+		return ErrInvalidEffectiveDates
+	}
+	return nil
+}
+
+// ExportPDF generates and exports the document as PDF
+// If PDF already exists, returns the existing URL unless a regeneration is forced
+// If not, generates a new PDF and stores the URL, keeping the previous ones in
+// the PDF history of the document
+// A watermarked draft, an archival PDF/A file or a PDF on another paper than
+// the one of the document is generated on every export and never stored
+func (s *DocumentService) ExportPDF(ctx context.Context, id primitive.ObjectID, opts models.PDFExportOptions) (string, error) {
+	// Get existing document
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	watermarked := opts.Watermark && !document.Status.IsPublished()
+	oneOff := watermarked || opts.Archival || opts.PageLayout != nil
+
+	// If PDF already exists, return the URL
+	if document.PdfUrl != "" && !oneOff && !opts.Force {
+		fmt.Printf("📄 [EXPORT] PDF already exists for document %s: %s\n", document.Reference, document.PdfUrl)
+		return document.PdfUrl, s.checkPDFExport(ctx, document, document.PdfUrl, opts)
+	}
+
+	// Generate PDF if service is available
+	if s.pdfService == nil {
+		return "", fmt.Errorf("PDF service not available")
+	}
+
+	fmt.Printf("📄 [EXPORT] Generating new PDF for document: %s (%s)\n", document.Title, document.Reference)
+	pdfURL, err := s.pdfService.GenerateDocumentPDF(ctx, document, opts)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate PDF: %w", err)
 	}
+	if oneOff {
+		fmt.Printf("✅ [EXPORT] One-off PDF generated: %s\n", pdfURL)
+		return pdfURL, s.checkPDFExport(ctx, document, pdfURL, opts)
+	}
 
 	// Store PDF URL in document
+	now := time.Now()
+	generated := models.GeneratedPDF{
+		Version:     document.Version,
+		Revision:    document.Revision,
+		URL:         pdfURL,
+		GeneratedAt: now,
+	}
 	_, err = s.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": id},
 		bson.M{
 			"$set": bson.M{
 				"pdf_url":    pdfURL,
-				"updated_at": time.Now(),
+				"updated_at": now,
+			},
+			"$push": bson.M{
+				"pdf_history": bson.M{"$each": []models.GeneratedPDF{generated}, "$slice": -maxPDFHistory},
 			},
 		},
 	)
@@ -698,7 +1363,226 @@ func (s *DocumentService) ExportPDF(ctx context.Context, id primitive.ObjectID)
 	}
 
 	fmt.Printf("✅ [EXPORT] PDF generated and stored successfully: %s\n", pdfURL)
-	return pdfURL, nil
+	return pdfURL, s.checkPDFExport(ctx, document, pdfURL, opts)
+}
+
+// maxPDFHistory bounds the number of generated PDFs kept in the history of a document
+const maxPDFHistory = 50
+
+// PDFHistory returns the PDFs generated for a document, the most recent first
+func (s *DocumentService) PDFHistory(ctx context.Context, id primitive.ObjectID) ([]models.GeneratedPDF, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]models.GeneratedPDF, 0, len(document.PDFHistory))
+	for i := len(document.PDFHistory) - 1; i >= 0; i-- {
+		history = append(history, document.PDFHistory[i])
+	}
+	return history, nil
+}
+
+// maxPDFRegenerationDocuments bounds the number of PDFs regenerated by one batch
+const maxPDFRegenerationDocuments = 1000
+
+// ListPDFRegenerationCandidates returns the documents with a stored PDF
+// matching the filters of a batch regeneration, sorted by reference
+func (s *DocumentService) ListPDFRegenerationCandidates(ctx context.Context, req *models.RegeneratePDFsRequest) ([]*models.Document, error) {
+	query := bson.M{"pdf_url": bson.M{"$exists": true, "$ne": ""}}
+	if len(req.Statuses) > 0 {
+		query["status"] = bson.M{"$in": req.Statuses}
+	}
+	if req.UpdatedFrom != nil || req.UpdatedTo != nil {
+		if req.UpdatedFrom != nil && req.UpdatedTo != nil && !req.UpdatedTo.After(*req.UpdatedFrom) {
+			return nil, errors.New("invalid date range: updatedTo must be after updatedFrom")
+		}
+		updatedAt := bson.M{}
+		if req.UpdatedFrom != nil {
+			updatedAt["$gte"] = *req.UpdatedFrom
+		}
+		if req.UpdatedTo != nil {
+			updatedAt["$lt"] = *req.UpdatedTo
+		}
+		query["updated_at"] = updatedAt
+	}
+	query = models.NotDeleted(query)
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+	if total > maxPDFRegenerationDocuments {
+		return nil, fmt.Errorf("too many documents to regenerate (%d), narrow the filters to at most %d documents", total, maxPDFRegenerationDocuments)
+	}
+
+	cursor, err := s.collection.Find(ctx, query, options.Find().
+		SetSort(bson.D{{Key: "reference", Value: 1}}).
+		SetProjection(bson.M{"_id": 1, "reference": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	documents := make([]*models.Document, 0)
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode documents: %w", err)
+	}
+	return documents, nil
+}
+
+// checkPDFExport runs the export hooks on the PDF of a document before its
+// link is served
+func (s *DocumentService) checkPDFExport(ctx context.Context, document *models.Document, pdfURL string, opts models.PDFExportOptions) error {
+	return s.exportHooks.Check(ctx, &models.OutboundExport{
+		Kind:        models.OutboundExportDocumentPDF,
+		URL:         pdfURL,
+		Documents:   []*models.Document{document},
+		RequestedBy: opts.RequestedBy,
+	})
+}
+
+// maxBulkExportDocuments bounds the number of PDFs generated by one bulk export
+const maxBulkExportDocuments = 200
+
+// ExportBundle generates the PDFs of the selected documents the user can access,
+// bundles them into a ZIP archive stored in MinIO and returns its download URL
+func (s *DocumentService) ExportBundle(ctx context.Context, req *models.BulkExportRequest, userID primitive.ObjectID, userRole models.UserRole) (*models.BulkExportResponse, error) {
+	if s.pdfService == nil {
+		return nil, fmt.Errorf("PDF service not available")
+	}
+
+	query, err := s.bulkExportQuery(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if userRole != models.RoleAdmin {
+		query = bson.M{"$and": []bson.M{query, s.accessQuery(ctx, userID)}}
+	}
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+	if total == 0 {
+		return nil, errors.New("no documents match the export criteria")
+	}
+	if total > maxBulkExportDocuments {
+		return nil, fmt.Errorf("too many documents to export (%d), narrow the criteria to at most %d documents", total, maxBulkExportDocuments)
+	}
+
+	cursor, err := s.collection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "reference", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	documents := make([]*models.Document, 0)
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode documents: %w", err)
+	}
+
+	skipped := make([]models.BulkExportSkipped, 0)
+	if len(req.DocumentIDs) > 0 {
+		// Report the requested documents that were not found or are not accessible
+		found := make(map[string]bool, len(documents))
+		for _, document := range documents {
+			found[document.ID.Hex()] = true
+		}
+		for _, id := range req.DocumentIDs {
+			if !found[id] {
+				skipped = append(skipped, models.BulkExportSkipped{DocumentID: id, Reason: "document not found"})
+			}
+		}
+	}
+
+	fileName := fmt.Sprintf("documents_%s.zip", time.Now().Format("20060102_150405"))
+	objectPath := fmt.Sprintf("exports/%s/%s", userID.Hex(), fileName)
+
+	downloadURL, failed, err := s.pdfService.GenerateDocumentsArchive(ctx, documents, objectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate export archive: %w", err)
+	}
+	if err := s.exportHooks.Check(ctx, &models.OutboundExport{
+		Kind:        models.OutboundExportDocumentArchive,
+		URL:         downloadURL,
+		Documents:   documents,
+		RequestedBy: userID,
+	}); err != nil {
+		// The archive is never served
+		if delErr := s.minioService.DeleteFile(ctx, downloadURL); delErr != nil {
+			fmt.Printf("⚠️ [EXPORT] Failed to delete blocked archive: %v\n", delErr)
+		}
+		return nil, err
+	}
+	skipped = append(skipped, failed...)
+
+	return &models.BulkExportResponse{
+		DownloadURL:   downloadURL,
+		FileName:      fileName,
+		DocumentCount: len(documents) - len(failed),
+		Skipped:       skipped,
+	}, nil
+}
+
+// bulkExportQuery builds the document query of a bulk export request
+func (s *DocumentService) bulkExportQuery(ctx context.Context, req *models.BulkExportRequest) (bson.M, error) {
+	query := models.NotDeleted(bson.M{})
+
+	if len(req.DocumentIDs) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(req.DocumentIDs))
+		for _, hex := range req.DocumentIDs {
+			id, err := primitive.ObjectIDFromHex(hex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid document ID: %s", hex)
+			}
+			ids = append(ids, id)
+		}
+		query["_id"] = bson.M{"$in": ids}
+		return query, nil
+	}
+
+	if req.Status == nil && req.MacroID == "" && req.DepartmentID == "" && req.Search == "" {
+		return nil, errors.New("document IDs or at least one filter are required")
+	}
+
+	if req.Status != nil {
+		query["status"] = *req.Status
+	}
+
+	if req.MacroID != "" {
+		macroID, err := primitive.ObjectIDFromHex(req.MacroID)
+		if err != nil {
+			return nil, errors.New("invalid macro ID")
+		}
+		query["macro_id"] = macroID
+	}
+
+	if req.Search != "" {
+		query["$or"] = []bson.M{
+			{"title": bson.M{"$regex": req.Search, "$options": "i"}},
+			{"reference": bson.M{"$regex": req.Search, "$options": "i"}},
+			{"process_code": bson.M{"$regex": req.Search, "$options": "i"}},
+		}
+	}
+
+	if req.DepartmentID != "" {
+		departmentID, err := primitive.ObjectIDFromHex(req.DepartmentID)
+		if err != nil {
+			return nil, errors.New("invalid department ID")
+		}
+		memberIDs, err := s.userCollection.Distinct(ctx, "_id", bson.M{"department_id": departmentID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get department members: %w", err)
+		}
+		departmentQuery := bson.M{"$or": []bson.M{
+			{"created_by": bson.M{"$in": memberIDs}},
+			{"contributors.authors.user_id": bson.M{"$in": memberIDs}},
+		}}
+		query = bson.M{"$and": []bson.M{query, departmentQuery}}
+	}
+
+	return query, nil
 }
 
 // RenderDocumentView renders the document as HTML (same design as PDF)
@@ -723,154 +1607,1133 @@ func (s *DocumentService) RenderDocumentView(ctx context.Context, id primitive.O
 		return "", fmt.Errorf("failed to render document HTML: %w", err)
 	}
 
-	fmt.Printf("✅ [VIEW] HTML rendered successfully, size: %d bytes\n", len(html))
-	return html, nil
+	fmt.Printf("✅ [VIEW] HTML rendered successfully, size: %d bytes\n", len(html))
+	return html, nil
+}
+
+// Delete moves a document to the trash. It is permanently removed by the
+// scheduled purge once the retention period has elapsed.
+func (s *DocumentService) Delete(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) error {
+	now := time.Now()
+	result, err := s.collection.UpdateOne(ctx, models.NotDeleted(bson.M{"_id": id}), bson.M{
+		"$set": bson.M{
+			"deleted_at": now,
+			"deleted_by": userID,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("document not found")
+	}
+
+	// Trigger documentation update
+	if s.documentationService != nil {
+		s.documentationService.TriggerUpdate()
+	}
+
+	return nil
+}
+
+// Restore moves a document out of the trash
+func (s *DocumentService) Restore(ctx context.Context, id primitive.ObjectID) (*models.Document, error) {
+	var document models.Document
+	err := s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id, "deleted_at": bson.M{"$exists": true}},
+		bson.M{
+			"$unset": bson.M{"deleted_at": "", "deleted_by": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&document)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("document not found in trash")
+		}
+		return nil, fmt.Errorf("failed to restore document: %w", err)
+	}
+
+	// Trigger documentation update
+	if s.documentationService != nil {
+		s.documentationService.TriggerUpdate()
+	}
+
+	return &document, nil
+}
+
+// ListTrash retrieves the trashed documents, most recently deleted first.
+// Admins see the whole trash; other users see the documents they created or deleted.
+func (s *DocumentService) ListTrash(ctx context.Context, userID primitive.ObjectID, userRole models.UserRole, page, limit int) ([]*models.Document, int64, error) {
+	query := bson.M{"deleted_at": bson.M{"$exists": true}}
+	if userRole != models.RoleAdmin {
+		query["$or"] = []bson.M{
+			{"created_by": userID},
+			{"deleted_by": userID},
+		}
+	}
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := s.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	documents := make([]*models.Document, 0)
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode documents: %w", err)
+	}
+
+	return documents, total, nil
+}
+
+// PurgeDeleted permanently removes the documents trashed before the given
+// time, along with their versions, their stored files and the records
+// referencing them, and returns how many were removed
+func (s *DocumentService) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	filter := bson.M{"deleted_at": bson.M{"$lt": before}}
+
+	cursor, err := s.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find trashed documents: %w", err)
+	}
+	var expired []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &expired); err != nil {
+		return 0, fmt.Errorf("failed to decode trashed documents: %w", err)
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(expired))
+	for i, doc := range expired {
+		ids[i] = doc.ID
+	}
+
+	// Files go first: the documents are kept until their files are removed,
+	// so that a failure is retried by the next purge
+	if s.minioService != nil {
+		for _, id := range ids {
+			if err := s.minioService.DeleteDocumentObjects(ctx, id.Hex()); err != nil {
+				return 0, fmt.Errorf("failed to purge files of document %s: %w", id.Hex(), err)
+			}
+		}
+	}
+
+	// Reactions reference the comments, not the documents
+	commentIDs, err := s.commentCollection.Distinct(ctx, "_id", bson.M{"document_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find document comments: %w", err)
+	}
+	if len(commentIDs) > 0 {
+		if _, err := s.reactionCollection.DeleteMany(ctx, bson.M{
+			"target_type": models.ReactionTargetComment,
+			"target_id":   bson.M{"$in": commentIDs},
+		}); err != nil {
+			return 0, fmt.Errorf("failed to purge comment reactions: %w", err)
+		}
+	}
+
+	related := []struct {
+		collection *mongo.Collection
+		name       string
+	}{
+		{s.versionCollection, "document versions"},
+		{s.draftCollection, "document drafts"},
+		{s.commentCollection, "document comments"},
+		{s.signatureCollection, "document signatures"},
+		{s.favoriteCollection, "document favorites"},
+		{s.recentCollection, "document recent views"},
+		{s.acknowledgmentCollection, "document acknowledgments"},
+		{s.campaignCollection, "document acknowledgment campaigns"},
+		{s.invitationCollection, "document invitations"},
+		{s.permissionCollection, "document permissions"},
+		{s.watchCollection, "document watches"},
+		{s.viewCollection, "document views"},
+		{s.qmsSyncCollection, "document QMS syncs"},
+	}
+	for _, r := range related {
+		if _, err := r.collection.DeleteMany(ctx, bson.M{"document_id": bson.M{"$in": ids}}); err != nil {
+			return 0, fmt.Errorf("failed to purge %s: %w", r.name, err)
+		}
+	}
+
+	result, err := s.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge documents: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+// StartTrashPurge runs the trash purge once a day until the context is cancelled.
+// The retention period is set by DOCUMENT_TRASH_RETENTION_DAYS (default 30 days).
+func (s *DocumentService) StartTrashPurge(ctx context.Context) {
+	retentionDays := 30
+	if v, err := strconv.Atoi(os.Getenv("DOCUMENT_TRASH_RETENTION_DAYS")); err == nil && v > 0 {
+		retentionDays = v
+	}
+
+	purge := func() {
+		purgeCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		count, err := s.PurgeDeleted(purgeCtx, time.Now().AddDate(0, 0, -retentionDays))
+		if err != nil {
+			fmt.Printf("Warning: Failed to purge document trash: %v\n", err)
+			return
+		}
+		if count > 0 {
+			fmt.Printf("🗑️ Purged %d document(s) from the trash\n", count)
+		}
+	}
+
+	go func() {
+		purge()
+
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purge()
+			}
+		}
+	}()
+	fmt.Printf("🗑️ Document trash purge started (retention: %d days)\n", retentionDays)
+}
+
+// Duplicate creates a draft copy of a document. The options select the copied
+// content, annex files are copied in storage so both documents own their files.
+func (s *DocumentService) Duplicate(ctx context.Context, id primitive.ObjectID, req *models.DuplicateDocumentRequest, userID primitive.ObjectID) (*models.Document, error) {
+	// Get original document
+	original, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		req = &models.DuplicateDocumentRequest{}
+	}
+
+	reference := req.Reference
+	if reference == "" {
+		if reference, err = s.copyReference(ctx, original.Reference); err != nil {
+			return nil, err
+		}
+	} else if exists, err := s.referenceExists(ctx, reference); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, errors.New("document reference already exists")
+	}
+
+	title := req.Title
+	if title == "" {
+		title = fmt.Sprintf("%s (Copy)", original.Title)
+	}
+
+	version := "1.0"
+	if req.ResetVersion != nil && !*req.ResetVersion {
+		version = original.Version
+	}
+
+	// Copied contributors have to sign the copy again
+	contributors := models.Contributors{
+		Authors:    make([]models.Contributor, 0),
+		Verifiers:  make([]models.Contributor, 0),
+		Validators: make([]models.Contributor, 0),
+	}
+	if req.IncludeContributors == nil || *req.IncludeContributors {
+		contributors.Authors = resetContributors(original.Contributors.Authors)
+		contributors.Verifiers = resetContributors(original.Contributors.Verifiers)
+		contributors.Validators = resetContributors(original.Contributors.Validators)
+	}
+	if !slices.ContainsFunc(contributors.Authors, func(c models.Contributor) bool { return c.UserID == userID }) {
+		user, err := s.userService.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user details: %w", err)
+		}
+		contributors.Authors = append(contributors.Authors, models.Contributor{
+			UserID:    userID,
+			Name:      fmt.Sprintf("%s %s", user.FirstName, user.LastName),
+			Team:      models.ContributorTeamAuthors,
+			Status:    models.SignatureStatusJoined,
+			InvitedAt: time.Now(),
+		})
+	}
+
+	metadata := original.Metadata
+	if req.IncludeChangeHistory != nil && !*req.IncludeChangeHistory {
+		metadata.ChangeHistory = make([]models.ChangeHistoryEntry, 0)
+	}
+
+	// Create new document
+	now := time.Now()
+	newDocument := &models.Document{
+		ID:               primitive.NewObjectID(),
+		Reference:        reference,
+		Title:            title,
+		ShortDescription: original.ShortDescription,
+		Description:      original.Description,
+		IsActive:         original.IsActive,
+		Stakeholders:     original.Stakeholders,
+		Version:          version,
+		Status:           models.DocumentStatusDraft,
+		CreatedBy:        userID,
+		Contributors:     contributors,
+		Metadata:         metadata,
+		ProcessGroups:    original.ProcessGroups,
+		Annexes:          make([]models.Annex, 0),
+		PageLayout:       original.PageLayout,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	var copiedFiles []string
+	if req.IncludeAnnexes == nil || *req.IncludeAnnexes {
+		newDocument.Annexes, copiedFiles, err = s.copyAnnexes(ctx, original.Annexes, newDocument.ID)
+		if err != nil {
+			s.deleteCopiedFiles(copiedFiles)
+			return nil, err
+		}
+	}
+
+	_, err = s.collection.InsertOne(ctx, newDocument)
+	if err != nil {
+		s.deleteCopiedFiles(copiedFiles)
+		return nil, fmt.Errorf("failed to duplicate document: %w", err)
+	}
+
+	if err := s.CreateVersion(ctx, newDocument, userID, fmt.Sprintf("Duplicated from %s", original.Reference)); err != nil {
+		fmt.Printf("Failed to create initial version: %v\n", err)
+	}
+
+	return newDocument, nil
+}
+
+// copyReference returns the first free copy reference of a document: REF-COPY, REF-COPY-2...
+func (s *DocumentService) copyReference(ctx context.Context, reference string) (string, error) {
+	candidate := fmt.Sprintf("%s-COPY", reference)
+	for n := 2; ; n++ {
+		exists, err := s.referenceExists(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-COPY-%d", reference, n)
+	}
+}
+
+// copyAnnexes returns copies of the annexes whose files are copied in storage
+// under the new document. It also returns the object keys of the copied
+// files, so they can be removed if the duplication fails.
+func (s *DocumentService) copyAnnexes(ctx context.Context, annexes []models.Annex, documentID primitive.ObjectID) ([]models.Annex, []string, error) {
+	copied := make([]models.Annex, 0, len(annexes))
+	copiedFiles := make([]string, 0)
+
+	for _, annex := range annexes {
+		// Files uploaded through the annex content
+		content := make(map[string]interface{}, len(annex.Content))
+		for key, value := range annex.Content {
+			content[key] = value
+		}
+		if files := annexContentFiles(annex.Content); files != nil {
+			copies := make([]interface{}, 0, len(files))
+			for _, file := range files {
+				url, _ := file["url"].(string)
+				if url == "" || s.minioService == nil {
+					copies = append(copies, file)
+					continue
+				}
+				sourceKey, err := s.minioService.extractObjectKeyFromURL(url)
+				if err != nil {
+					return nil, copiedFiles, fmt.Errorf("failed to extract object key from URL: %w", err)
+				}
+				fileID := primitive.NewObjectID().Hex()
+				objectKey, err := s.minioService.CopyAnnexObject(ctx, sourceKey, documentID.Hex(), annex.ID, fileID)
+				if err != nil {
+					return nil, copiedFiles, err
+				}
+				copiedFiles = append(copiedFiles, objectKey)
+				file["id"] = fileID
+				file["url"] = s.minioService.ObjectURL(objectKey)
+				copies = append(copies, file)
+			}
+			content["files"] = copies
+		}
+		annex.Content = content
+
+		// Attachments stored by object name
+		attachments := make([]models.FileAttachment, 0, len(annex.Files))
+		for _, attachment := range annex.Files {
+			if attachment.MinioObjectName != "" && s.minioService != nil {
+				fileID := primitive.NewObjectID()
+				objectKey, err := s.minioService.CopyAnnexObject(ctx, attachment.MinioObjectName, documentID.Hex(), annex.ID, fileID.Hex())
+				if err != nil {
+					return nil, copiedFiles, err
+				}
+				copiedFiles = append(copiedFiles, objectKey)
+				attachment.ID = fileID
+				attachment.MinioObjectName = objectKey
+			}
+			attachments = append(attachments, attachment)
+		}
+		annex.Files = attachments
+
+		copied = append(copied, annex)
+	}
+
+	return copied, copiedFiles, nil
+}
+
+// deleteCopiedFiles removes files copied for a duplication that failed
+func (s *DocumentService) deleteCopiedFiles(files []string) {
+	if len(files) == 0 || s.minioService == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, file := range files {
+		if err := s.minioService.DeleteAnnexObject(ctx, file); err != nil {
+			fmt.Printf("Warning: Failed to delete copied annex file %s: %v\n", file, err)
+		}
+	}
+}
+
+// annexContentFiles returns copies of the file entries of an annex content, or
+// nil when the content has none
+func annexContentFiles(content map[string]interface{}) []map[string]interface{} {
+	var entries []interface{}
+	switch files := content["files"].(type) {
+	case []interface{}:
+		entries = files
+	case primitive.A:
+		entries = files
+	default:
+		return nil
+	}
+
+	result := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		file := make(map[string]interface{})
+		switch value := entry.(type) {
+		case map[string]interface{}:
+			for k, v := range value {
+				file[k] = v
+			}
+		case primitive.M:
+			for k, v := range value {
+				file[k] = v
+			}
+		case primitive.D:
+			for _, e := range value {
+				file[e.Key] = e.Value
+			}
+		default:
+			continue
+		}
+		result = append(result, file)
+	}
+	return result
+}
+
+// Revise drafts a new version of an archived document. The draft keeps the
+// reference and content of the original, and replaces it once archived.
+func (s *DocumentService) Revise(ctx context.Context, id primitive.ObjectID, req *models.ReviseDocumentRequest, userID primitive.ObjectID) (*models.Document, error) {
+	original, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !original.Status.IsRevisable() {
+		return nil, fmt.Errorf("only archived documents can be revised, document status is %s", original.Status)
+	}
+
+	// A single revision at a time, trashed ones excepted
+	count, err := s.collection.CountDocuments(ctx, models.NotDeleted(bson.M{"supersedes": id}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing revisions: %w", err)
+	}
+	if count > 0 {
+		return nil, errors.New("document already has a revision in progress")
+	}
+
+	version := req.Version
+	if version == "" {
+		version = nextMinorVersion(original.Version)
+	}
+	if version == original.Version {
+		return nil, errors.New("revision version must differ from the revised version")
+	}
+
+	// Contributors sign again
+	contributors := models.Contributors{
+		Authors:    resetContributors(original.Contributors.Authors),
+		Verifiers:  resetContributors(original.Contributors.Verifiers),
+		Validators: resetContributors(original.Contributors.Validators),
+	}
+
+	now := time.Now()
+	revision := &models.Document{
+		ID:               primitive.NewObjectID(),
+		MacroID:          original.MacroID,
+		ProcessCode:      original.ProcessCode,
+		Reference:        original.Reference,
+		Title:            original.Title,
+		ShortDescription: original.ShortDescription,
+		Description:      original.Description,
+		IsActive:         original.IsActive,
+		Stakeholders:     original.Stakeholders,
+		Tasks:            original.Tasks,
+		Version:          version,
+		Status:           models.DocumentStatusDraft,
+		CreatedBy:        userID,
+		Contributors:     contributors,
+		Metadata:         original.Metadata,
+		ProcessGroups:    original.ProcessGroups,
+		Annexes:          original.Annexes,
+		References:       original.References,
+		Order:            original.Order,
+		Deadlines:        original.Deadlines,
+		PageLayout:       original.PageLayout,
+		Workflow:         original.Workflow,
+		Supersedes:       &original.ID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	if _, err := s.collection.InsertOne(ctx, revision); err != nil {
+		return nil, fmt.Errorf("failed to create revision: %w", err)
+	}
+
+	changeNote := req.ChangeNote
+	if changeNote == "" {
+		changeNote = fmt.Sprintf("Revision of version %s", original.Version)
+	}
+	if err := s.CreateVersion(ctx, revision, userID, changeNote); err != nil {
+		// Log error but don't fail the revision
+		fmt.Printf("Failed to create initial version of revision: %v\n", err)
+	}
+
+	return revision, nil
+}
+
+// supersede marks an archived document as replaced by its archived revision.
+// Unless a transition period was set, the document stops being in force when
+// the revision comes into force.
+func (s *DocumentService) supersede(ctx context.Context, id primitive.ObjectID, revision *models.Document, now time.Time) error {
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{
+				"status":        models.DocumentStatusSuperseded,
+				"superseded_by": revision.ID,
+				"superseded_at": now,
+				"updated_at":    now,
+			},
+			"$unset": bson.M{"next_review_date": ""},
+			"$inc":   bson.M{"revision": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	supersessionDate := now
+	if revision.EffectiveDate != nil {
+		supersessionDate = *revision.EffectiveDate
+	}
+	_, err = s.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "supersession_date": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"supersession_date": supersessionDate}},
+	)
+	return err
+}
+
+// resetContributors returns the contributors with their signatures cleared
+func resetContributors(contributors []models.Contributor) []models.Contributor {
+	reset := make([]models.Contributor, len(contributors))
+	for i, contributor := range contributors {
+		contributor.Status = models.SignatureStatusJoined
+		contributor.SignatureDate = nil
+		reset[i] = contributor
+	}
+	return reset
+}
+
+// nextMinorVersion increments the last number of a version, 1.0 becomes 1.1
+func nextMinorVersion(version string) string {
+	parts := strings.Split(version, ".")
+	last, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return version + ".1"
+	}
+	parts[len(parts)-1] = strconv.Itoa(last + 1)
+	return strings.Join(parts, ".")
+}
+
+// GetVersions retrieves all versions of a document
+func (s *DocumentService) GetVersions(ctx context.Context, documentID primitive.ObjectID) ([]*models.DocumentVersion, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := s.versionCollection.Find(ctx, bson.M{"document_id": documentID}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	versions := make([]*models.DocumentVersion, 0)
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetStatusCounts returns the number of documents in each status among the
+// documents the user can access, all of them for admins
+func (s *DocumentService) GetStatusCounts(ctx context.Context, userID primitive.ObjectID, userRole models.UserRole) (map[models.DocumentStatus]int64, error) {
+	match, err := s.listQuery(ctx, &models.DocumentFilter{}, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$status"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate document statuses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Status models.DocumentStatus `bson:"_id"`
+		Count  int64                 `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode document statuses: %w", err)
+	}
+
+	counts := make(map[models.DocumentStatus]int64, len(results))
+	for _, r := range results {
+		counts[r.Status] = r.Count
+	}
+
+	return counts, nil
+}
+
+// Helper functions
+
+// referenceExists checks if a document reference already exists.
+// Trashed documents are included so that restoring one cannot create a duplicate.
+func (s *DocumentService) referenceExists(ctx context.Context, reference string) (bool, error) {
+	count, err := s.collection.CountDocuments(ctx, bson.M{"reference": reference})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateMetadata updates document metadata
+func (s *DocumentService) UpdateMetadata(ctx context.Context, id primitive.ObjectID, req *models.UpdateMetadataRequest) (*models.Document, error) {
+	// Get existing document to verify it exists
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Document locking: Prevent editing published documents
+	if document.Status.IsPublished() {
+		return nil, fmt.Errorf("cannot modify document in '%s' status - document is locked", document.Status)
+	}
+
+	// Build update document
+	update := bson.M{}
+	if req.Objectives != nil {
+		update["metadata.objectives"] = *req.Objectives
+	}
+	if req.ImplicatedActors != nil {
+		actors, err := s.normalizeActors(ctx, *req.ImplicatedActors)
+		if err != nil {
+			return nil, err
+		}
+		update["metadata.implicated_actors"] = actors
+	}
+	if req.ManagementRules != nil {
+		update["metadata.management_rules"] = *req.ManagementRules
+	}
+	if req.Terminology != nil {
+		update["metadata.terminology"] = *req.Terminology
+	}
+	if req.CustomSections != nil {
+		sections, err := s.normalizeCustomSections(ctx, document.MacroID, *req.CustomSections)
+		if err != nil {
+			return nil, err
+		}
+		update["metadata.custom_sections"] = sections
+	}
+	update["updated_at"] = time.Now()
+
+	// Update document
+	result := s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id},
+		models.InvalidatePDF(bson.M{"$set": update, "$inc": bson.M{"revision": 1}}),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+
+	var updatedDocument models.Document
+	if err := result.Decode(&updatedDocument); err != nil {
+		return nil, fmt.Errorf("failed to decode updated document: %w", err)
+	}
+
+	return &updatedDocument, nil
+}
+
+// resolveReferences validates referenced documents and fills their reference,
+// title and version. References without a version point to the current one.
+func (s *DocumentService) resolveReferences(ctx context.Context, documentID primitive.ObjectID, references []models.DocumentReference) ([]models.DocumentReference, error) {
+	resolved := make([]models.DocumentReference, 0, len(references))
+	seen := make(map[primitive.ObjectID]bool, len(references))
+	for _, ref := range references {
+		if ref.DocumentID == documentID {
+			return nil, fmt.Errorf("invalid reference: a document cannot reference itself")
+		}
+		if seen[ref.DocumentID] {
+			continue
+		}
+		seen[ref.DocumentID] = true
+
+		target, err := s.GetByID(ctx, ref.DocumentID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference: document %s not found", ref.DocumentID.Hex())
+		}
+
+		entry := models.DocumentReference{
+			DocumentID: target.ID,
+			Reference:  target.Reference,
+			Title:      target.Title,
+			Version:    ref.Version,
+		}
+		if entry.Version == "" {
+			entry.Version = target.Version
+		}
+		if (target.Status == models.DocumentStatusArchived || target.Status == models.DocumentStatusReviewDue) && entry.Version != target.Version {
+			now := time.Now()
+			entry.Stale = true
+			entry.CurrentVersion = target.Version
+			entry.StaleSince = &now
+		}
+		resolved = append(resolved, entry)
+	}
+	return resolved, nil
+}
+
+// MarkStaleReferences flags references to older versions of a document that
+// has just been published and returns the documents holding them
+func (s *DocumentService) MarkStaleReferences(ctx context.Context, superseding *models.Document) ([]*models.Document, error) {
+	filter := bson.M{
+		"references": bson.M{"$elemMatch": bson.M{
+			"document_id": superseding.ID,
+			"version":     bson.M{"$ne": superseding.Version},
+			"stale":       false,
+		}},
+	}
+
+	cursor, err := s.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find referencing documents: %w", err)
+	}
+	var documents []*models.Document
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode referencing documents: %w", err)
+	}
+	if len(documents) == 0 {
+		return documents, nil
+	}
+
+	_, err = s.collection.UpdateMany(ctx, filter, bson.M{
+		"$set": bson.M{
+			"references.$[ref].stale":           true,
+			"references.$[ref].current_version": superseding.Version,
+			"references.$[ref].stale_since":     time.Now(),
+		},
+	}, options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.M{
+			"ref.document_id": superseding.ID,
+			"ref.version":     bson.M{"$ne": superseding.Version},
+		}},
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to flag stale references: %w", err)
+	}
+
+	// Trashed documents are flagged too, so they are accurate if restored,
+	// but their contributors are not notified
+	return slices.DeleteFunc(documents, func(d *models.Document) bool { return d.DeletedAt != nil }), nil
+}
+
+// Lint runs the quality linter on a document
+func (s *DocumentService) Lint(ctx context.Context, id primitive.ObjectID) (*models.DocumentLintResponse, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]models.LintIssue, 0)
+	for i, ref := range document.References {
+		if ref.Stale {
+			issues = append(issues, models.LintIssue{
+				Rule:     models.LintRuleStaleReference,
+				Severity: models.LintSeverityError,
+				Message:  fmt.Sprintf("Reference to %s v%s is obsolete: version %s has been published", ref.Reference, ref.Version, ref.CurrentVersion),
+				Field:    fmt.Sprintf("references[%d]", i),
+			})
+		}
+	}
+	if len(document.Metadata.Objectives) == 0 {
+		issues = append(issues, models.LintIssue{
+			Rule:     models.LintRuleMissingObjectives,
+			Severity: models.LintSeverityWarning,
+			Message:  "The procedure has no objectives",
+			Field:    "metadata.objectives",
+		})
+	}
+	if len(document.Metadata.ImplicatedActors) == 0 {
+		issues = append(issues, models.LintIssue{
+			Rule:     models.LintRuleMissingActors,
+			Severity: models.LintSeverityWarning,
+			Message:  "The procedure has no implicated actors",
+			Field:    "metadata.implicatedActors",
+		})
+	}
+	for i, group := range document.ProcessGroups {
+		for j, step := range group.ProcessSteps {
+			if strings.TrimSpace(step.Responsible) == "" {
+				issues = append(issues, models.LintIssue{
+					Rule:     models.LintRuleStepNoResponsible,
+					Severity: models.LintSeverityWarning,
+					Message:  fmt.Sprintf("Step '%s' in '%s' has no responsible", step.Title, group.Title),
+					Field:    fmt.Sprintf("processGroups[%d].processSteps[%d].responsible", i, j),
+				})
+			}
+		}
+	}
+	if len(document.Contributors.Validators) == 0 {
+		issues = append(issues, models.LintIssue{
+			Rule:     models.LintRuleMissingValidators,
+			Severity: models.LintSeverityError,
+			Message:  "The procedure has no validator",
+			Field:    "contributors.validators",
+		})
+	}
+
+	response := &models.DocumentLintResponse{
+		DocumentID: document.ID.Hex(),
+		Issues:     issues,
+	}
+	for _, issue := range issues {
+		if issue.Severity == models.LintSeverityError {
+			response.ErrorCount++
+		} else {
+			response.WarningCount++
+		}
+	}
+	return response, nil
+}
+
+// normalizeActors maps implicated actors to their canonical registry names
+func (s *DocumentService) normalizeActors(ctx context.Context, actors []string) ([]string, error) {
+	if s.actorService == nil || actors == nil {
+		return actors, nil
+	}
+	return s.actorService.NormalizeNames(ctx, actors)
 }
 
-// Delete deletes a document
-func (s *DocumentService) Delete(ctx context.Context, id primitive.ObjectID) error {
-	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": id})
+// NormalizeImplicatedActors rewrites the implicated actors of every document
+// using the actors registry and returns the number of updated documents
+func (s *DocumentService) NormalizeImplicatedActors(ctx context.Context) (int, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{"metadata.implicated_actors.0": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"metadata.implicated_actors": 1}))
 	if err != nil {
-		return fmt.Errorf("failed to delete document: %w", err)
+		return 0, fmt.Errorf("failed to find documents: %w", err)
 	}
+	defer cursor.Close(ctx)
 
-	if result.DeletedCount == 0 {
-		return errors.New("document not found")
-	}
+	updated := 0
+	for cursor.Next(ctx) {
+		var document models.Document
+		if err := cursor.Decode(&document); err != nil {
+			return updated, fmt.Errorf("failed to decode document: %w", err)
+		}
 
-	// Trigger documentation update
-	if s.documentationService != nil {
-		s.documentationService.TriggerUpdate()
+		actors, err := s.normalizeActors(ctx, document.Metadata.ImplicatedActors)
+		if err != nil {
+			return updated, err
+		}
+		if slices.Equal(actors, document.Metadata.ImplicatedActors) {
+			continue
+		}
+
+		// Updated in place: approved documents keep their content but use canonical names
+		if _, err := s.collection.UpdateOne(ctx, bson.M{"_id": document.ID}, bson.M{
+			"$set": bson.M{"metadata.implicated_actors": actors},
+		}); err != nil {
+			return updated, fmt.Errorf("failed to update document: %w", err)
+		}
+		updated++
 	}
 
-	return nil
+	return updated, cursor.Err()
 }
 
-// Duplicate creates a copy of a document
-func (s *DocumentService) Duplicate(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*models.Document, error) {
-	// Get original document
-	original, err := s.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
+// normalizeStepResponsibles maps step responsibles to their canonical registry names
+func (s *DocumentService) normalizeStepResponsibles(ctx context.Context, groups []models.ProcessGroup) error {
+	if s.actorService == nil {
+		return nil
 	}
 
-	// Create new document with modified reference
-	now := time.Now()
-	newDocument := &models.Document{
-		ID:            primitive.NewObjectID(),
-		Reference:     fmt.Sprintf("%s-COPY", original.Reference),
-		Title:         fmt.Sprintf("%s (Copy)", original.Title),
-		Version:       "1.0",
-		Status:        models.DocumentStatusDraft,
-		CreatedBy:     userID,
-		Contributors:  original.Contributors,
-		Metadata:      original.Metadata,
-		ProcessGroups: original.ProcessGroups,
-		Annexes:       original.Annexes,
-		CreatedAt:     now,
-		UpdatedAt:     now,
+	keys := make([]string, 0)
+	for _, group := range groups {
+		for _, step := range group.ProcessSteps {
+			if key := NormalizeActorName(step.Responsible); key != "" {
+				keys = append(keys, key)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil
 	}
 
-	_, err = s.collection.InsertOne(ctx, newDocument)
+	canonical, err := s.actorService.CanonicalNames(ctx, keys)
 	if err != nil {
-		return nil, fmt.Errorf("failed to duplicate document: %w", err)
+		return err
 	}
-
-	return newDocument, nil
+	for i := range groups {
+		for j := range groups[i].ProcessSteps {
+			step := &groups[i].ProcessSteps[j]
+			if name, ok := canonical[NormalizeActorName(step.Responsible)]; ok {
+				step.Responsible = name
+			}
+		}
+	}
+	return nil
 }
 
-// GetVersions retrieves all versions of a document
-func (s *DocumentService) GetVersions(ctx context.Context, documentID primitive.ObjectID) ([]*models.DocumentVersion, error) {
-	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+// actorNameCollation compares actor names regardless of case and accents,
+// as NormalizeActorName does
+var actorNameCollation = &options.Collation{Locale: "fr", Strength: 1}
 
-	cursor, err := s.versionCollection.Find(ctx, bson.M{"document_id": documentID}, findOptions)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find versions: %w", err)
+// SearchByActor returns the steps of approved documents where one of the actor
+// keys is responsible, and the tasks where the job position is an intervenant.
+// The documents are matched and their steps filtered by the database, on the
+// keys and the registry spellings of the actors.
+func (s *DocumentService) SearchByActor(ctx context.Context, actorKeys []string, jobPositionID *primitive.ObjectID) (*models.ActorSearchResponse, error) {
+	response := &models.ActorSearchResponse{
+		Steps: make([]models.ActorStepMatch, 0),
+		Tasks: make([]models.ActorTaskMatch, 0),
 	}
-	defer cursor.Close(ctx)
 
-	versions := make([]*models.DocumentVersion, 0)
-	if err = cursor.All(ctx, &versions); err != nil {
-		return nil, fmt.Errorf("failed to decode versions: %w", err)
+	keys := make(map[string]bool, len(actorKeys))
+	for _, key := range actorKeys {
+		keys[key] = true
+	}
+	names := slices.Clone(actorKeys)
+	if len(actorKeys) > 0 && s.actorService != nil {
+		spellings, err := s.actorService.Spellings(ctx, actorKeys)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, spellings...)
+	}
+
+	var conditions []bson.M
+	if len(names) > 0 {
+		conditions = append(conditions, bson.M{"process_groups.process_steps.responsible": bson.M{"$in": names}})
+	}
+	if jobPositionID != nil {
+		conditions = append(conditions, bson.M{"tasks.intervenants": *jobPositionID})
+	}
+	if len(conditions) == 0 {
+		return response, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: models.NotDeleted(bson.M{"status": models.DocumentStatusApproved, "$or": conditions})}},
+		{{Key: "$sort", Value: bson.D{{Key: "process_code", Value: 1}, {Key: "reference", Value: 1}}}},
+		{{Key: "$project", Value: bson.M{
+			"process_code": 1,
+			"reference":    1,
+			"title":        1,
+			"version":      1,
+			"process_groups": bson.M{"$map": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$process_groups", bson.A{}}},
+				"as":    "group",
+				"in": bson.M{
+					"id":    "$$group.id",
+					"title": "$$group.title",
+					"order": "$$group.order",
+					"process_steps": bson.M{"$filter": bson.M{
+						"input": bson.M{"$ifNull": bson.A{"$$group.process_steps", bson.A{}}},
+						"as":    "step",
+						"cond":  bson.M{"$in": bson.A{"$$step.responsible", names}},
+					}},
+				},
+			}},
+			"tasks": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$tasks", bson.A{}}},
+				"as":    "task",
+				"cond":  bson.M{"$in": bson.A{jobPositionID, bson.M{"$ifNull": bson.A{"$$task.intervenants", bson.A{}}}}},
+			}},
+		}}},
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline, options.Aggregate().SetCollation(actorNameCollation))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
 	}
+	defer cursor.Close(ctx)
 
-	return versions, nil
-}
+	for cursor.Next(ctx) {
+		var document models.Document
+		if err := cursor.Decode(&document); err != nil {
+			return nil, fmt.Errorf("failed to decode document: %w", err)
+		}
 
-// Helper functions
+		matched := false
+		for _, group := range document.ProcessGroups {
+			for _, step := range group.ProcessSteps {
+				// The collation ignores case and accents, not punctuation
+				if !keys[NormalizeActorName(step.Responsible)] && !slices.Contains(names, step.Responsible) {
+					continue
+				}
+				matched = true
+				response.Steps = append(response.Steps, models.ActorStepMatch{
+					DocumentID:  document.ID.Hex(),
+					ProcessCode: document.ProcessCode,
+					Reference:   document.Reference,
+					Title:       document.Title,
+					Version:     document.Version,
+					GroupID:     group.ID,
+					GroupTitle:  group.Title,
+					StepID:      step.ID,
+					StepTitle:   step.Title,
+					StepOrder:   step.Order,
+					Responsible: step.Responsible,
+				})
+			}
+		}
 
-// referenceExists checks if a document reference already exists
-func (s *DocumentService) referenceExists(ctx context.Context, reference string) (bool, error) {
-	count, err := s.collection.CountDocuments(ctx, bson.M{"reference": reference})
-	if err != nil {
-		return false, err
-	}
-	return count > 0, nil
-}
+		if jobPositionID != nil {
+			for _, task := range document.Tasks {
+				matched = true
+				response.Tasks = append(response.Tasks, models.ActorTaskMatch{
+					DocumentID:  document.ID.Hex(),
+					ProcessCode: document.ProcessCode,
+					Reference:   document.Reference,
+					Title:       document.Title,
+					Version:     document.Version,
+					TaskCode:    task.Code,
+					Description: task.Description,
+				})
+			}
+		}
 
-// createVersion creates a version snapshot of a document
-func (s *DocumentService) createVersion(ctx context.Context, document *models.Document, userID primitive.ObjectID, changeNote string) error {
-	version := &models.DocumentVersion{
-		ID:         primitive.NewObjectID(),
-		DocumentID: document.ID,
-		Version:    document.Version,
-		Data:       *document,
-		CreatedBy:  userID,
-		CreatedAt:  time.Now(),
-		ChangeNote: changeNote,
+		if matched {
+			response.DocumentCount++
+		}
 	}
-
-	_, err := s.versionCollection.InsertOne(ctx, version)
-	if err != nil {
-		return fmt.Errorf("failed to create version: %w", err)
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate documents: %w", err)
 	}
 
-	return nil
+	return response, nil
 }
 
-// UpdateMetadata updates document metadata
-func (s *DocumentService) UpdateMetadata(ctx context.Context, id primitive.ObjectID, req *models.UpdateMetadataRequest) (*models.Document, error) {
-	// Get existing document to verify it exists
-	document, err := s.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-
-	// Document locking: Prevent editing approved or archived documents
-	if document.Status == models.DocumentStatusApproved || document.Status == models.DocumentStatusArchived {
-		return nil, fmt.Errorf("cannot modify document in '%s' status - document is locked", document.Status)
+// normalizeCustomSections validates custom sections against their definitions and
+// copies title, type and columns from the definition so documents stay consistent
+func (s *DocumentService) normalizeCustomSections(ctx context.Context, macroID *primitive.ObjectID, sections []models.CustomMetadataSection) ([]models.CustomMetadataSection, error) {
+	if s.sectionService == nil {
+		return nil, fmt.Errorf("custom metadata sections are not available")
 	}
 
-	// Build update document
-	update := bson.M{}
-	if req.Objectives != nil {
-		update["metadata.objectives"] = *req.Objectives
-	}
-	if req.ImplicatedActors != nil {
-		update["metadata.implicated_actors"] = *req.ImplicatedActors
-	}
-	if req.ManagementRules != nil {
-		update["metadata.management_rules"] = *req.ManagementRules
+	definitions, err := s.sectionService.List(ctx, macroID, false)
+	if err != nil {
+		return nil, err
 	}
-	if req.Terminology != nil {
-		update["metadata.terminology"] = *req.Terminology
+	byKey := make(map[string]models.MetadataSectionDefinition, len(definitions))
+	for _, def := range definitions {
+		// Macro-specific definitions override the default template
+		if existing, ok := byKey[def.Key]; ok && existing.MacroID != nil {
+			continue
+		}
+		byKey[def.Key] = def
 	}
-	update["updated_at"] = time.Now()
 
-	// Update document
-	result := s.collection.FindOneAndUpdate(
-		ctx,
-		bson.M{"_id": id},
-		bson.M{"$set": update},
-		options.FindOneAndUpdate().SetReturnDocument(options.After),
-	)
+	normalized := make([]models.CustomMetadataSection, 0, len(sections))
+	seen := make(map[string]bool, len(sections))
+	for _, section := range sections {
+		def, ok := byKey[section.Key]
+		if !ok {
+			return nil, fmt.Errorf("unknown metadata section: %s", section.Key)
+		}
+		if seen[section.Key] {
+			return nil, fmt.Errorf("duplicate metadata section: %s", section.Key)
+		}
+		seen[section.Key] = true
 
-	var updatedDocument models.Document
-	if err := result.Decode(&updatedDocument); err != nil {
-		return nil, fmt.Errorf("failed to decode updated document: %w", err)
+		entry := models.CustomMetadataSection{
+			Key:   def.Key,
+			Title: def.Title,
+			Type:  def.Type,
+			Order: def.Order,
+		}
+		switch def.Type {
+		case models.MetadataSectionTypeList:
+			entry.Items = section.Items
+		case models.MetadataSectionTypeRichText:
+			entry.Content = section.Content
+		case models.MetadataSectionTypeTable:
+			entry.Columns = def.Columns
+			entry.Rows = make([][]string, 0, len(section.Rows))
+			for _, row := range section.Rows {
+				// Pad or trim rows to the configured columns
+				cells := make([]string, len(def.Columns))
+				copy(cells, row)
+				entry.Rows = append(entry.Rows, cells)
+			}
+		}
+		normalized = append(normalized, entry)
 	}
 
-	return &updatedDocument, nil
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return normalized[i].Order < normalized[j].Order
+	})
+
+	return normalized, nil
 }
 
 // CreateAnnex creates a new annex for a document
@@ -881,8 +2744,8 @@ func (s *DocumentService) CreateAnnex(ctx context.Context, documentID primitive.
 		return nil, err
 	}
 
-	// Document locking: Prevent editing approved or archived documents
-	if document.Status == models.DocumentStatusApproved || document.Status == models.DocumentStatusArchived {
+	// Document locking: Prevent editing published documents
+	if document.Status.IsPublished() {
 		return nil, fmt.Errorf("cannot add annexes to document in '%s' status - document is locked", document.Status)
 	}
 
@@ -912,10 +2775,11 @@ func (s *DocumentService) CreateAnnex(ctx context.Context, documentID primitive.
 	_, err = s.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": documentID},
-		bson.M{
+		models.InvalidatePDF(bson.M{
 			"$push": bson.M{"annexes": annex},
 			"$set":  bson.M{"updated_at": time.Now()},
-		},
+			"$inc":  bson.M{"revision": 1},
+		}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create annex: %w", err)
@@ -932,8 +2796,8 @@ func (s *DocumentService) UpdateAnnex(ctx context.Context, documentID primitive.
 		return nil, err
 	}
 
-	// Document locking: Prevent editing approved or archived documents
-	if document.Status == models.DocumentStatusApproved || document.Status == models.DocumentStatusArchived {
+	// Document locking: Prevent editing published documents
+	if document.Status.IsPublished() {
 		return nil, fmt.Errorf("cannot update annexes in document with '%s' status - document is locked", document.Status)
 	}
 
@@ -976,7 +2840,7 @@ func (s *DocumentService) UpdateAnnex(ctx context.Context, documentID primitive.
 	_, err = s.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": documentID},
-		bson.M{"$set": update},
+		models.InvalidatePDF(bson.M{"$set": update, "$inc": bson.M{"revision": 1}}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update annex: %w", err)
@@ -993,8 +2857,8 @@ func (s *DocumentService) DeleteAnnex(ctx context.Context, documentID primitive.
 		return err
 	}
 
-	// Document locking: Prevent editing approved or archived documents
-	if document.Status == models.DocumentStatusApproved || document.Status == models.DocumentStatusArchived {
+	// Document locking: Prevent editing published documents
+	if document.Status.IsPublished() {
 		return fmt.Errorf("cannot delete annexes from document in '%s' status - document is locked", document.Status)
 	}
 
@@ -1015,10 +2879,11 @@ func (s *DocumentService) DeleteAnnex(ctx context.Context, documentID primitive.
 	_, err = s.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": documentID},
-		bson.M{
+		models.InvalidatePDF(bson.M{
 			"$pull": bson.M{"annexes": bson.M{"id": annexID}},
 			"$set":  bson.M{"updated_at": time.Now()},
-		},
+			"$inc":  bson.M{"revision": 1},
+		}),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to delete annex: %w", err)
@@ -1026,3 +2891,342 @@ func (s *DocumentService) DeleteAnnex(ctx context.Context, documentID primitive.
 
 	return nil
 }
+
+// ReorderAnnexes changes the order of the annexes of a document. Every annex
+// must be listed exactly once.
+func (s *DocumentService) ReorderAnnexes(ctx context.Context, documentID primitive.ObjectID, annexIDs []string) ([]models.Annex, error) {
+	for attempt := 0; attempt < maxSectionWriteAttempts; attempt++ {
+		document, err := s.GetByID(ctx, documentID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Document locking: Prevent editing published documents
+		if document.Status.IsPublished() {
+			return nil, fmt.Errorf("cannot reorder annexes in document with '%s' status - document is locked", document.Status)
+		}
+
+		if len(annexIDs) != len(document.Annexes) {
+			return nil, errors.New("annex order must list every annex of the document exactly once")
+		}
+		annexes := make(map[string]models.Annex, len(document.Annexes))
+		for _, annex := range document.Annexes {
+			annexes[annex.ID] = annex
+		}
+		reordered := make([]models.Annex, 0, len(annexIDs))
+		for i, id := range annexIDs {
+			annex, ok := annexes[id]
+			if !ok {
+				return nil, errors.New("annex order must list every annex of the document exactly once")
+			}
+			delete(annexes, id)
+			annex.Order = i + 1
+			reordered = append(reordered, annex)
+		}
+
+		result, err := s.collection.UpdateOne(ctx,
+			bson.M{"_id": documentID, "revision": revisionFilter(document.Revision)},
+			models.InvalidatePDF(bson.M{
+				"$set": bson.M{"annexes": reordered, "updated_at": time.Now()},
+				"$inc": bson.M{"revision": 1},
+			}),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reorder annexes: %w", err)
+		}
+		if result.MatchedCount > 0 {
+			return reordered, nil
+		}
+	}
+
+	return nil, ErrDocumentRevisionConflict
+}
+
+// maxSectionWriteAttempts bounds the retries of a step or annex edit racing with other writes
+const maxSectionWriteAttempts = 3
+
+// ReorderSteps changes the order of the steps of a process group. Every step of
+// the group must be listed exactly once.
+func (s *DocumentService) ReorderSteps(ctx context.Context, documentID primitive.ObjectID, groupID string, stepIDs []string) (*models.ProcessGroup, error) {
+	return s.editProcessGroup(ctx, documentID, groupID, func(group *models.ProcessGroup) error {
+		if len(stepIDs) != len(group.ProcessSteps) {
+			return errors.New("step order must list every step of the process group exactly once")
+		}
+
+		steps := make(map[string]models.ProcessStep, len(group.ProcessSteps))
+		for _, step := range group.ProcessSteps {
+			steps[step.ID] = step
+		}
+
+		reordered := make([]models.ProcessStep, 0, len(stepIDs))
+		for _, id := range stepIDs {
+			step, ok := steps[id]
+			if !ok {
+				return errors.New("step order must list every step of the process group exactly once")
+			}
+			delete(steps, id)
+			reordered = append(reordered, step)
+		}
+		group.ProcessSteps = reordered
+		return nil
+	})
+}
+
+// BulkEditSteps deletes, updates and creates steps of a process group in a single write
+func (s *DocumentService) BulkEditSteps(ctx context.Context, documentID primitive.ObjectID, groupID string, req *models.BulkStepsRequest) (*models.ProcessGroup, error) {
+	return s.editProcessGroup(ctx, documentID, groupID, func(group *models.ProcessGroup) error {
+		if len(req.Delete) > 0 {
+			deleted := make(map[string]bool, len(req.Delete))
+			for _, id := range req.Delete {
+				deleted[id] = true
+			}
+			kept := make([]models.ProcessStep, 0, len(group.ProcessSteps))
+			for _, step := range group.ProcessSteps {
+				if deleted[step.ID] {
+					delete(deleted, step.ID)
+					continue
+				}
+				kept = append(kept, step)
+			}
+			for _, id := range req.Delete {
+				if deleted[id] {
+					return fmt.Errorf("process step not found: %s", id)
+				}
+			}
+			group.ProcessSteps = kept
+		}
+
+		for _, update := range req.Update {
+			index := slices.IndexFunc(group.ProcessSteps, func(step models.ProcessStep) bool { return step.ID == update.ID })
+			if index == -1 {
+				return fmt.Errorf("process step not found: %s", update.ID)
+			}
+			step := &group.ProcessSteps[index]
+			if update.Title != nil {
+				step.Title = *update.Title
+			}
+			if update.Outputs != nil {
+				step.Outputs = *update.Outputs
+			}
+			if update.Durations != nil {
+				step.Durations = *update.Durations
+			}
+			if update.Responsible != nil {
+				step.Responsible = *update.Responsible
+			}
+			if update.Descriptions != nil {
+				step.Descriptions = *update.Descriptions
+			}
+		}
+
+		for _, create := range req.Create {
+			id := create.ID
+			if id == "" {
+				id = primitive.NewObjectID().Hex()
+			}
+			if slices.ContainsFunc(group.ProcessSteps, func(step models.ProcessStep) bool { return step.ID == id }) {
+				return fmt.Errorf("duplicate process step ID: %s", id)
+			}
+
+			step := models.ProcessStep{
+				ID:           id,
+				Title:        create.Title,
+				Outputs:      create.Outputs,
+				Durations:    create.Durations,
+				Responsible:  create.Responsible,
+				Descriptions: create.Descriptions,
+			}
+			if step.Outputs == nil {
+				step.Outputs = make([]string, 0)
+			}
+			if step.Durations == nil {
+				step.Durations = make([]string, 0)
+			}
+			if step.Descriptions == nil {
+				step.Descriptions = make([]models.ProcessDescription, 0)
+			}
+
+			position := len(group.ProcessSteps)
+			if create.Position != nil && *create.Position >= 1 && *create.Position <= len(group.ProcessSteps) {
+				position = *create.Position - 1
+			}
+			group.ProcessSteps = slices.Insert(group.ProcessSteps, position, step)
+		}
+		return nil
+	})
+}
+
+// editProcessGroup applies an edit to the steps of a process group, then
+// renumbers them from 1 in slice order. The write only succeeds if the document
+// was not modified since it was read, and is retried on a fresh copy otherwise,
+// so concurrent edits to other parts of the document are never overwritten.
+func (s *DocumentService) editProcessGroup(ctx context.Context, documentID primitive.ObjectID, groupID string, edit func(group *models.ProcessGroup) error) (*models.ProcessGroup, error) {
+	for attempt := 0; attempt < maxSectionWriteAttempts; attempt++ {
+		document, err := s.GetByID(ctx, documentID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Document locking: Prevent editing published documents
+		if document.Status.IsPublished() {
+			return nil, fmt.Errorf("cannot modify process steps of document in '%s' status - document is locked", document.Status)
+		}
+
+		index := slices.IndexFunc(document.ProcessGroups, func(group models.ProcessGroup) bool { return group.ID == groupID })
+		if index == -1 {
+			return nil, errors.New("process group not found")
+		}
+
+		group := document.ProcessGroups[index]
+		group.ProcessSteps = slices.Clone(group.ProcessSteps)
+		if group.ProcessSteps == nil {
+			group.ProcessSteps = make([]models.ProcessStep, 0)
+		}
+		sort.SliceStable(group.ProcessSteps, func(i, j int) bool {
+			return group.ProcessSteps[i].Order < group.ProcessSteps[j].Order
+		})
+
+		if err := edit(&group); err != nil {
+			return nil, err
+		}
+		for i := range group.ProcessSteps {
+			group.ProcessSteps[i].Order = i + 1
+		}
+		if err := s.normalizeStepResponsibles(ctx, []models.ProcessGroup{group}); err != nil {
+			return nil, err
+		}
+
+		result, err := s.collection.UpdateOne(ctx,
+			bson.M{"_id": documentID, "revision": revisionFilter(document.Revision)},
+			models.InvalidatePDF(bson.M{
+				"$set": bson.M{
+					fmt.Sprintf("process_groups.%d.process_steps", index): group.ProcessSteps,
+					"updated_at": time.Now(),
+				},
+				"$inc": bson.M{"revision": 1},
+			}),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update process steps: %w", err)
+		}
+		if result.MatchedCount > 0 {
+			if s.documentationService != nil {
+				s.documentationService.TriggerUpdate()
+			}
+			return &group, nil
+		}
+	}
+
+	return nil, ErrDocumentRevisionConflict
+}
+
+// sectionLockedError builds the authorization error of a field in a locked section
+func sectionLockedError(field string, section models.DocumentSection) models.FieldAuthorizationError {
+	return models.FieldAuthorizationError{
+		Field:   field,
+		Section: section,
+		Message: fmt.Sprintf("the %s section is locked by the document owner", section),
+	}
+}
+
+// CheckSectionAccess returns a SectionAccessError when the user may not modify a section of the document
+func (s *DocumentService) CheckSectionAccess(ctx context.Context, id primitive.ObjectID, section models.DocumentSection, userID primitive.ObjectID, userRole models.UserRole) error {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !document.CanEditSection(section, userID, userRole) {
+		field := string(section)
+		if section == models.DocumentSectionProcessGroups {
+			field = "processGroups"
+		}
+		return &models.SectionAccessError{Fields: []models.FieldAuthorizationError{sectionLockedError(field, section)}}
+	}
+	return nil
+}
+
+// SetSectionLock restricts the edition of a section to the given users and
+// contributor teams, replacing its previous lock. Only the owner or an admin can lock.
+func (s *DocumentService) SetSectionLock(ctx context.Context, id primitive.ObjectID, section models.DocumentSection, req *models.SectionLockRequest, userID primitive.ObjectID, userRole models.UserRole) (*models.Document, error) {
+	if !section.IsValid() {
+		return nil, fmt.Errorf("invalid section: must be metadata, process_groups or annexes")
+	}
+
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if userRole != models.RoleAdmin && document.CreatedBy != userID {
+		return nil, fmt.Errorf("only the document owner can lock sections")
+	}
+
+	lock := models.SectionLock{
+		Section:  section,
+		UserIDs:  make([]primitive.ObjectID, 0, len(req.UserIDs)),
+		Teams:    req.Teams,
+		LockedBy: userID,
+		LockedAt: time.Now(),
+	}
+	for _, idStr := range req.UserIDs {
+		lockUserID, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user ID format: %s", idStr)
+		}
+		if !slices.Contains(lock.UserIDs, lockUserID) {
+			lock.UserIDs = append(lock.UserIDs, lockUserID)
+		}
+	}
+
+	locks := []models.SectionLock{lock}
+	for _, existing := range document.SectionLocks {
+		if existing.Section != section {
+			locks = append(locks, existing)
+		}
+	}
+
+	return s.saveSectionLocks(ctx, id, locks)
+}
+
+// RemoveSectionLock opens a section again to everyone with write access
+func (s *DocumentService) RemoveSectionLock(ctx context.Context, id primitive.ObjectID, section models.DocumentSection, userID primitive.ObjectID, userRole models.UserRole) (*models.Document, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if userRole != models.RoleAdmin && document.CreatedBy != userID {
+		return nil, fmt.Errorf("only the document owner can lock sections")
+	}
+
+	locks := make([]models.SectionLock, 0, len(document.SectionLocks))
+	found := false
+	for _, existing := range document.SectionLocks {
+		if existing.Section == section {
+			found = true
+			continue
+		}
+		locks = append(locks, existing)
+	}
+	if !found {
+		return nil, fmt.Errorf("section lock not found")
+	}
+
+	return s.saveSectionLocks(ctx, id, locks)
+}
+
+func (s *DocumentService) saveSectionLocks(ctx context.Context, id primitive.ObjectID, locks []models.SectionLock) (*models.Document, error) {
+	var updatedDocument models.Document
+	err := s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{"section_locks": locks, "updated_at": time.Now()},
+			"$inc": bson.M{"revision": 1},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updatedDocument)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update section locks: %w", err)
+	}
+
+	return &updatedDocument, nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// fakeObjectStore answers the object listings and deletions of the MinIO
+// client from a set of object keys
+type fakeObjectStore struct {
+	mu      sync.Mutex
+	objects map[string]bool
+	deleted []string
+}
+
+func (f *fakeObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		prefix := r.URL.Query().Get("prefix")
+		var contents strings.Builder
+		for object := range f.objects {
+			if strings.HasPrefix(object, prefix) {
+				fmt.Fprintf(&contents, "<Contents><Key>%s</Key><Size>1</Size></Contents>", object)
+			}
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Name>%s</Name><Prefix>%s</Prefix><MaxKeys>1000</MaxKeys><IsTruncated>false</IsTruncated>%s</ListBucketResult>`,
+			bucket, prefix, contents.String())
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		f.deleted = append(f.deleted, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestPurgeDeletedCascadesToRelatedRecordsAndFiles(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("expired document", func(mt *mtest.T) {
+		documentID := primitive.NewObjectID()
+		otherID := primitive.NewObjectID()
+		commentID := primitive.NewObjectID()
+
+		store := &fakeObjectStore{objects: map[string]bool{
+			fmt.Sprintf("documents/%s/annexes/a1/f1.png", documentID.Hex()):       true,
+			fmt.Sprintf("documents/%s/annexes/a2/f2.pdf", documentID.Hex()):       true,
+			fmt.Sprintf("documents/%s/pdf/PRO-001_v1.pdf", documentID.Hex()):      true,
+			fmt.Sprintf("documents/%s/archive/PRO-001_v1.pdf", documentID.Hex()):  true,
+			fmt.Sprintf("documents/%s/versions/PRO-001_v0.pdf", documentID.Hex()): true,
+			fmt.Sprintf("documents/%s/annexes/a1/f3.png", otherID.Hex()):          true,
+		}}
+		server := httptest.NewServer(store)
+		defer server.Close()
+		client, err := minio.New(strings.TrimPrefix(server.URL, "http://"), &minio.Options{
+			Creds:  credentials.NewStaticV4("access", "secret", ""),
+			Region: "us-east-1",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ns := mt.DB.Name() + ".documents"
+		responses := []bson.D{
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: documentID}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{commentID}}),
+		}
+		for i := 0; i < 15; i++ { // Reactions, related collections, then the documents
+			responses = append(responses, mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+		}
+		mt.AddMockResponses(responses...)
+
+		service := NewDocumentService(mt.DB, nil, nil, nil, nil, nil, nil, nil, nil,
+			&MinIOService{client: client, bucketName: "process-manager"}, nil)
+		count, err := service.PurgeDeleted(context.Background(), time.Now().AddDate(0, 0, -30))
+		if err != nil {
+			t.Fatalf("PurgeDeleted failed: %v", err)
+		}
+		if count != 1 {
+			t.Fatalf("expected 1 purged document, got %d", count)
+		}
+
+		// Every file of the document is removed, its PDFs included, the other
+		// document keeps its own
+		if len(store.deleted) != 5 {
+			t.Errorf("expected the 5 files of the document to be deleted, got %v", store.deleted)
+		}
+		for _, key := range store.deleted {
+			if !strings.HasPrefix(key, "documents/"+documentID.Hex()+"/") {
+				t.Errorf("deleted a file of another document: %s", key)
+			}
+		}
+
+		purged := map[string]bool{}
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName != "delete" {
+				continue
+			}
+			var cmd struct {
+				Collection string `bson:"delete"`
+				Deletes    []struct {
+					Q bson.M `bson:"q"`
+				} `bson:"deletes"`
+			}
+			if err := bson.Unmarshal(event.Command, &cmd); err != nil {
+				t.Fatal(err)
+			}
+			filter, want := cmd.Deletes[0].Q["document_id"], documentID
+			switch cmd.Collection {
+			case "documents":
+				filter = cmd.Deletes[0].Q["_id"]
+			case "reactions":
+				filter, want = cmd.Deletes[0].Q["target_id"], commentID
+			}
+			in, _ := filter.(bson.M)["$in"].(bson.A)
+			if len(in) != 1 || in[0] != want {
+				t.Errorf("%s: expected the records of the purged document to be deleted, got %v", cmd.Collection, cmd.Deletes[0].Q)
+			}
+			purged[cmd.Collection] = true
+		}
+		for _, collection := range []string{
+			"document_versions", "documents_drafts", "comments", "signatures", "document_favorites",
+			"recently_viewed", "acknowledgments", "acknowledgment_campaigns", "invitations", "permissions",
+			"document_watches", "document_views", "qms_syncs", "reactions", "documents",
+		} {
+			if !purged[collection] {
+				t.Errorf("expected the %s of the document to be purged", collection)
+			}
+		}
+	})
+}
+
+func TestSearchByActorMatchesStepsInTheDatabase(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("responsible and intervenant", func(mt *mtest.T) {
+		jobPositionID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			// Registry spellings of the actor
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".actors", mtest.FirstBatch,
+				bson.D{{Key: "name", Value: "Direction Générale"}, {Key: "aliases", Value: bson.A{"DG"}}}),
+			// Documents with their matching steps and tasks only
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".documents", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "reference", Value: "PRO-ACH-001"},
+				{Key: "process_groups", Value: bson.A{bson.D{
+					{Key: "id", Value: "g1"},
+					{Key: "process_steps", Value: bson.A{bson.D{{Key: "id", Value: "s1"}, {Key: "responsible", Value: "DG"}}}},
+				}}},
+				{Key: "tasks", Value: bson.A{bson.D{{Key: "code", Value: "M1_P1_T1"}, {Key: "intervenants", Value: bson.A{jobPositionID}}}}},
+			}),
+		)
+
+		service := NewDocumentService(mt.DB, nil, nil, nil, nil, nil,
+			&ActorService{collection: mt.DB.Collection("actors")}, nil, nil, nil, nil)
+		response, err := service.SearchByActor(context.Background(), []string{"direction generale"}, &jobPositionID)
+		if err != nil {
+			t.Fatalf("SearchByActor failed: %v", err)
+		}
+		if response.DocumentCount != 1 || len(response.Steps) != 1 || response.Steps[0].StepID != "s1" || len(response.Tasks) != 1 {
+			t.Fatalf("expected the step and the task of the document, got %+v", response)
+		}
+
+		// The documents are matched by the database, never all loaded
+		events := mt.GetAllStartedEvents()
+		if len(events) != 2 || events[1].CommandName != "aggregate" {
+			t.Fatalf("expected the documents to be aggregated, got %v", events)
+		}
+		var cmd struct {
+			Pipeline  []bson.M `bson:"pipeline"`
+			Collation bson.M   `bson:"collation"`
+		}
+		if err := bson.Unmarshal(events[1].Command, &cmd); err != nil {
+			t.Fatal(err)
+		}
+		match, _ := cmd.Pipeline[0]["$match"].(bson.M)
+		conditions, _ := match["$or"].(bson.A)
+		if len(conditions) != 2 {
+			t.Fatalf("expected a match on the responsible and the intervenants, got %v", match)
+		}
+		responsible, _ := conditions[0].(bson.M)["process_groups.process_steps.responsible"].(bson.M)
+		names, _ := responsible["$in"].(bson.A)
+		if len(names) != 3 || names[1] != "Direction Générale" || names[2] != "DG" {
+			t.Errorf("expected the keys and the registry spellings of the actor, got %v", responsible)
+		}
+		if conditions[1].(bson.M)["tasks.intervenants"] != jobPositionID {
+			t.Errorf("expected a match on the job position, got %v", conditions[1])
+		}
+		if cmd.Collation["strength"] != int32(1) {
+			t.Errorf("expected a case and accent insensitive match, got %v", cmd.Collation)
+		}
+		if _, ok := cmd.Pipeline[2]["$project"]; !ok {
+			t.Errorf("expected the steps to be projected, got %v", cmd.Pipeline[2])
+		}
+	})
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/html"
+)
+
+// AnnexTable returns the cells of an annex to export it to a spreadsheet:
+// the rows of table annexes, stored as structured data or HTML, and the
+// first table of rich text annexes, else their paragraphs
+func (s *DocumentService) AnnexTable(ctx context.Context, documentID primitive.ObjectID, annexID string) (*models.AnnexTable, error) {
+	document, err := s.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var annex *models.Annex
+	for i := range document.Annexes {
+		if document.Annexes[i].ID == annexID {
+			annex = &document.Annexes[i]
+			break
+		}
+	}
+	if annex == nil {
+		return nil, errors.New("annex not found")
+	}
+
+	table := &models.AnnexTable{Reference: document.Reference, Title: annex.Title}
+	var rows [][]string
+	switch annex.Type {
+	case models.AnnexTypeTable:
+		if headers, ok := storedArray(annex.Content["headers"]); ok {
+			for _, header := range headers {
+				table.Header = append(table.Header, annexCell(header))
+			}
+		}
+		if stored, ok := storedArray(annex.Content["rows"]); ok {
+			rows = structuredAnnexRows(stored, table.Header)
+		} else if source, ok := annex.Content["html"].(string); ok {
+			rows = htmlTableRows(source)
+		}
+	case models.AnnexTypeText:
+		source, _ := annex.Content["html"].(string)
+		if source == "" {
+			source, _ = annex.Content["content"].(string)
+		}
+		if rows = htmlTableRows(source); len(rows) == 0 {
+			table.Header = []string{annex.Title}
+			for _, paragraph := range htmlParagraphs(source) {
+				rows = append(rows, []string{paragraph})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("annex has no table: %s annexes cannot be exported", annex.Type)
+	}
+
+	// Tables without headers use their first row
+	if len(table.Header) == 0 && len(rows) > 0 {
+		table.Header, rows = rows[0], rows[1:]
+	}
+	if len(table.Header) == 0 {
+		return nil, errors.New("annex has no table: it is empty")
+	}
+	table.Rows = rows
+	return table, nil
+}
+
+// structuredAnnexRows reads the rows of a table annex, either arrays of cells
+// or objects keyed by header
+func structuredAnnexRows(stored []interface{}, header []string) [][]string {
+	rows := make([][]string, 0, len(stored))
+	for _, item := range stored {
+		if cells, ok := storedArray(item); ok {
+			row := make([]string, 0, len(cells))
+			for _, cell := range cells {
+				row = append(row, annexCell(cell))
+			}
+			rows = append(rows, row)
+			continue
+		}
+		if fields, ok := storedObject(item); ok {
+			row := make([]string, 0, len(header))
+			for _, column := range header {
+				row = append(row, annexCell(fields[column]))
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// annexCell formats a stored table cell, cells edited with formatting
+// keeping their text under "value"
+func annexCell(value interface{}) string {
+	if fields, ok := storedObject(value); ok {
+		value = fields["value"]
+	}
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// htmlTableRows returns the text of the cells of the first table of an HTML fragment
+func htmlTableRows(source string) [][]string {
+	root := parseHTMLFragment(source)
+	if root == nil {
+		return nil
+	}
+	tableNode := findHTMLElement(root, "table")
+	if tableNode == nil {
+		return nil
+	}
+
+	var rows [][]string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch child.Data {
+			case "table":
+				// Nested tables stay in the text of their cell
+			case "tr":
+				var row []string
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+						row = append(row, htmlText(cell))
+					}
+				}
+				rows = append(rows, row)
+			default:
+				walk(child)
+			}
+		}
+	}
+	walk(tableNode)
+	return rows
+}
+
+// htmlParagraphs returns the text of the blocks of an HTML fragment
+func htmlParagraphs(source string) []string {
+	root := parseHTMLFragment(source)
+	if root == nil {
+		return nil
+	}
+
+	var paragraphs []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			switch {
+			case child.Type == html.ElementNode && htmlBlockElements[child.Data]:
+				if text := htmlText(child); text != "" {
+					paragraphs = append(paragraphs, text)
+				}
+			case child.Type == html.ElementNode:
+				walk(child)
+			case child.Type == html.TextNode:
+				if text := strings.Join(strings.Fields(child.Data), " "); text != "" {
+					paragraphs = append(paragraphs, text)
+				}
+			}
+		}
+	}
+	walk(root)
+	return paragraphs
+}
+
+// htmlBlockElements are the elements exported as one paragraph each
+var htmlBlockElements = map[string]bool{
+	"p": true, "li": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"blockquote": true, "pre": true,
+}
+
+// parseHTMLFragment parses rich text content, nil when it is empty or invalid
+func parseHTMLFragment(source string) *html.Node {
+	if strings.TrimSpace(source) == "" {
+		return nil
+	}
+	root, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		return nil
+	}
+	return root
+}
+
+// findHTMLElement returns the first element with a tag name
+func findHTMLElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findHTMLElement(child, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// htmlText returns the text of a node with its whitespace collapsed, line
+// breaks being kept between blocks
+func htmlText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.Data == "br":
+			b.WriteString("\n")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+			if child.Type == html.ElementNode && htmlBlockElements[child.Data] {
+				b.WriteString("\n")
+			}
+		}
+	}
+	walk(n)
+
+	lines := strings.Split(b.String(), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
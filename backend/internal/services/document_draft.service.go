@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SaveDraft merges autosaved changes into the draft of the user on a
+// document, creating it from the current revision of the document on the
+// first autosave. The document itself is left untouched.
+func (s *DocumentService) SaveDraft(ctx context.Context, id primitive.ObjectID, req *models.UpdateDocumentRequest, userID primitive.ObjectID) (*models.DocumentDraft, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if document.Status.IsPublished() {
+		return nil, fmt.Errorf("cannot modify document in '%s' status - document is locked", document.Status)
+	}
+
+	changes, err := bson.Marshal(models.NewDocumentDraftChanges(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode draft changes: %w", err)
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(changes, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode draft changes: %w", err)
+	}
+
+	// Only the autosaved fields are replaced, earlier changes are kept
+	now := time.Now()
+	set := bson.M{"updated_at": now}
+	for field, value := range fields {
+		set["changes."+field] = value
+	}
+
+	var draft models.DocumentDraft
+	err = s.draftCollection.FindOneAndUpdate(ctx,
+		bson.M{"document_id": id, "user_id": userID},
+		bson.M{
+			"$set": set,
+			"$setOnInsert": bson.M{
+				"_id":           primitive.NewObjectID(),
+				"base_revision": document.Revision,
+				"created_at":    now,
+			},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&draft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	draft.Stale = draft.BaseRevision != document.Revision
+	return &draft, nil
+}
+
+// GetDraft returns the draft of the user on a document, flagged as stale when
+// the document was modified since the draft was started
+func (s *DocumentService) GetDraft(ctx context.Context, id, userID primitive.ObjectID) (*models.DocumentDraft, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := s.findDraft(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	draft.Stale = draft.BaseRevision != document.Revision
+	return draft, nil
+}
+
+// CommitDraft applies the draft of the user to the document and deletes it.
+// A draft started from an older revision is rejected with
+// ErrDocumentRevisionConflict unless forced.
+func (s *DocumentService) CommitDraft(ctx context.Context, id primitive.ObjectID, force bool, userID primitive.ObjectID, userRole models.UserRole) (*models.Document, error) {
+	draft, err := s.findDraft(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := draft.Changes.ToUpdateRequest()
+	if !force {
+		req.Revision = &draft.BaseRevision
+	}
+
+	document, err := s.Update(ctx, id, req, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.draftCollection.DeleteOne(ctx, bson.M{"_id": draft.ID}); err != nil {
+		return nil, fmt.Errorf("failed to delete committed draft: %w", err)
+	}
+	return document, nil
+}
+
+// DiscardDraft deletes the draft of the user on a document
+func (s *DocumentService) DiscardDraft(ctx context.Context, id, userID primitive.ObjectID) error {
+	result, err := s.draftCollection.DeleteOne(ctx, bson.M{"document_id": id, "user_id": userID})
+	if err != nil {
+		return fmt.Errorf("failed to discard draft: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("draft not found")
+	}
+	return nil
+}
+
+// findDraft returns the draft of the user on a document
+func (s *DocumentService) findDraft(ctx context.Context, id, userID primitive.ObjectID) (*models.DocumentDraft, error) {
+	var draft models.DocumentDraft
+	err := s.draftCollection.FindOne(ctx, bson.M{"document_id": id, "user_id": userID}).Decode(&draft)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("draft not found")
+		}
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+	return &draft, nil
+}
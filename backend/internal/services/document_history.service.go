@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DocumentHistoryService builds the audit trail of a document
+type DocumentHistoryService struct {
+	activityPartitions   *activityLogPartitions
+	versionCollection    *mongo.Collection
+	signatureCollection  *mongo.Collection
+	invitationCollection *mongo.Collection
+	userCollection       *mongo.Collection
+}
+
+// NewDocumentHistoryService creates a new document history service instance
+func NewDocumentHistoryService(db *DatabaseService) *DocumentHistoryService {
+	return &DocumentHistoryService{
+		activityPartitions:   newActivityLogPartitions(db.Database),
+		versionCollection:    db.Collection("document_versions"),
+		signatureCollection:  db.Collection("signatures"),
+		invitationCollection: db.Collection("invitations"),
+		userCollection:       db.Collection("users"),
+	}
+}
+
+// GetHistory merges the activity logs, versions, signatures and invitations of
+// a document into a single chronological timeline
+func (s *DocumentHistoryService) GetHistory(ctx context.Context, documentID primitive.ObjectID, filter *models.DocumentHistoryFilter) ([]models.DocumentHistoryEntry, error) {
+	collectors := map[models.DocumentHistorySource]func(context.Context, primitive.ObjectID) ([]models.DocumentHistoryEntry, error){
+		models.DocumentHistorySourceActivity:   s.activityEntries,
+		models.DocumentHistorySourceVersion:    s.versionEntries,
+		models.DocumentHistorySourceSignature:  s.signatureEntries,
+		models.DocumentHistorySourceInvitation: s.invitationEntries,
+	}
+
+	sources := filter.Sources
+	if len(sources) == 0 {
+		sources = []models.DocumentHistorySource{
+			models.DocumentHistorySourceActivity,
+			models.DocumentHistorySourceVersion,
+			models.DocumentHistorySourceSignature,
+			models.DocumentHistorySourceInvitation,
+		}
+	}
+
+	entries := make([]models.DocumentHistoryEntry, 0)
+	for _, source := range sources {
+		collected, err := collectors[source](ctx, documentID)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range collected {
+			if filter.DateFrom != nil && entry.Timestamp.Before(*filter.DateFrom) {
+				continue
+			}
+			if filter.DateTo != nil && entry.Timestamp.After(*filter.DateTo) {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	if err := s.resolveActors(ctx, entries); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if filter.Desc {
+			return entries[i].Timestamp.After(entries[j].Timestamp)
+		}
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	return entries, nil
+}
+
+// activityEntries lists the activity logs recorded against the document
+func (s *DocumentHistoryService) activityEntries(ctx context.Context, documentID primitive.ObjectID) ([]models.DocumentHistoryEntry, error) {
+	partitions, err := s.activityPartitions.list(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var logs []models.ActivityLog
+	err = s.activityPartitions.stream(ctx, partitions, bson.M{
+		"resource_type": "document",
+		"resource_id":   documentID,
+	}, func(activityLog *models.ActivityLog) error {
+		logs = append(logs, *activityLog)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.DocumentHistoryEntry, 0, len(logs))
+	for _, log := range logs {
+		entry := models.DocumentHistoryEntry{
+			Timestamp:   log.Timestamp,
+			Source:      models.DocumentHistorySourceActivity,
+			Event:       string(log.Action),
+			Description: log.Description,
+			RecordID:    log.ID.Hex(),
+			Details:     log.Details,
+		}
+		if log.UserID != nil || log.ActorName != "" {
+			entry.Actor = &models.DocumentHistoryActor{
+				Name:   log.ActorName,
+				Email:  log.ActorEmail,
+				Avatar: log.ActorAvatar,
+			}
+			if log.UserID != nil {
+				entry.Actor.ID = log.UserID.Hex()
+			}
+		}
+		if !log.Success {
+			if entry.Details == nil {
+				entry.Details = make(map[string]interface{})
+			}
+			entry.Details["success"] = false
+			if log.ErrorMessage != "" {
+				entry.Details["error"] = log.ErrorMessage
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// versionEntries lists the saved versions of the document
+func (s *DocumentHistoryService) versionEntries(ctx context.Context, documentID primitive.ObjectID) ([]models.DocumentHistoryEntry, error) {
+	cursor, err := s.versionCollection.Find(ctx,
+		bson.M{"document_id": documentID},
+		options.Find().SetProjection(bson.M{"data": 0}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find document versions: %w", err)
+	}
+	var versions []models.DocumentVersion
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode document versions: %w", err)
+	}
+
+	entries := make([]models.DocumentHistoryEntry, 0, len(versions))
+	for _, version := range versions {
+		description := fmt.Sprintf("Version %s saved", version.Version)
+		if version.ChangeNote != "" {
+			description = fmt.Sprintf("%s: %s", description, version.ChangeNote)
+		}
+		entries = append(entries, models.DocumentHistoryEntry{
+			Timestamp:   version.CreatedAt,
+			Source:      models.DocumentHistorySourceVersion,
+			Event:       "version_created",
+			Description: description,
+			Actor:       actorRef(version.CreatedBy),
+			Version:     version.Version,
+			RecordID:    version.ID.Hex(),
+		})
+	}
+	return entries, nil
+}
+
+// signatureEntries lists the signatures given on the document
+func (s *DocumentHistoryService) signatureEntries(ctx context.Context, documentID primitive.ObjectID) ([]models.DocumentHistoryEntry, error) {
+	cursor, err := s.signatureCollection.Find(ctx,
+		bson.M{"document_id": documentID},
+		options.Find().SetProjection(bson.M{"signature_data": 0}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signatures: %w", err)
+	}
+	var signatures []models.Signature
+	if err := cursor.All(ctx, &signatures); err != nil {
+		return nil, fmt.Errorf("failed to decode signatures: %w", err)
+	}
+
+	entries := make([]models.DocumentHistoryEntry, 0, len(signatures))
+	for _, signature := range signatures {
+		details := map[string]interface{}{
+			"type":      signature.Type,
+			"ipAddress": signature.IPAddress,
+		}
+		if signature.Comments != "" {
+			details["comments"] = signature.Comments
+		}
+		if signature.InvalidatedAt != nil {
+			details["invalidatedAt"] = signature.InvalidatedAt
+		}
+		entries = append(entries, models.DocumentHistoryEntry{
+			Timestamp:   signature.SignedAt,
+			Source:      models.DocumentHistorySourceSignature,
+			Event:       "document_signed",
+			Description: fmt.Sprintf("Signed as %s", signature.Type),
+			Actor:       actorRef(signature.UserID),
+			Version:     signature.Version,
+			RecordID:    signature.ID.Hex(),
+			Details:     details,
+		})
+	}
+	return entries, nil
+}
+
+// invitationEntries lists the sending, acceptance and refusal of invitations to the document
+func (s *DocumentHistoryService) invitationEntries(ctx context.Context, documentID primitive.ObjectID) ([]models.DocumentHistoryEntry, error) {
+	cursor, err := s.invitationCollection.Find(ctx, bson.M{"document_id": documentID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitations: %w", err)
+	}
+	var invitations []models.Invitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, fmt.Errorf("failed to decode invitations: %w", err)
+	}
+
+	entries := make([]models.DocumentHistoryEntry, 0, len(invitations))
+	for _, invitation := range invitations {
+		details := map[string]interface{}{
+			"invitedEmail": invitation.InvitedEmail,
+			"team":         invitation.Team,
+			"status":       invitation.Status,
+		}
+		sentAt := invitation.SentAt
+		if sentAt.IsZero() {
+			sentAt = invitation.CreatedAt
+		}
+		entries = append(entries, models.DocumentHistoryEntry{
+			Timestamp:   sentAt,
+			Source:      models.DocumentHistorySourceInvitation,
+			Event:       "invitation_sent",
+			Description: fmt.Sprintf("%s invited to the %s", invitation.InvitedEmail, invitation.Team),
+			Actor:       actorRef(invitation.InvitedBy),
+			RecordID:    invitation.ID.Hex(),
+			Details:     details,
+		})
+
+		invitee := &models.DocumentHistoryActor{Email: invitation.InvitedEmail}
+		if invitation.InvitedUserID != nil {
+			invitee.ID = invitation.InvitedUserID.Hex()
+		}
+		if invitation.AcceptedAt != nil {
+			entries = append(entries, models.DocumentHistoryEntry{
+				Timestamp:   *invitation.AcceptedAt,
+				Source:      models.DocumentHistorySourceInvitation,
+				Event:       "invitation_accepted",
+				Description: fmt.Sprintf("%s joined the %s", invitation.InvitedEmail, invitation.Team),
+				Actor:       invitee,
+				RecordID:    invitation.ID.Hex(),
+				Details:     details,
+			})
+		}
+		if invitation.DeclinedAt != nil {
+			declined := details
+			if invitation.DeclineReason != "" {
+				declined = map[string]interface{}{"reason": invitation.DeclineReason}
+				for key, value := range details {
+					declined[key] = value
+				}
+			}
+			entries = append(entries, models.DocumentHistoryEntry{
+				Timestamp:   *invitation.DeclinedAt,
+				Source:      models.DocumentHistorySourceInvitation,
+				Event:       "invitation_declined",
+				Description: fmt.Sprintf("%s declined to join the %s", invitation.InvitedEmail, invitation.Team),
+				Actor:       invitee,
+				RecordID:    invitation.ID.Hex(),
+				Details:     declined,
+			})
+		}
+	}
+	return entries, nil
+}
+
+// resolveActors fills in the name, email and avatar of actors only known by ID
+func (s *DocumentHistoryService) resolveActors(ctx context.Context, entries []models.DocumentHistoryEntry) error {
+	seen := make(map[string]bool)
+	ids := make([]primitive.ObjectID, 0)
+	for _, entry := range entries {
+		if entry.Actor == nil || entry.Actor.ID == "" || entry.Actor.Name != "" || seen[entry.Actor.ID] {
+			continue
+		}
+		seen[entry.Actor.ID] = true
+		if objID, err := primitive.ObjectIDFromHex(entry.Actor.ID); err == nil {
+			ids = append(ids, objID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	cursor, err := s.userCollection.Find(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"first_name": 1, "last_name": 1, "email": 1, "avatar": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to find history actors: %w", err)
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return fmt.Errorf("failed to decode history actors: %w", err)
+	}
+
+	actors := make(map[string]models.User, len(users))
+	for _, user := range users {
+		actors[user.ID.Hex()] = user
+	}
+	for i := range entries {
+		actor := entries[i].Actor
+		if actor == nil || actor.Name != "" {
+			continue
+		}
+		user, ok := actors[actor.ID]
+		if !ok {
+			continue
+		}
+		actor.Name = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		actor.Email = user.Email
+		actor.Avatar = user.Avatar
+	}
+	return nil
+}
+
+// actorRef returns an actor known only by its user ID
+func actorRef(userID primitive.ObjectID) *models.DocumentHistoryActor {
+	if userID.IsZero() {
+		return nil
+	}
+	return &models.DocumentHistoryActor{ID: userID.Hex()}
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxDocumentImportRows bounds the number of data rows of an import spreadsheet
+const MaxDocumentImportRows = 5000
+
+// defaultImportGroupTitle names the process group of steps listed without a group
+const defaultImportGroupTitle = "Process"
+
+// documentImportColumns maps the normalized header names of an import
+// spreadsheet to their column key
+var documentImportColumns = map[string]string{
+	"reference":        "reference",
+	"ref":              "reference",
+	"title":            "title",
+	"macro":            "macro",
+	"macrocode":        "macro",
+	"macroid":          "macro",
+	"processcode":      "processCode",
+	"shortdescription": "shortDescription",
+	"description":      "description",
+	"version":          "version",
+	"stakeholders":     "stakeholders",
+	"objectives":       "objectives",
+	"implicatedactors": "implicatedActors",
+	"actors":           "implicatedActors",
+	"managementrules":  "managementRules",
+	"rules":            "managementRules",
+	"terminology":      "terminology",
+	"group":            "group",
+	"processgroup":     "group",
+	"step":             "step",
+	"processstep":      "step",
+	"responsible":      "responsible",
+	"outputs":          "outputs",
+	"durations":        "durations",
+	"instructions":     "instructions",
+	"task":             "task",
+	"tasks":            "task",
+}
+
+// documentImportDraft accumulates the rows of a document being imported
+type documentImportDraft struct {
+	item     models.DocumentImportItem
+	macroID  primitive.ObjectID
+	request  models.CreateDocumentRequest
+	scalars  map[string]int // Row each document-level column was set on
+	hasError bool
+}
+
+// ImportDocuments reads draft documents from the rows of an import
+// spreadsheet, the first row being the header. Each row starting with a
+// reference or a title opens a document; following rows without either add
+// process steps, tasks and list values (stakeholders, terminology, ...) to it.
+// Documents are only created when the whole file is valid, and never on a
+// dry run.
+func (s *DocumentService) ImportDocuments(ctx context.Context, rows [][]string, defaultMacro string, dryRun bool, userID primitive.ObjectID) (*models.DocumentImportResult, error) {
+	result := &models.DocumentImportResult{
+		DryRun:    dryRun,
+		Documents: []models.DocumentImportItem{},
+		Errors:    []models.DocumentImportRowError{},
+	}
+	addError := func(row int, column, format string, args ...interface{}) {
+		result.Errors = append(result.Errors, models.DocumentImportRowError{Row: row, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(rows) == 0 {
+		addError(1, "", "the file is empty")
+		return result, nil
+	}
+	if len(rows)-1 > MaxDocumentImportRows {
+		addError(1, "", "the file has more than %d rows", MaxDocumentImportRows)
+		return result, nil
+	}
+
+	// Map the header to the column keys
+	columns := make(map[string]int)
+	for i, header := range rows[0] {
+		key, ok := documentImportColumns[normalizeImportHeader(header)]
+		if !ok {
+			continue
+		}
+		if _, duplicate := columns[key]; duplicate {
+			addError(1, header, "duplicate column")
+			continue
+		}
+		columns[key] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		addError(1, "title", "missing title column")
+		return result, nil
+	}
+
+	var drafts []*documentImportDraft
+	var current *documentImportDraft
+	for i, row := range rows[1:] {
+		rowNum := i + 2
+		cell := func(key string) string {
+			if index, ok := columns[key]; ok && index < len(row) {
+				return strings.TrimSpace(row[index])
+			}
+			return ""
+		}
+		if isBlankImportRow(row) {
+			continue
+		}
+		result.TotalRows++
+
+		if cell("reference") != "" || cell("title") != "" {
+			current = &documentImportDraft{
+				item:    models.DocumentImportItem{FirstRow: rowNum},
+				scalars: make(map[string]int),
+			}
+			current.request.Tasks = []models.Task{}
+			current.request.ProcessGroups = []models.ProcessGroup{}
+			drafts = append(drafts, current)
+		} else if current == nil {
+			addError(rowNum, "title", "the first row of a document needs a reference or a title")
+			continue
+		}
+		draft := current
+		draft.item.LastRow = rowNum
+		req := &draft.request
+
+		setScalar := func(key string, field *string) {
+			value := cell(key)
+			if value == "" {
+				return
+			}
+			if *field == "" {
+				*field = value
+				draft.scalars[key] = rowNum
+			} else if *field != value {
+				addError(rowNum, key, "conflicts with the value of row %d", draft.scalars[key])
+				draft.hasError = true
+			}
+		}
+		setScalar("reference", &req.Reference)
+		setScalar("title", &req.Title)
+		setScalar("macro", &draft.item.MacroCode)
+		setScalar("processCode", &req.ProcessCode)
+		setScalar("shortDescription", &req.ShortDescription)
+		setScalar("description", &req.Description)
+		setScalar("version", &req.Version)
+
+		req.Stakeholders = append(req.Stakeholders, splitImportList(cell("stakeholders"))...)
+		req.Metadata.Objectives = append(req.Metadata.Objectives, splitImportList(cell("objectives"))...)
+		req.Metadata.ImplicatedActors = append(req.Metadata.ImplicatedActors, splitImportList(cell("implicatedActors"))...)
+		req.Metadata.ManagementRules = append(req.Metadata.ManagementRules, splitImportList(cell("managementRules"))...)
+		req.Metadata.Terminology = append(req.Metadata.Terminology, splitImportList(cell("terminology"))...)
+
+		// Process steps: a group cell opens a group, a step cell adds a step
+		// to the current group, step details without a step complete the
+		// previous one
+		if group := cell("group"); group != "" {
+			last := len(req.ProcessGroups) - 1
+			if last < 0 || req.ProcessGroups[last].Title != group {
+				req.ProcessGroups = append(req.ProcessGroups, newImportGroup(group, len(req.ProcessGroups)+1))
+			}
+		}
+		outputs, durations := splitImportList(cell("outputs")), splitImportList(cell("durations"))
+		responsible, instructions := cell("responsible"), splitImportList(cell("instructions"))
+		if step := cell("step"); step != "" {
+			if len(req.ProcessGroups) == 0 {
+				req.ProcessGroups = append(req.ProcessGroups, newImportGroup(defaultImportGroupTitle, 1))
+			}
+			group := &req.ProcessGroups[len(req.ProcessGroups)-1]
+			group.ProcessSteps = append(group.ProcessSteps, models.ProcessStep{
+				ID:           primitive.NewObjectID().Hex(),
+				Title:        step,
+				Order:        len(group.ProcessSteps) + 1,
+				Outputs:      nonNilStrings(outputs),
+				Durations:    nonNilStrings(durations),
+				Responsible:  responsible,
+				Descriptions: []models.ProcessDescription{},
+			})
+			draft.item.StepCount++
+		} else if len(outputs) > 0 || len(durations) > 0 || responsible != "" || len(instructions) > 0 {
+			if !appendToLastImportStep(req.ProcessGroups, outputs, durations, responsible) {
+				addError(rowNum, "step", "step details need a step title")
+				draft.hasError = true
+			}
+		}
+		if len(instructions) > 0 {
+			if step := lastImportStep(req.ProcessGroups); step != nil {
+				step.Descriptions = append(step.Descriptions, models.ProcessDescription{
+					Instructions: instructions,
+					Order:        len(step.Descriptions) + 1,
+				})
+			}
+		}
+
+		if task := cell("task"); task != "" {
+			req.Tasks = append(req.Tasks, models.Task{
+				Code:        fmt.Sprintf("T%d", len(req.Tasks)+1),
+				Description: task,
+				IsActive:    true,
+				Order:       len(req.Tasks) + 1,
+			})
+		}
+	}
+
+	// Validate the documents against the macros and the existing documents
+	macros := make(map[string]*models.Macro)
+	references := make(map[string]int)
+	processCodes := make(map[string]int)
+	for _, draft := range drafts {
+		req := &draft.request
+		row := draft.item.FirstRow
+		draft.item.Title = req.Title
+		draft.item.Reference = req.Reference
+		draft.item.GroupCount = len(req.ProcessGroups)
+		draft.item.TaskCount = len(req.Tasks)
+
+		if req.Title == "" {
+			addError(row, "title", "title is required")
+			draft.hasError = true
+		}
+
+		macroRef := draft.item.MacroCode
+		if macroRef == "" {
+			macroRef = defaultMacro
+		}
+		if macroRef == "" {
+			addError(row, "macro", "macro is required")
+			draft.hasError = true
+		} else {
+			macro, ok := macros[macroRef]
+			if !ok {
+				var err error
+				macro, err = s.findImportMacro(ctx, macroRef)
+				if err != nil {
+					return nil, err
+				}
+				macros[macroRef] = macro
+			}
+			if macro == nil {
+				addError(row, "macro", "macro %s not found", macroRef)
+				draft.hasError = true
+			} else {
+				draft.macroID = macro.ID
+				draft.item.MacroCode = macro.Code
+				macroID := macro.ID.Hex()
+				req.MacroID = &macroID
+			}
+		}
+
+		if req.Reference != "" {
+			if first, seen := references[req.Reference]; seen {
+				addError(row, "reference", "reference %s is already used on row %d", req.Reference, first)
+				draft.hasError = true
+			} else {
+				references[req.Reference] = row
+				exists, err := s.referenceExists(ctx, req.Reference)
+				if err != nil {
+					return nil, err
+				}
+				if exists {
+					addError(row, "reference", "document reference %s already exists", req.Reference)
+					draft.hasError = true
+				}
+			}
+		}
+
+		if req.ProcessCode != "" && !draft.macroID.IsZero() {
+			if first, seen := processCodes[req.ProcessCode]; seen {
+				addError(row, "processCode", "process code %s is already used on row %d", req.ProcessCode, first)
+				draft.hasError = true
+			} else {
+				processCodes[req.ProcessCode] = row
+				if err := s.checkProcessCode(ctx, draft.macroID, req.ProcessCode, nil); err != nil {
+					addError(row, "processCode", "%s", err.Error())
+					draft.hasError = true
+				}
+			}
+		}
+	}
+
+	result.Valid = len(result.Errors) == 0
+	if !result.Valid || dryRun {
+		for _, draft := range drafts {
+			result.Documents = append(result.Documents, draft.item)
+		}
+		return result, nil
+	}
+
+	// Create the documents, a failure does not roll back the documents
+	// already created and is reported on its item. They wait in the triage
+	// queue until a quality manager checks them.
+	for _, draft := range drafts {
+		document, err := s.Create(ctx, &draft.request, userID)
+		if err != nil {
+			draft.item.Error = err.Error()
+		} else {
+			if err := s.queueForTriage(ctx, document, models.TriageSourceImport); err != nil {
+				fmt.Printf("⚠️ [IMPORT] Failed to queue %s for triage: %v\n", document.Reference, err)
+			}
+			draft.item.DocumentID = document.ID.Hex()
+			draft.item.Reference = document.Reference
+			result.CreatedCount++
+		}
+		result.Documents = append(result.Documents, draft.item)
+	}
+
+	return result, nil
+}
+
+// findImportMacro resolves the macro of an imported document from its ID or its code
+func (s *DocumentService) findImportMacro(ctx context.Context, value string) (*models.Macro, error) {
+	if id, err := primitive.ObjectIDFromHex(value); err == nil {
+		macro, err := s.macroService.GetMacroByID(ctx, id)
+		if err != nil {
+			if err.Error() == "macro not found" {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return macro, nil
+	}
+	return s.macroService.GetMacroByCode(ctx, strings.ToUpper(value))
+}
+
+// normalizeImportHeader lowercases a header and strips its spaces and punctuation
+func normalizeImportHeader(header string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(header) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// splitImportList splits a list cell on new lines and semicolons
+func splitImportList(value string) []string {
+	var values []string
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == ';' }) {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// isBlankImportRow reports whether every cell of a row is empty
+func isBlankImportRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func newImportGroup(title string, order int) models.ProcessGroup {
+	return models.ProcessGroup{
+		ID:           primitive.NewObjectID().Hex(),
+		Title:        title,
+		Order:        order,
+		ProcessSteps: []models.ProcessStep{},
+	}
+}
+
+// lastImportStep returns the last step of the last group, if any
+func lastImportStep(groups []models.ProcessGroup) *models.ProcessStep {
+	if len(groups) == 0 {
+		return nil
+	}
+	steps := groups[len(groups)-1].ProcessSteps
+	if len(steps) == 0 {
+		return nil
+	}
+	return &steps[len(steps)-1]
+}
+
+// appendToLastImportStep adds step details to the last step, reporting
+// whether there was one
+func appendToLastImportStep(groups []models.ProcessGroup, outputs, durations []string, responsible string) bool {
+	step := lastImportStep(groups)
+	if step == nil {
+		return false
+	}
+	step.Outputs = append(step.Outputs, outputs...)
+	step.Durations = append(step.Durations, durations...)
+	if responsible != "" {
+		step.Responsible = responsible
+	}
+	return true
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
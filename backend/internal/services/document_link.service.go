@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/skip2/go-qrcode"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// appURL returns the URL of the web application, set by APP_URL
+func appURL() string {
+	if v := strings.TrimRight(os.Getenv("APP_URL"), "/"); v != "" {
+		return v
+	}
+	return "http://localhost:3000"
+}
+
+// DocumentShortLink returns the short link to the live version of the
+// documents with a reference, served under SHORT_LINK_BASE_URL, else APP_URL
+func DocumentShortLink(reference string) string {
+	base := strings.TrimRight(os.Getenv("SHORT_LINK_BASE_URL"), "/")
+	if base == "" {
+		base = appURL()
+	}
+	return base + "/d/" + url.PathEscape(reference)
+}
+
+// documentQRCode returns a PNG data URL of the QR code encoding the short
+// link of a document, printed on its PDF so paper copies can be checked
+// against the live version
+func documentQRCode(reference string) template.URL {
+	if reference == "" {
+		return ""
+	}
+	png, err := qrcode.Encode(DocumentShortLink(reference), qrcode.Medium, 256)
+	if err != nil {
+		fmt.Printf("⚠️  [PDF] Failed to encode the QR code of %s: %v\n", reference, err)
+		return ""
+	}
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png))
+}
+
+// ResolveShortLink returns the web application URL of the live version of
+// the documents with a reference: the latest one in force, else the latest
+// revision being written
+func (s *DocumentService) ResolveShortLink(ctx context.Context, reference string) (string, error) {
+	var document models.Document
+	err := s.collection.FindOne(ctx,
+		models.NotDeleted(bson.M{
+			"reference": reference,
+			"status":    bson.M{"$in": []models.DocumentStatus{models.DocumentStatusArchived, models.DocumentStatusReviewDue}},
+		}),
+		options.FindOne().SetSort(bson.D{{Key: "approved_at", Value: -1}, {Key: "created_at", Value: -1}}),
+	).Decode(&document)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = s.collection.FindOne(ctx,
+			models.NotDeleted(bson.M{"reference": reference}),
+			options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+		).Decode(&document)
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", errors.New("document not found")
+		}
+		return "", fmt.Errorf("failed to resolve document link: %w", err)
+	}
+
+	return fmt.Sprintf("%s/documents/%s", appURL(), document.ID.Hex()), nil
+}
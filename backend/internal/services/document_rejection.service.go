@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Reject returns a document under review to its authors. Only a contributor
+// of the team reviewing the document (verifiers or validators) can reject it.
+// The signatures given so far are invalidated: the authors sign again when
+// the document returns to author_review, the other teams wait for the next
+// publication.
+func (s *DocumentService) Reject(ctx context.Context, id primitive.ObjectID, req *models.RejectDocumentRequest, user *models.User) (*models.Document, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var team models.ContributorTeam
+	switch document.Status {
+	case models.DocumentStatusVerifierReview:
+		team = models.ContributorTeamVerifiers
+	case models.DocumentStatusValidatorReview:
+		team = models.ContributorTeamValidators
+	default:
+		return nil, fmt.Errorf("document cannot be rejected from status: %s", document.Status)
+	}
+
+	isReviewer := false
+	for _, contributor := range document.Contributors.Team(team) {
+		if contributor.UserID == user.ID {
+			isReviewer = true
+			break
+		}
+	}
+	if !isReviewer {
+		return nil, fmt.Errorf("only %s of the document can reject it", team)
+	}
+
+	target := req.Target
+	if target == "" {
+		target = models.DocumentStatusAuthorReview
+	}
+	if target == models.DocumentStatusAuthorReview && len(document.Contributors.Authors) == 0 {
+		return nil, errors.New("document cannot be returned to author_review without authors")
+	}
+
+	// Reset the signature statuses: the authors sign again right away when
+	// returned to author_review, everyone else waits for the next publication
+	contributors := models.Contributors{
+		Authors:    resetContributors(document.Contributors.Authors),
+		Verifiers:  resetContributors(document.Contributors.Verifiers),
+		Validators: resetContributors(document.Contributors.Validators),
+	}
+	if target == models.DocumentStatusAuthorReview {
+		for i := range contributors.Authors {
+			contributors.Authors[i].Status = models.SignatureStatusPending
+		}
+	}
+
+	now := time.Now()
+	rejection := &models.DocumentRejection{
+		Reason:         req.Reason,
+		FromStatus:     document.Status,
+		ToStatus:       target,
+		Team:           team,
+		RejectedBy:     user.ID,
+		RejectedByName: fmt.Sprintf("%s %s", user.FirstName, user.LastName),
+		RejectedAt:     now,
+	}
+
+	var updated models.Document
+	err = s.collection.FindOneAndUpdate(ctx,
+		models.NotDeleted(bson.M{"_id": id, "status": document.Status, "revision": revisionFilter(document.Revision)}),
+		models.InvalidatePDF(bson.M{
+			"$set": bson.M{
+				"status":         target,
+				"contributors":   contributors,
+				"last_rejection": rejection,
+				"stage_deadline": NewStageDeadline(document, target, now),
+				"updated_at":     now,
+			},
+			"$inc": bson.M{"revision": 1},
+		}),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrDocumentRevisionConflict
+		}
+		return nil, fmt.Errorf("failed to reject document: %w", err)
+	}
+
+	// Signatures are kept for the audit trail but no longer count
+	if _, err := s.signatureCollection.UpdateMany(ctx,
+		models.ActiveSignatures(bson.M{"document_id": id}),
+		bson.M{"$set": bson.M{"invalidated_at": now}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to invalidate signatures: %w", err)
+	}
+
+	return &updated, nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// overdueReviewListLimit bounds the overdue documents listed in the statistics
+const overdueReviewListLimit = 20
+
+// GetStatistics summarizes the documents of the organization in a single
+// aggregation: counts per status and per creator department, the average
+// time from creation to approval, the signatures owed by each contributor
+// and the documents overdue for their periodic review
+func (s *DocumentService) GetStatistics(ctx context.Context) (*models.DocumentStatsResponse, error) {
+	now := time.Now()
+	overdue := bson.M{"$or": bson.A{
+		bson.M{"status": models.DocumentStatusReviewDue},
+		bson.M{"status": models.DocumentStatusArchived, "next_review_date": bson.M{"$lt": now}},
+	}}
+
+	facets := bson.M{
+		"statuses": bson.A{
+			bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+		},
+		"departments": bson.A{
+			bson.M{"$lookup": bson.M{
+				"from":         "users",
+				"localField":   "created_by",
+				"foreignField": "_id",
+				"as":           "creator",
+			}},
+			bson.M{"$group": bson.M{
+				"_id":   bson.M{"$arrayElemAt": bson.A{"$creator.department_id", 0}},
+				"total": bson.M{"$sum": 1},
+				"published": bson.M{"$sum": bson.M{"$cond": bson.A{
+					bson.M{"$in": bson.A{"$status", models.PublishedDocumentStatuses}}, 1, 0,
+				}}},
+			}},
+			bson.M{"$lookup": bson.M{
+				"from":         "departments",
+				"localField":   "_id",
+				"foreignField": "_id",
+				"as":           "department",
+			}},
+			bson.M{"$project": bson.M{
+				"total":     1,
+				"published": 1,
+				"name":      bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$department.name", 0}}, "No department"}},
+			}},
+			bson.M{"$sort": bson.D{{Key: "total", Value: -1}, {Key: "name", Value: 1}}},
+		},
+		"approval": bson.A{
+			bson.M{"$match": bson.M{"approved_at": bson.M{"$ne": nil}}},
+			bson.M{"$group": bson.M{
+				"_id":     nil,
+				"avgMs":   bson.M{"$avg": bson.M{"$subtract": bson.A{"$approved_at", "$created_at"}}},
+				"samples": bson.M{"$sum": 1},
+			}},
+		},
+		"signatures": bson.A{
+			bson.M{"$project": bson.M{"contributors": bson.M{"$concatArrays": bson.A{
+				bson.M{"$ifNull": bson.A{"$contributors.authors", bson.A{}}},
+				bson.M{"$ifNull": bson.A{"$contributors.verifiers", bson.A{}}},
+				bson.M{"$ifNull": bson.A{"$contributors.validators", bson.A{}}},
+			}}}},
+			bson.M{"$unwind": "$contributors"},
+			bson.M{"$match": bson.M{"contributors.status": models.SignatureStatusPending}},
+			bson.M{"$group": bson.M{"_id": "$contributors.user_id", "pending": bson.M{"$sum": 1}}},
+			bson.M{"$lookup": bson.M{
+				"from":         "users",
+				"localField":   "_id",
+				"foreignField": "_id",
+				"as":           "user",
+			}},
+			bson.M{"$project": bson.M{
+				"pending": 1,
+				"email":   bson.M{"$arrayElemAt": bson.A{"$user.email", 0}},
+				"name": bson.M{"$trim": bson.M{"input": bson.M{"$concat": bson.A{
+					bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$user.first_name", 0}}, ""}}, " ",
+					bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$user.last_name", 0}}, ""}},
+				}}}},
+			}},
+			bson.M{"$sort": bson.D{{Key: "pending", Value: -1}, {Key: "name", Value: 1}}},
+		},
+		"overdueCount": bson.A{
+			bson.M{"$match": overdue},
+			bson.M{"$count": "count"},
+		},
+		"overdue": bson.A{
+			bson.M{"$match": overdue},
+			bson.M{"$sort": bson.D{{Key: "next_review_date", Value: 1}}},
+			bson.M{"$limit": overdueReviewListLimit},
+			bson.M{"$project": bson.M{"reference": 1, "title": 1, "version": 1, "next_review_date": 1}},
+		},
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: models.NotDeleted(bson.M{})}},
+		{{Key: "$facet", Value: facets}},
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate document statistics: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Statuses []struct {
+			ID    models.DocumentStatus `bson:"_id"`
+			Count int64                 `bson:"count"`
+		} `bson:"statuses"`
+		Departments []models.DepartmentDocumentCount `bson:"departments"`
+		Approval    []struct {
+			AvgMs   float64 `bson:"avgMs"`
+			Samples int64   `bson:"samples"`
+		} `bson:"approval"`
+		Signatures   []models.PendingSignatureCount `bson:"signatures"`
+		OverdueCount []struct {
+			Count int64 `bson:"count"`
+		} `bson:"overdueCount"`
+		Overdue []models.OverdueReviewDocument `bson:"overdue"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode document statistics: %w", err)
+	}
+
+	stats := &models.DocumentStatsResponse{
+		ByStatus:          make(map[models.DocumentStatus]int64),
+		ByDepartment:      []models.DepartmentDocumentCount{},
+		PendingSignatures: []models.PendingSignatureCount{},
+		OverdueReviews:    []models.OverdueReviewDocument{},
+		GeneratedAt:       now,
+	}
+	if len(results) == 0 {
+		return stats, nil
+	}
+	result := results[0]
+
+	for _, status := range result.Statuses {
+		stats.ByStatus[status.ID] = status.Count
+		stats.Total += status.Count
+	}
+	if result.Departments != nil {
+		stats.ByDepartment = result.Departments
+	}
+	if len(result.Approval) > 0 {
+		stats.AverageDraftToApprovedHours = result.Approval[0].AvgMs / float64(time.Hour/time.Millisecond)
+		stats.ApprovedSampleSize = result.Approval[0].Samples
+	}
+	if result.Signatures != nil {
+		stats.PendingSignatures = result.Signatures
+	}
+	if len(result.OverdueCount) > 0 {
+		stats.OverdueReviewCount = result.OverdueCount[0].Count
+	}
+	if result.Overdue != nil {
+		stats.OverdueReviews = result.Overdue
+	}
+
+	return stats, nil
+}
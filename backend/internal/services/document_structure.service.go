@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportStructure returns the canonical structure of a document
+func (s *DocumentService) ExportStructure(ctx context.Context, id primitive.ObjectID) (*models.DocumentStructure, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var macroCode string
+	if document.MacroID != nil {
+		macro, err := s.macroService.GetMacroByID(ctx, *document.MacroID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get macro: %w", err)
+		}
+		macroCode = macro.Code
+	}
+
+	return models.NewDocumentStructure(document, macroCode), nil
+}
+
+// ImportStructure creates a draft document from an exported structure. The
+// macro is looked up by code; the process code is kept when it is free in
+// the macro, otherwise the next one is generated.
+func (s *DocumentService) ImportStructure(ctx context.Context, structure *models.DocumentStructure, userID primitive.ObjectID) (*models.Document, error) {
+	if structure.SchemaVersion != models.DocumentStructureSchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d", structure.SchemaVersion)
+	}
+	if strings.TrimSpace(structure.Title) == "" {
+		return nil, errors.New("title is required")
+	}
+	if structure.MacroCode == "" {
+		return nil, errors.New("macro code is required")
+	}
+
+	macro, err := s.macroService.GetMacroByCode(ctx, structure.MacroCode)
+	if err != nil {
+		return nil, err
+	}
+	if macro == nil {
+		return nil, fmt.Errorf("macro %s not found", structure.MacroCode)
+	}
+
+	processCode := structure.ProcessCode
+	if processCode != "" {
+		if err := s.checkProcessCode(ctx, macro.ID, processCode, nil); err != nil {
+			processCode = ""
+		}
+	}
+
+	req := structure.ToCreateRequest(macro.ID.Hex(), processCode, func() string {
+		return primitive.NewObjectID().Hex()
+	})
+	return s.Create(ctx, req, userID)
+}
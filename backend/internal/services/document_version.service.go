@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// versionPDFTimeout bounds the rendering of a version snapshot PDF in the background
+const versionPDFTimeout = 3 * time.Minute
+
+// CreateVersion stores a version snapshot of a document. With
+// VERSION_PDF_SNAPSHOTS enabled, the PDF of the snapshot is rendered in the
+// background so the version stays reproducible after later edits.
+func (s *DocumentService) CreateVersion(ctx context.Context, document *models.Document, userID primitive.ObjectID, changeNote string) error {
+	version := &models.DocumentVersion{
+		ID:         primitive.NewObjectID(),
+		DocumentID: document.ID,
+		Version:    document.Version,
+		Data:       *document,
+		CreatedBy:  userID,
+		CreatedAt:  time.Now(),
+		ChangeNote: changeNote,
+	}
+
+	_, err := s.versionCollection.InsertOne(ctx, version)
+	if err != nil {
+		return fmt.Errorf("failed to create version: %w", err)
+	}
+
+	if s.versionPDFSnapshots && s.pdfService != nil {
+		go s.snapshotVersionPDF(version)
+	}
+
+	return nil
+}
+
+// GetVersion retrieves one version of a document
+func (s *DocumentService) GetVersion(ctx context.Context, documentID, versionID primitive.ObjectID) (*models.DocumentVersion, error) {
+	var version models.DocumentVersion
+	err := s.versionCollection.FindOne(ctx, bson.M{"_id": versionID, "document_id": documentID}).Decode(&version)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("version not found")
+		}
+		return nil, fmt.Errorf("failed to get version: %w", err)
+	}
+	return &version, nil
+}
+
+// VersionPDF returns the PDF of a version snapshot. Versions created without
+// a snapshot PDF get one rendered from their snapshot on the first request,
+// which is then kept for the next ones.
+func (s *DocumentService) VersionPDF(ctx context.Context, documentID, versionID, requestedBy primitive.ObjectID) (string, error) {
+	version, err := s.GetVersion(ctx, documentID, versionID)
+	if err != nil {
+		return "", err
+	}
+
+	pdfURL := version.PdfUrl
+	if pdfURL == "" {
+		if s.pdfService == nil {
+			return "", fmt.Errorf("PDF service not available")
+		}
+		if pdfURL, err = s.renderVersionPDF(ctx, version); err != nil {
+			return "", err
+		}
+	}
+
+	return pdfURL, s.checkPDFExport(ctx, &version.Data, pdfURL, models.PDFExportOptions{RequestedBy: requestedBy})
+}
+
+// renderVersionPDF renders the PDF of a version snapshot and stores its URL on the version
+func (s *DocumentService) renderVersionPDF(ctx context.Context, version *models.DocumentVersion) (string, error) {
+	fmt.Printf("📄 [VERSION] Generating PDF for version %s of %s\n", version.Version, version.Data.Reference)
+	pdfURL, err := s.pdfService.GenerateDocumentPDF(ctx, &version.Data, models.PDFExportOptions{Snapshot: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	// A snapshot PDF is never replaced, a concurrent render keeps the first one
+	result, err := s.versionCollection.UpdateOne(ctx,
+		bson.M{"_id": version.ID, "pdf_url": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"pdf_url": pdfURL}},
+	)
+	if err != nil {
+		fmt.Printf("⚠️ [VERSION] Failed to store PDF URL of version %s: %v\n", version.ID.Hex(), err)
+		return pdfURL, nil
+	}
+	if result.ModifiedCount == 0 {
+		stored, err := s.GetVersion(ctx, version.DocumentID, version.ID)
+		if err == nil && stored.PdfUrl != "" {
+			return stored.PdfUrl, nil
+		}
+	}
+
+	version.PdfUrl = pdfURL
+	return pdfURL, nil
+}
+
+// snapshotVersionPDF renders the PDF of a new version in the background
+func (s *DocumentService) snapshotVersionPDF(version *models.DocumentVersion) {
+	ctx, cancel := context.WithTimeout(context.Background(), versionPDFTimeout)
+	defer cancel()
+
+	if _, err := s.renderVersionPDF(withPDFRenderTimeout(ctx, versionPDFTimeout), version); err != nil {
+		fmt.Printf("⚠️ [VERSION] Failed to snapshot PDF of version %s: %v\n", version.ID.Hex(), err)
+	}
+}
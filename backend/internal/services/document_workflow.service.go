@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EffectiveWorkflow returns the workflow driving a document: its own, else
+// the one of its macro, else the default one
+func (s *DocumentService) EffectiveWorkflow(ctx context.Context, document *models.Document) (*models.EffectiveWorkflow, error) {
+	if document.Workflow != nil {
+		return &models.EffectiveWorkflow{Source: models.WorkflowSourceDocument, Workflow: document.Workflow}, nil
+	}
+
+	if document.MacroID != nil {
+		macro, err := s.macroService.GetMacroByID(ctx, *document.MacroID)
+		if err != nil && err.Error() != "macro not found" {
+			return nil, err
+		}
+		if macro != nil && macro.Workflow != nil {
+			return &models.EffectiveWorkflow{Source: models.WorkflowSourceMacro, Workflow: macro.Workflow}, nil
+		}
+	}
+
+	return &models.EffectiveWorkflow{Source: models.WorkflowSourceDefault, Workflow: models.DefaultWorkflow()}, nil
+}
+
+// GetWorkflow returns the workflow driving a document
+func (s *DocumentService) GetWorkflow(ctx context.Context, id primitive.ObjectID) (*models.EffectiveWorkflow, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.EffectiveWorkflow(ctx, document)
+}
+
+// SetWorkflow sets the workflow of a document, overriding the one of its
+// macro. The workflow of a document in signature cannot change.
+func (s *DocumentService) SetWorkflow(ctx context.Context, id primitive.ObjectID, workflow *models.WorkflowDefinition) (*models.Document, error) {
+	if err := workflow.Validate(); err != nil {
+		return nil, err
+	}
+	return s.updateWorkflow(ctx, id, bson.M{"$set": bson.M{"workflow": workflow}})
+}
+
+// ClearWorkflow removes the workflow of a document, which follows the one of
+// its macro again
+func (s *DocumentService) ClearWorkflow(ctx context.Context, id primitive.ObjectID) (*models.Document, error) {
+	return s.updateWorkflow(ctx, id, bson.M{"$unset": bson.M{"workflow": ""}})
+}
+
+// updateWorkflow applies a workflow update to a draft document
+func (s *DocumentService) updateWorkflow(ctx context.Context, id primitive.ObjectID, update bson.M) (*models.Document, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if document.Status != models.DocumentStatusDraft {
+		return nil, fmt.Errorf("workflow cannot be changed in '%s' status - only draft documents can change workflow", document.Status)
+	}
+
+	if _, ok := update["$set"]; !ok {
+		update["$set"] = bson.M{}
+	}
+	update["$set"].(bson.M)["updated_at"] = time.Now()
+	update["$inc"] = bson.M{"revision": 1}
+
+	result, err := s.collection.UpdateOne(ctx,
+		models.NotDeleted(bson.M{"_id": id, "status": models.DocumentStatusDraft}),
+		update,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update workflow: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("document not found")
+	}
+
+	return s.GetByID(ctx, id)
+}
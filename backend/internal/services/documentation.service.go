@@ -134,7 +134,7 @@ func (s *DocumentationService) AggregateData(ctx context.Context) (*PublicDocume
 		// Fetch Active Processes for this Macro
 		// Sort by 'order' then 'process_code'
 		procOpts := options.Find().SetSort(bson.D{{Key: "order", Value: 1}, {Key: "process_code", Value: 1}})
-		procCursor, err := docCollection.Find(ctx, bson.M{"macro_id": macro.ID, "is_active": true}, procOpts)
+		procCursor, err := docCollection.Find(ctx, models.NotDeleted(bson.M{"macro_id": macro.ID, "is_active": true}), procOpts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch processes for macro %s: %w", macro.Code, err)
 		}
@@ -2,66 +2,45 @@ package services
 
 import (
 	"bytes"
-	"crypto/tls"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"html/template"
-	"io"
 	"net"
-	"net/http"
-	"net/smtp"
 	"os"
-	"strconv"
 	"strings"
+	"sync"
+	texttemplate "text/template"
 	"time"
+
+	"github.com/kodesonik/process-manager/internal/i18n"
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type EmailService struct {
-	smtpHost     string
-	smtpPort     int
-	smtpUsername string
-	smtpPassword string
-	fromEmail    string
-	fromName     string
-	appURL       string
-	// Brevo configuration
-	brevoAPIKey string
-	brevoAPIURL string
-
-	// External PHP Mailer API
-	mailerAPIURL string
-	mailerAPIKey string
+	fromEmail string
+	fromName  string
+	appURL    string
+	providers []EmailProvider // In failover order, selected by EMAIL_PROVIDERS
+
+	brandingService *BrandingService
+	templates       *TemplateCache        // Parsed email bodies, keyed by source
+	outbox          *EmailOutboxService   // Queue retrying the deliveries, set up by NewEmailOutboxService
+	customTemplates *EmailTemplateService // Templates edited by the admins, set up by NewEmailTemplateService
+	eventSettings   *EmailSettingsService // Events whose emails are turned off, set up by NewEmailSettingsService
+	throttle        *EmailThrottleService // Per-recipient limit and duplicate check, set up by NewEmailThrottleService
 }
 
+// emailTemplateCacheSize bounds the number of parsed email bodies kept in memory
+const emailTemplateCacheSize = 128
+
 type EmailTemplate struct {
 	Subject  string
 	HTMLBody string
 	TextBody string
 }
 
-// Brevo API structures
-type BrevoEmailRequest struct {
-	Sender      BrevoSender    `json:"sender"`
-	To          []BrevoContact `json:"to"`
-	Subject     string         `json:"subject"`
-	HTMLContent string         `json:"htmlContent"`
-	TextContent string         `json:"textContent,omitempty"`
-}
-
-type BrevoSender struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-}
-
-type BrevoContact struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-}
-
-type BrevoResponse struct {
-	MessageID string `json:"messageId"`
-}
-
 type EmailData struct {
 	UserName        string
 	UserEmail       string
@@ -83,25 +62,36 @@ type EmailData struct {
 	InvitationURL string
 	RoleName      string
 	TeamName      string
+	// Periodic review fields
+	DocumentURL   string
+	ReviewDueDate string
+	// Signature reminder fields
+	SignatureDueDate string
+	// Files attached to the email, such as calendar invites
+	Attachments []models.EmailAttachment
+	// Tells apart the sends of an email that are not duplicates of each
+	// other, such as the reminders sent manually
+	DedupNonce string
+	// Notification digest fields
+	DigestEntries    []EmailDigestEntry
+	DigestCount      int
+	NotificationsURL string
+	// Branding fields, filled from the organization branding when sending
+	LogoURL        string
+	PrimaryColor   string
+	SecondaryColor string
+	FooterText     string
 }
 
-func NewEmailService() *EmailService {
-	smtpHost := os.Getenv("SMTP_HOST")
-	if smtpHost == "" {
-		smtpHost = "smtp.hostinger.com"
-	}
-
-	smtpPortStr := os.Getenv("SMTP_PORT")
-	smtpPort := 465
-	if smtpPortStr != "" {
-		if port, err := strconv.Atoi(smtpPortStr); err == nil {
-			smtpPort = port
-		}
-	}
-
-	smtpUsername := os.Getenv("SMTP_USERNAME")
-	smtpPassword := os.Getenv("SMTP_PASSWORD")
+// EmailDigestEntry is a notification listed in a digest email
+type EmailDigestEntry struct {
+	Title string
+	Body  string
+	URL   string
+	Time  string
+}
 
+func NewEmailService(brandingService *BrandingService) *EmailService {
 	fromEmail := os.Getenv("FROM_EMAIL")
 	if fromEmail == "" {
 		fromEmail = "noreply@process-manager.com"
@@ -117,148 +107,111 @@ func NewEmailService() *EmailService {
 		appURL = "http://localhost:3000"
 	}
 
-	// Brevo configuration
-	brevoAPIKey := os.Getenv("BREVO_KEY")
-	brevoAPIURL := "https://api.brevo.com/v3/smtp/email"
-
-	// PHP Mailer API configuration
-	mailerAPIURL := os.Getenv("MAILER_API_URL")
-	mailerAPIKey := os.Getenv("MAILER_API_KEY")
-
-	return &EmailService{
-		smtpHost:     smtpHost,
-		smtpPort:     smtpPort,
-		smtpUsername: smtpUsername,
-		smtpPassword: smtpPassword,
-		fromEmail:    fromEmail,
-		fromName:     fromName,
-		appURL:       appURL,
-		brevoAPIKey:  brevoAPIKey,
-		brevoAPIURL:  brevoAPIURL,
-		mailerAPIURL: mailerAPIURL,
-		mailerAPIKey: mailerAPIKey,
+	service := &EmailService{
+		fromEmail:       fromEmail,
+		fromName:        fromName,
+		appURL:          appURL,
+		providers:       newEmailProviders(fromName, fromEmail),
+		brandingService: brandingService,
+		templates:       NewTemplateCache(emailTemplateCacheSize),
+	}
+	if brandingService != nil {
+		brandingService.OnChange(service.templates.Invalidate)
 	}
+
+	return service
 }
 
-func (e *EmailService) SendWelcomeEmail(userEmail, userName string) error {
+func (e *EmailService) SendWelcomeEmail(userEmail, userName, lang string) error {
 	data := EmailData{
-		UserName:     userName,
-		UserEmail:    userEmail,
-		AppName:      "Process Manager",
-		AppURL:       e.appURL,
-		SupportEmail: "support@process-manager.com",
-		CompanyName:  "Process Manager Team",
+		UserName:  userName,
+		UserEmail: userEmail,
+		AppURL:    e.appURL,
 	}
 
-	template := e.getWelcomeTemplate()
-	return e.sendEmail(userEmail, userName, template, data)
+	return e.sendSystemEmail("welcome", lang, userEmail, userName, data)
 }
 
-func (e *EmailService) SendVerificationEmail(userEmail, userName, token string) error {
+func (e *EmailService) SendVerificationEmail(userEmail, userName, token, lang string) error {
 	verificationURL := fmt.Sprintf("%s/verify-email?token=%s", e.appURL, token)
 
 	data := EmailData{
 		UserName:        userName,
 		UserEmail:       userEmail,
-		AppName:         "Process Manager",
 		AppURL:          e.appURL,
 		VerificationURL: verificationURL,
 		Token:           token,
-		SupportEmail:    "support@process-manager.com",
-		CompanyName:     "Process Manager Team",
 	}
 
-	template := e.getVerificationTemplate()
-	return e.sendEmail(userEmail, userName, template, data)
+	return e.sendSystemEmail("verification", lang, userEmail, userName, data)
 }
 
 // SendOTPEmail sends an OTP code via email
-func (e *EmailService) SendOTPEmail(userEmail, userName, otp string) error {
+func (e *EmailService) SendOTPEmail(userEmail, userName, otp, lang string) error {
 	data := EmailData{
-		UserName:     userName,
-		UserEmail:    userEmail,
-		AppName:      "Process Manager",
-		AppURL:       e.appURL,
-		OTP:          otp,
-		OTPExpiry:    "5 minutes",
-		SupportEmail: "support@process-manager.com",
-		CompanyName:  "Process Manager Team",
-	}
-
-	template := e.getOTPTemplate()
-	return e.sendEmail(userEmail, userName, template, data)
+		UserName:  userName,
+		UserEmail: userEmail,
+		AppURL:    e.appURL,
+		OTP:       otp,
+		OTPExpiry: "5 minutes",
+	}
+
+	return e.sendSystemEmail("otp", lang, userEmail, userName, data)
 }
 
 // SendRegistrationOTPEmail sends OTP email specifically for registration
-func (e *EmailService) SendRegistrationOTPEmail(userEmail, otp string) error {
+func (e *EmailService) SendRegistrationOTPEmail(userEmail, otp, lang string) error {
 	data := EmailData{
-		UserEmail:    userEmail,
-		AppName:      "Process Manager",
-		AppURL:       e.appURL,
-		OTP:          otp,
-		OTPExpiry:    "5 minutes",
-		SupportEmail: "support@process-manager.com",
-		CompanyName:  "Process Manager Team",
+		UserEmail: userEmail,
+		AppURL:    e.appURL,
+		OTP:       otp,
+		OTPExpiry: "5 minutes",
 	}
 
-	template := e.getRegistrationOTPTemplate()
-	return e.sendEmail(userEmail, "", template, data)
+	return e.sendSystemEmail("registration_otp", lang, userEmail, "", data)
 }
 
 // SendRegistrationPendingEmail sends confirmation that registration is pending admin approval
-func (e *EmailService) SendRegistrationPendingEmail(userEmail, userName string) error {
+func (e *EmailService) SendRegistrationPendingEmail(userEmail, userName, lang string) error {
 	data := EmailData{
-		UserName:     userName,
-		UserEmail:    userEmail,
-		AppName:      "Process Manager",
-		AppURL:       e.appURL,
-		SupportEmail: "support@process-manager.com",
-		CompanyName:  "Process Manager Team",
+		UserName:  userName,
+		UserEmail: userEmail,
+		AppURL:    e.appURL,
 	}
 
-	template := e.getRegistrationPendingTemplate()
-	return e.sendEmail(userEmail, userName, template, data)
+	return e.sendSystemEmail("registration_pending", lang, userEmail, userName, data)
 }
 
 // SendAccountApprovedEmail sends confirmation that account has been approved
-func (e *EmailService) SendAccountApprovedEmail(userEmail, userName string) error {
+func (e *EmailService) SendAccountApprovedEmail(userEmail, userName, lang string) error {
 	data := EmailData{
-		UserName:     userName,
-		UserEmail:    userEmail,
-		AppName:      "Process Manager",
-		AppURL:       e.appURL,
-		SupportEmail: "support@process-manager.com",
-		CompanyName:  "Process Manager Team",
+		UserName:  userName,
+		UserEmail: userEmail,
+		AppURL:    e.appURL,
 	}
 
-	template := e.getAccountApprovedTemplate()
-	return e.sendEmail(userEmail, userName, template, data)
+	return e.sendSystemEmail("account_approved", lang, userEmail, userName, data)
 }
 
 // SendAccountRejectedEmail sends notification that account registration was rejected
-func (e *EmailService) SendAccountRejectedEmail(userEmail, userName, reason string) error {
+func (e *EmailService) SendAccountRejectedEmail(userEmail, userName, reason, lang string) error {
 	data := EmailData{
 		UserName:        userName,
 		UserEmail:       userEmail,
-		AppName:         "Process Manager",
 		AppURL:          e.appURL,
 		RejectionReason: reason,
-		SupportEmail:    "support@process-manager.com",
-		CompanyName:     "Process Manager Team",
 	}
 
-	template := e.getAccountRejectedTemplate()
-	return e.sendEmail(userEmail, userName, template, data)
+	return e.sendSystemEmail("account_rejected", lang, userEmail, userName, data)
 }
 
 // SendInvitationEmail sends a collaboration invitation email
-func (e *EmailService) SendInvitationEmail(userEmail, userName, inviterName, documentTitle, documentRef, teamName, invitationToken string) error {
+func (e *EmailService) SendInvitationEmail(userEmail, userName, inviterName, documentTitle, documentRef, teamName, invitationToken, lang string) error {
 	invitationURL := fmt.Sprintf("%s/invitations/accept?token=%s", e.appURL, invitationToken)
 
 	data := EmailData{
 		UserName:      userName,
 		UserEmail:     userEmail,
-		AppName:       "Process Manager",
 		AppURL:        e.appURL,
 		InviterName:   inviterName,
 		DocumentTitle: documentTitle,
@@ -266,366 +219,467 @@ func (e *EmailService) SendInvitationEmail(userEmail, userName, inviterName, doc
 		InvitationURL: invitationURL,
 		TeamName:      teamName,
 		Token:         invitationToken,
-		SupportEmail:  "support@process-manager.com",
-		CompanyName:   "Process Manager Team",
 	}
 
-	template := e.getInvitationTemplate()
-	return e.sendEmail(userEmail, userName, template, data)
+	return e.sendSystemEmail("invitation", lang, userEmail, userName, data)
 }
 
-func (e *EmailService) sendEmail(toEmail, toName string, emailTemplate EmailTemplate, data EmailData) error {
-	// Log email method configuration
-	fmt.Printf("🔧 Email Configuration - MailerAPI: %t, Brevo: %t, SMTP: %t\n",
-		e.mailerAPIURL != "",
-		e.brevoAPIKey != "",
-		e.smtpUsername != "" && e.smtpPassword != "")
-
-	// Prefer external Mailer API if configured
-	if e.mailerAPIURL != "" {
-		fmt.Printf("📧 Using Mailer API to send email to %s...\n", toEmail)
-		if err := e.sendEmailViaMailerAPI(toEmail, toName, emailTemplate, data); err != nil {
-			fmt.Printf("❌ Mailer API failed: %v\n", err)
-			return err
-		}
-		fmt.Printf("✅ Email successfully sent via Mailer API to %s\n", toEmail)
-		return nil
-	}
-
-	// Fallback to Brevo if available
-	if e.brevoAPIKey != "" {
-		fmt.Printf("📧 Using Brevo API to send email to %s...\n", toEmail)
-		if err := e.sendEmailViaBrevo(toEmail, toName, emailTemplate, data); err != nil {
-			fmt.Printf("❌ Brevo API failed: %v\n", err)
-			return err
-		}
-		fmt.Printf("✅ Email successfully sent via Brevo API to %s\n", toEmail)
-		return nil
-	}
-
-	// Finally fallback to SMTP
-	if e.smtpUsername != "" && e.smtpPassword != "" {
-		fmt.Printf("📧 Using SMTP to send email to %s...\n", toEmail)
-		if err := e.sendEmailViaSMTP(toEmail, toName, emailTemplate, data); err != nil {
-			fmt.Printf("❌ SMTP failed: %v\n", err)
-			return err
-		}
-		fmt.Printf("✅ Email successfully sent via SMTP to %s\n", toEmail)
-		return nil
+// SendReviewDueEmail notifies an author that a published document must be re-validated
+func (e *EmailService) SendReviewDueEmail(userEmail, userName, documentTitle, documentRef, documentID string, dueDate time.Time, lang string) error {
+	data := EmailData{
+		UserName:      userName,
+		UserEmail:     userEmail,
+		AppURL:        e.appURL,
+		DocumentTitle: documentTitle,
+		DocumentRef:   documentRef,
+		DocumentURL:   fmt.Sprintf("%s/documents/%s", e.appURL, documentID),
+		ReviewDueDate: dueDate.Format("02/01/2006"),
 	}
 
-	return fmt.Errorf("no email method available")
+	return e.sendSystemEmail("review_due", lang, userEmail, userName, data)
 }
 
-// sendEmailViaMailerAPI sends email using the external PHP mailer API
-func (e *EmailService) sendEmailViaMailerAPI(toEmail, toName string, emailTemplate EmailTemplate, data EmailData) error {
-	if e.mailerAPIURL == "" {
-		return fmt.Errorf("Mailer API URL not configured")
+// SendSignatureReminderEmail reminds a contributor that a document under
+// review is waiting for their signature. dueDate is the stage deadline, nil
+// when there is none. Manual reminders are sent even when a scheduled one
+// went out recently, instead of being dropped as its duplicate.
+func (e *EmailService) SendSignatureReminderEmail(userEmail, userName, documentTitle, documentRef, documentID string, dueDate *time.Time, manual bool, lang string) error {
+	data := EmailData{
+		UserName:      userName,
+		UserEmail:     userEmail,
+		AppURL:        e.appURL,
+		DocumentTitle: documentTitle,
+		DocumentRef:   documentRef,
+		DocumentURL:   fmt.Sprintf("%s/documents/%s", e.appURL, documentID),
 	}
-
-	// Render templates
-	htmlTemplate, err := template.New("html").Parse(emailTemplate.HTMLBody)
-	if err != nil {
-		return fmt.Errorf("failed to parse HTML template: %w", err)
+	if dueDate != nil {
+		data.SignatureDueDate = dueDate.Format("02/01/2006")
 	}
-
-	textTemplate, err := template.New("text").Parse(emailTemplate.TextBody)
-	if err != nil {
-		return fmt.Errorf("failed to parse text template: %w", err)
+	if manual {
+		data.DedupNonce = primitive.NewObjectID().Hex()
 	}
 
-	var htmlBuffer, textBuffer bytes.Buffer
-	if err := htmlTemplate.Execute(&htmlBuffer, data); err != nil {
-		return fmt.Errorf("failed to execute HTML template: %w", err)
+	return e.sendSystemEmail("signature_reminder", lang, userEmail, userName, data)
+}
+
+// SendSignatureRequestEmail tells a contributor that a document entered a
+// review stage where their signature is expected. The stage deadline, if
+// any, is attached as a calendar invite built from ics.
+func (e *EmailService) SendSignatureRequestEmail(userEmail, userName, documentTitle, documentRef, documentID string, dueDate *time.Time, ics []byte, lang string) error {
+	data := EmailData{
+		UserName:      userName,
+		UserEmail:     userEmail,
+		AppURL:        e.appURL,
+		DocumentTitle: documentTitle,
+		DocumentRef:   documentRef,
+		DocumentURL:   fmt.Sprintf("%s/documents/%s", e.appURL, documentID),
 	}
-	if err := textTemplate.Execute(&textBuffer, data); err != nil {
-		return fmt.Errorf("failed to execute text template: %w", err)
+	if dueDate != nil {
+		data.SignatureDueDate = dueDate.Format("02/01/2006")
 	}
-
-	// Build payload expected by PHP mailer API
-	payload := map[string]any{
-		"to": []map[string]string{{
-			"email": toEmail,
-			"name":  toName,
-		}},
-		"subject": emailTemplate.Subject,
-		"html":    htmlBuffer.String(),
-		"text":    textBuffer.String(),
+	if len(ics) > 0 {
+		data.Attachments = []models.EmailAttachment{{
+			Filename:    "invite.ics",
+			ContentType: `text/calendar; charset="UTF-8"; method=PUBLISH`,
+			Content:     ics,
+		}}
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal mailer payload: %w", err)
-	}
+	return e.sendSystemEmail("signature_request", lang, userEmail, userName, data)
+}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest("POST", e.mailerAPIURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create mailer request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	if e.mailerAPIKey != "" {
-		req.Header.Set("X-API-KEY", e.mailerAPIKey)
+// SendNotificationDigestEmail sends the summary of the notifications received
+// by a user since their previous digest
+func (e *EmailService) SendNotificationDigestEmail(userEmail, userName string, entries []EmailDigestEntry, lang string) error {
+	data := EmailData{
+		UserName:         userName,
+		UserEmail:        userEmail,
+		AppURL:           e.appURL,
+		DigestEntries:    entries,
+		DigestCount:      len(entries),
+		NotificationsURL: fmt.Sprintf("%s/notifications", e.appURL),
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("mailer API request failed: %w", err)
+	return e.sendSystemEmail("notification_digest", lang, userEmail, userName, data)
+}
+
+// sendSystemEmail sends the built-in email of a kind in the language of its
+// recipient, unless its event is turned off for the organization or for them
+func (e *EmailService) sendSystemEmail(kind, lang, toEmail, toName string, data EmailData) error {
+	if e.eventSettings != nil && !e.eventSettings.allowsEmail(context.Background(), kind, toEmail) {
+		fmt.Printf("📭 Skipping %s email to %s, turned off in the email settings\n", kind, toEmail)
+		return nil
 	}
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("mailer API error (status %d): %s", resp.StatusCode, string(body))
+	emailTemplate, ok := e.builtinTemplate(kind, lang)
+	if !ok {
+		return fmt.Errorf("unknown email kind: %s", kind)
 	}
-
-	return nil
+	return e.sendEmail(kind, lang, toEmail, toName, emailTemplate, data)
 }
 
-// sendEmailViaSMTP sends email using SMTP with retry logic
-func (e *EmailService) sendEmailViaSMTP(toEmail, toName string, emailTemplate EmailTemplate, data EmailData) error {
-	fmt.Printf("🔧 [SMTP] Configuration - Host: %s, Port: %d, Username: %s\n",
-		e.smtpHost, e.smtpPort, e.smtpUsername)
-
-	// Retry logic for SMTP connection
-	maxRetries := 3
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		fmt.Printf("🔄 [SMTP] Attempt %d/%d to send email to %s\n", attempt, maxRetries, toEmail)
-		err := e.attemptSendEmail(toEmail, toName, emailTemplate, data)
-		if err == nil {
-			fmt.Printf("✅ [SMTP] Email sent successfully to %s on attempt %d\n", toEmail, attempt)
-			return nil // Success
+// sendEmail renders an email for a recipient and queues it in the outbox,
+// which retries failed deliveries. Without an outbox it is sent right away.
+// Duplicates of a recent email are dropped and ErrEmailRateLimited is
+// returned once the recipient got too many emails.
+func (e *EmailService) sendEmail(kind, lang, toEmail, toName string, emailTemplate EmailTemplate, data EmailData) (err error) {
+	if e.throttle != nil {
+		admitted, release, err := e.throttle.admit(context.Background(), kind, toEmail, data)
+		if err != nil {
+			fmt.Printf("📭 Not sending %s email to %s: %v\n", kind, toEmail, err)
+			return err
 		}
-
-		if attempt < maxRetries {
-			fmt.Printf("⚠️ [SMTP] Attempt %d failed for %s: %v, retrying in 5s...\n", attempt, toEmail, err)
-			time.Sleep(5 * time.Second)
-		} else {
-			fmt.Printf("❌ [SMTP] All %d attempts failed for %s\n", maxRetries, toEmail)
-			return fmt.Errorf("failed to send email via SMTP after %d attempts: %w", maxRetries, err)
+		if !admitted {
+			fmt.Printf("📭 Skipping %s email to %s, already sent recently\n", kind, toEmail)
+			return nil
 		}
+		defer func() {
+			if err != nil {
+				release()
+			}
+		}()
 	}
 
-	return nil // Should never reach here
+	email, err := e.render(kind, lang, toEmail, toName, emailTemplate, data)
+	if err != nil {
+		return err
+	}
+	if e.outbox != nil {
+		return e.outbox.enqueue(email)
+	}
+	return e.deliver(email)
 }
 
-// sendEmailViaBrevo sends email using Brevo API
-func (e *EmailService) sendEmailViaBrevo(toEmail, toName string, emailTemplate EmailTemplate, data EmailData) error {
-	if e.brevoAPIKey == "" {
-		return fmt.Errorf("Brevo API key not configured")
+// render builds an email for a recipient with the organization branding,
+// using the template edited by the admins for its kind and language when
+// there is one
+func (e *EmailService) render(kind, lang, toEmail, toName string, emailTemplate EmailTemplate, data EmailData) (*models.OutboxEmail, error) {
+	lang = i18n.Normalize(lang)
+	custom := false
+	if e.customTemplates != nil {
+		if tmpl := e.customTemplates.resolve(kind, lang); tmpl != nil {
+			emailTemplate = EmailTemplate{Subject: tmpl.Subject, HTMLBody: tmpl.HTMLBody, TextBody: tmpl.TextBody}
+			custom = true
+		}
 	}
 
-	// Parse and execute template
-	htmlTemplate, err := template.New("html").Parse(emailTemplate.HTMLBody)
-	if err != nil {
-		return fmt.Errorf("failed to parse HTML template: %w", err)
+	emailTemplate, data = e.applyBranding(emailTemplate, data)
+	if custom {
+		subject, err := renderSubject(emailTemplate.Subject, data)
+		if err != nil {
+			return nil, err
+		}
+		emailTemplate.Subject = subject
 	}
-
-	textTemplate, err := template.New("text").Parse(emailTemplate.TextBody)
+	htmlBody, textBody, err := e.renderBodies(emailTemplate, data)
 	if err != nil {
-		return fmt.Errorf("failed to parse text template: %w", err)
+		return nil, err
 	}
+	return &models.OutboxEmail{
+		Kind:        kind,
+		Language:    lang,
+		ToEmail:     toEmail,
+		ToName:      toName,
+		Subject:     emailTemplate.Subject,
+		HTMLBody:    htmlBody,
+		TextBody:    textBody,
+		Attachments: data.Attachments,
+	}, nil
+}
 
-	var htmlBuffer, textBuffer bytes.Buffer
-
-	if err := htmlTemplate.Execute(&htmlBuffer, data); err != nil {
-		return fmt.Errorf("failed to execute HTML template: %w", err)
+// deliver sends a rendered email through the configured providers in
+// order, falling back to the next one when a provider fails, and records the
+// provider that delivered it
+func (e *EmailService) deliver(email *models.OutboxEmail) error {
+	if len(e.providers) == 0 {
+		return fmt.Errorf("no email method available")
+	}
+
+	var errs []error
+	for _, provider := range e.providers {
+		fmt.Printf("📧 Using %s to send email to %s...\n", provider.Name(), email.ToEmail)
+		if err := provider.Send(email); err != nil {
+			fmt.Printf("❌ %s failed: %v\n", provider.Name(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+		email.Provider = provider.Name()
+		fmt.Printf("✅ Email successfully sent via %s to %s\n", provider.Name(), email.ToEmail)
+		return nil
 	}
+	return fmt.Errorf("all email providers failed: %w", errors.Join(errs...))
+}
 
-	if err := textTemplate.Execute(&textBuffer, data); err != nil {
-		return fmt.Errorf("failed to execute text template: %w", err)
+// applyBranding fills the organization branding into the email data and subject
+func (e *EmailService) applyBranding(emailTemplate EmailTemplate, data EmailData) (EmailTemplate, EmailData) {
+	branding := models.DefaultBranding()
+	if e.brandingService != nil {
+		branding = e.brandingService.Get(context.Background())
 	}
 
-	// Prepare Brevo email request
-	brevoRequest := BrevoEmailRequest{
-		Sender: BrevoSender{
-			Name:  e.fromName,
-			Email: e.fromEmail,
-		},
-		To: []BrevoContact{
-			{
-				Name:  toName,
-				Email: toEmail,
-			},
-		},
-		Subject:     emailTemplate.Subject,
-		HTMLContent: htmlBuffer.String(),
-		TextContent: textBuffer.String(),
-	}
+	data.AppName = branding.AppName
+	data.CompanyName = branding.CompanyName
+	data.SupportEmail = branding.SupportEmail
+	data.LogoURL = branding.LogoURL
+	data.PrimaryColor = branding.PrimaryColor
+	data.SecondaryColor = branding.SecondaryColor
+	data.FooterText = branding.FooterText
 
-	// Marshal request to JSON
-	jsonData, err := json.Marshal(brevoRequest)
-	if err != nil {
-		return fmt.Errorf("failed to marshal Brevo request: %w", err)
-	}
+	emailTemplate.Subject = strings.ReplaceAll(emailTemplate.Subject, "{{.AppName}}", branding.AppName)
+	return emailTemplate, data
+}
 
-	// Log the request details (without API key)
-	fmt.Printf("📤 [BREVO] Sending request to: %s\n", e.brevoAPIURL)
-	fmt.Printf("📤 [BREVO] Request payload: %s\n", string(jsonData))
+// Provider returns the name of the primary email provider, the one tried first
+func (e *EmailService) Provider() string {
+	if len(e.providers) == 0 {
+		return "none"
+	}
+	return e.providers[0].Name()
+}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+// Health checks that at least one of the configured email providers is reachable
+func (e *EmailService) Health(ctx context.Context) error {
+	if len(e.providers) == 0 {
+		return fmt.Errorf("no email method available")
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequest("POST", e.brevoAPIURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	var errs []error
+	for _, provider := range e.providers {
+		conn, err := dialer.DialContext(ctx, "tcp", provider.Address())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("email provider %s unreachable: %w", provider.Name(), err))
+			continue
+		}
+		return conn.Close()
 	}
+	return errors.Join(errs...)
+}
 
-	// Set headers according to Brevo API documentation
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("api-key", e.brevoAPIKey)
-	req.Header.Set("Accept", "application/json")
+// ProviderReachability dials every configured email provider at once and
+// tells which ones are reachable, keyed by provider name
+func (e *EmailService) ProviderReachability(ctx context.Context) map[string]bool {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	reachable := make(map[string]bool, len(e.providers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, provider := range e.providers {
+		wg.Add(1)
+		go func(provider EmailProvider) {
+			defer wg.Done()
+			conn, err := dialer.DialContext(ctx, "tcp", provider.Address())
+			if err == nil {
+				conn.Close()
+			}
+			mu.Lock()
+			reachable[provider.Name()] = err == nil
+			mu.Unlock()
+		}(provider)
+	}
+	wg.Wait()
+	return reachable
+}
 
-	// Send request
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send HTTP request to Brevo: %w", err)
+// SendTestEmail sends a test email right away through a provider, the
+// primary one when providerName is empty, bypassing the outbox and the
+// failover. The report tells each step of the delivery, such as the SMTP
+// handshake or the response of the Brevo API, even when it failed.
+func (e *EmailService) SendTestEmail(toEmail, toName, subject, body, lang, providerName string) (*models.EmailTestReport, error) {
+	if len(e.providers) == 0 {
+		return nil, errors.New("no email provider configured")
+	}
+	provider := e.providers[0]
+	if providerName != "" {
+		provider = nil
+		for _, p := range e.providers {
+			if p.Name() == providerName {
+				provider = p
+				break
+			}
+		}
+		if provider == nil {
+			return nil, fmt.Errorf("email provider %s is not configured", providerName)
+		}
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	email, err := e.render("test", lang, toEmail, toName, e.customEmailTemplate(subject, body, lang), EmailData{
+		UserName:  toName,
+		UserEmail: toEmail,
+		AppURL:    e.appURL,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to read Brevo response: %w", err)
+		return nil, err
 	}
 
-	// Check response status
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Brevo API error (status %d): %s", resp.StatusCode, string(body))
+	report := &models.EmailTestReport{
+		Provider: provider.Name(),
+		Address:  provider.Address(),
+		ToEmail:  toEmail,
+	}
+	for _, p := range e.providers {
+		report.Providers = append(report.Providers, p.Name())
 	}
 
-	// Parse response
-	var brevoResponse BrevoResponse
-	if err := json.Unmarshal(body, &brevoResponse); err != nil {
-		fmt.Printf("⚠️ Warning: Failed to parse Brevo response: %v\n", err)
-		fmt.Printf("📄 Raw Brevo response: %s\n", string(body))
+	trace := &emailTrace{}
+	start := time.Now()
+	if traced, ok := provider.(tracedEmailProvider); ok {
+		err = traced.sendTraced(email, trace)
 	} else {
-		fmt.Printf("✅ [BREVO] Email sent successfully (MessageID: %s) to %s\n", brevoResponse.MessageID, toEmail)
+		err = trace.record("send", start, "", provider.Send(email))
 	}
-
-	fmt.Printf("📊 [BREVO] Response Status: %d, Response Body: %s\n", resp.StatusCode, string(body))
-	return nil
+	report.DurationMs = time.Since(start).Milliseconds()
+	report.Steps = trace.steps
+	report.Success = err == nil
+	report.MessageID = email.MessageID
+	if err != nil {
+		report.Error = err.Error()
+	}
+	return report, nil
 }
 
-func (e *EmailService) attemptSendEmail(toEmail, toName string, emailTemplate EmailTemplate, data EmailData) error {
-	// Parse and execute template
-	htmlTemplate, err := template.New("html").Parse(emailTemplate.HTMLBody)
+// renderBodies executes the HTML and text bodies of a template, which are
+// parsed once and then served from the template cache
+func (e *EmailService) renderBodies(emailTemplate EmailTemplate, data EmailData) (string, string, error) {
+	htmlTemplate, err := e.templates.Get(templateKey("html", emailTemplate.HTMLBody), func() (*template.Template, error) {
+		return template.New("html").Parse(emailTemplate.HTMLBody)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to parse HTML template: %w", err)
+		return "", "", fmt.Errorf("failed to parse HTML template: %w", err)
 	}
 
-	textTemplate, err := template.New("text").Parse(emailTemplate.TextBody)
+	textTemplate, err := e.templates.Get(templateKey("text", emailTemplate.TextBody), func() (*template.Template, error) {
+		return template.New("text").Parse(emailTemplate.TextBody)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to parse text template: %w", err)
+		return "", "", fmt.Errorf("failed to parse text template: %w", err)
 	}
 
 	var htmlBuffer, textBuffer bytes.Buffer
-
 	if err := htmlTemplate.Execute(&htmlBuffer, data); err != nil {
-		return fmt.Errorf("failed to execute HTML template: %w", err)
+		return "", "", fmt.Errorf("failed to execute HTML template: %w", err)
 	}
-
 	if err := textTemplate.Execute(&textBuffer, data); err != nil {
-		return fmt.Errorf("failed to execute text template: %w", err)
-	}
-
-	// Prepare email message
-	message := e.buildMimeMessage(toEmail, toName, emailTemplate.Subject, htmlBuffer.String(), textBuffer.String())
-
-	// Send email
-	auth := smtp.PlainAuth("", e.smtpUsername, e.smtpPassword, e.smtpHost)
-
-	// Configure TLS
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: false,
-		ServerName:         e.smtpHost,
+		return "", "", fmt.Errorf("failed to execute text template: %w", err)
 	}
 
-	address := fmt.Sprintf("%s:%d", e.smtpHost, e.smtpPort)
-
-	// Create dialer with timeout
-	dialer := &net.Dialer{
-		Timeout: 30 * time.Second,
-	}
+	return htmlBuffer.String(), textBuffer.String(), nil
+}
 
-	// Connect to server with timeout
-	conn, err := tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+// renderSubject executes the subject of an edited template, as plain text
+// since subjects are not HTML
+func renderSubject(subject string, data EmailData) (string, error) {
+	subjectTemplate, err := texttemplate.New("subject").Parse(subject)
 	if err != nil {
-		return fmt.Errorf("failed to connect to SMTP server %s: %w", address, err)
+		return "", fmt.Errorf("failed to parse subject template: %w", err)
 	}
-
-	client, err := smtp.NewClient(conn, e.smtpHost)
-	if err != nil {
-		return fmt.Errorf("failed to create SMTP client: %w", err)
+	var buffer bytes.Buffer
+	if err := subjectTemplate.Execute(&buffer, data); err != nil {
+		return "", fmt.Errorf("failed to execute subject template: %w", err)
 	}
-	defer client.Quit()
+	return strings.TrimSpace(buffer.String()), nil
+}
 
-	// Authenticate
-	if err := client.Auth(auth); err != nil {
-		return fmt.Errorf("failed to authenticate with SMTP server: %w", err)
+// builtinTemplate returns the built-in template of an editable email kind in
+// a language, the default language being used when it is not supported
+func (e *EmailService) builtinTemplate(kind, lang string) (EmailTemplate, bool) {
+	getters := map[string]map[string]func() EmailTemplate{
+		"en": {
+			"welcome":              e.getWelcomeTemplate,
+			"verification":         e.getVerificationTemplate,
+			"otp":                  e.getOTPTemplate,
+			"registration_otp":     e.getRegistrationOTPTemplate,
+			"registration_pending": e.getRegistrationPendingTemplate,
+			"account_approved":     e.getAccountApprovedTemplate,
+			"account_rejected":     e.getAccountRejectedTemplate,
+			"invitation":           e.getInvitationTemplate,
+			"review_due":           e.getReviewDueTemplate,
+			"notification_digest":  e.getNotificationDigestTemplate,
+			"signature_reminder":   e.getSignatureReminderTemplate,
+			"signature_request":    e.getSignatureRequestTemplate,
+		},
+		"fr": {
+			"welcome":              e.getWelcomeTemplateFR,
+			"verification":         e.getVerificationTemplateFR,
+			"otp":                  e.getOTPTemplateFR,
+			"registration_otp":     e.getRegistrationOTPTemplateFR,
+			"registration_pending": e.getRegistrationPendingTemplateFR,
+			"account_approved":     e.getAccountApprovedTemplateFR,
+			"account_rejected":     e.getAccountRejectedTemplateFR,
+			"invitation":           e.getInvitationTemplateFR,
+			"review_due":           e.getReviewDueTemplateFR,
+			"notification_digest":  e.getNotificationDigestTemplateFR,
+			"signature_reminder":   e.getSignatureReminderTemplateFR,
+			"signature_request":    e.getSignatureRequestTemplateFR,
+		},
 	}
-
-	// Set sender and recipient
-	if err := client.Mail(e.fromEmail); err != nil {
-		return fmt.Errorf("failed to set sender: %w", err)
-	}
-
-	if err := client.Rcpt(toEmail); err != nil {
-		return fmt.Errorf("failed to set recipient: %w", err)
+	getter, ok := getters[i18n.Normalize(lang)][kind]
+	if !ok {
+		return EmailTemplate{}, false
 	}
+	return getter(), true
+}
 
-	// Send message
-	writer, err := client.Data()
-	if err != nil {
-		return fmt.Errorf("failed to get data writer: %w", err)
+// customEmailTemplate wraps the body of a custom email in the layout of a language
+func (e *EmailService) customEmailTemplate(subject, body, lang string) EmailTemplate {
+	if i18n.Normalize(lang) == "fr" {
+		return e.getCustomEmailTemplateFR(subject, body)
 	}
+	return e.getCustomEmailTemplate(subject, body)
+}
 
-	_, err = writer.Write([]byte(message))
+// renderSample renders a template with sample data in a language and the
+// organization branding, to preview it or check it before it is saved
+func (e *EmailService) renderSample(emailTemplate EmailTemplate, lang string) (*models.EmailTemplatePreview, error) {
+	emailTemplate, data := e.applyBranding(emailTemplate, e.sampleEmailData(lang))
+	subject, err := renderSubject(emailTemplate.Subject, data)
 	if err != nil {
-		return fmt.Errorf("failed to write message: %w", err)
+		return nil, err
 	}
-
-	err = writer.Close()
+	htmlBody, textBody, err := e.renderBodies(emailTemplate, data)
 	if err != nil {
-		return fmt.Errorf("failed to close writer: %w", err)
+		return nil, err
 	}
-
-	return nil
+	return &models.EmailTemplatePreview{Subject: subject, HTMLBody: htmlBody, TextBody: textBody}, nil
 }
 
-func (e *EmailService) buildMimeMessage(toEmail, toName, subject, htmlBody, textBody string) string {
-	var message strings.Builder
-
-	// Headers
-	message.WriteString(fmt.Sprintf("From: %s <%s>\r\n", e.fromName, e.fromEmail))
-	message.WriteString(fmt.Sprintf("To: %s <%s>\r\n", toName, toEmail))
-	message.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
-	message.WriteString("MIME-Version: 1.0\r\n")
-	message.WriteString("Content-Type: multipart/alternative; boundary=\"boundary123\"\r\n")
-	message.WriteString("\r\n")
-
-	// Text part
-	message.WriteString("--boundary123\r\n")
-	message.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
-	message.WriteString("Content-Transfer-Encoding: 7bit\r\n")
-	message.WriteString("\r\n")
-	message.WriteString(textBody)
-	message.WriteString("\r\n")
-
-	// HTML part
-	message.WriteString("--boundary123\r\n")
-	message.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
-	message.WriteString("Content-Transfer-Encoding: 7bit\r\n")
-	message.WriteString("\r\n")
-	message.WriteString(htmlBody)
-	message.WriteString("\r\n")
-
-	// End boundary
-	message.WriteString("--boundary123--\r\n")
-
-	return message.String()
+// sampleEmailData returns the data used to preview the email templates
+func (e *EmailService) sampleEmailData(lang string) EmailData {
+	data := EmailData{
+		UserName:         "Jane Doe",
+		UserEmail:        "jane.doe@example.com",
+		AppURL:           e.appURL,
+		VerificationURL:  fmt.Sprintf("%s/verify-email?token=sample-token", e.appURL),
+		ResetURL:         fmt.Sprintf("%s/reset-password?token=sample-token", e.appURL),
+		Token:            "sample-token",
+		OTP:              "123456",
+		OTPExpiry:        "5 minutes",
+		RejectionReason:  "The information provided could not be verified.",
+		InviterName:      "John Smith",
+		DocumentTitle:    "Purchasing procedure",
+		DocumentRef:      "PRO-ACH-001",
+		InvitationURL:    fmt.Sprintf("%s/invitations/accept?token=sample-token", e.appURL),
+		RoleName:         "Contributor",
+		TeamName:         "Authors",
+		DocumentURL:      fmt.Sprintf("%s/documents/sample", e.appURL),
+		ReviewDueDate:    time.Now().AddDate(0, 0, 14).Format("02/01/2006"),
+		SignatureDueDate: time.Now().AddDate(0, 0, 3).Format("02/01/2006"),
+		DigestEntries: []EmailDigestEntry{
+			{Title: "Signature requested", Body: "Purchasing procedure (PRO-ACH-001) is waiting for your signature.", URL: fmt.Sprintf("%s/documents/sample", e.appURL), Time: time.Now().Add(-2 * time.Hour).Format("02/01/2006 15:04")},
+			{Title: "New comment", Body: "John Smith commented on Purchasing procedure.", URL: fmt.Sprintf("%s/documents/sample", e.appURL), Time: time.Now().Add(-time.Hour).Format("02/01/2006 15:04")},
+		},
+		NotificationsURL: fmt.Sprintf("%s/notifications", e.appURL),
+	}
+	if i18n.Normalize(lang) == "fr" {
+		data.RejectionReason = "Les informations fournies n'ont pas pu être vérifiées."
+		data.DocumentTitle = "Procédure d'achat"
+		data.RoleName = "Contributeur"
+		data.TeamName = "Auteurs"
+		data.DigestEntries[0].Title = "Signature demandée"
+		data.DigestEntries[0].Body = "Procédure d'achat (PRO-ACH-001) attend votre signature."
+		data.DigestEntries[1].Title = "Nouveau commentaire"
+		data.DigestEntries[1].Body = "John Smith a commenté Procédure d'achat."
+	}
+	data.DigestCount = len(data.DigestEntries)
+	return data
 }
 
 func (e *EmailService) getRegistrationPendingTemplate() EmailTemplate {
@@ -639,6 +693,7 @@ func (e *EmailService) getRegistrationPendingTemplate() EmailTemplate {
     <title>Registration Received - {{.AppName}}</title>
 </head>
 <body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
     <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
         <h1 style="color: #f39c12; text-align: center;">Registration Received</h1>
         
@@ -673,6 +728,7 @@ func (e *EmailService) getRegistrationPendingTemplate() EmailTemplate {
             This email was sent to {{.UserEmail}}. If you didn't register for {{.AppName}}, please ignore this email.
         </p>
     </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
 </body>
 </html>`,
 		TextBody: `Registration Received - {{.AppName}}
@@ -705,7 +761,7 @@ This email was sent to {{.UserEmail}}. If you didn't register for {{.AppName}},
 
 func (e *EmailService) getAccountApprovedTemplate() EmailTemplate {
 	return EmailTemplate{
-		Subject: "Account Approved - Welcome to Process Manager!",
+		Subject: "Account Approved - Welcome to {{.AppName}}!",
 		HTMLBody: `
 <!DOCTYPE html>
 <html>
@@ -714,6 +770,7 @@ func (e *EmailService) getAccountApprovedTemplate() EmailTemplate {
     <title>Account Approved - {{.AppName}}</title>
 </head>
 <body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
     <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
         <h1 style="color: #27ae60; text-align: center;">🎉 Account Approved!</h1>
         
@@ -754,6 +811,7 @@ func (e *EmailService) getAccountApprovedTemplate() EmailTemplate {
             This email was sent to {{.UserEmail}}. For support, contact us at <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
         </p>
     </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
 </body>
 </html>`,
 		TextBody: `Account Approved - {{.AppName}}
@@ -790,7 +848,7 @@ This email was sent to {{.UserEmail}}. For support, contact us at {{.SupportEmai
 
 func (e *EmailService) getAccountRejectedTemplate() EmailTemplate {
 	return EmailTemplate{
-		Subject: "Registration Update - Process Manager",
+		Subject: "Registration Update - {{.AppName}}",
 		HTMLBody: `
 <!DOCTYPE html>
 <html>
@@ -799,6 +857,7 @@ func (e *EmailService) getAccountRejectedTemplate() EmailTemplate {
     <title>Registration Update - {{.AppName}}</title>
 </head>
 <body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
     <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
         <h1 style="color: #e74c3c; text-align: center;">Registration Update</h1>
         
@@ -823,7 +882,7 @@ func (e *EmailService) getAccountRejectedTemplate() EmailTemplate {
         </ul>
         
         <div style="text-align: center; margin: 30px 0;">
-            <a href="mailto:{{.SupportEmail}}" style="background-color: #3498db; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Contact Support</a>
+            <a href="mailto:{{.SupportEmail}}" style="background-color: {{.PrimaryColor}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Contact Support</a>
         </div>
         
         <p>We appreciate your understanding and interest in {{.AppName}}.</p>
@@ -835,6 +894,7 @@ func (e *EmailService) getAccountRejectedTemplate() EmailTemplate {
             This email was sent to {{.UserEmail}}. For support, contact us at <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
         </p>
     </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
 </body>
 </html>`,
 		TextBody: `Registration Update - {{.AppName}}
@@ -866,7 +926,7 @@ This email was sent to {{.UserEmail}}. For support, contact us at {{.SupportEmai
 
 func (e *EmailService) getWelcomeTemplate() EmailTemplate {
 	return EmailTemplate{
-		Subject: "Welcome to Process Manager!",
+		Subject: "Welcome to {{.AppName}}!",
 		HTMLBody: `
 <!DOCTYPE html>
 <html>
@@ -875,14 +935,15 @@ func (e *EmailService) getWelcomeTemplate() EmailTemplate {
     <title>Welcome to {{.AppName}}</title>
 </head>
 <body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
     <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
-        <h1 style="color: #2c3e50; text-align: center;">Welcome to {{.AppName}}!</h1>
+        <h1 style="color: {{.SecondaryColor}}; text-align: center;">Welcome to {{.AppName}}!</h1>
         
         <p>Dear {{.UserName}},</p>
         
-        <p>Welcome to Process Manager! We're excited to have you on board. Our platform helps telecommunications companies digitize and manage their procedural documentation efficiently.</p>
+        <p>Welcome to {{.AppName}}! We're excited to have you on board. Our platform helps telecommunications companies digitize and manage their procedural documentation efficiently.</p>
         
-        <p>With Process Manager, you can:</p>
+        <p>With {{.AppName}}, you can:</p>
         <ul>
             <li>Create and manage digital processes collaboratively</li>
             <li>Handle multi-step form workflows</li>
@@ -891,7 +952,7 @@ func (e *EmailService) getWelcomeTemplate() EmailTemplate {
         </ul>
         
         <div style="text-align: center; margin: 30px 0;">
-            <a href="{{.AppURL}}" style="background-color: #3498db; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Get Started</a>
+            <a href="{{.AppURL}}" style="background-color: {{.PrimaryColor}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Get Started</a>
         </div>
         
         <p>If you have any questions or need assistance, feel free to reach out to our support team at <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
@@ -903,15 +964,16 @@ func (e *EmailService) getWelcomeTemplate() EmailTemplate {
             This email was sent to {{.UserEmail}}. If you didn't create an account with us, please ignore this email.
         </p>
     </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
 </body>
 </html>`,
 		TextBody: `Welcome to {{.AppName}}!
 
 Dear {{.UserName}},
 
-Welcome to Process Manager! We're excited to have you on board. Our platform helps telecommunications companies digitize and manage their procedural documentation efficiently.
+Welcome to {{.AppName}}! We're excited to have you on board. Our platform helps telecommunications companies digitize and manage their procedural documentation efficiently.
 
-With Process Manager, you can:
+With {{.AppName}}, you can:
 • Create and manage digital processes collaboratively
 • Handle multi-step form workflows
 • Generate digital signatures and PDF exports
@@ -940,8 +1002,9 @@ func (e *EmailService) getVerificationTemplate() EmailTemplate {
     <title>Verify Your Email - {{.AppName}}</title>
 </head>
 <body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
     <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
-        <h1 style="color: #2c3e50; text-align: center;">Verify Your Email Address</h1>
+        <h1 style="color: {{.SecondaryColor}}; text-align: center;">Verify Your Email Address</h1>
         
         <p>Dear {{.UserName}},</p>
         
@@ -965,6 +1028,7 @@ func (e *EmailService) getVerificationTemplate() EmailTemplate {
             This email was sent to {{.UserEmail}}. For support, contact us at <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
         </p>
     </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
 </body>
 </html>`,
 		TextBody: `Verify Your Email Address
@@ -989,7 +1053,7 @@ This email was sent to {{.UserEmail}}. For support, contact us at {{.SupportEmai
 
 func (e *EmailService) getOTPTemplate() EmailTemplate {
 	return EmailTemplate{
-		Subject: "Your Login Code for Process Manager",
+		Subject: "Your Login Code for {{.AppName}}",
 		HTMLBody: `
 <!DOCTYPE html>
 <html>
@@ -998,15 +1062,16 @@ func (e *EmailService) getOTPTemplate() EmailTemplate {
     <title>Your Login Code - {{.AppName}}</title>
 </head>
 <body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
     <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
-        <h1 style="color: #2c3e50; text-align: center;">Your Login Code</h1>
+        <h1 style="color: {{.SecondaryColor}}; text-align: center;">Your Login Code</h1>
         
         <p>Dear {{.UserName}},</p>
         
         <p>You're trying to sign in to your {{.AppName}} account. Please use the verification code below:</p>
         
-        <div style="text-align: center; margin: 30px 0; background-color: #ffffff; padding: 20px; border-radius: 8px; border: 2px solid #3498db;">
-            <h2 style="color: #2c3e50; font-size: 32px; letter-spacing: 8px; margin: 0; font-family: 'Courier New', monospace;">{{.OTP}}</h2>
+        <div style="text-align: center; margin: 30px 0; background-color: #ffffff; padding: 20px; border-radius: 8px; border: 2px solid {{.PrimaryColor}};">
+            <h2 style="color: {{.SecondaryColor}}; font-size: 32px; letter-spacing: 8px; margin: 0; font-family: 'Courier New', monospace;">{{.OTP}}</h2>
         </div>
         
         <div style="background-color: #fff3cd; border: 1px solid #ffeaa7; color: #856404; padding: 12px; border-radius: 4px; margin: 20px 0;">
@@ -1024,7 +1089,7 @@ func (e *EmailService) getOTPTemplate() EmailTemplate {
         <p>If you're having trouble signing in, you can request a new code or contact our support team.</p>
         
         <div style="text-align: center; margin: 30px 0;">
-            <a href="{{.AppURL}}" style="background-color: #3498db; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Go to {{.AppName}}</a>
+            <a href="{{.AppURL}}" style="background-color: {{.PrimaryColor}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Go to {{.AppName}}</a>
         </div>
         
         <p>Best regards,<br>{{.CompanyName}}</p>
@@ -1034,6 +1099,7 @@ func (e *EmailService) getOTPTemplate() EmailTemplate {
             This email was sent to {{.UserEmail}}. For support, contact us at <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
         </p>
     </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
 </body>
 </html>`,
 		TextBody: `Your Login Code for {{.AppName}}
@@ -1066,18 +1132,53 @@ This email was sent to {{.UserEmail}}. For support, contact us at {{.SupportEmai
 
 // getRegistrationOTPTemplate returns the registration OTP email template
 // SendCustomEmail sends a custom email to a user
-func (e *EmailService) SendCustomEmail(toEmail, toName, subject, body string) error {
+func (e *EmailService) SendCustomEmail(toEmail, toName, subject, body, lang string) error {
+	data := EmailData{
+		UserName:  toName,
+		UserEmail: toEmail,
+		AppURL:    e.appURL,
+	}
+
+	return e.sendEmail("custom", lang, toEmail, toName, e.customEmailTemplate(subject, body, lang), data)
+}
+
+// DeliverCustomEmail sends a custom email right away, bypassing the outbox,
+// for the senders reporting the outcome themselves: test emails, and
+// campaigns when there is no outbox
+func (e *EmailService) DeliverCustomEmail(toEmail, toName, subject, body, lang string) error {
+	data := EmailData{
+		UserName:  toName,
+		UserEmail: toEmail,
+		AppURL:    e.appURL,
+	}
+
+	email, err := e.render("custom", lang, toEmail, toName, e.customEmailTemplate(subject, body, lang), data)
+	if err != nil {
+		return err
+	}
+	return e.deliver(email)
+}
+
+// QueueCampaignEmail queues a custom email of a campaign in the outbox under
+// the ID of its recipient, so queuing it twice is rejected as a duplicate
+// and the outcome can be followed with OnDelivery
+func (e *EmailService) QueueCampaignEmail(recipientID, campaignID primitive.ObjectID, toEmail, toName, subject, body, lang string) error {
+	if e.outbox == nil {
+		return errors.New("email outbox is not configured")
+	}
 	data := EmailData{
-		UserName:     toName,
-		UserEmail:    toEmail,
-		AppName:      "Process Manager",
-		AppURL:       e.appURL,
-		SupportEmail: "support@process-manager.com",
-		CompanyName:  "Process Manager Team",
+		UserName:  toName,
+		UserEmail: toEmail,
+		AppURL:    e.appURL,
 	}
 
-	template := e.getCustomEmailTemplate(subject, body)
-	return e.sendEmail(toEmail, toName, template, data)
+	email, err := e.render("campaign", lang, toEmail, toName, e.customEmailTemplate(subject, body, lang), data)
+	if err != nil {
+		return err
+	}
+	email.ID = recipientID
+	email.CampaignID = &campaignID
+	return e.outbox.enqueue(email)
 }
 
 // getCustomEmailTemplate creates a template for custom emails
@@ -1092,15 +1193,16 @@ func (e *EmailService) getCustomEmailTemplate(subject, body string) EmailTemplat
     <title>%s</title>
 </head>
 <body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
     <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
-        <h1 style="color: #2c3e50; text-align: center;">{{.AppName}}</h1>
+        <h1 style="color: {{.SecondaryColor}}; text-align: center;">{{.AppName}}</h1>
 
         <p>Dear {{.UserName}},</p>
 
         %s
 
         <div style="text-align: center; margin: 30px 0;">
-            <a href="{{.AppURL}}" style="background-color: #3498db; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Go to {{.AppName}}</a>
+            <a href="{{.AppURL}}" style="background-color: {{.PrimaryColor}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Go to {{.AppName}}</a>
         </div>
 
         <p>Best regards,<br>{{.CompanyName}}</p>
@@ -1110,6 +1212,7 @@ func (e *EmailService) getCustomEmailTemplate(subject, body string) EmailTemplat
             This email was sent to {{.UserEmail}}. For support, contact us at <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
         </p>
     </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
 </body>
 </html>`, subject, body),
 		TextBody: fmt.Sprintf(`%s
@@ -1139,9 +1242,10 @@ func (e *EmailService) getRegistrationOTPTemplate() EmailTemplate {
     <title>Registration Verification Code</title>
 </head>
 <body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
     <div style="background-color: #f8f9fa; padding: 20px; border-radius: 10px;">
         <div style="text-align: center; margin-bottom: 30px;">
-            <h1 style="color: #2c3e50; margin: 0;">{{.AppName}}</h1>
+            <h1 style="color: {{.SecondaryColor}}; margin: 0;">{{.AppName}}</h1>
             <h2 style="color: #27ae60; margin: 10px 0;">Complete Your Registration</h2>
         </div>
 
@@ -1187,6 +1291,7 @@ func (e *EmailService) getRegistrationOTPTemplate() EmailTemplate {
             This email was sent to {{.UserEmail}}. For support, contact us at <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
         </p>
     </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
 </body>
 </html>`,
 		TextBody: `Complete Your Registration - Verification Code
@@ -1232,14 +1337,15 @@ func (e *EmailService) getInvitationTemplate() EmailTemplate {
     <title>Document Collaboration Invitation - {{.AppName}}</title>
 </head>
 <body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
     <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
-        <h1 style="color: #3498db; text-align: center;">📄 Document Collaboration Invitation</h1>
+        <h1 style="color: {{.PrimaryColor}}; text-align: center;">📄 Document Collaboration Invitation</h1>
 
         <p>Dear {{.UserName}},</p>
 
         <p><strong>{{.InviterName}}</strong> has invited you to collaborate on a document in {{.AppName}}.</p>
 
-        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid #3498db; margin: 20px 0;">
+        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid {{.PrimaryColor}}; margin: 20px 0;">
             <p style="margin: 5px 0;"><strong>Document:</strong> {{.DocumentTitle}}</p>
             <p style="margin: 5px 0;"><strong>Reference:</strong> {{.DocumentRef}}</p>
             <p style="margin: 5px 0;"><strong>Role:</strong> {{.TeamName}}</p>
@@ -1274,6 +1380,7 @@ func (e *EmailService) getInvitationTemplate() EmailTemplate {
             This email was sent to {{.UserEmail}}. If you didn't expect this invitation, please contact <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
         </p>
     </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
 </body>
 </html>`,
 		TextBody: `Document Collaboration Invitation - {{.AppName}}
@@ -1307,3 +1414,273 @@ Best regards,
 This email was sent to {{.UserEmail}}. If you didn't expect this invitation, please contact {{.SupportEmail}}.`,
 	}
 }
+
+func (e *EmailService) getReviewDueTemplate() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Periodic review due for a published document",
+		HTMLBody: `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Periodic Review Due - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #e67e22; text-align: center;">🔁 Periodic Review Due</h1>
+
+        <p>Dear {{.UserName}},</p>
+
+        <p>A procedure you authored has reached its periodic review date and must be re-validated.</p>
+
+        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid #e67e22; margin: 20px 0;">
+            <p style="margin: 5px 0;"><strong>Document:</strong> {{.DocumentTitle}}</p>
+            <p style="margin: 5px 0;"><strong>Reference:</strong> {{.DocumentRef}}</p>
+            <p style="margin: 5px 0;"><strong>Review date:</strong> {{.ReviewDueDate}}</p>
+        </div>
+
+        <p>Please check that the procedure still reflects current practice, then confirm the review or start a revision.</p>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.DocumentURL}}" style="background-color: #e67e22; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Open Document</a>
+        </div>
+
+        <p>If the button above doesn't work, you can copy and paste this link into your browser:</p>
+        <p style="word-break: break-all; background-color: #f8f9fa; padding: 10px; border-left: 4px solid #e67e22;">{{.DocumentURL}}</p>
+
+        <p>If you have any questions, please contact our support team at <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
+
+        <p>Best regards,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            This email was sent to {{.UserEmail}} because you are an author of this document.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Periodic Review Due - {{.AppName}}
+
+Dear {{.UserName}},
+
+A procedure you authored has reached its periodic review date and must be re-validated.
+
+Document Details:
+• Document: {{.DocumentTitle}}
+• Reference: {{.DocumentRef}}
+• Review date: {{.ReviewDueDate}}
+
+Please check that the procedure still reflects current practice, then confirm the review or start a revision.
+
+Open Document: {{.DocumentURL}}
+
+If you have any questions, please contact our support team at {{.SupportEmail}}.
+
+Best regards,
+{{.CompanyName}}
+
+---
+This email was sent to {{.UserEmail}} because you are an author of this document.`,
+	}
+}
+
+func (e *EmailService) getNotificationDigestTemplate() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Your notification summary",
+		HTMLBody: `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Notification Summary - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #3498db; text-align: center;">📬 Notification Summary</h1>
+
+        <p>Dear {{.UserName}},</p>
+
+        <p>You received {{.DigestCount}} notification(s) since your last summary.</p>
+
+        {{range .DigestEntries}}
+        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid #3498db; margin: 10px 0;">
+            <p style="margin: 0 0 5px 0;"><strong>{{.Title}}</strong> <span style="font-size: 12px; color: #666;">{{.Time}}</span></p>
+            <p style="margin: 0;">{{.Body}}</p>
+            {{if .URL}}<p style="margin: 5px 0 0 0;"><a href="{{.URL}}" style="color: #3498db;">Open</a></p>{{end}}
+        </div>
+        {{end}}
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.NotificationsURL}}" style="background-color: #3498db; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">View All Notifications</a>
+        </div>
+
+        <p>Best regards,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            This email was sent to {{.UserEmail}} because you chose to receive your notifications as a summary. You can change this in your notification preferences.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Notification Summary - {{.AppName}}
+
+Dear {{.UserName}},
+
+You received {{.DigestCount}} notification(s) since your last summary.
+{{range .DigestEntries}}
+• {{.Title}} ({{.Time}})
+  {{.Body}}{{if .URL}}
+  {{.URL}}{{end}}
+{{end}}
+View all notifications: {{.NotificationsURL}}
+
+Best regards,
+{{.CompanyName}}
+
+---
+This email was sent to {{.UserEmail}} because you chose to receive your notifications as a summary. You can change this in your notification preferences.`,
+	}
+}
+
+func (e *EmailService) getSignatureReminderTemplate() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Reminder: a document is waiting for your signature",
+		HTMLBody: `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Signature Reminder - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #2980b9; text-align: center;">✍️ Signature Reminder</h1>
+
+        <p>Dear {{.UserName}},</p>
+
+        <p>A document is still waiting for your signature.</p>
+
+        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid #2980b9; margin: 20px 0;">
+            <p style="margin: 5px 0;"><strong>Document:</strong> {{.DocumentTitle}}</p>
+            <p style="margin: 5px 0;"><strong>Reference:</strong> {{.DocumentRef}}</p>
+            {{if .SignatureDueDate}}<p style="margin: 5px 0;"><strong>Sign before:</strong> {{.SignatureDueDate}}</p>{{end}}
+        </div>
+
+        <p>Please review the document, then sign it or reject it with your comments.</p>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.DocumentURL}}" style="background-color: #2980b9; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Open Document</a>
+        </div>
+
+        <p>If the button above doesn't work, you can copy and paste this link into your browser:</p>
+        <p style="word-break: break-all; background-color: #f8f9fa; padding: 10px; border-left: 4px solid #2980b9;">{{.DocumentURL}}</p>
+
+        <p>If you have any questions, please contact our support team at <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
+
+        <p>Best regards,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            This email was sent to {{.UserEmail}} because you are a contributor of this document.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Signature Reminder - {{.AppName}}
+
+Dear {{.UserName}},
+
+A document is still waiting for your signature.
+
+Document Details:
+• Document: {{.DocumentTitle}}
+• Reference: {{.DocumentRef}}
+{{if .SignatureDueDate}}• Sign before: {{.SignatureDueDate}}
+{{end}}
+Please review the document, then sign it or reject it with your comments.
+
+Open Document: {{.DocumentURL}}
+
+If you have any questions, please contact our support team at {{.SupportEmail}}.
+
+Best regards,
+{{.CompanyName}}
+
+---
+This email was sent to {{.UserEmail}} because you are a contributor of this document.`,
+	}
+}
+
+func (e *EmailService) getSignatureRequestTemplate() EmailTemplate {
+	return EmailTemplate{
+		Subject: "A document is waiting for your signature",
+		HTMLBody: `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Signature Request - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #2980b9; text-align: center;">✍️ Signature Request</h1>
+
+        <p>Dear {{.UserName}},</p>
+
+        <p>A document has entered a review stage and is waiting for your signature.</p>
+
+        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid #2980b9; margin: 20px 0;">
+            <p style="margin: 5px 0;"><strong>Document:</strong> {{.DocumentTitle}}</p>
+            <p style="margin: 5px 0;"><strong>Reference:</strong> {{.DocumentRef}}</p>
+            {{if .SignatureDueDate}}<p style="margin: 5px 0;"><strong>Sign before:</strong> {{.SignatureDueDate}}</p>{{end}}
+        </div>
+
+        <p>Please review the document, then sign it or reject it with your comments.{{if .SignatureDueDate}} The attached calendar invite adds the deadline to your calendar.{{end}}</p>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.DocumentURL}}" style="background-color: #2980b9; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Open Document</a>
+        </div>
+
+        <p>If the button above doesn't work, you can copy and paste this link into your browser:</p>
+        <p style="word-break: break-all; background-color: #f8f9fa; padding: 10px; border-left: 4px solid #2980b9;">{{.DocumentURL}}</p>
+
+        <p>If you have any questions, please contact our support team at <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
+
+        <p>Best regards,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            This email was sent to {{.UserEmail}} because you are a contributor of this document.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Signature Request - {{.AppName}}
+
+Dear {{.UserName}},
+
+A document has entered a review stage and is waiting for your signature.
+
+Document Details:
+• Document: {{.DocumentTitle}}
+• Reference: {{.DocumentRef}}
+{{if .SignatureDueDate}}• Sign before: {{.SignatureDueDate}}
+{{end}}
+Please review the document, then sign it or reject it with your comments.{{if .SignatureDueDate}} The attached calendar invite adds the deadline to your calendar.{{end}}
+
+Open Document: {{.DocumentURL}}
+
+If you have any questions, please contact our support team at {{.SupportEmail}}.
+
+Best regards,
+{{.CompanyName}}
+
+---
+This email was sent to {{.UserEmail}} because you are a contributor of this document.`,
+	}
+}
@@ -0,0 +1,615 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var campaignVariablePattern = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// EmailCampaignService manages bulk emails to user segments. Deliveries are
+// queued as recipient records and drained at a throttled rate into the email
+// outbox, which retries them and reports their outcome.
+type EmailCampaignService struct {
+	campaignCollection   *mongo.Collection
+	recipientCollection  *mongo.Collection
+	userCollection       *mongo.Collection
+	departmentCollection *mongo.Collection
+	emailService         *EmailService
+	interval             time.Duration // Delay between two deliveries
+}
+
+// NewEmailCampaignService creates a new email campaign service.
+// EMAIL_CAMPAIGN_RATE_PER_MINUTE caps the delivery rate (default 30).
+func NewEmailCampaignService(db *DatabaseService, emailService *EmailService) *EmailCampaignService {
+	rate := 30
+	if v, err := strconv.Atoi(os.Getenv("EMAIL_CAMPAIGN_RATE_PER_MINUTE")); err == nil && v > 0 {
+		rate = v
+	}
+
+	service := &EmailCampaignService{
+		campaignCollection:   db.Collection("email_campaigns"),
+		recipientCollection:  db.Collection("email_campaign_recipients"),
+		userCollection:       db.Collection("users"),
+		departmentCollection: db.Collection("departments"),
+		emailService:         emailService,
+		interval:             time.Minute / time.Duration(rate),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.campaignCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "scheduled_at", Value: 1}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create email campaign indexes: %v\n", err)
+	}
+	if _, err := service.recipientCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "campaign_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create email campaign recipient indexes: %v\n", err)
+	}
+	if emailService.outbox != nil {
+		emailService.outbox.OnDelivery(service.recordDelivery)
+	}
+
+	return service
+}
+
+// ParseSegment validates a segment request
+func ParseSegment(req models.UserSegmentRequest) (models.UserSegment, error) {
+	var segment models.UserSegment
+	for _, role := range req.Roles {
+		if !models.IsValidRole(models.UserRole(role)) {
+			return segment, fmt.Errorf("invalid segment role: %s", role)
+		}
+		segment.Roles = append(segment.Roles, models.UserRole(role))
+	}
+	for _, status := range req.Statuses {
+		if !models.IsValidStatus(models.UserStatus(status)) {
+			return segment, fmt.Errorf("invalid segment status: %s", status)
+		}
+		segment.Statuses = append(segment.Statuses, models.UserStatus(status))
+	}
+	for _, id := range req.DepartmentIDs {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return segment, fmt.Errorf("invalid segment department ID: %s", id)
+		}
+		segment.DepartmentIDs = append(segment.DepartmentIDs, objID)
+	}
+	return segment, nil
+}
+
+// validateTemplate rejects placeholders that cannot be filled
+func validateTemplate(texts ...string) error {
+	for _, text := range texts {
+		for _, match := range campaignVariablePattern.FindAllStringSubmatch(text, -1) {
+			if !slices.Contains(models.CampaignTemplateVariables, match[1]) {
+				return fmt.Errorf("invalid template variable: {{%s}}", match[1])
+			}
+		}
+	}
+	return nil
+}
+
+// segmentFilter builds the user query of a segment
+func segmentFilter(segment models.UserSegment) bson.M {
+	filter := bson.M{"status": models.StatusActive}
+	if len(segment.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": segment.Statuses}
+	}
+	if len(segment.Roles) > 0 {
+		filter["role"] = bson.M{"$in": segment.Roles}
+	}
+	if len(segment.DepartmentIDs) > 0 {
+		filter["department_id"] = bson.M{"$in": segment.DepartmentIDs}
+	}
+	return filter
+}
+
+// render fills the template variables for one recipient
+func (s *EmailCampaignService) render(text string, user *models.User, departmentName string, escape bool) string {
+	values := map[string]string{
+		"firstName":  user.FirstName,
+		"lastName":   user.LastName,
+		"fullName":   strings.TrimSpace(user.FirstName + " " + user.LastName),
+		"email":      user.Email,
+		"role":       string(user.Role),
+		"department": departmentName,
+	}
+	return campaignVariablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		value := values[campaignVariablePattern.FindStringSubmatch(match)[1]]
+		if escape {
+			return html.EscapeString(value)
+		}
+		return value
+	})
+}
+
+// renderBody fills a campaign body and wraps plain text the way single emails are
+func (s *EmailCampaignService) renderBody(body string, isHTML bool, user *models.User, departmentName string) string {
+	rendered := s.render(body, user, departmentName, true)
+	if !isHTML {
+		rendered = "<p>" + rendered + "</p>"
+	}
+	return rendered
+}
+
+// departmentName returns the name of the user's department, if any
+func (s *EmailCampaignService) departmentName(ctx context.Context, departmentID *primitive.ObjectID) string {
+	if departmentID == nil {
+		return ""
+	}
+	var department models.Department
+	if err := s.departmentCollection.FindOne(ctx, bson.M{"_id": departmentID}).Decode(&department); err != nil {
+		return ""
+	}
+	return department.Name
+}
+
+// Preview counts the recipients of a segment and renders the email for a sample one
+func (s *EmailCampaignService) Preview(ctx context.Context, req *models.PreviewEmailCampaignRequest) (*models.EmailCampaignPreview, error) {
+	segment, err := ParseSegment(req.Segment)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTemplate(req.Subject, req.Body); err != nil {
+		return nil, err
+	}
+
+	filter := segmentFilter(segment)
+	count, err := s.userCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recipients: %w", err)
+	}
+
+	preview := &models.EmailCampaignPreview{
+		Subject:        req.Subject,
+		Body:           req.Body,
+		RecipientCount: count,
+	}
+
+	sampleFilter := filter
+	if req.UserID != "" {
+		userID, err := primitive.ObjectIDFromHex(req.UserID)
+		if err != nil {
+			return nil, errors.New("invalid sample user ID")
+		}
+		sampleFilter = bson.M{"_id": userID}
+	}
+
+	var sample models.User
+	if err := s.userCollection.FindOne(ctx, sampleFilter).Decode(&sample); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return preview, nil
+		}
+		return nil, fmt.Errorf("failed to get sample recipient: %w", err)
+	}
+
+	department := s.departmentName(ctx, sample.DepartmentID)
+	preview.Subject = s.render(req.Subject, &sample, department, false)
+	preview.Body = s.renderBody(req.Body, req.IsHTML, &sample, department)
+	preview.SampleEmail = sample.Email
+	return preview, nil
+}
+
+// Create schedules a campaign. Recipients are resolved when it starts sending.
+func (s *EmailCampaignService) Create(ctx context.Context, req *models.CreateEmailCampaignRequest, createdBy primitive.ObjectID) (*models.EmailCampaign, error) {
+	segment, err := ParseSegment(req.Segment)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTemplate(req.Subject, req.Body); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	scheduledAt := now
+	if req.ScheduledAt != nil {
+		if req.ScheduledAt.Before(now.Add(-time.Minute)) {
+			return nil, errors.New("scheduled time must be in the future")
+		}
+		scheduledAt = *req.ScheduledAt
+	}
+
+	campaign := &models.EmailCampaign{
+		ID:          primitive.NewObjectID(),
+		Subject:     req.Subject,
+		Body:        req.Body,
+		IsHTML:      req.IsHTML,
+		Segment:     segment,
+		Status:      models.EmailCampaignStatusScheduled,
+		ScheduledAt: scheduledAt,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if _, err := s.campaignCollection.InsertOne(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// List returns campaigns, most recent first
+func (s *EmailCampaignService) List(ctx context.Context, page, limit int) ([]*models.EmailCampaign, int64, error) {
+	total, err := s.campaignCollection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count campaigns: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := s.campaignCollection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find campaigns: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	campaigns := make([]*models.EmailCampaign, 0)
+	if err = cursor.All(ctx, &campaigns); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode campaigns: %w", err)
+	}
+
+	return campaigns, total, nil
+}
+
+// GetByID retrieves a campaign
+func (s *EmailCampaignService) GetByID(ctx context.Context, id primitive.ObjectID) (*models.EmailCampaign, error) {
+	var campaign models.EmailCampaign
+	if err := s.campaignCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&campaign); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("campaign not found")
+		}
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	return &campaign, nil
+}
+
+// GetRecipients returns the delivery report of a campaign, optionally filtered by status
+func (s *EmailCampaignService) GetRecipients(ctx context.Context, campaignID primitive.ObjectID, status string, page, limit int) ([]*models.EmailCampaignRecipient, int64, models.EmailCampaignStatusCounts, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"campaign_id": campaignID}}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := s.recipientCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to count deliveries: %w", err)
+	}
+	var groups []struct {
+		Status models.EmailDeliveryStatus `bson:"_id"`
+		Count  int64                      `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to decode deliveries: %w", err)
+	}
+	counts := make(models.EmailCampaignStatusCounts, len(groups))
+	for _, g := range groups {
+		counts[g.Status] = g.Count
+	}
+
+	filter := bson.M{"campaign_id": campaignID}
+	if status != "" {
+		filter["status"] = status
+	}
+	total, err := s.recipientCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to count recipients: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err = s.recipientCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to find recipients: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	recipients := make([]*models.EmailCampaignRecipient, 0)
+	if err = cursor.All(ctx, &recipients); err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to decode recipients: %w", err)
+	}
+
+	return recipients, total, counts, nil
+}
+
+// Cancel stops a campaign; deliveries already sent are kept
+func (s *EmailCampaignService) Cancel(ctx context.Context, id primitive.ObjectID) (*models.EmailCampaign, error) {
+	now := time.Now()
+	var campaign models.EmailCampaign
+	err := s.campaignCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id, "status": bson.M{"$in": []models.EmailCampaignStatus{
+			models.EmailCampaignStatusScheduled,
+			models.EmailCampaignStatusSending,
+		}}},
+		bson.M{"$set": bson.M{
+			"status":       models.EmailCampaignStatusCancelled,
+			"completed_at": now,
+			"updated_at":   now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&campaign)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, getErr := s.GetByID(ctx, id); getErr != nil {
+				return nil, getErr
+			}
+			return nil, errors.New("campaign is already finished")
+		}
+		return nil, fmt.Errorf("failed to cancel campaign: %w", err)
+	}
+
+	if _, err := s.recipientCollection.UpdateMany(ctx, bson.M{
+		"campaign_id": id,
+		"status":      models.EmailDeliveryStatusPending,
+	}, bson.M{"$set": bson.M{
+		"status":     models.EmailDeliveryStatusCancelled,
+		"updated_at": now,
+	}}); err != nil {
+		return nil, fmt.Errorf("failed to cancel pending deliveries: %w", err)
+	}
+
+	return &campaign, nil
+}
+
+// Start runs the campaign dispatcher until the context is cancelled. Each
+// tick starts the campaigns that are due and delivers at most one email.
+func (s *EmailCampaignService) Start(ctx context.Context) {
+	go func() {
+		// Deliveries interrupted by a restart are sent again
+		if _, err := s.recipientCollection.UpdateMany(ctx, bson.M{"status": models.EmailDeliveryStatusSending},
+			bson.M{"$set": bson.M{"status": models.EmailDeliveryStatusPending}}); err != nil {
+			fmt.Printf("Warning: Failed to requeue interrupted campaign deliveries: %v\n", err)
+		}
+		if err := s.resumeInterruptedCampaigns(ctx); err != nil {
+			fmt.Printf("Warning: Failed to resume email campaigns: %v\n", err)
+		}
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				tickCtx, cancel := context.WithTimeout(ctx, time.Minute)
+				if err := s.startDueCampaigns(tickCtx); err != nil {
+					fmt.Printf("Warning: Failed to start email campaigns: %v\n", err)
+				}
+				if err := s.deliverNext(tickCtx); err != nil {
+					fmt.Printf("Warning: Failed to deliver campaign email: %v\n", err)
+				}
+				cancel()
+			}
+		}
+	}()
+	fmt.Printf("📧 Email campaign dispatcher started (one email every %s)\n", s.interval)
+}
+
+// startDueCampaigns resolves the segment of due campaigns and queues their recipients
+func (s *EmailCampaignService) startDueCampaigns(ctx context.Context) error {
+	for {
+		now := time.Now()
+		var campaign models.EmailCampaign
+		err := s.campaignCollection.FindOneAndUpdate(
+			ctx,
+			bson.M{"status": models.EmailCampaignStatusScheduled, "scheduled_at": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{
+				"status":     models.EmailCampaignStatusSending,
+				"started_at": now,
+				"updated_at": now,
+			}},
+			options.FindOneAndUpdate().SetSort(bson.D{{Key: "scheduled_at", Value: 1}}).SetReturnDocument(options.After),
+		).Decode(&campaign)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.enqueueRecipients(ctx, &campaign); err != nil {
+			return fmt.Errorf("failed to queue recipients of campaign %s: %w", campaign.ID.Hex(), err)
+		}
+	}
+}
+
+// resumeInterruptedCampaigns queues again the campaigns whose recipients were
+// being resolved during a restart; existing deliveries are not duplicated
+func (s *EmailCampaignService) resumeInterruptedCampaigns(ctx context.Context) error {
+	cursor, err := s.campaignCollection.Find(ctx, bson.M{
+		"status":           models.EmailCampaignStatusSending,
+		"total_recipients": 0,
+	})
+	if err != nil {
+		return err
+	}
+	var campaigns []models.EmailCampaign
+	if err := cursor.All(ctx, &campaigns); err != nil {
+		return err
+	}
+	for i := range campaigns {
+		if err := s.enqueueRecipients(ctx, &campaigns[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueueRecipients creates one pending delivery per user of the campaign segment
+func (s *EmailCampaignService) enqueueRecipients(ctx context.Context, campaign *models.EmailCampaign) error {
+	cursor, err := s.userCollection.Find(ctx, segmentFilter(campaign.Segment),
+		options.Find().SetProjection(bson.M{"email": 1, "first_name": 1, "last_name": 1}))
+	if err != nil {
+		return err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	recipients := make([]interface{}, 0, len(users))
+	for _, user := range users {
+		recipients = append(recipients, models.EmailCampaignRecipient{
+			ID:         primitive.NewObjectID(),
+			CampaignID: campaign.ID,
+			UserID:     user.ID,
+			Email:      user.Email,
+			Name:       strings.TrimSpace(user.FirstName + " " + user.LastName),
+			Status:     models.EmailDeliveryStatusPending,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+	if len(recipients) > 0 {
+		// Unordered so that a restart after a partial insert skips the duplicates
+		_, err := s.recipientCollection.InsertMany(ctx, recipients, options.InsertMany().SetOrdered(false))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+
+	update := bson.M{"total_recipients": int64(len(recipients)), "updated_at": now}
+	if len(recipients) == 0 {
+		update["status"] = models.EmailCampaignStatusCompleted
+		update["completed_at"] = now
+	}
+	_, err = s.campaignCollection.UpdateOne(ctx, bson.M{"_id": campaign.ID}, bson.M{"$set": update})
+	return err
+}
+
+// deliverNext sends the oldest pending email of the queue
+func (s *EmailCampaignService) deliverNext(ctx context.Context) error {
+	var recipient models.EmailCampaignRecipient
+	err := s.recipientCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"status": models.EmailDeliveryStatusPending},
+		bson.M{"$set": bson.M{"status": models.EmailDeliveryStatusSending, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetReturnDocument(options.After),
+	).Decode(&recipient)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	campaign, err := s.GetByID(ctx, recipient.CampaignID)
+	if err != nil {
+		return err
+	}
+
+	var user models.User
+	var sendErr error
+	if err := s.userCollection.FindOne(ctx, bson.M{"_id": recipient.UserID}).Decode(&user); err != nil {
+		sendErr = errors.New("user no longer exists")
+	} else {
+		department := s.departmentName(ctx, user.DepartmentID)
+		subject := s.render(campaign.Subject, &user, department, false)
+		body := s.renderBody(campaign.Body, campaign.IsHTML, &user, department)
+		if s.emailService.outbox == nil {
+			sendErr = s.emailService.DeliverCustomEmail(recipient.Email, recipient.Name, subject, body, user.Language)
+		} else {
+			// A duplicate means it was queued before a restart. The outbox may
+			// already have reported the outcome, hence the status condition.
+			sendErr = s.emailService.QueueCampaignEmail(recipient.ID, campaign.ID, recipient.Email, recipient.Name, subject, body, user.Language)
+			if sendErr == nil || mongo.IsDuplicateKeyError(sendErr) {
+				_, err := s.recipientCollection.UpdateOne(ctx, bson.M{
+					"_id":    recipient.ID,
+					"status": models.EmailDeliveryStatusSending,
+				}, bson.M{"$set": bson.M{
+					"status":     models.EmailDeliveryStatusQueued,
+					"updated_at": time.Now(),
+				}})
+				return err
+			}
+		}
+	}
+
+	return s.finishDelivery(ctx, recipient.ID, campaign.ID, sendErr, models.EmailDeliveryStatusSending)
+}
+
+// recordDelivery records the outcome of a campaign email reported by the outbox
+func (s *EmailCampaignService) recordDelivery(ctx context.Context, email *models.OutboxEmail) {
+	if email.CampaignID == nil {
+		return
+	}
+	var sendErr error
+	if email.Status != models.EmailDeliveryStatusSent {
+		sendErr = errors.New(email.LastError)
+	}
+	// The recipient may have been requeued by a restart after being queued
+	if err := s.finishDelivery(ctx, email.ID, *email.CampaignID, sendErr,
+		models.EmailDeliveryStatusPending, models.EmailDeliveryStatusSending, models.EmailDeliveryStatusQueued); err != nil {
+		fmt.Printf("Warning: Failed to record delivery of campaign email %s: %v\n", email.ID.Hex(), err)
+	}
+}
+
+// finishDelivery marks a recipient sent or failed, updates the counters of
+// its campaign and completes the campaign once its queue is drained. The
+// recipient must still be in one of the given statuses, so an outcome is
+// counted once.
+func (s *EmailCampaignService) finishDelivery(ctx context.Context, recipientID, campaignID primitive.ObjectID, sendErr error, from ...models.EmailDeliveryStatus) error {
+	now := time.Now()
+	set := bson.M{"status": models.EmailDeliveryStatusSent, "sent_at": now, "updated_at": now}
+	counter := "sent_count"
+	if sendErr != nil {
+		set = bson.M{"status": models.EmailDeliveryStatusFailed, "error": sendErr.Error(), "updated_at": now}
+		counter = "failed_count"
+	}
+	result, err := s.recipientCollection.UpdateOne(ctx, bson.M{"_id": recipientID, "status": bson.M{"$in": from}}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return nil
+	}
+	if _, err := s.campaignCollection.UpdateOne(ctx, bson.M{"_id": campaignID}, bson.M{
+		"$inc": bson.M{counter: 1},
+		"$set": bson.M{"updated_at": now},
+	}); err != nil {
+		return err
+	}
+
+	// Complete the campaign once its queue is drained
+	remaining, err := s.recipientCollection.CountDocuments(ctx, bson.M{
+		"campaign_id": campaignID,
+		"status": bson.M{"$in": []models.EmailDeliveryStatus{
+			models.EmailDeliveryStatusPending,
+			models.EmailDeliveryStatusSending,
+			models.EmailDeliveryStatusQueued,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if remaining == 0 {
+		_, err = s.campaignCollection.UpdateOne(ctx, bson.M{"_id": campaignID, "status": models.EmailCampaignStatusSending}, bson.M{
+			"$set": bson.M{"status": models.EmailCampaignStatusCompleted, "completed_at": now},
+		})
+	}
+	return err
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// emailDigestInterval is the delay between two checks for due digests
+const emailDigestInterval = 5 * time.Minute
+
+// EmailDigestService batches the notifications of the users who chose an
+// hourly or daily email frequency in email_digest_items, and sends them a
+// single summary email per period instead of one email per notification
+type EmailDigestService struct {
+	collection          *mongo.Collection
+	notificationService *NotificationService
+	emailService        *EmailService
+	userService         *UserService
+	dailyHour           int
+}
+
+// NewEmailDigestService creates the digest builder and routes the
+// notifications of notificationService through it. EMAIL_DIGEST_HOUR sets
+// the hour of the daily digest in the timezone of the user (default 8).
+func NewEmailDigestService(db *DatabaseService, notificationService *NotificationService, emailService *EmailService, userService *UserService) *EmailDigestService {
+	dailyHour := 8
+	if v, err := strconv.Atoi(os.Getenv("EMAIL_DIGEST_HOUR")); err == nil && v >= 0 && v < 24 {
+		dailyHour = v
+	}
+
+	service := &EmailDigestService{
+		collection:          db.Collection("email_digest_items"),
+		notificationService: notificationService,
+		emailService:        emailService,
+		userService:         userService,
+		dailyHour:           dailyHour,
+	}
+	notificationService.digest = service
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "digest_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create email digest indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// Start sends the due digests every few minutes until the context is cancelled
+func (s *EmailDigestService) Start(ctx context.Context) {
+	go func() {
+		// Digests interrupted by a restart are sent again
+		if _, err := s.collection.UpdateMany(ctx, bson.M{"digest_id": bson.M{"$exists": true}},
+			bson.M{"$unset": bson.M{"digest_id": ""}}); err != nil {
+			fmt.Printf("Warning: Failed to release interrupted email digests: %v\n", err)
+		}
+
+		ticker := time.NewTicker(emailDigestInterval)
+		defer ticker.Stop()
+		for {
+			count, err := s.RunDigests(ctx)
+			if err != nil {
+				fmt.Printf("Warning: Failed to send email digests: %v\n", err)
+			} else if count > 0 {
+				fmt.Printf("📬 %d email digest(s) sent\n", count)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	fmt.Printf("📬 Email digest builder started (daily digest at %02d:00)\n", s.dailyHour)
+}
+
+// queue keeps a notification for the next digest of the targeted users who
+// chose an hourly or daily email frequency and did not turn off its category
+// or event
+func (s *EmailDigestService) queue(ctx context.Context, req *models.SendNotificationRequest, userIDs []primitive.ObjectID) {
+	actionURL := req.ActionURL
+	if actionURL == "" {
+		actionURL = req.ClickAction
+	}
+	if documentID, ok := req.Data["documentId"].(string); ok && actionURL == "" {
+		actionURL = fmt.Sprintf("%s/documents/%s", s.emailService.appURL, documentID)
+	}
+
+	action, _ := req.Data["action"].(string)
+	now := time.Now()
+	var items []interface{}
+	for _, userID := range userIDs {
+		prefs, err := s.notificationService.GetUserPreferences(ctx, userID)
+		if err != nil || !prefs.UsesEmailDigest() {
+			continue
+		}
+		if allowed, exists := prefs.Categories[req.Category]; exists && !allowed {
+			continue
+		}
+		if s.emailService.eventSettings != nil && !s.emailService.eventSettings.allowsNotification(ctx, action, prefs) {
+			continue
+		}
+		items = append(items, models.EmailDigestItem{
+			UserID:    userID,
+			Category:  req.Category,
+			Priority:  req.Priority,
+			Title:     req.Title,
+			Body:      req.Body,
+			ActionURL: actionURL,
+			CreatedAt: now,
+		})
+	}
+	if len(items) == 0 {
+		return
+	}
+	if _, err := s.collection.InsertMany(ctx, items); err != nil {
+		fmt.Printf("Warning: Failed to queue notification for email digests: %v\n", err)
+	}
+}
+
+// RunDigests sends a digest to every user whose period is over and who has
+// notifications waiting, and returns the number of digests sent
+func (s *EmailDigestService) RunDigests(ctx context.Context) (int, error) {
+	userIDs, err := s.collection.Distinct(ctx, "user_id", bson.M{"digest_id": bson.M{"$exists": false}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find pending email digests: %w", err)
+	}
+
+	count := 0
+	now := time.Now()
+	for _, value := range userIDs {
+		if ctx.Err() != nil {
+			return count, ctx.Err()
+		}
+		userID, ok := value.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		prefs, err := s.notificationService.GetUserPreferences(ctx, userID)
+		if err != nil {
+			fmt.Printf("Warning: Failed to get notification preferences of %s: %v\n", userID.Hex(), err)
+			continue
+		}
+
+		// Users who turned the emails off no longer get the waiting notifications
+		if !prefs.EmailEnabled {
+			if _, err := s.collection.DeleteMany(ctx, bson.M{"user_id": userID, "digest_id": bson.M{"$exists": false}}); err != nil {
+				fmt.Printf("Warning: Failed to drop email digest of %s: %v\n", userID.Hex(), err)
+			}
+			continue
+		}
+
+		loc := digestLocation(prefs.Timezone)
+		sent, err := s.sendDigest(ctx, userID, s.digestBoundary(prefs.EmailFrequency, now.In(loc)), loc)
+		if err != nil {
+			fmt.Printf("Warning: Failed to send email digest to %s: %v\n", userID.Hex(), err)
+			continue
+		}
+		if sent {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// sendDigest sends the notifications of a user queued before the boundary in
+// a single email, false when there were none. The items are claimed first so
+// concurrent instances do not send them twice, and released if sending fails.
+func (s *EmailDigestService) sendDigest(ctx context.Context, userID primitive.ObjectID, boundary time.Time, loc *time.Location) (bool, error) {
+	digestID := primitive.NewObjectID()
+	result, err := s.collection.UpdateMany(ctx, bson.M{
+		"user_id":    userID,
+		"digest_id":  bson.M{"$exists": false},
+		"created_at": bson.M{"$lt": boundary},
+	}, bson.M{"$set": bson.M{"digest_id": digestID}})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim email digest items: %w", err)
+	}
+	if result.ModifiedCount == 0 {
+		return false, nil
+	}
+
+	cursor, err := s.collection.Find(ctx, bson.M{"digest_id": digestID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		s.release(ctx, digestID)
+		return false, fmt.Errorf("failed to find email digest items: %w", err)
+	}
+	var items []models.EmailDigestItem
+	if err := cursor.All(ctx, &items); err != nil {
+		s.release(ctx, digestID)
+		return false, fmt.Errorf("failed to decode email digest items: %w", err)
+	}
+
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil || !user.Active {
+		// Nobody is left to read them
+		_, err := s.collection.DeleteMany(ctx, bson.M{"digest_id": digestID})
+		return false, err
+	}
+
+	entries := make([]EmailDigestEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, EmailDigestEntry{
+			Title: item.Title,
+			Body:  item.Body,
+			URL:   item.ActionURL,
+			Time:  item.CreatedAt.In(loc).Format("02/01/2006 15:04"),
+		})
+	}
+	name := fmt.Sprintf("%s %s", user.FirstName, user.LastName)
+	if err := s.emailService.SendNotificationDigestEmail(user.Email, name, entries, user.Language); err != nil {
+		s.release(ctx, digestID)
+		return false, err
+	}
+
+	if _, err := s.collection.DeleteMany(ctx, bson.M{"digest_id": digestID}); err != nil {
+		return true, fmt.Errorf("failed to remove sent email digest items: %w", err)
+	}
+	return true, nil
+}
+
+// release returns the items of a digest that could not be sent to the queue
+func (s *EmailDigestService) release(ctx context.Context, digestID primitive.ObjectID) {
+	if _, err := s.collection.UpdateMany(ctx, bson.M{"digest_id": digestID},
+		bson.M{"$unset": bson.M{"digest_id": ""}}); err != nil {
+		fmt.Printf("Warning: Failed to release email digest %s: %v\n", digestID.Hex(), err)
+	}
+}
+
+// digestBoundary returns the end of the last period of a frequency: the
+// notifications queued before it are due. Hourly digests go out at the top
+// of every hour and daily ones at the digest hour, while the notifications
+// left when a user switches back to immediate are sent right away.
+func (s *EmailDigestService) digestBoundary(frequency models.EmailFrequency, now time.Time) time.Time {
+	switch frequency {
+	case models.EmailFrequencyHourly:
+		return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
+	case models.EmailFrequencyDaily:
+		boundary := time.Date(now.Year(), now.Month(), now.Day(), s.dailyHour, 0, 0, 0, now.Location())
+		if now.Before(boundary) {
+			boundary = boundary.AddDate(0, 0, -1)
+		}
+		return boundary
+	default:
+		return now
+	}
+}
+
+// digestLocation returns the timezone of a user, the server one when it is
+// unset or unknown
+func digestLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
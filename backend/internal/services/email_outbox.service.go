@@ -0,0 +1,416 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// emailOutboxInterval is the delay between two scans of the outbox for due retries
+	emailOutboxInterval = 15 * time.Second
+	// emailOutboxBatchSize bounds the emails delivered by one scan
+	emailOutboxBatchSize = 50
+	// emailRetryBaseDelay is the delay before the first retry, doubled at each attempt
+	emailRetryBaseDelay = 30 * time.Second
+	// emailRetryMaxDelay caps the delay between two attempts
+	emailRetryMaxDelay = time.Hour
+	// emailSendLease is how long an email may stay in sending before its
+	// delivery is considered interrupted. It outlasts the 30 seconds timeout
+	// of each provider across the whole fallback chain.
+	emailSendLease = 5 * time.Minute
+)
+
+// EmailOutboxService stores the emails to send in the email_outbox
+// collection and delivers them in the background, retrying failed
+// deliveries with an exponential backoff. The events reported by the provider
+// once sent are recorded on the emails, and the addresses that bounce are
+// kept in undeliverable_emails.
+type EmailOutboxService struct {
+	collection              *mongo.Collection
+	undeliverableCollection *mongo.Collection
+	userCollection          *mongo.Collection
+	emailService            *EmailService
+	maxAttempts             int
+	wake                    chan struct{} // Signals new emails to the dispatcher
+	onDelivery              []func(ctx context.Context, email *models.OutboxEmail)
+}
+
+// NewEmailOutboxService creates the outbox and routes the emails of
+// emailService through it. EMAIL_OUTBOX_MAX_ATTEMPTS sets the attempts before
+// an email is marked failed (default 6) and EMAIL_OUTBOX_RETENTION_DAYS how
+// long the emails are kept (default 30).
+func NewEmailOutboxService(db *DatabaseService, emailService *EmailService) *EmailOutboxService {
+	maxAttempts := 6
+	if v, err := strconv.Atoi(os.Getenv("EMAIL_OUTBOX_MAX_ATTEMPTS")); err == nil && v > 0 {
+		maxAttempts = v
+	}
+	retentionDays := 30
+	if v, err := strconv.Atoi(os.Getenv("EMAIL_OUTBOX_RETENTION_DAYS")); err == nil && v > 0 {
+		retentionDays = v
+	}
+
+	service := &EmailOutboxService{
+		collection:              db.Collection("email_outbox"),
+		undeliverableCollection: db.Collection("undeliverable_emails"),
+		userCollection:          db.Collection("users"),
+		emailService:            emailService,
+		maxAttempts:             maxAttempts,
+		wake:                    make(chan struct{}, 1),
+	}
+	emailService.outbox = service
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}, Options: options.Index().SetExpireAfterSeconds(int32(retentionDays * 24 * 3600))},
+		{Keys: bson.D{{Key: "message_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create email outbox indexes: %v\n", err)
+	}
+	if _, err := service.undeliverableCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "last_at", Value: -1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create undeliverable email indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// enqueue stores an email to send and wakes the dispatcher up
+func (s *EmailOutboxService) enqueue(email *models.OutboxEmail) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	email.Status = models.EmailDeliveryStatusPending
+	email.NextAttemptAt = now
+	email.CreatedAt = now
+	email.UpdatedAt = now
+	if _, err := s.collection.InsertOne(ctx, email); err != nil {
+		return fmt.Errorf("failed to queue email: %w", err)
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// OnDelivery registers a callback run once an email is sent or given up on,
+// for the senders following the outcome of their emails
+func (s *EmailOutboxService) OnDelivery(fn func(ctx context.Context, email *models.OutboxEmail)) {
+	s.onDelivery = append(s.onDelivery, fn)
+}
+
+// Start runs the outbox dispatcher until the context is cancelled. Emails
+// are sent as soon as they are queued, retries when they are due.
+func (s *EmailOutboxService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(emailOutboxInterval)
+		defer ticker.Stop()
+		for {
+			s.requeueInterrupted(ctx, time.Now())
+			s.dispatch(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+	fmt.Printf("📧 Email outbox dispatcher started (%d attempts per email)\n", s.maxAttempts)
+}
+
+// requeueInterrupted sends again the deliveries interrupted by a restart or
+// a crash. Only the emails in sending for longer than the lease are
+// requeued, the recent ones may still be sent by another instance.
+func (s *EmailOutboxService) requeueInterrupted(ctx context.Context, now time.Time) {
+	result, err := s.collection.UpdateMany(ctx,
+		bson.M{"status": models.EmailDeliveryStatusSending, "updated_at": bson.M{"$lt": now.Add(-emailSendLease)}},
+		bson.M{"$set": bson.M{"status": models.EmailDeliveryStatusPending, "updated_at": now}})
+	if err != nil {
+		fmt.Printf("Warning: Failed to requeue interrupted emails: %v\n", err)
+		return
+	}
+	if result.ModifiedCount > 0 {
+		fmt.Printf("⚠️  [OUTBOX] Requeued %d interrupted email deliveries\n", result.ModifiedCount)
+	}
+}
+
+// dispatch delivers the due emails, oldest first
+func (s *EmailOutboxService) dispatch(ctx context.Context) {
+	for i := 0; i < emailOutboxBatchSize; i++ {
+		if ctx.Err() != nil {
+			return
+		}
+		delivered, err := s.deliverNext(ctx)
+		if err != nil {
+			fmt.Printf("Warning: Failed to deliver outbox email: %v\n", err)
+			return
+		}
+		if !delivered {
+			return
+		}
+	}
+}
+
+// deliverNext sends the next due email and records the outcome, false when
+// no email is due
+func (s *EmailOutboxService) deliverNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	var email models.OutboxEmail
+	err := s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"status": models.EmailDeliveryStatusPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{
+			"$set": bson.M{"status": models.EmailDeliveryStatusSending, "updated_at": now},
+			"$inc": bson.M{"attempts": 1},
+		},
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).SetReturnDocument(options.After),
+	).Decode(&email)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	sendErr := s.emailService.deliver(&email)
+
+	now = time.Now()
+	var update bson.M
+	final := true
+	switch {
+	case sendErr == nil:
+		email.Status = models.EmailDeliveryStatusSent
+		// The bodies can hold one-time codes, they are not kept once sent,
+		// and neither are the attachments
+		set := bson.M{
+			"status":     models.EmailDeliveryStatusSent,
+			"provider":   email.Provider,
+			"sent_at":    now,
+			"updated_at": now,
+		}
+		if email.MessageID != "" {
+			set["message_id"] = email.MessageID
+		}
+		update = bson.M{
+			"$set":   set,
+			"$unset": bson.M{"html_body": "", "text_body": "", "attachments": "", "last_error": ""},
+		}
+	case email.Attempts >= s.maxAttempts:
+		fmt.Printf("❌ [OUTBOX] Giving up on %s email to %s after %d attempts: %v\n", email.Kind, email.ToEmail, email.Attempts, sendErr)
+		email.Status = models.EmailDeliveryStatusFailed
+		email.LastError = sendErr.Error()
+		update = bson.M{"$set": bson.M{
+			"status":     models.EmailDeliveryStatusFailed,
+			"last_error": sendErr.Error(),
+			"updated_at": now,
+		}}
+	default:
+		final = false
+		retryAt := now.Add(emailRetryDelay(email.Attempts))
+		fmt.Printf("⚠️  [OUTBOX] Attempt %d of %s email to %s failed, retrying at %s: %v\n",
+			email.Attempts, email.Kind, email.ToEmail, retryAt.Format(time.RFC3339), sendErr)
+		update = bson.M{"$set": bson.M{
+			"status":          models.EmailDeliveryStatusPending,
+			"last_error":      sendErr.Error(),
+			"next_attempt_at": retryAt,
+			"updated_at":      now,
+		}}
+	}
+
+	if _, err := s.collection.UpdateOne(ctx, bson.M{"_id": email.ID}, update); err != nil {
+		return true, fmt.Errorf("failed to record delivery of email %s: %w", email.ID.Hex(), err)
+	}
+	if final {
+		for _, fn := range s.onDelivery {
+			fn(ctx, &email)
+		}
+	}
+	return true, nil
+}
+
+// emailRetryDelay returns the delay before the next attempt of an email
+// that failed attempts times
+func emailRetryDelay(attempts int) time.Duration {
+	delay := emailRetryBaseDelay
+	for i := 1; i < attempts && delay < emailRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, emailRetryMaxDelay)
+}
+
+// List returns the emails of the outbox, newest first
+func (s *EmailOutboxService) List(ctx context.Context, filter models.OutboxEmailFilter) ([]*models.OutboxEmail, int64, error) {
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Event != "" {
+		query["last_event"] = filter.Event
+	}
+	if filter.Kind != "" {
+		query["kind"] = filter.Kind
+	}
+	if filter.Email != "" {
+		query["to_email"] = bson.M{"$regex": regexp.QuoteMeta(filter.Email), "$options": "i"}
+	}
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count emails: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit)).
+		SetProjection(bson.M{"html_body": 0, "text_body": 0, "attachments": 0})
+
+	cursor, err := s.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find emails: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	emails := make([]*models.OutboxEmail, 0)
+	if err = cursor.All(ctx, &emails); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode emails: %w", err)
+	}
+
+	return emails, total, nil
+}
+
+// RecordEvent stores an event reported by the email provider on the outbox
+// email it concerns. Bounces, blocks and spam reports flag the address as
+// undeliverable, a later delivery lifts the flag.
+func (s *EmailOutboxService) RecordEvent(ctx context.Context, event models.ProviderEmailEvent) error {
+	address := strings.ToLower(strings.TrimSpace(event.Email))
+
+	var filter bson.M
+	if event.MessageID != "" {
+		filter = bson.M{"message_id": event.MessageID}
+	} else if id, err := primitive.ObjectIDFromHex(event.OutboxID); err == nil {
+		filter = bson.M{"_id": id}
+	}
+
+	kind := ""
+	if filter != nil {
+		var email models.OutboxEmail
+		err := s.collection.FindOneAndUpdate(ctx, filter, bson.M{
+			"$set": bson.M{"last_event": event.Type, "updated_at": time.Now()},
+			"$push": bson.M{"events": models.EmailEvent{
+				Type:   event.Type,
+				Reason: event.Reason,
+				At:     event.At,
+			}},
+		}, options.FindOneAndUpdate().SetProjection(bson.M{"kind": 1, "to_email": 1})).Decode(&email)
+		switch {
+		case err == nil:
+			kind = email.Kind
+			if address == "" {
+				address = strings.ToLower(email.ToEmail)
+			}
+		case err != mongo.ErrNoDocuments:
+			return fmt.Errorf("failed to record email event: %w", err)
+		}
+		// Emails past their retention are no longer in the outbox, the
+		// address is still flagged
+	}
+	if address == "" {
+		return nil
+	}
+
+	switch {
+	case event.Type.IsUndeliverable():
+		return s.flagUndeliverable(ctx, address, kind, event)
+	case event.Type == models.EmailEventDelivered:
+		if _, err := s.undeliverableCollection.DeleteOne(ctx, bson.M{"email": address}); err != nil {
+			return fmt.Errorf("failed to clear undeliverable email: %w", err)
+		}
+	}
+	return nil
+}
+
+// flagUndeliverable records that an address does not receive the emails
+func (s *EmailOutboxService) flagUndeliverable(ctx context.Context, address, kind string, event models.ProviderEmailEvent) error {
+	set := bson.M{
+		"event":   event.Type,
+		"reason":  event.Reason,
+		"last_at": event.At,
+	}
+	if kind != "" {
+		set["last_kind"] = kind
+	}
+	var user models.User
+	if err := s.userCollection.FindOne(ctx, bson.M{"email": address},
+		options.FindOne().SetProjection(bson.M{"_id": 1})).Decode(&user); err == nil {
+		set["user_id"] = user.ID
+	}
+
+	if _, err := s.undeliverableCollection.UpdateOne(ctx, bson.M{"email": address}, bson.M{
+		"$set":         set,
+		"$setOnInsert": bson.M{"first_at": event.At},
+		"$inc":         bson.M{"count": 1},
+	}, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to flag undeliverable email: %w", err)
+	}
+
+	fmt.Printf("⚠️  [OUTBOX] %s flagged as undeliverable (%s): %s\n", address, event.Type, event.Reason)
+	return nil
+}
+
+// ListUndeliverable returns the addresses flagged as undeliverable, most recent first
+func (s *EmailOutboxService) ListUndeliverable(ctx context.Context, page, limit int) ([]*models.UndeliverableEmail, int64, error) {
+	total, err := s.undeliverableCollection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count undeliverable emails: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "last_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := s.undeliverableCollection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find undeliverable emails: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	emails := make([]*models.UndeliverableEmail, 0)
+	if err = cursor.All(ctx, &emails); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode undeliverable emails: %w", err)
+	}
+
+	return emails, total, nil
+}
+
+// ClearUndeliverable lifts the flag of an address, once it has been fixed
+func (s *EmailOutboxService) ClearUndeliverable(ctx context.Context, address string) error {
+	result, err := s.undeliverableCollection.DeleteOne(ctx, bson.M{"email": strings.ToLower(strings.TrimSpace(address))})
+	if err != nil {
+		return fmt.Errorf("failed to clear undeliverable email: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("undeliverable email not found")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestRequeueInterruptedOnlyRequeuesStaleDeliveries(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("stale and fresh deliveries", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		service := &EmailOutboxService{collection: mt.Coll}
+		now := time.Now()
+		service.requeueInterrupted(context.Background(), now)
+
+		event := mt.GetStartedEvent()
+		if event == nil || event.CommandName != "update" {
+			t.Fatalf("expected an update command, got %v", event)
+		}
+		var cmd struct {
+			Updates []struct {
+				Q bson.M `bson:"q"`
+			} `bson:"updates"`
+		}
+		if err := bson.Unmarshal(event.Command, &cmd); err != nil {
+			t.Fatal(err)
+		}
+		if len(cmd.Updates) != 1 {
+			t.Fatalf("expected one update statement, got %d", len(cmd.Updates))
+		}
+		filter := cmd.Updates[0].Q
+		if filter["status"] != string(models.EmailDeliveryStatusSending) {
+			t.Fatalf("expected the emails in sending to be requeued, got status %v", filter["status"])
+		}
+		updatedAt, ok := filter["updated_at"].(bson.M)
+		if !ok {
+			t.Fatalf("expected the requeue to be limited by updated_at, got %v", filter)
+		}
+		cutoff := updatedAt["$lt"].(primitive.DateTime).Time()
+
+		// An email claimed before the lease expired was interrupted
+		stale := now.Add(-emailSendLease - time.Minute)
+		if !stale.Before(cutoff) {
+			t.Errorf("expected an email in sending since %s to be requeued", stale.Format(time.RFC3339))
+		}
+		// An email claimed a few seconds ago may still be sent by another instance
+		fresh := now.Add(-10 * time.Second)
+		if fresh.Before(cutoff) {
+			t.Errorf("expected an email in sending since %s to be left alone", fresh.Format(time.RFC3339))
+		}
+	})
+}
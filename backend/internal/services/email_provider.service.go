@@ -0,0 +1,186 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// EmailProvider delivers the rendered emails through an email service
+type EmailProvider interface {
+	// Name identifies the provider in EMAIL_PROVIDERS and in the outbox
+	Name() string
+	// Send delivers an email, setting its MessageID when the provider returns one
+	Send(email *models.OutboxEmail) error
+	// Address is the host:port dialed by the health check
+	Address() string
+}
+
+// tracedEmailProvider is implemented by the providers able to report the
+// steps of a delivery, used by the test emails
+type tracedEmailProvider interface {
+	sendTraced(email *models.OutboxEmail, trace *emailTrace) error
+}
+
+// emailTrace records the steps of a delivery. A nil trace records nothing.
+type emailTrace struct {
+	steps []models.EmailDiagnosticStep
+}
+
+// record adds a step started at start and returns its error
+func (t *emailTrace) record(name string, start time.Time, detail string, err error) error {
+	if t == nil {
+		return err
+	}
+	if err != nil {
+		if detail != "" {
+			detail += ": "
+		}
+		detail += err.Error()
+	}
+	t.steps = append(t.steps, models.EmailDiagnosticStep{
+		Name:       name,
+		Success:    err == nil,
+		Detail:     detail,
+		DurationMs: time.Since(start).Milliseconds(),
+	})
+	return err
+}
+
+// defaultEmailProviderOrder is the failover order used when EMAIL_PROVIDERS is unset
+const defaultEmailProviderOrder = "mailer_api,brevo,smtp,ses,mailgun"
+
+// emailProviderFactories build the providers from their configuration, nil
+// when a provider is not configured
+var emailProviderFactories = map[string]func(fromName, fromEmail string) EmailProvider{
+	"mailer_api": newMailerAPIEmailProvider,
+	"brevo":      newBrevoEmailProvider,
+	"smtp":       newSMTPEmailProvider,
+	"ses":        newSESEmailProvider,
+	"mailgun":    newMailgunEmailProvider,
+}
+
+// newEmailProviders returns the configured providers in failover order.
+// EMAIL_PROVIDERS lists the providers to use, the first one being tried
+// first (for example "brevo,smtp"); without it every configured provider is
+// used in the default order.
+func newEmailProviders(fromName, fromEmail string) []EmailProvider {
+	order := os.Getenv("EMAIL_PROVIDERS")
+	if strings.TrimSpace(order) == "" {
+		order = defaultEmailProviderOrder
+	}
+
+	var providers []EmailProvider
+	seen := map[string]bool{}
+	for _, name := range strings.Split(order, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		factory, ok := emailProviderFactories[name]
+		if !ok {
+			fmt.Printf("Warning: Unknown email provider %q in EMAIL_PROVIDERS\n", name)
+			continue
+		}
+		provider := factory(fromName, fromEmail)
+		if provider == nil {
+			if os.Getenv("EMAIL_PROVIDERS") != "" {
+				fmt.Printf("Warning: Email provider %s is not configured, skipping it\n", name)
+			}
+			continue
+		}
+		providers = append(providers, provider)
+	}
+
+	names := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		names = append(names, provider.Name())
+	}
+	if len(names) == 0 {
+		fmt.Println("⚠️  Warning: No email provider configured, emails will not be sent")
+	} else {
+		fmt.Printf("📧 Email providers (failover order): %s\n", strings.Join(names, ", "))
+	}
+	return providers
+}
+
+// hostPortFromURL returns the host:port pair of an HTTP(S) URL
+func hostPortFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "http" {
+		return u.Hostname() + ":80"
+	}
+	return u.Hostname() + ":443"
+}
+
+// buildMIMEMessage builds the MIME message of an email, with its text and
+// HTML bodies as alternatives and its attachments, if any, alongside them
+func buildMIMEMessage(from, to string, email *models.OutboxEmail) string {
+	var message strings.Builder
+
+	// Headers
+	message.WriteString(fmt.Sprintf("From: %s\r\n", from))
+	message.WriteString(fmt.Sprintf("To: %s\r\n", to))
+	message.WriteString(fmt.Sprintf("Subject: %s\r\n", email.Subject))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	if len(email.Attachments) > 0 {
+		message.WriteString("Content-Type: multipart/mixed; boundary=\"mixed123\"\r\n")
+		message.WriteString("\r\n")
+		message.WriteString("--mixed123\r\n")
+	}
+	message.WriteString("Content-Type: multipart/alternative; boundary=\"boundary123\"\r\n")
+	message.WriteString("\r\n")
+
+	// Text part
+	message.WriteString("--boundary123\r\n")
+	message.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	message.WriteString("Content-Transfer-Encoding: 7bit\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(email.TextBody)
+	message.WriteString("\r\n")
+
+	// HTML part
+	message.WriteString("--boundary123\r\n")
+	message.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	message.WriteString("Content-Transfer-Encoding: 7bit\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(email.HTMLBody)
+	message.WriteString("\r\n")
+
+	// End boundary
+	message.WriteString("--boundary123--\r\n")
+	if len(email.Attachments) == 0 {
+		return message.String()
+	}
+
+	// Attachments, base64 encoded in lines of 76 characters
+	for _, attachment := range email.Attachments {
+		message.WriteString("--mixed123\r\n")
+		message.WriteString(fmt.Sprintf("Content-Type: %s; name=\"%s\"\r\n", attachment.ContentType, attachment.Filename))
+		message.WriteString(fmt.Sprintf("Content-Disposition: attachment; filename=\"%s\"\r\n", attachment.Filename))
+		message.WriteString("Content-Transfer-Encoding: base64\r\n")
+		message.WriteString("\r\n")
+		encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+		for len(encoded) > 76 {
+			message.WriteString(encoded[:76] + "\r\n")
+			encoded = encoded[76:]
+		}
+		message.WriteString(encoded + "\r\n")
+	}
+	message.WriteString("--mixed123--\r\n")
+
+	return message.String()
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// Brevo API structures
+type BrevoEmailRequest struct {
+	Sender      BrevoSender       `json:"sender"`
+	To          []BrevoContact    `json:"to"`
+	Subject     string            `json:"subject"`
+	HTMLContent string            `json:"htmlContent"`
+	TextContent string            `json:"textContent,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Attachment  []BrevoAttachment `json:"attachment,omitempty"`
+}
+
+type BrevoSender struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type BrevoContact struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// BrevoAttachment is a file attached to an email, its content base64 encoded
+type BrevoAttachment struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+type BrevoResponse struct {
+	MessageID string `json:"messageId"`
+}
+
+// brevoEmailProvider sends the emails through the Brevo transactional API,
+// configured by BREVO_KEY
+type brevoEmailProvider struct {
+	apiKey    string
+	apiURL    string
+	fromName  string
+	fromEmail string
+}
+
+func newBrevoEmailProvider(fromName, fromEmail string) EmailProvider {
+	apiKey := os.Getenv("BREVO_KEY")
+	if apiKey == "" {
+		return nil
+	}
+	return &brevoEmailProvider{
+		apiKey:    apiKey,
+		apiURL:    "https://api.brevo.com/v3/smtp/email",
+		fromName:  fromName,
+		fromEmail: fromEmail,
+	}
+}
+
+func (p *brevoEmailProvider) Name() string { return "brevo" }
+
+func (p *brevoEmailProvider) Address() string { return hostPortFromURL(p.apiURL) }
+
+// Send sends email using Brevo API
+func (p *brevoEmailProvider) Send(email *models.OutboxEmail) error {
+	return p.sendTraced(email, nil)
+}
+
+// sendTraced sends an email, recording the response of the Brevo API
+func (p *brevoEmailProvider) sendTraced(email *models.OutboxEmail, trace *emailTrace) error {
+	// Prepare Brevo email request
+	brevoRequest := BrevoEmailRequest{
+		Sender: BrevoSender{
+			Name:  p.fromName,
+			Email: p.fromEmail,
+		},
+		To: []BrevoContact{
+			{
+				Name:  email.ToName,
+				Email: email.ToEmail,
+			},
+		},
+		Subject:     email.Subject,
+		HTMLContent: email.HTMLBody,
+		TextContent: email.TextBody,
+	}
+	if !email.ID.IsZero() {
+		// Echoed in the webhook events, to match them with the outbox
+		brevoRequest.Headers = map[string]string{"X-Mailin-custom": email.ID.Hex()}
+	}
+	for _, attachment := range email.Attachments {
+		brevoRequest.Attachment = append(brevoRequest.Attachment, BrevoAttachment{
+			Name:    attachment.Filename,
+			Content: base64.StdEncoding.EncodeToString(attachment.Content),
+		})
+	}
+
+	// Marshal request to JSON
+	jsonData, err := json.Marshal(brevoRequest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Brevo request: %w", err)
+	}
+
+	// Log the request details (without API key)
+	fmt.Printf("📤 [BREVO] Sending request to: %s\n", p.apiURL)
+	fmt.Printf("📤 [BREVO] Request payload: %s\n", string(jsonData))
+
+	// Create HTTP client with timeout
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequest("POST", p.apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers according to Brevo API documentation
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("api-key", p.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	// Send request
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return trace.record("api_request", start, p.apiURL, fmt.Errorf("failed to send HTTP request to Brevo: %w", err))
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return trace.record("api_request", start, p.apiURL, fmt.Errorf("failed to read Brevo response: %w", err))
+	}
+
+	// Check response status
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return trace.record("api_request", start, "", fmt.Errorf("Brevo API error (status %d): %s", resp.StatusCode, string(body)))
+	}
+	trace.record("api_request", start, fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), nil)
+
+	// Parse response
+	var brevoResponse BrevoResponse
+	if err := json.Unmarshal(body, &brevoResponse); err != nil {
+		fmt.Printf("⚠️ Warning: Failed to parse Brevo response: %v\n", err)
+		fmt.Printf("📄 Raw Brevo response: %s\n", string(body))
+	} else {
+		email.MessageID = brevoResponse.MessageID
+		fmt.Printf("✅ [BREVO] Email sent successfully (MessageID: %s) to %s\n", brevoResponse.MessageID, email.ToEmail)
+	}
+
+	fmt.Printf("📊 [BREVO] Response Status: %d, Response Body: %s\n", resp.StatusCode, string(body))
+	return nil
+}
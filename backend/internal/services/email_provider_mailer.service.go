@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// mailerAPIEmailProvider sends the emails through the external PHP mailer
+// API, configured by MAILER_API_URL and MAILER_API_KEY
+type mailerAPIEmailProvider struct {
+	apiURL string
+	apiKey string
+}
+
+func newMailerAPIEmailProvider(fromName, fromEmail string) EmailProvider {
+	apiURL := os.Getenv("MAILER_API_URL")
+	if apiURL == "" {
+		return nil
+	}
+	return &mailerAPIEmailProvider{apiURL: apiURL, apiKey: os.Getenv("MAILER_API_KEY")}
+}
+
+func (p *mailerAPIEmailProvider) Name() string { return "mailer_api" }
+
+func (p *mailerAPIEmailProvider) Address() string { return hostPortFromURL(p.apiURL) }
+
+// Send sends email using the external PHP mailer API
+func (p *mailerAPIEmailProvider) Send(email *models.OutboxEmail) error {
+	// Build payload expected by PHP mailer API
+	payload := map[string]any{
+		"to": []map[string]string{{
+			"email": email.ToEmail,
+			"name":  email.ToName,
+		}},
+		"subject": email.Subject,
+		"html":    email.HTMLBody,
+		"text":    email.TextBody,
+	}
+	if len(email.Attachments) > 0 {
+		attachments := make([]map[string]string, 0, len(email.Attachments))
+		for _, attachment := range email.Attachments {
+			attachments = append(attachments, map[string]string{
+				"filename":    attachment.Filename,
+				"contentType": attachment.ContentType,
+				"content":     base64.StdEncoding.EncodeToString(attachment.Content),
+			})
+		}
+		payload["attachments"] = attachments
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mailer payload: %w", err)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequest("POST", p.apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create mailer request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("X-API-KEY", p.apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mailer API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("mailer API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
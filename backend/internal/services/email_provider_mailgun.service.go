@@ -0,0 +1,131 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// mailgunEmailProvider sends the emails through the Mailgun messages API,
+// configured by MAILGUN_API_KEY, MAILGUN_DOMAIN and MAILGUN_API_URL
+// (https://api.eu.mailgun.net for domains hosted in the EU)
+type mailgunEmailProvider struct {
+	apiKey    string
+	endpoint  string
+	fromName  string
+	fromEmail string
+}
+
+func newMailgunEmailProvider(fromName, fromEmail string) EmailProvider {
+	apiKey := os.Getenv("MAILGUN_API_KEY")
+	domain := os.Getenv("MAILGUN_DOMAIN")
+	if apiKey == "" || domain == "" {
+		return nil
+	}
+	apiURL := strings.TrimSuffix(os.Getenv("MAILGUN_API_URL"), "/")
+	if apiURL == "" {
+		apiURL = "https://api.mailgun.net"
+	}
+
+	return &mailgunEmailProvider{
+		apiKey:    apiKey,
+		endpoint:  fmt.Sprintf("%s/v3/%s/messages", apiURL, url.PathEscape(domain)),
+		fromName:  fromName,
+		fromEmail: fromEmail,
+	}
+}
+
+func (p *mailgunEmailProvider) Name() string { return "mailgun" }
+
+func (p *mailgunEmailProvider) Address() string { return hostPortFromURL(p.endpoint) }
+
+// Send sends email using the Mailgun messages API
+func (p *mailgunEmailProvider) Send(email *models.OutboxEmail) error {
+	form := url.Values{}
+	form.Set("from", (&mail.Address{Name: p.fromName, Address: p.fromEmail}).String())
+	form.Set("to", (&mail.Address{Name: email.ToName, Address: email.ToEmail}).String())
+	form.Set("subject", email.Subject)
+	form.Set("html", email.HTMLBody)
+	if email.TextBody != "" {
+		form.Set("text", email.TextBody)
+	}
+	if !email.ID.IsZero() {
+		// Echoed in the Mailgun events, to match them with the outbox
+		form.Set("v:outbox_id", email.ID.Hex())
+	}
+
+	// The attachments need a multipart form, the plain form is kept otherwise
+	contentType := "application/x-www-form-urlencoded"
+	var payload io.Reader = strings.NewReader(form.Encode())
+	if len(email.Attachments) > 0 {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		for key, values := range form {
+			for _, value := range values {
+				if err := writer.WriteField(key, value); err != nil {
+					return fmt.Errorf("failed to build Mailgun form: %w", err)
+				}
+			}
+		}
+		for _, attachment := range email.Attachments {
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachment"; filename="%s"`, attachment.Filename))
+			header.Set("Content-Type", attachment.ContentType)
+			part, err := writer.CreatePart(header)
+			if err != nil {
+				return fmt.Errorf("failed to build Mailgun form: %w", err)
+			}
+			if _, err := part.Write(attachment.Content); err != nil {
+				return fmt.Errorf("failed to build Mailgun form: %w", err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return fmt.Errorf("failed to build Mailgun form: %w", err)
+		}
+		contentType = writer.FormDataContentType()
+		payload = &buf
+	}
+
+	req, err := http.NewRequest("POST", p.endpoint, payload)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.SetBasicAuth("api", p.apiKey)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send HTTP request to Mailgun: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read Mailgun response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Mailgun API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var mailgunResponse struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &mailgunResponse); err != nil {
+		fmt.Printf("⚠️ Warning: Failed to parse Mailgun response: %v\n", err)
+	} else {
+		email.MessageID = strings.Trim(mailgunResponse.ID, "<>")
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"os"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// sesEmailProvider sends the emails through the Amazon SES v2 API, configured
+// by AWS_SES_REGION (or AWS_REGION), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+// and the optional AWS_SESSION_TOKEN and AWS_SES_CONFIGURATION_SET. Requests
+// are signed with AWS Signature Version 4.
+type sesEmailProvider struct {
+	region           string
+	accessKeyID      string
+	secretAccessKey  string
+	sessionToken     string
+	configurationSet string
+	endpoint         string
+	fromName         string
+	fromEmail        string
+}
+
+// sesSendEmailRequest is the body of the SES v2 SendEmail operation
+type sesSendEmailRequest struct {
+	FromEmailAddress     string         `json:"FromEmailAddress"`
+	Destination          sesDestination `json:"Destination"`
+	Content              sesContent     `json:"Content"`
+	EmailTags            []sesEmailTag  `json:"EmailTags,omitempty"`
+	ConfigurationSetName string         `json:"ConfigurationSetName,omitempty"`
+}
+
+type sesDestination struct {
+	ToAddresses []string `json:"ToAddresses"`
+}
+
+// sesContent holds either a simple message or, for the emails with
+// attachments, the raw MIME message
+type sesContent struct {
+	Simple *sesMessage `json:"Simple,omitempty"`
+	Raw    *sesRaw     `json:"Raw,omitempty"`
+}
+
+type sesRaw struct {
+	Data []byte `json:"Data"`
+}
+
+type sesMessage struct {
+	Subject sesText `json:"Subject"`
+	Body    sesBody `json:"Body"`
+}
+
+type sesBody struct {
+	HTML *sesText `json:"Html,omitempty"`
+	Text *sesText `json:"Text,omitempty"`
+}
+
+type sesText struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesEmailTag struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+func newSESEmailProvider(fromName, fromEmail string) EmailProvider {
+	region := os.Getenv("AWS_SES_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if region == "" || accessKeyID == "" || secretAccessKey == "" {
+		return nil
+	}
+
+	return &sesEmailProvider{
+		region:           region,
+		accessKeyID:      accessKeyID,
+		secretAccessKey:  secretAccessKey,
+		sessionToken:     os.Getenv("AWS_SESSION_TOKEN"),
+		configurationSet: os.Getenv("AWS_SES_CONFIGURATION_SET"),
+		endpoint:         fmt.Sprintf("https://email.%s.amazonaws.com/v2/email/outbound-emails", region),
+		fromName:         fromName,
+		fromEmail:        fromEmail,
+	}
+}
+
+func (p *sesEmailProvider) Name() string { return "ses" }
+
+func (p *sesEmailProvider) Address() string { return hostPortFromURL(p.endpoint) }
+
+// Send sends email using the SES v2 SendEmail operation
+func (p *sesEmailProvider) Send(email *models.OutboxEmail) error {
+	from := (&mail.Address{Name: p.fromName, Address: p.fromEmail}).String()
+	to := (&mail.Address{Name: email.ToName, Address: email.ToEmail}).String()
+
+	sesRequest := sesSendEmailRequest{
+		FromEmailAddress:     from,
+		Destination:          sesDestination{ToAddresses: []string{to}},
+		ConfigurationSetName: p.configurationSet,
+	}
+	if len(email.Attachments) > 0 {
+		sesRequest.Content.Raw = &sesRaw{Data: []byte(buildMIMEMessage(from, to, email))}
+	} else {
+		sesRequest.Content.Simple = &sesMessage{
+			Subject: sesText{Data: email.Subject, Charset: "UTF-8"},
+			Body: sesBody{
+				HTML: &sesText{Data: email.HTMLBody, Charset: "UTF-8"},
+			},
+		}
+		if email.TextBody != "" {
+			sesRequest.Content.Simple.Body.Text = &sesText{Data: email.TextBody, Charset: "UTF-8"}
+		}
+	}
+	if !email.ID.IsZero() {
+		// Echoed in the SES events, to match them with the outbox
+		sesRequest.EmailTags = []sesEmailTag{{Name: "outbox_id", Value: email.ID.Hex()}}
+	}
+
+	jsonData, err := json.Marshal(sesRequest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SES request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", p.endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	p.sign(req, jsonData, time.Now())
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send HTTP request to SES: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read SES response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("SES API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var sesResponse struct {
+		MessageID string `json:"MessageId"`
+	}
+	if err := json.Unmarshal(body, &sesResponse); err != nil {
+		fmt.Printf("⚠️ Warning: Failed to parse SES response: %v\n", err)
+	} else {
+		email.MessageID = sesResponse.MessageID
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 headers to a request
+func (p *sesEmailProvider) sign(req *http.Request, payload []byte, now time.Time) {
+	const service = "ses"
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	signedHeaders := "content-type;host;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-amz-date:%s\n", req.Header.Get("Content-Type"), req.URL.Host, amzDate)
+	if p.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.sessionToken)
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += fmt.Sprintf("x-amz-security-token:%s\n", p.sessionToken)
+	}
+
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s",
+		req.Method, req.URL.EscapedPath(), req.URL.RawQuery, canonicalHeaders, signedHeaders, hex.EncodeToString(payloadHash[:]))
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, p.region, service)
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := fmt.Sprintf("AWS4-HMAC-SHA256\n%s\n%s\n%s", amzDate, scope, hex.EncodeToString(requestHash[:]))
+
+	key := hmacSHA256([]byte("AWS4"+p.secretAccessKey), date)
+	key = hmacSHA256(key, p.region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.accessKeyID, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// smtpEmailProvider sends the emails through an SMTP server over TLS,
+// configured by SMTP_HOST, SMTP_PORT, SMTP_USERNAME and SMTP_PASSWORD
+type smtpEmailProvider struct {
+	host      string
+	port      int
+	username  string
+	password  string
+	fromName  string
+	fromEmail string
+}
+
+func newSMTPEmailProvider(fromName, fromEmail string) EmailProvider {
+	username := os.Getenv("SMTP_USERNAME")
+	password := os.Getenv("SMTP_PASSWORD")
+	if username == "" || password == "" {
+		return nil
+	}
+
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		host = "smtp.hostinger.com"
+	}
+	port := 465
+	if v, err := strconv.Atoi(os.Getenv("SMTP_PORT")); err == nil {
+		port = v
+	}
+
+	return &smtpEmailProvider{
+		host:      host,
+		port:      port,
+		username:  username,
+		password:  password,
+		fromName:  fromName,
+		fromEmail: fromEmail,
+	}
+}
+
+func (p *smtpEmailProvider) Name() string { return "smtp" }
+
+func (p *smtpEmailProvider) Address() string { return fmt.Sprintf("%s:%d", p.host, p.port) }
+
+// Send sends email using SMTP with retry logic
+func (p *smtpEmailProvider) Send(email *models.OutboxEmail) error {
+	toEmail := email.ToEmail
+	fmt.Printf("🔧 [SMTP] Configuration - Host: %s, Port: %d, Username: %s\n",
+		p.host, p.port, p.username)
+
+	// Retry logic for SMTP connection
+	maxRetries := 3
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		fmt.Printf("🔄 [SMTP] Attempt %d/%d to send email to %s\n", attempt, maxRetries, toEmail)
+		err := p.attemptSend(email, nil)
+		if err == nil {
+			fmt.Printf("✅ [SMTP] Email sent successfully to %s on attempt %d\n", toEmail, attempt)
+			return nil // Success
+		}
+
+		if attempt < maxRetries {
+			fmt.Printf("⚠️ [SMTP] Attempt %d failed for %s: %v, retrying in 5s...\n", attempt, toEmail, err)
+			time.Sleep(5 * time.Second)
+		} else {
+			fmt.Printf("❌ [SMTP] All %d attempts failed for %s\n", maxRetries, toEmail)
+			return fmt.Errorf("failed to send email via SMTP after %d attempts: %w", maxRetries, err)
+		}
+	}
+
+	return nil // Should never reach here
+}
+
+// sendTraced sends an email in a single attempt, recording each step of the
+// SMTP session
+func (p *smtpEmailProvider) sendTraced(email *models.OutboxEmail, trace *emailTrace) error {
+	return p.attemptSend(email, trace)
+}
+
+func (p *smtpEmailProvider) attemptSend(email *models.OutboxEmail, trace *emailTrace) error {
+	// Prepare email message
+	message := buildMIMEMessage(fmt.Sprintf("%s <%s>", p.fromName, p.fromEmail), fmt.Sprintf("%s <%s>", email.ToName, email.ToEmail), email)
+
+	// Send email
+	auth := smtp.PlainAuth("", p.username, p.password, p.host)
+
+	// Configure TLS
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: false,
+		ServerName:         p.host,
+	}
+
+	address := p.Address()
+
+	// Create dialer with timeout
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+	}
+
+	// Connect to server with timeout
+	start := time.Now()
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	if err != nil {
+		return trace.record("tls_handshake", start, "", fmt.Errorf("failed to connect to SMTP server %s: %w", address, err))
+	}
+	state := conn.ConnectionState()
+	trace.record("tls_handshake", start, fmt.Sprintf("%s, %s, %s", address, tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite)), nil)
+
+	start = time.Now()
+	client, err := smtp.NewClient(conn, p.host)
+	if err != nil {
+		conn.Close()
+		return trace.record("greeting", start, "", fmt.Errorf("failed to create SMTP client: %w", err))
+	}
+	defer client.Quit()
+	trace.record("greeting", start, "", nil)
+
+	if trace != nil {
+		start = time.Now()
+		ok, mechanisms := client.Extension("AUTH")
+		detail := "AUTH not advertised"
+		if ok {
+			detail = "AUTH " + mechanisms
+		}
+		trace.record("ehlo", start, detail, nil)
+	}
+
+	// Authenticate
+	start = time.Now()
+	if err := client.Auth(auth); err != nil {
+		return trace.record("auth", start, p.username, fmt.Errorf("failed to authenticate with SMTP server: %w", err))
+	}
+	trace.record("auth", start, p.username, nil)
+
+	// Set sender and recipient
+	start = time.Now()
+	if err := client.Mail(p.fromEmail); err != nil {
+		return trace.record("mail_from", start, p.fromEmail, fmt.Errorf("failed to set sender: %w", err))
+	}
+	trace.record("mail_from", start, p.fromEmail, nil)
+
+	start = time.Now()
+	if err := client.Rcpt(email.ToEmail); err != nil {
+		return trace.record("rcpt_to", start, email.ToEmail, fmt.Errorf("failed to set recipient: %w", err))
+	}
+	trace.record("rcpt_to", start, email.ToEmail, nil)
+
+	// Send message
+	start = time.Now()
+	writer, err := client.Data()
+	if err != nil {
+		return trace.record("data", start, "", fmt.Errorf("failed to get data writer: %w", err))
+	}
+
+	_, err = writer.Write([]byte(message))
+	if err != nil {
+		return trace.record("data", start, "", fmt.Errorf("failed to write message: %w", err))
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return trace.record("data", start, "", fmt.Errorf("failed to close writer: %w", err))
+	}
+	trace.record("data", start, fmt.Sprintf("%d bytes", len(message)), nil)
+
+	return nil
+}
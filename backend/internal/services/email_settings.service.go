@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// emailSettingsTTL bounds how long the email event settings are cached, so
+// changes made through another instance are picked up quickly
+const emailSettingsTTL = time.Minute
+
+// EmailSettingsService handles which system events send emails. The
+// organization settings turn an event off for everyone, the notification
+// preferences of a user turn it off for them. Both are checked by the
+// EmailService before sending and by the digest builder before queuing.
+type EmailSettingsService struct {
+	collection          *mongo.Collection
+	userCollection      *mongo.Collection
+	notificationService *NotificationService
+
+	mu       sync.RWMutex
+	current  *models.EmailEventSettings
+	loadedAt time.Time
+}
+
+// NewEmailSettingsService creates the email event settings and makes
+// emailService enforce them
+func NewEmailSettingsService(db *DatabaseService, emailService *EmailService, notificationService *NotificationService) *EmailSettingsService {
+	service := &EmailSettingsService{
+		collection:          db.Collection("settings"),
+		userCollection:      db.Collection("users"),
+		notificationService: notificationService,
+	}
+	emailService.eventSettings = service
+	return service
+}
+
+// Get returns the organization settings, every event sending emails when
+// none were saved. It never fails so that emails are not lost to a lookup
+// error.
+func (s *EmailSettingsService) Get(ctx context.Context) *models.EmailEventSettings {
+	s.mu.RLock()
+	if s.current != nil && time.Since(s.loadedAt) < emailSettingsTTL {
+		current := s.current
+		s.mu.RUnlock()
+		return current
+	}
+	s.mu.RUnlock()
+
+	settings := models.DefaultEmailEventSettings()
+	err := s.collection.FindOne(ctx, bson.M{"_id": models.EmailEventSettingsID}).Decode(settings)
+	if err != nil && err != mongo.ErrNoDocuments {
+		fmt.Printf("Warning: Failed to load email settings, using defaults: %v\n", err)
+		return models.DefaultEmailEventSettings()
+	}
+	for _, event := range models.SystemEmailEvents {
+		if _, ok := settings.Events[event]; !ok {
+			settings.Events[event] = true
+		}
+	}
+
+	s.mu.Lock()
+	s.current = settings
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	return settings
+}
+
+// Update turns the emails of the given events on or off for the organization
+func (s *EmailSettingsService) Update(ctx context.Context, req *models.UpdateEmailEventSettingsRequest, updatedBy primitive.ObjectID) (*models.EmailEventSettings, error) {
+	set := bson.M{
+		"updated_by": updatedBy,
+		"updated_at": time.Now(),
+	}
+	for event, enabled := range req.Events {
+		if !models.IsSystemEmailEvent(event) {
+			return nil, models.ErrInvalidEmailEvent
+		}
+		set["events."+string(event)] = enabled
+	}
+
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": models.EmailEventSettingsID},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetUpsert(true),
+	).Err()
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to update email settings: %w", err)
+	}
+
+	s.mu.Lock()
+	s.current = nil
+	s.mu.Unlock()
+
+	return s.Get(ctx), nil
+}
+
+// allowsEmail tells whether an email of a kind may be sent to an address.
+// The kinds tied to no event, such as one-time codes, are always sent, and
+// the addresses of no user only follow the organization settings.
+func (s *EmailSettingsService) allowsEmail(ctx context.Context, kind, toEmail string) bool {
+	event, ok := models.EmailKindEvents[kind]
+	if !ok {
+		return true
+	}
+	if !s.Get(ctx).Enabled(event) {
+		return false
+	}
+
+	var user struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err := s.userCollection.FindOne(ctx, bson.M{"email": toEmail},
+		options.FindOne().SetProjection(bson.M{"_id": 1})).Decode(&user)
+	if err != nil {
+		return true
+	}
+	prefs, err := s.notificationService.GetUserPreferences(ctx, user.ID)
+	if err != nil {
+		return true
+	}
+	return prefs.EmailEventEnabled(event)
+}
+
+// allowsNotification tells whether a notification with an action may be
+// emailed to a user with the given preferences
+func (s *EmailSettingsService) allowsNotification(ctx context.Context, action string, prefs *models.NotificationPreferences) bool {
+	event, ok := models.NotificationActionEvents[action]
+	if !ok {
+		return true
+	}
+	return s.Get(ctx).Enabled(event) && prefs.EmailEventEnabled(event)
+}
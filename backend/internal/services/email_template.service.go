@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/i18n"
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// emailTemplateRefreshInterval is how long the edited templates are served
+// from memory before being read again, so that the edits made through
+// another instance apply
+const emailTemplateRefreshInterval = time.Minute
+
+// EmailTemplateService stores the email templates edited by the admins in
+// the email_templates collection, one per kind and language, and their
+// history in email_template_versions. Kinds without an edited template in a
+// language use the built-in one.
+type EmailTemplateService struct {
+	collection        *mongo.Collection
+	versionCollection *mongo.Collection
+	emailService      *EmailService
+
+	mu       sync.RWMutex
+	cache    map[string]*models.CustomEmailTemplate // Edited templates by emailTemplateKey
+	loadedAt time.Time
+}
+
+// NewEmailTemplateService creates the template store and makes emailService
+// send the edited templates
+func NewEmailTemplateService(db *DatabaseService, emailService *EmailService) *EmailTemplateService {
+	service := &EmailTemplateService{
+		collection:        db.Collection("email_templates"),
+		versionCollection: db.Collection("email_template_versions"),
+		emailService:      emailService,
+	}
+	emailService.customTemplates = service
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "kind", Value: 1}, {Key: "language", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create email template indexes: %v\n", err)
+	}
+	if _, err := service.versionCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "kind", Value: 1}, {Key: "language", Value: 1}, {Key: "version", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create email template version indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// List returns the template in use for every editable email kind in every language
+func (s *EmailTemplateService) List(ctx context.Context) ([]models.EmailTemplateSummary, error) {
+	custom, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]models.EmailTemplateSummary, 0, len(models.EmailTemplateKinds)*len(i18n.SupportedLanguages))
+	for _, kind := range models.EmailTemplateKinds {
+		for _, lang := range i18n.SupportedLanguages {
+			summaries = append(summaries, emailTemplateSummary(kind, lang, custom[emailTemplateKey(kind, lang)]))
+		}
+	}
+	return summaries, nil
+}
+
+// Get returns the template in use for an email kind in a language, edited or built-in
+func (s *EmailTemplateService) Get(ctx context.Context, kind, lang string) (*models.EmailTemplateDetail, error) {
+	builtin, ok := s.emailService.builtinTemplate(kind, lang)
+	if !ok || !i18n.IsSupported(lang) {
+		return nil, errors.New("email template not found")
+	}
+	custom, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := custom[emailTemplateKey(kind, lang)]
+
+	detail := &models.EmailTemplateDetail{
+		EmailTemplateSummary: emailTemplateSummary(kind, lang, tmpl),
+		Subject:              builtin.Subject,
+		HTMLBody:             builtin.HTMLBody,
+		TextBody:             builtin.TextBody,
+		Variables:            emailTemplateVariables(),
+	}
+	if tmpl != nil {
+		detail.Subject, detail.HTMLBody, detail.TextBody = tmpl.Subject, tmpl.HTMLBody, tmpl.TextBody
+	}
+	return detail, nil
+}
+
+// Save stores a new version of the template of an email kind in a language
+// after checking it renders with sample data, and makes it the template in use
+func (s *EmailTemplateService) Save(ctx context.Context, kind, lang string, req *models.SaveEmailTemplateRequest, userID primitive.ObjectID) (*models.CustomEmailTemplate, error) {
+	if !models.IsEmailTemplateKind(kind) || !i18n.IsSupported(lang) {
+		return nil, errors.New("email template not found")
+	}
+	if _, err := s.emailService.renderSample(EmailTemplate{Subject: req.Subject, HTMLBody: req.HTMLBody, TextBody: req.TextBody}, lang); err != nil {
+		return nil, fmt.Errorf("invalid email template: %w", err)
+	}
+
+	var latest models.EmailTemplateVersion
+	err := s.versionCollection.FindOne(ctx, bson.M{"kind": kind, "language": lang},
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})).Decode(&latest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to find email template versions: %w", err)
+	}
+
+	now := time.Now()
+	version := &models.EmailTemplateVersion{
+		ID:         primitive.NewObjectID(),
+		Kind:       kind,
+		Language:   lang,
+		Version:    latest.Version + 1,
+		Subject:    req.Subject,
+		HTMLBody:   req.HTMLBody,
+		TextBody:   req.TextBody,
+		ChangeNote: req.ChangeNote,
+		CreatedBy:  userID,
+		CreatedAt:  now,
+	}
+	if _, err := s.versionCollection.InsertOne(ctx, version); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.New("email template was modified concurrently, please retry")
+		}
+		return nil, fmt.Errorf("failed to save email template version: %w", err)
+	}
+
+	tmpl := &models.CustomEmailTemplate{
+		Kind:      kind,
+		Language:  lang,
+		Subject:   req.Subject,
+		HTMLBody:  req.HTMLBody,
+		TextBody:  req.TextBody,
+		Version:   version.Version,
+		UpdatedBy: userID,
+		UpdatedAt: now,
+	}
+	if err := s.collection.FindOneAndUpdate(ctx, bson.M{"kind": kind, "language": lang},
+		bson.M{"$set": tmpl},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(tmpl); err != nil {
+		return nil, fmt.Errorf("failed to save email template: %w", err)
+	}
+
+	s.invalidate()
+	return tmpl, nil
+}
+
+// Reset removes the edited template of an email kind in a language, which
+// uses its built-in template again. The versions are kept and can be restored.
+func (s *EmailTemplateService) Reset(ctx context.Context, kind, lang string) error {
+	if !models.IsEmailTemplateKind(kind) || !i18n.IsSupported(lang) {
+		return errors.New("email template not found")
+	}
+
+	result, err := s.collection.DeleteOne(ctx, bson.M{"kind": kind, "language": lang})
+	if err != nil {
+		return fmt.Errorf("failed to reset email template: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("email template is not customized")
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// Versions returns the saved versions of the template of an email kind in a
+// language, newest first
+func (s *EmailTemplateService) Versions(ctx context.Context, kind, lang string) ([]*models.EmailTemplateVersion, error) {
+	if !models.IsEmailTemplateKind(kind) || !i18n.IsSupported(lang) {
+		return nil, errors.New("email template not found")
+	}
+
+	cursor, err := s.versionCollection.Find(ctx, bson.M{"kind": kind, "language": lang},
+		options.Find().SetSort(bson.D{{Key: "version", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email template versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	versions := make([]*models.EmailTemplateVersion, 0)
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode email template versions: %w", err)
+	}
+	return versions, nil
+}
+
+// Restore saves a former version of the template of an email kind in a
+// language as its new version
+func (s *EmailTemplateService) Restore(ctx context.Context, kind, lang string, version int, userID primitive.ObjectID) (*models.CustomEmailTemplate, error) {
+	if !models.IsEmailTemplateKind(kind) || !i18n.IsSupported(lang) {
+		return nil, errors.New("email template not found")
+	}
+
+	var former models.EmailTemplateVersion
+	err := s.versionCollection.FindOne(ctx, bson.M{"kind": kind, "language": lang, "version": version}).Decode(&former)
+	if err == mongo.ErrNoDocuments {
+		return nil, errors.New("email template version not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email template version: %w", err)
+	}
+
+	return s.Save(ctx, kind, lang, &models.SaveEmailTemplateRequest{
+		Subject:    former.Subject,
+		HTMLBody:   former.HTMLBody,
+		TextBody:   former.TextBody,
+		ChangeNote: fmt.Sprintf("Restored version %d", version),
+	}, userID)
+}
+
+// Preview renders the template of an email kind in a language with sample
+// data, the fields of the request replacing those of the template in use
+func (s *EmailTemplateService) Preview(ctx context.Context, kind, lang string, req *models.PreviewEmailTemplateRequest) (*models.EmailTemplatePreview, error) {
+	current, err := s.Get(ctx, kind, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	emailTemplate := EmailTemplate{Subject: current.Subject, HTMLBody: current.HTMLBody, TextBody: current.TextBody}
+	if req.Subject != nil {
+		emailTemplate.Subject = *req.Subject
+	}
+	if req.HTMLBody != nil {
+		emailTemplate.HTMLBody = *req.HTMLBody
+	}
+	if req.TextBody != nil {
+		emailTemplate.TextBody = *req.TextBody
+	}
+
+	preview, err := s.emailService.renderSample(emailTemplate, lang)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email template: %w", err)
+	}
+	return preview, nil
+}
+
+// resolve returns the edited template of an email kind in a language, nil
+// when the built-in template applies. A store that cannot be read falls back
+// to the templates loaded last.
+func (s *EmailTemplateService) resolve(kind, lang string) *models.CustomEmailTemplate {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	custom, err := s.load(ctx)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load email templates: %v\n", err)
+	}
+	return custom[emailTemplateKey(kind, lang)]
+}
+
+// load returns the edited templates by emailTemplateKey, read again from the database
+// once emailTemplateRefreshInterval has passed
+func (s *EmailTemplateService) load(ctx context.Context) (map[string]*models.CustomEmailTemplate, error) {
+	s.mu.RLock()
+	cache, loadedAt := s.cache, s.loadedAt
+	s.mu.RUnlock()
+	if cache != nil && time.Since(loadedAt) < emailTemplateRefreshInterval {
+		return cache, nil
+	}
+
+	cursor, err := s.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return cache, fmt.Errorf("failed to find email templates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var templates []*models.CustomEmailTemplate
+	if err = cursor.All(ctx, &templates); err != nil {
+		return cache, fmt.Errorf("failed to decode email templates: %w", err)
+	}
+
+	loaded := make(map[string]*models.CustomEmailTemplate, len(templates))
+	for _, tmpl := range templates {
+		loaded[emailTemplateKey(tmpl.Kind, tmpl.Language)] = tmpl
+	}
+
+	s.mu.Lock()
+	s.cache, s.loadedAt = loaded, time.Now()
+	s.mu.Unlock()
+	return loaded, nil
+}
+
+// invalidate makes the next lookup read the edited templates again
+func (s *EmailTemplateService) invalidate() {
+	s.mu.Lock()
+	s.cache = nil
+	s.mu.Unlock()
+}
+
+// emailTemplateKey identifies the edited template of a kind in a language
+func emailTemplateKey(kind, lang string) string {
+	return kind + ":" + lang
+}
+
+// emailTemplateSummary describes the template of a kind in a language, tmpl
+// being nil when the built-in template is used
+func emailTemplateSummary(kind, lang string, tmpl *models.CustomEmailTemplate) models.EmailTemplateSummary {
+	summary := models.EmailTemplateSummary{Kind: kind, Language: lang}
+	if tmpl != nil {
+		updatedAt := tmpl.UpdatedAt
+		summary.Customized = true
+		summary.Version = tmpl.Version
+		summary.UpdatedAt = &updatedAt
+	}
+	return summary
+}
+
+// emailTemplateVariables returns the fields of the email data the templates
+// can use, such as {{.UserName}}
+func emailTemplateVariables() []string {
+	fields := reflect.VisibleFields(reflect.TypeOf(EmailData{}))
+	variables := make([]string, 0, len(fields))
+	for _, field := range fields {
+		variables = append(variables, field.Name)
+	}
+	return variables
+}
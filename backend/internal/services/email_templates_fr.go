@@ -0,0 +1,958 @@
+package services
+
+import "fmt"
+
+// French variants of the built-in email templates, sent to the users whose
+// language is French
+
+func (e *EmailService) getRegistrationPendingTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Inscription reçue - En attente de validation",
+		HTMLBody: `
+<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Inscription reçue - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #f39c12; text-align: center;">Inscription reçue</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p>Merci pour votre inscription sur {{.AppName}} ! Nous avons bien reçu votre demande, elle est en cours d'examen par nos administrateurs.</p>
+
+        <div style="background-color: #fff3cd; border: 1px solid #ffeaa7; color: #856404; padding: 12px; border-radius: 4px; margin: 20px 0;">
+            <strong>⏳ Statut :</strong> Votre compte est en attente de validation
+        </div>
+
+        <p><strong>Et ensuite ?</strong></p>
+        <ul>
+            <li>Nos administrateurs vont examiner les informations de votre inscription</li>
+            <li>Vous recevrez un email dès que votre compte sera validé ou si des informations complémentaires sont nécessaires</li>
+            <li>Une fois validé, vous pourrez utiliser {{.AppName}} avec une connexion par code à usage unique</li>
+        </ul>
+
+        <p><strong>Détails de l'inscription :</strong></p>
+        <ul>
+            <li>Email : {{.UserEmail}}</li>
+            <li>Nom : {{.UserName}}</li>
+            <li>Envoyée : à l'instant</li>
+        </ul>
+
+        <p>Pour toute question en attendant la validation, contactez notre équipe support à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}}. Si vous ne vous êtes pas inscrit sur {{.AppName}}, ignorez cet email.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Inscription reçue - {{.AppName}}
+
+Bonjour {{.UserName}},
+
+Merci pour votre inscription sur {{.AppName}} ! Nous avons bien reçu votre demande, elle est en cours d'examen par nos administrateurs.
+
+⏳ Statut : Votre compte est en attente de validation
+
+Et ensuite ?
+• Nos administrateurs vont examiner les informations de votre inscription
+• Vous recevrez un email dès que votre compte sera validé ou si des informations complémentaires sont nécessaires
+• Une fois validé, vous pourrez utiliser {{.AppName}} avec une connexion par code à usage unique
+
+Détails de l'inscription :
+• Email : {{.UserEmail}}
+• Nom : {{.UserName}}
+• Envoyée : à l'instant
+
+Pour toute question en attendant la validation, contactez notre équipe support à {{.SupportEmail}}.
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}}. Si vous ne vous êtes pas inscrit sur {{.AppName}}, ignorez cet email.`,
+	}
+}
+
+func (e *EmailService) getAccountApprovedTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Compte validé - Bienvenue sur {{.AppName}} !",
+		HTMLBody: `
+<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Compte validé - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #27ae60; text-align: center;">🎉 Compte validé !</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p>Bonne nouvelle ! Votre compte {{.AppName}} a été validé par nos administrateurs. Vous pouvez dès maintenant accéder à la plateforme et gérer vos processus.</p>
+
+        <div style="background-color: #d4edda; border: 1px solid #c3e6cb; color: #155724; padding: 12px; border-radius: 4px; margin: 20px 0;">
+            <strong>✅ Statut :</strong> Votre compte est actif et prêt à l'emploi !
+        </div>
+
+        <p><strong>Pour commencer :</strong></p>
+        <ul>
+            <li>Rendez-vous sur la page de connexion et saisissez votre adresse email</li>
+            <li>Vous recevrez un code de connexion sécurisé par email</li>
+            <li>Saisissez ce code pour accéder à votre compte</li>
+            <li>Découvrez la plateforme et créez vos premiers processus</li>
+        </ul>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.AppURL}}/login" style="background-color: #27ae60; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Accéder à mon compte</a>
+        </div>
+
+        <p><strong>Avec {{.AppName}}, vous pouvez :</strong></p>
+        <ul>
+            <li>Créer et gérer vos processus de manière collaborative</li>
+            <li>Gérer des formulaires en plusieurs étapes</li>
+            <li>Signer électroniquement et exporter en PDF</li>
+            <li>Suivre la performance mensuelle grâce aux rapports d'incidents</li>
+        </ul>
+
+        <p>Besoin d'aide pour démarrer ? Notre équipe support est disponible à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
+
+        <p>Bienvenue à bord !<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Compte validé - {{.AppName}}
+
+Bonjour {{.UserName}},
+
+Bonne nouvelle ! Votre compte {{.AppName}} a été validé par nos administrateurs. Vous pouvez dès maintenant accéder à la plateforme et gérer vos processus.
+
+✅ Statut : Votre compte est actif et prêt à l'emploi !
+
+Pour commencer :
+• Rendez-vous sur la page de connexion et saisissez votre adresse email
+• Vous recevrez un code de connexion sécurisé par email
+• Saisissez ce code pour accéder à votre compte
+• Découvrez la plateforme et créez vos premiers processus
+
+Accéder à mon compte : {{.AppURL}}/login
+
+Avec {{.AppName}}, vous pouvez :
+• Créer et gérer vos processus de manière collaborative
+• Gérer des formulaires en plusieurs étapes
+• Signer électroniquement et exporter en PDF
+• Suivre la performance mensuelle grâce aux rapports d'incidents
+
+Besoin d'aide pour démarrer ? Notre équipe support est disponible à {{.SupportEmail}}.
+
+Bienvenue à bord !
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à {{.SupportEmail}}.`,
+	}
+}
+
+func (e *EmailService) getAccountRejectedTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Suite de votre inscription - {{.AppName}}",
+		HTMLBody: `
+<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Suite de votre inscription - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #e74c3c; text-align: center;">Suite de votre inscription</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p>Merci pour l'intérêt que vous portez à {{.AppName}}. Après examen de votre inscription, nous ne sommes pas en mesure de valider votre compte pour le moment.</p>
+
+        <div style="background-color: #f8d7da; border: 1px solid #f5c6cb; color: #721c24; padding: 12px; border-radius: 4px; margin: 20px 0;">
+            <strong>❌ Statut :</strong> Inscription non validée
+        </div>
+
+        {{if .RejectionReason}}
+        <p><strong>Motif :</strong></p>
+        <p style="background-color: #f8f9fa; padding: 10px; border-left: 4px solid #e74c3c; margin: 15px 0;">{{.RejectionReason}}</p>
+        {{end}}
+
+        <p><strong>Et maintenant ?</strong></p>
+        <ul>
+            <li>Si vous pensez qu'il s'agit d'une erreur, contactez notre équipe support</li>
+            <li>Vous pourrez vous inscrire de nouveau si votre situation évolue</li>
+            <li>Notre équipe support peut vous indiquer les conditions de validation</li>
+        </ul>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="mailto:{{.SupportEmail}}" style="background-color: {{.PrimaryColor}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Contacter le support</a>
+        </div>
+
+        <p>Nous vous remercions de votre compréhension et de votre intérêt pour {{.AppName}}.</p>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Suite de votre inscription - {{.AppName}}
+
+Bonjour {{.UserName}},
+
+Merci pour l'intérêt que vous portez à {{.AppName}}. Après examen de votre inscription, nous ne sommes pas en mesure de valider votre compte pour le moment.
+
+❌ Statut : Inscription non validée
+
+{{if .RejectionReason}}Motif : {{.RejectionReason}}{{end}}
+
+Et maintenant ?
+• Si vous pensez qu'il s'agit d'une erreur, contactez notre équipe support
+• Vous pourrez vous inscrire de nouveau si votre situation évolue
+• Notre équipe support peut vous indiquer les conditions de validation
+
+Contacter le support : {{.SupportEmail}}
+
+Nous vous remercions de votre compréhension et de votre intérêt pour {{.AppName}}.
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à {{.SupportEmail}}.`,
+	}
+}
+
+func (e *EmailService) getWelcomeTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Bienvenue sur {{.AppName}} !",
+		HTMLBody: `
+<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Bienvenue sur {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: {{.SecondaryColor}}; text-align: center;">Bienvenue sur {{.AppName}} !</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p>Bienvenue sur {{.AppName}} ! Nous sommes ravis de vous compter parmi nous. Notre plateforme aide les entreprises de télécommunications à numériser et gérer efficacement leur documentation procédurale.</p>
+
+        <p>Avec {{.AppName}}, vous pouvez :</p>
+        <ul>
+            <li>Créer et gérer vos processus de manière collaborative</li>
+            <li>Gérer des formulaires en plusieurs étapes</li>
+            <li>Signer électroniquement et exporter en PDF</li>
+            <li>Suivre la performance mensuelle grâce aux rapports d'incidents</li>
+        </ul>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.AppURL}}" style="background-color: {{.PrimaryColor}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Commencer</a>
+        </div>
+
+        <p>Pour toute question ou demande d'aide, n'hésitez pas à contacter notre équipe support à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}}. Si vous n'avez pas créé de compte chez nous, ignorez cet email.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Bienvenue sur {{.AppName}} !
+
+Bonjour {{.UserName}},
+
+Bienvenue sur {{.AppName}} ! Nous sommes ravis de vous compter parmi nous. Notre plateforme aide les entreprises de télécommunications à numériser et gérer efficacement leur documentation procédurale.
+
+Avec {{.AppName}}, vous pouvez :
+• Créer et gérer vos processus de manière collaborative
+• Gérer des formulaires en plusieurs étapes
+• Signer électroniquement et exporter en PDF
+• Suivre la performance mensuelle grâce aux rapports d'incidents
+
+Commencer : {{.AppURL}}
+
+Pour toute question ou demande d'aide, n'hésitez pas à contacter notre équipe support à {{.SupportEmail}}.
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}}. Si vous n'avez pas créé de compte chez nous, ignorez cet email.`,
+	}
+}
+
+func (e *EmailService) getVerificationTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Vérifiez votre adresse email",
+		HTMLBody: `
+<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Vérifiez votre adresse email - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: {{.SecondaryColor}}; text-align: center;">Vérifiez votre adresse email</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p>Merci pour votre inscription sur {{.AppName}} ! Pour finaliser votre inscription et sécuriser votre compte, vérifiez votre adresse email en cliquant sur le bouton ci-dessous.</p>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.VerificationURL}}" style="background-color: #27ae60; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Vérifier mon adresse email</a>
+        </div>
+
+        <p>Si le bouton ne fonctionne pas, copiez et collez ce lien dans votre navigateur :</p>
+        <p style="word-break: break-all; background-color: #f8f9fa; padding: 10px; border-left: 4px solid #27ae60;">{{.VerificationURL}}</p>
+
+        <p><strong>Important :</strong> pour des raisons de sécurité, ce lien de vérification expire dans 24 heures.</p>
+
+        <p>Si vous n'avez pas créé de compte sur {{.AppName}}, ignorez cet email.</p>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Vérifiez votre adresse email
+
+Bonjour {{.UserName}},
+
+Merci pour votre inscription sur {{.AppName}} ! Pour finaliser votre inscription et sécuriser votre compte, vérifiez votre adresse email en ouvrant ce lien :
+
+{{.VerificationURL}}
+
+Important : pour des raisons de sécurité, ce lien de vérification expire dans 24 heures.
+
+Si vous n'avez pas créé de compte sur {{.AppName}}, ignorez cet email.
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à {{.SupportEmail}}.`,
+	}
+}
+
+func (e *EmailService) getOTPTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Votre code de connexion à {{.AppName}}",
+		HTMLBody: `
+<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Votre code de connexion - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: {{.SecondaryColor}}; text-align: center;">Votre code de connexion</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p>Vous essayez de vous connecter à votre compte {{.AppName}}. Utilisez le code de vérification ci-dessous :</p>
+
+        <div style="text-align: center; margin: 30px 0; background-color: #ffffff; padding: 20px; border-radius: 8px; border: 2px solid {{.PrimaryColor}};">
+            <h2 style="color: {{.SecondaryColor}}; font-size: 32px; letter-spacing: 8px; margin: 0; font-family: 'Courier New', monospace;">{{.OTP}}</h2>
+        </div>
+
+        <div style="background-color: #fff3cd; border: 1px solid #ffeaa7; color: #856404; padding: 12px; border-radius: 4px; margin: 20px 0;">
+            <strong>⚠️ Important :</strong> pour des raisons de sécurité, ce code expire dans {{.OTPExpiry}}.
+        </div>
+
+        <p><strong>Consignes de sécurité :</strong></p>
+        <ul>
+            <li>Ne communiquez jamais ce code à qui que ce soit</li>
+            <li>{{.AppName}} ne vous demandera jamais votre code par téléphone ou par email</li>
+            <li>Ce code n'est utilisable qu'une seule fois</li>
+            <li>Si vous n'avez pas demandé ce code, ignorez cet email</li>
+        </ul>
+
+        <p>En cas de difficulté pour vous connecter, vous pouvez demander un nouveau code ou contacter notre équipe support.</p>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.AppURL}}" style="background-color: {{.PrimaryColor}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Aller sur {{.AppName}}</a>
+        </div>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Votre code de connexion à {{.AppName}}
+
+Bonjour {{.UserName}},
+
+Vous essayez de vous connecter à votre compte {{.AppName}}. Utilisez le code de vérification ci-dessous :
+
+**{{.OTP}}**
+
+⚠️ Important : pour des raisons de sécurité, ce code expire dans {{.OTPExpiry}}.
+
+Consignes de sécurité :
+• Ne communiquez jamais ce code à qui que ce soit
+• {{.AppName}} ne vous demandera jamais votre code par téléphone ou par email
+• Ce code n'est utilisable qu'une seule fois
+• Si vous n'avez pas demandé ce code, ignorez cet email
+
+En cas de difficulté pour vous connecter, vous pouvez demander un nouveau code ou contacter notre équipe support.
+
+Aller sur {{.AppName}} : {{.AppURL}}
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à {{.SupportEmail}}.`,
+	}
+}
+
+// getCustomEmailTemplateFR creates a French template for custom emails
+func (e *EmailService) getCustomEmailTemplateFR(subject, body string) EmailTemplate {
+	return EmailTemplate{
+		Subject: subject,
+		HTMLBody: fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>%s</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: {{.SecondaryColor}}; text-align: center;">{{.AppName}}</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        %s
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.AppURL}}" style="background-color: {{.PrimaryColor}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Aller sur {{.AppName}}</a>
+        </div>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`, subject, body),
+		TextBody: fmt.Sprintf(`%s
+
+Bonjour {{.UserName}},
+
+%s
+
+Aller sur {{.AppName}} : {{.AppURL}}
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à {{.SupportEmail}}.`, subject, body),
+	}
+}
+
+func (e *EmailService) getRegistrationOTPTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Finalisez votre inscription - Code de vérification",
+		HTMLBody: `<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Code de vérification d'inscription</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f8f9fa; padding: 20px; border-radius: 10px;">
+        <div style="text-align: center; margin-bottom: 30px;">
+            <h1 style="color: {{.SecondaryColor}}; margin: 0;">{{.AppName}}</h1>
+            <h2 style="color: #27ae60; margin: 10px 0;">Finalisez votre inscription</h2>
+        </div>
+
+        <p>Bonjour,</p>
+
+        <p>Merci d'avoir commencé votre inscription sur {{.AppName}} ! Pour la finaliser, utilisez le code de vérification suivant :</p>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <div style="background-color: #27ae60; color: white; padding: 20px; border-radius: 10px; font-size: 32px; font-weight: bold; letter-spacing: 5px; display: inline-block;">
+                {{.OTP}}
+            </div>
+        </div>
+
+        <div style="background-color: #fff3cd; border: 1px solid #ffeaa7; color: #856404; padding: 12px; border-radius: 4px; margin: 20px 0;">
+            <strong>⚠️ Important :</strong> pour des raisons de sécurité, ce code de vérification expire dans {{.OTPExpiry}}.
+        </div>
+
+        <p><strong>Prochaines étapes :</strong></p>
+        <ol>
+            <li>Saisissez ce code de vérification sur la page d'inscription</li>
+            <li>Complétez les informations de votre profil</li>
+            <li>Attendez la validation de votre compte par un administrateur</li>
+        </ol>
+
+        <p><strong>Consignes de sécurité :</strong></p>
+        <ul>
+            <li>Ne communiquez jamais ce code à qui que ce soit</li>
+            <li>{{.AppName}} ne vous demandera jamais votre code par téléphone ou par email</li>
+            <li>Ce code n'est utilisable qu'une seule fois</li>
+            <li>Si vous n'êtes pas à l'origine de cette inscription, ignorez cet email</li>
+        </ul>
+
+        <p>En cas de difficulté lors de l'inscription, contactez notre équipe support.</p>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.AppURL}}" style="background-color: #27ae60; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Poursuivre l'inscription</a>
+        </div>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Finalisez votre inscription - Code de vérification
+
+Bonjour,
+
+Merci d'avoir commencé votre inscription sur {{.AppName}} ! Pour la finaliser, utilisez le code de vérification suivant :
+
+CODE DE VÉRIFICATION : {{.OTP}}
+
+IMPORTANT : pour des raisons de sécurité, ce code de vérification expire dans {{.OTPExpiry}}.
+
+Prochaines étapes :
+1. Saisissez ce code de vérification sur la page d'inscription
+2. Complétez les informations de votre profil
+3. Attendez la validation de votre compte par un administrateur
+
+Consignes de sécurité :
+- Ne communiquez jamais ce code à qui que ce soit
+- {{.AppName}} ne vous demandera jamais votre code par téléphone ou par email
+- Ce code n'est utilisable qu'une seule fois
+- Si vous n'êtes pas à l'origine de cette inscription, ignorez cet email
+
+En cas de difficulté lors de l'inscription, contactez notre équipe support.
+
+Poursuivre l'inscription : {{.AppURL}}
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}}. Pour toute assistance, contactez-nous à {{.SupportEmail}}.`,
+	}
+}
+
+func (e *EmailService) getInvitationTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Vous êtes invité à collaborer sur un document",
+		HTMLBody: `<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Invitation à collaborer sur un document - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: {{.PrimaryColor}}; text-align: center;">📄 Invitation à collaborer sur un document</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p><strong>{{.InviterName}}</strong> vous invite à collaborer sur un document dans {{.AppName}}.</p>
+
+        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid {{.PrimaryColor}}; margin: 20px 0;">
+            <p style="margin: 5px 0;"><strong>Document :</strong> {{.DocumentTitle}}</p>
+            <p style="margin: 5px 0;"><strong>Référence :</strong> {{.DocumentRef}}</p>
+            <p style="margin: 5px 0;"><strong>Rôle :</strong> {{.TeamName}}</p>
+        </div>
+
+        <p><strong>Ce que cela signifie :</strong></p>
+        <ul>
+            <li>Vous pourrez collaborer sur le document au sein de l'équipe {{.TeamName}}</li>
+            <li>Vous pourrez relire, modifier et contribuer au document selon votre rôle</li>
+            <li>Vous serez notifié des mises à jour du document</li>
+        </ul>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.InvitationURL}}" style="background-color: #27ae60; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Accepter l'invitation</a>
+        </div>
+
+        <p>Si le bouton ne fonctionne pas, copiez et collez ce lien dans votre navigateur :</p>
+        <p style="word-break: break-all; background-color: #f8f9fa; padding: 10px; border-left: 4px solid #27ae60;">{{.InvitationURL}}</p>
+
+        <div style="background-color: #fff3cd; border: 1px solid #ffeaa7; color: #856404; padding: 12px; border-radius: 4px; margin: 20px 0;">
+            <strong>⏳ Remarque :</strong> pour des raisons de sécurité, cette invitation expire dans 7 jours.
+        </div>
+
+        <p>Si vous ne souhaitez pas collaborer sur ce document, vous pouvez ignorer cet email.</p>
+
+        <p>Pour toute question, contactez notre équipe support à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}}. Si vous n'attendiez pas cette invitation, contactez <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Invitation à collaborer sur un document - {{.AppName}}
+
+Bonjour {{.UserName}},
+
+{{.InviterName}} vous invite à collaborer sur un document dans {{.AppName}}.
+
+Détails du document :
+• Document : {{.DocumentTitle}}
+• Référence : {{.DocumentRef}}
+• Rôle : {{.TeamName}}
+
+Ce que cela signifie :
+• Vous pourrez collaborer sur le document au sein de l'équipe {{.TeamName}}
+• Vous pourrez relire, modifier et contribuer au document selon votre rôle
+• Vous serez notifié des mises à jour du document
+
+Accepter l'invitation : {{.InvitationURL}}
+
+⏳ Remarque : pour des raisons de sécurité, cette invitation expire dans 7 jours.
+
+Si vous ne souhaitez pas collaborer sur ce document, vous pouvez ignorer cet email.
+
+Pour toute question, contactez notre équipe support à {{.SupportEmail}}.
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}}. Si vous n'attendiez pas cette invitation, contactez {{.SupportEmail}}.`,
+	}
+}
+
+func (e *EmailService) getReviewDueTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Revue périodique à effectuer sur un document publié",
+		HTMLBody: `<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Revue périodique à effectuer - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #e67e22; text-align: center;">🔁 Revue périodique à effectuer</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p>Une procédure dont vous êtes l'auteur a atteint sa date de revue périodique et doit être revalidée.</p>
+
+        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid #e67e22; margin: 20px 0;">
+            <p style="margin: 5px 0;"><strong>Document :</strong> {{.DocumentTitle}}</p>
+            <p style="margin: 5px 0;"><strong>Référence :</strong> {{.DocumentRef}}</p>
+            <p style="margin: 5px 0;"><strong>Date de revue :</strong> {{.ReviewDueDate}}</p>
+        </div>
+
+        <p>Vérifiez que la procédure reflète toujours les pratiques en vigueur, puis confirmez la revue ou lancez une révision.</p>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.DocumentURL}}" style="background-color: #e67e22; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Ouvrir le document</a>
+        </div>
+
+        <p>Si le bouton ne fonctionne pas, copiez et collez ce lien dans votre navigateur :</p>
+        <p style="word-break: break-all; background-color: #f8f9fa; padding: 10px; border-left: 4px solid #e67e22;">{{.DocumentURL}}</p>
+
+        <p>Pour toute question, contactez notre équipe support à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}} car vous êtes auteur de ce document.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Revue périodique à effectuer - {{.AppName}}
+
+Bonjour {{.UserName}},
+
+Une procédure dont vous êtes l'auteur a atteint sa date de revue périodique et doit être revalidée.
+
+Détails du document :
+• Document : {{.DocumentTitle}}
+• Référence : {{.DocumentRef}}
+• Date de revue : {{.ReviewDueDate}}
+
+Vérifiez que la procédure reflète toujours les pratiques en vigueur, puis confirmez la revue ou lancez une révision.
+
+Ouvrir le document : {{.DocumentURL}}
+
+Pour toute question, contactez notre équipe support à {{.SupportEmail}}.
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}} car vous êtes auteur de ce document.`,
+	}
+}
+
+func (e *EmailService) getNotificationDigestTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Le résumé de vos notifications",
+		HTMLBody: `<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Résumé des notifications - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #3498db; text-align: center;">📬 Résumé des notifications</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p>Vous avez reçu {{.DigestCount}} notification(s) depuis votre dernier résumé.</p>
+
+        {{range .DigestEntries}}
+        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid #3498db; margin: 10px 0;">
+            <p style="margin: 0 0 5px 0;"><strong>{{.Title}}</strong> <span style="font-size: 12px; color: #666;">{{.Time}}</span></p>
+            <p style="margin: 0;">{{.Body}}</p>
+            {{if .URL}}<p style="margin: 5px 0 0 0;"><a href="{{.URL}}" style="color: #3498db;">Ouvrir</a></p>{{end}}
+        </div>
+        {{end}}
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.NotificationsURL}}" style="background-color: #3498db; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Voir toutes les notifications</a>
+        </div>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}} car vous avez choisi de recevoir vos notifications sous forme de résumé. Vous pouvez modifier ce choix dans vos préférences de notification.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Résumé des notifications - {{.AppName}}
+
+Bonjour {{.UserName}},
+
+Vous avez reçu {{.DigestCount}} notification(s) depuis votre dernier résumé.
+{{range .DigestEntries}}
+• {{.Title}} ({{.Time}})
+  {{.Body}}{{if .URL}}
+  {{.URL}}{{end}}
+{{end}}
+Voir toutes les notifications : {{.NotificationsURL}}
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}} car vous avez choisi de recevoir vos notifications sous forme de résumé. Vous pouvez modifier ce choix dans vos préférences de notification.`,
+	}
+}
+
+func (e *EmailService) getSignatureReminderTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Rappel : un document attend votre signature",
+		HTMLBody: `<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Rappel de signature - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #2980b9; text-align: center;">✍️ Rappel de signature</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p>Un document attend toujours votre signature.</p>
+
+        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid #2980b9; margin: 20px 0;">
+            <p style="margin: 5px 0;"><strong>Document :</strong> {{.DocumentTitle}}</p>
+            <p style="margin: 5px 0;"><strong>Référence :</strong> {{.DocumentRef}}</p>
+            {{if .SignatureDueDate}}<p style="margin: 5px 0;"><strong>À signer avant le :</strong> {{.SignatureDueDate}}</p>{{end}}
+        </div>
+
+        <p>Relisez le document, puis signez-le ou rejetez-le avec vos commentaires.</p>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.DocumentURL}}" style="background-color: #2980b9; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Ouvrir le document</a>
+        </div>
+
+        <p>Si le bouton ne fonctionne pas, copiez et collez ce lien dans votre navigateur :</p>
+        <p style="word-break: break-all; background-color: #f8f9fa; padding: 10px; border-left: 4px solid #2980b9;">{{.DocumentURL}}</p>
+
+        <p>Pour toute question, contactez notre équipe support à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}} car vous êtes contributeur de ce document.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Rappel de signature - {{.AppName}}
+
+Bonjour {{.UserName}},
+
+Un document attend toujours votre signature.
+
+Détails du document :
+• Document : {{.DocumentTitle}}
+• Référence : {{.DocumentRef}}
+{{if .SignatureDueDate}}• À signer avant le : {{.SignatureDueDate}}
+{{end}}
+Relisez le document, puis signez-le ou rejetez-le avec vos commentaires.
+
+Ouvrir le document : {{.DocumentURL}}
+
+Pour toute question, contactez notre équipe support à {{.SupportEmail}}.
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}} car vous êtes contributeur de ce document.`,
+	}
+}
+
+func (e *EmailService) getSignatureRequestTemplateFR() EmailTemplate {
+	return EmailTemplate{
+		Subject: "Un document attend votre signature",
+		HTMLBody: `<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Demande de signature - {{.AppName}}</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
+    {{if .LogoURL}}<div style="text-align: center; margin-bottom: 20px;"><img src="{{.LogoURL}}" alt="{{.CompanyName}}" style="max-height: 60px;"></div>{{end}}
+    <div style="background-color: #f4f4f4; padding: 20px; border-radius: 10px;">
+        <h1 style="color: #2980b9; text-align: center;">✍️ Demande de signature</h1>
+
+        <p>Bonjour {{.UserName}},</p>
+
+        <p>Un document est entré en relecture et attend votre signature.</p>
+
+        <div style="background-color: #ffffff; padding: 15px; border-radius: 8px; border-left: 4px solid #2980b9; margin: 20px 0;">
+            <p style="margin: 5px 0;"><strong>Document :</strong> {{.DocumentTitle}}</p>
+            <p style="margin: 5px 0;"><strong>Référence :</strong> {{.DocumentRef}}</p>
+            {{if .SignatureDueDate}}<p style="margin: 5px 0;"><strong>À signer avant le :</strong> {{.SignatureDueDate}}</p>{{end}}
+        </div>
+
+        <p>Relisez le document, puis signez-le ou rejetez-le avec vos commentaires.{{if .SignatureDueDate}} L'invitation jointe ajoute l'échéance à votre calendrier.{{end}}</p>
+
+        <div style="text-align: center; margin: 30px 0;">
+            <a href="{{.DocumentURL}}" style="background-color: #2980b9; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Ouvrir le document</a>
+        </div>
+
+        <p>Si le bouton ne fonctionne pas, copiez et collez ce lien dans votre navigateur :</p>
+        <p style="word-break: break-all; background-color: #f8f9fa; padding: 10px; border-left: 4px solid #2980b9;">{{.DocumentURL}}</p>
+
+        <p>Pour toute question, contactez notre équipe support à <a href="mailto:{{.SupportEmail}}">{{.SupportEmail}}</a>.</p>
+
+        <p>Cordialement,<br>{{.CompanyName}}</p>
+
+        <hr style="border: none; border-top: 1px solid #ddd; margin: 30px 0;">
+        <p style="font-size: 12px; color: #666; text-align: center;">
+            Cet email a été envoyé à {{.UserEmail}} car vous êtes contributeur de ce document.
+        </p>
+    </div>
+    {{if .FooterText}}<p style="font-size: 12px; color: #999; text-align: center; margin-top: 20px;">{{.FooterText}}</p>{{end}}
+</body>
+</html>`,
+		TextBody: `Demande de signature - {{.AppName}}
+
+Bonjour {{.UserName}},
+
+Un document est entré en relecture et attend votre signature.
+
+Détails du document :
+• Document : {{.DocumentTitle}}
+• Référence : {{.DocumentRef}}
+{{if .SignatureDueDate}}• À signer avant le : {{.SignatureDueDate}}
+{{end}}
+Relisez le document, puis signez-le ou rejetez-le avec vos commentaires.{{if .SignatureDueDate}} L'invitation jointe ajoute l'échéance à votre calendrier.{{end}}
+
+Ouvrir le document : {{.DocumentURL}}
+
+Pour toute question, contactez notre équipe support à {{.SupportEmail}}.
+
+Cordialement,
+{{.CompanyName}}
+
+---
+Cet email a été envoyé à {{.UserEmail}} car vous êtes contributeur de ce document.`,
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// emailRateKeyPrefix prefixes the counters of the emails sent to a recipient
+	emailRateKeyPrefix = "email:rate:"
+	// emailAccountRateKeyPrefix prefixes the counters of the account emails
+	emailAccountRateKeyPrefix = "email:rate:account:"
+	// emailDedupKeyPrefix prefixes the marks of the emails already sent
+	emailDedupKeyPrefix = "email:dedup:"
+	// emailRateWindow is the window of the per-recipient email counters
+	emailRateWindow = time.Hour
+)
+
+// emailAccountKinds are the emails a user needs to sign in and manage their
+// account. They are counted apart so that a burst of document emails cannot
+// lock a user out.
+var emailAccountKinds = map[string]bool{
+	"otp":                  true,
+	"registration_otp":     true,
+	"verification":         true,
+	"welcome":              true,
+	"registration_pending": true,
+	"account_approved":     true,
+	"account_rejected":     true,
+}
+
+// EmailThrottleService caps the emails sent to each recipient and drops the
+// duplicates, such as the emails about a document sent again to every
+// contributor when it is republished. Both are tracked in Redis so they hold
+// across instances, and Redis errors let the emails through.
+type EmailThrottleService struct {
+	redisClient        *redis.Client
+	ratePerHour        int64
+	accountRatePerHour int64
+	dedupWindow        time.Duration
+}
+
+// NewEmailThrottleService creates the throttle and makes emailService apply
+// it. EMAIL_RATE_LIMIT_PER_HOUR sets the emails a recipient can get per hour
+// (default 30, 0 turns the limit off), EMAIL_ACCOUNT_RATE_LIMIT_PER_HOUR the
+// account emails such as the sign-in codes, counted apart (default 10, 0
+// turns the limit off), and EMAIL_DEDUP_WINDOW_HOURS how long an email is not
+// sent again (default 24, 0 turns the check off).
+func NewEmailThrottleService(redisClient *redis.Client, emailService *EmailService) *EmailThrottleService {
+	ratePerHour := int64(30)
+	if v, err := strconv.Atoi(os.Getenv("EMAIL_RATE_LIMIT_PER_HOUR")); err == nil && v >= 0 {
+		ratePerHour = int64(v)
+	}
+	accountRatePerHour := int64(10)
+	if v, err := strconv.Atoi(os.Getenv("EMAIL_ACCOUNT_RATE_LIMIT_PER_HOUR")); err == nil && v >= 0 {
+		accountRatePerHour = int64(v)
+	}
+	dedupHours := 24
+	if v, err := strconv.Atoi(os.Getenv("EMAIL_DEDUP_WINDOW_HOURS")); err == nil && v >= 0 {
+		dedupHours = v
+	}
+
+	service := &EmailThrottleService{
+		redisClient:        redisClient,
+		ratePerHour:        ratePerHour,
+		accountRatePerHour: accountRatePerHour,
+		dedupWindow:        time.Duration(dedupHours) * time.Hour,
+	}
+	emailService.throttle = service
+	return service
+}
+
+// admit tells whether an email may be sent. It returns false for a duplicate
+// of an email sent within the window, and ErrEmailRateLimited when the
+// recipient got too many emails of the same bucket this hour. release must be called when the
+// admitted email could not be sent, so that it is not taken as a duplicate.
+func (s *EmailThrottleService) admit(ctx context.Context, kind, toEmail string, data EmailData) (admitted bool, release func(), err error) {
+	release = func() {}
+	recipient := strings.ToLower(strings.TrimSpace(toEmail))
+
+	if key := s.dedupKey(kind, recipient, data); key != "" {
+		first, err := s.redisClient.SetNX(ctx, key, time.Now().Unix(), s.dedupWindow).Result()
+		if err != nil {
+			fmt.Printf("Warning: Failed to check duplicate email to %s: %v\n", recipient, err)
+		} else if !first {
+			return false, release, nil
+		} else {
+			release = func() {
+				if err := s.redisClient.Del(context.Background(), key).Err(); err != nil {
+					fmt.Printf("Warning: Failed to release duplicate email check of %s: %v\n", recipient, err)
+				}
+			}
+		}
+	}
+
+	key, limit := emailRateKeyPrefix+recipient, s.ratePerHour
+	if emailAccountKinds[kind] {
+		key, limit = emailAccountRateKeyPrefix+recipient, s.accountRatePerHour
+	}
+	if limit > 0 {
+		count, err := s.redisClient.Incr(ctx, key).Result()
+		if err != nil {
+			fmt.Printf("Warning: Failed to count emails sent to %s: %v\n", recipient, err)
+			return true, release, nil
+		}
+		if count == 1 {
+			s.redisClient.Expire(ctx, key, emailRateWindow)
+		}
+		if count > limit {
+			release()
+			return false, func() {}, models.ErrEmailRateLimited
+		}
+	}
+
+	return true, release, nil
+}
+
+// dedupKey identifies an email by its template, document and recipient, empty
+// for the emails about no document which are never duplicates. An email
+// carrying a new link, such as a resent invitation, or a nonce, such as a
+// manual reminder, is not a duplicate either.
+func (s *EmailThrottleService) dedupKey(kind, recipient string, data EmailData) string {
+	if s.dedupWindow <= 0 || data.DocumentRef == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{kind, data.DocumentRef, data.Token, data.DedupNonce, recipient}, "\x00")))
+	return emailDedupKeyPrefix + hex.EncodeToString(sum[:])
+}
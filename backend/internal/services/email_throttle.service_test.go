@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// memoryRedisHook answers the commands used by the email throttle from memory,
+// so that the tests need no Redis server
+type memoryRedisHook struct {
+	values map[string]int64
+}
+
+func (h *memoryRedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *memoryRedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		args := cmd.Args()
+		key, _ := args[1].(string)
+		switch c := cmd.(type) {
+		case *redis.BoolCmd: // SET NX, EXPIRE
+			_, exists := h.values[key]
+			if strings.ToLower(cmd.Name()) == "set" {
+				if !exists {
+					h.values[key] = 1
+				}
+				c.SetVal(!exists)
+			} else {
+				c.SetVal(exists)
+			}
+		case *redis.IntCmd: // INCR, DEL
+			if strings.ToLower(cmd.Name()) == "del" {
+				delete(h.values, key)
+				c.SetVal(1)
+			} else {
+				h.values[key]++
+				c.SetVal(h.values[key])
+			}
+		}
+		return nil
+	}
+}
+
+func (h *memoryRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+// recordingEmailProvider delivers the emails to a slice
+type recordingEmailProvider struct {
+	sent []*models.OutboxEmail
+}
+
+func (p *recordingEmailProvider) Name() string    { return "recording" }
+func (p *recordingEmailProvider) Address() string { return "localhost:0" }
+func (p *recordingEmailProvider) Send(email *models.OutboxEmail) error {
+	p.sent = append(p.sent, email)
+	return nil
+}
+
+func TestManualSignatureReminderIsNotDroppedAsDuplicate(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(&memoryRedisHook{values: map[string]int64{}})
+	defer client.Close()
+
+	provider := &recordingEmailProvider{}
+	emailService := &EmailService{
+		appURL:    "https://app.example.com",
+		providers: []EmailProvider{provider},
+		templates: NewTemplateCache(emailTemplateCacheSize),
+	}
+	NewEmailThrottleService(client, emailService)
+
+	due := time.Now().AddDate(0, 0, 3)
+	remind := func(manual bool) {
+		if err := emailService.SendSignatureReminderEmail("jane.doe@example.com", "Jane Doe", "Purchasing procedure", "PRO-ACH-001", "sample", &due, manual, "en"); err != nil {
+			t.Fatalf("SendSignatureReminderEmail(manual=%v) failed: %v", manual, err)
+		}
+	}
+
+	remind(false)
+	if len(provider.sent) != 1 {
+		t.Fatalf("expected the scheduled reminder to be sent, got %d emails", len(provider.sent))
+	}
+
+	// The next scheduled run within the window is a duplicate
+	remind(false)
+	if len(provider.sent) != 1 {
+		t.Fatalf("expected the repeated scheduled reminder to be dropped, got %d emails", len(provider.sent))
+	}
+
+	// The owner sends a reminder right after the scheduled one
+	remind(true)
+	if len(provider.sent) != 2 {
+		t.Fatalf("expected the manual reminder to be sent, got %d emails", len(provider.sent))
+	}
+	remind(true)
+	if len(provider.sent) != 3 {
+		t.Fatalf("expected a second manual reminder to be sent, got %d emails", len(provider.sent))
+	}
+}
+
+func TestOTPEmailIsSentAfterRecipientRateLimit(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(&memoryRedisHook{values: map[string]int64{}})
+	defer client.Close()
+
+	provider := &recordingEmailProvider{}
+	emailService := &EmailService{
+		appURL:    "https://app.example.com",
+		providers: []EmailProvider{provider},
+		templates: NewTemplateCache(emailTemplateCacheSize),
+	}
+	throttle := NewEmailThrottleService(client, emailService)
+	throttle.ratePerHour = 2
+
+	// A burst of document emails uses up the hourly limit of the recipient
+	due := time.Now().AddDate(0, 0, 3)
+	for i := 0; i < 2; i++ {
+		if err := emailService.SendSignatureReminderEmail("jane.doe@example.com", "Jane Doe", "Purchasing procedure", "PRO-ACH-001", "sample", &due, true, "en"); err != nil {
+			t.Fatalf("reminder %d failed: %v", i+1, err)
+		}
+	}
+	err := emailService.SendSignatureReminderEmail("jane.doe@example.com", "Jane Doe", "Purchasing procedure", "PRO-ACH-001", "sample", &due, true, "en")
+	if !errors.Is(err, models.ErrEmailRateLimited) {
+		t.Fatalf("expected the third reminder to be rate limited, got %v", err)
+	}
+
+	// The sign-in code still goes out
+	if err := emailService.SendOTPEmail("jane.doe@example.com", "Jane Doe", "123456", "en"); err != nil {
+		t.Fatalf("expected the OTP email to be sent after the rate limit, got %v", err)
+	}
+	if len(provider.sent) != 3 || provider.sent[2].Kind != "otp" {
+		t.Fatalf("expected the OTP email to be delivered, got %d emails", len(provider.sent))
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// exportBatchSize is the number of records fetched per cursor batch by exports
+const exportBatchSize = 500
+
+// exportJobTimeout bounds the duration of a background export
+const exportJobTimeout = 30 * time.Minute
+
+// ExportWriteFunc writes an export to w and returns the number of rows written
+type ExportWriteFunc func(ctx context.Context, w io.Writer) (int64, error)
+
+// ExportService runs the list exports that are streamed to MinIO in the
+// background and downloaded once completed
+type ExportService struct {
+	collection   *mongo.Collection
+	minioService *MinIOService
+}
+
+// NewExportService creates a new export service instance
+func NewExportService(db *DatabaseService, minioService *MinIOService) *ExportService {
+	service := &ExportService{
+		collection:   db.Collection("export_jobs"),
+		minioService: minioService,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "created_at", Value: -1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create export job indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// Start creates an export job and streams the export to MinIO in the
+// background. The rows are piped to the upload as they are written, so the
+// export is never held in memory.
+func (s *ExportService) Start(ctx context.Context, kind models.ExportKind, format, contentType string, write ExportWriteFunc, userID primitive.ObjectID) (*models.ExportJob, error) {
+	if s.minioService == nil {
+		return nil, errors.New("file storage is not available")
+	}
+
+	job := &models.ExportJob{
+		ID:        primitive.NewObjectID(),
+		Kind:      kind,
+		Format:    format,
+		Status:    models.ExportJobRunning,
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	if _, err := s.collection.InsertOne(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	objectKey := fmt.Sprintf("exports/%s/%s_%s.%s", userID.Hex(), kind, job.CreatedAt.Format("20060102_150405"), format)
+	go s.run(job.ID, objectKey, contentType, write)
+
+	return job, nil
+}
+
+// run streams an export to MinIO and records its outcome
+func (s *ExportService) run(jobID primitive.ObjectID, objectKey, contentType string, write ExportWriteFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportJobTimeout)
+	defer cancel()
+
+	reader, writer := io.Pipe()
+	rowsCh := make(chan int64, 1)
+	go func() {
+		rows, err := write(ctx, writer)
+		rowsCh <- rows
+		writer.CloseWithError(err) // A nil error ends the upload normally
+	}()
+
+	fileURL, err := s.minioService.UploadFile(ctx, objectKey, reader, -1, contentType)
+	reader.CloseWithError(err) // Unblocks the writer when the upload failed
+	rows := <-rowsCh
+
+	now := time.Now()
+	update := bson.M{"row_count": rows, "completed_at": now}
+	if err != nil {
+		log.Printf("⚠️  Export %s failed: %v", jobID.Hex(), err)
+		update["status"] = models.ExportJobFailed
+		update["error"] = err.Error()
+	} else {
+		update["status"] = models.ExportJobCompleted
+		update["file_url"] = fileURL
+	}
+
+	updateCtx, updateCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer updateCancel()
+	if _, err := s.collection.UpdateOne(updateCtx, bson.M{"_id": jobID}, bson.M{"$set": update}); err != nil {
+		log.Printf("⚠️  Failed to update export job %s: %v", jobID.Hex(), err)
+	}
+}
+
+// GetByID returns an export job of the user, admins can read every job
+func (s *ExportService) GetByID(ctx context.Context, id, userID primitive.ObjectID, userRole models.UserRole) (*models.ExportJob, error) {
+	filter := bson.M{"_id": id}
+	if userRole != models.RoleAdmin {
+		filter["created_by"] = userID
+	}
+
+	var job models.ExportJob
+	if err := s.collection.FindOne(ctx, filter).Decode(&job); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("export job not found")
+		}
+		return nil, fmt.Errorf("failed to get export job: %w", err)
+	}
+	return &job, nil
+}
+
+// ListByUser returns the latest export jobs of the user
+func (s *ExportService) ListByUser(ctx context.Context, userID primitive.ObjectID, limit int) ([]models.ExportJob, error) {
+	cursor, err := s.collection.Find(ctx,
+		bson.M{"created_by": userID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list export jobs: %w", err)
+	}
+
+	jobs := []models.ExportJob{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode export jobs: %w", err)
+	}
+	return jobs, nil
+}
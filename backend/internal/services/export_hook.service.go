@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportHook inspects a file before its link is served. Returning an error
+// blocks the export, a *models.ExportBlockedError gives the reason to the user.
+type ExportHook interface {
+	Name() string
+	CheckExport(ctx context.Context, export *models.OutboundExport) error
+}
+
+// ExportHookService runs the registered hooks on the PDFs and ZIP archives
+// leaving the platform and logs the blocked attempts.
+//
+// EXPORT_BLOCKED_CLASSIFICATIONS lists the document classifications which
+// cannot be exported, such as "confidential,restricted".
+type ExportHookService struct {
+	hooks              []ExportHook
+	activityLogService *ActivityLogService
+}
+
+// NewExportHookService creates a new export hook service instance
+func NewExportHookService(activityLogService *ActivityLogService) *ExportHookService {
+	service := &ExportHookService{
+		activityLogService: activityLogService,
+	}
+
+	blocked := make(map[models.DocumentClassification]bool)
+	for _, value := range strings.Split(os.Getenv("EXPORT_BLOCKED_CLASSIFICATIONS"), ",") {
+		classification := models.DocumentClassification(strings.TrimSpace(value))
+		if classification == "" {
+			continue
+		}
+		if !models.IsValidDocumentClassification(classification) {
+			fmt.Printf("Warning: Ignoring unknown blocked export classification %q\n", classification)
+			continue
+		}
+		blocked[classification] = true
+	}
+	if len(blocked) > 0 {
+		service.Register(&classificationExportHook{blocked: blocked})
+	}
+
+	return service
+}
+
+// Register adds a hook, hooks run in their registration order
+func (s *ExportHookService) Register(hook ExportHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// Check runs the hooks on an export and returns the error of the first one
+// refusing it, as a *models.ExportBlockedError
+func (s *ExportHookService) Check(ctx context.Context, export *models.OutboundExport) error {
+	if s == nil {
+		return nil
+	}
+
+	for _, hook := range s.hooks {
+		err := hook.CheckExport(ctx, export)
+		if err == nil {
+			continue
+		}
+
+		var blocked *models.ExportBlockedError
+		if !errors.As(err, &blocked) {
+			blocked = &models.ExportBlockedError{Hook: hook.Name(), Reason: err.Error()}
+		}
+		s.logBlocked(ctx, export, blocked)
+		return blocked
+	}
+	return nil
+}
+
+// logBlocked records a refused export in the activity logs
+func (s *ExportHookService) logBlocked(ctx context.Context, export *models.OutboundExport, blocked *models.ExportBlockedError) {
+	references := make([]string, 0, len(export.Documents))
+	for _, document := range export.Documents {
+		references = append(references, document.Reference)
+	}
+	fmt.Printf("🚫 [EXPORT] %s of %s blocked by %s: %s\n", export.Kind, strings.Join(references, ", "), blocked.Hook, blocked.Reason)
+
+	if s.activityLogService == nil {
+		return
+	}
+	var userID *primitive.ObjectID
+	if !export.RequestedBy.IsZero() {
+		userID = &export.RequestedBy
+	}
+	description := fmt.Sprintf("Blocked %s export of %s: %s", export.Kind, strings.Join(references, ", "), blocked.Error())
+	if err := s.activityLogService.LogActivitySimple(ctx, "export_blocked", description, userID, false); err != nil {
+		fmt.Printf("Failed to log activity: %v\n", err)
+	}
+}
+
+// classificationExportHook blocks the exports containing documents of the
+// configured classifications
+type classificationExportHook struct {
+	blocked map[models.DocumentClassification]bool
+}
+
+func (h *classificationExportHook) Name() string {
+	return "classification policy"
+}
+
+func (h *classificationExportHook) CheckExport(ctx context.Context, export *models.OutboundExport) error {
+	for _, document := range export.Documents {
+		if h.blocked[document.Classification] {
+			return &models.ExportBlockedError{
+				Hook:   h.Name(),
+				Reason: fmt.Sprintf("document %s is classified %s", document.Reference, document.Classification),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// recentlyViewedLimit bounds the number of recently viewed documents kept per user
+const recentlyViewedLimit = 50
+
+// FavoriteService handles the quick access lists of users: their starred
+// documents and the documents they viewed recently
+type FavoriteService struct {
+	favoriteCollection *mongo.Collection
+	recentCollection   *mongo.Collection
+	documentService    *DocumentService
+}
+
+// NewFavoriteService creates a new favorite service instance
+func NewFavoriteService(db *DatabaseService, documentService *DocumentService) *FavoriteService {
+	service := &FavoriteService{
+		favoriteCollection: db.Collection("document_favorites"),
+		recentCollection:   db.Collection("recently_viewed"),
+		documentService:    documentService,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.favoriteCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "document_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create document favorite indexes: %v\n", err)
+	}
+	if _, err := service.recentCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "document_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "viewed_at", Value: -1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create recently viewed indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// AddFavorite stars a document; starring it twice is a no-op
+func (s *FavoriteService) AddFavorite(ctx context.Context, userID, documentID primitive.ObjectID) error {
+	_, err := s.favoriteCollection.UpdateOne(ctx,
+		bson.M{"user_id": userID, "document_id": documentID},
+		bson.M{"$setOnInsert": bson.M{"created_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add favorite: %w", err)
+	}
+	return nil
+}
+
+// RemoveFavorite unstars a document
+func (s *FavoriteService) RemoveFavorite(ctx context.Context, userID, documentID primitive.ObjectID) error {
+	if _, err := s.favoriteCollection.DeleteOne(ctx, bson.M{"user_id": userID, "document_id": documentID}); err != nil {
+		return fmt.Errorf("failed to remove favorite: %w", err)
+	}
+	return nil
+}
+
+// IsFavorite tells whether the user starred the document
+func (s *FavoriteService) IsFavorite(ctx context.Context, userID, documentID primitive.ObjectID) (bool, error) {
+	count, err := s.favoriteCollection.CountDocuments(ctx, bson.M{"user_id": userID, "document_id": documentID})
+	if err != nil {
+		return false, fmt.Errorf("failed to check favorite: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ListFavorites returns the starred documents the user can still access, most recently starred first
+func (s *FavoriteService) ListFavorites(ctx context.Context, userID primitive.ObjectID, userRole models.UserRole) ([]models.FavoriteDocumentResponse, error) {
+	cursor, err := s.favoriteCollection.Find(ctx,
+		bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find favorites: %w", err)
+	}
+	var favorites []models.DocumentFavorite
+	if err := cursor.All(ctx, &favorites); err != nil {
+		return nil, fmt.Errorf("failed to decode favorites: %w", err)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(favorites))
+	for _, favorite := range favorites {
+		ids = append(ids, favorite.DocumentID)
+	}
+	documents, err := s.documentService.GetAccessibleByIDs(ctx, ids, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.FavoriteDocumentResponse, 0, len(documents))
+	for _, favorite := range favorites {
+		if document, ok := documents[favorite.DocumentID]; ok {
+			result = append(result, models.FavoriteDocumentResponse{
+				FavoritedAt: favorite.CreatedAt,
+				Document:    document.ToResponse(),
+			})
+		}
+	}
+	return result, nil
+}
+
+// RecordView moves the document to the top of the user's recently viewed
+// list and drops the oldest entries beyond recentlyViewedLimit
+func (s *FavoriteService) RecordView(ctx context.Context, userID, documentID primitive.ObjectID) error {
+	_, err := s.recentCollection.UpdateOne(ctx,
+		bson.M{"user_id": userID, "document_id": documentID},
+		bson.M{
+			"$set": bson.M{"viewed_at": time.Now()},
+			"$inc": bson.M{"view_count": 1},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record view: %w", err)
+	}
+
+	cursor, err := s.recentCollection.Find(ctx,
+		bson.M{"user_id": userID},
+		options.Find().
+			SetSort(bson.D{{Key: "viewed_at", Value: -1}}).
+			SetSkip(recentlyViewedLimit).
+			SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to find old views: %w", err)
+	}
+	var old []models.RecentlyViewed
+	if err := cursor.All(ctx, &old); err != nil {
+		return fmt.Errorf("failed to decode old views: %w", err)
+	}
+	if len(old) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(old))
+	for _, view := range old {
+		ids = append(ids, view.ID)
+	}
+	if _, err := s.recentCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return fmt.Errorf("failed to trim recently viewed: %w", err)
+	}
+	return nil
+}
+
+// ListRecent returns the documents the user viewed recently and can still access, most recent first
+func (s *FavoriteService) ListRecent(ctx context.Context, userID primitive.ObjectID, userRole models.UserRole, limit int) ([]models.RecentDocumentResponse, error) {
+	cursor, err := s.recentCollection.Find(ctx,
+		bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "viewed_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recently viewed: %w", err)
+	}
+	var views []models.RecentlyViewed
+	if err := cursor.All(ctx, &views); err != nil {
+		return nil, fmt.Errorf("failed to decode recently viewed: %w", err)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(views))
+	for _, view := range views {
+		ids = append(ids, view.DocumentID)
+	}
+	documents, err := s.documentService.GetAccessibleByIDs(ctx, ids, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.RecentDocumentResponse, 0, limit)
+	for _, view := range views {
+		if len(result) == limit {
+			break
+		}
+		if document, ok := documents[view.DocumentID]; ok {
+			result = append(result, models.RecentDocumentResponse{
+				ViewedAt:  view.ViewedAt,
+				ViewCount: view.ViewCount,
+				Document:  document.ToResponse(),
+			})
+		}
+	}
+	return result, nil
+}
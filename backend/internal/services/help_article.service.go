@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var helpArticleKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_.-]*$`)
+
+// maxHelpLookupKeys bounds the number of keys resolved by one lookup
+const maxHelpLookupKeys = 100
+
+// HelpArticleService handles the contextual help content
+type HelpArticleService struct {
+	collection *mongo.Collection
+}
+
+// NewHelpArticleService creates a new help article service instance
+func NewHelpArticleService(db *DatabaseService) *HelpArticleService {
+	collection := db.Collection("help_articles")
+
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}, {Key: "locale", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "locale", Value: 1}, {Key: "tags", Value: 1}},
+		},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		fmt.Printf("Warning: Failed to create help article indexes: %v\n", err)
+	}
+
+	return &HelpArticleService{
+		collection: collection,
+	}
+}
+
+// Create creates a help article for a key and locale
+func (s *HelpArticleService) Create(ctx context.Context, req *models.CreateHelpArticleRequest, createdBy primitive.ObjectID) (*models.HelpArticle, error) {
+	if !helpArticleKeyPattern.MatchString(req.Key) {
+		return nil, fmt.Errorf("invalid help article key: use lowercase letters, digits, dots, dashes and underscores")
+	}
+
+	now := time.Now()
+	article := &models.HelpArticle{
+		Key:       req.Key,
+		Locale:    req.Locale,
+		Title:     req.Title,
+		Summary:   req.Summary,
+		Content:   req.Content,
+		Tags:      normalizeHelpTags(req.Tags),
+		IsActive:  true,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	result, err := s.collection.InsertOne(ctx, article)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("help article %s already exists in locale %s", req.Key, req.Locale)
+		}
+		return nil, fmt.Errorf("failed to create help article: %w", err)
+	}
+	article.ID = result.InsertedID.(primitive.ObjectID)
+
+	return article, nil
+}
+
+// GetByID retrieves a help article by ID
+func (s *HelpArticleService) GetByID(ctx context.Context, id primitive.ObjectID) (*models.HelpArticle, error) {
+	var article models.HelpArticle
+	if err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&article); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("help article not found")
+		}
+		return nil, fmt.Errorf("failed to get help article: %w", err)
+	}
+	return &article, nil
+}
+
+// GetByKey returns the active article of a key in the requested locale,
+// falling back to the other locales when it has not been translated yet
+func (s *HelpArticleService) GetByKey(ctx context.Context, key, locale string) (*models.HelpArticle, error) {
+	articles, err := s.Lookup(ctx, []string{key}, locale)
+	if err != nil {
+		return nil, err
+	}
+	article, ok := articles[key]
+	if !ok {
+		return nil, fmt.Errorf("help article not found")
+	}
+	return article, nil
+}
+
+// Lookup resolves several keys at once, e.g. every tooltip of a page.
+// Keys without any active article are left out of the result.
+func (s *HelpArticleService) Lookup(ctx context.Context, keys []string, locale string) (map[string]*models.HelpArticle, error) {
+	if len(keys) > maxHelpLookupKeys {
+		return nil, fmt.Errorf("too many keys: at most %d can be looked up at once", maxHelpLookupKeys)
+	}
+
+	cursor, err := s.collection.Find(ctx, bson.M{
+		"key":       bson.M{"$in": keys},
+		"is_active": true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find help articles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var found []*models.HelpArticle
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, fmt.Errorf("failed to decode help articles: %w", err)
+	}
+
+	// Keep the best locale of each key: the requested one, then the fallback order
+	articles := make(map[string]*models.HelpArticle, len(found))
+	for _, article := range found {
+		current, ok := articles[article.Key]
+		if !ok || helpLocaleRank(article.Locale, locale) < helpLocaleRank(current.Locale, locale) {
+			articles[article.Key] = article
+		}
+	}
+
+	return articles, nil
+}
+
+// helpLocaleRank orders locales by preference for a requested locale
+func helpLocaleRank(articleLocale, requested string) int {
+	if articleLocale == requested {
+		return 0
+	}
+	if i := slices.Index(models.HelpLocales, articleLocale); i >= 0 {
+		return i + 1
+	}
+	return len(models.HelpLocales) + 1
+}
+
+// List returns help articles matching the filter, sorted by key
+func (s *HelpArticleService) List(ctx context.Context, filter *models.HelpArticleFilter) ([]*models.HelpArticle, int64, error) {
+	query := bson.M{}
+	if filter.Locale != "" {
+		query["locale"] = filter.Locale
+	}
+	if filter.Tag != "" {
+		query["tags"] = strings.ToLower(filter.Tag)
+	}
+	if !filter.IncludeInactive {
+		query["is_active"] = true
+	}
+	if filter.Search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Search), Options: "i"}
+		query["$or"] = []bson.M{
+			{"key": pattern},
+			{"title": pattern},
+			{"summary": pattern},
+			{"content": pattern},
+		}
+	}
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count help articles: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "key", Value: 1}, {Key: "locale", Value: 1}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+
+	cursor, err := s.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find help articles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	articles := make([]*models.HelpArticle, 0)
+	if err := cursor.All(ctx, &articles); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode help articles: %w", err)
+	}
+
+	return articles, total, nil
+}
+
+// Update updates a help article
+func (s *HelpArticleService) Update(ctx context.Context, id primitive.ObjectID, req *models.UpdateHelpArticleRequest, updatedBy primitive.ObjectID) (*models.HelpArticle, error) {
+	setFields := bson.M{
+		"updated_by": updatedBy,
+		"updated_at": time.Now(),
+	}
+	if req.Title != nil {
+		setFields["title"] = *req.Title
+	}
+	if req.Summary != nil {
+		setFields["summary"] = *req.Summary
+	}
+	if req.Content != nil {
+		setFields["content"] = *req.Content
+	}
+	if req.Tags != nil {
+		setFields["tags"] = normalizeHelpTags(*req.Tags)
+	}
+	if req.IsActive != nil {
+		setFields["is_active"] = *req.IsActive
+	}
+
+	result := s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": setFields},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+
+	var article models.HelpArticle
+	if err := result.Decode(&article); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("help article not found")
+		}
+		return nil, fmt.Errorf("failed to update help article: %w", err)
+	}
+
+	return &article, nil
+}
+
+// Delete removes a help article
+func (s *HelpArticleService) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete help article: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("help article not found")
+	}
+	return nil
+}
+
+// normalizeHelpTags lowercases tags and drops blanks and duplicates
+func normalizeHelpTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
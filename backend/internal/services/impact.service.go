@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ImpactService finds procedures affected by organizational changes
+type ImpactService struct {
+	documentCollection    *mongo.Collection
+	departmentCollection  *mongo.Collection
+	jobPositionCollection *mongo.Collection
+	userCollection        *mongo.Collection
+	actorCollection       *mongo.Collection
+}
+
+// NewImpactService creates a new impact analysis service instance
+func NewImpactService(db *DatabaseService) *ImpactService {
+	return &ImpactService{
+		documentCollection:    db.Collection("documents"),
+		departmentCollection:  db.Collection("departments"),
+		jobPositionCollection: db.Collection("job_positions"),
+		userCollection:        db.Collection("users"),
+		actorCollection:       db.Collection("actors"),
+	}
+}
+
+// impactScope holds everything that may reference the analyzed target
+type impactScope struct {
+	names     map[string]bool             // Normalized names matched against free text
+	positions map[primitive.ObjectID]bool // Job positions matched against task intervenants
+	users     map[primitive.ObjectID]bool // Users matched against contributor assignments
+}
+
+// AnalyzeDepartment lists documents referencing a department, its job positions or its members
+func (s *ImpactService) AnalyzeDepartment(ctx context.Context, departmentID primitive.ObjectID) (*models.ImpactAnalysisResponse, error) {
+	var department models.Department
+	if err := s.departmentCollection.FindOne(ctx, bson.M{"_id": departmentID}).Decode(&department); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("department not found")
+		}
+		return nil, fmt.Errorf("failed to get department: %w", err)
+	}
+
+	scope := newImpactScope()
+	scope.addName(department.Name)
+	scope.addName(department.Code)
+
+	// Job positions belonging to the department
+	cursor, err := s.jobPositionCollection.Find(ctx, bson.M{"department_id": departmentID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find job positions: %w", err)
+	}
+	var positions []models.JobPosition
+	if err := cursor.All(ctx, &positions); err != nil {
+		return nil, fmt.Errorf("failed to decode job positions: %w", err)
+	}
+
+	relatedPositions := make([]string, 0, len(positions))
+	positionIDs := make([]primitive.ObjectID, 0, len(positions))
+	for _, position := range positions {
+		scope.positions[position.ID] = true
+		scope.addName(position.Title)
+		relatedPositions = append(relatedPositions, position.Title)
+		positionIDs = append(positionIDs, position.ID)
+	}
+
+	if err := s.collectScope(ctx, scope,
+		bson.M{"$or": []bson.M{{"department_id": departmentID}, {"job_position_id": bson.M{"$in": positionIDs}}}},
+		bson.M{"department_id": departmentID},
+	); err != nil {
+		return nil, err
+	}
+
+	response, err := s.scan(ctx, scope, models.ImpactTarget{
+		Type: models.ImpactTargetDepartment,
+		ID:   department.ID.Hex(),
+		Name: department.Name,
+		Code: department.Code,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response.RelatedPositions = relatedPositions
+
+	return response, nil
+}
+
+// AnalyzeJobPosition lists documents referencing a job position or its holders
+func (s *ImpactService) AnalyzeJobPosition(ctx context.Context, jobPositionID primitive.ObjectID) (*models.ImpactAnalysisResponse, error) {
+	var position models.JobPosition
+	if err := s.jobPositionCollection.FindOne(ctx, bson.M{"_id": jobPositionID}).Decode(&position); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("job position not found")
+		}
+		return nil, fmt.Errorf("failed to get job position: %w", err)
+	}
+
+	scope := newImpactScope()
+	scope.positions[position.ID] = true
+	scope.addName(position.Title)
+	scope.addName(position.Code)
+
+	if err := s.collectScope(ctx, scope,
+		bson.M{"job_position_id": jobPositionID},
+		bson.M{"job_position_id": jobPositionID},
+	); err != nil {
+		return nil, err
+	}
+
+	return s.scan(ctx, scope, models.ImpactTarget{
+		Type: models.ImpactTargetJobPosition,
+		ID:   position.ID.Hex(),
+		Name: position.Title,
+		Code: position.Code,
+	})
+}
+
+func newImpactScope() *impactScope {
+	return &impactScope{
+		names:     make(map[string]bool),
+		positions: make(map[primitive.ObjectID]bool),
+		users:     make(map[primitive.ObjectID]bool),
+	}
+}
+
+func (sc *impactScope) addName(name string) {
+	if key := NormalizeActorName(name); key != "" {
+		sc.names[key] = true
+	}
+}
+
+// collectScope adds registry actors and users matching the filters to the scope
+func (s *ImpactService) collectScope(ctx context.Context, scope *impactScope, actorFilter, userFilter bson.M) error {
+	cursor, err := s.actorCollection.Find(ctx, actorFilter)
+	if err != nil {
+		return fmt.Errorf("failed to find actors: %w", err)
+	}
+	var actors []models.Actor
+	if err := cursor.All(ctx, &actors); err != nil {
+		return fmt.Errorf("failed to decode actors: %w", err)
+	}
+	for _, actor := range actors {
+		for _, key := range actor.NormalizedKeys {
+			scope.names[key] = true
+		}
+	}
+
+	cursor, err = s.userCollection.Find(ctx, userFilter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to find users: %w", err)
+	}
+	var users []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &users); err != nil {
+		return fmt.Errorf("failed to decode users: %w", err)
+	}
+	for _, user := range users {
+		scope.users[user.ID] = true
+	}
+
+	return nil
+}
+
+// scan walks every non-archived document and records references to the scope
+func (s *ImpactService) scan(ctx context.Context, scope *impactScope, target models.ImpactTarget) (*models.ImpactAnalysisResponse, error) {
+	filter := models.NotDeleted(bson.M{"status": bson.M{"$nin": []models.DocumentStatus{models.DocumentStatusArchived, models.DocumentStatusReviewDue, models.DocumentStatusSuperseded}}})
+	findOptions := options.Find().SetSort(bson.D{{Key: "process_code", Value: 1}, {Key: "reference", Value: 1}})
+
+	cursor, err := s.documentCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	response := &models.ImpactAnalysisResponse{
+		Target:    target,
+		Documents: make([]models.ImpactedDocument, 0),
+	}
+
+	for cursor.Next(ctx) {
+		var document models.Document
+		if err := cursor.Decode(&document); err != nil {
+			return nil, fmt.Errorf("failed to decode document: %w", err)
+		}
+
+		impacted := models.ImpactedDocument{
+			DocumentID:  document.ID.Hex(),
+			ProcessCode: document.ProcessCode,
+			Reference:   document.Reference,
+			Title:       document.Title,
+			Version:     document.Version,
+			Status:      document.Status,
+		}
+
+		for _, stakeholder := range document.Stakeholders {
+			if scope.names[NormalizeActorName(stakeholder)] {
+				impacted.Stakeholders = append(impacted.Stakeholders, stakeholder)
+			}
+		}
+		for _, actor := range document.Metadata.ImplicatedActors {
+			if scope.names[NormalizeActorName(actor)] {
+				impacted.ImplicatedActors = append(impacted.ImplicatedActors, actor)
+			}
+		}
+		for _, group := range document.ProcessGroups {
+			for _, step := range group.ProcessSteps {
+				if scope.names[NormalizeActorName(step.Responsible)] {
+					impacted.Steps = append(impacted.Steps, models.ImpactedStep{
+						GroupTitle:  group.Title,
+						StepID:      step.ID,
+						StepTitle:   step.Title,
+						Responsible: step.Responsible,
+					})
+				}
+			}
+		}
+		for _, task := range document.Tasks {
+			for _, positionID := range task.Intervenants {
+				if scope.positions[positionID] {
+					impacted.Tasks = append(impacted.Tasks, models.ImpactedTask{
+						TaskCode:      task.Code,
+						Description:   task.Description,
+						JobPositionID: positionID.Hex(),
+					})
+				}
+			}
+		}
+		for _, team := range [][]models.Contributor{
+			document.Contributors.Authors,
+			document.Contributors.Verifiers,
+			document.Contributors.Validators,
+		} {
+			for _, contributor := range team {
+				if scope.users[contributor.UserID] || scope.names[NormalizeActorName(contributor.Department)] {
+					impacted.Contributors = append(impacted.Contributors, models.ImpactedContributor{
+						UserID: contributor.UserID.Hex(),
+						Name:   contributor.Name,
+						Team:   contributor.Team,
+						Status: contributor.Status,
+					})
+				}
+			}
+		}
+
+		if len(impacted.Stakeholders) == 0 && len(impacted.ImplicatedActors) == 0 &&
+			len(impacted.Steps) == 0 && len(impacted.Tasks) == 0 && len(impacted.Contributors) == 0 {
+			continue
+		}
+
+		response.StepCount += len(impacted.Steps)
+		response.TaskCount += len(impacted.Tasks)
+		response.ContributorCount += len(impacted.Contributors)
+		response.Documents = append(response.Documents, impacted)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate documents: %w", err)
+	}
+
+	response.DocumentCount = len(response.Documents)
+	return response, nil
+}
@@ -0,0 +1,512 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// jobQueueKey lists the IDs of the queued jobs, oldest on the right
+	jobQueueKey = "jobs:queue"
+	// jobProcessingKey lists the IDs of the jobs taken by a worker, put back
+	// in the queue on startup when the process stopped while running them
+	jobProcessingKey = "jobs:processing"
+	// jobTTL is how long a job can be polled after its creation
+	jobTTL = 24 * time.Hour
+	// jobMaxAttempts bounds the runs of a job interrupted by restarts
+	jobMaxAttempts = 3
+	// jobPollTimeout is how long a worker blocks waiting for a job
+	jobPollTimeout = 5 * time.Second
+)
+
+// JobQueueService runs slow work, such as the PDF generation of large
+// documents, outside of the HTTP requests. Jobs are queued in Redis so they
+// survive restarts, and the user is notified when they finish.
+//
+// PDF_JOB_WORKERS sets the number of workers (2) and PDF_JOB_TIMEOUT_SECONDS
+// the time allowed to render a PDF (300).
+type JobQueueService struct {
+	redisClient         *redis.Client
+	documentService     *DocumentService
+	notificationService *NotificationService
+	workers             int
+	timeout             time.Duration
+}
+
+// NewJobQueueService creates a new job queue service instance
+func NewJobQueueService(redisClient *redis.Client, documentService *DocumentService, notificationService *NotificationService) *JobQueueService {
+	service := &JobQueueService{
+		redisClient:         redisClient,
+		documentService:     documentService,
+		notificationService: notificationService,
+		workers:             2,
+		timeout:             5 * time.Minute,
+	}
+	if v, err := strconv.Atoi(os.Getenv("PDF_JOB_WORKERS")); err == nil && v > 0 {
+		service.workers = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("PDF_JOB_TIMEOUT_SECONDS")); err == nil && v > 0 {
+		service.timeout = time.Duration(v) * time.Second
+	}
+	return service
+}
+
+// EnqueueDocumentPDF queues the PDF generation of a document
+func (s *JobQueueService) EnqueueDocumentPDF(ctx context.Context, documentID, userID primitive.ObjectID, opts models.PDFExportOptions) (*models.Job, error) {
+	document, err := s.documentService.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.Job{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       models.JobTypeDocumentPDF,
+		Status:     models.JobStatusQueued,
+		DocumentID: document.ID.Hex(),
+		Reference:  document.Reference,
+		Watermark:  opts.Watermark,
+		Archival:   opts.Archival,
+		Force:      opts.Force,
+		PageLayout: opts.PageLayout,
+		CreatedBy:  userID.Hex(),
+		CreatedAt:  time.Now(),
+	}
+	if err := s.enqueue(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// EnqueuePDFRegeneration queues the regeneration of the stored PDF of every
+// document matching the filters, such as after a template change. The jobs
+// are grouped in a batch reporting the outcome of each document, the admin
+// is notified once all of them finished.
+func (s *JobQueueService) EnqueuePDFRegeneration(ctx context.Context, req *models.RegeneratePDFsRequest, userID primitive.ObjectID) (*models.PDFRegenerationBatch, error) {
+	documents, err := s.documentService.ListPDFRegenerationCandidates(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	batch := &models.PDFRegenerationBatch{
+		ID:        primitive.NewObjectID().Hex(),
+		Total:     len(documents),
+		Items:     make([]models.PDFRegenerationItem, 0, len(documents)),
+		CreatedBy: userID.Hex(),
+		CreatedAt: now,
+	}
+	jobs := make([]*models.Job, 0, len(documents))
+	for _, document := range documents {
+		job := &models.Job{
+			ID:         primitive.NewObjectID().Hex(),
+			Type:       models.JobTypeDocumentPDF,
+			Status:     models.JobStatusQueued,
+			DocumentID: document.ID.Hex(),
+			Reference:  document.Reference,
+			Force:      true,
+			BatchID:    batch.ID,
+			CreatedBy:  userID.Hex(),
+			CreatedAt:  now,
+		}
+		jobs = append(jobs, job)
+		batch.Items = append(batch.Items, models.PDFRegenerationItem{
+			DocumentID: job.DocumentID,
+			Reference:  job.Reference,
+			JobID:      job.ID,
+			Status:     models.JobStatusQueued,
+		})
+	}
+
+	// The batch is stored first so the workers find it when a job finishes
+	if err := s.saveBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+	for i, job := range jobs {
+		if err := s.enqueue(ctx, job); err != nil {
+			batch.Items[i].JobID = ""
+			batch.Items[i].Status = models.JobStatusFailed
+			batch.Items[i].Error = err.Error()
+		}
+	}
+	if err := s.saveBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	return s.GetBatch(ctx, batch.ID)
+}
+
+// GetBatch returns a batch with the current outcome of each of its jobs
+func (s *JobQueueService) GetBatch(ctx context.Context, id string) (*models.PDFRegenerationBatch, error) {
+	batchJSON, err := s.redisClient.Get(ctx, s.getBatchKey(id)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, models.ErrBatchNotFound
+		}
+		return nil, fmt.Errorf("failed to get batch from Redis: %w", err)
+	}
+	var batch models.PDFRegenerationBatch
+	if err := json.Unmarshal([]byte(batchJSON), &batch); err != nil {
+		return nil, fmt.Errorf("failed to deserialize batch: %w", err)
+	}
+
+	keys := make([]string, 0, len(batch.Items))
+	for _, item := range batch.Items {
+		if item.JobID != "" {
+			keys = append(keys, s.getJobKey(item.JobID))
+		}
+	}
+	jobs := make(map[string]*models.Job, len(keys))
+	if len(keys) > 0 {
+		values, err := s.redisClient.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get batch jobs from Redis: %w", err)
+		}
+		for _, value := range values {
+			jobJSON, ok := value.(string)
+			if !ok {
+				continue // Expired
+			}
+			var job models.Job
+			if err := json.Unmarshal([]byte(jobJSON), &job); err == nil {
+				jobs[job.ID] = &job
+			}
+		}
+	}
+
+	batch.Queued, batch.Completed, batch.Failed = 0, 0, 0
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if job, ok := jobs[item.JobID]; ok {
+			item.Status = job.Status
+			item.ResultURL = job.ResultURL
+			item.Error = job.Error
+		}
+		switch item.Status {
+		case models.JobStatusCompleted:
+			batch.Completed++
+		case models.JobStatusFailed:
+			batch.Failed++
+		default:
+			batch.Queued++
+		}
+	}
+	return &batch, nil
+}
+
+// Get returns a job of the user, admins can read every job
+func (s *JobQueueService) Get(ctx context.Context, id string, userID primitive.ObjectID, userRole models.UserRole) (*models.Job, error) {
+	job, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if userRole != models.RoleAdmin && job.CreatedBy != userID.Hex() {
+		return nil, models.ErrJobNotFound
+	}
+	return job, nil
+}
+
+// Stats returns the number of queued jobs and the jobs being run
+func (s *JobQueueService) Stats(ctx context.Context) (*models.JobQueueStats, error) {
+	queued, err := s.redisClient.LLen(ctx, jobQueueKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count queued jobs: %w", err)
+	}
+	ids, err := s.redisClient.LRange(ctx, jobProcessingKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list running jobs: %w", err)
+	}
+
+	stats := &models.JobQueueStats{
+		Workers:        s.workers,
+		TimeoutSeconds: int(s.timeout / time.Second),
+		Queued:         queued,
+		Running:        make([]models.Job, 0, len(ids)),
+	}
+	for _, id := range ids {
+		job, err := s.load(ctx, id)
+		if err != nil {
+			continue // Expired in the meantime
+		}
+		stats.Running = append(stats.Running, *job)
+	}
+	return stats, nil
+}
+
+// Start puts back the jobs interrupted by the last stop in the queue and runs
+// the workers until the context is cancelled
+func (s *JobQueueService) Start(ctx context.Context) {
+	requeued := 0
+	for {
+		err := s.redisClient.LMove(ctx, jobProcessingKey, jobQueueKey, "RIGHT", "RIGHT").Err()
+		if err != nil {
+			if err != redis.Nil {
+				fmt.Printf("Warning: Failed to requeue interrupted jobs: %v\n", err)
+			}
+			break
+		}
+		requeued++
+	}
+	if requeued > 0 {
+		fmt.Printf("🔁 Requeued %d interrupted job(s)\n", requeued)
+	}
+
+	for i := 0; i < s.workers; i++ {
+		go s.work(ctx)
+	}
+	fmt.Printf("🧵 Job queue started (%d workers, %s timeout)\n", s.workers, s.timeout)
+}
+
+// work runs the queued jobs one at a time until the context is cancelled
+func (s *JobQueueService) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		id, err := s.redisClient.BLMove(ctx, jobQueueKey, jobProcessingKey, "RIGHT", "LEFT", jobPollTimeout).Result()
+		if err != nil {
+			if err != redis.Nil && ctx.Err() == nil {
+				fmt.Printf("⚠️  Failed to take a job from the queue: %v\n", err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+
+		s.run(id)
+
+		if err := s.redisClient.LRem(context.Background(), jobProcessingKey, 1, id).Err(); err != nil {
+			fmt.Printf("⚠️  Failed to release job %s: %v\n", id, err)
+		}
+	}
+}
+
+// run executes a job and records its outcome
+func (s *JobQueueService) run(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout+30*time.Second)
+	defer cancel()
+
+	job, err := s.load(ctx, id)
+	if err != nil {
+		// The job expired while queued
+		fmt.Printf("⚠️  Skipping job %s: %v\n", id, err)
+		return
+	}
+	if job.IsFinished() {
+		return
+	}
+
+	job.Attempts++
+	if job.Attempts > jobMaxAttempts {
+		s.finish(ctx, job, "", errors.New("job was interrupted too many times"))
+		return
+	}
+	now := time.Now()
+	job.Status = models.JobStatusRunning
+	job.StartedAt = &now
+	if err := s.save(ctx, job); err != nil {
+		fmt.Printf("⚠️  Failed to update job %s: %v\n", id, err)
+	}
+
+	var resultURL string
+	switch job.Type {
+	case models.JobTypeDocumentPDF:
+		resultURL, err = s.generateDocumentPDF(ctx, job)
+	default:
+		err = fmt.Errorf("unknown job type: %s", job.Type)
+	}
+	s.finish(ctx, job, resultURL, err)
+}
+
+// generateDocumentPDF generates the PDF of the document of a job, with the
+// longer render timeout of background jobs
+func (s *JobQueueService) generateDocumentPDF(ctx context.Context, job *models.Job) (string, error) {
+	documentID, err := primitive.ObjectIDFromHex(job.DocumentID)
+	if err != nil {
+		return "", fmt.Errorf("invalid document ID: %w", err)
+	}
+	requestedBy, _ := primitive.ObjectIDFromHex(job.CreatedBy)
+	renderCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.documentService.ExportPDF(withPDFRenderTimeout(renderCtx, s.timeout), documentID, models.PDFExportOptions{Watermark: job.Watermark, Archival: job.Archival, Force: job.Force, PageLayout: job.PageLayout, RequestedBy: requestedBy})
+}
+
+// finish records the outcome of a job and notifies the user who queued it
+func (s *JobQueueService) finish(ctx context.Context, job *models.Job, resultURL string, jobErr error) {
+	now := time.Now()
+	job.CompletedAt = &now
+	if jobErr != nil {
+		fmt.Printf("❌ Job %s (%s) failed: %v\n", job.ID, job.Type, jobErr)
+		job.Status = models.JobStatusFailed
+		job.Error = jobErr.Error()
+	} else {
+		fmt.Printf("✅ Job %s (%s) completed\n", job.ID, job.Type)
+		job.Status = models.JobStatusCompleted
+		job.ResultURL = resultURL
+	}
+	if err := s.save(ctx, job); err != nil {
+		fmt.Printf("⚠️  Failed to update job %s: %v\n", job.ID, err)
+	}
+
+	if job.BatchID != "" {
+		s.batchJobFinished(ctx, job)
+		return
+	}
+	s.notify(ctx, job)
+}
+
+// batchJobFinished counts the finished jobs of a batch and notifies the admin
+// who queued it once the last one finished
+func (s *JobQueueService) batchJobFinished(ctx context.Context, job *models.Job) {
+	finishedKey := s.getBatchKey(job.BatchID) + ":finished"
+	finished, err := s.redisClient.Incr(ctx, finishedKey).Result()
+	if err != nil {
+		fmt.Printf("⚠️  Failed to count the finished jobs of batch %s: %v\n", job.BatchID, err)
+		return
+	}
+	s.redisClient.Expire(ctx, finishedKey, jobTTL)
+
+	batch, err := s.GetBatch(ctx, job.BatchID)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to load batch %s: %v\n", job.BatchID, err)
+		return
+	}
+	queued := 0
+	for _, item := range batch.Items {
+		if item.JobID != "" {
+			queued++
+		}
+	}
+	if finished != int64(queued) {
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(batch.CreatedBy)
+	if err != nil {
+		return
+	}
+	priority := models.NotificationPriorityNormal
+	if batch.Failed > 0 {
+		priority = models.NotificationPriorityHigh
+	}
+	notificationReq := &models.SendNotificationRequest{
+		UserIDs:  []string{batch.CreatedBy},
+		Title:    "PDF Regeneration Finished",
+		Body:     fmt.Sprintf("%d of %d PDF(s) were regenerated, %d failed.", batch.Completed, batch.Total, batch.Failed),
+		Category: models.NotificationCategorySystem,
+		Priority: priority,
+		Data: map[string]interface{}{
+			"batchId":   batch.ID,
+			"completed": batch.Completed,
+			"failed":    batch.Failed,
+			"action":    "pdf_regeneration_finished",
+		},
+	}
+	if _, err := s.notificationService.SendNotification(ctx, notificationReq, userID); err != nil {
+		fmt.Printf("⚠️  Failed to notify the end of batch %s: %v\n", batch.ID, err)
+	}
+}
+
+// notify tells the user who queued a job that it finished
+func (s *JobQueueService) notify(ctx context.Context, job *models.Job) {
+	title := "PDF Ready"
+	body := fmt.Sprintf("The PDF of document %s is ready to download.", job.Reference)
+	priority := models.NotificationPriorityNormal
+	if job.Status == models.JobStatusFailed {
+		title = "PDF Generation Failed"
+		body = fmt.Sprintf("The PDF of document %s could not be generated: %s", job.Reference, job.Error)
+		priority = models.NotificationPriorityHigh
+	}
+
+	userID, err := primitive.ObjectIDFromHex(job.CreatedBy)
+	if err != nil {
+		return
+	}
+	notificationReq := &models.SendNotificationRequest{
+		UserIDs:  []string{job.CreatedBy},
+		Title:    title,
+		Body:     body,
+		Category: models.NotificationCategorySystem,
+		Priority: priority,
+		Data: map[string]interface{}{
+			"jobId":      job.ID,
+			"documentId": job.DocumentID,
+			"reference":  job.Reference,
+			"status":     string(job.Status),
+			"resultUrl":  job.ResultURL,
+			"action":     "job_finished",
+		},
+	}
+	if _, err := s.notificationService.SendNotification(ctx, notificationReq, userID); err != nil {
+		fmt.Printf("⚠️  Failed to notify the end of job %s: %v\n", job.ID, err)
+	}
+}
+
+// enqueue stores a job and puts it in the queue
+func (s *JobQueueService) enqueue(ctx context.Context, job *models.Job) error {
+	if err := s.save(ctx, job); err != nil {
+		return err
+	}
+	if err := s.redisClient.LPush(ctx, jobQueueKey, job.ID).Err(); err != nil {
+		return fmt.Errorf("failed to queue job: %w", err)
+	}
+	return nil
+}
+
+// saveBatch stores a batch in Redis, for as long as its jobs
+func (s *JobQueueService) saveBatch(ctx context.Context, batch *models.PDFRegenerationBatch) error {
+	batchJSON, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to serialize batch: %w", err)
+	}
+	if err := s.redisClient.Set(ctx, s.getBatchKey(batch.ID), batchJSON, jobTTL).Err(); err != nil {
+		return fmt.Errorf("failed to store batch in Redis: %w", err)
+	}
+	return nil
+}
+
+// save stores a job in Redis
+func (s *JobQueueService) save(ctx context.Context, job *models.Job) error {
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to serialize job: %w", err)
+	}
+	ttl := time.Until(job.CreatedAt.Add(jobTTL))
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	if err := s.redisClient.Set(ctx, s.getJobKey(job.ID), jobJSON, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store job in Redis: %w", err)
+	}
+	return nil
+}
+
+// load reads a job from Redis
+func (s *JobQueueService) load(ctx context.Context, id string) (*models.Job, error) {
+	jobJSON, err := s.redisClient.Get(ctx, s.getJobKey(id)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, models.ErrJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get job from Redis: %w", err)
+	}
+
+	var job models.Job
+	if err := json.Unmarshal([]byte(jobJSON), &job); err != nil {
+		return nil, fmt.Errorf("failed to deserialize job: %w", err)
+	}
+	return &job, nil
+}
+
+func (s *JobQueueService) getJobKey(id string) string {
+	return "job:" + id
+}
+
+func (s *JobQueueService) getBatchKey(id string) string {
+	return "job_batch:" + id
+}
@@ -16,6 +16,7 @@ type JWTService struct {
 	issuer        string
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
+	displayExpiry time.Duration
 }
 
 // JWTCustomClaims represents the JWT claims
@@ -25,7 +26,7 @@ type JWTCustomClaims struct {
 	FirstName string             `json:"firstName"`
 	LastName  string             `json:"lastName"`
 	Role      models.UserRole    `json:"role"`
-	TokenType string             `json:"tokenType"` // "access" or "refresh"
+	TokenType string             `json:"tokenType"` // "access", "refresh" or "display"
 	jwt.RegisteredClaims
 }
 
@@ -58,11 +59,20 @@ func NewJWTService() *JWTService {
 		}
 	}
 
+	// Display (wallboard) sessions expire in 12 hours
+	displayExpiry := 12 * time.Hour
+	if exp := os.Getenv("JWT_DISPLAY_EXPIRY"); exp != "" {
+		if duration, err := time.ParseDuration(exp); err == nil {
+			displayExpiry = duration
+		}
+	}
+
 	return &JWTService{
 		secretKey:     []byte(secretKey),
 		issuer:        issuer,
 		accessExpiry:  accessExpiry,
 		refreshExpiry: refreshExpiry,
+		displayExpiry: displayExpiry,
 	}
 }
 
@@ -97,6 +107,15 @@ func (s *JWTService) GenerateRefreshToken(user *models.User) (string, error) {
 	return s.generateToken(user, "refresh", s.refreshExpiry)
 }
 
+// GenerateDisplayToken generates a read-only token for a display approved by a user
+func (s *JWTService) GenerateDisplayToken(user *models.User) (string, time.Time, error) {
+	token, err := s.generateToken(user, "display", s.displayExpiry)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, time.Now().Add(s.displayExpiry), nil
+}
+
 // generateToken creates a JWT token with the specified type and expiry
 func (s *JWTService) generateToken(user *models.User, tokenType string, expiry time.Duration) (string, error) {
 	claims := JWTCustomClaims{
@@ -191,6 +210,21 @@ func (s *JWTService) ValidateRefreshToken(tokenString string) (*JWTCustomClaims,
 	return claims, nil
 }
 
+// ValidateDisplayToken validates a read-only display token
+func (s *JWTService) ValidateDisplayToken(tokenString string) (*JWTCustomClaims, error) {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure it's a display token
+	if claims.TokenType != "display" {
+		return nil, models.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
 // ExtractTokenFromHeader extracts the token from Authorization header
 func (s *JWTService) ExtractTokenFromHeader(authHeader string) string {
 	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
@@ -220,3 +254,8 @@ func (s *JWTService) GetAccessTokenExpiry() time.Duration {
 func (s *JWTService) GetRefreshTokenExpiry() time.Duration {
 	return s.refreshExpiry
 }
+
+// GetDisplayTokenExpiry returns the display token expiry duration
+func (s *JWTService) GetDisplayTokenExpiry() time.Duration {
+	return s.displayExpiry
+}
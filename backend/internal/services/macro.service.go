@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/kodesonik/process-manager/internal/models"
@@ -18,6 +19,7 @@ type MacroService struct {
 	db                   *DatabaseService
 	macroCollection      *mongo.Collection
 	docCollection        *mongo.Collection
+	counterCollection    *mongo.Collection
 	pdfService           *PDFService
 	documentationService *DocumentationService
 }
@@ -28,6 +30,7 @@ func NewMacroService(db *DatabaseService, pdfService *PDFService, documentationS
 		db:                   db,
 		macroCollection:      db.Collection("macros"),
 		docCollection:        db.Collection("documents"),
+		counterCollection:    db.Collection("process_counters"),
 		pdfService:           pdfService,
 		documentationService: documentationService,
 	}
@@ -267,6 +270,41 @@ func (s *MacroService) UpdateMacro(ctx context.Context, id primitive.ObjectID, r
 	return &macro, nil
 }
 
+// SetWorkflow sets the workflow of the documents of a macro. Documents with
+// their own workflow keep it.
+func (s *MacroService) SetWorkflow(ctx context.Context, id primitive.ObjectID, workflow *models.WorkflowDefinition) (*models.Macro, error) {
+	if err := workflow.Validate(); err != nil {
+		return nil, err
+	}
+	return s.updateWorkflow(ctx, id, bson.M{"$set": bson.M{"workflow": workflow, "updated_at": time.Now()}})
+}
+
+// ClearWorkflow removes the workflow of a macro, whose documents follow the
+// default workflow again
+func (s *MacroService) ClearWorkflow(ctx context.Context, id primitive.ObjectID) (*models.Macro, error) {
+	return s.updateWorkflow(ctx, id, bson.M{
+		"$unset": bson.M{"workflow": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	})
+}
+
+// updateWorkflow applies a workflow update to a macro
+func (s *MacroService) updateWorkflow(ctx context.Context, id primitive.ObjectID, update bson.M) (*models.Macro, error) {
+	var macro models.Macro
+	err := s.macroCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&macro)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("macro not found")
+		}
+		return nil, fmt.Errorf("failed to update macro workflow: %w", err)
+	}
+	return &macro, nil
+}
+
 // DeleteMacro deletes a macro by ID
 func (s *MacroService) DeleteMacro(ctx context.Context, id primitive.ObjectID) error {
 	// Check if macro has associated processes
@@ -299,7 +337,7 @@ func (s *MacroService) DeleteMacro(ctx context.Context, id primitive.ObjectID) e
 // GetProcessesByMacroID retrieves all processes (documents) belonging to a macro
 func (s *MacroService) GetProcessesByMacroID(ctx context.Context, macroID primitive.ObjectID, limit int, page int, isActive *bool) ([]models.DocumentResponse, int64, error) {
 	// Build query
-	query := bson.M{"macro_id": macroID}
+	query := models.NotDeleted(bson.M{"macro_id": macroID})
 
 	// Active status filter
 	if isActive != nil {
@@ -349,37 +387,20 @@ func (s *MacroService) GetProcessesByMacroID(ctx context.Context, macroID primit
 
 // GetProcessCountByMacroID returns the count of processes for a given macro
 func (s *MacroService) GetProcessCountByMacroID(ctx context.Context, macroID primitive.ObjectID) (int64, error) {
-	count, err := s.docCollection.CountDocuments(ctx, bson.M{"macro_id": macroID})
+	count, err := s.docCollection.CountDocuments(ctx, models.NotDeleted(bson.M{"macro_id": macroID}))
 	if err != nil {
 		return 0, fmt.Errorf("failed to count processes: %w", err)
 	}
 	return count, nil
 }
 
-// ExportPDF generates and exports the macro as PDF
-func (s *MacroService) ExportPDF(ctx context.Context, id primitive.ObjectID) (string, error) {
-	// Get existing macro
-	macro, err := s.GetMacroByID(ctx, id)
-	if err != nil {
-		return "", err
-	}
-
-	// Get all processes for this macro (no pagination, only active ones)
-	active := true
-	_, _, err = s.GetProcessesByMacroID(ctx, id, 0, 0, &active)
-	if err != nil {
-		return "", fmt.Errorf("failed to get processes: %w", err)
-	}
-
-	// Convert DocumentResponse back to Document for PDF service
-	// This is a bit inefficient but necessary since PDF service expects []models.Document
-	// Ideally we should refactor PDF service to accept a more generic interface or use DocumentResponse
-	// For now, let's fetch the raw documents again
-	// Build query
-	query := bson.M{
-		"macro_id":  id,
+// ActiveProcesses returns the active processes of a macro in their order,
+// as laid out in the macro PDF
+func (s *MacroService) ActiveProcesses(ctx context.Context, macroID primitive.ObjectID) ([]models.Document, error) {
+	query := models.NotDeleted(bson.M{
+		"macro_id":  macroID,
 		"is_active": true,
-	}
+	})
 	opts := options.Find().SetSort(bson.D{
 		{Key: "order", Value: 1},
 		{Key: "process_code", Value: 1},
@@ -387,13 +408,29 @@ func (s *MacroService) ExportPDF(ctx context.Context, id primitive.ObjectID) (st
 
 	cursor, err := s.docCollection.Find(ctx, query, opts)
 	if err != nil {
-		return "", fmt.Errorf("failed to find processes: %w", err)
+		return nil, fmt.Errorf("failed to find processes: %w", err)
 	}
 	defer cursor.Close(ctx)
 
-	var rawProcesses []models.Document
-	if err := cursor.All(ctx, &rawProcesses); err != nil {
-		return "", fmt.Errorf("failed to decode processes: %w", err)
+	processes := []models.Document{}
+	if err := cursor.All(ctx, &processes); err != nil {
+		return nil, fmt.Errorf("failed to decode processes: %w", err)
+	}
+	return processes, nil
+}
+
+// ExportPDF generates and exports the macro as PDF
+func (s *MacroService) ExportPDF(ctx context.Context, id primitive.ObjectID) (string, error) {
+	// Get existing macro
+	macro, err := s.GetMacroByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	// Get all active processes for this macro, as the PDF service expects them
+	rawProcesses, err := s.ActiveProcesses(ctx, id)
+	if err != nil {
+		return "", err
 	}
 
 	// Generate PDF if service is available
@@ -478,29 +515,101 @@ func (s *MacroService) ValidateTaskCode(code string) bool {
 	return matched
 }
 
-// GetNextProcessNumber returns the next process number for a given macro
-func (s *MacroService) GetNextProcessNumber(ctx context.Context, macroID primitive.ObjectID) (int, error) {
-	// Find the highest process number for this macro
-	opts := options.FindOne().SetSort(bson.D{{Key: "process_code", Value: -1}})
-	var doc models.Document
-	err := s.docCollection.FindOne(ctx, bson.M{"macro_id": macroID}, opts).Decode(&doc)
+// maxProcessCodeAttempts bounds how many numbers are skipped because a
+// process was given the code manually
+const maxProcessCodeAttempts = 100
+
+// processNumberPattern extracts the number of a process code (M1_P5 -> 5)
+var processNumberPattern = regexp.MustCompile(`_P(\d+)$`)
 
+// NextProcessCode reserves and returns the next free process code of a macro,
+// in the form {macroCode}_P{n}. Numbers are never reused, trashed processes
+// included, so that a restored process keeps a unique code.
+func (s *MacroService) NextProcessCode(ctx context.Context, macroID primitive.ObjectID) (string, error) {
+	macro, err := s.GetMacroByID(ctx, macroID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			// No processes yet, start with 1
-			return 1, nil
+		return "", fmt.Errorf("failed to get macro: %w", err)
+	}
+
+	// Start after the highest existing number, the counter may not exist yet
+	// or lag behind codes given manually or by the seeder
+	highest, err := s.highestProcessNumber(ctx, macroID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.counterCollection.UpdateOne(ctx,
+		bson.M{"_id": macroID},
+		bson.M{"$max": bson.M{"sequence": highest}},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		return "", fmt.Errorf("failed to initialize process counter: %w", err)
+	}
+
+	for attempt := 0; attempt < maxProcessCodeAttempts; attempt++ {
+		var counter struct {
+			Sequence int `bson:"sequence"`
+		}
+		err := s.counterCollection.FindOneAndUpdate(ctx,
+			bson.M{"_id": macroID},
+			bson.M{"$inc": bson.M{"sequence": 1}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&counter)
+		if err != nil {
+			return "", fmt.Errorf("failed to increment process counter: %w", err)
+		}
+
+		code := fmt.Sprintf("%s_P%d", macro.Code, counter.Sequence)
+		exists, err := s.ProcessCodeExists(ctx, macroID, code, nil)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return code, nil
 		}
-		return 0, fmt.Errorf("failed to get next process number: %w", err)
 	}
 
-	// Extract number from process code (M1_P5 -> 5)
-	re := regexp.MustCompile(`_P(\d+)$`)
-	matches := re.FindStringSubmatch(doc.ProcessCode)
-	if len(matches) < 2 {
-		return 1, nil
+	return "", fmt.Errorf("failed to find a free process code")
+}
+
+// ProcessCodeExists checks whether a process of the macro, trashed ones included,
+// already uses the code. The excluded document and its revisions are ignored.
+func (s *MacroService) ProcessCodeExists(ctx context.Context, macroID primitive.ObjectID, code string, exclude *primitive.ObjectID) (bool, error) {
+	filter := bson.M{"macro_id": macroID, "process_code": code}
+	if exclude != nil {
+		filter["_id"] = bson.M{"$ne": *exclude}
+		filter["supersedes"] = bson.M{"$ne": *exclude}
+	}
+	count, err := s.docCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return false, fmt.Errorf("failed to check process code: %w", err)
+	}
+	return count > 0, nil
+}
+
+// highestProcessNumber returns the highest process number used in the macro.
+// Codes are compared numerically, M1_P10 comes after M1_P9.
+func (s *MacroService) highestProcessNumber(ctx context.Context, macroID primitive.ObjectID) (int, error) {
+	cursor, err := s.docCollection.Find(ctx,
+		bson.M{"macro_id": macroID, "process_code": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"process_code": 1}),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get process codes: %w", err)
+	}
+	var docs []models.Document
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, fmt.Errorf("failed to decode process codes: %w", err)
 	}
 
-	var lastNumber int
-	fmt.Sscanf(matches[1], "%d", &lastNumber)
-	return lastNumber + 1, nil
+	highest := 0
+	for _, doc := range docs {
+		matches := processNumberPattern.FindStringSubmatch(doc.ProcessCode)
+		if len(matches) < 2 {
+			continue
+		}
+		if number, err := strconv.Atoi(matches[1]); err == nil && number > highest {
+			highest = number
+		}
+	}
+	return highest, nil
 }
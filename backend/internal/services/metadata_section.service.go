@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var metadataSectionKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// MetadataSectionService handles admin-defined custom metadata sections
+type MetadataSectionService struct {
+	collection *mongo.Collection
+}
+
+// NewMetadataSectionService creates a new metadata section service instance
+func NewMetadataSectionService(db *DatabaseService) *MetadataSectionService {
+	collection := db.Collection("metadata_sections")
+
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}, {Key: "macro_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "order", Value: 1}},
+		},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		fmt.Printf("Warning: Failed to create metadata section indexes: %v\n", err)
+	}
+
+	return &MetadataSectionService{
+		collection: collection,
+	}
+}
+
+// Create defines a new custom metadata section
+func (s *MetadataSectionService) Create(ctx context.Context, req *models.CreateMetadataSectionRequest, createdBy primitive.ObjectID) (*models.MetadataSectionDefinition, error) {
+	if !metadataSectionKeyPattern.MatchString(req.Key) {
+		return nil, fmt.Errorf("invalid section key: use lowercase letters, digits and underscores")
+	}
+	if !req.Type.IsValid() {
+		return nil, fmt.Errorf("invalid section type: must be list, richtext or table")
+	}
+	if req.Type == models.MetadataSectionTypeTable && len(req.Columns) == 0 {
+		return nil, fmt.Errorf("table sections require at least one column")
+	}
+
+	now := time.Now()
+	section := &models.MetadataSectionDefinition{
+		Key:       req.Key,
+		Title:     req.Title,
+		Type:      req.Type,
+		Order:     req.Order,
+		IsActive:  true,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.Type == models.MetadataSectionTypeTable {
+		section.Columns = req.Columns
+	}
+	if req.MacroID != nil && *req.MacroID != "" {
+		macroID, err := primitive.ObjectIDFromHex(*req.MacroID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid macro ID format")
+		}
+		section.MacroID = &macroID
+	}
+
+	result, err := s.collection.InsertOne(ctx, section)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("metadata section with key %s already exists", req.Key)
+		}
+		return nil, fmt.Errorf("failed to create metadata section: %w", err)
+	}
+	section.ID = result.InsertedID.(primitive.ObjectID)
+
+	return section, nil
+}
+
+// GetByID retrieves a metadata section definition by ID
+func (s *MetadataSectionService) GetByID(ctx context.Context, id primitive.ObjectID) (*models.MetadataSectionDefinition, error) {
+	var section models.MetadataSectionDefinition
+	if err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&section); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("metadata section not found")
+		}
+		return nil, fmt.Errorf("failed to get metadata section: %w", err)
+	}
+	return &section, nil
+}
+
+// List returns section definitions applicable to a macro: the default template
+// sections plus those attached to the macro. A nil macro returns every section.
+func (s *MetadataSectionService) List(ctx context.Context, macroID *primitive.ObjectID, includeInactive bool) ([]models.MetadataSectionDefinition, error) {
+	filter := bson.M{}
+	if macroID != nil {
+		filter["$or"] = []bson.M{
+			{"macro_id": bson.M{"$exists": false}},
+			{"macro_id": *macroID},
+		}
+	}
+	if !includeInactive {
+		filter["is_active"] = true
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "order", Value: 1}, {Key: "created_at", Value: 1}})
+	cursor, err := s.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find metadata sections: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	sections := make([]models.MetadataSectionDefinition, 0)
+	if err := cursor.All(ctx, &sections); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata sections: %w", err)
+	}
+
+	return sections, nil
+}
+
+// Update updates a metadata section definition
+func (s *MetadataSectionService) Update(ctx context.Context, id primitive.ObjectID, req *models.UpdateMetadataSectionRequest) (*models.MetadataSectionDefinition, error) {
+	setFields := bson.M{
+		"updated_at": time.Now(),
+	}
+	if req.Title != nil {
+		setFields["title"] = *req.Title
+	}
+	if req.Columns != nil {
+		setFields["columns"] = *req.Columns
+	}
+	if req.Order != nil {
+		setFields["order"] = *req.Order
+	}
+	if req.IsActive != nil {
+		setFields["is_active"] = *req.IsActive
+	}
+
+	result := s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": setFields},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+
+	var section models.MetadataSectionDefinition
+	if err := result.Decode(&section); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("metadata section not found")
+		}
+		return nil, fmt.Errorf("failed to update metadata section: %w", err)
+	}
+
+	return &section, nil
+}
+
+// Delete removes a metadata section definition. Content already stored in
+// documents is kept so published procedures remain unchanged.
+func (s *MetadataSectionService) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete metadata section: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("metadata section not found")
+	}
+	return nil
+}
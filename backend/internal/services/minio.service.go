@@ -348,6 +348,56 @@ func (s *MinIOService) DeleteAnnexFile(ctx context.Context, fileURL string) erro
 	return nil
 }
 
+// CopyAnnexObject copies an annex object to the annex of another document and
+// returns the object key of the copy
+func (s *MinIOService) CopyAnnexObject(ctx context.Context, sourceKey string, documentID string, annexID string, fileID string) (string, error) {
+	objectKey := fmt.Sprintf("documents/%s/annexes/%s/%s%s", documentID, annexID, fileID, filepath.Ext(sourceKey))
+
+	_, err := s.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: s.bucketName, Object: objectKey},
+		minio.CopySrcOptions{Bucket: s.bucketName, Object: sourceKey},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to copy annex file: %w", err)
+	}
+
+	log.Printf("✅ Annex file copied successfully: %s -> %s", sourceKey, objectKey)
+	return objectKey, nil
+}
+
+// DeleteAnnexObject removes an annex file from MinIO by its object key
+func (s *MinIOService) DeleteAnnexObject(ctx context.Context, objectKey string) error {
+	err := s.client.RemoveObject(ctx, s.bucketName, objectKey, minio.RemoveObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to delete annex file: %w", err)
+	}
+
+	log.Printf("✅ Annex file deleted successfully: %s", objectKey)
+	return nil
+}
+
+// DeleteDocumentObjects removes every file stored under a document: annex
+// files, generated PDFs, archives and version snapshots
+func (s *MinIOService) DeleteDocumentObjects(ctx context.Context, documentID string) error {
+	prefix := fmt.Sprintf("documents/%s/", documentID)
+	for object := range s.client.ListObjects(ctx, s.bucketName, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return fmt.Errorf("failed to list document files: %w", object.Err)
+		}
+		if err := s.client.RemoveObject(ctx, s.bucketName, object.Key, minio.RemoveObjectOptions{}); err != nil {
+			return fmt.Errorf("failed to delete document file %s: %w", object.Key, err)
+		}
+	}
+
+	log.Printf("✅ Document files deleted successfully: %s", prefix)
+	return nil
+}
+
+// ObjectURL returns the public URL of an object
+func (s *MinIOService) ObjectURL(objectKey string) string {
+	return fmt.Sprintf("%s/%s/%s", s.publicURL, s.bucketName, objectKey)
+}
+
 // UploadFile uploads a generic file to MinIO
 func (s *MinIOService) UploadFile(ctx context.Context, objectKey string, reader io.Reader, size int64, contentType string) (string, error) {
 	// Upload options
@@ -370,3 +420,42 @@ func (s *MinIOService) UploadFile(ctx context.Context, objectKey string, reader
 	fileURL := fmt.Sprintf("%s/%s/%s", s.publicURL, s.bucketName, objectKey)
 	return fileURL, nil
 }
+
+// DownloadFile returns the content of a file stored in MinIO
+func (s *MinIOService) DownloadFile(ctx context.Context, fileURL string) ([]byte, error) {
+	objectKey, err := s.extractObjectKeyFromURL(fileURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract object key from URL: %w", err)
+	}
+
+	object, err := s.client.GetObject(ctx, s.bucketName, objectKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
+// DeleteFile removes a file uploaded with UploadFile from MinIO
+func (s *MinIOService) DeleteFile(ctx context.Context, fileURL string) error {
+	if fileURL == "" {
+		return nil // Nothing to delete
+	}
+
+	objectKey, err := s.extractObjectKeyFromURL(fileURL)
+	if err != nil {
+		return fmt.Errorf("failed to extract object key from URL: %w", err)
+	}
+
+	if err := s.client.RemoveObject(ctx, s.bucketName, objectKey, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	log.Printf("✅ File deleted successfully: %s", objectKey)
+	return nil
+}
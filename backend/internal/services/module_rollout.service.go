@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ModuleRolloutService manages per-department rollout of new modules
+type ModuleRolloutService struct {
+	collection *mongo.Collection
+}
+
+// NewModuleRolloutService creates a new module rollout service instance
+func NewModuleRolloutService(db *DatabaseService) *ModuleRolloutService {
+	collection := db.Collection("module_rollouts")
+
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "module", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		fmt.Printf("Warning: Failed to create module rollout indexes: %v\n", err)
+	}
+
+	return &ModuleRolloutService{
+		collection: collection,
+	}
+}
+
+// GetRollout returns the rollout configuration of a module.
+// Modules without configuration are disabled for everyone.
+func (s *ModuleRolloutService) GetRollout(ctx context.Context, module models.ModuleKey) (*models.ModuleRollout, error) {
+	var rollout models.ModuleRollout
+	err := s.collection.FindOne(ctx, bson.M{"module": module}).Decode(&rollout)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &models.ModuleRollout{
+				Module:        module,
+				DepartmentIDs: []primitive.ObjectID{},
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to get module rollout: %w", err)
+	}
+
+	return &rollout, nil
+}
+
+// ListRollouts returns the rollout configuration of every known module
+func (s *ModuleRolloutService) ListRollouts(ctx context.Context) ([]*models.ModuleRollout, error) {
+	rollouts := make([]*models.ModuleRollout, 0, len(models.KnownModules))
+	for _, module := range models.KnownModules {
+		rollout, err := s.GetRollout(ctx, module)
+		if err != nil {
+			return nil, err
+		}
+		rollouts = append(rollouts, rollout)
+	}
+	return rollouts, nil
+}
+
+// UpdateRollout creates or updates the rollout configuration of a module
+func (s *ModuleRolloutService) UpdateRollout(ctx context.Context, module models.ModuleKey, req *models.UpdateModuleRolloutRequest, updatedBy primitive.ObjectID) (*models.ModuleRollout, error) {
+	update := bson.M{
+		"updated_at": time.Now(),
+		"updated_by": updatedBy,
+	}
+
+	if req.EnabledForAll != nil {
+		update["enabled_for_all"] = *req.EnabledForAll
+	}
+	if req.Description != nil {
+		update["description"] = *req.Description
+	}
+	if req.DepartmentIDs != nil {
+		departmentIDs := make([]primitive.ObjectID, 0, len(*req.DepartmentIDs))
+		for _, idStr := range *req.DepartmentIDs {
+			id, err := primitive.ObjectIDFromHex(idStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid department ID: %s", idStr)
+			}
+			departmentIDs = append(departmentIDs, id)
+		}
+		update["department_ids"] = departmentIDs
+	}
+
+	result := s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"module": module},
+		bson.M{
+			"$set":         update,
+			"$setOnInsert": bson.M{"module": module},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	)
+
+	var rollout models.ModuleRollout
+	if err := result.Decode(&rollout); err != nil {
+		return nil, fmt.Errorf("failed to update module rollout: %w", err)
+	}
+	if rollout.DepartmentIDs == nil {
+		rollout.DepartmentIDs = []primitive.ObjectID{}
+	}
+
+	return &rollout, nil
+}
+
+// IsEnabledForUser reports whether a module is enabled for the user's department
+func (s *ModuleRolloutService) IsEnabledForUser(ctx context.Context, module models.ModuleKey, user *models.User) (bool, error) {
+	rollout, err := s.GetRollout(ctx, module)
+	if err != nil {
+		return false, err
+	}
+	return rollout.IsEnabledFor(user.DepartmentID), nil
+}
+
+// GetEnabledModules returns the modules enabled for the user's department
+func (s *ModuleRolloutService) GetEnabledModules(ctx context.Context, user *models.User) ([]models.ModuleKey, error) {
+	rollouts, err := s.ListRollouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make([]models.ModuleKey, 0)
+	for _, rollout := range rollouts {
+		if rollout.IsEnabledFor(user.DepartmentID) {
+			enabled = append(enabled, rollout.Module)
+		}
+	}
+	return enabled, nil
+}
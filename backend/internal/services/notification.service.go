@@ -19,6 +19,7 @@ type NotificationService struct {
 	firebaseService        *FirebaseService
 	deviceService          *DeviceService
 	userService            *UserService
+	digest                 *EmailDigestService // Groups the notification emails, set up by NewEmailDigestService
 }
 
 // NewNotificationService creates a new notification service
@@ -41,6 +42,9 @@ func NewNotificationService(db *DatabaseService, firebaseService *FirebaseServic
 		{
 			Keys: bson.D{{Key: "createdAt", Value: -1}},
 		},
+		{
+			Keys: bson.D{{Key: "broadcastId", Value: 1}},
+		},
 		{
 			Keys: bson.D{{Key: "expiresAt", Value: 1}},
 			Options: options.Index().SetExpireAfterSeconds(0),
@@ -91,6 +95,12 @@ func (s *NotificationService) SendNotification(ctx context.Context, req *models.
 		return nil, fmt.Errorf("no valid targets found")
 	}
 
+	// Users on an hourly or daily email frequency get it in their next digest,
+	// whether or not they have a device
+	if s.digest != nil {
+		s.digest.queue(ctx, req, targetUserIDs)
+	}
+
 	// Get devices for notification
 	devices, err := s.deviceService.GetDevicesForNotification(ctx, targetUserIDs, targetDeviceIDs)
 	if err != nil {
@@ -107,11 +117,18 @@ func (s *NotificationService) SendNotification(ctx context.Context, req *models.
 		return nil, fmt.Errorf("failed to filter devices: %w", err)
 	}
 
+	// Announcements share a broadcast ID so reactions can be aggregated
+	var broadcastID *primitive.ObjectID
+	if req.Announcement {
+		id := primitive.NewObjectID()
+		broadcastID = &id
+	}
+
 	// Create notifications for each target user
-	notifications := s.createNotifications(req, filteredDevices, senderID)
+	notifications := s.createNotifications(req, filteredDevices, senderID, broadcastID)
 
 	// Send Firebase messages
-	summary := &models.NotificationSummary{}
+	summary := &models.NotificationSummary{BroadcastID: broadcastID}
 	for _, notification := range notifications {
 		// Save notification to database first
 		result, err := s.notificationCollection.InsertOne(ctx, notification)
@@ -213,6 +230,31 @@ func (s *NotificationService) MarkAsRead(ctx context.Context, userID, notificati
 	return nil
 }
 
+// GetBroadcastID returns the announcement a user's notification belongs to
+func (s *NotificationService) GetBroadcastID(ctx context.Context, userID, notificationID primitive.ObjectID) (primitive.ObjectID, error) {
+	var notification models.Notification
+	err := s.notificationCollection.FindOne(ctx, bson.M{"_id": notificationID, "userId": userID}).Decode(&notification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return primitive.NilObjectID, models.ErrNotificationNotFound
+		}
+		return primitive.NilObjectID, fmt.Errorf("failed to get notification: %w", err)
+	}
+	if notification.BroadcastID == nil {
+		return primitive.NilObjectID, models.ErrNotAnnouncement
+	}
+	return *notification.BroadcastID, nil
+}
+
+// CountBroadcastRecipients returns how many users received an announcement
+func (s *NotificationService) CountBroadcastRecipients(ctx context.Context, broadcastID primitive.ObjectID) (int64, error) {
+	count, err := s.notificationCollection.CountDocuments(ctx, bson.M{"broadcastId": broadcastID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count broadcast recipients: %w", err)
+	}
+	return count, nil
+}
+
 // GetUserPreferences returns notification preferences for a user
 func (s *NotificationService) GetUserPreferences(ctx context.Context, userID primitive.ObjectID) (*models.NotificationPreferences, error) {
 	filter := bson.M{"userId": userID}
@@ -229,8 +271,31 @@ func (s *NotificationService) GetUserPreferences(ctx context.Context, userID pri
 	return &prefs, nil
 }
 
+// UsesEmailDigest tells whether the notification emails of a user are
+// grouped in a digest, in which case the emails tied to a notification are
+// not sent on their own
+func (s *NotificationService) UsesEmailDigest(ctx context.Context, userID primitive.ObjectID) bool {
+	if s.digest == nil {
+		return false
+	}
+	prefs, err := s.GetUserPreferences(ctx, userID)
+	if err != nil {
+		return false
+	}
+	return prefs.UsesEmailDigest()
+}
+
 // UpdateUserPreferences updates notification preferences for a user
 func (s *NotificationService) UpdateUserPreferences(ctx context.Context, userID primitive.ObjectID, req *models.UpdatePreferencesRequest) (*models.NotificationPreferences, error) {
+	if req.EmailFrequency != nil && !models.IsValidEmailFrequency(*req.EmailFrequency) {
+		return nil, models.ErrInvalidEmailFrequency
+	}
+	for event := range req.EmailEvents {
+		if !models.IsSystemEmailEvent(event) {
+			return nil, models.ErrInvalidEmailEvent
+		}
+	}
+
 	filter := bson.M{"userId": userID}
 
 	update := bson.M{
@@ -242,6 +307,12 @@ func (s *NotificationService) UpdateUserPreferences(ctx context.Context, userID
 	if req.EmailEnabled != nil {
 		update["$set"].(bson.M)["emailEnabled"] = *req.EmailEnabled
 	}
+	if req.EmailFrequency != nil {
+		update["$set"].(bson.M)["emailFrequency"] = *req.EmailFrequency
+	}
+	for event, enabled := range req.EmailEvents {
+		update["$set"].(bson.M)["emailEvents."+string(event)] = enabled
+	}
 	if req.PushEnabled != nil {
 		update["$set"].(bson.M)["pushEnabled"] = *req.PushEnabled
 	}
@@ -415,7 +486,7 @@ func (s *NotificationService) filterDevicesByPreferences(ctx context.Context, de
 	return filtered, nil
 }
 
-func (s *NotificationService) createNotifications(req *models.SendNotificationRequest, devices []*models.Device, senderID primitive.ObjectID) []*models.Notification {
+func (s *NotificationService) createNotifications(req *models.SendNotificationRequest, devices []*models.Device, senderID primitive.ObjectID, broadcastID *primitive.ObjectID) []*models.Notification {
 	now := time.Now()
 	var notifications []*models.Notification
 
@@ -437,6 +508,7 @@ func (s *NotificationService) createNotifications(req *models.SendNotificationRe
 			Data:     req.Data,
 			ImageURL: req.ImageURL,
 			Status:   models.NotificationStatusPending,
+			BroadcastID: broadcastID,
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
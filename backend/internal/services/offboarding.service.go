@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OffboardingService lists what a departing user still holds, so admins can
+// hand it over before the account is deactivated
+type OffboardingService struct {
+	documentCollection   *mongo.Collection
+	invitationCollection *mongo.Collection
+	userService          *UserService
+}
+
+// NewOffboardingService creates a new offboarding service instance
+func NewOffboardingService(db *DatabaseService, userService *UserService) *OffboardingService {
+	return &OffboardingService{
+		documentCollection:   db.Collection("documents"),
+		invitationCollection: db.Collection("invitations"),
+		userService:          userService,
+	}
+}
+
+// Report returns the documents, signatures and invitations blocking the
+// offboarding of a user, with the endpoints handing each of them over
+func (s *OffboardingService) Report(ctx context.Context, userID primitive.ObjectID) (*models.OffboardingReport, error) {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.OffboardingReport{
+		UserID:            user.ID,
+		Name:              user.FirstName + " " + user.LastName,
+		Email:             user.Email,
+		OwnedDocuments:    []models.OffboardingDocument{},
+		PendingSignatures: []models.OffboardingSignature{},
+		OpenInvitations:   []models.OffboardingInvitation{},
+	}
+
+	if err := s.collectOwnedDocuments(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := s.collectPendingSignatures(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := s.collectOpenInvitations(ctx, report); err != nil {
+		return nil, err
+	}
+
+	report.Clean = len(report.OwnedDocuments) == 0 && len(report.PendingSignatures) == 0 && len(report.OpenInvitations) == 0
+	return report, nil
+}
+
+// collectOwnedDocuments adds the documents created by the user, superseded
+// versions excepted
+func (s *OffboardingService) collectOwnedDocuments(ctx context.Context, report *models.OffboardingReport) error {
+	filter := models.NotDeleted(bson.M{
+		"created_by": report.UserID,
+		"status":     bson.M{"$ne": models.DocumentStatusSuperseded},
+	})
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetProjection(bson.M{"reference": 1, "title": 1, "status": 1, "updated_at": 1})
+
+	cursor, err := s.documentCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("failed to find owned documents: %w", err)
+	}
+	var documents []models.Document
+	if err := cursor.All(ctx, &documents); err != nil {
+		return fmt.Errorf("failed to decode owned documents: %w", err)
+	}
+
+	for _, document := range documents {
+		documentPath := "/api/documents/" + document.ID.Hex()
+		report.OwnedDocuments = append(report.OwnedDocuments, models.OffboardingDocument{
+			ID:        document.ID,
+			Reference: document.Reference,
+			Title:     document.Title,
+			Status:    document.Status,
+			UpdatedAt: document.UpdatedAt,
+			Actions: []models.OffboardingAction{
+				{Method: "POST", Href: documentPath + "/permissions", Description: "Grant access to the successor"},
+				{Method: "PUT", Href: documentPath, Description: "Reassign the contributors"},
+			},
+		})
+	}
+	return nil
+}
+
+// collectPendingSignatures adds the published documents waiting for the
+// signature of the user
+func (s *OffboardingService) collectPendingSignatures(ctx context.Context, report *models.OffboardingReport) error {
+	teams := []models.ContributorTeam{models.ContributorTeamAuthors, models.ContributorTeamVerifiers, models.ContributorTeamValidators}
+	pending := bson.M{"user_id": report.UserID, "status": models.SignatureStatusPending}
+
+	conditions := make([]bson.M, 0, len(teams))
+	for _, team := range teams {
+		conditions = append(conditions, bson.M{"contributors." + string(team): bson.M{"$elemMatch": pending}})
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetProjection(bson.M{"reference": 1, "title": 1, "status": 1, "contributors": 1})
+
+	cursor, err := s.documentCollection.Find(ctx, models.NotDeleted(bson.M{"$or": conditions}), opts)
+	if err != nil {
+		return fmt.Errorf("failed to find pending signatures: %w", err)
+	}
+	var documents []models.Document
+	if err := cursor.All(ctx, &documents); err != nil {
+		return fmt.Errorf("failed to decode pending signatures: %w", err)
+	}
+
+	for _, document := range documents {
+		for _, team := range teams {
+			for _, contributor := range document.Contributors.Team(team) {
+				if contributor.UserID != report.UserID || contributor.Status != models.SignatureStatusPending {
+					continue
+				}
+				report.PendingSignatures = append(report.PendingSignatures, models.OffboardingSignature{
+					DocumentID: document.ID,
+					Reference:  document.Reference,
+					Title:      document.Title,
+					Status:     document.Status,
+					Team:       team,
+					Actions: []models.OffboardingAction{
+						{Method: "PUT", Href: "/api/documents/" + document.ID.Hex(), Description: "Replace the contributor of the " + string(team)},
+					},
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// collectOpenInvitations adds the pending invitations sent by the user
+func (s *OffboardingService) collectOpenInvitations(ctx context.Context, report *models.OffboardingReport) error {
+	filter := bson.M{"inviter_id": report.UserID, "status": models.InvitationStatusPending}
+	opts := options.Find().SetSort(bson.D{{Key: "sent_at", Value: -1}})
+
+	cursor, err := s.invitationCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("failed to find open invitations: %w", err)
+	}
+	var invitations []models.Invitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return fmt.Errorf("failed to decode open invitations: %w", err)
+	}
+
+	for _, invitation := range invitations {
+		if invitation.IsExpired() {
+			continue
+		}
+		report.OpenInvitations = append(report.OpenInvitations, models.OffboardingInvitation{
+			ID:           invitation.ID,
+			DocumentID:   invitation.DocumentID,
+			InvitedEmail: invitation.InvitedEmail,
+			Team:         invitation.Team,
+			ExpiresAt:    invitation.ExpiresAt,
+			Actions: []models.OffboardingAction{
+				{Method: "DELETE", Href: "/api/invitations/" + invitation.ID.Hex() + "/cancel", Description: "Cancel the invitation"},
+			},
+		})
+	}
+	return nil
+}
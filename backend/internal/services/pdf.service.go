@@ -1,53 +1,129 @@
 package services
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"net/http"
 	"strings"
 	"time"
 
-	"github.com/chromedp/cdproto/page"
-	"github.com/chromedp/chromedp"
 	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// defaultPDFRenderTimeout bounds the rendering of a PDF by the renderer
+const defaultPDFRenderTimeout = 30 * time.Second
+
+// pdfRenderTimeoutKey carries a longer render timeout for background jobs
+type pdfRenderTimeoutKey struct{}
+
+// withPDFRenderTimeout overrides the render timeout of the PDFs generated with ctx
+func withPDFRenderTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, pdfRenderTimeoutKey{}, timeout)
+}
+
+// pdfProgressKey carries the listener of the stages of a PDF export
+type pdfProgressKey struct{}
+
+// WithPDFProgress reports the stages of the PDFs generated with ctx to onStage
+func WithPDFProgress(ctx context.Context, onStage func(models.PDFExportStage)) context.Context {
+	return context.WithValue(ctx, pdfProgressKey{}, onStage)
+}
+
+// reportPDFProgress notifies the listener of the export, if any, of a new stage
+func reportPDFProgress(ctx context.Context, stage models.PDFExportStage) {
+	if onStage, ok := ctx.Value(pdfProgressKey{}).(func(models.PDFExportStage)); ok {
+		onStage(stage)
+	}
+}
+
 type PDFService struct {
-	minioService  *MinIOService
-	openaiService *OpenAIService
+	signatureCollection     *mongo.Collection // Signatures captured on the documents
+	userSignatureCollection *mongo.Collection // Saved signatures of the users
+	minioService            *MinIOService
+	openaiService           *OpenAIService
+	brandingService         *BrandingService
+	templateService         *PDFTemplateService // Corporate layouts replacing the built-in ones
+	templates               *TemplateCache      // Parsed document, macro and comments report templates
+	renderPool              *PDFRenderPool      // HTML to PDF engine set by PDF_RENDERER, with bounded concurrency
 }
 
-func NewPDFService(minioService *MinIOService, openaiService *OpenAIService) *PDFService {
-	return &PDFService{
-		minioService:  minioService,
-		openaiService: openaiService,
+func NewPDFService(db *DatabaseService, minioService *MinIOService, openaiService *OpenAIService, brandingService *BrandingService, templateService *PDFTemplateService) *PDFService {
+	service := &PDFService{
+		signatureCollection:     db.Collection("signatures"),
+		userSignatureCollection: db.Collection("user_signatures"),
+		minioService:            minioService,
+		openaiService:           openaiService,
+		brandingService:         brandingService,
+		templateService:         templateService,
+		templates:               NewTemplateCache(16),
+		renderPool:              NewPDFRenderPool(NewPDFRenderer()),
 	}
+	if brandingService != nil {
+		brandingService.OnChange(service.templates.Invalidate)
+	}
+	return service
 }
 
 // GenerateDocumentPDF generates a PDF for a document and uploads it to MinIO
-func (s *PDFService) GenerateDocumentPDF(ctx context.Context, document *models.Document) (string, error) {
+func (s *PDFService) GenerateDocumentPDF(ctx context.Context, document *models.Document, opts models.PDFExportOptions) (string, error) {
 	fmt.Printf("📄 [PDF] Generating PDF for document: %s (%s)\n", document.Title, document.Reference)
 
+	// The export can print on another paper than the one of the document
+	if opts.PageLayout != nil {
+		withLayout := *document
+		withLayout.PageLayout = document.PageLayout.Override(opts.PageLayout)
+		document = &withLayout
+	}
+
 	// Generate HTML from template
-	html, err := s.renderDocumentHTML(document)
+	reportPDFProgress(ctx, models.PDFExportStageRenderingHTML)
+	branding := s.brandingService.Get(ctx)
+	html, err := s.renderDocumentLayout(ctx, branding, document)
 	if err != nil {
 		return "", fmt.Errorf("failed to render HTML: %w", err)
 	}
+	if opts.Watermark && !document.Status.IsPublished() {
+		html = insertBeforeBodyEnd(html, draftWatermarkHTML)
+	}
 	fmt.Printf("📄 [PDF] Generated HTML length: %d bytes\n", len(html))
 
-	// Convert HTML to PDF using chromedp
-	pdfBytes, err := s.htmlToPDF(ctx, html)
+	// Convert HTML to PDF, archival exports as PDF/A with embedded metadata
+	renderOpts := PDFRenderOptions{Page: document.PageLayout}
+	if opts.Archival {
+		renderOpts.PDFA = pdfaConformance()
+		renderOpts.Metadata = archivalMetadata(document, branding)
+	}
+	pdfBytes, err := s.htmlToPDF(ctx, html, renderOpts)
 	if err != nil {
 		return "", fmt.Errorf("failed to convert HTML to PDF: %w", err)
 	}
 	fmt.Printf("📄 [PDF] Generated PDF size: %d bytes\n", len(pdfBytes))
 
+	folder := "pdf"
+	if opts.Archival {
+		if err := validateArchivalPDF(pdfBytes, renderOpts.PDFA, document); err != nil {
+			return "", err
+		}
+		fmt.Printf("📄 [PDF] Validated %s archive\n", renderOpts.PDFA)
+		folder = "archive"
+	}
+	if opts.Snapshot {
+		folder = "versions"
+	}
+
 	// Upload PDF to MinIO
+	reportPDFProgress(ctx, models.PDFExportStageUploading)
 	fileName := fmt.Sprintf("%s_%s_v%s.pdf", document.Reference, time.Now().Format("20060102_150405"), document.Version)
-	objectPath := fmt.Sprintf("documents/%s/pdf/%s", document.ID.Hex(), fileName)
+	objectPath := fmt.Sprintf("documents/%s/%s/%s", document.ID.Hex(), folder, fileName)
 
 	pdfURL, err := s.minioService.UploadFile(ctx, objectPath, bytes.NewReader(pdfBytes), int64(len(pdfBytes)), "application/pdf")
 	if err != nil {
@@ -56,8 +132,9 @@ func (s *PDFService) GenerateDocumentPDF(ctx context.Context, document *models.D
 
 	fmt.Printf("✅ [PDF] PDF generated and uploaded: %s\n", pdfURL)
 
-	// Upload PDF to OpenAI for assistant training
-	if s.openaiService != nil {
+	// Upload PDF to OpenAI for assistant training, archives and version
+	// snapshots duplicate the regular PDF
+	if s.openaiService != nil && !opts.Archival && !opts.Snapshot {
 		fmt.Printf("📤 [PDF] Uploading to OpenAI for assistant training...\n")
 		err = s.openaiService.UploadDocumentFromReader(ctx, bytes.NewReader(pdfBytes), fileName, document.ID.Hex())
 		if err != nil {
@@ -71,68 +148,109 @@ func (s *PDFService) GenerateDocumentPDF(ctx context.Context, document *models.D
 	return pdfURL, nil
 }
 
-// htmlToPDF converts HTML to PDF using headless Chrome
-func (s *PDFService) htmlToPDF(ctx context.Context, html string) ([]byte, error) {
+// GenerateDocumentsArchive generates the PDF of each document, bundles them
+// into a ZIP archive uploaded to MinIO and returns its URL. Documents whose PDF
+// cannot be generated are left out of the archive and reported as skipped.
+func (s *PDFService) GenerateDocumentsArchive(ctx context.Context, documents []*models.Document, objectPath string) (string, []models.BulkExportSkipped, error) {
+	fmt.Printf("📦 [PDF] Generating archive of %d document(s)\n", len(documents))
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	skipped := make([]models.BulkExportSkipped, 0)
+	added := 0
+	branding := s.brandingService.Get(ctx)
+
+	for _, document := range documents {
+		if err := ctx.Err(); err != nil {
+			return "", nil, err
+		}
+
+		html, err := s.renderDocumentLayout(ctx, branding, document)
+		if err == nil {
+			var pdfBytes []byte
+			if pdfBytes, err = s.htmlToPDF(ctx, html, PDFRenderOptions{Page: document.PageLayout}); err == nil {
+				err = addArchiveFile(archive, archiveFileName(document), pdfBytes)
+			}
+		}
+		if err != nil {
+			fmt.Printf("⚠️  [PDF] Skipping %s in archive: %v\n", document.Reference, err)
+			skipped = append(skipped, models.BulkExportSkipped{
+				DocumentID: document.ID.Hex(),
+				Reference:  document.Reference,
+				Reason:     err.Error(),
+			})
+			continue
+		}
+		added++
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", nil, fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	if added == 0 {
+		return "", skipped, fmt.Errorf("no PDF could be generated")
+	}
+
+	archiveURL, err := s.minioService.UploadFile(ctx, objectPath, bytes.NewReader(buf.Bytes()), int64(buf.Len()), "application/zip")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to upload archive: %w", err)
+	}
+
+	fmt.Printf("✅ [PDF] Archive generated and uploaded: %s (%d bytes)\n", archiveURL, buf.Len())
+	return archiveURL, skipped, nil
+}
+
+// archiveFileName returns the name of a document PDF inside an export archive
+func archiveFileName(document *models.Document) string {
+	name := strings.NewReplacer("/", "-", "\\", "-").Replace(document.Reference)
+	if name == "" {
+		name = document.ID.Hex()
+	}
+	return fmt.Sprintf("%s_v%s.pdf", name, document.Version)
+}
+
+// addArchiveFile writes a file into a ZIP archive
+func addArchiveFile(archive *zip.Writer, name string, content []byte) error {
+	w, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := w.Write(content); err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+// htmlToPDF converts HTML to PDF with the configured renderer
+func (s *PDFService) htmlToPDF(ctx context.Context, html string, opts PDFRenderOptions) ([]byte, error) {
 	// Replace external URLs with internal Docker network URLs for image access
 	// http://localhost/files -> http://minio:9000/process-documents
 	html = strings.ReplaceAll(html, "http://localhost/files/process-documents", "http://minio:9000/process-documents")
 
 	fmt.Printf("📄 [PDF] Replaced external URLs with internal MinIO URLs\n")
 
-	// Create allocator options for headless Chrome
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.DisableGPU,
-		chromedp.NoDefaultBrowserCheck,
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("no-sandbox", true),
-	)
-
-	// Create context with allocator
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
-
-	// Create browser context
-	browserCtx, cancel := chromedp.NewContext(allocCtx)
-	defer cancel()
-
-	// Set a timeout for PDF generation
-	browserCtx, cancel = context.WithTimeout(browserCtx, 30*time.Second)
-	defer cancel()
-
-	var pdfBuf []byte
-
-	// Use base64 encoding for data URL to preserve CSS and avoid encoding issues
-	encodedHTML := base64.StdEncoding.EncodeToString([]byte(html))
-	dataURL := "data:text/html;charset=utf-8;base64," + encodedHTML
-
-	fmt.Printf("📄 [PDF] Data URL length: %d bytes\n", len(dataURL))
-
-	// Navigate to the data URL and wait for rendering, then print to PDF
-	if err := chromedp.Run(browserCtx,
-		chromedp.Navigate(dataURL),
-		chromedp.WaitReady("body"),
-		chromedp.Sleep(2*time.Second), // Give time for CSS, images, and SVG rendering
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			var err error
-			pdfBuf, _, err = page.PrintToPDF().
-				WithPrintBackground(true).
-				WithDisplayHeaderFooter(false).
-				WithPreferCSSPageSize(true). // Use CSS @page rules
-				Do(ctx)
-			return err
-		}),
-	); err != nil {
-		return nil, err
+	// The pool bounds the concurrent renders and applies the render timeout,
+	// background jobs allow more time
+	pdfBuf, err := s.renderPool.Render(ctx, html, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s renderer: %w", s.renderPool.renderer.Name(), err)
 	}
-
 	return pdfBuf, nil
 }
 
+// RenderStats returns the queue depth and the render durations of the PDFs
+func (s *PDFService) RenderStats() *models.PDFRenderStats {
+	return s.renderPool.Stats()
+}
+
 // RenderDocumentHTML renders the document as HTML using template (public method)
 // This is used both for PDF generation and direct HTML view
 func (s *PDFService) RenderDocumentHTML(ctx context.Context, document *models.Document) (string, error) {
-	return s.renderDocumentHTML(document)
+	return s.renderDocumentLayout(ctx, s.brandingService.Get(ctx), document)
 }
 
 // GenerateMacroPDF generates a PDF for a macro and uploads it to MinIO
@@ -140,14 +258,14 @@ func (s *PDFService) GenerateMacroPDF(ctx context.Context, macro *models.Macro,
 	fmt.Printf("📄 [PDF] Generating PDF for macro: %s (%s)\n", macro.Name, macro.Code)
 
 	// Generate HTML from template
-	html, err := s.renderMacroHTML(macro, processes)
+	html, err := s.renderMacroLayout(ctx, s.brandingService.Get(ctx), macro, processes)
 	if err != nil {
 		return "", fmt.Errorf("failed to render HTML: %w", err)
 	}
 	fmt.Printf("📄 [PDF] Generated HTML length: %d bytes\n", len(html))
 
-	// Convert HTML to PDF using chromedp
-	pdfBytes, err := s.htmlToPDF(ctx, html)
+	// Convert HTML to PDF
+	pdfBytes, err := s.htmlToPDF(ctx, html, PDFRenderOptions{})
 	if err != nil {
 		return "", fmt.Errorf("failed to convert HTML to PDF: %w", err)
 	}
@@ -169,300 +287,351 @@ func (s *PDFService) GenerateMacroPDF(ctx context.Context, macro *models.Macro,
 
 // RenderMacroHTML renders the macro as HTML using template (public method)
 func (s *PDFService) RenderMacroHTML(ctx context.Context, macro *models.Macro, processes []models.Document) (string, error) {
-	return s.renderMacroHTML(macro, processes)
+	return s.renderMacroLayout(ctx, s.brandingService.Get(ctx), macro, processes)
 }
 
-// getFloat64 safely extracts a float64 value from a map, handling different numeric types
-func getFloat64(m map[string]interface{}, key string) float64 {
-	if val, ok := m[key]; ok {
-		switch v := val.(type) {
-		case float64:
-			return v
-		case float32:
-			return float64(v)
-		case int:
-			return float64(v)
-		case int32:
-			return float64(v)
-		case int64:
-			return float64(v)
+// pdfTemplateFuncs are the helpers available to the PDF templates. branding is
+// bound to the organization branding when a template is parsed.
+var pdfTemplateFuncs = template.FuncMap{
+	"branding": func() *models.Branding { return nil },
+	"formatDate": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
 		}
-	}
-	return 0
+		return t.Format("02/01/2006")
+	},
+	"formatDateTime": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("02/01/2006 15:04")
+	},
+	"formatPtrDate": func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.Format("02/01/2006")
+	},
+	"formatPtrDateTime": func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.Format("02/01/2006 15:04")
+	},
+	"documentQR": documentQRCode,
+	"signatureSrc": func(src string) template.URL {
+		// Only set from signatureImageSrc, which keeps image URLs and data URLs
+		return template.URL(src)
+	},
+	"getContributorStatus": func(status models.SignatureStatus) string {
+		switch status {
+		case models.SignatureStatusPending:
+			return "En attente"
+		case models.SignatureStatusSigned:
+			return "Signé"
+		case models.SignatureStatusJoined:
+			return "Rejoint"
+		default:
+			return string(status)
+		}
+	},
+	"getCommentStatus": func(status models.CommentStatus) string {
+		switch status {
+		case models.CommentStatusOpen:
+			return "Ouvert"
+		case models.CommentStatusResolved:
+			return "Résolu"
+		default:
+			return string(status)
+		}
+	},
+	"renderDiagramSVG": func(shapes interface{}) template.HTML {
+		return template.HTML(renderShapesToSVG(shapes))
+	},
 }
 
-// renderShapesToSVG converts diagram shapes to SVG markup
-func renderShapesToSVG(shapes interface{}) string {
-	fmt.Printf("🎨 [SVG] Rendering shapes, type: %T\n", shapes)
-
-	var shapesList []interface{}
+// renderTemplate executes a PDF template with the given branding. Templates
+// are parsed once per branding, which only changes when an administrator
+// updates it.
+func (s *PDFService) renderTemplate(name, source string, branding *models.Branding, data interface{}) (string, error) {
+	bound := *branding
+	fingerprint, err := json.Marshal(bound)
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint branding: %w", err)
+	}
 
-	// Handle different types
-	switch v := shapes.(type) {
-	case []interface{}:
-		shapesList = v
-	case primitive.A:
-		// MongoDB BSON array type
-		shapesList = []interface{}(v)
-	default:
-		fmt.Printf("❌ [SVG] Unsupported type: %T\n", shapes)
-		return fmt.Sprintf(`<div style="border: 2px dashed #ddd; padding: 20px; text-align: center; color: #666;">
-			<p>Type error: %T</p>
-		</div>`, shapes)
+	tmpl, err := s.templates.Get(templateKey(name, string(fingerprint)), func() (*template.Template, error) {
+		return parsePDFTemplate(name, source, &bound)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	if len(shapesList) == 0 {
-		return `<div style="border: 2px dashed #ddd; padding: 20px; text-align: center; color: #666;">
-			<p>No shapes in diagram</p>
-		</div>`
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	fmt.Printf("✅ [SVG] Found %d shapes to render\n", len(shapesList))
+	return buf.String(), nil
+}
 
-	// Calculate bounding box
-	var minX, minY, maxX, maxY float64
-	minX, minY = 1000000, 1000000
-	maxX, maxY = 0, 0
+// parsePDFTemplate parses a PDF template bound to the given branding
+func parsePDFTemplate(name, source string, branding *models.Branding) (*template.Template, error) {
+	return template.New(name).Funcs(pdfTemplateFuncs).Funcs(template.FuncMap{
+		"branding": func() *models.Branding { return branding },
+	}).Parse(source)
+}
 
-	for _, shape := range shapesList {
-		var shapeMap map[string]interface{}
+// layout returns the name and source of the template laying out the PDFs of
+// the kind created by a user, the built-in layout when no template applies
+func (s *PDFService) layout(ctx context.Context, kind models.PDFTemplateKind, creatorID primitive.ObjectID, builtin string) (string, string) {
+	if s.templateService == nil {
+		return string(kind), builtin
+	}
+	tmpl, err := s.templateService.Resolve(ctx, kind, creatorID)
+	if err != nil {
+		fmt.Printf("⚠️  [PDF] Failed to resolve %s template, using the built-in layout: %v\n", kind, err)
+		return string(kind), builtin
+	}
+	if tmpl == nil {
+		return string(kind), builtin
+	}
+	// The update date is part of the name so an edited template is parsed again
+	return fmt.Sprintf("%s:%s:%d", kind, tmpl.ID.Hex(), tmpl.UpdatedAt.UnixNano()), tmpl.Source
+}
 
-		// Handle different map types
-		switch v := shape.(type) {
-		case map[string]interface{}:
-			shapeMap = v
-		case primitive.M:
-			shapeMap = map[string]interface{}(v)
-		case primitive.D:
-			shapeMap = v.Map()
-		default:
-			continue
-		}
+// renderDocumentLayout renders the document as HTML with the template of its
+// creator's department or organization
+func (s *PDFService) renderDocumentLayout(ctx context.Context, branding *models.Branding, document *models.Document) (string, error) {
+	name, source := s.layout(ctx, models.PDFTemplateKindDocument, document.CreatedBy, documentHTMLTemplate)
+	html, err := s.renderTemplate(name, source, branding, s.withSignatureImages(ctx, document))
+	if err != nil {
+		return "", err
+	}
+	if label := document.Classification.Label(); label != "" {
+		html = insertBeforeBodyEnd(html, fmt.Sprintf(classificationBannerHTML, template.HTMLEscapeString(label)))
+	}
+	if document.PageLayout != nil {
+		width, _ := document.PageLayout.Dimensions()
+		html = insertBeforeBodyEnd(html, fmt.Sprintf(pageLayoutHTML, document.PageLayout.CSSSize(), width))
+	}
+	return html, nil
+}
 
-		x := getFloat64(shapeMap, "x")
-		y := getFloat64(shapeMap, "y")
-		width := getFloat64(shapeMap, "width")
-		height := getFloat64(shapeMap, "height")
+// withSignatureImages returns a copy of the document whose signed contributors
+// carry their captured signature, the one given when signing or else the
+// saved signature of the user. Missing signatures leave the cells empty.
+func (s *PDFService) withSignatureImages(ctx context.Context, document *models.Document) *models.Document {
+	if s.signatureCollection == nil {
+		return document
+	}
 
-		if x < minX {
-			minX = x
+	type signer struct {
+		userID primitive.ObjectID
+		team   models.ContributorTeam
+	}
+	teams := map[models.SignatureType]models.ContributorTeam{
+		models.SignatureTypeAuthor:    models.ContributorTeamAuthors,
+		models.SignatureTypeVerifier:  models.ContributorTeamVerifiers,
+		models.SignatureTypeValidator: models.ContributorTeamValidators,
+	}
+
+	// Latest signature of each contributor, the documents keep the ones not
+	// invalidated by a rejection
+	images := make(map[signer]string)
+	cursor, err := s.signatureCollection.Find(ctx,
+		models.ActiveSignatures(bson.M{"document_id": document.ID}),
+		options.Find().SetSort(bson.D{{Key: "signed_at", Value: 1}}),
+	)
+	if err != nil {
+		fmt.Printf("⚠️  [PDF] Failed to load signatures of %s: %v\n", document.Reference, err)
+		return document
+	}
+	var signatures []models.Signature
+	if err := cursor.All(ctx, &signatures); err != nil {
+		fmt.Printf("⚠️  [PDF] Failed to decode signatures of %s: %v\n", document.Reference, err)
+		return document
+	}
+	for _, signature := range signatures {
+		if src := signatureImageSrc(signature.SignatureData); src != "" {
+			images[signer{signature.UserID, teams[signature.Type]}] = src
 		}
-		if y < minY {
-			minY = y
+	}
+
+	// Saved signatures of the signers without a captured image
+	missing := make([]primitive.ObjectID, 0)
+	for _, team := range []models.ContributorTeam{models.ContributorTeamAuthors, models.ContributorTeamVerifiers, models.ContributorTeamValidators} {
+		for _, contributor := range document.Contributors.Team(team) {
+			if contributor.Status == models.SignatureStatusSigned && images[signer{contributor.UserID, team}] == "" {
+				missing = append(missing, contributor.UserID)
+			}
 		}
-		if x+width > maxX {
-			maxX = x + width
+	}
+	saved := make(map[primitive.ObjectID]string)
+	if len(missing) > 0 {
+		cursor, err := s.userSignatureCollection.Find(ctx, bson.M{
+			"user_id": bson.M{"$in": missing},
+			"type":    bson.M{"$in": []models.UserSignatureType{models.UserSignatureTypeImage, models.UserSignatureTypeDrawn}},
+		})
+		if err == nil {
+			var userSignatures []models.UserSignature
+			if err = cursor.All(ctx, &userSignatures); err == nil {
+				for _, userSignature := range userSignatures {
+					saved[userSignature.UserID] = signatureImageSrc(userSignature.Data)
+				}
+			}
 		}
-		if y+height > maxY {
-			maxY = y + height
+		if err != nil {
+			fmt.Printf("⚠️  [PDF] Failed to load saved signatures of %s: %v\n", document.Reference, err)
 		}
+	}
 
-		// Check for arrows
-		endX := getFloat64(shapeMap, "endX")
-		endY := getFloat64(shapeMap, "endY")
-		if endX > 0 {
-			if endX > maxX {
-				maxX = endX
-			}
-			if endX < minX {
-				minX = endX
-			}
+	withImages := func(team models.ContributorTeam, contributors []models.Contributor) []models.Contributor {
+		if contributors == nil {
+			return nil
 		}
-		if endY > 0 {
-			if endY > maxY {
-				maxY = endY
+		result := make([]models.Contributor, len(contributors))
+		copy(result, contributors)
+		for i := range result {
+			if result[i].Status != models.SignatureStatusSigned {
+				continue
 			}
-			if endY < minY {
-				minY = endY
+			if src := images[signer{result[i].UserID, team}]; src != "" {
+				result[i].SignatureImage = src
+			} else {
+				result[i].SignatureImage = saved[result[i].UserID]
 			}
 		}
+		return result
 	}
 
-	// Add padding
-	padding := 20.0
-	viewBoxX := minX - padding
-	viewBoxY := minY - padding
-	viewBoxWidth := maxX - minX + 2*padding
-	viewBoxHeight := maxY - minY + 2*padding
-
-	svg := fmt.Sprintf(`<svg viewBox="%.1f %.1f %.1f %.1f" style="max-width: 100%%; height: auto; border: 1px solid #ddd; background: white;" xmlns="http://www.w3.org/2000/svg">`,
-		viewBoxX, viewBoxY, viewBoxWidth, viewBoxHeight)
-
-	// Render each shape
-	for _, shape := range shapesList {
-		var shapeMap map[string]interface{}
-
-		// Handle different map types
-		switch v := shape.(type) {
-		case map[string]interface{}:
-			shapeMap = v
-		case primitive.M:
-			shapeMap = map[string]interface{}(v)
-		case primitive.D:
-			shapeMap = v.Map()
-		default:
-			fmt.Printf("⚠️  [SVG] Unknown shape type: %T\n", shape)
-			continue
-		}
+	signed := *document
+	signed.Contributors = models.Contributors{
+		Authors:    withImages(models.ContributorTeamAuthors, document.Contributors.Authors),
+		Verifiers:  withImages(models.ContributorTeamVerifiers, document.Contributors.Verifiers),
+		Validators: withImages(models.ContributorTeamValidators, document.Contributors.Validators),
+	}
+	return &signed
+}
 
-		shapeType, _ := shapeMap["type"].(string)
-		color, _ := shapeMap["color"].(string)
-
-		// Handle numeric values that might be different types
-		x := getFloat64(shapeMap, "x")
-		y := getFloat64(shapeMap, "y")
-		width := getFloat64(shapeMap, "width")
-		height := getFloat64(shapeMap, "height")
-
-		switch shapeType {
-		case "rectangle":
-			svg += fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="#000" stroke-width="2"/>`,
-				x, y, width, height, color)
-
-		case "circle":
-			radius := width / 2
-			svg += fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="#000" stroke-width="2"/>`,
-				x+radius, y+radius, radius, color)
-
-		case "hexagon":
-			// Simple hexagon approximation
-			cx := x + width/2
-			cy := y + height/2
-			w := width / 2
-			h := height / 2
-			svg += fmt.Sprintf(`<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s" stroke="#000" stroke-width="2"/>`,
-				cx, cy-h, cx+w, cy-h/2, cx+w, cy+h/2, cx, cy+h, cx-w, cy+h/2, cx-w, cy-h/2, color)
-
-		case "arrow":
-			endX := getFloat64(shapeMap, "endX")
-			endY := getFloat64(shapeMap, "endY")
-			svg += fmt.Sprintf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2" marker-end="url(#arrowhead)"/>`,
-				x, y, endX, endY, color)
-		}
+// signatureImageSrc returns the image source of stored signature data: an
+// image URL, a data URL or raw base64 image content. Other data, such as a
+// signature hash, has no image.
+func signatureImageSrc(data string) string {
+	data = strings.TrimSpace(data)
+	switch {
+	case data == "":
+		return ""
+	case strings.HasPrefix(data, "data:image/"), strings.HasPrefix(data, "http://"), strings.HasPrefix(data, "https://"):
+		return data
 	}
+	content, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return ""
+	}
+	if contentType := http.DetectContentType(content); strings.HasPrefix(contentType, "image/") {
+		return "data:" + contentType + ";base64," + data
+	}
+	return ""
+}
+
+// draftWatermarkHTML stamps every page of a document not approved yet
+const draftWatermarkHTML = `<div style="position: fixed; top: 50%; left: 50%; transform: translate(-50%, -50%) rotate(-45deg); font-size: 72pt; font-weight: bold; color: rgba(200, 0, 0, 0.12); white-space: nowrap; pointer-events: none; z-index: 2000;">BROUILLON / DRAFT</div>`
 
-	// Add arrowhead marker definition
-	svg = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="` + fmt.Sprintf("%.1f %.1f %.1f %.1f", viewBoxX, viewBoxY, viewBoxWidth, viewBoxHeight) + `" style="max-width: 100%; height: auto; border: 1px solid #ddd; background: white;">
-		<defs>
-			<marker id="arrowhead" markerWidth="10" markerHeight="10" refX="9" refY="3" orient="auto">
-				<polygon points="0 0, 10 3, 0 6" fill="#000"/>
-			</marker>
-		</defs>` + svg[strings.Index(svg, ">")+1:]
+// classificationBannerHTML prints the confidentiality level above the footer
+// of every page, the body keeps room for it
+const classificationBannerHTML = `<style>@media print { body { padding-bottom: 26mm !important; } }</style>
+<div style="position: fixed; bottom: 19mm; left: 0; right: 0; text-align: center; font-size: 8pt; font-weight: bold; letter-spacing: 1px; color: #b00020; z-index: 1001;">%s</div>`
 
-	svg += "</svg>"
-	return svg
+// pageLayoutHTML replaces the A4 portrait paper of the layouts with the paper
+// of the document, the body spanning the width of the page
+const pageLayoutHTML = `<style>@page { size: %s; } body { width: %gmm !important; }</style>`
+
+// insertBeforeBodyEnd adds markup at the end of the body of a rendered page
+func insertBeforeBodyEnd(html, markup string) string {
+	if i := strings.LastIndex(html, "</body>"); i >= 0 {
+		return html[:i] + markup + html[i:]
+	}
+	return html + markup
 }
 
-// renderDocumentHTML renders the document as HTML using template (private helper)
-func (s *PDFService) renderDocumentHTML(document *models.Document) (string, error) {
-	tmpl, err := template.New("document").Funcs(template.FuncMap{
-		"formatDate": func(t time.Time) string {
-			if t.IsZero() {
-				return ""
-			}
-			return t.Format("02/01/2006")
-		},
-		"formatDateTime": func(t time.Time) string {
-			if t.IsZero() {
-				return ""
-			}
-			return t.Format("02/01/2006 15:04")
-		},
-		"formatPtrDate": func(t *time.Time) string {
-			if t == nil || t.IsZero() {
-				return ""
-			}
-			return t.Format("02/01/2006")
-		},
-		"getContributorStatus": func(status models.SignatureStatus) string {
-			switch status {
-			case models.SignatureStatusPending:
-				return "En attente"
-			case models.SignatureStatusSigned:
-				return "Signé"
-			case models.SignatureStatusJoined:
-				return "Rejoint"
-			default:
-				return string(status)
-			}
-		},
-		"renderDiagramSVG": func(shapes interface{}) template.HTML {
-			return template.HTML(renderShapesToSVG(shapes))
-		},
-	}).Parse(documentHTMLTemplate)
+// renderMacroLayout renders the macro as HTML with the template of its
+// creator's department or organization
+func (s *PDFService) renderMacroLayout(ctx context.Context, branding *models.Branding, macro *models.Macro, processes []models.Document) (string, error) {
+	name, source := s.layout(ctx, models.PDFTemplateKindMacro, macro.CreatedBy, macroHTMLTemplate)
+	return s.renderTemplate(name, source, branding, macroTemplateData(macro, processes))
+}
 
-	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
+// RenderTemplatePreview renders a template source, saved or not, with a
+// document or a macro and its processes. The result is the HTML, or the PDF
+// when asPDF is set. Previews are not cached.
+func (s *PDFService) RenderTemplatePreview(ctx context.Context, kind models.PDFTemplateKind, source string, document *models.Document, macro *models.Macro, processes []models.Document, asPDF bool) ([]byte, error) {
+	var data interface{}
+	switch kind {
+	case models.PDFTemplateKindDocument:
+		data = document
+	case models.PDFTemplateKindMacro:
+		data = macroTemplateData(macro, processes)
+	default:
+		return nil, fmt.Errorf("invalid pdf template: unknown kind %s", kind)
 	}
 
+	tmpl, err := parsePDFTemplate("preview", source, s.brandingService.Get(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pdf template: %w", err)
+	}
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, document); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("invalid pdf template: %w", err)
+	}
+	if !asPDF {
+		return buf.Bytes(), nil
 	}
 
-	return buf.String(), nil
+	pdfBytes, err := s.htmlToPDF(ctx, buf.String(), PDFRenderOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert HTML to PDF: %w", err)
+	}
+	return pdfBytes, nil
+}
+
+// renderDocumentHTML renders the document as HTML using template (private helper)
+func (s *PDFService) renderDocumentHTML(branding *models.Branding, document *models.Document) (string, error) {
+	return s.renderTemplate("document", documentHTMLTemplate, branding, document)
 }
 
 // renderMacroHTML renders the macro as HTML using template (private helper)
-func (s *PDFService) renderMacroHTML(macro *models.Macro, processes []models.Document) (string, error) {
-	data := struct {
+func (s *PDFService) renderMacroHTML(branding *models.Branding, macro *models.Macro, processes []models.Document) (string, error) {
+	return s.renderTemplate("macro", macroHTMLTemplate, branding, macroTemplateData(macro, processes))
+}
+
+// macroTemplateData is the data of the macro templates
+func macroTemplateData(macro *models.Macro, processes []models.Document) interface{} {
+	return struct {
 		Macro     *models.Macro
 		Processes []models.Document
 	}{
 		Macro:     macro,
 		Processes: processes,
 	}
+}
 
-	tmpl, err := template.New("macro").Funcs(template.FuncMap{
-		"formatDate": func(t time.Time) string {
-			if t.IsZero() {
-				return ""
-			}
-			return t.Format("02/01/2006")
-		},
-		"formatDateTime": func(t time.Time) string {
-			if t.IsZero() {
-				return ""
-			}
-			return t.Format("02/01/2006 15:04")
-		},
-		"formatPtrDate": func(t *time.Time) string {
-			if t == nil || t.IsZero() {
-				return ""
-			}
-			return t.Format("02/01/2006")
-		},
-		"getContributorStatus": func(status models.SignatureStatus) string {
-			switch status {
-			case models.SignatureStatusPending:
-				return "En attente"
-			case models.SignatureStatusSigned:
-				return "Signé"
-			case models.SignatureStatusJoined:
-				return "Rejoint"
-			default:
-				return string(status)
-			}
-		},
-		"renderDiagramSVG": func(shapes interface{}) template.HTML {
-			return template.HTML(renderShapesToSVG(shapes))
-		},
-	}).Parse(macroHTMLTemplate)
-
+// GenerateCommentsReportPDF renders the review comments report of a document as a PDF
+func (s *PDFService) GenerateCommentsReportPDF(ctx context.Context, report *models.CommentReport) ([]byte, error) {
+	html, err := s.renderTemplate("comments", commentsReportHTMLTemplate, s.brandingService.Get(ctx), report)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
+		return nil, err
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
+	pdfBytes, err := s.htmlToPDF(ctx, html, PDFRenderOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert HTML to PDF: %w", err)
 	}
-
-	return buf.String(), nil
+	return pdfBytes, nil
 }
 
 // documentHTMLTemplate is the HTML template for the PDF
-const documentHTMLTemplate = `
+const documentHTMLTemplate = `{{$brand := branding}}
 <!DOCTYPE html>
 <html lang="fr">
 <head>
@@ -492,9 +661,28 @@ const documentHTMLTemplate = `
 
         /* Header styling */
         .page-header {
+            display: flex;
+            justify-content: space-between;
+            align-items: flex-start;
             margin-bottom: 15px;
         }
 
+        .header-fields {
+            text-align: right;
+            font-size: 8pt;
+            line-height: 1.3;
+        }
+
+        .header-stamp {
+            display: inline-block;
+            border: 1.5px solid #b00020;
+            color: #b00020;
+            font-weight: bold;
+            padding: 1px 6px;
+            margin-bottom: 2px;
+            text-transform: uppercase;
+        }
+
         .logo-section {
             text-align: left;
         }
@@ -502,7 +690,7 @@ const documentHTMLTemplate = `
         .company-name {
             font-size: 11pt;
             font-weight: bold;
-            color: #FF9500;
+            color: {{$brand.PrimaryColor}};
             margin-bottom: 2px;
         }
 
@@ -512,6 +700,11 @@ const documentHTMLTemplate = `
             line-height: 1.2;
         }
 
+        .company-logo {
+            max-height: 12mm;
+            margin-bottom: 2px;
+        }
+
         @media print {
             .page-header {
                 position: fixed !important;
@@ -539,7 +732,7 @@ const documentHTMLTemplate = `
             height: 20mm;
             font-size: 8pt;
             color: #000;
-            border-top: 2px solid #FF9500;
+            border-top: 2px solid {{$brand.PrimaryColor}};
             padding-top: 8px;
             background-color: #fff;
             z-index: 1000;
@@ -560,7 +753,7 @@ const documentHTMLTemplate = `
                 right: -15mm !important;
                 width: calc(100% + 30mm) !important;
                 height: 18mm !important;
-                border-top: 2px solid #FF9500 !important;
+                border-top: 2px solid {{$brand.PrimaryColor}} !important;
                 background-color: #fff !important;
                 padding-top: 6px !important;
                 display: block !important;
@@ -628,7 +821,7 @@ const documentHTMLTemplate = `
 
         .footer-tagline {
             font-style: italic;
-            color: #FF9500;
+            color: {{$brand.PrimaryColor}};
             font-weight: bold;
             margin-top: 3px;
         }
@@ -749,6 +942,18 @@ const documentHTMLTemplate = `
             font-weight: normal;
         }
 
+        .title-table .title-qr {
+            width: 28mm;
+            text-align: center;
+            vertical-align: middle;
+            font-size: 7pt;
+        }
+
+        .title-qr img {
+            width: 22mm;
+            height: 22mm;
+        }
+
         /* Signature tables */
         .signature-table {
             margin: 15px 0;
@@ -765,6 +970,12 @@ const documentHTMLTemplate = `
             height: 40px;
         }
 
+        .signature-image {
+            max-height: 36px;
+            max-width: 140px;
+            object-fit: contain;
+        }
+
         /* Section headers as table rows */
         .section-header-row {
             background-color: white;
@@ -858,11 +1069,11 @@ const documentHTMLTemplate = `
         .section-title-text {
             font-size: 32pt;
             font-weight: bold;
-            color: #FF9500;
+            color: {{$brand.PrimaryColor}};
             text-transform: uppercase;
             letter-spacing: 3px;
             padding: 40px;
-            border: 4px solid #FF9500;
+            border: 4px solid {{$brand.PrimaryColor}};
             background-color: #fff;
         }
 
@@ -892,13 +1103,26 @@ const documentHTMLTemplate = `
     </style>
 </head>
 <body>
-    <!-- Header on first page -->
+    <!-- Header repeated on each page, with the header fields of the document -->
+    {{$header := .Metadata.Header}}
+    {{$logo := $brand.LogoURL}}{{if $header}}{{$logo = $brand.Logo $header.LogoVariant}}{{end}}
     <div class="page-header">
         <div class="logo-section">
-            <div class="company-name">TOGOCOM</div>
-            <div class="company-tagline">TOGOCEL | TOGO TELECOM</div>
-            <div class="company-tagline">Filiales du Groupe Togocom</div>
+            {{if $logo}}<img class="company-logo" src="{{$logo}}" alt="{{$brand.CompanyName}}">{{end}}
+            <div class="company-name">{{$brand.CompanyName}}</div>
+            {{range $brand.HeaderLines}}
+            <div class="company-tagline">{{.}}</div>
+            {{end}}
+        </div>
+        {{if $header}}
+        <div class="header-fields">
+            {{if $header.Stamp}}<div class="header-stamp">{{$header.Stamp}}</div>{{end}}
+            {{if $header.ProjectName}}<div class="header-field"><strong>Projet :</strong> {{$header.ProjectName}}</div>{{end}}
+            {{range $header.Fields}}
+            <div class="header-field"><strong>{{.Label}} :</strong> {{.Value}}</div>
+            {{end}}
         </div>
+        {{end}}
     </div>
 
     <!-- Title Table -->
@@ -906,11 +1130,29 @@ const documentHTMLTemplate = `
         <tr>
             <th>Référence:</th>
             <td>{{.Reference}} v{{.Version}}</td>
+            {{with documentQR .Reference}}
+            <td class="title-qr" rowspan="4">
+                <img src="{{.}}" alt="QR">
+                <div>Version en vigueur</div>
+            </td>
+            {{end}}
         </tr>
         <tr>
             <th>Titre de document</th>
             <td><strong>{{.Title}}</strong></td>
         </tr>
+        {{if .EffectiveDate}}
+        <tr>
+            <th>Date d'entrée en vigueur</th>
+            <td>{{formatPtrDate .EffectiveDate}}</td>
+        </tr>
+        {{end}}
+        {{if .SupersessionDate}}
+        <tr>
+            <th>Date de fin de validité</th>
+            <td>{{formatPtrDate .SupersessionDate}}</td>
+        </tr>
+        {{end}}
     </table>
 
     <!-- Contributors Signature Tables -->
@@ -928,8 +1170,8 @@ const documentHTMLTemplate = `
         <tr>
             <td>{{.Name}}</td>
             <td>{{.Title}}</td>
-            <td class="signature-cell"></td>
-            <td>{{formatPtrDate .SignatureDate}}</td>
+            <td class="signature-cell">{{if .SignatureImage}}<img class="signature-image" src="{{signatureSrc .SignatureImage}}" alt="Signature">{{end}}</td>
+            <td>{{if eq .Status "signed"}}{{formatPtrDate .SignatureDate}}{{end}}</td>
         </tr>
         {{end}}
     </table>
@@ -949,8 +1191,8 @@ const documentHTMLTemplate = `
         <tr>
             <td>{{.Name}}</td>
             <td>{{.Title}}</td>
-            <td class="signature-cell"></td>
-            <td>{{formatPtrDate .SignatureDate}}</td>
+            <td class="signature-cell">{{if .SignatureImage}}<img class="signature-image" src="{{signatureSrc .SignatureImage}}" alt="Signature">{{end}}</td>
+            <td>{{if eq .Status "signed"}}{{formatPtrDate .SignatureDate}}{{end}}</td>
         </tr>
         {{end}}
     </table>
@@ -971,8 +1213,8 @@ const documentHTMLTemplate = `
         <tr>
             <td>{{.Name}}</td>
             <td>{{.Title}}</td>
-            <td class="signature-cell"></td>
-            <td>{{formatPtrDate .SignatureDate}}</td>
+            <td class="signature-cell">{{if .SignatureImage}}<img class="signature-image" src="{{signatureSrc .SignatureImage}}" alt="Signature">{{end}}</td>
+            <td>{{if eq .Status "signed"}}{{formatPtrDate .SignatureDate}}{{end}}</td>
         </tr>
         {{end}}
     </table>
@@ -1051,6 +1293,55 @@ const documentHTMLTemplate = `
     </table>
     {{end}}
 
+    <!-- Custom Metadata Sections -->
+    {{range .Metadata.CustomSections}}
+    {{if eq .Type "table"}}
+    <table class="content-table">
+        <tr class="section-header-row">
+            <td colspan="{{len .Columns}}">{{.Title}}</td>
+        </tr>
+        <tr>
+            {{range .Columns}}
+            <th>{{.}}</th>
+            {{end}}
+        </tr>
+        {{range .Rows}}
+        <tr>
+            {{range .}}
+            <td>{{.}}</td>
+            {{end}}
+        </tr>
+        {{end}}
+    </table>
+    {{else if eq .Type "richtext"}}
+    {{if .Content}}
+    <table class="content-table">
+        <tr class="section-header-row">
+            <td>{{.Title}}</td>
+        </tr>
+        <tr>
+            <td class="rich-text-content">{{.Content}}</td>
+        </tr>
+    </table>
+    {{end}}
+    {{else if .Items}}
+    <table class="content-table">
+        <tr class="section-header-row">
+            <td>{{.Title}}</td>
+        </tr>
+        <tr>
+            <td>
+                <ul>
+                    {{range .Items}}
+                    <li>{{.}}</li>
+                    {{end}}
+                </ul>
+            </td>
+        </tr>
+    </table>
+    {{end}}
+    {{end}}
+
     <!-- Change History -->
     {{if .Metadata.ChangeHistory}}
     <table>
@@ -1143,7 +1434,7 @@ const documentHTMLTemplate = `
 
     {{range .Annexes}}
     <div class="annex-content" style="margin: 20px 0;">
-        <h3 style="color: #FF9500; margin-bottom: 10px;">{{.Title}}</h3>
+        <h3 style="color: {{$brand.PrimaryColor}}; margin-bottom: 10px;">{{.Title}}</h3>
 
         {{if eq .Type "diagram"}}
         <!-- Diagram/Image Content -->
@@ -1228,7 +1519,7 @@ const documentHTMLTemplate = `
                         <ul class="file-list" style="list-style: none; padding: 0;">
                             <li>
                                 <span class="file-icon">📎</span>
-                                <span style="color: #FF9500;">{{.OriginalName}}</span>
+                                <span style="color: {{$brand.PrimaryColor}};">{{.OriginalName}}</span>
                                 {{if .FileSize}}
                                 <span style="color: #666; font-size: 8pt;"> ({{.FileSize}} bytes)</span>
                                 {{end}}
@@ -1258,11 +1549,11 @@ const documentHTMLTemplate = `
                             <li>
                                 <span class="file-icon">📎</span>
                                 {{if index . "url"}}
-                                <a href="{{index . "url"}}" target="_blank" style="color: #FF9500; text-decoration: none;">
+                                <a href="{{index . "url"}}" target="_blank" style="color: {{$brand.PrimaryColor}}; text-decoration: none;">
                                     {{if index . "name"}}{{index . "name"}}{{else}}File{{end}}
                                 </a>
                                 {{else}}
-                                <span style="color: #FF9500;">
+                                <span style="color: {{$brand.PrimaryColor}};">
                                     {{if index . "name"}}{{index . "name"}}{{else}}File{{end}}
                                 </span>
                                 {{end}}
@@ -1286,17 +1577,17 @@ const documentHTMLTemplate = `
     <div class="page-footer">
         <div class="footer-content">
             <div class="footer-left">
-                Place de la Réconciliation – (Quartier Atchanté)<br>
-                Boîte postale : 333 – Lomé – Togo<br>
-                <span class="footer-tagline">Avancer. Pour vous. Pour Tous.</span>
+                {{range $brand.AddressLines}}{{.}}<br>{{end}}
+                <span class="footer-tagline">{{$brand.Tagline}}</span>
+                {{if $brand.FooterText}}<br>{{$brand.FooterText}}{{end}}
             </div>
             <div class="footer-center">
                 <span class="page-number"></span>
             </div>
             <div class="footer-right">
-                Téléphone : +228 22 53 44 01<br>
-                E-mail : spdgtgt@togotelecom.tg<br>
-                Site web : togocom.tg
+                {{if $brand.Phone}}Téléphone : {{$brand.Phone}}<br>{{end}}
+                {{if $brand.ContactEmail}}E-mail : {{$brand.ContactEmail}}<br>{{end}}
+                {{if $brand.Website}}Site web : {{$brand.Website}}{{end}}
             </div>
         </div>
     </div>
@@ -1320,7 +1611,7 @@ const documentHTMLTemplate = `
 `
 
 // macroHTMLTemplate is the HTML template for the Macro PDF
-const macroHTMLTemplate = `
+const macroHTMLTemplate = `{{$brand := branding}}
 <!DOCTYPE html>
 <html lang="fr">
 <head>
@@ -1356,7 +1647,7 @@ const macroHTMLTemplate = `
         .company-name {
             font-size: 11pt;
             font-weight: bold;
-            color: #FF9500;
+            color: {{$brand.PrimaryColor}};
             margin-bottom: 2px;
         }
 
@@ -1366,6 +1657,11 @@ const macroHTMLTemplate = `
             line-height: 1.2;
         }
 
+        .company-logo {
+            max-height: 12mm;
+            margin-bottom: 2px;
+        }
+
         /* Footer styling */
         .page-footer {
             position: fixed;
@@ -1376,7 +1672,7 @@ const macroHTMLTemplate = `
             height: 20mm;
             font-size: 8pt;
             color: #000;
-            border-top: 2px solid #FF9500;
+            border-top: 2px solid {{$brand.PrimaryColor}};
             padding-top: 8px;
             background-color: #fff;
             z-index: 1000;
@@ -1387,7 +1683,7 @@ const macroHTMLTemplate = `
         .footer-left { flex: 1; text-align: left; }
         .footer-center { flex: 0 0 100px; text-align: center; font-weight: bold; }
         .footer-right { flex: 1; text-align: right; }
-        .footer-tagline { font-style: italic; color: #FF9500; font-weight: bold; }
+        .footer-tagline { font-style: italic; color: {{$brand.PrimaryColor}}; font-weight: bold; }
 
         @media print {
             .page-footer {
@@ -1400,8 +1696,8 @@ const macroHTMLTemplate = `
         }
 
         /* Content Styling */
-        h1 { font-size: 24pt; color: #FF9500; margin-bottom: 20px; text-transform: uppercase; }
-        h2 { font-size: 16pt; color: #333; margin: 30px 0 15px 0; border-bottom: 1px solid #FF9500; padding-bottom: 5px; }
+        h1 { font-size: 24pt; color: {{$brand.PrimaryColor}}; margin-bottom: 20px; text-transform: uppercase; }
+        h2 { font-size: 16pt; color: #333; margin: 30px 0 15px 0; border-bottom: 1px solid {{$brand.PrimaryColor}}; padding-bottom: 5px; }
         h3 { font-size: 14pt; color: #666; margin: 20px 0 10px 0; }
         
         p { margin-bottom: 10px; text-align: justify; }
@@ -1422,7 +1718,7 @@ const macroHTMLTemplate = `
         .process-header {
             background-color: #f0f0f0;
             padding: 10px;
-            border-left: 5px solid #FF9500;
+            border-left: 5px solid {{$brand.PrimaryColor}};
             margin-bottom: 15px;
         }
 
@@ -1447,7 +1743,7 @@ const macroHTMLTemplate = `
 <body>
     <!-- Process Title Page -->
     <div style="text-align: center; padding-top: 50mm;">
-        <div style="font-size: 48pt; color: #FF9500; font-weight: bold; margin-bottom: 20px;">MACRO PROCESSUS</div>
+        <div style="font-size: 48pt; color: {{$brand.PrimaryColor}}; font-weight: bold; margin-bottom: 20px;">MACRO PROCESSUS</div>
         <div style="font-size: 24pt; font-weight: bold; margin-bottom: 10px;">{{.Macro.Code}}</div>
         <div style="font-size: 36pt; color: #333; margin-bottom: 40px;">{{.Macro.Name}}</div>
         <div style="font-size: 12pt; color: #666;">Généré le: {{formatDateTime .Macro.UpdatedAt}}</div>
@@ -1457,8 +1753,11 @@ const macroHTMLTemplate = `
 
     <!-- Header for subsequent pages -->
     <div class="page-header">
-        <div class="company-name">KG TECH</div>
-        <div class="company-tagline">Excellence opérationnelle & Innovation technologique</div>
+        {{if $brand.LogoURL}}<img class="company-logo" src="{{$brand.LogoURL}}" alt="{{$brand.CompanyName}}">{{end}}
+        <div class="company-name">{{$brand.CompanyName}}</div>
+        {{range $brand.HeaderLines}}
+        <div class="company-tagline">{{.}}</div>
+        {{end}}
     </div>
 
     <h1>Description du Macro-Processus</h1>
@@ -1521,17 +1820,155 @@ const macroHTMLTemplate = `
     <!-- Footer for all pages -->
     <div class="page-footer">
         <div class="footer-left">
-            <div>KG TECH S.A.R.L</div>
-            <div style="color: #666; font-size: 6pt;">RC: 12345 | IF: 67890 | ICE: 001122334455667</div>
+            <div>{{$brand.CompanyName}}</div>
+            {{if $brand.FooterText}}<div style="color: #666; font-size: 6pt;">{{$brand.FooterText}}</div>{{end}}
         </div>
         <div class="footer-center">
             MACRO: {{.Macro.Code}}
         </div>
         <div class="footer-right">
-            <div class="footer-tagline">Driving Business Excellence</div>
-            <div>www.kgtech.ma</div>
+            <div class="footer-tagline">{{$brand.Tagline}}</div>
+            {{if $brand.Website}}<div>{{$brand.Website}}</div>{{end}}
         </div>
     </div>
 </body>
 </html>
 `
+
+// commentsReportHTMLTemplate is the HTML template for the review comments report
+const commentsReportHTMLTemplate = `{{$brand := branding}}
+<!DOCTYPE html>
+<html lang="fr">
+<head>
+    <meta charset="UTF-8">
+    <title>Rapport de revue - {{.Reference}}</title>
+    <style>
+        @page {
+            size: A4 landscape;
+            margin: 15mm 12mm 15mm 12mm;
+        }
+
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+
+        body {
+            font-family: Arial, Helvetica, sans-serif;
+            font-size: 9pt;
+            line-height: 1.3;
+            color: #000;
+        }
+
+        .company-name {
+            font-size: 11pt;
+            font-weight: bold;
+            color: {{$brand.PrimaryColor}};
+            margin-bottom: 8px;
+        }
+
+        h1 {
+            font-size: 14pt;
+            margin-bottom: 6px;
+        }
+
+        .meta {
+            margin-bottom: 12px;
+            color: #333;
+        }
+
+        .summary {
+            margin-bottom: 12px;
+        }
+
+        .summary span {
+            display: inline-block;
+            margin-right: 15px;
+        }
+
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+
+        th, td {
+            border: 1px solid #999;
+            padding: 4px 5px;
+            vertical-align: top;
+            text-align: left;
+        }
+
+        th {
+            background-color: #f0f0f0;
+        }
+
+        tr.reply td {
+            background-color: #fafafa;
+        }
+
+        tr.reply td.content {
+            padding-left: 15px;
+        }
+
+        .content {
+            white-space: pre-wrap;
+        }
+
+        .deleted {
+            color: #888;
+            font-style: italic;
+        }
+    </style>
+</head>
+<body>
+    <div class="company-name">{{$brand.CompanyName}}</div>
+    <h1>Rapport des commentaires de revue</h1>
+    <div class="meta">
+        <div><strong>Document :</strong> {{.Reference}} - {{.Title}} (Version {{.Version}})</div>
+        <div><strong>Statut :</strong> {{.Status}}</div>
+        <div><strong>Généré le :</strong> {{formatDateTime .GeneratedAt}}{{if .GeneratedBy}} par {{.GeneratedBy}}{{end}}</div>
+    </div>
+
+    <div class="summary">
+        <span><strong>Fils :</strong> {{.Counts.Total}}</span>
+        <span><strong>Ouverts :</strong> {{.Counts.Open}}</span>
+        <span><strong>Résolus :</strong> {{.Counts.Resolved}}</span>
+        <span><strong>Bloquants ouverts :</strong> {{.Counts.BlockingOpen}}</span>
+    </div>
+
+    {{if .Entries}}
+    <table>
+        <thead>
+            <tr>
+                <th style="width: 4%;">#</th>
+                <th style="width: 12%;">Auteur</th>
+                <th style="width: 12%;">Date</th>
+                <th style="width: 12%;">Section</th>
+                <th style="width: 32%;">Commentaire</th>
+                <th style="width: 7%;">Bloquant</th>
+                <th style="width: 7%;">Statut</th>
+                <th style="width: 14%;">Résolution</th>
+            </tr>
+        </thead>
+        <tbody>
+            {{range .Entries}}
+            <tr{{if .IsReply}} class="reply"{{end}}>
+                <td>{{.ThreadNumber}}{{if .IsReply}}.r{{end}}</td>
+                <td>{{.AuthorName}}</td>
+                <td>{{formatDateTime .CreatedAt}}</td>
+                <td>{{.Section}}</td>
+                <td class="content">{{if .Deleted}}<span class="deleted">Commentaire supprimé</span>{{else}}{{.Content}}{{end}}{{if .EditCount}} <em>(modifié {{.EditCount}} fois)</em>{{end}}</td>
+                <td>{{if .IsReply}}{{else if .Blocking}}Oui{{else}}Non{{end}}</td>
+                <td>{{if not .IsReply}}{{getCommentStatus .Status}}{{end}}</td>
+                <td>{{if .ResolvedAt}}{{.ResolvedByName}}<br>{{formatPtrDateTime .ResolvedAt}}{{end}}</td>
+            </tr>
+            {{end}}
+        </tbody>
+    </table>
+    {{else}}
+    <p>Aucun commentaire de revue pour ce document.</p>
+    {{end}}
+</body>
+</html>
+`
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// ErrInvalidArchivalPDF is returned when a generated PDF/A file fails its validation
+var ErrInvalidArchivalPDF = errors.New("generated file is not a valid PDF/A archive")
+
+// pdfaLevels are the PDF/A conformance levels the renderer can produce
+var pdfaLevels = map[string]bool{"PDF/A-1b": true, "PDF/A-2b": true, "PDF/A-3b": true}
+
+// pdfaConformance returns the PDF/A level of the archival exports, set by PDFA_CONFORMANCE
+func pdfaConformance() string {
+	if v := strings.TrimSpace(os.Getenv("PDFA_CONFORMANCE")); pdfaLevels[v] {
+		return v
+	}
+	return "PDF/A-2b"
+}
+
+// archivalMetadata returns the properties embedded in the archival PDF of a
+// document, written to both its Info dictionary and its XMP packet
+func archivalMetadata(document *models.Document, branding *models.Branding) map[string]interface{} {
+	keywords := []string{document.Reference, "version " + document.Version}
+	approval := "not approved yet"
+	if document.ApprovedAt != nil {
+		approvedAt := document.ApprovedAt.Format("2006-01-02")
+		keywords = append(keywords, "approved "+approvedAt)
+		approval = "approved on " + approvedAt
+	}
+
+	contributors := make([]string, 0)
+	for _, team := range []models.ContributorTeam{models.ContributorTeamAuthors, models.ContributorTeamVerifiers, models.ContributorTeamValidators} {
+		for _, contributor := range document.Contributors.Team(team) {
+			contributors = append(contributors, fmt.Sprintf("%s (%s)", contributor.Name, team))
+		}
+	}
+
+	metadata := map[string]interface{}{
+		"Title":    fmt.Sprintf("%s - %s", document.Reference, document.Title),
+		"Subject":  fmt.Sprintf("Reference %s, version %s, %s", document.Reference, document.Version, approval),
+		"Author":   strings.Join(contributors, "; "),
+		"Keywords": keywords,
+	}
+	if branding != nil && branding.AppName != "" {
+		metadata["Creator"] = branding.AppName
+	}
+	return metadata
+}
+
+// pdfaPartPattern reads the PDF/A part and conformance from the XMP identification schema
+var (
+	pdfaPartPattern        = regexp.MustCompile(`pdfaid:part(?:="|>)\s*(\d)`)
+	pdfaConformancePattern = regexp.MustCompile(`pdfaid:conformance(?:="|>)\s*([A-Za-z])`)
+)
+
+// validateArchivalPDF checks a generated file before it is archived: it must
+// be a complete, unencrypted PDF declaring the requested PDF/A level in its
+// XMP packet, which must carry the document reference
+func validateArchivalPDF(pdfBytes []byte, conformance string, document *models.Document) error {
+	if !bytes.HasPrefix(pdfBytes, []byte("%PDF-")) {
+		return fmt.Errorf("%w: missing PDF header", ErrInvalidArchivalPDF)
+	}
+	tail := pdfBytes
+	if len(tail) > 1024 {
+		tail = tail[len(tail)-1024:]
+	}
+	if !bytes.Contains(tail, []byte("%%EOF")) {
+		return fmt.Errorf("%w: truncated file", ErrInvalidArchivalPDF)
+	}
+	if bytes.Contains(pdfBytes, []byte("/Encrypt")) {
+		return fmt.Errorf("%w: encrypted file", ErrInvalidArchivalPDF)
+	}
+
+	// The XMP packet of a PDF/A file is stored uncompressed
+	part := pdfaPartPattern.FindSubmatch(pdfBytes)
+	level := pdfaConformancePattern.FindSubmatch(pdfBytes)
+	if part == nil || level == nil {
+		return fmt.Errorf("%w: missing PDF/A identification", ErrInvalidArchivalPDF)
+	}
+	declared := fmt.Sprintf("PDF/A-%s%s", part[1], strings.ToLower(string(level[1])))
+	if declared != conformance {
+		return fmt.Errorf("%w: declares %s instead of %s", ErrInvalidArchivalPDF, declared, conformance)
+	}
+	if !bytes.Contains(pdfBytes, []byte(document.Reference)) {
+		return fmt.Errorf("%w: missing metadata", ErrInvalidArchivalPDF)
+	}
+	return nil
+}
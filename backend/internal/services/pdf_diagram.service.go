@@ -0,0 +1,491 @@
+package services
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// diagramPadding is the margin kept around the shapes of a diagram
+	diagramPadding = 20.0
+	// diagramFontSize is the size of the labels without their own
+	diagramFontSize = 14.0
+	// diagramCharWidth approximates the width of a character relative to the
+	// font size, used to wrap the labels since SVG text does not wrap
+	diagramCharWidth = 0.55
+	// swimlaneHeaderSize is the width of the band holding the swimlane name
+	swimlaneHeaderSize = 30.0
+)
+
+// getFloat64 safely extracts a float64 value from a map, handling different numeric types
+func getFloat64(m map[string]interface{}, key string) float64 {
+	if val, ok := m[key]; ok {
+		switch v := val.(type) {
+		case float64:
+			return v
+		case float32:
+			return float64(v)
+		case int:
+			return float64(v)
+		case int32:
+			return float64(v)
+		case int64:
+			return float64(v)
+		}
+	}
+	return 0
+}
+
+// getString extracts a string value from a map, numeric IDs being formatted
+func getString(m map[string]interface{}, key string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case float64, float32, int, int32, int64:
+		return strconv.FormatFloat(getFloat64(m, key), 'f', -1, 64)
+	}
+	return ""
+}
+
+// storedArray returns the items of an array stored in free-form content
+func storedArray(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case primitive.A:
+		// MongoDB BSON array type
+		return []interface{}(v), true
+	}
+	return nil, false
+}
+
+// storedObject returns the fields of an object stored in free-form content
+func storedObject(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case primitive.M:
+		return map[string]interface{}(v), true
+	case primitive.D:
+		return v.Map(), true
+	}
+	return nil, false
+}
+
+// parseDiagramShape reads a shape stored in the content of a diagram annex
+func parseDiagramShape(m map[string]interface{}) models.DiagramShape {
+	shape := models.DiagramShape{
+		ID:          getString(m, "id"),
+		Type:        models.DiagramShapeType(getString(m, "type")),
+		X:           getFloat64(m, "x"),
+		Y:           getFloat64(m, "y"),
+		Width:       getFloat64(m, "width"),
+		Height:      getFloat64(m, "height"),
+		Color:       getString(m, "color"),
+		StrokeColor: getString(m, "strokeColor"),
+		TextColor:   getString(m, "textColor"),
+		FontSize:    getFloat64(m, "fontSize"),
+		Label:       getString(m, "label"),
+		EndX:        getFloat64(m, "endX"),
+		EndY:        getFloat64(m, "endY"),
+		From:        getString(m, "from"),
+		To:          getString(m, "to"),
+		Routing:     models.ConnectorRouting(getString(m, "routing")),
+	}
+	if shape.Label == "" {
+		shape.Label = getString(m, "text")
+	}
+	// Circles are only sized by their width
+	if shape.Type == models.DiagramShapeCircle {
+		shape.Height = shape.Width
+	}
+	if points, ok := storedArray(m["points"]); ok {
+		for _, point := range points {
+			if pm, ok := storedObject(point); ok {
+				shape.Points = append(shape.Points, models.DiagramPoint{X: getFloat64(pm, "x"), Y: getFloat64(pm, "y")})
+			}
+		}
+	}
+	return shape
+}
+
+// diagramBounds is the bounding box of the shapes of a diagram
+type diagramBounds struct {
+	minX, minY, maxX, maxY float64
+	set                    bool
+}
+
+func (b *diagramBounds) add(x, y float64) {
+	if !b.set {
+		b.minX, b.minY, b.maxX, b.maxY, b.set = x, y, x, y, true
+		return
+	}
+	b.minX = math.Min(b.minX, x)
+	b.minY = math.Min(b.minY, y)
+	b.maxX = math.Max(b.maxX, x)
+	b.maxY = math.Max(b.maxY, y)
+}
+
+func (b *diagramBounds) addShape(shape *models.DiagramShape) {
+	b.add(shape.X, shape.Y)
+	b.add(shape.X+shape.Width, shape.Y+shape.Height)
+}
+
+// renderShapesToSVG converts diagram shapes to SVG markup: swimlanes first,
+// then the nodes and the connectors on top of them
+func renderShapesToSVG(shapes interface{}) string {
+	fmt.Printf("🎨 [SVG] Rendering shapes, type: %T\n", shapes)
+
+	shapesList, ok := storedArray(shapes)
+	if !ok {
+		fmt.Printf("❌ [SVG] Unsupported type: %T\n", shapes)
+		return fmt.Sprintf(`<div style="border: 2px dashed #ddd; padding: 20px; text-align: center; color: #666;">
+			<p>Type error: %T</p>
+		</div>`, shapes)
+	}
+
+	parsed := make([]models.DiagramShape, 0, len(shapesList))
+	for _, shape := range shapesList {
+		shapeMap, ok := storedObject(shape)
+		if !ok {
+			fmt.Printf("⚠️  [SVG] Unknown shape type: %T\n", shape)
+			continue
+		}
+		parsed = append(parsed, parseDiagramShape(shapeMap))
+	}
+
+	// Nodes connectors can be attached to
+	nodes := make(map[string]*models.DiagramShape)
+	for i := range parsed {
+		if parsed[i].ID != "" && !parsed[i].Type.IsConnector() && parsed[i].Type != models.DiagramShapeSwimlane {
+			nodes[parsed[i].ID] = &parsed[i]
+		}
+	}
+
+	var lanes, body, links strings.Builder
+	var bounds diagramBounds
+	for i := range parsed {
+		shape := &parsed[i]
+		switch {
+		case shape.Type == models.DiagramShapeSwimlane:
+			bounds.addShape(shape)
+			lanes.WriteString(renderDiagramSwimlane(shape))
+		case shape.Type.IsConnector():
+			points := connectorPoints(shape, nodes)
+			for _, point := range points {
+				bounds.add(point.X, point.Y)
+			}
+			links.WriteString(renderDiagramConnector(shape, points))
+		default:
+			node := renderDiagramNode(shape)
+			if node == "" {
+				fmt.Printf("⚠️  [SVG] Unknown shape: %s\n", shape.Type)
+				continue
+			}
+			bounds.addShape(shape)
+			body.WriteString(node)
+		}
+	}
+
+	if !bounds.set {
+		return `<div style="border: 2px dashed #ddd; padding: 20px; text-align: center; color: #666;">
+			<p>No shapes in diagram</p>
+		</div>`
+	}
+
+	fmt.Printf("✅ [SVG] Rendered %d shapes\n", len(parsed))
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="%.1f %.1f %.1f %.1f" style="max-width: 100%%; height: auto; border: 1px solid #ddd; background: white;">
+		<defs>
+			<marker id="arrowhead" markerWidth="10" markerHeight="10" refX="9" refY="3" orient="auto">
+				<polygon points="0 0, 10 3, 0 6" fill="#000"/>
+			</marker>
+		</defs>%s%s%s</svg>`,
+		bounds.minX-diagramPadding, bounds.minY-diagramPadding,
+		bounds.maxX-bounds.minX+2*diagramPadding, bounds.maxY-bounds.minY+2*diagramPadding,
+		lanes.String(), body.String(), links.String())
+}
+
+// renderDiagramNode renders a node with its label, or nothing for unknown shapes
+func renderDiagramNode(shape *models.DiagramShape) string {
+	fill := svgAttr(shape.Color, "#ffffff")
+	stroke := svgAttr(shape.StrokeColor, "#000")
+	x, y, width, height := shape.X, shape.Y, shape.Width, shape.Height
+	center := shape.Center()
+	// Labels of slanted shapes are kept within their inner rectangle
+	labelWidth := width - 10
+
+	var svg string
+	switch shape.Type {
+	case models.DiagramShapeRectangle:
+		svg = fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="%s" stroke-width="2"/>`,
+			x, y, width, height, fill, stroke)
+
+	case models.DiagramShapeCircle:
+		radius := width / 2
+		svg = fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="%s" stroke-width="2"/>`,
+			x+radius, y+radius, radius, fill, stroke)
+		labelWidth = width * 0.7
+
+	case models.DiagramShapeHexagon:
+		w := width / 2
+		h := height / 2
+		svg = fmt.Sprintf(`<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s" stroke="%s" stroke-width="2"/>`,
+			center.X, center.Y-h, center.X+w, center.Y-h/2, center.X+w, center.Y+h/2, center.X, center.Y+h, center.X-w, center.Y+h/2, center.X-w, center.Y-h/2, fill, stroke)
+		labelWidth = width - 20
+
+	case models.DiagramShapeDiamond:
+		svg = fmt.Sprintf(`<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s" stroke="%s" stroke-width="2"/>`,
+			center.X, y, x+width, center.Y, center.X, y+height, x, center.Y, fill, stroke)
+		labelWidth = width / 2
+
+	default:
+		return ""
+	}
+
+	if shape.Label != "" {
+		svg += renderDiagramText(shape.Label, center.X, center.Y, labelWidth, shape.FontSize, shape.TextColor)
+	}
+	return svg
+}
+
+// renderDiagramSwimlane renders a swimlane with its name in a header band, on
+// the left of horizontal lanes and on top of vertical ones
+func renderDiagramSwimlane(shape *models.DiagramShape) string {
+	fill := svgAttr(shape.Color, "#f8f9fa")
+	stroke := svgAttr(shape.StrokeColor, "#6c757d")
+	x, y, width, height := shape.X, shape.Y, shape.Width, shape.Height
+
+	svg := fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="%s" stroke-width="1.5"/>`,
+		x, y, width, height, fill, stroke)
+	if width >= height {
+		svg += fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" fill-opacity="0.15" stroke="%s" stroke-width="1.5"/>`,
+			x, y, swimlaneHeaderSize, height, stroke, stroke)
+		if shape.Label != "" {
+			cx, cy := x+swimlaneHeaderSize/2, y+height/2
+			svg += fmt.Sprintf(`<g transform="rotate(-90 %.1f %.1f)">%s</g>`,
+				cx, cy, renderDiagramText(shape.Label, cx, cy, height-10, shape.FontSize, shape.TextColor))
+		}
+		return svg
+	}
+
+	svg += fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" fill-opacity="0.15" stroke="%s" stroke-width="1.5"/>`,
+		x, y, width, swimlaneHeaderSize, stroke, stroke)
+	if shape.Label != "" {
+		svg += renderDiagramText(shape.Label, x+width/2, y+swimlaneHeaderSize/2, width-10, shape.FontSize, shape.TextColor)
+	}
+	return svg
+}
+
+// renderDiagramConnector renders a connector through its points, with its
+// label on a blank background halfway along it
+func renderDiagramConnector(shape *models.DiagramShape, points []models.DiagramPoint) string {
+	stroke := svgAttr(shape.Color, "#000")
+
+	var path string
+	if shape.Routing == models.ConnectorRoutingCurved {
+		path = curvedPath(points)
+	} else {
+		path = fmt.Sprintf("M %.1f %.1f", points[0].X, points[0].Y)
+		for _, point := range points[1:] {
+			path += fmt.Sprintf(" L %.1f %.1f", point.X, point.Y)
+		}
+	}
+	svg := fmt.Sprintf(`<path d="%s" fill="none" stroke="%s" stroke-width="2" marker-end="url(#arrowhead)"/>`, path, stroke)
+
+	if shape.Label != "" {
+		fontSize := shape.FontSize
+		if fontSize <= 0 {
+			fontSize = diagramFontSize * 0.85
+		}
+		lines := wrapDiagramLabel(shape.Label, 0, fontSize)
+		longest := 0
+		for _, line := range lines {
+			longest = max(longest, len([]rune(line)))
+		}
+		width := float64(longest)*fontSize*diagramCharWidth + 8
+		height := float64(len(lines))*fontSize*1.2 + 4
+		mid := polylineMidpoint(points)
+		svg += fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="3" fill="#ffffff" fill-opacity="0.9"/>`,
+			mid.X-width/2, mid.Y-height/2, width, height)
+		svg += renderDiagramText(shape.Label, mid.X, mid.Y, 0, fontSize, shape.TextColor)
+	}
+	return svg
+}
+
+// connectorPoints returns the points a connector goes through. The ends of
+// connectors attached to nodes are moved to the border of the nodes, and
+// orthogonal connectors without points get an elbow route.
+func connectorPoints(shape *models.DiagramShape, nodes map[string]*models.DiagramShape) []models.DiagramPoint {
+	start := models.DiagramPoint{X: shape.X, Y: shape.Y}
+	end := models.DiagramPoint{X: shape.EndX, Y: shape.EndY}
+	from, to := nodes[shape.From], nodes[shape.To]
+	if from != nil {
+		start = from.Center()
+	}
+	if to != nil {
+		end = to.Center()
+	}
+
+	if shape.Routing == models.ConnectorRoutingOrthogonal && len(shape.Points) == 0 {
+		horizontal := math.Abs(end.X-start.X) >= math.Abs(end.Y-start.Y)
+		if from != nil {
+			start = sideAnchor(from, end, horizontal)
+		}
+		if to != nil {
+			end = sideAnchor(to, start, horizontal)
+		}
+		if horizontal {
+			midX := (start.X + end.X) / 2
+			return []models.DiagramPoint{start, {X: midX, Y: start.Y}, {X: midX, Y: end.Y}, end}
+		}
+		midY := (start.Y + end.Y) / 2
+		return []models.DiagramPoint{start, {X: start.X, Y: midY}, {X: end.X, Y: midY}, end}
+	}
+
+	points := make([]models.DiagramPoint, 0, len(shape.Points)+2)
+	points = append(points, start)
+	points = append(points, shape.Points...)
+	points = append(points, end)
+	last := len(points) - 1
+	if from != nil {
+		points[0] = borderAnchor(from, points[1])
+	}
+	if to != nil {
+		points[last] = borderAnchor(to, points[last-1])
+	}
+	return points
+}
+
+// sideAnchor returns the middle of the side of a node facing a point
+func sideAnchor(node *models.DiagramShape, toward models.DiagramPoint, horizontal bool) models.DiagramPoint {
+	center := node.Center()
+	if horizontal {
+		return models.DiagramPoint{X: center.X + math.Copysign(node.Width/2, toward.X-center.X), Y: center.Y}
+	}
+	return models.DiagramPoint{X: center.X, Y: center.Y + math.Copysign(node.Height/2, toward.Y-center.Y)}
+}
+
+// borderAnchor returns where the line from the center of a node to a point
+// crosses the border of the node
+func borderAnchor(node *models.DiagramShape, toward models.DiagramPoint) models.DiagramPoint {
+	center := node.Center()
+	dx, dy := toward.X-center.X, toward.Y-center.Y
+	halfWidth, halfHeight := node.Width/2, node.Height/2
+	if (dx == 0 && dy == 0) || halfWidth <= 0 || halfHeight <= 0 {
+		return center
+	}
+
+	var t float64
+	switch node.Type {
+	case models.DiagramShapeCircle:
+		t = halfWidth / math.Hypot(dx, dy)
+	case models.DiagramShapeDiamond:
+		t = 1 / (math.Abs(dx)/halfWidth + math.Abs(dy)/halfHeight)
+	default:
+		t = math.Min(halfWidth/math.Abs(dx), halfHeight/math.Abs(dy))
+	}
+	return models.DiagramPoint{X: center.X + dx*t, Y: center.Y + dy*t}
+}
+
+// curvedPath returns a smooth path through points: an S-curve between two
+// points, else a Catmull-Rom spline through all of them
+func curvedPath(points []models.DiagramPoint) string {
+	start, end := points[0], points[len(points)-1]
+	if len(points) == 2 {
+		if math.Abs(end.X-start.X) >= math.Abs(end.Y-start.Y) {
+			midX := (start.X + end.X) / 2
+			return fmt.Sprintf("M %.1f %.1f C %.1f %.1f %.1f %.1f %.1f %.1f", start.X, start.Y, midX, start.Y, midX, end.Y, end.X, end.Y)
+		}
+		midY := (start.Y + end.Y) / 2
+		return fmt.Sprintf("M %.1f %.1f C %.1f %.1f %.1f %.1f %.1f %.1f", start.X, start.Y, start.X, midY, end.X, midY, end.X, end.Y)
+	}
+
+	path := fmt.Sprintf("M %.1f %.1f", start.X, start.Y)
+	for i := 0; i < len(points)-1; i++ {
+		p0, p1, p2, p3 := points[max(i-1, 0)], points[i], points[i+1], points[min(i+2, len(points)-1)]
+		path += fmt.Sprintf(" C %.1f %.1f %.1f %.1f %.1f %.1f",
+			p1.X+(p2.X-p0.X)/6, p1.Y+(p2.Y-p0.Y)/6,
+			p2.X-(p3.X-p1.X)/6, p2.Y-(p3.Y-p1.Y)/6,
+			p2.X, p2.Y)
+	}
+	return path
+}
+
+// polylineMidpoint returns the point halfway along a polyline
+func polylineMidpoint(points []models.DiagramPoint) models.DiagramPoint {
+	total := 0.0
+	for i := 1; i < len(points); i++ {
+		total += math.Hypot(points[i].X-points[i-1].X, points[i].Y-points[i-1].Y)
+	}
+	remaining := total / 2
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		length := math.Hypot(b.X-a.X, b.Y-a.Y)
+		if length > 0 && remaining <= length {
+			t := remaining / length
+			return models.DiagramPoint{X: a.X + (b.X-a.X)*t, Y: a.Y + (b.Y-a.Y)*t}
+		}
+		remaining -= length
+	}
+	return points[0]
+}
+
+// renderDiagramText renders a label centered on a point, wrapped to a width
+func renderDiagramText(label string, cx, cy, maxWidth, fontSize float64, color string) string {
+	if fontSize <= 0 {
+		fontSize = diagramFontSize
+	}
+	lines := wrapDiagramLabel(label, maxWidth, fontSize)
+	lineHeight := fontSize * 1.2
+	top := cy - lineHeight*float64(len(lines)-1)/2
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<text text-anchor="middle" dominant-baseline="central" font-family="Arial, sans-serif" font-size="%.1f" fill="%s">`,
+		fontSize, svgAttr(color, "#000"))
+	for i, line := range lines {
+		fmt.Fprintf(&b, `<tspan x="%.1f" y="%.1f">%s</tspan>`, cx, top+lineHeight*float64(i), template.HTMLEscapeString(line))
+	}
+	b.WriteString(`</text>`)
+	return b.String()
+}
+
+// wrapDiagramLabel splits a label on its line breaks and wraps the lines
+// wider than maxWidth on word boundaries, no width meaning no wrapping
+func wrapDiagramLabel(label string, maxWidth, fontSize float64) []string {
+	maxChars := int(maxWidth / (fontSize * diagramCharWidth))
+
+	var lines []string
+	for _, paragraph := range strings.Split(label, "\n") {
+		words := strings.Fields(paragraph)
+		if maxWidth <= 0 || len(words) == 0 {
+			lines = append(lines, strings.TrimSpace(paragraph))
+			continue
+		}
+		line := words[0]
+		for _, word := range words[1:] {
+			if len([]rune(line))+1+len([]rune(word)) > maxChars {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// svgAttr escapes a value set by users in an SVG attribute, with a default
+func svgAttr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return template.HTMLEscapeString(value)
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// pdfRenderSampleSize is the number of recent renders kept for the durations
+const pdfRenderSampleSize = 200
+
+// PDFRenderPool bounds the renders running at once, each Chrome render
+// holding hundreds of megabytes. At most PDF_MAX_CONCURRENT_RENDERS renders
+// run, up to PDF_MAX_QUEUED_RENDERS more wait for a slot during at most
+// PDF_RENDER_QUEUE_WAIT_SECONDS; beyond that renders are refused with
+// models.ErrPDFRenderBusy. Background jobs, already bounded by their
+// workers, wait for a slot until their render timeout.
+type PDFRenderPool struct {
+	renderer  PDFRenderer
+	slots     chan struct{}
+	maxQueued int64
+	maxWait   time.Duration
+
+	mu        sync.Mutex
+	inFlight  int64
+	queued    int64
+	completed int64
+	failed    int64
+	rejected  int64
+	durations []time.Duration
+	waits     []time.Duration
+	next      int
+	max       time.Duration
+}
+
+// NewPDFRenderPool creates a new render pool around a renderer
+func NewPDFRenderPool(renderer PDFRenderer) *PDFRenderPool {
+	concurrency := 2
+	if v, err := strconv.Atoi(os.Getenv("PDF_MAX_CONCURRENT_RENDERS")); err == nil && v > 0 {
+		concurrency = v
+	}
+	maxQueued := int64(20)
+	if v, err := strconv.ParseInt(os.Getenv("PDF_MAX_QUEUED_RENDERS"), 10, 64); err == nil && v >= 0 {
+		maxQueued = v
+	}
+	maxWait := 20 * time.Second
+	if v, err := strconv.Atoi(os.Getenv("PDF_RENDER_QUEUE_WAIT_SECONDS")); err == nil && v > 0 {
+		maxWait = time.Duration(v) * time.Second
+	}
+
+	return &PDFRenderPool{
+		renderer:  renderer,
+		slots:     make(chan struct{}, concurrency),
+		maxQueued: maxQueued,
+		maxWait:   maxWait,
+	}
+}
+
+// Render waits for a slot then renders the page, within the render timeout
+func (p *PDFRenderPool) Render(ctx context.Context, html string, opts PDFRenderOptions) ([]byte, error) {
+	timeout, background := pdfRenderTimeout(ctx)
+
+	p.mu.Lock()
+	if !background && p.queued >= p.maxQueued && len(p.slots) == cap(p.slots) {
+		p.rejected++
+		p.mu.Unlock()
+		fmt.Printf("⚠️ [PDF] Render refused: %d renders queued\n", p.maxQueued)
+		return nil, models.ErrPDFRenderBusy
+	}
+	p.queued++
+	p.mu.Unlock()
+
+	wait := p.maxWait
+	if background {
+		wait = timeout
+	}
+	waitCtx, cancelWait := context.WithTimeout(ctx, wait)
+	defer cancelWait()
+
+	queuedAt := time.Now()
+	if len(p.slots) == cap(p.slots) {
+		reportPDFProgress(ctx, models.PDFExportStageQueued)
+	}
+	select {
+	case p.slots <- struct{}{}:
+	case <-waitCtx.Done():
+		p.mu.Lock()
+		p.queued--
+		p.rejected++
+		p.mu.Unlock()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		fmt.Printf("⚠️ [PDF] Render refused: no slot within %s\n", wait)
+		return nil, models.ErrPDFRenderBusy
+	}
+	defer func() { <-p.slots }()
+	waited := time.Since(queuedAt)
+
+	p.mu.Lock()
+	p.queued--
+	p.inFlight++
+	p.mu.Unlock()
+
+	renderCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	pdfBuf, err := p.renderer.Render(renderCtx, html, opts)
+	p.record(waited, time.Since(start), err)
+
+	return pdfBuf, err
+}
+
+// record updates the counters once a render finished
+func (p *PDFRenderPool) record(waited, duration time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inFlight--
+	if err != nil {
+		p.failed++
+		return
+	}
+	p.completed++
+
+	if len(p.durations) < pdfRenderSampleSize {
+		p.durations = append(p.durations, duration)
+		p.waits = append(p.waits, waited)
+	} else {
+		p.durations[p.next] = duration
+		p.waits[p.next] = waited
+		p.next = (p.next + 1) % pdfRenderSampleSize
+	}
+	if duration > p.max {
+		p.max = duration
+	}
+}
+
+// Stats returns the queue depth, the render counters and the recent durations
+func (p *PDFRenderPool) Stats() *models.PDFRenderStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sorted := make([]time.Duration, len(p.durations))
+	copy(sorted, p.durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var totalWait time.Duration
+	for _, w := range p.waits {
+		totalWait += w
+	}
+	var avgWait time.Duration
+	if len(p.waits) > 0 {
+		avgWait = totalWait / time.Duration(len(p.waits))
+	}
+
+	return &models.PDFRenderStats{
+		Renderer:       p.renderer.Name(),
+		MaxConcurrency: cap(p.slots),
+		MaxQueued:      p.maxQueued,
+		InFlight:       p.inFlight,
+		Queued:         p.queued,
+		Completed:      p.completed,
+		Failed:         p.failed,
+		Rejected:       p.rejected,
+		P50Ms:          durationMs(percentile(sorted, 50)),
+		P95Ms:          durationMs(percentile(sorted, 95)),
+		MaxMs:          durationMs(p.max),
+		AvgWaitMs:      durationMs(avgWait),
+	}
+}
+
+// pdfRenderTimeout returns the render timeout of the PDFs generated with ctx,
+// and whether it was set by a background job
+func pdfRenderTimeout(ctx context.Context) (time.Duration, bool) {
+	if d, ok := ctx.Value(pdfRenderTimeoutKey{}).(time.Duration); ok && d > 0 {
+		return d, true
+	}
+	return defaultPDFRenderTimeout, false
+}
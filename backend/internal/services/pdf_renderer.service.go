@@ -0,0 +1,199 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+// ErrPDFArchivalUnsupported is returned when the renderer cannot produce PDF/A files
+var ErrPDFArchivalUnsupported = errors.New("PDF/A output requires the gotenberg PDF renderer")
+
+// PDFRenderOptions customizes the file produced by a renderer
+type PDFRenderOptions struct {
+	PDFA     string                 // PDF/A conformance level such as "PDF/A-2b", empty for a regular PDF
+	Metadata map[string]interface{} // Document properties written to the Info dictionary and the XMP packet
+	Page     *models.PDFPageLayout  // Paper of the PDF, A4 portrait when nil
+}
+
+// paperInches returns the width and height of the paper of a PDF in inches
+func (o PDFRenderOptions) paperInches() (float64, float64) {
+	width, height := o.Page.Dimensions()
+	return width / 25.4, height / 25.4
+}
+
+// PDFRenderer converts a rendered HTML page to a PDF
+type PDFRenderer interface {
+	Name() string
+	Render(ctx context.Context, html string, opts PDFRenderOptions) ([]byte, error)
+}
+
+// NewPDFRenderer returns the renderer selected by PDF_RENDERER: "chrome"
+// (default) starts a headless Chrome inside the API container, "gotenberg"
+// sends the page to the Gotenberg service at GOTENBERG_URL.
+func NewPDFRenderer() PDFRenderer {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("PDF_RENDERER"))) {
+	case "", "chrome":
+		return &chromePDFRenderer{}
+	case "gotenberg":
+		baseURL := strings.TrimRight(os.Getenv("GOTENBERG_URL"), "/")
+		if baseURL == "" {
+			baseURL = "http://gotenberg:3000"
+		}
+		return &gotenbergPDFRenderer{
+			baseURL: baseURL,
+			client:  &http.Client{},
+		}
+	default:
+		fmt.Printf("Warning: Unknown PDF_RENDERER %q, using headless Chrome\n", os.Getenv("PDF_RENDERER"))
+		return &chromePDFRenderer{}
+	}
+}
+
+// chromePDFRenderer prints the page with a headless Chrome started for each PDF
+type chromePDFRenderer struct{}
+
+func (r *chromePDFRenderer) Name() string {
+	return "chrome"
+}
+
+func (r *chromePDFRenderer) Render(ctx context.Context, html string, opts PDFRenderOptions) ([]byte, error) {
+	if opts.PDFA != "" || len(opts.Metadata) > 0 {
+		return nil, ErrPDFArchivalUnsupported
+	}
+
+	// Create allocator options for headless Chrome
+	reportPDFProgress(ctx, models.PDFExportStageLaunchingBrowser)
+	allocOpts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.DisableGPU,
+		chromedp.NoDefaultBrowserCheck,
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("no-sandbox", true),
+	)
+
+	// Create context with allocator
+	allocCtx, cancel := chromedp.NewExecAllocator(ctx, allocOpts...)
+	defer cancel()
+
+	// Create browser context
+	browserCtx, cancel := chromedp.NewContext(allocCtx)
+	defer cancel()
+
+	var pdfBuf []byte
+	paperWidth, paperHeight := opts.paperInches()
+
+	// Use base64 encoding for data URL to preserve CSS and avoid encoding issues
+	encodedHTML := base64.StdEncoding.EncodeToString([]byte(html))
+	dataURL := "data:text/html;charset=utf-8;base64," + encodedHTML
+
+	fmt.Printf("📄 [PDF] Data URL length: %d bytes\n", len(dataURL))
+
+	// Navigate to the data URL and wait for rendering, then print to PDF
+	if err := chromedp.Run(browserCtx,
+		chromedp.Navigate(dataURL),
+		chromedp.WaitReady("body"),
+		chromedp.Sleep(2*time.Second), // Give time for CSS, images, and SVG rendering
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			reportPDFProgress(ctx, models.PDFExportStagePrinting)
+			var err error
+			pdfBuf, _, err = page.PrintToPDF().
+				WithPrintBackground(true).
+				WithDisplayHeaderFooter(false).
+				WithPaperWidth(paperWidth).
+				WithPaperHeight(paperHeight).
+				WithPreferCSSPageSize(true). // Use CSS @page rules
+				Do(ctx)
+			return err
+		}),
+	); err != nil {
+		return nil, err
+	}
+
+	return pdfBuf, nil
+}
+
+// gotenbergPDFRenderer delegates the printing to a Gotenberg service, which
+// keeps Chrome out of the API container
+type gotenbergPDFRenderer struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (r *gotenbergPDFRenderer) Name() string {
+	return "gotenberg"
+}
+
+func (r *gotenbergPDFRenderer) Render(ctx context.Context, html string, opts PDFRenderOptions) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	// Gotenberg requires the page to be named index.html
+	part, err := writer.CreateFormFile("files", "index.html")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(part, html); err != nil {
+		return nil, err
+	}
+	paperWidth, paperHeight := opts.paperInches()
+	fields := map[string]string{
+		"printBackground":   "true",
+		"preferCssPageSize": "true", // Use CSS @page rules
+		"waitDelay":         "2s",   // Give time for CSS, images, and SVG rendering
+		"paperWidth":        strconv.FormatFloat(paperWidth, 'f', 2, 64),
+		"paperHeight":       strconv.FormatFloat(paperHeight, 'f', 2, 64),
+	}
+	if opts.PDFA != "" {
+		fields["pdfa"] = opts.PDFA
+	}
+	if len(opts.Metadata) > 0 {
+		metadata, err := json.Marshal(opts.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		fields["metadata"] = string(metadata)
+	}
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+"/forms/chromium/convert/html", &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	// Gotenberg keeps its browser running, the page is printed right away
+	reportPDFProgress(ctx, models.PDFExportStagePrinting)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gotenberg request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("gotenberg returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return io.ReadAll(resp.Body)
+}
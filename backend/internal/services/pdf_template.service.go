@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PDFTemplateService manages the corporate layouts of the PDFs and selects
+// the one applying to a document or macro
+type PDFTemplateService struct {
+	collection     *mongo.Collection
+	userCollection *mongo.Collection
+}
+
+// NewPDFTemplateService creates a new PDF template service instance
+func NewPDFTemplateService(db *DatabaseService) *PDFTemplateService {
+	service := &PDFTemplateService{
+		collection:     db.Collection("pdf_templates"),
+		userCollection: db.Collection("users"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "is_default", Value: 1}}},
+		{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "department_ids", Value: 1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create PDF template indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// Create adds a PDF template after checking its source parses
+func (s *PDFTemplateService) Create(ctx context.Context, req *models.CreatePDFTemplateRequest, userID primitive.ObjectID) (*models.PDFTemplate, error) {
+	if err := validatePDFTemplateSource(req.Source); err != nil {
+		return nil, err
+	}
+	departmentIDs, err := parseDepartmentIDs(req.DepartmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tmpl := &models.PDFTemplate{
+		ID:            primitive.NewObjectID(),
+		Name:          strings.TrimSpace(req.Name),
+		Description:   req.Description,
+		Kind:          req.Kind,
+		Source:        req.Source,
+		IsDefault:     req.IsDefault,
+		DepartmentIDs: departmentIDs,
+		CreatedBy:     userID,
+		UpdatedBy:     userID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.releaseSelection(ctx, tmpl); err != nil {
+		return nil, err
+	}
+	if _, err := s.collection.InsertOne(ctx, tmpl); err != nil {
+		return nil, fmt.Errorf("failed to create PDF template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// GetByID retrieves a PDF template by ID
+func (s *PDFTemplateService) GetByID(ctx context.Context, id primitive.ObjectID) (*models.PDFTemplate, error) {
+	var tmpl models.PDFTemplate
+	if err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&tmpl); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("pdf template not found")
+		}
+		return nil, fmt.Errorf("failed to get PDF template: %w", err)
+	}
+	return &tmpl, nil
+}
+
+// List returns the PDF templates, of a kind when set, by name
+func (s *PDFTemplateService) List(ctx context.Context, kind models.PDFTemplateKind) ([]models.PDFTemplate, error) {
+	filter := bson.M{}
+	if kind != "" {
+		filter["kind"] = kind
+	}
+
+	cursor, err := s.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find PDF templates: %w", err)
+	}
+	templates := make([]models.PDFTemplate, 0)
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode PDF templates: %w", err)
+	}
+	return templates, nil
+}
+
+// Update updates a PDF template. Making it the default or assigning it to
+// departments releases them from the other templates of its kind.
+func (s *PDFTemplateService) Update(ctx context.Context, id primitive.ObjectID, req *models.UpdatePDFTemplateRequest, userID primitive.ObjectID) (*models.PDFTemplate, error) {
+	tmpl, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		tmpl.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		tmpl.Description = *req.Description
+	}
+	if req.Source != nil {
+		if err := validatePDFTemplateSource(*req.Source); err != nil {
+			return nil, err
+		}
+		tmpl.Source = *req.Source
+	}
+	if req.IsDefault != nil {
+		tmpl.IsDefault = *req.IsDefault
+	}
+	if req.DepartmentIDs != nil {
+		if tmpl.DepartmentIDs, err = parseDepartmentIDs(*req.DepartmentIDs); err != nil {
+			return nil, err
+		}
+	}
+	tmpl.UpdatedBy = userID
+	tmpl.UpdatedAt = time.Now()
+
+	if err := s.releaseSelection(ctx, tmpl); err != nil {
+		return nil, err
+	}
+	if _, err := s.collection.ReplaceOne(ctx, bson.M{"_id": id}, tmpl); err != nil {
+		return nil, fmt.Errorf("failed to update PDF template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// Delete removes a PDF template, its PDFs fall back to the default layout
+func (s *PDFTemplateService) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete PDF template: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("pdf template not found")
+	}
+	return nil
+}
+
+// Resolve returns the template laying out the PDFs of the given kind created
+// by a user: the template of their department, else the default template of
+// the organization. It returns nil when the built-in layout applies.
+func (s *PDFTemplateService) Resolve(ctx context.Context, kind models.PDFTemplateKind, creatorID primitive.ObjectID) (*models.PDFTemplate, error) {
+	var creator struct {
+		DepartmentID *primitive.ObjectID `bson:"department_id"`
+	}
+	err := s.userCollection.FindOne(ctx, bson.M{"_id": creatorID},
+		options.FindOne().SetProjection(bson.M{"department_id": 1}),
+	).Decode(&creator)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to get document creator: %w", err)
+	}
+
+	filters := []bson.M{}
+	if creator.DepartmentID != nil {
+		filters = append(filters, bson.M{"kind": kind, "department_ids": *creator.DepartmentID})
+	}
+	filters = append(filters, bson.M{"kind": kind, "is_default": true})
+
+	for _, filter := range filters {
+		var tmpl models.PDFTemplate
+		err := s.collection.FindOne(ctx, filter).Decode(&tmpl)
+		if err == nil {
+			return &tmpl, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("failed to resolve PDF template: %w", err)
+		}
+	}
+	return nil, nil
+}
+
+// releaseSelection unsets the default flag and the departments of the other
+// templates of the same kind, so each selection points to a single template
+func (s *PDFTemplateService) releaseSelection(ctx context.Context, tmpl *models.PDFTemplate) error {
+	others := bson.M{"_id": bson.M{"$ne": tmpl.ID}, "kind": tmpl.Kind}
+	if tmpl.IsDefault {
+		if _, err := s.collection.UpdateMany(ctx, others, bson.M{"$set": bson.M{"is_default": false}}); err != nil {
+			return fmt.Errorf("failed to release default PDF template: %w", err)
+		}
+	}
+	if len(tmpl.DepartmentIDs) > 0 {
+		if _, err := s.collection.UpdateMany(ctx, others,
+			bson.M{"$pull": bson.M{"department_ids": bson.M{"$in": tmpl.DepartmentIDs}}},
+		); err != nil {
+			return fmt.Errorf("failed to release department PDF templates: %w", err)
+		}
+	}
+	return nil
+}
+
+// validatePDFTemplateSource checks a template source parses with the
+// functions available to the PDF layouts
+func validatePDFTemplateSource(source string) error {
+	if _, err := parsePDFTemplate("validate", source, models.DefaultBranding()); err != nil {
+		return fmt.Errorf("invalid pdf template: %w", err)
+	}
+	return nil
+}
+
+// parseDepartmentIDs converts department IDs from their hex form
+func parseDepartmentIDs(ids []string) ([]primitive.ObjectID, error) {
+	departmentIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		departmentID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid department ID: %s", id)
+		}
+		departmentIDs = append(departmentIDs, departmentID)
+	}
+	return departmentIDs, nil
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// latencySampleSize is the number of recent requests kept per endpoint
+	latencySampleSize = 1000
+	// slowQueryLogSize is the number of recent slow queries kept
+	slowQueryLogSize = 200
+	// slowQueryMaxLength truncates logged command documents
+	slowQueryMaxLength = 512
+)
+
+// ignoredCommands are driver housekeeping commands never reported as slow queries
+var ignoredCommands = map[string]bool{
+	"hello": true, "isMaster": true, "ismaster": true, "ping": true,
+	"saslStart": true, "saslContinue": true, "endSessions": true, "buildInfo": true,
+	"getMore": true, // Cursor batches are reported through their originating command
+}
+
+type endpointStats struct {
+	method  string
+	path    string
+	count   int64
+	errors  int64
+	max     time.Duration
+	samples []time.Duration
+	next    int
+}
+
+type pendingCommand struct {
+	name       string
+	database   string
+	collection string
+	query      string
+}
+
+// PerfService collects request latencies and MongoDB slow queries in memory.
+// It is only active when PERF_PROFILING=true.
+type PerfService struct {
+	enabled       bool
+	slowThreshold time.Duration
+
+	mu          sync.Mutex
+	since       time.Time
+	endpoints   map[string]*endpointStats
+	slowQueries []models.SlowQuery
+	pending     sync.Map // Started commands by request ID
+}
+
+// NewPerfService creates a new profiling service instance
+func NewPerfService() *PerfService {
+	threshold := 100 * time.Millisecond
+	if ms, err := strconv.Atoi(os.Getenv("MONGO_SLOW_QUERY_MS")); err == nil && ms > 0 {
+		threshold = time.Duration(ms) * time.Millisecond
+	}
+
+	return &PerfService{
+		enabled:       os.Getenv("PERF_PROFILING") == "true",
+		slowThreshold: threshold,
+		since:         time.Now(),
+		endpoints:     make(map[string]*endpointStats),
+	}
+}
+
+// IsEnabled reports whether profiling is active
+func (s *PerfService) IsEnabled() bool {
+	return s.enabled
+}
+
+// RecordRequest stores the latency of a handled request
+func (s *PerfService) RecordRequest(method, path string, status int, duration time.Duration) {
+	if !s.enabled {
+		return
+	}
+	key := method + " " + path
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, ok := s.endpoints[key]
+	if !ok {
+		stats = &endpointStats{method: method, path: path, samples: make([]time.Duration, 0, 64)}
+		s.endpoints[key] = stats
+	}
+	stats.count++
+	if status >= 500 {
+		stats.errors++
+	}
+	if duration > stats.max {
+		stats.max = duration
+	}
+	if len(stats.samples) < latencySampleSize {
+		stats.samples = append(stats.samples, duration)
+	} else {
+		stats.samples[stats.next] = duration
+		stats.next = (stats.next + 1) % latencySampleSize
+	}
+}
+
+// ClientOptions returns the MongoDB client options installing the slow query
+// monitor, or nil when profiling is disabled
+func (s *PerfService) ClientOptions() *options.ClientOptions {
+	if !s.enabled {
+		return nil
+	}
+
+	return options.Client().SetMonitor(&event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			if ignoredCommands[e.CommandName] {
+				return
+			}
+			cmd := pendingCommand{name: e.CommandName, database: e.DatabaseName}
+			if first, err := e.Command.IndexErr(0); err == nil {
+				if collection, ok := first.Value().StringValueOK(); ok {
+					cmd.collection = collection
+				}
+			}
+			cmd.query = e.Command.String()
+			if len(cmd.query) > slowQueryMaxLength {
+				cmd.query = cmd.query[:slowQueryMaxLength] + "..."
+			}
+			s.pending.Store(e.RequestID, cmd)
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			s.finishCommand(e.RequestID, e.Duration, "")
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			s.finishCommand(e.RequestID, e.Duration, e.Failure)
+		},
+	})
+}
+
+func (s *PerfService) finishCommand(requestID int64, duration time.Duration, failure string) {
+	value, ok := s.pending.LoadAndDelete(requestID)
+	if !ok || duration < s.slowThreshold {
+		return
+	}
+	cmd := value.(pendingCommand)
+
+	query := models.SlowQuery{
+		Command:    cmd.name,
+		Database:   cmd.database,
+		Collection: cmd.collection,
+		DurationMs: durationMs(duration),
+		Failed:     failure != "",
+		Error:      failure,
+		Query:      cmd.query,
+		At:         time.Now(),
+	}
+	fmt.Printf("🐢 [PERF] Slow query: %s %s.%s took %.1fms\n", cmd.name, cmd.database, cmd.collection, query.DurationMs)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.slowQueries = append(s.slowQueries, query)
+	if len(s.slowQueries) > slowQueryLogSize {
+		s.slowQueries = s.slowQueries[len(s.slowQueries)-slowQueryLogSize:]
+	}
+}
+
+// Report returns the collected latencies, slowest endpoints first
+func (s *PerfService) Report() *models.PerfReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &models.PerfReport{
+		Enabled:              s.enabled,
+		Since:                s.since,
+		SlowQueryThresholdMs: s.slowThreshold.Milliseconds(),
+		Endpoints:            make([]models.EndpointLatency, 0, len(s.endpoints)),
+		SlowQueries:          make([]models.SlowQuery, 0, len(s.slowQueries)),
+	}
+
+	for _, stats := range s.endpoints {
+		sorted := make([]time.Duration, len(stats.samples))
+		copy(sorted, stats.samples)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		report.Endpoints = append(report.Endpoints, models.EndpointLatency{
+			Method: stats.method,
+			Path:   stats.path,
+			Count:  stats.count,
+			Errors: stats.errors,
+			P50Ms:  durationMs(percentile(sorted, 50)),
+			P95Ms:  durationMs(percentile(sorted, 95)),
+			P99Ms:  durationMs(percentile(sorted, 99)),
+			MaxMs:  durationMs(stats.max),
+		})
+	}
+	sort.Slice(report.Endpoints, func(i, j int) bool {
+		return report.Endpoints[i].P95Ms > report.Endpoints[j].P95Ms
+	})
+
+	for i := len(s.slowQueries) - 1; i >= 0; i-- {
+		report.SlowQueries = append(report.SlowQueries, s.slowQueries[i])
+	}
+
+	return report
+}
+
+// Reset clears the collected data, e.g. before measuring a change
+func (s *PerfService) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.since = time.Now()
+	s.endpoints = make(map[string]*endpointStats)
+	s.slowQueries = nil
+}
+
+// percentile returns the nearest-rank percentile of sorted samples
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func durationMs(d time.Duration) float64 {
+	return math.Round(float64(d.Microseconds())/10) / 100
+}
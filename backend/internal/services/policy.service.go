@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// currentPoliciesTTL bounds how long the published policies are cached, so
+// versions published by another instance are picked up quickly
+const currentPoliciesTTL = time.Minute
+
+// PolicyService handles versioned policy documents and their acceptance by users
+type PolicyService struct {
+	collection           *mongo.Collection
+	acceptanceCollection *mongo.Collection
+	userCollection       *mongo.Collection
+
+	mu       sync.RWMutex
+	current  []*models.Policy
+	loadedAt time.Time
+}
+
+// NewPolicyService creates a new policy service instance
+func NewPolicyService(db *DatabaseService) *PolicyService {
+	service := &PolicyService{
+		collection:           db.Collection("policies"),
+		acceptanceCollection: db.Collection("policy_acceptances"),
+		userCollection:       db.Collection("users"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "type", Value: 1}, {Key: "version", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create policy indexes: %v\n", err)
+	}
+	if _, err := service.acceptanceCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "policy_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "policy_id", Value: 1}, {Key: "accepted_at", Value: -1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create policy acceptance indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// Create drafts a new policy version
+func (s *PolicyService) Create(ctx context.Context, req *models.CreatePolicyRequest, createdBy primitive.ObjectID) (*models.Policy, error) {
+	now := time.Now()
+	policy := &models.Policy{
+		Type:      req.Type,
+		Version:   req.Version,
+		Title:     req.Title,
+		Content:   req.Content,
+		Summary:   req.Summary,
+		Status:    models.PolicyStatusDraft,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	result, err := s.collection.InsertOne(ctx, policy)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("policy %s version %s already exists", req.Type, req.Version)
+		}
+		return nil, fmt.Errorf("failed to create policy: %w", err)
+	}
+	policy.ID = result.InsertedID.(primitive.ObjectID)
+
+	return policy, nil
+}
+
+// GetByID retrieves a policy version by ID
+func (s *PolicyService) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Policy, error) {
+	var policy models.Policy
+	if err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&policy); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("policy not found")
+		}
+		return nil, fmt.Errorf("failed to get policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// List returns every version of the policies, newest first, optionally of one type
+func (s *PolicyService) List(ctx context.Context, policyType string) ([]*models.Policy, error) {
+	filter := bson.M{}
+	if policyType != "" {
+		filter["type"] = policyType
+	}
+
+	cursor, err := s.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "type", Value: 1}, {Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find policies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	policies := make([]*models.Policy, 0)
+	if err := cursor.All(ctx, &policies); err != nil {
+		return nil, fmt.Errorf("failed to decode policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// Update updates a draft policy version. Published versions are immutable
+// since users accepted their exact content.
+func (s *PolicyService) Update(ctx context.Context, id primitive.ObjectID, req *models.UpdatePolicyRequest) (*models.Policy, error) {
+	setFields := bson.M{
+		"updated_at": time.Now(),
+	}
+	if req.Title != nil {
+		setFields["title"] = *req.Title
+	}
+	if req.Content != nil {
+		setFields["content"] = *req.Content
+	}
+	if req.Summary != nil {
+		setFields["summary"] = *req.Summary
+	}
+
+	var policy models.Policy
+	err := s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id, "status": models.PolicyStatusDraft},
+		bson.M{"$set": setFields},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&policy)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, s.draftError(ctx, id)
+		}
+		return nil, fmt.Errorf("failed to update policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// Delete removes a draft policy version
+func (s *PolicyService) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": id, "status": models.PolicyStatusDraft})
+	if err != nil {
+		return fmt.Errorf("failed to delete policy: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return s.draftError(ctx, id)
+	}
+	return nil
+}
+
+// draftError explains why a policy could not be changed as a draft
+func (s *PolicyService) draftError(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return fmt.Errorf("only draft policies can be modified")
+}
+
+// Publish makes a draft the current version of its policy type. Every user
+// has to accept it, including those who accepted the previous version.
+func (s *PolicyService) Publish(ctx context.Context, id, publishedBy primitive.ObjectID) (*models.Policy, error) {
+	policy, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Status != models.PolicyStatusDraft {
+		return nil, fmt.Errorf("only draft policies can be published")
+	}
+
+	now := time.Now()
+	if _, err := s.collection.UpdateMany(ctx,
+		bson.M{"type": policy.Type, "status": models.PolicyStatusPublished},
+		bson.M{"$set": bson.M{"status": models.PolicyStatusSuperseded, "updated_at": now}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to supersede previous policy: %w", err)
+	}
+
+	err = s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id, "status": models.PolicyStatusDraft},
+		bson.M{"$set": bson.M{
+			"status":       models.PolicyStatusPublished,
+			"published_at": now,
+			"published_by": publishedBy,
+			"updated_at":   now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish policy: %w", err)
+	}
+
+	s.invalidateCurrent()
+	return policy, nil
+}
+
+// GetCurrent returns the published version of each policy type
+func (s *PolicyService) GetCurrent(ctx context.Context) ([]*models.Policy, error) {
+	s.mu.RLock()
+	if s.current != nil && time.Since(s.loadedAt) < currentPoliciesTTL {
+		current := s.current
+		s.mu.RUnlock()
+		return current, nil
+	}
+	s.mu.RUnlock()
+
+	cursor, err := s.collection.Find(ctx, bson.M{"status": models.PolicyStatusPublished}, options.Find().SetSort(bson.D{{Key: "type", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find published policies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	current := make([]*models.Policy, 0)
+	if err := cursor.All(ctx, &current); err != nil {
+		return nil, fmt.Errorf("failed to decode published policies: %w", err)
+	}
+
+	s.mu.Lock()
+	s.current = current
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	return current, nil
+}
+
+func (s *PolicyService) invalidateCurrent() {
+	s.mu.Lock()
+	s.current = nil
+	s.mu.Unlock()
+}
+
+// PendingForUser returns the published policies the user has not accepted in their current version
+func (s *PolicyService) PendingForUser(ctx context.Context, user *models.User) ([]*models.Policy, error) {
+	current, err := s.GetCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]*models.Policy, 0)
+	for _, policy := range current {
+		if user.AcceptedPolicies[policy.Type] != policy.Version {
+			pending = append(pending, policy)
+		}
+	}
+	return pending, nil
+}
+
+// Accept records the acceptance of published policy versions by a user
+func (s *PolicyService) Accept(ctx context.Context, user *models.User, policyIDs []primitive.ObjectID, ipAddress, userAgent string) ([]*models.Policy, error) {
+	current, err := s.GetCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := make([]*models.Policy, 0, len(policyIDs))
+	for _, id := range policyIDs {
+		var policy *models.Policy
+		for _, p := range current {
+			if p.ID == id {
+				policy = p
+				break
+			}
+		}
+		if policy == nil {
+			return nil, fmt.Errorf("policy %s is not a published policy", id.Hex())
+		}
+		accepted = append(accepted, policy)
+	}
+
+	now := time.Now()
+	userUpdate := bson.M{"updated_at": now}
+	for _, policy := range accepted {
+		acceptance := models.PolicyAcceptance{
+			UserID:     user.ID,
+			PolicyID:   policy.ID,
+			Type:       policy.Type,
+			Version:    policy.Version,
+			IPAddress:  ipAddress,
+			UserAgent:  userAgent,
+			AcceptedAt: now,
+		}
+		// Accepting the same version twice keeps the first record
+		if _, err := s.acceptanceCollection.InsertOne(ctx, acceptance); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("failed to record policy acceptance: %w", err)
+		}
+		userUpdate["accepted_policies."+string(policy.Type)] = policy.Version
+	}
+
+	if _, err := s.userCollection.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": userUpdate}); err != nil {
+		return nil, fmt.Errorf("failed to update user policies: %w", err)
+	}
+
+	return accepted, nil
+}
+
+// GetStats returns the acceptance completion rate of each published policy among active users
+func (s *PolicyService) GetStats(ctx context.Context) ([]*models.PolicyAcceptanceStats, error) {
+	current, err := s.GetCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	activeUsers := bson.M{"active": true, "status": models.StatusActive}
+	totalUsers, err := s.userCollection.CountDocuments(ctx, activeUsers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	stats := make([]*models.PolicyAcceptanceStats, 0, len(current))
+	for _, policy := range current {
+		filter := bson.M{"accepted_policies." + string(policy.Type): policy.Version}
+		for k, v := range activeUsers {
+			filter[k] = v
+		}
+		accepted, err := s.userCollection.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count policy acceptances: %w", err)
+		}
+
+		stat := &models.PolicyAcceptanceStats{
+			PolicyID:      policy.ID,
+			Type:          policy.Type,
+			Version:       policy.Version,
+			PublishedAt:   policy.PublishedAt,
+			AcceptedCount: accepted,
+			TotalUsers:    totalUsers,
+		}
+		if totalUsers > 0 {
+			stat.CompletionRate = float64(accepted) / float64(totalUsers) * 100
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
+
+// ListAcceptances returns the acceptance records of a policy version, most recent first
+func (s *PolicyService) ListAcceptances(ctx context.Context, policyID primitive.ObjectID, page, limit int) ([]*models.PolicyAcceptance, int64, error) {
+	filter := bson.M{"policy_id": policyID}
+
+	total, err := s.acceptanceCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count policy acceptances: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "accepted_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := s.acceptanceCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find policy acceptances: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	acceptances := make([]*models.PolicyAcceptance, 0)
+	if err := cursor.All(ctx, &acceptances); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode policy acceptances: %w", err)
+	}
+
+	return acceptances, total, nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PresenceService tracks the users who currently have a document open. The
+// clients send a heartbeat while the document stays open, a user is gone
+// once no heartbeat arrived within the presence TTL.
+type PresenceService struct {
+	redisClient *redis.Client
+	userService *UserService
+	ttl         time.Duration
+}
+
+// NewPresenceService creates a new presence service instance
+func NewPresenceService(redisClient *redis.Client, userService *UserService) *PresenceService {
+	return &PresenceService{
+		redisClient: redisClient,
+		userService: userService,
+		ttl:         presenceTTL(),
+	}
+}
+
+// presenceTTL returns how long a heartbeat keeps a user present, set by PRESENCE_TTL_SECONDS
+func presenceTTL() time.Duration {
+	if v, err := strconv.Atoi(os.Getenv("PRESENCE_TTL_SECONDS")); err == nil && v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return 45 * time.Second
+}
+
+// HeartbeatInterval is the time the clients should wait between two
+// heartbeats, so one lost heartbeat does not hide a user
+func (s *PresenceService) HeartbeatInterval() time.Duration {
+	return s.ttl / 3
+}
+
+// Heartbeat marks a user as present on a document
+func (s *PresenceService) Heartbeat(ctx context.Context, documentID, userID primitive.ObjectID, mode models.PresenceMode) error {
+	if mode == "" {
+		mode = models.PresenceModeViewing
+	}
+	key := s.getPresenceKey(documentID)
+	modesKey := s.getModesKey(documentID)
+
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(time.Now().UnixMilli()), Member: userID.Hex()})
+		pipe.HSet(ctx, modesKey, userID.Hex(), string(mode))
+		pipe.Expire(ctx, key, s.ttl)
+		pipe.Expire(ctx, modesKey, s.ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store presence: %w", err)
+	}
+	return nil
+}
+
+// Leave removes a user from the users present on a document
+func (s *PresenceService) Leave(ctx context.Context, documentID, userID primitive.ObjectID) error {
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, s.getPresenceKey(documentID), userID.Hex())
+		pipe.HDel(ctx, s.getModesKey(documentID), userID.Hex())
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove presence: %w", err)
+	}
+	return nil
+}
+
+// List returns the users present on a document, the most recently seen first
+func (s *PresenceService) List(ctx context.Context, documentID, currentUserID primitive.ObjectID) (*models.DocumentPresenceResponse, error) {
+	key := s.getPresenceKey(documentID)
+	modesKey := s.getModesKey(documentID)
+
+	// Drop the users whose last heartbeat expired
+	cutoff := time.Now().Add(-s.ttl).UnixMilli()
+	if err := s.redisClient.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(cutoff, 10)).Err(); err != nil {
+		return nil, fmt.Errorf("failed to expire presence: %w", err)
+	}
+
+	entries, err := s.redisClient.ZRangeWithScores(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presence: %w", err)
+	}
+
+	response := &models.DocumentPresenceResponse{
+		DocumentID:        documentID.Hex(),
+		Users:             []models.DocumentPresence{},
+		HeartbeatInterval: int(s.HeartbeatInterval() / time.Second),
+	}
+	if len(entries) == 0 {
+		return response, nil
+	}
+
+	members := make([]string, 0, len(entries))
+	userIDs := make([]primitive.ObjectID, 0, len(entries))
+	for _, entry := range entries {
+		member, _ := entry.Member.(string)
+		id, err := primitive.ObjectIDFromHex(member)
+		if err != nil {
+			continue
+		}
+		members = append(members, member)
+		userIDs = append(userIDs, id)
+	}
+	if len(userIDs) == 0 {
+		return response, nil
+	}
+
+	modes, err := s.redisClient.HMGet(ctx, modesKey, members...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presence modes: %w", err)
+	}
+	users, _, err := s.userService.ListUsers(ctx, 0, int64(len(userIDs)), bson.M{"_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[primitive.ObjectID]*models.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+	modesByMember := make(map[string]models.PresenceMode, len(members))
+	for i, member := range members {
+		if value, ok := modes[i].(string); ok && value != "" {
+			modesByMember[member] = models.PresenceMode(value)
+		}
+	}
+
+	for _, entry := range entries {
+		member, _ := entry.Member.(string)
+		id, err := primitive.ObjectIDFromHex(member)
+		if err != nil {
+			continue
+		}
+		user, ok := usersByID[id]
+		if !ok {
+			continue // Deleted in the meantime
+		}
+
+		mode, ok := modesByMember[member]
+		if !ok {
+			mode = models.PresenceModeViewing
+		}
+
+		response.Users = append(response.Users, models.DocumentPresence{
+			UserID:     id,
+			FirstName:  user.FirstName,
+			LastName:   user.LastName,
+			Avatar:     user.Avatar,
+			Mode:       mode,
+			LastSeenAt: time.UnixMilli(int64(entry.Score)),
+			IsCurrent:  id == currentUserID,
+		})
+	}
+
+	sort.Slice(response.Users, func(i, j int) bool {
+		return response.Users[i].LastSeenAt.After(response.Users[j].LastSeenAt)
+	})
+
+	return response, nil
+}
+
+// getPresenceKey returns the sorted set of the last heartbeats on a document
+func (s *PresenceService) getPresenceKey(documentID primitive.ObjectID) string {
+	return fmt.Sprintf("presence:document:%s", documentID.Hex())
+}
+
+// getModesKey returns the hash of the presence modes on a document
+func (s *PresenceService) getModesKey(documentID primitive.ObjectID) string {
+	return fmt.Sprintf("presence:document:%s:modes", documentID.Hex())
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// publicationCheckInterval is how often scheduled publications are looked for
+const publicationCheckInterval = time.Minute
+
+// PublicationService publishes approved documents at their scheduled date and
+// runs the follow-ups of a publication to the organization
+type PublicationService struct {
+	documentService     *DocumentService
+	notificationService *NotificationService
+	qmsSyncService      *QMSSyncService
+	watchService        *WatchService
+}
+
+// NewPublicationService creates a new publication service instance
+func NewPublicationService(documentService *DocumentService, notificationService *NotificationService, qmsSyncService *QMSSyncService, watchService *WatchService) *PublicationService {
+	return &PublicationService{
+		documentService:     documentService,
+		notificationService: notificationService,
+		qmsSyncService:      qmsSyncService,
+		watchService:        watchService,
+	}
+}
+
+// Start publishes the due documents every minute until the context is cancelled
+func (s *PublicationService) Start(ctx context.Context) {
+	run := func() {
+		runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		count, err := s.RunDue(runCtx)
+		if err != nil {
+			fmt.Printf("Warning: Failed to run scheduled publications: %v\n", err)
+			return
+		}
+		if count > 0 {
+			fmt.Printf("📢 Published %d scheduled document(s)\n", count)
+		}
+	}
+
+	go func() {
+		run()
+
+		ticker := time.NewTicker(publicationCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				run()
+			}
+		}
+	}()
+	fmt.Printf("📢 Scheduled publication worker started (every %s)\n", publicationCheckInterval)
+}
+
+// RunDue publishes the approved documents whose scheduled date has passed and
+// returns how many were published
+func (s *PublicationService) RunDue(ctx context.Context) (int, error) {
+	documents, err := s.documentService.ListDueScheduledPublications(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, scheduled := range documents {
+		document, err := s.documentService.Publish(ctx, scheduled.ID, nil)
+		if err != nil {
+			fmt.Printf("⚠️ [PUBLISH] Failed to publish scheduled document %s: %v\n", scheduled.Reference, err)
+			continue
+		}
+		published++
+
+		var senderID primitive.ObjectID
+		if scheduled.ScheduledPublishBy != nil {
+			senderID = *scheduled.ScheduledPublishBy
+			s.notifyScheduler(ctx, document, senderID)
+		}
+		s.notifyLive(ctx, document, senderID)
+		s.Published(document, senderID)
+	}
+
+	return published, nil
+}
+
+// Published runs the follow-ups of a document published to the organization:
+// documents referencing a version it supersedes are flagged and their owners
+// notified, and the document is queued for the QMS sync along with the version
+// it supersedes. It returns immediately, the work is done in the background.
+func (s *PublicationService) Published(document *models.Document, senderID primitive.ObjectID) {
+	if document.Status != models.DocumentStatusArchived {
+		return
+	}
+
+	go s.notifyStaleReferences(document, senderID)
+	go s.queueQMSSync(document)
+
+	// References still point to the revised document when this is a revision
+	if document.Supersedes != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if revised, err := s.documentService.GetByID(ctx, *document.Supersedes); err == nil {
+			go s.queueQMSSync(revised) // The QMS learns it is superseded
+			stale := *revised
+			stale.Version = document.Version
+			go s.notifyStaleReferences(&stale, senderID)
+		}
+	}
+}
+
+// queueQMSSync queues the sync of a document to the external QMS
+func (s *PublicationService) queueQMSSync(document *models.Document) {
+	if s.qmsSyncService == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.qmsSyncService.Enqueue(ctx, document); err != nil {
+		fmt.Printf("⚠️  Failed to queue QMS sync of %s: %v\n", document.Reference, err)
+	}
+}
+
+// notifyScheduler tells the user who scheduled the publication that it happened
+func (s *PublicationService) notifyScheduler(ctx context.Context, document *models.Document, userID primitive.ObjectID) {
+	notificationReq := &models.SendNotificationRequest{
+		UserIDs:  []string{userID.Hex()},
+		Title:    "Scheduled Publication Completed",
+		Body:     fmt.Sprintf("Document '%s' (%s) has been published to the organization as scheduled.", document.Title, document.Reference),
+		Category: "document",
+		Data: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"reference":  document.Reference,
+			"title":      document.Title,
+			"action":     "document_published",
+		},
+	}
+	if _, err := s.notificationService.SendNotification(ctx, notificationReq, userID); err != nil {
+		fmt.Printf("⚠️  Failed to notify scheduler of %s: %v\n", document.Reference, err)
+	}
+}
+
+// notifyLive tells the contributors and the watchers of a document published
+// at its scheduled date that it is live. Nobody is at the keyboard to spread
+// the word, unlike a manual publication.
+func (s *PublicationService) notifyLive(ctx context.Context, document *models.Document, senderID primitive.ObjectID) {
+	recipients := make(map[string]bool)
+	for _, team := range []models.ContributorTeam{models.ContributorTeamAuthors, models.ContributorTeamVerifiers, models.ContributorTeamValidators} {
+		for _, contributor := range document.Contributors.Team(team) {
+			if contributor.UserID != senderID {
+				recipients[contributor.UserID.Hex()] = true
+			}
+		}
+	}
+	if len(recipients) > 0 {
+		userIDs := make([]string, 0, len(recipients))
+		for id := range recipients {
+			userIDs = append(userIDs, id)
+		}
+		notificationReq := &models.SendNotificationRequest{
+			UserIDs:  userIDs,
+			Title:    "Document Published",
+			Body:     fmt.Sprintf("Document '%s' (%s) version %s is now live.", document.Title, document.Reference, document.Version),
+			Category: models.NotificationCategoryUpdate,
+			Data: map[string]interface{}{
+				"documentId": document.ID.Hex(),
+				"reference":  document.Reference,
+				"title":      document.Title,
+				"pdfUrl":     document.PdfUrl,
+				"action":     "document_published",
+			},
+		}
+		if _, err := s.notificationService.SendNotification(ctx, notificationReq, senderID); err != nil {
+			fmt.Printf("⚠️  Failed to notify contributors of %s: %v\n", document.Reference, err)
+		}
+	}
+
+	if s.watchService != nil {
+		err := s.watchService.Notify(ctx, document.ID, document, models.WatchEventStatusChanged,
+			"Watched Document Status Changed",
+			fmt.Sprintf("Document '%s' (%s) is now %s.", document.Title, document.Reference, document.Status),
+			senderID)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to notify watchers of %s: %v\n", document.Reference, err)
+		}
+	}
+}
+
+// notifyStaleReferences flags references to superseded versions of a document
+// and notifies the owners of the referencing documents
+func (s *PublicationService) notifyStaleReferences(document *models.Document, senderID primitive.ObjectID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	referencing, err := s.documentService.MarkStaleReferences(ctx, document)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to flag stale references to %s: %v\n", document.Reference, err)
+		return
+	}
+
+	for _, doc := range referencing {
+		owners := map[string]bool{doc.CreatedBy.Hex(): true}
+		for _, author := range doc.Contributors.Authors {
+			owners[author.UserID.Hex()] = true
+		}
+		userIDs := make([]string, 0, len(owners))
+		for id := range owners {
+			userIDs = append(userIDs, id)
+		}
+
+		notificationReq := &models.SendNotificationRequest{
+			UserIDs:  userIDs,
+			Title:    "Referenced Document Superseded",
+			Body:     fmt.Sprintf("Document '%s' references '%s', which has been superseded by version %s. Please review the reference.", doc.Title, document.Title, document.Version),
+			Category: "document",
+			Data: map[string]interface{}{
+				"documentId":           doc.ID.Hex(),
+				"reference":            doc.Reference,
+				"referencedDocumentId": document.ID.Hex(),
+				"referencedReference":  document.Reference,
+				"currentVersion":       document.Version,
+				"action":               "reference_obsolete",
+			},
+		}
+		if _, err := s.notificationService.SendNotification(ctx, notificationReq, senderID); err != nil {
+			fmt.Printf("⚠️  Failed to notify owners of %s about stale reference: %v\n", doc.Reference, err)
+		}
+	}
+
+	if len(referencing) > 0 {
+		fmt.Printf("🔗 Flagged stale references to %s in %d documents\n", document.Reference, len(referencing))
+	}
+}
@@ -0,0 +1,471 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// qmsSyncCheckInterval is how often due syncs are pushed to the QMS
+	qmsSyncCheckInterval = time.Minute
+	// qmsRetryBaseDelay is the delay before the first retry, doubled on each failure
+	qmsRetryBaseDelay = time.Minute
+	// qmsRetryMaxDelay caps the delay between two retries
+	qmsRetryMaxDelay = 6 * time.Hour
+)
+
+// QMSSyncService publishes the metadata and PDF of published documents to an
+// external quality-management system and tracks the sync of each document
+type QMSSyncService struct {
+	settingsCollection *mongo.Collection
+	syncCollection     *mongo.Collection
+	documentService    *DocumentService
+	minioService       *MinIOService
+	client             *http.Client
+}
+
+// NewQMSSyncService creates a new QMS sync service instance
+func NewQMSSyncService(db *DatabaseService, documentService *DocumentService, minioService *MinIOService) *QMSSyncService {
+	service := &QMSSyncService{
+		settingsCollection: db.Collection("settings"),
+		syncCollection:     db.Collection("qms_syncs"),
+		documentService:    documentService,
+		minioService:       minioService,
+		client:             &http.Client{Timeout: 60 * time.Second},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.syncCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "document_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create QMS sync indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// GetConnector returns the connector settings, or the default disabled connector
+func (s *QMSSyncService) GetConnector(ctx context.Context) (*models.QMSConnector, error) {
+	connector := models.DefaultQMSConnector()
+	err := s.settingsCollection.FindOne(ctx, bson.M{"_id": models.QMSConnectorSettingsID}).Decode(connector)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to get QMS connector: %w", err)
+	}
+	connector.HasAPIKey = connector.APIKey != ""
+	return connector, nil
+}
+
+// UpdateConnector saves the given connector settings
+func (s *QMSSyncService) UpdateConnector(ctx context.Context, req *models.UpdateQMSConnectorRequest, updatedBy primitive.ObjectID) (*models.QMSConnector, error) {
+	connector, err := s.GetConnector(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled != nil {
+		connector.Enabled = *req.Enabled
+	}
+	if req.Endpoint != nil {
+		connector.Endpoint = *req.Endpoint
+	}
+	if req.AuthHeader != nil {
+		connector.AuthHeader = *req.AuthHeader
+	}
+	if req.APIKey != nil {
+		connector.APIKey = *req.APIKey
+	}
+	if req.FieldMapping != nil {
+		for target, source := range *req.FieldMapping {
+			if target == "" {
+				return nil, errors.New("invalid QMS field mapping: empty target field")
+			}
+			if !slices.Contains(models.QMSSourceFields, source) {
+				return nil, fmt.Errorf("invalid QMS field mapping: unknown document field '%s'", source)
+			}
+		}
+		connector.FieldMapping = *req.FieldMapping
+	}
+	if req.IncludePDF != nil {
+		connector.IncludePDF = *req.IncludePDF
+	}
+	if req.MaxAttempts != nil {
+		connector.MaxAttempts = *req.MaxAttempts
+	}
+	if connector.Enabled && connector.Endpoint == "" {
+		return nil, errors.New("an endpoint is required to enable the QMS connector")
+	}
+
+	connector.UpdatedBy = &updatedBy
+	connector.UpdatedAt = time.Now()
+	if _, err := s.settingsCollection.ReplaceOne(ctx,
+		bson.M{"_id": models.QMSConnectorSettingsID},
+		connector,
+		options.Replace().SetUpsert(true),
+	); err != nil {
+		return nil, fmt.Errorf("failed to update QMS connector: %w", err)
+	}
+
+	connector.HasAPIKey = connector.APIKey != ""
+	return connector, nil
+}
+
+// Enqueue schedules the sync of a document right away. It does nothing while
+// the connector is disabled.
+func (s *QMSSyncService) Enqueue(ctx context.Context, document *models.Document) error {
+	connector, err := s.GetConnector(ctx)
+	if err != nil {
+		return err
+	}
+	if !connector.Enabled {
+		return nil
+	}
+	_, err = s.queue(ctx, document)
+	return err
+}
+
+// Sync schedules the sync of a document right away, whatever its previous
+// attempts, and returns its sync record
+func (s *QMSSyncService) Sync(ctx context.Context, documentID primitive.ObjectID) (*models.QMSSync, error) {
+	connector, err := s.GetConnector(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !connector.Enabled {
+		return nil, errors.New("QMS connector is disabled")
+	}
+
+	document, err := s.documentService.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(models.PublishedDocumentStatuses, document.Status) {
+		return nil, errors.New("only published documents can be synced")
+	}
+
+	return s.queue(ctx, document)
+}
+
+// queue resets the sync record of a document to pending
+func (s *QMSSyncService) queue(ctx context.Context, document *models.Document) (*models.QMSSync, error) {
+	now := time.Now()
+	var sync models.QMSSync
+	err := s.syncCollection.FindOneAndUpdate(ctx,
+		bson.M{"document_id": document.ID},
+		bson.M{
+			"$set": bson.M{
+				"reference":       document.Reference,
+				"version":         document.Version,
+				"status":          models.QMSSyncStatusPending,
+				"attempts":        0,
+				"next_attempt_at": now,
+				"updated_at":      now,
+			},
+			"$unset":       bson.M{"last_error": ""},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&sync)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue QMS sync: %w", err)
+	}
+	return &sync, nil
+}
+
+// GetByDocument returns the sync record of a document
+func (s *QMSSyncService) GetByDocument(ctx context.Context, documentID primitive.ObjectID) (*models.QMSSync, error) {
+	var sync models.QMSSync
+	if err := s.syncCollection.FindOne(ctx, bson.M{"document_id": documentID}).Decode(&sync); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("document has not been synced")
+		}
+		return nil, fmt.Errorf("failed to get QMS sync: %w", err)
+	}
+	return &sync, nil
+}
+
+// List returns the sync records, most recently updated first, optionally filtered by status
+func (s *QMSSyncService) List(ctx context.Context, status string, page, limit int) ([]*models.QMSSync, int64, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	total, err := s.syncCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count QMS syncs: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := s.syncCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find QMS syncs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	syncs := make([]*models.QMSSync, 0)
+	if err := cursor.All(ctx, &syncs); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode QMS syncs: %w", err)
+	}
+
+	return syncs, total, nil
+}
+
+// Start pushes the due syncs every minute until the context is cancelled
+func (s *QMSSyncService) Start(ctx context.Context) {
+	run := func() {
+		runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		count, err := s.RunDue(runCtx)
+		if err != nil {
+			fmt.Printf("Warning: Failed to run QMS syncs: %v\n", err)
+			return
+		}
+		if count > 0 {
+			fmt.Printf("🔄 Synced %d document(s) to the QMS\n", count)
+		}
+	}
+
+	go func() {
+		// Syncs interrupted by a restart are attempted again
+		if _, err := s.syncCollection.UpdateMany(ctx, bson.M{"status": models.QMSSyncStatusSyncing},
+			bson.M{"$set": bson.M{"status": models.QMSSyncStatusPending, "next_attempt_at": time.Now()}}); err != nil {
+			fmt.Printf("Warning: Failed to requeue interrupted QMS syncs: %v\n", err)
+		}
+
+		run()
+
+		ticker := time.NewTicker(qmsSyncCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				run()
+			}
+		}
+	}()
+	fmt.Printf("🔄 QMS sync worker started (every %s)\n", qmsSyncCheckInterval)
+}
+
+// RunDue pushes the pending and failed syncs whose next attempt is due and
+// returns how many succeeded
+func (s *QMSSyncService) RunDue(ctx context.Context) (int, error) {
+	connector, err := s.GetConnector(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !connector.Enabled {
+		return 0, nil
+	}
+
+	synced := 0
+	for {
+		now := time.Now()
+		var sync models.QMSSync
+		err := s.syncCollection.FindOneAndUpdate(ctx,
+			bson.M{
+				"status":          bson.M{"$in": []models.QMSSyncStatus{models.QMSSyncStatusPending, models.QMSSyncStatusFailed}},
+				"next_attempt_at": bson.M{"$lte": now},
+			},
+			bson.M{
+				"$set": bson.M{"status": models.QMSSyncStatusSyncing, "last_attempt_at": now, "updated_at": now},
+				"$inc": bson.M{"attempts": 1},
+			},
+			options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).SetReturnDocument(options.After),
+		).Decode(&sync)
+		if err == mongo.ErrNoDocuments {
+			return synced, nil
+		}
+		if err != nil {
+			return synced, fmt.Errorf("failed to claim QMS sync: %w", err)
+		}
+
+		if s.attempt(ctx, connector, &sync) {
+			synced++
+		}
+	}
+}
+
+// attempt pushes one document and records the outcome on its sync record
+func (s *QMSSyncService) attempt(ctx context.Context, connector *models.QMSConnector, sync *models.QMSSync) bool {
+	version, statusCode, pushErr := s.push(ctx, connector, sync.DocumentID)
+
+	now := time.Now()
+	set := bson.M{"updated_at": now}
+	unset := bson.M{}
+	if statusCode > 0 {
+		set["last_response"] = statusCode
+	}
+
+	if pushErr == nil {
+		set["status"] = models.QMSSyncStatusSynced
+		set["synced_at"] = now
+		set["synced_version"] = version
+		unset["last_error"] = ""
+		unset["next_attempt_at"] = ""
+	} else {
+		fmt.Printf("⚠️ [QMS] Failed to sync document %s (attempt %d): %v\n", sync.Reference, sync.Attempts, pushErr)
+		set["status"] = models.QMSSyncStatusFailed
+		set["last_error"] = pushErr.Error()
+		if sync.Attempts < connector.MaxAttempts {
+			set["next_attempt_at"] = now.Add(qmsRetryDelay(sync.Attempts))
+		} else {
+			unset["next_attempt_at"] = ""
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	// A sync queued again while this attempt ran is left pending
+	if _, err := s.syncCollection.UpdateOne(ctx,
+		bson.M{"_id": sync.ID, "status": models.QMSSyncStatusSyncing},
+		update,
+	); err != nil {
+		fmt.Printf("⚠️ [QMS] Failed to record sync of %s: %v\n", sync.Reference, err)
+	}
+
+	return pushErr == nil
+}
+
+// push sends a document to the QMS endpoint. It returns the synced version and
+// the HTTP status of the response, if any.
+func (s *QMSSyncService) push(ctx context.Context, connector *models.QMSConnector, documentID primitive.ObjectID) (string, int, error) {
+	document, err := s.documentService.GetByID(ctx, documentID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if document.PdfUrl == "" && (connector.IncludePDF || slices.Contains(mappedSources(connector), "pdfUrl")) {
+		pdfURL, err := s.documentService.ExportPDF(ctx, documentID, models.PDFExportOptions{})
+		if err != nil {
+			return "", 0, err
+		}
+		document.PdfUrl = pdfURL
+	}
+
+	payload := qmsPayload(document, connector.FieldMapping)
+	if connector.IncludePDF {
+		content, err := s.minioService.DownloadFile(ctx, document.PdfUrl)
+		if err != nil {
+			return "", 0, err
+		}
+		payload["pdf"] = map[string]interface{}{
+			"fileName":    fmt.Sprintf("%s_v%s.pdf", document.Reference, document.Version),
+			"contentType": "application/pdf",
+			"content":     base64.StdEncoding.EncodeToString(content),
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to marshal QMS payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, connector.Endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create QMS request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if connector.APIKey != "" {
+		req.Header.Set(connector.AuthHeader, connector.APIKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("QMS request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", resp.StatusCode, fmt.Errorf("QMS API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	return document.Version, resp.StatusCode, nil
+}
+
+// qmsRetryDelay returns the delay before the retry following the given attempt
+func qmsRetryDelay(attempts int) time.Duration {
+	delay := qmsRetryBaseDelay
+	for i := 1; i < attempts && delay < qmsRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, qmsRetryMaxDelay)
+}
+
+// mappedSources returns the document fields sent by the connector
+func mappedSources(connector *models.QMSConnector) []string {
+	sources := make([]string, 0, len(connector.FieldMapping))
+	for _, source := range connector.FieldMapping {
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+// qmsPayload maps the document fields to the QMS field names
+func qmsPayload(document *models.Document, mapping map[string]string) map[string]interface{} {
+	contributorNames := func(contributors []models.Contributor) []string {
+		names := make([]string, 0, len(contributors))
+		for _, contributor := range contributors {
+			names = append(names, contributor.Name)
+		}
+		return names
+	}
+	hexOrNil := func(id *primitive.ObjectID) interface{} {
+		if id == nil {
+			return nil
+		}
+		return id.Hex()
+	}
+
+	values := map[string]interface{}{
+		"id":               document.ID.Hex(),
+		"reference":        document.Reference,
+		"processCode":      document.ProcessCode,
+		"macroId":          hexOrNil(document.MacroID),
+		"title":            document.Title,
+		"shortDescription": document.ShortDescription,
+		"description":      document.Description,
+		"version":          document.Version,
+		"status":           document.Status,
+		"stakeholders":     document.Stakeholders,
+		"effectiveDate":    document.EffectiveDate,
+		"supersessionDate": document.SupersessionDate,
+		"approvedAt":       document.ApprovedAt,
+		"nextReviewDate":   document.NextReviewDate,
+		"supersedes":       hexOrNil(document.Supersedes),
+		"supersededBy":     hexOrNil(document.SupersededBy),
+		"authors":          contributorNames(document.Contributors.Authors),
+		"verifiers":        contributorNames(document.Contributors.Verifiers),
+		"validators":       contributorNames(document.Contributors.Validators),
+		"pdfUrl":           document.PdfUrl,
+	}
+
+	payload := make(map[string]interface{}, len(mapping))
+	for target, source := range mapping {
+		payload[target] = values[source]
+	}
+	return payload
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReactionService handles acknowledgment reactions on comments and announcements
+type ReactionService struct {
+	collection *mongo.Collection
+}
+
+// NewReactionService creates a new reaction service instance
+func NewReactionService(db *DatabaseService) *ReactionService {
+	collection := db.Collection("reactions")
+
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "target_type", Value: 1},
+				{Key: "target_id", Value: 1},
+				{Key: "user_id", Value: 1},
+				{Key: "type", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		fmt.Printf("Warning: Failed to create reaction indexes: %v\n", err)
+	}
+
+	return &ReactionService{collection: collection}
+}
+
+// Add records a reaction; reacting twice with the same type is a no-op
+func (s *ReactionService) Add(ctx context.Context, targetType models.ReactionTargetType, targetID, userID primitive.ObjectID, reactionType models.ReactionType) error {
+	if !models.IsValidReactionType(reactionType) {
+		return fmt.Errorf("invalid reaction type: %s", reactionType)
+	}
+
+	filter := bson.M{
+		"target_type": targetType,
+		"target_id":   targetID,
+		"user_id":     userID,
+		"type":        reactionType,
+	}
+	update := bson.M{"$setOnInsert": bson.M{"created_at": time.Now()}}
+
+	if _, err := s.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes a user's reaction
+func (s *ReactionService) Remove(ctx context.Context, targetType models.ReactionTargetType, targetID, userID primitive.ObjectID, reactionType models.ReactionType) error {
+	if !models.IsValidReactionType(reactionType) {
+		return fmt.Errorf("invalid reaction type: %s", reactionType)
+	}
+
+	_, err := s.collection.DeleteOne(ctx, bson.M{
+		"target_type": targetType,
+		"target_id":   targetID,
+		"user_id":     userID,
+		"type":        reactionType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove reaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveAll deletes every reaction on a target
+func (s *ReactionService) RemoveAll(ctx context.Context, targetType models.ReactionTargetType, targetID primitive.ObjectID) error {
+	if _, err := s.collection.DeleteMany(ctx, bson.M{"target_type": targetType, "target_id": targetID}); err != nil {
+		return fmt.Errorf("failed to remove reactions: %w", err)
+	}
+	return nil
+}
+
+// Summaries aggregates the reactions of several targets, flagging those of the given user
+func (s *ReactionService) Summaries(ctx context.Context, targetType models.ReactionTargetType, targetIDs []primitive.ObjectID, userID primitive.ObjectID) (map[primitive.ObjectID]*models.ReactionSummary, error) {
+	summaries := make(map[primitive.ObjectID]*models.ReactionSummary, len(targetIDs))
+	for _, id := range targetIDs {
+		summaries[id] = models.NewReactionSummary()
+	}
+	if len(targetIDs) == 0 {
+		return summaries, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"target_type": targetType,
+			"target_id":   bson.M{"$in": targetIDs},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"target_id": "$target_id", "type": "$type"},
+			"count": bson.M{"$sum": 1},
+			"mine":  bson.M{"$max": bson.M{"$eq": bson.A{"$user_id", userID}}},
+		}}},
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate reactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID struct {
+			TargetID primitive.ObjectID  `bson:"target_id"`
+			Type     models.ReactionType `bson:"type"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+		Mine  bool  `bson:"mine"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode reactions: %w", err)
+	}
+
+	for _, r := range results {
+		summary, ok := summaries[r.ID.TargetID]
+		if !ok {
+			continue
+		}
+		summary.Counts[r.ID.Type] = r.Count
+		summary.Total += r.Count
+		if r.Mine {
+			summary.Mine = append(summary.Mine, r.ID.Type)
+		}
+	}
+
+	return summaries, nil
+}
+
+// Summary aggregates the reactions of a single target
+func (s *ReactionService) Summary(ctx context.Context, targetType models.ReactionTargetType, targetID, userID primitive.ObjectID) (*models.ReactionSummary, error) {
+	summaries, err := s.Summaries(ctx, targetType, []primitive.ObjectID{targetID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	return summaries[targetID], nil
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxReferenceAttempts bounds how many sequence numbers are skipped because a
+// document was given the reference manually
+const maxReferenceAttempts = 100
+
+// ErrInvalidReferencePattern is returned when a pattern cannot generate unique references
+var ErrInvalidReferencePattern = errors.New("reference pattern must contain " + models.ReferenceTokenSequence)
+
+// ReferenceService generates document references from the configured scheme
+type ReferenceService struct {
+	settingsCollection   *mongo.Collection
+	counterCollection    *mongo.Collection
+	documentCollection   *mongo.Collection
+	departmentCollection *mongo.Collection
+	userCollection       *mongo.Collection
+	macroService         *MacroService
+}
+
+// NewReferenceService creates a new reference service instance
+func NewReferenceService(db *DatabaseService, macroService *MacroService) *ReferenceService {
+	return &ReferenceService{
+		settingsCollection:   db.Collection("settings"),
+		counterCollection:    db.Collection("reference_counters"),
+		documentCollection:   db.Collection("documents"),
+		departmentCollection: db.Collection("departments"),
+		userCollection:       db.Collection("users"),
+		macroService:         macroService,
+	}
+}
+
+// GetScheme returns the configured scheme, or the default one when none was saved
+func (s *ReferenceService) GetScheme(ctx context.Context) (*models.ReferenceScheme, error) {
+	scheme := models.DefaultReferenceScheme()
+	err := s.settingsCollection.FindOne(ctx, bson.M{"_id": models.ReferenceSchemeSettingsID}).Decode(scheme)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to get reference scheme: %w", err)
+	}
+	return scheme, nil
+}
+
+// UpdateScheme saves the given scheme fields. Counters are kept per resolved
+// pattern, so a new pattern starts its own sequences.
+func (s *ReferenceService) UpdateScheme(ctx context.Context, req *models.UpdateReferenceSchemeRequest, updatedBy primitive.ObjectID) (*models.ReferenceScheme, error) {
+	scheme, err := s.GetScheme(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Pattern != nil {
+		if !strings.Contains(*req.Pattern, models.ReferenceTokenSequence) {
+			return nil, ErrInvalidReferencePattern
+		}
+		scheme.Pattern = *req.Pattern
+	}
+	if req.SequencePadding != nil {
+		scheme.SequencePadding = *req.SequencePadding
+	}
+	if req.DefaultDept != nil {
+		scheme.DefaultDept = *req.DefaultDept
+	}
+	scheme.UpdatedBy = &updatedBy
+	scheme.UpdatedAt = time.Now()
+
+	if _, err := s.settingsCollection.ReplaceOne(ctx,
+		bson.M{"_id": models.ReferenceSchemeSettingsID},
+		scheme,
+		options.Replace().SetUpsert(true),
+	); err != nil {
+		return nil, fmt.Errorf("failed to update reference scheme: %w", err)
+	}
+
+	return scheme, nil
+}
+
+// Preview returns the reference the next document would receive, without reserving it
+func (s *ReferenceService) Preview(ctx context.Context, macroID primitive.ObjectID, departmentID *primitive.ObjectID, userID primitive.ObjectID) (*models.ReferencePreview, error) {
+	scheme, counterKey, err := s.resolve(ctx, macroID, departmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var counter models.ReferenceCounter
+	err = s.counterCollection.FindOne(ctx, bson.M{"_id": counterKey}).Decode(&counter)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to get reference counter: %w", err)
+	}
+
+	for sequence := counter.Sequence + 1; sequence <= counter.Sequence+maxReferenceAttempts; sequence++ {
+		reference := formatReference(counterKey, sequence, scheme.SequencePadding)
+		exists, err := s.referenceExists(ctx, reference)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return &models.ReferencePreview{Reference: reference, Pattern: scheme.Pattern}, nil
+		}
+	}
+
+	return nil, errors.New("failed to find a free reference")
+}
+
+// Next reserves and returns the next free reference for a new document
+func (s *ReferenceService) Next(ctx context.Context, macroID primitive.ObjectID, departmentID *primitive.ObjectID, userID primitive.ObjectID) (string, error) {
+	scheme, counterKey, err := s.resolve(ctx, macroID, departmentID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	for attempt := 0; attempt < maxReferenceAttempts; attempt++ {
+		var counter models.ReferenceCounter
+		err := s.counterCollection.FindOneAndUpdate(ctx,
+			bson.M{"_id": counterKey},
+			bson.M{
+				"$inc": bson.M{"sequence": 1},
+				"$set": bson.M{"updated_at": time.Now()},
+			},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&counter)
+		if err != nil {
+			return "", fmt.Errorf("failed to increment reference counter: %w", err)
+		}
+
+		reference := formatReference(counterKey, counter.Sequence, scheme.SequencePadding)
+		exists, err := s.referenceExists(ctx, reference)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return reference, nil
+		}
+	}
+
+	return "", errors.New("failed to find a free reference")
+}
+
+// resolve returns the scheme and its pattern with every token but {SEQ}
+// replaced, which is also the key of the counter to use
+func (s *ReferenceService) resolve(ctx context.Context, macroID primitive.ObjectID, departmentID *primitive.ObjectID, userID primitive.ObjectID) (*models.ReferenceScheme, string, error) {
+	scheme, err := s.GetScheme(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	macro, err := s.macroService.GetMacroByID(ctx, macroID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get macro: %w", err)
+	}
+
+	departmentCode, err := s.departmentCode(ctx, departmentID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if departmentCode == "" {
+		departmentCode = scheme.DefaultDept
+	}
+
+	key := strings.NewReplacer(
+		models.ReferenceTokenDepartment, strings.ToUpper(departmentCode),
+		models.ReferenceTokenMacro, macro.Code,
+		models.ReferenceTokenYear, strconv.Itoa(time.Now().Year()),
+	).Replace(scheme.Pattern)
+
+	return scheme, key, nil
+}
+
+// departmentCode returns the code of the given department, or of the user's
+// department when none is given
+func (s *ReferenceService) departmentCode(ctx context.Context, departmentID *primitive.ObjectID, userID primitive.ObjectID) (string, error) {
+	if departmentID == nil {
+		var user models.User
+		err := s.userCollection.FindOne(ctx, bson.M{"_id": userID},
+			options.FindOne().SetProjection(bson.M{"department_id": 1})).Decode(&user)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return "", fmt.Errorf("failed to get user department: %w", err)
+		}
+		if user.DepartmentID == nil {
+			return "", nil
+		}
+		departmentID = user.DepartmentID
+	}
+
+	var department models.Department
+	if err := s.departmentCollection.FindOne(ctx, bson.M{"_id": *departmentID}).Decode(&department); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", errors.New("department not found")
+		}
+		return "", fmt.Errorf("failed to get department: %w", err)
+	}
+	return department.Code, nil
+}
+
+// referenceExists checks the reference against every document, trashed ones included
+func (s *ReferenceService) referenceExists(ctx context.Context, reference string) (bool, error) {
+	count, err := s.documentCollection.CountDocuments(ctx, bson.M{"reference": reference})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// formatReference replaces {SEQ} with the zero-padded sequence number
+func formatReference(counterKey string, sequence int64, padding int) string {
+	return strings.ReplaceAll(counterKey, models.ReferenceTokenSequence, fmt.Sprintf("%0*d", padding, sequence))
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReportService builds the periodic registers reviewed by the quality team
+type ReportService struct {
+	signatureCollection *mongo.Collection
+	documentCollection  *mongo.Collection
+	userCollection      *mongo.Collection
+}
+
+// NewReportService creates a new report service instance
+func NewReportService(db *DatabaseService) *ReportService {
+	service := &ReportService{
+		signatureCollection: db.Collection("signatures"),
+		documentCollection:  db.Collection("documents"),
+		userCollection:      db.Collection("users"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.signatureCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "signed_at", Value: 1}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create signature indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// SignatureRegister returns every signature made in [from, to), oldest first.
+// Trashed documents are kept since the register is an audit record.
+func (s *ReportService) SignatureRegister(ctx context.Context, from, to time.Time) ([]models.SignatureRegisterEntry, error) {
+	cursor, err := s.signatureCollection.Find(ctx,
+		bson.M{"signed_at": bson.M{"$gte": from, "$lt": to}},
+		options.Find().SetSort(bson.D{{Key: "signed_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signatures: %w", err)
+	}
+	var signatures []models.Signature
+	if err := cursor.All(ctx, &signatures); err != nil {
+		return nil, fmt.Errorf("failed to decode signatures: %w", err)
+	}
+
+	entries := make([]models.SignatureRegisterEntry, 0, len(signatures))
+	if len(signatures) == 0 {
+		return entries, nil
+	}
+
+	documentIDs := make([]primitive.ObjectID, 0, len(signatures))
+	userIDs := make([]primitive.ObjectID, 0, len(signatures))
+	for _, signature := range signatures {
+		documentIDs = append(documentIDs, signature.DocumentID)
+		userIDs = append(userIDs, signature.UserID)
+	}
+
+	documents := make(map[primitive.ObjectID]models.Document)
+	cursor, err = s.documentCollection.Find(ctx, bson.M{"_id": bson.M{"$in": documentIDs}},
+		options.Find().SetProjection(bson.M{"reference": 1, "title": 1, "version": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signed documents: %w", err)
+	}
+	var docs []models.Document
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode signed documents: %w", err)
+	}
+	for _, doc := range docs {
+		documents[doc.ID] = doc
+	}
+
+	users := make(map[primitive.ObjectID]models.User)
+	cursor, err = s.userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}},
+		options.Find().SetProjection(bson.M{"first_name": 1, "last_name": 1, "email": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signers: %w", err)
+	}
+	var signers []models.User
+	if err := cursor.All(ctx, &signers); err != nil {
+		return nil, fmt.Errorf("failed to decode signers: %w", err)
+	}
+	for _, user := range signers {
+		users[user.ID] = user
+	}
+
+	for _, signature := range signatures {
+		document := documents[signature.DocumentID]
+		signer := users[signature.UserID]
+
+		// Signatures made before the version was recorded fall back to the current one
+		version := signature.Version
+		if version == "" {
+			version = document.Version
+		}
+
+		entries = append(entries, models.SignatureRegisterEntry{
+			SignatureID:       signature.ID,
+			SignedAt:          signature.SignedAt,
+			DocumentID:        signature.DocumentID,
+			DocumentReference: document.Reference,
+			DocumentTitle:     document.Title,
+			Version:           version,
+			SignerName:        fmt.Sprintf("%s %s", signer.FirstName, signer.LastName),
+			SignerEmail:       signer.Email,
+			Role:              signature.Type,
+			Method:            signature.Method(),
+			IPAddress:         signature.IPAddress,
+		})
+	}
+
+	return entries, nil
+}
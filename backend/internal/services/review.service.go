@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReviewService schedules the periodic re-validation of published procedures
+type ReviewService struct {
+	documentCollection  *mongo.Collection
+	notificationService *NotificationService
+	emailService        *EmailService
+	userService         *UserService
+}
+
+// NewReviewService creates a new review scheduling service
+func NewReviewService(db *DatabaseService, notificationService *NotificationService, emailService *EmailService, userService *UserService) *ReviewService {
+	service := &ReviewService{
+		documentCollection:  db.Collection("documents"),
+		notificationService: notificationService,
+		emailService:        emailService,
+		userService:         userService,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.documentCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_review_date", Value: 1}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create review indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// reviewIntervalMonths returns the time between two reviews of a published
+// document, set by DOCUMENT_REVIEW_INTERVAL_MONTHS. Telecom procedures must
+// be re-validated yearly, hence the 12 months default.
+func reviewIntervalMonths() int {
+	if v, err := strconv.Atoi(os.Getenv("DOCUMENT_REVIEW_INTERVAL_MONTHS")); err == nil && v > 0 {
+		return v
+	}
+	return 12
+}
+
+// NextReviewDate returns the review deadline of a document published or reviewed at the given time
+func NextReviewDate(from time.Time) time.Time {
+	return from.AddDate(0, reviewIntervalMonths(), 0)
+}
+
+// Start runs the review scan once a day until the context is cancelled
+func (s *ReviewService) Start(ctx context.Context) {
+	scan := func() {
+		scanCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		count, err := s.RunScan(scanCtx)
+		if err != nil {
+			fmt.Printf("Warning: Failed to scan document reviews: %v\n", err)
+			return
+		}
+		if count > 0 {
+			fmt.Printf("🔁 %d document(s) are now due for review\n", count)
+		}
+	}
+
+	go func() {
+		scan()
+
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				scan()
+			}
+		}
+	}()
+	fmt.Printf("🔁 Document review scheduler started (interval: %d months)\n", reviewIntervalMonths())
+}
+
+// RunScan schedules archived documents that have no review date yet, then
+// moves the overdue ones to review_due and notifies their authors. It returns
+// the number of documents that became due.
+func (s *ReviewService) RunScan(ctx context.Context) (int, error) {
+	if err := s.scheduleUnplanned(ctx); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	cursor, err := s.documentCollection.Find(ctx, models.NotDeleted(bson.M{
+		"status":           models.DocumentStatusArchived,
+		"next_review_date": bson.M{"$lte": now},
+	}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find overdue documents: %w", err)
+	}
+	var overdue []*models.Document
+	if err := cursor.All(ctx, &overdue); err != nil {
+		return 0, fmt.Errorf("failed to decode overdue documents: %w", err)
+	}
+
+	count := 0
+	for _, document := range overdue {
+		// The status condition keeps concurrent scans from notifying twice
+		result, err := s.documentCollection.UpdateOne(ctx, bson.M{
+			"_id":    document.ID,
+			"status": models.DocumentStatusArchived,
+		}, bson.M{"$set": bson.M{
+			"status":     models.DocumentStatusReviewDue,
+			"updated_at": now,
+		}})
+		if err != nil {
+			return count, fmt.Errorf("failed to mark document %s as due for review: %w", document.Reference, err)
+		}
+		if result.ModifiedCount == 0 {
+			continue
+		}
+
+		count++
+		s.notifyAuthors(ctx, document)
+	}
+
+	return count, nil
+}
+
+// scheduleUnplanned sets the review date of archived documents published before reviews existed
+func (s *ReviewService) scheduleUnplanned(ctx context.Context) error {
+	cursor, err := s.documentCollection.Find(ctx, models.NotDeleted(bson.M{
+		"status":           models.DocumentStatusArchived,
+		"next_review_date": bson.M{"$exists": false},
+	}), options.Find().SetProjection(bson.M{"approved_at": 1, "updated_at": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to find unscheduled documents: %w", err)
+	}
+	var unplanned []struct {
+		ID         primitive.ObjectID `bson:"_id"`
+		ApprovedAt *time.Time         `bson:"approved_at"`
+		UpdatedAt  time.Time          `bson:"updated_at"`
+	}
+	if err := cursor.All(ctx, &unplanned); err != nil {
+		return fmt.Errorf("failed to decode unscheduled documents: %w", err)
+	}
+
+	for _, doc := range unplanned {
+		publishedAt := doc.UpdatedAt
+		if doc.ApprovedAt != nil {
+			publishedAt = *doc.ApprovedAt
+		}
+		if _, err := s.documentCollection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{
+			"$set": bson.M{"next_review_date": NextReviewDate(publishedAt)},
+		}); err != nil {
+			return fmt.Errorf("failed to schedule document review: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// notifyAuthors sends the review reminder to the creator and authors of a document
+func (s *ReviewService) notifyAuthors(ctx context.Context, document *models.Document) {
+	recipients := []primitive.ObjectID{document.CreatedBy}
+	seen := map[primitive.ObjectID]bool{document.CreatedBy: true}
+	for _, author := range document.Contributors.Authors {
+		if !seen[author.UserID] {
+			seen[author.UserID] = true
+			recipients = append(recipients, author.UserID)
+		}
+	}
+
+	if s.notificationService != nil {
+		userIDs := make([]string, 0, len(recipients))
+		for _, id := range recipients {
+			userIDs = append(userIDs, id.Hex())
+		}
+		notificationReq := &models.SendNotificationRequest{
+			UserIDs:  userIDs,
+			Title:    "Periodic review due",
+			Body:     fmt.Sprintf("Document '%s' (%s) has reached its review date and must be re-validated.", document.Title, document.Reference),
+			Category: "document",
+			Priority: models.NotificationPriorityHigh,
+			Data: map[string]interface{}{
+				"documentId": document.ID.Hex(),
+				"reference":  document.Reference,
+				"title":      document.Title,
+				"action":     "review_due",
+			},
+		}
+		if _, err := s.notificationService.SendNotification(ctx, notificationReq, primitive.NilObjectID); err != nil {
+			fmt.Printf("⚠️  Failed to send review notification for %s: %v\n", document.Reference, err)
+		}
+	}
+
+	if s.emailService == nil || s.userService == nil {
+		return
+	}
+	for _, id := range recipients {
+		user, err := s.userService.GetUserByID(ctx, id)
+		if err != nil || !user.Active {
+			continue
+		}
+		// The review notification is already part of their next digest
+		if s.notificationService != nil && s.notificationService.UsesEmailDigest(ctx, user.ID) {
+			continue
+		}
+		name := fmt.Sprintf("%s %s", user.FirstName, user.LastName)
+		if err := s.emailService.SendReviewDueEmail(user.Email, name, document.Title, document.Reference, document.ID.Hex(), *document.NextReviewDate, user.Language); err != nil {
+			fmt.Printf("⚠️  Failed to send review email to %s: %v\n", user.Email, err)
+		}
+	}
+}
+
+// CompleteReview records that a published document has been re-validated
+// and schedules its next review. Documents may be reviewed ahead of time.
+func (s *ReviewService) CompleteReview(ctx context.Context, documentID, userID primitive.ObjectID) (*models.Document, error) {
+	var existing models.Document
+	if err := s.documentCollection.FindOne(ctx, models.NotDeleted(bson.M{"_id": documentID})).Decode(&existing); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("document not found")
+		}
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if existing.Status != models.DocumentStatusArchived && existing.Status != models.DocumentStatusReviewDue {
+		return nil, fmt.Errorf("only published documents can be reviewed, document is in '%s' status", existing.Status)
+	}
+
+	now := time.Now()
+	var document models.Document
+	err := s.documentCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": documentID, "status": existing.Status},
+		bson.M{"$set": bson.M{
+			"status":           models.DocumentStatusArchived,
+			"next_review_date": NextReviewDate(now),
+			"last_reviewed_at": now,
+			"last_reviewed_by": userID,
+			"updated_at":       now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&document)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("document status changed, please retry")
+		}
+		return nil, fmt.Errorf("failed to complete review: %w", err)
+	}
+
+	return &document, nil
+}
+
+// ListUpcoming returns the published documents due for review within the given number of days,
+// overdue ones first
+func (s *ReviewService) ListUpcoming(ctx context.Context, days int) ([]*models.Document, error) {
+	filter := models.NotDeleted(bson.M{
+		"status": bson.M{"$in": []models.DocumentStatus{
+			models.DocumentStatusArchived,
+			models.DocumentStatusReviewDue,
+		}},
+		"next_review_date": bson.M{"$lte": time.Now().AddDate(0, 0, days)},
+	})
+	findOptions := options.Find().SetSort(bson.D{{Key: "next_review_date", Value: 1}})
+
+	cursor, err := s.documentCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	documents := make([]*models.Document, 0)
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode documents: %w", err)
+	}
+
+	return documents, nil
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SnippetService manages the library of process step snippets shared by documents
+type SnippetService struct {
+	collection         *mongo.Collection
+	documentCollection *mongo.Collection
+	documentService    *DocumentService
+}
+
+// NewSnippetService creates a new snippet service instance
+func NewSnippetService(db *DatabaseService, documentService *DocumentService) *SnippetService {
+	collection := db.Collection("snippets")
+	documentCollection := db.Collection("documents")
+
+	ctx := context.Background()
+	if _, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "name", Value: 1}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create snippet indexes: %v\n", err)
+	}
+	if _, err := documentCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "process_groups.process_steps.snippet.snippet_id", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create document snippet indexes: %v\n", err)
+	}
+
+	return &SnippetService{
+		collection:         collection,
+		documentCollection: documentCollection,
+		documentService:    documentService,
+	}
+}
+
+// Create adds a snippet to the library, at version 1
+func (s *SnippetService) Create(ctx context.Context, req *models.CreateSnippetRequest, userID primitive.ObjectID) (*models.Snippet, error) {
+	now := time.Now()
+	snippet := &models.Snippet{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Steps:       normalizeSnippetSteps(req.Steps),
+		Version:     1,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	result, err := s.collection.InsertOne(ctx, snippet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snippet: %w", err)
+	}
+	snippet.ID = result.InsertedID.(primitive.ObjectID)
+
+	return snippet, nil
+}
+
+// GetByID retrieves a snippet by ID
+func (s *SnippetService) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Snippet, error) {
+	var snippet models.Snippet
+	if err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&snippet); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("snippet not found")
+		}
+		return nil, fmt.Errorf("failed to get snippet: %w", err)
+	}
+	return &snippet, nil
+}
+
+// List returns the snippets matching the filter, by name
+func (s *SnippetService) List(ctx context.Context, filter *models.SnippetFilter) ([]models.Snippet, int64, error) {
+	query := bson.M{}
+	if filter.Search != nil && *filter.Search != "" {
+		query["name"] = primitive.Regex{Pattern: regexp.QuoteMeta(*filter.Search), Options: "i"}
+	}
+
+	total, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count snippets: %w", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+		if filter.Page > 0 {
+			opts.SetSkip(int64((filter.Page - 1) * filter.Limit))
+		}
+	}
+
+	cursor, err := s.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	snippets := make([]models.Snippet, 0)
+	if err := cursor.All(ctx, &snippets); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+
+	return snippets, total, nil
+}
+
+// Update updates a snippet. A change of the steps increments its version,
+// the documents embedding the previous version become outdated.
+func (s *SnippetService) Update(ctx context.Context, id primitive.ObjectID, req *models.UpdateSnippetRequest, userID primitive.ObjectID) (*models.Snippet, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"updated_by": userID,
+			"updated_at": time.Now(),
+		},
+	}
+	setFields := update["$set"].(bson.M)
+
+	if req.Name != nil {
+		setFields["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		setFields["description"] = *req.Description
+	}
+	if req.Steps != nil {
+		setFields["steps"] = normalizeSnippetSteps(*req.Steps)
+		update["$inc"] = bson.M{"version": 1}
+	}
+
+	var snippet models.Snippet
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&snippet)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("snippet not found")
+		}
+		return nil, fmt.Errorf("failed to update snippet: %w", err)
+	}
+
+	return &snippet, nil
+}
+
+// Delete removes a snippet from the library. Documents keep their copy of
+// its steps.
+func (s *SnippetService) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete snippet: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("snippet not found")
+	}
+	return nil
+}
+
+// Embed copies the steps of a snippet into a process group of a document, at
+// the given position or at the end of the group. The copied steps reference
+// the snippet and its current version.
+func (s *SnippetService) Embed(ctx context.Context, snippetID, documentID primitive.ObjectID, req *models.EmbedSnippetRequest, userID primitive.ObjectID, userRole models.UserRole) (*models.Document, error) {
+	snippet, err := s.GetByID(ctx, snippetID)
+	if err != nil {
+		return nil, err
+	}
+	document, err := s.documentService.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := document.ProcessGroups
+	index := -1
+	for i := range groups {
+		if groups[i].ID == req.GroupID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, errors.New("process group not found")
+	}
+
+	steps := groups[index].ProcessSteps
+	position := len(steps)
+	if req.Position != nil && *req.Position < position {
+		position = *req.Position
+	}
+
+	embedded := make([]models.ProcessStep, 0, len(snippet.Steps))
+	for _, step := range snippet.Steps {
+		embedded = append(embedded, models.ProcessStep{
+			ID:           primitive.NewObjectID().Hex(),
+			Title:        step.Title,
+			Outputs:      step.Outputs,
+			Durations:    step.Durations,
+			Responsible:  step.Responsible,
+			Descriptions: step.Descriptions,
+			Snippet:      &models.SnippetRef{SnippetID: snippet.ID, Version: snippet.Version},
+		})
+	}
+
+	merged := make([]models.ProcessStep, 0, len(steps)+len(embedded))
+	merged = append(merged, steps[:position]...)
+	merged = append(merged, embedded...)
+	merged = append(merged, steps[position:]...)
+	for i := range merged {
+		merged[i].Order = i + 1
+	}
+	groups[index].ProcessSteps = merged
+
+	revision := document.Revision
+	return s.documentService.Update(ctx, documentID, &models.UpdateDocumentRequest{
+		ProcessGroups: &groups,
+		Revision:      &revision,
+	}, userID, userRole)
+}
+
+// Usages lists the documents embedding a snippet, or any snippet when
+// snippetID is nil. With outdatedOnly, only the documents embedding an older
+// version than the current one are listed.
+func (s *SnippetService) Usages(ctx context.Context, snippetID *primitive.ObjectID, outdatedOnly bool) ([]models.SnippetUsage, error) {
+	snippetQuery := bson.M{}
+	if snippetID != nil {
+		snippetQuery["_id"] = *snippetID
+	}
+	cursor, err := s.collection.Find(ctx, snippetQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snippets: %w", err)
+	}
+	var snippets []models.Snippet
+	if err := cursor.All(ctx, &snippets); err != nil {
+		return nil, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+	if snippetID != nil && len(snippets) == 0 {
+		return nil, errors.New("snippet not found")
+	}
+
+	byID := make(map[primitive.ObjectID]*models.Snippet, len(snippets))
+	ids := make([]primitive.ObjectID, 0, len(snippets))
+	for i := range snippets {
+		byID[snippets[i].ID] = &snippets[i]
+		ids = append(ids, snippets[i].ID)
+	}
+
+	usages := make([]models.SnippetUsage, 0)
+	if len(ids) == 0 {
+		return usages, nil
+	}
+
+	cursor, err = s.documentCollection.Find(ctx,
+		models.NotDeleted(bson.M{"process_groups.process_steps.snippet.snippet_id": bson.M{"$in": ids}}),
+		options.Find().
+			SetSort(bson.D{{Key: "reference", Value: 1}}).
+			SetProjection(bson.M{"reference": 1, "title": 1, "status": 1, "process_groups": 1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents embedding snippets: %w", err)
+	}
+	var documents []models.Document
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode documents embedding snippets: %w", err)
+	}
+
+	for _, document := range documents {
+		// Embedded versions and step counts by snippet
+		versions := make(map[primitive.ObjectID]map[int]bool)
+		counts := make(map[primitive.ObjectID]int)
+		for _, group := range document.ProcessGroups {
+			for _, step := range group.ProcessSteps {
+				if step.Snippet == nil || byID[step.Snippet.SnippetID] == nil {
+					continue
+				}
+				if versions[step.Snippet.SnippetID] == nil {
+					versions[step.Snippet.SnippetID] = make(map[int]bool)
+				}
+				versions[step.Snippet.SnippetID][step.Snippet.Version] = true
+				counts[step.Snippet.SnippetID]++
+			}
+		}
+
+		for id, embedded := range versions {
+			snippet := byID[id]
+			usage := models.SnippetUsage{
+				SnippetID:      id.Hex(),
+				SnippetName:    snippet.Name,
+				CurrentVersion: snippet.Version,
+				DocumentID:     document.ID.Hex(),
+				Reference:      document.Reference,
+				Title:          document.Title,
+				Status:         document.Status,
+				Versions:       make([]int, 0, len(embedded)),
+				StepCount:      counts[id],
+			}
+			for version := range embedded {
+				usage.Versions = append(usage.Versions, version)
+				if version < snippet.Version {
+					usage.Outdated = true
+				}
+			}
+			sort.Ints(usage.Versions)
+			if outdatedOnly && !usage.Outdated {
+				continue
+			}
+			usages = append(usages, usage)
+		}
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].SnippetName != usages[j].SnippetName {
+			return usages[i].SnippetName < usages[j].SnippetName
+		}
+		return usages[i].Reference < usages[j].Reference
+	})
+
+	return usages, nil
+}
+
+// normalizeSnippetSteps trims the titles of the steps and replaces nil lists
+// by empty ones
+func normalizeSnippetSteps(steps []models.SnippetStep) []models.SnippetStep {
+	normalized := make([]models.SnippetStep, 0, len(steps))
+	for _, step := range steps {
+		step.Title = strings.TrimSpace(step.Title)
+		if step.Outputs == nil {
+			step.Outputs = []string{}
+		}
+		if step.Durations == nil {
+			step.Durations = []string{}
+		}
+		if step.Descriptions == nil {
+			step.Descriptions = []models.ProcessDescription{}
+		}
+		normalized = append(normalized, step)
+	}
+	return normalized
+}
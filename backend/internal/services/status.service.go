@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// healthCheck describes how to probe a single system component
+type healthCheck struct {
+	component models.SystemComponent
+	check     func(ctx context.Context) error // nil when the dependency is not configured
+}
+
+// StatusService samples component health periodically and serves the status page
+type StatusService struct {
+	sampleCollection   *mongo.Collection
+	incidentCollection *mongo.Collection
+	alertService       *StatusAlertService
+	checks             []healthCheck
+	interval           time.Duration
+	startedAt          time.Time
+	version            string
+}
+
+// NewStatusService creates a new status service instance
+func NewStatusService(db *DatabaseService, redisService *RedisService, minioService *MinIOService, emailService *EmailService, firebaseService *FirebaseService, alertService *StatusAlertService) *StatusService {
+	sampleCollection := db.Collection("health_samples")
+	incidentCollection := db.Collection("status_incidents")
+
+	// Keep 7 days of samples
+	ctx := context.Background()
+	sampleIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "component", Value: 1}, {Key: "checked_at", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "checked_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32((7 * 24 * time.Hour).Seconds())),
+		},
+	}
+	if _, err := sampleCollection.Indexes().CreateMany(ctx, sampleIndexes); err != nil {
+		fmt.Printf("Warning: Failed to create health sample indexes: %v\n", err)
+	}
+
+	incidentIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "component", Value: 1}, {Key: "resolved_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "started_at", Value: -1}},
+		},
+	}
+	if _, err := incidentCollection.Indexes().CreateMany(ctx, incidentIndexes); err != nil {
+		fmt.Printf("Warning: Failed to create status incident indexes: %v\n", err)
+	}
+
+	interval := time.Minute
+	if v := os.Getenv("STATUS_SAMPLE_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			interval = d
+		}
+	}
+
+	version := os.Getenv("APP_VERSION")
+	if version == "" {
+		version = "1.0.0"
+	}
+
+	s := &StatusService{
+		sampleCollection:   sampleCollection,
+		incidentCollection: incidentCollection,
+		alertService:       alertService,
+		interval:           interval,
+		startedAt:          time.Now(),
+		version:            version,
+	}
+
+	s.checks = []healthCheck{
+		{component: models.ComponentDatabase, check: db.Health},
+		{component: models.ComponentRedis, check: redisService.Health},
+		{component: models.ComponentMinIO, check: minioService.Health},
+		{component: models.ComponentEmail, check: emailService.Health},
+	}
+	if firebaseService != nil {
+		s.checks = append(s.checks, healthCheck{component: models.ComponentPush, check: firebaseService.Health})
+	} else {
+		s.checks = append(s.checks, healthCheck{component: models.ComponentPush})
+	}
+
+	return s
+}
+
+// Start launches the periodic health sampler until the context is cancelled
+func (s *StatusService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.sampleAll(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sampleAll(ctx)
+			}
+		}
+	}()
+	log.Printf("🩺 Health sampler started (interval: %s)", s.interval)
+}
+
+// sampleAll probes every component once and records the results
+func (s *StatusService) sampleAll(ctx context.Context) {
+	for _, hc := range s.checks {
+		sample := s.probe(ctx, hc)
+
+		writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		if _, err := s.sampleCollection.InsertOne(writeCtx, sample); err != nil {
+			log.Printf("⚠️  Failed to store health sample for %s: %v", hc.component, err)
+		}
+		if err := s.trackIncident(writeCtx, sample); err != nil {
+			log.Printf("⚠️  Failed to track incident for %s: %v", hc.component, err)
+		}
+		cancel()
+
+		if s.alertService != nil {
+			s.alertService.Observe(sample)
+		}
+	}
+}
+
+// probe runs a single health check with a timeout
+func (s *StatusService) probe(ctx context.Context, hc healthCheck) *models.HealthSample {
+	sample := &models.HealthSample{
+		Component: hc.component,
+		CheckedAt: time.Now(),
+	}
+
+	if hc.check == nil {
+		sample.State = models.ComponentStateDisabled
+		return sample
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := hc.check(checkCtx)
+	sample.LatencyMs = time.Since(start).Milliseconds()
+
+	switch {
+	case err != nil:
+		sample.State = models.ComponentStateDown
+		sample.Error = err.Error()
+	case sample.LatencyMs > 2000:
+		sample.State = models.ComponentStateDegraded
+	default:
+		sample.State = models.ComponentStateOperational
+	}
+
+	return sample
+}
+
+// trackIncident opens an incident when a component goes down and resolves it when it recovers
+func (s *StatusService) trackIncident(ctx context.Context, sample *models.HealthSample) error {
+	openFilter := bson.M{
+		"component":   sample.Component,
+		"resolved_at": bson.M{"$exists": false},
+	}
+
+	if sample.State == models.ComponentStateDown {
+		count, err := s.incidentCollection.CountDocuments(ctx, openFilter)
+		if err != nil || count > 0 {
+			return err
+		}
+		_, err = s.incidentCollection.InsertOne(ctx, models.StatusIncident{
+			Component: sample.Component,
+			State:     sample.State,
+			Error:     sample.Error,
+			StartedAt: sample.CheckedAt,
+		})
+		return err
+	}
+
+	_, err := s.incidentCollection.UpdateMany(ctx, openFilter, bson.M{
+		"$set": bson.M{"resolved_at": sample.CheckedAt},
+	})
+	return err
+}
+
+// GetStatus builds the public status page for the given history window,
+// with the health of each component aggregated by hour
+func (s *StatusService) GetStatus(ctx context.Context, windowHours int) (*models.SystemStatusResponse, error) {
+	return s.buildStatus(ctx, windowHours, false)
+}
+
+// GetStatusHistory builds the status page for administrators, with the raw
+// health samples of each component and the error details
+func (s *StatusService) GetStatusHistory(ctx context.Context, windowHours int) (*models.SystemStatusResponse, error) {
+	return s.buildStatus(ctx, windowHours, true)
+}
+
+// buildStatus builds the status page, with the raw samples when detailed
+func (s *StatusService) buildStatus(ctx context.Context, windowHours int, detailed bool) (*models.SystemStatusResponse, error) {
+	now := time.Now()
+	since := now.Add(-time.Duration(windowHours) * time.Hour)
+
+	response := &models.SystemStatusResponse{
+		Status:        models.ComponentStateOperational,
+		Version:       s.version,
+		StartedAt:     s.startedAt,
+		UptimeSeconds: int64(now.Sub(s.startedAt).Seconds()),
+		WindowHours:   windowHours,
+		Components:    make([]models.ComponentStatus, 0, len(s.checks)),
+		GeneratedAt:   now,
+	}
+
+	for _, hc := range s.checks {
+		buckets, samples, up, err := s.uptimeBuckets(ctx, hc.component, since)
+		if err != nil {
+			return nil, err
+		}
+
+		component := models.ComponentStatus{
+			Component: hc.component,
+			State:     models.ComponentStateOperational,
+			Uptime:    buckets,
+		}
+		if hc.check == nil {
+			component.State = models.ComponentStateDisabled
+		}
+
+		if samples > 0 {
+			component.UptimePercent = float64(up) * 100 / float64(samples)
+
+			var last models.HealthSample
+			err := s.sampleCollection.FindOne(ctx, bson.M{"component": hc.component},
+				options.FindOne().SetSort(bson.D{{Key: "checked_at", Value: -1}})).Decode(&last)
+			if err != nil && err != mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("failed to find last health sample: %w", err)
+			}
+			if err == nil {
+				component.State = last.State
+				component.LatencyMs = last.LatencyMs
+				component.LastCheckedAt = &last.CheckedAt
+			}
+		} else if hc.check != nil {
+			component.UptimePercent = 100
+		}
+
+		if detailed {
+			findOptions := options.Find().SetSort(bson.D{{Key: "checked_at", Value: 1}})
+			cursor, err := s.sampleCollection.Find(ctx, bson.M{
+				"component":  hc.component,
+				"checked_at": bson.M{"$gte": since},
+			}, findOptions)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find health samples: %w", err)
+			}
+			component.History = make([]models.HealthSample, 0)
+			if err = cursor.All(ctx, &component.History); err != nil {
+				return nil, fmt.Errorf("failed to decode health samples: %w", err)
+			}
+		}
+
+		switch component.State {
+		case models.ComponentStateDown:
+			response.Status = models.ComponentStateDown
+		case models.ComponentStateDegraded:
+			if response.Status == models.ComponentStateOperational {
+				response.Status = models.ComponentStateDegraded
+			}
+		}
+
+		response.Components = append(response.Components, component)
+	}
+
+	incidents, err := s.GetRecentIncidents(ctx, since, 20)
+	if err != nil {
+		return nil, err
+	}
+	if !detailed {
+		for i := range incidents {
+			incidents[i].Error = ""
+		}
+	}
+	response.RecentIncidents = incidents
+
+	return response, nil
+}
+
+// uptimeBuckets aggregates the health samples of a component since the given
+// time by hour, oldest first. It also returns the number of samples of the
+// window and how many of them were up.
+func (s *StatusService) uptimeBuckets(ctx context.Context, component models.SystemComponent, since time.Time) (buckets []models.UptimeBucket, samples, up int, err error) {
+	hour := time.Hour.Milliseconds()
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"component": component, "checked_at": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"$subtract": bson.A{"$checked_at", bson.M{"$mod": bson.A{bson.M{"$toLong": "$checked_at"}, hour}}}},
+			"samples":  bson.M{"$sum": 1},
+			"down":     bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$state", models.ComponentStateDown}}, 1, 0}}},
+			"degraded": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$state", models.ComponentStateDegraded}}, 1, 0}}},
+			"disabled": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$state", models.ComponentStateDisabled}}, 1, 0}}},
+			"latency":  bson.M{"$avg": "$latency_ms"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := s.sampleCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to aggregate health samples: %w", err)
+	}
+	var results []struct {
+		Start    time.Time `bson:"_id"`
+		Samples  int       `bson:"samples"`
+		Down     int       `bson:"down"`
+		Degraded int       `bson:"degraded"`
+		Disabled int       `bson:"disabled"`
+		Latency  float64   `bson:"latency"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to decode health samples: %w", err)
+	}
+
+	buckets = make([]models.UptimeBucket, 0, len(results))
+	for _, r := range results {
+		samples += r.Samples
+		up += r.Samples - r.Down
+		bucket := models.UptimeBucket{
+			Start:        r.Start,
+			Samples:      r.Samples,
+			AvgLatencyMs: int64(r.Latency),
+			State:        models.ComponentStateOperational,
+		}
+		if r.Samples > 0 {
+			bucket.UptimePercent = float64(r.Samples-r.Down) * 100 / float64(r.Samples)
+		}
+		switch {
+		case r.Down > 0:
+			bucket.State = models.ComponentStateDown
+		case r.Degraded > 0:
+			bucket.State = models.ComponentStateDegraded
+		case r.Disabled == r.Samples:
+			bucket.State = models.ComponentStateDisabled
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, samples, up, nil
+}
+
+// GetRecentIncidents returns incidents started after the given time, most recent first
+func (s *StatusService) GetRecentIncidents(ctx context.Context, since time.Time, limit int64) ([]models.StatusIncident, error) {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "started_at", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := s.incidentCollection.Find(ctx, bson.M{
+		"$or": []bson.M{
+			{"started_at": bson.M{"$gte": since}},
+			{"resolved_at": bson.M{"$exists": false}},
+		},
+	}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find incidents: %w", err)
+	}
+
+	incidents := make([]models.StatusIncident, 0)
+	if err = cursor.All(ctx, &incidents); err != nil {
+		return nil, fmt.Errorf("failed to decode incidents: %w", err)
+	}
+
+	return incidents, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestPublicStatusServesHourlyUptimeWithoutRawSamples(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("database component", func(mt *mtest.T) {
+		hour := time.Now().Truncate(time.Hour)
+		samples := mt.DB.Name() + ".health_samples"
+		incidents := mt.DB.Name() + ".status_incidents"
+		mt.AddMockResponses(
+			// Hourly buckets, 60 samples of which 3 were down
+			mtest.CreateCursorResponse(0, samples, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: hour.Add(-time.Hour)}, {Key: "samples", Value: 60}, {Key: "down", Value: 3}, {Key: "degraded", Value: 0}, {Key: "disabled", Value: 0}, {Key: "latency", Value: 12.5}},
+				bson.D{{Key: "_id", Value: hour}, {Key: "samples", Value: 20}, {Key: "down", Value: 0}, {Key: "degraded", Value: 1}, {Key: "disabled", Value: 0}, {Key: "latency", Value: 30.0}}),
+			// Last sample
+			mtest.CreateCursorResponse(0, samples, mtest.FirstBatch,
+				bson.D{{Key: "component", Value: "database"}, {Key: "state", Value: "operational"}, {Key: "latency_ms", Value: int64(8)}, {Key: "checked_at", Value: time.Now()}}),
+			mtest.CreateCursorResponse(0, incidents, mtest.FirstBatch,
+				bson.D{{Key: "component", Value: "database"}, {Key: "state", Value: "down"}, {Key: "error", Value: "connection refused to 10.0.0.12"}, {Key: "started_at", Value: hour}}),
+		)
+
+		service := &StatusService{
+			sampleCollection:   mt.DB.Collection("health_samples"),
+			incidentCollection: mt.DB.Collection("status_incidents"),
+			checks: []healthCheck{{component: models.ComponentDatabase, check: func(ctx context.Context) error {
+				return nil
+			}}},
+			startedAt: time.Now(),
+		}
+
+		status, err := service.GetStatus(context.Background(), 24)
+		if err != nil {
+			t.Fatalf("GetStatus failed: %v", err)
+		}
+
+		component := status.Components[0]
+		if len(component.Uptime) != 2 || component.Uptime[0].State != models.ComponentStateDown || component.Uptime[1].State != models.ComponentStateDegraded {
+			t.Fatalf("expected 2 hourly buckets, down then degraded, got %+v", component.Uptime)
+		}
+		if component.UptimePercent != float64(77)*100/80 {
+			t.Errorf("expected the uptime of the window to be computed from the buckets, got %v", component.UptimePercent)
+		}
+		if component.History != nil {
+			t.Errorf("expected no raw samples on the public status, got %d", len(component.History))
+		}
+		if status.RecentIncidents[0].Error != "" {
+			t.Errorf("expected the incident errors to be hidden, got %q", status.RecentIncidents[0].Error)
+		}
+
+		// The samples are never read one by one, only the last one is
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName == "find" && event.Command.Lookup("find").StringValue() == "health_samples" {
+				if limit, ok := event.Command.Lookup("limit").AsInt64OK(); !ok || limit != 1 {
+					t.Errorf("expected the public status not to read the raw samples, got %s", event.Command)
+				}
+			}
+		}
+		body, _ := json.Marshal(status)
+		if strings.Contains(string(body), `"history"`) {
+			t.Errorf("expected no history in the public payload: %s", body)
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// componentAlertState tracks the consecutive failures of a component
+type componentAlertState struct {
+	failures    int
+	downSince   time.Time
+	lastError   string
+	alerted     bool      // An alert was sent for the current outage
+	lastAlertAt time.Time // Bounds reminders and alerts of flapping components
+}
+
+// StatusAlertService alerts administrators when a component stays down for
+// several consecutive health samples, and again when it recovers. Alerts are
+// sent as push notifications to admins and to optional webhooks.
+//
+// STATUS_ALERT_THRESHOLD sets the consecutive failures before alerting (default 3),
+// STATUS_ALERT_COOLDOWN the minimum delay between two alerts of a component (default 30m),
+// STATUS_ALERT_WEBHOOK_URL receives a JSON payload and STATUS_ALERT_TEAMS_WEBHOOK_URL
+// a Microsoft Teams message card.
+type StatusAlertService struct {
+	notificationService *NotificationService
+	userService         *UserService
+	threshold           int
+	cooldown            time.Duration
+	webhookURL          string
+	teamsWebhookURL     string
+	client              *http.Client
+	states              map[models.SystemComponent]*componentAlertState // Only used by the sampler goroutine
+}
+
+// NewStatusAlertService creates a new status alert service instance
+func NewStatusAlertService(notificationService *NotificationService, userService *UserService) *StatusAlertService {
+	threshold := 3
+	if v, err := strconv.Atoi(os.Getenv("STATUS_ALERT_THRESHOLD")); err == nil && v > 0 {
+		threshold = v
+	}
+
+	cooldown := 30 * time.Minute
+	if v := os.Getenv("STATUS_ALERT_COOLDOWN"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			cooldown = d
+		}
+	}
+
+	return &StatusAlertService{
+		notificationService: notificationService,
+		userService:         userService,
+		threshold:           threshold,
+		cooldown:            cooldown,
+		webhookURL:          os.Getenv("STATUS_ALERT_WEBHOOK_URL"),
+		teamsWebhookURL:     os.Getenv("STATUS_ALERT_TEAMS_WEBHOOK_URL"),
+		client:              &http.Client{Timeout: 10 * time.Second},
+		states:              make(map[models.SystemComponent]*componentAlertState),
+	}
+}
+
+// Observe records a health sample and sends the alerts it triggers. While a
+// component stays down, the alert is repeated once per cooldown.
+func (s *StatusAlertService) Observe(sample *models.HealthSample) {
+	state, ok := s.states[sample.Component]
+	if !ok {
+		state = &componentAlertState{}
+		s.states[sample.Component] = state
+	}
+
+	if sample.State != models.ComponentStateDown {
+		if state.alerted {
+			s.send(models.StatusAlert{
+				Event:     models.StatusAlertRecovered,
+				Component: sample.Component,
+				State:     sample.State,
+				DownSince: state.downSince,
+				Failures:  state.failures,
+				At:        sample.CheckedAt,
+			})
+		}
+		state.failures = 0
+		state.alerted = false
+		return
+	}
+
+	if state.failures == 0 {
+		state.downSince = sample.CheckedAt
+	}
+	state.failures++
+	state.lastError = sample.Error
+
+	if state.failures < s.threshold || sample.CheckedAt.Sub(state.lastAlertAt) < s.cooldown {
+		return
+	}
+
+	state.alerted = true
+	state.lastAlertAt = sample.CheckedAt
+	s.send(models.StatusAlert{
+		Event:     models.StatusAlertDown,
+		Component: sample.Component,
+		State:     sample.State,
+		Error:     state.lastError,
+		DownSince: state.downSince,
+		Failures:  state.failures,
+		At:        sample.CheckedAt,
+	})
+}
+
+// send delivers an alert in the background so sampling is not delayed
+func (s *StatusAlertService) send(alert models.StatusAlert) {
+	alert.Message = alertMessage(alert)
+	log.Printf("🚨 %s", alert.Message)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		s.notifyAdmins(ctx, alert)
+		if s.webhookURL != "" {
+			if err := s.post(ctx, s.webhookURL, alert); err != nil {
+				log.Printf("⚠️  Failed to send status alert webhook: %v", err)
+			}
+		}
+		if s.teamsWebhookURL != "" {
+			if err := s.post(ctx, s.teamsWebhookURL, teamsCard(alert)); err != nil {
+				log.Printf("⚠️  Failed to send status alert to Teams: %v", err)
+			}
+		}
+	}()
+}
+
+// notifyAdmins pushes the alert to every administrator
+func (s *StatusAlertService) notifyAdmins(ctx context.Context, alert models.StatusAlert) {
+	if s.notificationService == nil || s.userService == nil {
+		return
+	}
+
+	admins, err := s.userService.GetAllUsersForNotification([]string{string(models.RoleAdmin)}, "")
+	if err != nil {
+		log.Printf("⚠️  Failed to find admins for status alert: %v", err)
+		return
+	}
+	if len(admins) == 0 {
+		return
+	}
+	adminIDs := make([]string, 0, len(admins))
+	for _, admin := range admins {
+		adminIDs = append(adminIDs, admin.ID.Hex())
+	}
+
+	title := fmt.Sprintf("Service Down: %s", alert.Component)
+	priority := models.NotificationPriorityUrgent
+	if alert.Event == models.StatusAlertRecovered {
+		title = fmt.Sprintf("Service Recovered: %s", alert.Component)
+		priority = models.NotificationPriorityNormal
+	}
+
+	notificationReq := &models.SendNotificationRequest{
+		UserIDs:  adminIDs,
+		Title:    title,
+		Body:     alert.Message,
+		Category: models.NotificationCategorySystem,
+		Priority: priority,
+		Data: map[string]interface{}{
+			"component": alert.Component,
+			"state":     alert.State,
+			"action":    "status_" + string(alert.Event),
+		},
+	}
+	if _, err := s.notificationService.SendNotification(ctx, notificationReq, primitive.NilObjectID); err != nil {
+		log.Printf("⚠️  Failed to notify admins of status alert: %v", err)
+	}
+}
+
+// post sends a JSON payload to a webhook
+func (s *StatusAlertService) post(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("alert request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("alert webhook error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// alertMessage describes an alert in one sentence
+func alertMessage(alert models.StatusAlert) string {
+	if alert.Event == models.StatusAlertRecovered {
+		return fmt.Sprintf("%s is %s again after being down since %s.",
+			alert.Component, alert.State, alert.DownSince.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s has been down since %s (%d consecutive failed checks): %s",
+		alert.Component, alert.DownSince.Format(time.RFC3339), alert.Failures, alert.Error)
+}
+
+// teamsCard formats an alert as a Microsoft Teams message card
+func teamsCard(alert models.StatusAlert) map[string]interface{} {
+	color := "D70000"
+	title := fmt.Sprintf("🚨 Service Down: %s", alert.Component)
+	if alert.Event == models.StatusAlertRecovered {
+		color = "2EB886"
+		title = fmt.Sprintf("✅ Service Recovered: %s", alert.Component)
+	}
+	return map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"themeColor": color,
+		"summary":    title,
+		"title":      title,
+		"text":       alert.Message,
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"sync"
+)
+
+// TemplateCache keeps parsed templates in memory so they are parsed once
+// instead of on every render. The least recently used template is evicted
+// when the cache is full. Parsed templates are safe for concurrent execution.
+type TemplateCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // Most recently used first
+}
+
+// templateCacheEntry is a parsed template stored in the cache
+type templateCacheEntry struct {
+	key      string
+	template *template.Template
+}
+
+// NewTemplateCache creates a template cache holding at most capacity templates
+func NewTemplateCache(capacity int) *TemplateCache {
+	return &TemplateCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the template cached under the key, parsing and caching it on a
+// miss. Parse errors are not cached.
+func (c *TemplateCache) Get(key string, parse func() (*template.Template, error)) (*template.Template, error) {
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		tmpl := element.Value.(*templateCacheEntry).template
+		c.mu.Unlock()
+		return tmpl, nil
+	}
+	c.mu.Unlock()
+
+	// Parsed outside the lock, a concurrent miss on the same key parses twice
+	tmpl, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*templateCacheEntry).template, nil
+	}
+	c.entries[key] = c.order.PushFront(&templateCacheEntry{key: key, template: tmpl})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*templateCacheEntry).key)
+	}
+	return tmpl, nil
+}
+
+// Invalidate drops every cached template, they are parsed again on next use
+func (c *TemplateCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Len returns the number of cached templates
+func (c *TemplateCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// templateKey identifies a template by its source, for templates built at runtime
+func templateKey(name, source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return name + ":" + hex.EncodeToString(sum[:])
+}
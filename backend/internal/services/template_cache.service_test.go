@@ -0,0 +1,145 @@
+package services
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+)
+
+func TestTemplateCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewTemplateCache(2)
+	parses := 0
+	get := func(key string) {
+		if _, err := cache.Get(key, func() (*template.Template, error) {
+			parses++
+			return template.New(key).Parse(key)
+		}); err != nil {
+			t.Fatalf("Get(%q) failed: %v", key, err)
+		}
+	}
+
+	get("a")
+	get("b")
+	get("a") // a is now the most recently used
+	get("c") // evicts b
+	if parses != 3 {
+		t.Fatalf("expected 3 parses, got %d", parses)
+	}
+
+	get("a")
+	if parses != 3 {
+		t.Fatalf("expected a to stay cached, got %d parses", parses)
+	}
+	get("b")
+	if parses != 4 {
+		t.Fatalf("expected b to be parsed again, got %d parses", parses)
+	}
+
+	cache.Invalidate()
+	if cache.Len() != 0 {
+		t.Fatalf("expected an empty cache after Invalidate, got %d entries", cache.Len())
+	}
+}
+
+func TestRenderTemplateBindsBranding(t *testing.T) {
+	s := &PDFService{templates: NewTemplateCache(8)}
+	for _, name := range []string{"Acme", "Globex"} {
+		branding := models.DefaultBranding()
+		branding.CompanyName = name
+		html, err := s.renderTemplate("branding-test", `{{$brand := branding}}{{$brand.CompanyName}}`, branding, nil)
+		if err != nil {
+			t.Fatalf("renderTemplate failed: %v", err)
+		}
+		if html != name {
+			t.Fatalf("expected %q, got %q", name, html)
+		}
+	}
+}
+
+// benchmarkDocument is a document with enough content to exercise the PDF template
+func benchmarkDocument() *models.Document {
+	now := time.Now()
+	return &models.Document{
+		Reference: "PRC-001",
+		Title:     "Gestion des incidents",
+		Version:   "1.0",
+		Status:    models.DocumentStatusArchived,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Metadata: models.DocumentMetadata{
+			Objectives:  []string{"Restaurer le service", "Informer les clients"},
+			Terminology: []string{"SLA : accord de niveau de service"},
+		},
+	}
+}
+
+// BenchmarkDocumentTemplateParsePerRender measures the previous behavior:
+// the document template is parsed on every render
+func BenchmarkDocumentTemplateParsePerRender(b *testing.B) {
+	branding := models.DefaultBranding()
+	document := benchmarkDocument()
+	for i := 0; i < b.N; i++ {
+		tmpl, err := template.New("document").Funcs(pdfTemplateFuncs).Funcs(template.FuncMap{
+			"branding": func() *models.Branding { return branding },
+		}).Parse(documentHTMLTemplate)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, document); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDocumentTemplateCached measures renders served from the template cache
+func BenchmarkDocumentTemplateCached(b *testing.B) {
+	s := &PDFService{templates: NewTemplateCache(8)}
+	branding := models.DefaultBranding()
+	document := benchmarkDocument()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.renderDocumentHTML(branding, document); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEmailTemplateParsePerRender measures the previous behavior: the
+// email bodies are parsed on every send
+func BenchmarkEmailTemplateParsePerRender(b *testing.B) {
+	e := &EmailService{}
+	emailTemplate := e.getWelcomeTemplate()
+	data := EmailData{UserName: "Ama", UserEmail: "ama@example.com", AppName: "Process Manager"}
+	for i := 0; i < b.N; i++ {
+		htmlTemplate, err := template.New("html").Parse(emailTemplate.HTMLBody)
+		if err != nil {
+			b.Fatal(err)
+		}
+		textTemplate, err := template.New("text").Parse(emailTemplate.TextBody)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var htmlBuffer, textBuffer bytes.Buffer
+		if err := htmlTemplate.Execute(&htmlBuffer, data); err != nil {
+			b.Fatal(err)
+		}
+		if err := textTemplate.Execute(&textBuffer, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEmailTemplateCached measures sends served from the template cache
+func BenchmarkEmailTemplateCached(b *testing.B) {
+	e := &EmailService{templates: NewTemplateCache(emailTemplateCacheSize)}
+	emailTemplate := e.getWelcomeTemplate()
+	data := EmailData{UserName: "Ama", UserEmail: "ama@example.com", AppName: "Process Manager"}
+	for i := 0; i < b.N; i++ {
+		if _, _, err := e.renderBodies(emailTemplate, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
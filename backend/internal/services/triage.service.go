@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotInTriage is returned for a triage action on a document not waiting for one
+var ErrNotInTriage = errors.New("document is not waiting for triage")
+
+// TriageService manages the queue of the documents created in bulk, which
+// quality managers review before they join the regular workflow
+type TriageService struct {
+	collection          *mongo.Collection
+	documentService     *DocumentService
+	userService         *UserService
+	notificationService *NotificationService
+}
+
+// NewTriageService creates a new triage service instance
+func NewTriageService(db *DatabaseService, documentService *DocumentService, userService *UserService, notificationService *NotificationService) *TriageService {
+	service := &TriageService{
+		collection:          db.Collection("documents"),
+		documentService:     documentService,
+		userService:         userService,
+		notificationService: notificationService,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "triage.resolved_at", Value: 1}, {Key: "triage.due_at", Value: 1}},
+		Options: options.Index().SetPartialFilterExpression(bson.M{
+			"triage.queued_at": bson.M{"$exists": true},
+		}),
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create triage indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// triageSLA returns the time allowed to triage a document, set by TRIAGE_SLA_HOURS
+func triageSLA() time.Duration {
+	if v, err := strconv.Atoi(os.Getenv("TRIAGE_SLA_HOURS")); err == nil && v > 0 {
+		return time.Duration(v) * time.Hour
+	}
+	return 72 * time.Hour
+}
+
+// queueForTriage puts a newly created document in the triage queue
+func (s *DocumentService) queueForTriage(ctx context.Context, document *models.Document, source models.TriageSource) error {
+	now := time.Now()
+	triage := &models.DocumentTriage{
+		Source:   source,
+		QueuedAt: now,
+		DueAt:    now.Add(triageSLA()),
+	}
+	if _, err := s.collection.UpdateOne(ctx, bson.M{"_id": document.ID}, bson.M{"$set": bson.M{"triage": triage}}); err != nil {
+		return err
+	}
+	document.Triage = triage
+	return nil
+}
+
+// List returns the documents waiting for triage, the closest deadline first,
+// optionally those of an assignee or past their deadline only
+func (s *TriageService) List(ctx context.Context, assigneeID *primitive.ObjectID, overdueOnly bool, page, limit int) ([]models.TriageItem, int64, error) {
+	now := time.Now()
+	filter := models.NotDeleted(models.NeedsTriage(bson.M{}))
+	if assigneeID != nil {
+		filter["triage.assignee_id"] = *assigneeID
+	}
+	if overdueOnly {
+		filter["triage.due_at"] = bson.M{"$lt": now}
+	}
+
+	total, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count documents in triage: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "triage.due_at", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"reference": 1, "title": 1, "macro_id": 1, "process_code": 1, "created_by": 1, "triage": 1})
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find documents in triage: %w", err)
+	}
+	var documents []models.Document
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode documents in triage: %w", err)
+	}
+
+	items := make([]models.TriageItem, 0, len(documents))
+	for _, document := range documents {
+		items = append(items, models.TriageItem{
+			DocumentID:  document.ID,
+			Reference:   document.Reference,
+			Title:       document.Title,
+			MacroID:     document.MacroID,
+			ProcessCode: document.ProcessCode,
+			OwnerID:     document.CreatedBy,
+			Triage:      *document.Triage,
+			Overdue:     document.Triage.IsOverdue(now),
+		})
+	}
+	return items, total, nil
+}
+
+// Stats measures the queue and the triages of the last 30 days against the SLA
+func (s *TriageService) Stats(ctx context.Context) (*models.TriageStats, error) {
+	now := time.Now()
+	stats := &models.TriageStats{SLAHours: int(triageSLA() / time.Hour)}
+
+	open := models.NotDeleted(models.NeedsTriage(bson.M{}))
+	var err error
+	if stats.Open, err = s.collection.CountDocuments(ctx, open); err != nil {
+		return nil, fmt.Errorf("failed to count documents in triage: %w", err)
+	}
+	unassigned := models.NotDeleted(models.NeedsTriage(bson.M{"triage.assignee_id": bson.M{"$exists": false}}))
+	if stats.Unassigned, err = s.collection.CountDocuments(ctx, unassigned); err != nil {
+		return nil, fmt.Errorf("failed to count unassigned documents in triage: %w", err)
+	}
+	overdue := models.NotDeleted(models.NeedsTriage(bson.M{"triage.due_at": bson.M{"$lt": now}}))
+	if stats.Overdue, err = s.collection.CountDocuments(ctx, overdue); err != nil {
+		return nil, fmt.Errorf("failed to count overdue documents in triage: %w", err)
+	}
+
+	// Discarded documents are in the trash, they count as resolved
+	cursor, err := s.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"triage.resolved_at": bson.M{"$gte": now.AddDate(0, 0, -30)}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"count": bson.M{"$sum": 1},
+			"withinSla": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$lte": bson.A{"$triage.resolved_at", "$triage.due_at"}}, 1, 0},
+			}},
+			"avgMillis": bson.M{"$avg": bson.M{"$subtract": bson.A{"$triage.resolved_at", "$triage.queued_at"}}},
+		}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate resolved triages: %w", err)
+	}
+	var results []struct {
+		Count     int64   `bson:"count"`
+		WithinSLA int64   `bson:"withinSla"`
+		AvgMillis float64 `bson:"avgMillis"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode resolved triages: %w", err)
+	}
+	if len(results) > 0 && results[0].Count > 0 {
+		stats.ResolvedLast30Days = results[0].Count
+		stats.WithinSLARate = float64(results[0].WithinSLA) / float64(results[0].Count)
+		stats.AverageResolutionHours = results[0].AvgMillis / float64(time.Hour/time.Millisecond)
+	}
+
+	return stats, nil
+}
+
+// Assign gives the triage of a document to a quality manager, who is notified
+func (s *TriageService) Assign(ctx context.Context, id, assigneeID, actorID primitive.ObjectID) (*models.Document, error) {
+	document, err := s.getOpen(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assignee, err := s.userService.GetUserByID(ctx, assigneeID)
+	if err != nil {
+		return nil, err
+	}
+	if !assignee.CanLogin() || (assignee.Role != models.RoleManager && assignee.Role != models.RoleAdmin) {
+		return nil, errors.New("assignee must be an active manager or admin")
+	}
+
+	now := time.Now()
+	document, err = s.update(ctx, id, bson.M{"$set": bson.M{
+		"triage.assignee_id": assigneeID,
+		"triage.assigned_at": now,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	if assigneeID != actorID {
+		notificationReq := &models.SendNotificationRequest{
+			UserIDs:  []string{assigneeID.Hex()},
+			Title:    "Document to Triage",
+			Body:     fmt.Sprintf("Document %s (%s) was assigned to you for triage before %s.", document.Title, document.Reference, document.Triage.DueAt.Format("02/01/2006 15:04")),
+			Category: models.NotificationCategoryActivity,
+			Priority: models.NotificationPriorityNormal,
+			Data: map[string]interface{}{
+				"documentId": document.ID.Hex(),
+				"reference":  document.Reference,
+				"action":     "triage_assigned",
+			},
+		}
+		if _, err := s.notificationService.SendNotification(ctx, notificationReq, actorID); err != nil {
+			fmt.Printf("⚠️  Failed to notify the triage assignment of %s: %v\n", document.Reference, err)
+		}
+	}
+
+	return document, nil
+}
+
+// AssignMacro attaches a document in triage to its macro
+func (s *TriageService) AssignMacro(ctx context.Context, id, macroID primitive.ObjectID) (*models.Document, error) {
+	if _, err := s.getOpen(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.documentService.AttachToMacro(ctx, id, macroID)
+}
+
+// AssignOwner makes a user the owner of a document in triage, in place of
+// the user who imported it
+func (s *TriageService) AssignOwner(ctx context.Context, id, ownerID primitive.ObjectID) (*models.Document, error) {
+	if _, err := s.getOpen(ctx, id); err != nil {
+		return nil, err
+	}
+	owner, err := s.userService.GetUserByID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if !owner.CanLogin() {
+		return nil, errors.New("owner must be an active user")
+	}
+
+	return s.update(ctx, id, bson.M{
+		"$set": bson.M{"created_by": ownerID, "updated_at": time.Now()},
+		"$inc": bson.M{"revision": 1},
+	})
+}
+
+// Accept ends the triage of a document, which continues as a regular draft
+func (s *TriageService) Accept(ctx context.Context, id, userID primitive.ObjectID) (*models.Document, error) {
+	if _, err := s.getOpen(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, id, userID, models.TriageResolutionAccepted)
+}
+
+// Discard ends the triage of a document by moving it to the trash
+func (s *TriageService) Discard(ctx context.Context, id, userID primitive.ObjectID) (*models.Document, error) {
+	if _, err := s.getOpen(ctx, id); err != nil {
+		return nil, err
+	}
+	document, err := s.resolve(ctx, id, userID, models.TriageResolutionDiscarded)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.documentService.Delete(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// resolve records the outcome of the triage of a document
+func (s *TriageService) resolve(ctx context.Context, id, userID primitive.ObjectID, resolution models.TriageResolution) (*models.Document, error) {
+	return s.update(ctx, id, bson.M{"$set": bson.M{
+		"triage.resolved_at": time.Now(),
+		"triage.resolved_by": userID,
+		"triage.resolution":  resolution,
+	}})
+}
+
+// getOpen returns a document waiting for triage
+func (s *TriageService) getOpen(ctx context.Context, id primitive.ObjectID) (*models.Document, error) {
+	document, err := s.documentService.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !document.Triage.IsOpen() {
+		return nil, ErrNotInTriage
+	}
+	return document, nil
+}
+
+// update applies a change to a document waiting for triage
+func (s *TriageService) update(ctx context.Context, id primitive.ObjectID, update bson.M) (*models.Document, error) {
+	var document models.Document
+	err := s.collection.FindOneAndUpdate(ctx,
+		models.NotDeleted(models.NeedsTriage(bson.M{"_id": id})),
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&document)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotInTriage
+		}
+		return nil, fmt.Errorf("failed to update triage: %w", err)
+	}
+	return &document, nil
+}
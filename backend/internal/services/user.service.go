@@ -237,6 +237,9 @@ func (s *UserService) UpdateUser(ctx context.Context, userID primitive.ObjectID,
 	if req.Avatar != "" {
 		update["$set"].(bson.M)["avatar"] = req.Avatar
 	}
+	if req.Language != "" {
+		update["$set"].(bson.M)["language"] = req.Language
+	}
 
 	// Update and return the updated user
 	var user models.User
@@ -285,6 +288,61 @@ func (s *UserService) SoftDeleteUser(ctx context.Context, userID primitive.Objec
 	return nil
 }
 
+// MarkOnboardingStep records that the user reached an onboarding milestone.
+// The first time is kept, marking a step again has no effect.
+func (s *UserService) MarkOnboardingStep(ctx context.Context, userID primitive.ObjectID, step models.OnboardingStep) error {
+	field := "onboarding." + string(step)
+	_, err := s.userCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID, field: bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{field: time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update onboarding state: %w", err)
+	}
+
+	return nil
+}
+
+// AnonymizeUser irreversibly replaces the personal data of a user and disables
+// the account. The record itself is kept so that documents and activity logs
+// still resolve the user ID.
+func (s *UserService) AnonymizeUser(ctx context.Context, userID primitive.ObjectID) error {
+	update := bson.M{
+		"$set": bson.M{
+			"email":        fmt.Sprintf("deleted-%s@deleted.invalid", userID.Hex()),
+			"first_name":   "Deleted",
+			"last_name":    "User",
+			"status":       models.StatusInactive,
+			"active":       false,
+			"has_pin":      false,
+			"pin_hash":     "",
+			"pin_salt":     "",
+			"pin_attempts": 0,
+			"updated_at":   time.Now(),
+		},
+		"$unset": bson.M{
+			"avatar":          "",
+			"phone":           "",
+			"department_id":   "",
+			"job_position_id": "",
+			"pin_set_at":      "",
+			"pin_locked_at":   "",
+		},
+	}
+
+	result, err := s.userCollection.UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
 // ============================================
 // User Status Management
 // ============================================
@@ -449,6 +507,30 @@ func (s *UserService) GetPendingUsers(ctx context.Context, skip, limit int64) ([
 	return s.ListUsers(ctx, skip, limit, filter)
 }
 
+// StreamUsers calls fn for every user matching the filter, reading them from
+// a cursor instead of loading the whole list
+func (s *UserService) StreamUsers(ctx context.Context, filter bson.M, fn func(*models.User) error) error {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetBatchSize(exportBatchSize)
+	cursor, err := s.userCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return fmt.Errorf("failed to find users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user models.User
+		if err := cursor.Decode(&user); err != nil {
+			return fmt.Errorf("failed to decode user: %w", err)
+		}
+		if err := fn(&user); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
 // GetAllUsersForNotification returns users for notification purposes
 func (s *UserService) GetAllUsersForNotification(roles []string, status string) ([]*models.User, error) {
 	ctx := context.Background()
@@ -671,6 +753,7 @@ func (s *UserService) CreateUserFromRegistration(ctx context.Context, email stri
 		FirstName:     req.FirstName,
 		LastName:      req.LastName,
 		Phone:         req.Phone,
+		Language:      req.Language,
 		DepartmentID:  &departmentID,
 		JobPositionID: &jobPositionID,
 		Role:          models.RoleUser, // Default role for new registrations
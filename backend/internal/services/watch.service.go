@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WatchService handles the subscriptions of users to documents and notifies
+// the watchers of a document when it changes
+type WatchService struct {
+	collection          *mongo.Collection
+	notificationService *NotificationService
+}
+
+// NewWatchService creates a new watch service instance
+func NewWatchService(db *DatabaseService, notificationService *NotificationService) *WatchService {
+	service := &WatchService{
+		collection:          db.Collection("document_watches"),
+		notificationService: notificationService,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := service.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "document_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	}); err != nil {
+		fmt.Printf("Warning: Failed to create document watch indexes: %v\n", err)
+	}
+
+	return service
+}
+
+// Watch subscribes a user to a document; watching it twice is a no-op
+func (s *WatchService) Watch(ctx context.Context, userID, documentID primitive.ObjectID) error {
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"user_id": userID, "document_id": documentID},
+		bson.M{"$setOnInsert": bson.M{"created_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to watch document: %w", err)
+	}
+	return nil
+}
+
+// Unwatch unsubscribes a user from a document
+func (s *WatchService) Unwatch(ctx context.Context, userID, documentID primitive.ObjectID) error {
+	if _, err := s.collection.DeleteOne(ctx, bson.M{"user_id": userID, "document_id": documentID}); err != nil {
+		return fmt.Errorf("failed to unwatch document: %w", err)
+	}
+	return nil
+}
+
+// Status tells whether the user watches the document and how many users do
+func (s *WatchService) Status(ctx context.Context, userID, documentID primitive.ObjectID) (*models.WatchStatusResponse, error) {
+	watching, err := s.collection.CountDocuments(ctx, bson.M{"user_id": userID, "document_id": documentID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check watch: %w", err)
+	}
+	total, err := s.collection.CountDocuments(ctx, bson.M{"document_id": documentID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count watchers: %w", err)
+	}
+	return &models.WatchStatusResponse{
+		DocumentID:   documentID.Hex(),
+		IsWatching:   watching > 0,
+		WatcherCount: total,
+	}, nil
+}
+
+// Watchers returns the IDs of the users watching a document
+func (s *WatchService) Watchers(ctx context.Context, documentID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{"document_id": documentID}, options.Find().SetProjection(bson.M{"user_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find watchers: %w", err)
+	}
+	var watches []models.DocumentWatch
+	if err := cursor.All(ctx, &watches); err != nil {
+		return nil, fmt.Errorf("failed to decode watchers: %w", err)
+	}
+
+	userIDs := make([]primitive.ObjectID, 0, len(watches))
+	for _, watch := range watches {
+		userIDs = append(userIDs, watch.UserID)
+	}
+	return userIDs, nil
+}
+
+// Notify sends a notification about a change of the document to the
+// watchers of watchedID, except the user who made the change. The watched
+// document is the changed one, except for revisions which are notified to the
+// watchers of the version they supersede. The notifications go through the
+// notification service which applies the preferences of each watcher for the
+// update category.
+func (s *WatchService) Notify(ctx context.Context, watchedID primitive.ObjectID, document *models.Document, event models.WatchEvent, title, body string, actorID primitive.ObjectID) error {
+	watchers, err := s.Watchers(ctx, watchedID)
+	if err != nil {
+		return err
+	}
+
+	userIDs := make([]string, 0, len(watchers))
+	for _, userID := range watchers {
+		if userID != actorID {
+			userIDs = append(userIDs, userID.Hex())
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	req := &models.SendNotificationRequest{
+		UserIDs:  userIDs,
+		Title:    title,
+		Body:     body,
+		Category: models.NotificationCategoryUpdate,
+		Priority: models.NotificationPriorityNormal,
+		Data: map[string]interface{}{
+			"documentId": document.ID.Hex(),
+			"reference":  document.Reference,
+			"title":      document.Title,
+			"action":     "document_watch",
+			"event":      string(event),
+			"status":     string(document.Status),
+			"version":    document.Version,
+		},
+	}
+	if _, err := s.notificationService.SendNotification(ctx, req, actorID); err != nil {
+		return fmt.Errorf("failed to notify document watchers: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodesonik/process-manager/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// signatureStatuses are the statuses of the documents going through their workflow
+var signatureStatuses = []models.DocumentStatus{
+	models.DocumentStatusAuthorReview,
+	models.DocumentStatusAuthorSigned,
+	models.DocumentStatusVerifierReview,
+	models.DocumentStatusVerifierSigned,
+	models.DocumentStatusValidatorReview,
+}
+
+// SimulateWorkflow evaluates a proposed workflow and signature deadlines of a
+// macro against its documents in signature, without applying them. Documents
+// with their own workflow are not affected and left out.
+func (s *MacroService) SimulateWorkflow(ctx context.Context, id primitive.ObjectID, req *models.WorkflowSimulationRequest) (*models.WorkflowSimulationResult, error) {
+	proposed := req.Workflow
+	if proposed == nil {
+		proposed = models.DefaultWorkflow()
+	}
+	if err := proposed.Validate(); err != nil {
+		return nil, err
+	}
+
+	macro, err := s.GetMacroByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	current := macro.Workflow
+	if current == nil {
+		current = models.DefaultWorkflow()
+	}
+
+	cursor, err := s.docCollection.Find(ctx,
+		models.NotDeleted(bson.M{
+			"macro_id": id,
+			"workflow": bson.M{"$exists": false},
+			"status":   bson.M{"$in": signatureStatuses},
+		}),
+		options.Find().SetSort(bson.D{{Key: "reference", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var documents []models.Document
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode documents: %w", err)
+	}
+
+	result := &models.WorkflowSimulationResult{
+		MacroID:   id.Hex(),
+		Evaluated: len(documents),
+		Documents: []models.WorkflowSimulationDocument{},
+	}
+	now := time.Now()
+	for i := range documents {
+		report := simulateDocumentWorkflow(&documents[i], current, proposed, req.Deadlines, now)
+		if len(report.Impacts) == 0 {
+			continue
+		}
+		if report.StuckReason != "" {
+			result.Stuck++
+		} else {
+			result.Changed++
+		}
+		result.Documents = append(result.Documents, report)
+	}
+
+	return result, nil
+}
+
+// simulateDocumentWorkflow compares the progress of a document in signature
+// under its current workflow and under the proposed one
+func simulateDocumentWorkflow(document *models.Document, current, proposed *models.WorkflowDefinition, deadlines *models.ApprovalDeadlines, now time.Time) models.WorkflowSimulationDocument {
+	report := models.WorkflowSimulationDocument{
+		DocumentID: document.ID.Hex(),
+		Reference:  document.Reference,
+		Title:      document.Title,
+		Status:     document.Status,
+		Impacts:    []models.WorkflowImpact{},
+	}
+
+	currentNext, currentRequired, currentStuck := workflowNextStep(document, current)
+	report.NextStatus = currentNext
+	report.RequiredSignatures = currentRequired
+	if stage := current.StageIndex(document.Status); stage >= 0 {
+		for _, contributor := range document.Contributors.Team(current.Stages[stage].Team) {
+			if contributor.Status == models.SignatureStatusSigned {
+				report.SignaturesCollected++
+			}
+		}
+	}
+
+	newNext, newRequired, newStuck := workflowNextStep(document, proposed)
+	report.NewNextStatus = newNext
+	report.NewRequiredSignatures = newRequired
+
+	// Documents already stuck today are not made worse by the change
+	if newStuck != "" && currentStuck == "" {
+		report.Impacts = append(report.Impacts, models.WorkflowImpactStuck)
+		report.StuckReason = newStuck
+		return report
+	}
+	if newRequired != currentRequired {
+		report.Impacts = append(report.Impacts, models.WorkflowImpactSignersChanged)
+	}
+	if newNext != currentNext {
+		report.Impacts = append(report.Impacts, models.WorkflowImpactNextStepChanged)
+	}
+
+	if document.StageDeadline != nil {
+		report.DueAt = &document.StageDeadline.DueAt
+		report.Overdue = document.StageDeadline.DueAt.Before(now)
+		if deadlines != nil {
+			simulated := *document
+			simulated.Deadlines = deadlines
+			if deadline := NewStageDeadline(&simulated, document.Status, document.StageDeadline.StartedAt); deadline != nil && !deadline.DueAt.Equal(document.StageDeadline.DueAt) {
+				report.Impacts = append(report.Impacts, models.WorkflowImpactDeadlineChanged)
+				report.NewDueAt = &deadline.DueAt
+				report.Overdue = deadline.DueAt.Before(now)
+			}
+		}
+	}
+
+	return report
+}
+
+// workflowNextStep returns the status a document in signature moves to once
+// its current stage completes under a workflow, the signatures completing a
+// review stage, and why the document cannot progress when it is stuck
+func workflowNextStep(document *models.Document, workflow *models.WorkflowDefinition) (models.DocumentStatus, int, string) {
+	stage := workflow.StageIndex(document.Status)
+	if stage < 0 {
+		return "", 0, fmt.Sprintf("status %s is not a stage of the workflow", document.Status)
+	}
+
+	// Signed stages wait for the document to be published to the next team
+	if workflow.Stages[stage].SignedStatus() == document.Status {
+		next, _, err := workflow.PublishTransition(document)
+		if err != nil {
+			return "", 0, err.Error()
+		}
+		return next, 0, ""
+	}
+
+	contributors := len(document.Contributors.Team(workflow.Stages[stage].Team))
+	if contributors == 0 {
+		return "", 0, fmt.Sprintf("no %s can sign the document", workflow.Stages[stage].Team)
+	}
+	required := workflow.Stages[stage].RequiredSignatures(contributors)
+	next, _, _ := workflow.SignTransition(document, required)
+	return next, required, ""
+}